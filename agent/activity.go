@@ -0,0 +1,11 @@
+package agent
+
+import "net/http"
+
+// reportActivity records user activity that the agent can't observe
+// itself, e.g. heartbeats sent by the VS Code extension while its window
+// is focused.
+func (a *agent) reportActivity(rw http.ResponseWriter, _ *http.Request) {
+	a.activity.Touch()
+	rw.WriteHeader(http.StatusNoContent)
+}
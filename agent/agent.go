@@ -12,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/user"
@@ -28,13 +29,20 @@ import (
 	"github.com/spf13/afero"
 	"go.uber.org/atomic"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/netlogtype"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentactivity"
+	"github.com/coder/coder/agent/agentmetadata"
+	"github.com/coder/coder/agent/agentproc"
+	"github.com/coder/coder/agent/agentrecord"
+	"github.com/coder/coder/agent/agentscripts"
 	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/agent/agentupdate"
 	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/gitauth"
@@ -64,10 +72,31 @@ type Options struct {
 	SSHMaxTimeout          time.Duration
 	TailnetListenPort      uint16
 	Subsystem              codersdk.AgentSubsystem
+	// Update enables replacing the agent binary when the server version
+	// differs. If nil, the agent never updates itself.
+	Update *UpdateOptions
+	// RestartLifecycle is the lifecycle state of the agent process that
+	// restarted into this one after an update. If set, the startup
+	// scripts are not run again.
+	RestartLifecycle codersdk.WorkspaceAgentLifecycle
 
 	PrometheusRegistry *prometheus.Registry
 }
 
+// UpdateOptions configures how the agent updates itself.
+type UpdateOptions struct {
+	// Executable is the path of the agent binary that is replaced.
+	Executable string
+	// BinaryURL is where the binary matching the server is downloaded
+	// from. See agentupdate.BinaryURL.
+	BinaryURL  *url.URL
+	HTTPClient *http.Client
+	// Restart is called once the agent has closed after replacing the
+	// binary. It should start the new binary, passing the lifecycle state
+	// on, and usually only returns on failure.
+	Restart func(lifecycle codersdk.WorkspaceAgentLifecycle) error
+}
+
 type Client interface {
 	Manifest(ctx context.Context) (agentsdk.Manifest, error)
 	Listen(ctx context.Context) (net.Conn, error)
@@ -77,6 +106,9 @@ type Client interface {
 	PostStartup(ctx context.Context, req agentsdk.PostStartupRequest) error
 	PostMetadata(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
 	PatchStartupLogs(ctx context.Context, req agentsdk.PatchStartupLogs) error
+	PostScriptStatus(ctx context.Context, req agentsdk.PostScriptStatusRequest) error
+	PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error
+	PostProcesses(ctx context.Context, req codersdk.WorkspaceAgentProcesses) error
 }
 
 type Agent interface {
@@ -111,6 +143,12 @@ func New(options Options) Agent {
 		prometheusRegistry = prometheus.NewRegistry()
 	}
 
+	lifecycleStates := []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}}
+	if options.RestartLifecycle != "" {
+		// The previous process already reported this state.
+		lifecycleStates = []agentsdk.PostLifecycleRequest{{State: options.RestartLifecycle}}
+	}
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	a := &agent{
 		tailnetListenPort:      options.TailnetListenPort,
@@ -126,7 +164,9 @@ func New(options Options) Agent {
 		tempDir:                options.TempDir,
 		lifecycleUpdate:        make(chan struct{}, 1),
 		lifecycleReported:      make(chan codersdk.WorkspaceAgentLifecycle, 1),
-		lifecycleStates:        []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}},
+		lifecycleStates:        lifecycleStates,
+		restarted:              options.RestartLifecycle != "",
+		updateOptions:          options.Update,
 		ignorePorts:            options.IgnorePorts,
 		connStatsChan:          make(chan *agentsdk.Stats, 1),
 		sshMaxTimeout:          options.SSHMaxTimeout,
@@ -156,6 +196,11 @@ type agent struct {
 	reconnectingPTYs       sync.Map
 	reconnectingPTYTimeout time.Duration
 
+	peerUsersMu sync.Mutex // Protects following.
+	// peerUsers maps the tailnet addresses of clients to the user coderd
+	// authenticated them as, it's used to attribute session recordings.
+	peerUsers map[netip.Addr]uuid.UUID
+
 	connCloseWait sync.WaitGroup
 	closeCancel   context.CancelFunc
 	closeMutex    sync.Mutex
@@ -167,11 +212,28 @@ type agent struct {
 	sessionToken  atomic.Pointer[string]
 	sshServer     *agentssh.Server
 	sshMaxTimeout time.Duration
+	scriptRunner  *agentscripts.Runner
+	// sessions tracks the processes started by SSH sessions and
+	// reconnecting PTYs.
+	sessions *agentproc.Tracker
+	// activity tracks when a user last interacted with the workspace.
+	activity *agentactivity.Tracker
+	// metadataCollectors evaluates built-in metadata collectors. It is
+	// nil if system statistics are unavailable.
+	metadataCollectors *agentmetadata.Collectors
 
 	lifecycleUpdate   chan struct{}
 	lifecycleReported chan codersdk.WorkspaceAgentLifecycle
 	lifecycleMu       sync.RWMutex // Protects following.
 	lifecycleStates   []agentsdk.PostLifecycleRequest
+	// restarted is true if the agent was restarted after an update.
+	restarted bool
+
+	// updateOptions is nil if the agent doesn't update itself.
+	updateOptions *UpdateOptions
+	// updateVersion is the server version the agent last tried to
+	// update to.
+	updateVersion atomic.Pointer[string]
 
 	network       *tailnet.Conn
 	connStatsChan chan *agentsdk.Stats
@@ -191,7 +253,27 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.Env = a.envVars
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
+	sshSrv.NewSessionRecorder = func(typ codersdk.SessionRecordingType, width, height uint16, term string, remoteAddr net.Addr) *agentrecord.Recorder {
+		return a.newSessionRecorder(typ, width, height, term, a.peerUser(remoteAddr))
+	}
+	a.sessions = agentproc.NewTracker()
+	sshSrv.Sessions = a.sessions
+	a.activity = agentactivity.NewTracker()
+	sshSrv.Activity = a.activity
 	a.sshServer = sshSrv
+	a.metadataCollectors, err = agentmetadata.New()
+	if err != nil {
+		a.logger.Warn(ctx, "built-in metadata collectors are unavailable", slog.Error(err))
+	}
+	a.scriptRunner = agentscripts.New(agentscripts.Options{
+		LogDir:       a.logDir,
+		Logger:       a.logger.Named("script-runner"),
+		SSHServer:    sshSrv,
+		Filesystem:   a.filesystem,
+		PatchLogs:    a.client.PatchStartupLogs,
+		PostStatus:   a.client.PostScriptStatus,
+		PostMetadata: a.client.PostMetadata,
+	})
 
 	go a.runLoop(ctx)
 }
@@ -203,6 +285,7 @@ func (a *agent) init(ctx context.Context) {
 func (a *agent) runLoop(ctx context.Context) {
 	go a.reportLifecycleLoop(ctx)
 	go a.reportMetadataLoop(ctx)
+	go a.reportProcessesLoop(ctx)
 
 	for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
 		a.logger.Info(ctx, "connecting to coderd")
@@ -235,6 +318,19 @@ func (a *agent) collectMetadata(ctx context.Context, md codersdk.WorkspaceAgentM
 		// if it can guarantee the clocks are synchronized.
 		CollectedAt: time.Now(),
 	}
+	if md.Collector != "" {
+		if a.metadataCollectors == nil {
+			result.Error = "built-in metadata collectors are unavailable"
+			return result
+		}
+		value, err := a.metadataCollectors.Collect(ctx, md.Collector)
+		if err != nil {
+			result.Error = fmt.Sprintf("run collector: %+v", err)
+		}
+		result.Value = value
+		return result
+	}
+
 	cmdPty, err := a.sshServer.CreateCommand(ctx, md.Script, nil)
 	if err != nil {
 		result.Error = fmt.Sprintf("create cmd: %+v", err)
@@ -374,6 +470,12 @@ func (a *agent) reportMetadataLoop(ctx context.Context) {
 		// channel to synchronize the results and avoid both messy
 		// mutex logic and overloading the API.
 		for _, md := range manifest.Metadata {
+			if md.Script == "" && md.Collector == "" {
+				// Metadata without a script or collector is reported by
+				// other parts of the agent, e.g. the result of scheduled
+				// scripts.
+				continue
+			}
 			collectedAt, ok := lastCollectedAts[md.Key]
 			if ok {
 				// If the interval is zero, we assume the user just wants
@@ -415,6 +517,46 @@ func (a *agent) reportMetadataLoop(ctx context.Context) {
 	}
 }
 
+// reportProcessesLoop periodically reports the resource usage of the
+// processes in the workspace, at the interval set by the manifest.
+func (a *agent) reportProcessesLoop(ctx context.Context) {
+	collector, err := agentproc.NewCollector(agentproc.DefaultTopN)
+	if err != nil {
+		a.logger.Error(ctx, "create process collector", slog.Error(err))
+		return
+	}
+	ticker := time.NewTicker(adjustIntervalForTests(1))
+	defer ticker.Stop()
+
+	var lastReport time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		manifest := a.manifest.Load()
+		if manifest == nil || manifest.ProcessReportInterval <= 0 || time.Since(lastReport) < manifest.ProcessReportInterval {
+			continue
+		}
+		lastReport = time.Now()
+
+		processes, err := collector.Collect(a.sessions.Sessions())
+		if err != nil {
+			a.logger.Warn(ctx, "collect processes", slog.Error(err))
+			continue
+		}
+		err = a.client.PostProcesses(ctx, processes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Warn(ctx, "agent failed to report processes", slog.Error(err))
+		}
+	}
+}
+
 // reportLifecycleLoop reports the current lifecycle state once. All state
 // changes are reported in order.
 func (a *agent) reportLifecycleLoop(ctx context.Context) {
@@ -527,10 +669,26 @@ func (a *agent) run(ctx context.Context) error {
 
 	oldManifest := a.manifest.Swap(&manifest)
 
-	// The startup script should only execute on the first run!
-	if oldManifest == nil {
+	if a.updateOptions != nil && agentupdate.NeedsUpdate(buildinfo.Version(), manifest.ServerVersion) {
+		a.startUpdate(ctx, manifest.ServerVersion, manifest.ServerBinaryChecksum)
+	}
+
+	// The startup script should only execute on the first run, and not
+	// again after the agent restarted into an updated binary!
+	if oldManifest == nil && a.restarted {
+		err = a.scriptRunner.Init(manifest.Scripts)
+		if err != nil {
+			return xerrors.Errorf("init script runner: %w", err)
+		}
+		a.scriptRunner.StartCron()
+	} else if oldManifest == nil {
 		a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStarting)
 
+		err = a.scriptRunner.Init(manifest.Scripts)
+		if err != nil {
+			return xerrors.Errorf("init script runner: %w", err)
+		}
+
 		// Perform overrides early so that Git auth can work even if users
 		// connect to a workspace that is not yet ready. We don't run this
 		// concurrently with the startup script to avoid conflicts between
@@ -549,7 +707,18 @@ func (a *agent) run(ctx context.Context) error {
 		scriptDone := make(chan error, 1)
 		err = a.trackConnGoroutine(func() {
 			defer close(scriptDone)
-			scriptDone <- a.runStartupScript(ctx, manifest.StartupScript)
+			// The legacy startup script runs alongside the scripts
+			// that are set to run on start.
+			var eg errgroup.Group
+			eg.Go(func() error {
+				return a.runStartupScript(ctx, manifest.StartupScript)
+			})
+			eg.Go(func() error {
+				return a.scriptRunner.Execute(ctx, func(script codersdk.WorkspaceAgentScript) bool {
+					return script.RunOnStart
+				})
+			})
+			scriptDone <- eg.Wait()
 		})
 		if err != nil {
 			return xerrors.Errorf("track startup script: %w", err)
@@ -579,6 +748,9 @@ func (a *agent) run(ctx context.Context) error {
 				lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
 			}
 			a.setLifecycle(ctx, lifecycleState)
+			// Scheduled scripts only start running once startup
+			// has completed.
+			a.scriptRunner.StartCron()
 		}()
 	}
 
@@ -817,7 +989,12 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 	}
 	defer coordinator.Close()
 	a.logger.Info(ctx, "connected to coordination endpoint")
+	// The coordinator sends the nodes of all connected clients again.
+	a.peerUsersMu.Lock()
+	a.peerUsers = map[netip.Addr]uuid.UUID{}
+	a.peerUsersMu.Unlock()
 	sendNodes, errChan := tailnet.ServeCoordinator(coordinator, func(nodes []*tailnet.Node) error {
+		a.trackPeerUsers(nodes)
 		return network.UpdateNodes(nodes, false)
 	})
 	network.SetNodeCallback(sendNodes)
@@ -829,6 +1006,47 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 	}
 }
 
+// trackPeerUsers remembers the users of the client nodes received from the
+// coordinator.
+func (a *agent) trackPeerUsers(nodes []*tailnet.Node) {
+	a.peerUsersMu.Lock()
+	defer a.peerUsersMu.Unlock()
+	for _, node := range nodes {
+		if node.UserID == uuid.Nil {
+			continue
+		}
+		for _, prefix := range node.Addresses {
+			if prefix.IsSingleIP() {
+				a.peerUsers[prefix.Addr()] = node.UserID
+			}
+		}
+	}
+}
+
+// peerUser returns the user of the client connected from addr, or the zero
+// UUID if the client isn't known or isn't tied to a user.
+func (a *agent) peerUser(addr net.Addr) uuid.UUID {
+	if addr == nil {
+		return uuid.Nil
+	}
+	addrPort, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return uuid.Nil
+	}
+	a.peerUsersMu.Lock()
+	defer a.peerUsersMu.Unlock()
+	return a.peerUsers[addrPort.Addr().Unmap()]
+}
+
+func hasStopScripts(scripts []codersdk.WorkspaceAgentScript) bool {
+	for _, script := range scripts {
+		if script.RunOnStop {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *agent) runStartupScript(ctx context.Context, script string) error {
 	return a.runScript(ctx, "startup", script)
 }
@@ -979,6 +1197,14 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		}
 		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
 
+		// Clients connecting directly are attributed by the coordinator.
+		// coderd and workspace proxies connect on behalf of the user they
+		// authenticated and report it in the init message instead.
+		userID := a.peerUser(conn.RemoteAddr())
+		if userID == uuid.Nil {
+			userID = msg.UserID
+		}
+
 		// Default to buffer 64KiB.
 		circularBuffer, err := circbuf.NewBuffer(64 << 10)
 		if err != nil {
@@ -990,6 +1216,11 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			a.metrics.reconnectingPTYErrors.WithLabelValues("start_command").Add(1)
 			return xerrors.Errorf("start command: %w", err)
 		}
+		untrack := a.sessions.Track(agentproc.Session{
+			Type:      string(codersdk.SessionRecordingTypeReconnectingPTY),
+			PID:       process.PID(),
+			StartedAt: time.Now(),
+		})
 
 		ctx, cancel := context.WithCancel(ctx)
 		rpty = &reconnectingPTY{
@@ -1002,6 +1233,7 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			// Timeouts created with an after func can be reset!
 			timeout:        time.AfterFunc(a.reconnectingPTYTimeout, cancel),
 			circularBuffer: circularBuffer,
+			recorder:       a.newSessionRecorder(codersdk.SessionRecordingTypeReconnectingPTY, msg.Width, msg.Height, "xterm-256color", userID),
 		}
 		// We don't need to separately monitor for the process exiting.
 		// When it exits, our ptty.OutputReader() will return EOF after
@@ -1022,6 +1254,9 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 					break
 				}
 				part := buffer[:read]
+				if rpty.recorder != nil {
+					rpty.recorder.Output(part)
+				}
 				rpty.circularBufferMutex.Lock()
 				_, err = rpty.circularBuffer.Write(part)
 				rpty.circularBufferMutex.Unlock()
@@ -1047,17 +1282,25 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			// Cleanup the process, PTY, and delete it's
 			// ID from memory.
 			_ = process.Kill()
+			untrack()
 			rpty.Close()
 			a.reconnectingPTYs.Delete(msg.ID)
 		}); err != nil {
 			_ = process.Kill()
+			untrack()
 			_ = ptty.Close()
+			if rpty.recorder != nil {
+				_ = rpty.recorder.Close()
+			}
 			return xerrors.Errorf("start routine: %w", err)
 		}
 		connected = true
 		sendConnected <- rpty
 	}
 	// Resize the PTY to initial height + width.
+	if rpty.recorder != nil {
+		rpty.recorder.Resize(msg.Width, msg.Height)
+	}
 	err := rpty.ptty.Resize(msg.Height, msg.Width)
 	if err != nil {
 		// We can continue after this, it's not fatal!
@@ -1123,6 +1366,12 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			logger.Warn(ctx, "reconnecting PTY failed with read error", slog.Error(err))
 			return nil
 		}
+		if req.Data != "" {
+			a.activity.Touch()
+		}
+		if rpty.recorder != nil {
+			rpty.recorder.Input([]byte(req.Data))
+		}
 		_, err = rpty.ptty.InputWriter().Write([]byte(req.Data))
 		if err != nil {
 			logger.Warn(ctx, "reconnecting PTY failed with write error", slog.Error(err))
@@ -1133,6 +1382,9 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		if req.Height == 0 || req.Width == 0 {
 			continue
 		}
+		if rpty.recorder != nil {
+			rpty.recorder.Resize(req.Width, req.Height)
+		}
 		err = rpty.ptty.Resize(req.Height, req.Width)
 		if err != nil {
 			// We can continue after this, it's not fatal!
@@ -1165,6 +1417,9 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 
 		stats.SessionCountReconnectingPTY = a.connCountReconnectingPTY.Load()
 
+		a.activity.TouchConnections(networkStats)
+		stats.LastActivityAt = a.activity.LastActivity()
+
 		// Compute the median connection latency!
 		var wg sync.WaitGroup
 		var mu sync.Mutex
@@ -1297,11 +1552,20 @@ func (a *agent) Close() error {
 	}
 
 	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
-	if manifest := a.manifest.Load(); manifest != nil && manifest.ShutdownScript != "" {
+	if manifest := a.manifest.Load(); manifest != nil && (manifest.ShutdownScript != "" || hasStopScripts(manifest.Scripts)) {
 		scriptDone := make(chan error, 1)
 		go func() {
 			defer close(scriptDone)
-			scriptDone <- a.runShutdownScript(ctx, manifest.ShutdownScript)
+			var eg errgroup.Group
+			eg.Go(func() error {
+				return a.runShutdownScript(ctx, manifest.ShutdownScript)
+			})
+			eg.Go(func() error {
+				return a.scriptRunner.Execute(ctx, func(script codersdk.WorkspaceAgentScript) bool {
+					return script.RunOnStop
+				})
+			})
+			scriptDone <- eg.Wait()
 		}()
 
 		var timeout <-chan time.Time
@@ -1346,15 +1610,21 @@ lifecycleWaitLoop:
 		}
 	}
 
+	a.closeResources()
+	return nil
+}
+
+// closeResources stops the agent and waits for its goroutines to exit.
+// The caller must hold closeMutex.
+func (a *agent) closeResources() {
 	close(a.closed)
 	a.closeCancel()
 	_ = a.sshServer.Close()
+	_ = a.scriptRunner.Close()
 	if a.network != nil {
 		_ = a.network.Close()
 	}
 	a.connCloseWait.Wait()
-
-	return nil
 }
 
 type reconnectingPTY struct {
@@ -1365,6 +1635,8 @@ type reconnectingPTY struct {
 	circularBufferMutex sync.RWMutex
 	timeout             *time.Timer
 	ptty                pty.PTYCmd
+	// recorder is nil if sessions aren't recorded.
+	recorder *agentrecord.Recorder
 }
 
 // Close ends all connections to the reconnecting
@@ -1380,6 +1652,27 @@ func (r *reconnectingPTY) Close() {
 	r.circularBuffer.Reset()
 	r.circularBufferMutex.Unlock()
 	r.timeout.Stop()
+	if r.recorder != nil {
+		_ = r.recorder.Close()
+	}
+}
+
+// newSessionRecorder starts a recording of a PTY session of userID if the
+// deployment records sessions, otherwise it returns nil.
+func (a *agent) newSessionRecorder(typ codersdk.SessionRecordingType, width, height uint16, term string, userID uuid.UUID) *agentrecord.Recorder {
+	manifest := a.manifest.Load()
+	if manifest == nil || !manifest.RecordSessions {
+		return nil
+	}
+	return agentrecord.New(agentrecord.Options{
+		Logger: a.logger.Named("recorder").With(slog.F("type", typ)),
+		Type:   typ,
+		Width:  width,
+		Height: height,
+		Env:    map[string]string{"TERM": term},
+		UserID: userID,
+		Upload: a.client.PostSessionRecording,
+	})
 }
 
 // userHomeDir returns the home directory of the current user, giving
@@ -1429,3 +1722,7 @@ func expandDirectory(dir string) (string, error) {
 // specialized environment in which the agent is running
 // (e.g. envbox, envbuilder).
 const EnvAgentSubsystem = "CODER_AGENT_SUBSYSTEM"
+
+// EnvAgentRestartLifecycle is the environment variable an agent passes its
+// lifecycle state in when it restarts into an updated binary.
+const EnvAgentRestartLifecycle = "CODER_AGENT_RESTART_LIFECYCLE"
@@ -1,16 +1,22 @@
 package agent_test
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha1" //#nosec // Matches the checksums coderd serves.
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/netip"
+	"net/url"
 	"os"
 	"os/user"
 	"path"
@@ -44,6 +50,7 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
 	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/agent/agentupdate"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -122,6 +129,74 @@ func TestAgent_Stats_ReconnectingPTY(t *testing.T) {
 	)
 }
 
+func TestAgent_Stats_Activity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Heartbeat", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+		// Being connected isn't activity.
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		var s *agentsdk.Stats
+		require.Eventuallyf(t, func() bool {
+			var ok bool
+			s, ok = <-stats
+			return ok && s.ConnectionCount > 0
+		}, testutil.WaitLong, testutil.IntervalFast,
+			"never saw stats: %+v", s,
+		)
+		require.True(t, s.LastActivityAt.IsZero())
+
+		before := time.Now()
+		err = conn.ReportActivity(ctx)
+		require.NoError(t, err)
+		require.Eventuallyf(t, func() bool {
+			var ok bool
+			s, ok = <-stats
+			return ok && !s.LastActivityAt.Before(before)
+		}, testutil.WaitLong, testutil.IntervalFast,
+			"never saw stats: %+v", s,
+		)
+	})
+
+	t.Run("ReconnectingPTYInput", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+		ptyConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 128, 128, "/bin/bash")
+		require.NoError(t, err)
+		defer ptyConn.Close()
+
+		before := time.Now()
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo test\r\n",
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(data)
+		require.NoError(t, err)
+
+		var s *agentsdk.Stats
+		require.Eventuallyf(t, func() bool {
+			var ok bool
+			s, ok = <-stats
+			return ok && !s.LastActivityAt.Before(before)
+		}, testutil.WaitLong, testutil.IntervalFast,
+			"never saw stats: %+v", s,
+		)
+	})
+}
+
 func TestAgent_Stats_Magic(t *testing.T) {
 	t.Parallel()
 	t.Run("StripsEnvironmentVariable", func(t *testing.T) {
@@ -789,6 +864,114 @@ func TestAgent_SCP(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAgent_Files(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	//nolint:dogsled
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	dir := filepath.Join(os.TempDir(), "files")
+
+	// nolint:paralleltest
+	t.Run("WriteRead", func(t *testing.T) {
+		path := filepath.Join(dir, "write", "nested", "hello.txt")
+		file, err := conn.WriteFile(ctx, path, 0o600, strings.NewReader("hello"))
+		require.NoError(t, err)
+		require.Equal(t, path, file.Path)
+		require.EqualValues(t, 5, file.Size)
+		require.False(t, file.IsDir)
+
+		got, err := afero.ReadFile(fs, path)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(got))
+
+		rc, err := conn.ReadFile(ctx, path)
+		require.NoError(t, err)
+		defer rc.Close()
+		got, err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(got))
+	})
+
+	// nolint:paralleltest
+	t.Run("List", func(t *testing.T) {
+		path := filepath.Join(dir, "list")
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(path, "a.txt"), []byte("a"), 0o600))
+		require.NoError(t, fs.MkdirAll(filepath.Join(path, "b"), 0o755))
+
+		resp, err := conn.ListFiles(ctx, path)
+		require.NoError(t, err)
+		require.True(t, resp.File.IsDir)
+		require.Len(t, resp.Files, 2)
+		require.Equal(t, "a.txt", resp.Files[0].Name)
+		require.Equal(t, "b", resp.Files[1].Name)
+		require.True(t, resp.Files[1].IsDir)
+	})
+
+	// nolint:paralleltest
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := conn.ListFiles(ctx, filepath.Join(dir, "missing"))
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+
+	// nolint:paralleltest
+	t.Run("Tar", func(t *testing.T) {
+		src := filepath.Join(dir, "tar", "src")
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(src, "a.txt"), []byte("a"), 0o600))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(src, "sub", "b.txt"), []byte("b"), 0o600))
+
+		rc, err := conn.DownloadTar(ctx, src)
+		require.NoError(t, err)
+		defer rc.Close()
+		var buf bytes.Buffer
+		_, err = io.Copy(&buf, rc)
+		require.NoError(t, err)
+
+		names := []string{}
+		reader := tar.NewReader(bytes.NewReader(buf.Bytes()))
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, header.Name)
+		}
+		require.ElementsMatch(t, []string{"a.txt", "sub/", "sub/b.txt"}, names)
+
+		dst := filepath.Join(dir, "tar", "dst")
+		file, err := conn.UploadTar(ctx, dst, &buf)
+		require.NoError(t, err)
+		require.True(t, file.IsDir)
+		got, err := afero.ReadFile(fs, filepath.Join(dst, "sub", "b.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "b", string(got))
+	})
+
+	// nolint:paralleltest
+	t.Run("TarOutsideDirectory", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := tar.NewWriter(&buf)
+		require.NoError(t, writer.WriteHeader(&tar.Header{
+			Name:     "../escape.txt",
+			Typeflag: tar.TypeReg,
+			Mode:     0o600,
+			Size:     1,
+		}))
+		_, err := writer.Write([]byte("x"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		_, err = conn.UploadTar(ctx, filepath.Join(dir, "escape"), &buf)
+		require.ErrorContains(t, err, "outside of the target directory")
+		_, err = fs.Stat(filepath.Join(dir, "escape.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
 func TestAgent_EnvironmentVariables(t *testing.T) {
 	t.Parallel()
 	key := "EXAMPLE"
@@ -950,6 +1133,75 @@ func TestAgent_StartupScript(t *testing.T) {
 	})
 }
 
+func TestAgent_Scripts(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts use sh")
+	}
+	t.Run("RunOnStart", func(t *testing.T) {
+		t.Parallel()
+		succeeds := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			DisplayName: "succeeds",
+			Script:      "echo hello",
+			RunOnStart:  true,
+		}
+		fails := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			DisplayName: "fails",
+			Script:      "exit 3",
+			RunOnStart:  true,
+		}
+		stop := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			DisplayName: "stop",
+			Script:      "echo bye",
+			RunOnStop:   true,
+		}
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: []codersdk.WorkspaceAgentScript{succeeds, fails, stop},
+		}, 0)
+
+		require.Eventually(t, func() bool {
+			return slices.Contains(client.getLifecycleStates(), codersdk.WorkspaceAgentLifecycleStartError)
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		final := map[uuid.UUID]agentsdk.PostScriptStatusRequest{}
+		for _, status := range client.getScriptStatuses() {
+			if status.Status != codersdk.WorkspaceAgentScriptStatusRunning {
+				final[status.LogSourceID] = status
+			}
+		}
+		require.Len(t, final, 2)
+		require.Equal(t, codersdk.WorkspaceAgentScriptStatusSucceeded, final[succeeds.LogSourceID].Status)
+		require.Equal(t, codersdk.WorkspaceAgentScriptStatusFailed, final[fails.LogSourceID].Status)
+		require.EqualValues(t, 3, final[fails.LogSourceID].ExitCode)
+		require.NotContains(t, final, stop.LogSourceID)
+
+		logs := client.getStartupLogs()
+		require.Len(t, logs, 1)
+		require.Equal(t, "hello", logs[0].Output)
+	})
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		script := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			DisplayName: "sleeps",
+			Script:      "sleep 10",
+			RunOnStart:  true,
+			Timeout:     time.Millisecond,
+		}
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: []codersdk.WorkspaceAgentScript{script},
+		}, 0)
+
+		require.Eventually(t, func() bool {
+			statuses := client.getScriptStatuses()
+			return len(statuses) > 0 && statuses[len(statuses)-1].Status == codersdk.WorkspaceAgentScriptStatusTimedOut
+		}, testutil.WaitShort, testutil.IntervalMedium)
+	})
+}
+
 func TestAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1017,6 +1269,38 @@ func TestAgent_Metadata(t *testing.T) {
 			t.Fatalf("expected metadata to be collected again")
 		}
 	})
+
+	t.Run("Collector", func(t *testing.T) {
+		t.Parallel()
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Metadata: []codersdk.WorkspaceAgentMetadataDescription{
+				{
+					Key:       "memory",
+					Interval:  1,
+					Timeout:   100,
+					Collector: "memory",
+				},
+				{
+					Key:       "bad",
+					Interval:  1,
+					Timeout:   100,
+					Collector: "gpu",
+				},
+			},
+		}, 0)
+
+		var gotMd map[string]agentsdk.PostMetadataRequest
+		require.Eventually(t, func() bool {
+			gotMd = client.getMetadata()
+			return len(gotMd) == 2
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		assert.Empty(t, gotMd["memory"].Error)
+		assert.Contains(t, gotMd["memory"].Value, "GiB")
+		assert.Empty(t, gotMd["bad"].Value)
+		assert.Contains(t, gotMd["bad"].Error, `unknown collector "gpu"`)
+	})
 }
 
 func TestAgentMetadata_Timing(t *testing.T) {
@@ -1332,6 +1616,92 @@ func TestAgent_Lifecycle(t *testing.T) {
 	})
 }
 
+func TestAgent_Update(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+
+		binary := []byte("#!/bin/sh\necho updated\n")
+		//nolint:gosec // Matches the checksums coderd serves.
+		hash := sha1.Sum(binary)
+		// Hold the download until the agent is reachable, otherwise the
+		// agent could restart before the test connects.
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			<-release
+			assert.Equal(t, "/bin/coder-linux-amd64", r.URL.Path)
+			_, _ = rw.Write(binary)
+		}))
+		t.Cleanup(srv.Close)
+		srvURL, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+
+		executable := filepath.Join(t.TempDir(), "coder")
+		err = os.WriteFile(executable, []byte("old"), 0o600)
+		require.NoError(t, err)
+
+		restarted := make(chan codersdk.WorkspaceAgentLifecycle, 1)
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			ServerVersion:        "v99.0.0",
+			ServerBinaryChecksum: hex.EncodeToString(hash[:]),
+		}, 0, func(o agent.Options) agent.Options {
+			o.Update = &agent.UpdateOptions{
+				Executable: executable,
+				BinaryURL:  agentupdate.BinaryURL(srvURL, "linux", "amd64"),
+				HTTPClient: srv.Client(),
+				Restart: func(lifecycle codersdk.WorkspaceAgentLifecycle) error {
+					restarted <- lifecycle
+					return nil
+				},
+			}
+			return o
+		})
+		close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for restart")
+		case lifecycle := <-restarted:
+			require.Equal(t, codersdk.WorkspaceAgentLifecycleReady, lifecycle)
+		}
+
+		got, err := os.ReadFile(executable)
+		require.NoError(t, err)
+		require.Equal(t, binary, got)
+		// The agent doesn't shut down, it continues in the new binary.
+		require.Equal(t, []codersdk.WorkspaceAgentLifecycle{
+			codersdk.WorkspaceAgentLifecycleStarting,
+			codersdk.WorkspaceAgentLifecycleReady,
+		}, client.getLifecycleStates())
+	})
+
+	t.Run("Restarted", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "started")
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			StartupScript: "touch " + path,
+		}, 0, func(o agent.Options) agent.Options {
+			o.RestartLifecycle = codersdk.WorkspaceAgentLifecycleReady
+			return o
+		})
+
+		require.Eventually(t, func() bool {
+			return client.getStartup().Version != ""
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Never(t, func() bool {
+			_, err := os.Stat(path)
+			return err == nil
+		}, testutil.IntervalSlow, testutil.IntervalFast)
+		require.Empty(t, client.getLifecycleStates())
+	})
+}
+
 func TestAgent_Startup(t *testing.T) {
 	t.Parallel()
 
@@ -1464,6 +1834,168 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	expectLine(matchEchoOutput)
 }
 
+func TestAgent_Processes(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The test relies on sleep being available.")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	//nolint:dogsled
+	conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		ProcessReportInterval: testutil.IntervalFast,
+	}, 0)
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	err = session.Start("sleep 60")
+	require.NoError(t, err)
+
+	// The session is reported along with the resource usage of its
+	// processes.
+	var sessionPID int32
+	require.Eventually(t, func() bool {
+		reports := client.getProcesses()
+		if len(reports) == 0 {
+			return false
+		}
+		report := reports[len(reports)-1]
+		for _, session := range report.Sessions {
+			if session.Type == "ssh" && session.ProcessCount > 0 {
+				sessionPID = session.PID
+				return report.ProcessCount > 0 && report.Memory.Used > 0
+			}
+		}
+		return false
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	err = conn.KillProcess(ctx, sessionPID, codersdk.KillWorkspaceAgentProcessRequest{
+		Signal: codersdk.WorkspaceAgentProcessSignalKill,
+	})
+	require.NoError(t, err)
+	err = session.Wait()
+	var exitErr *ssh.ExitError
+	require.ErrorAs(t, err, &exitErr)
+
+	// Signaling a process that doesn't exist fails.
+	err = conn.KillProcess(ctx, math.MaxInt32, codersdk.KillWorkspaceAgentProcessRequest{})
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
+
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	// recording returns the uploaded recording once the final chunk has
+	// been received.
+	recording := func(t *testing.T, client *client) (codersdk.SessionRecordingType, string) {
+		t.Helper()
+		var chunks []agentsdk.PostSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			chunks = client.getRecordings()
+			return len(chunks) > 0 && chunks[len(chunks)-1].Final
+		}, testutil.WaitLong, testutil.IntervalFast)
+		var data []byte
+		for _, chunk := range chunks {
+			require.Equal(t, chunks[0].ID, chunk.ID)
+			data = append(data, chunk.Data...)
+		}
+		return chunks[0].Type, string(data)
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{RecordSessions: true}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		ptty := ptytest.New(t)
+		session.Stdout = ptty.Output()
+		session.Stderr = ptty.Output()
+		session.Stdin = ptty.Input()
+		err = session.Start("sh")
+		require.NoError(t, err)
+		_ = ptty.Peek(ctx, 1) // wait for the prompt
+		ptty.WriteLine("echo recorded")
+		ptty.ExpectMatch("recorded")
+		ptty.WriteLine("exit")
+		err = session.Wait()
+		require.NoError(t, err)
+
+		typ, data := recording(t, client)
+		require.Equal(t, codersdk.SessionRecordingTypeSSH, typ)
+		require.Contains(t, data, `"version":2`)
+		require.Contains(t, data, `"i","echo recorded`)
+		require.Contains(t, data, `"o",`)
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{RecordSessions: true}, 0)
+		// The test client isn't tied to a user by the coordinator, so the
+		// user from the init message is used.
+		userID := uuid.New()
+		netConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 24, 80, "sh", codersdk.WithReconnectingPTYUser(userID))
+		require.NoError(t, err)
+		defer netConn.Close()
+
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo recorded; exit\r",
+		})
+		require.NoError(t, err)
+		_, err = netConn.Write(data)
+		require.NoError(t, err)
+
+		typ, recorded := recording(t, client)
+		require.Equal(t, codersdk.SessionRecordingTypeReconnectingPTY, typ)
+		require.Contains(t, recorded, `"width":80`)
+		require.Contains(t, recorded, `"i","echo recorded; exit\r"`)
+		require.Equal(t, userID, client.getRecordings()[0].UserID)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		output, err := session.Output("echo hello")
+		require.NoError(t, err)
+		require.Contains(t, string(output), "hello")
+		require.Empty(t, client.getRecordings())
+	})
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -1814,6 +2346,9 @@ type client struct {
 	lifecycleStates []codersdk.WorkspaceAgentLifecycle
 	startup         agentsdk.PostStartupRequest
 	logs            []agentsdk.StartupLog
+	scriptStatuses  []agentsdk.PostScriptStatusRequest
+	recordings      []agentsdk.PostSessionRecordingRequest
+	processes       []codersdk.WorkspaceAgentProcesses
 }
 
 func (c *client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
@@ -1930,6 +2465,45 @@ func (c *client) PatchStartupLogs(_ context.Context, logs agentsdk.PatchStartupL
 	return nil
 }
 
+func (c *client) getScriptStatuses() []agentsdk.PostScriptStatusRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.scriptStatuses)
+}
+
+func (c *client) PostScriptStatus(_ context.Context, req agentsdk.PostScriptStatusRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scriptStatuses = append(c.scriptStatuses, req)
+	return nil
+}
+
+func (c *client) getRecordings() []agentsdk.PostSessionRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.recordings)
+}
+
+func (c *client) PostSessionRecording(_ context.Context, req agentsdk.PostSessionRecordingRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordings = append(c.recordings, req)
+	return nil
+}
+
+func (c *client) getProcesses() []codersdk.WorkspaceAgentProcesses {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.processes)
+}
+
+func (c *client) PostProcesses(_ context.Context, req codersdk.WorkspaceAgentProcesses) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processes = append(c.processes, req)
+	return nil
+}
+
 // tempDirUnixSocket returns a temporary directory that can safely hold unix
 // sockets (probably).
 //
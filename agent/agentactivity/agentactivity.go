@@ -0,0 +1,93 @@
+// Package agentactivity tracks when a user last interacted with a
+// workspace. Unlike connection counts, an idle terminal or an editor left
+// open overnight doesn't count as activity.
+package agentactivity
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"tailscale.com/types/netlogtype"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// Tracker records the time of the most recent user activity. It is safe
+// for concurrent use, and a nil Tracker ignores activity.
+type Tracker struct {
+	// last is the time of the most recent activity in Unix nanoseconds.
+	last atomic.Int64
+	now  func() time.Time
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{now: time.Now}
+}
+
+// Touch records activity at the current time.
+func (t *Tracker) Touch() {
+	if t == nil {
+		return
+	}
+	t.last.Store(t.now().UnixNano())
+}
+
+// LastActivity returns the time of the most recent activity, or the zero
+// time if there hasn't been any.
+func (t *Tracker) LastActivity() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	last := t.last.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+// Reader returns a reader that records activity whenever data is read from
+// r, e.g. input typed into a terminal.
+func (t *Tracker) Reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &reader{tracker: t, r: r}
+}
+
+type reader struct {
+	tracker *Tracker
+	r       io.Reader
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.tracker.Touch()
+	}
+	return n, err
+}
+
+// agentPorts are the ports of the agent's own services. Their traffic is
+// either tracked by the services themselves or isn't user activity.
+var agentPorts = map[uint16]struct{}{
+	codersdk.WorkspaceAgentSSHPort:             {},
+	codersdk.WorkspaceAgentReconnectingPTYPort: {},
+	codersdk.WorkspaceAgentSpeedtestPort:       {},
+	codersdk.WorkspaceAgentHTTPAPIServerPort:   {},
+}
+
+// TouchConnections records activity if any of the connections received
+// data on a port other than the agent's own, i.e. a workspace app or a
+// forwarded port was used. The source of the connections is the agent.
+func (t *Tracker) TouchConnections(connections map[netlogtype.Connection]netlogtype.Counts) {
+	for conn, counts := range connections {
+		if _, ok := agentPorts[conn.Src.Port()]; ok {
+			continue
+		}
+		if counts.RxBytes > 0 {
+			t.Touch()
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package agentactivity
+
+import (
+	"io"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/types/netlogtype"
+
+	"github.com/coder/coder/codersdk"
+)
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := &Tracker{now: func() time.Time { return now }}
+	require.True(t, tracker.LastActivity().IsZero())
+
+	// Reading no input isn't activity.
+	_, err := io.ReadAll(tracker.Reader(strings.NewReader("")))
+	require.NoError(t, err)
+	require.True(t, tracker.LastActivity().IsZero())
+
+	_, err = io.ReadAll(tracker.Reader(strings.NewReader("ls\n")))
+	require.NoError(t, err)
+	require.True(t, now.Equal(tracker.LastActivity()))
+
+	// Traffic to the agent's own services isn't app activity.
+	now = now.Add(time.Minute)
+	agentAddr := netip.MustParseAddr("fd7a:115c:a1e0::1")
+	tracker.TouchConnections(map[netlogtype.Connection]netlogtype.Counts{
+		{Src: netip.AddrPortFrom(agentAddr, codersdk.WorkspaceAgentHTTPAPIServerPort)}: {RxBytes: 100},
+		{Src: netip.AddrPortFrom(agentAddr, 8080)}:                                     {TxBytes: 100},
+	})
+	require.True(t, now.Add(-time.Minute).Equal(tracker.LastActivity()))
+
+	tracker.TouchConnections(map[netlogtype.Connection]netlogtype.Counts{
+		{Src: netip.AddrPortFrom(agentAddr, 8080)}: {RxBytes: 100},
+	})
+	require.True(t, now.Equal(tracker.LastActivity()))
+
+	var nilTracker *Tracker
+	nilTracker.Touch()
+	require.True(t, nilTracker.LastActivity().IsZero())
+}
@@ -0,0 +1,240 @@
+// Package agentfiles implements the file transfer primitives used by the
+// agent file API and the "coder cp" command.
+package agentfiles
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+)
+
+// WriteFile writes the contents of r to path, creating parent directories
+// as needed. The contents are written to a temporary file alongside path
+// and renamed into place, so readers never observe a partially written
+// file.
+func WriteFile(fs afero.Fs, path string, mode os.FileMode, r io.Reader) error {
+	dir := filepath.Dir(path)
+	err := fs.MkdirAll(dir, 0o755)
+	if err != nil {
+		return xerrors.Errorf("create parent directory: %w", err)
+	}
+	tmp, err := afero.TempFile(fs, dir, "."+filepath.Base(path)+".coder-*")
+	if err != nil {
+		return xerrors.Errorf("create temporary file: %w", err)
+	}
+	err = func() error {
+		_, err := io.Copy(tmp, r)
+		if err != nil {
+			_ = tmp.Close()
+			return xerrors.Errorf("write file: %w", err)
+		}
+		err = tmp.Close()
+		if err != nil {
+			return xerrors.Errorf("close file: %w", err)
+		}
+		err = fs.Chmod(tmp.Name(), mode)
+		if err != nil {
+			return xerrors.Errorf("chmod file: %w", err)
+		}
+		err = fs.Rename(tmp.Name(), path)
+		if err != nil {
+			return xerrors.Errorf("rename file: %w", err)
+		}
+		return nil
+	}()
+	if err != nil {
+		_ = fs.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Tar writes the file or directory at root to w as a tar archive. Entries
+// are named relative to root, or to its parent directory if root is a file.
+func Tar(fs afero.Fs, w io.Writer, root string) error {
+	rootInfo, err := fs.Stat(root)
+	if err != nil {
+		return err
+	}
+	tarWriter := tar.NewWriter(w)
+	base := root
+	if !rootInfo.IsDir() {
+		base = filepath.Dir(root)
+	}
+	err = afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if reader, ok := fs.(afero.LinkReader); ok {
+				link, err = reader.ReadlinkIfPossible(path)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		// Use unix paths in the tar archive.
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := fs.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tarWriter.Close()
+}
+
+// Untar extracts the tar archive read from r into dir, creating it if
+// needed. Entries that would be extracted outside of dir, either directly or
+// through a symbolic link, are rejected.
+func Untar(fs afero.Fs, dir string, r io.Reader) error {
+	dir = filepath.Clean(dir)
+	err := fs.MkdirAll(dir, 0o755)
+	if err != nil {
+		return xerrors.Errorf("create directory: %w", err)
+	}
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read tar: %w", err)
+		}
+		// #nosec G305 -- Entries escaping dir are rejected below.
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !withinDir(dir, target) {
+			return xerrors.Errorf("tar entry %q is outside of the target directory", header.Name)
+		}
+		// A previously extracted or pre-existing symlink could redirect the
+		// entry outside of dir, so every parent is resolved first.
+		err = checkParents(fs, dir, target)
+		if err != nil {
+			return xerrors.Errorf("extract %q: %w", header.Name, err)
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = fs.MkdirAll(target, mode|0o700)
+		case tar.TypeReg:
+			err = WriteFile(fs, target, mode, tarReader)
+		case tar.TypeSymlink:
+			linker, ok := fs.(afero.Linker)
+			if !ok {
+				continue
+			}
+			link := filepath.FromSlash(header.Linkname)
+			if !filepath.IsAbs(link) {
+				link = filepath.Join(filepath.Dir(target), link)
+			}
+			if !withinDir(dir, filepath.Clean(link)) {
+				return xerrors.Errorf("tar entry %q links to %q outside of the target directory", header.Name, header.Linkname)
+			}
+			_ = fs.Remove(target)
+			err = linker.SymlinkIfPossible(header.Linkname, target)
+		default:
+			// Other entry types aren't supported.
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("extract %q: %w", header.Name, err)
+		}
+	}
+}
+
+// maxSymlinkHops bounds how many symlinks are followed when resolving a
+// single path component, to guard against symlink loops.
+const maxSymlinkHops = 255
+
+// withinDir reports whether the cleaned path is dir or is inside of it.
+func withinDir(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// checkParents resolves each existing parent directory of target below dir
+// and returns an error if any of them is a symlink that leads outside of
+// dir. Filesystems without symlink support are always accepted.
+func checkParents(fs afero.Fs, dir, target string) error {
+	lstater, ok := fs.(afero.Lstater)
+	if !ok {
+		return nil
+	}
+	reader, ok := fs.(afero.LinkReader)
+	if !ok {
+		return nil
+	}
+	rel, err := filepath.Rel(dir, filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+	current := dir
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		for hops := 0; ; hops++ {
+			if hops > maxSymlinkHops {
+				return xerrors.Errorf("too many levels of symbolic links at %q", current)
+			}
+			info, _, err := lstater.LstatIfPossible(current)
+			if errors.Is(err, os.ErrNotExist) {
+				// The rest of the path will be created as directories.
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if info.Mode()&os.ModeSymlink == 0 {
+				break
+			}
+			link, err := reader.ReadlinkIfPossible(current)
+			if err != nil {
+				return err
+			}
+			if !filepath.IsAbs(link) {
+				link = filepath.Join(filepath.Dir(current), link)
+			}
+			link = filepath.Clean(link)
+			if !withinDir(dir, link) {
+				return xerrors.Errorf("%q links to %q outside of the target directory", current, link)
+			}
+			current = link
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package agentfiles_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/agent/agentfiles"
+)
+
+func TestUntar(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+
+	t.Run("SymlinkInside", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		archive := tarArchive(t,
+			&tar.Header{Name: "a.txt", Typeflag: tar.TypeReg, Mode: 0o600, Size: 1},
+			&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "a.txt"},
+		)
+		err := agentfiles.Untar(afero.NewOsFs(), dir, archive)
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(dir, "link"))
+		require.NoError(t, err)
+		require.Equal(t, "x", string(got))
+	})
+
+	t.Run("SymlinkOutside", func(t *testing.T) {
+		t.Parallel()
+		dir := filepath.Join(t.TempDir(), "dst")
+		archive := tarArchive(t,
+			&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../.."},
+		)
+		err := agentfiles.Untar(afero.NewOsFs(), dir, archive)
+		require.ErrorContains(t, err, "outside of the target directory")
+		_, err = os.Lstat(filepath.Join(dir, "link"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("WriteThroughSymlink", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		outside := filepath.Join(root, "outside")
+		dir := filepath.Join(root, "dst")
+		require.NoError(t, os.MkdirAll(outside, 0o755))
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.Symlink(outside, filepath.Join(dir, "link")))
+
+		archive := tarArchive(t,
+			&tar.Header{Name: "link/escape.txt", Typeflag: tar.TypeReg, Mode: 0o600, Size: 1},
+		)
+		err := agentfiles.Untar(afero.NewOsFs(), dir, archive)
+		require.ErrorContains(t, err, "outside of the target directory")
+		_, err = os.Stat(filepath.Join(outside, "escape.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// tarArchive returns a tar archive of the given headers. Regular files are
+// filled with "x" bytes up to their size.
+func tarArchive(t *testing.T, headers ...*tar.Header) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for _, header := range headers {
+		require.NoError(t, writer.WriteHeader(header))
+		if header.Typeflag == tar.TypeReg {
+			_, err := writer.Write(bytes.Repeat([]byte("x"), int(header.Size)))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, writer.Close())
+	return &buf
+}
@@ -0,0 +1,114 @@
+// Package agentmetadata evaluates built-in metadata collectors in-process.
+// Running a shell script for every metadata item is expensive at short
+// intervals, so common system statistics can be selected by name instead.
+package agentmetadata
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clistat"
+)
+
+const (
+	// CollectorCPU reports the CPU usage of the host in cores.
+	CollectorCPU = "cpu"
+	// CollectorMemory reports the memory usage of the host.
+	CollectorMemory = "memory"
+	// CollectorDisk reports the disk usage of the path given as an
+	// argument, e.g. "disk:/var". It defaults to the home directory.
+	CollectorDisk = "disk"
+	// CollectorLoad reports the one minute load average of the host.
+	CollectorLoad = "load"
+	// CollectorNetwork reports the throughput of the network interface
+	// given as an argument, e.g. "network:eth0". It defaults to all
+	// interfaces except loopback.
+	CollectorNetwork = "network"
+	// CollectorProcessCount reports the number of running processes.
+	CollectorProcessCount = "process_count"
+	// CollectorContainerCPU reports the CPU usage of the container the
+	// agent runs in, or "-" if it isn't containerized.
+	CollectorContainerCPU = "container_cpu"
+	// CollectorContainerMemory reports the memory usage of the container
+	// the agent runs in, or "-" if it isn't containerized.
+	CollectorContainerMemory = "container_memory"
+)
+
+// Collectors evaluates built-in collectors. It is safe for concurrent use.
+type Collectors struct {
+	statter *clistat.Statter
+}
+
+func New(opts ...clistat.Option) (*Collectors, error) {
+	st, err := clistat.New(opts...)
+	if err != nil {
+		return nil, xerrors.Errorf("create statter: %w", err)
+	}
+	return &Collectors{statter: st}, nil
+}
+
+// Parse splits a collector into its name and optional argument, e.g.
+// "disk:/home/coder" is the disk collector for /home/coder. It returns an
+// error if the name is not a known collector.
+func Parse(collector string) (name, arg string, err error) {
+	name, arg, _ = strings.Cut(collector, ":")
+	switch name {
+	case CollectorDisk, CollectorNetwork:
+		return name, arg, nil
+	case CollectorCPU, CollectorMemory, CollectorLoad, CollectorProcessCount,
+		CollectorContainerCPU, CollectorContainerMemory:
+		if arg != "" {
+			return "", "", xerrors.Errorf("collector %q does not accept an argument", name)
+		}
+		return name, "", nil
+	default:
+		return "", "", xerrors.Errorf("unknown collector %q", name)
+	}
+}
+
+// Collect evaluates the collector and returns its human-readable value,
+// formatted the same way as "coder stat".
+func (c *Collectors) Collect(ctx context.Context, collector string) (string, error) {
+	name, arg, err := Parse(collector)
+	if err != nil {
+		return "", err
+	}
+
+	var result *clistat.Result
+	switch name {
+	case CollectorCPU:
+		result, err = c.statter.HostCPU()
+	case CollectorMemory:
+		result, err = c.statter.HostMemory(clistat.PrefixGibi)
+	case CollectorDisk:
+		if arg == "" {
+			arg, err = os.UserHomeDir()
+			if err != nil {
+				return "", xerrors.Errorf("get home directory: %w", err)
+			}
+		}
+		result, err = c.statter.Disk(clistat.PrefixGibi, arg)
+	case CollectorLoad:
+		result, err = c.statter.HostLoad()
+	case CollectorNetwork:
+		result, err = c.statter.Network(clistat.PrefixKibi, arg)
+	case CollectorProcessCount:
+		result, err = c.statter.ProcessCount()
+	case CollectorContainerCPU:
+		result, err = c.statter.ContainerCPU()
+	case CollectorContainerMemory:
+		result, err = c.statter.ContainerMemory(clistat.PrefixGibi)
+	}
+	if err != nil {
+		return "", xerrors.Errorf("collect %s: %w", name, err)
+	}
+	// Sampling collectors wait for an interval, so the deadline may have
+	// passed in the meantime.
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
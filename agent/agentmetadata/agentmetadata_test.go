@@ -0,0 +1,83 @@
+package agentmetadata_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/agent/agentmetadata"
+	"github.com/coder/coder/cli/clistat"
+	"github.com/coder/coder/testutil"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		Collector string
+		Name      string
+		Arg       string
+		Error     string
+	}{
+		{Collector: "cpu", Name: agentmetadata.CollectorCPU},
+		{Collector: "disk", Name: agentmetadata.CollectorDisk},
+		{Collector: "disk:/var/lib", Name: agentmetadata.CollectorDisk, Arg: "/var/lib"},
+		{Collector: "network:eth0", Name: agentmetadata.CollectorNetwork, Arg: "eth0"},
+		{Collector: "container_memory", Name: agentmetadata.CollectorContainerMemory},
+		{Collector: "memory:/", Error: `collector "memory" does not accept an argument`},
+		{Collector: "gpu", Error: `unknown collector "gpu"`},
+		{Collector: "", Error: `unknown collector ""`},
+	} {
+		tt := tt
+		t.Run(tt.Collector, func(t *testing.T) {
+			t.Parallel()
+			name, arg, err := agentmetadata.Parse(tt.Collector)
+			if tt.Error != "" {
+				require.ErrorContains(t, err, tt.Error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.Name, name)
+			assert.Equal(t, tt.Arg, arg)
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	c, err := agentmetadata.New(clistat.WithSampleInterval(0))
+	require.NoError(t, err)
+
+	collectors := []string{
+		agentmetadata.CollectorCPU,
+		agentmetadata.CollectorMemory,
+		agentmetadata.CollectorDisk,
+		agentmetadata.CollectorProcessCount,
+		agentmetadata.CollectorContainerCPU,
+		agentmetadata.CollectorContainerMemory,
+	}
+	if runtime.GOOS != "windows" {
+		collectors = append(collectors, agentmetadata.CollectorLoad)
+	}
+	for _, collector := range collectors {
+		collector := collector
+		t.Run(collector, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+			value, err := c.Collect(ctx, collector)
+			require.NoError(t, err)
+			assert.NotEmpty(t, value)
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.Collect(context.Background(), "gpu")
+		require.ErrorContains(t, err, `unknown collector "gpu"`)
+	})
+}
@@ -0,0 +1,336 @@
+// Package agentproc collects the resource usage of the processes inside of
+// a workspace and attributes it to the sessions that started them.
+package agentproc
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/elastic/go-sysinfo"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clistat"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// DefaultTopN is the number of processes reported by CPU and by memory
+	// usage.
+	DefaultTopN = 10
+
+	// maxCmdlineLength truncates long command lines to keep reports small.
+	maxCmdlineLength = 256
+)
+
+// Session is the process started by an SSH session or a reconnecting PTY.
+type Session struct {
+	// Type is one of "ssh", "vscode", "jetbrains" or "reconnecting_pty".
+	Type      string
+	PID       int
+	StartedAt time.Time
+}
+
+// Tracker keeps track of the sessions with running processes. It is safe
+// for concurrent use.
+type Tracker struct {
+	mu       sync.Mutex
+	sessions map[int]Session
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{sessions: map[int]Session{}}
+}
+
+// Track registers a session until the returned function is called.
+func (t *Tracker) Track(session Session) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessions[session.PID] = session
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.sessions, session.PID)
+	}
+}
+
+// Sessions returns the tracked sessions ordered by PID.
+func (t *Tracker) Sessions() []Session {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sessions := make([]Session, 0, len(t.sessions))
+	for _, session := range t.sessions {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].PID < sessions[j].PID
+	})
+	return sessions
+}
+
+// process is the subset of process information used by the collector.
+type process struct {
+	pid       int
+	ppid      int
+	name      string
+	cmdline   string
+	startedAt time.Time
+	cpuTime   time.Duration
+	memory    uint64
+}
+
+// Collector takes snapshots of the resource usage of processes. The CPU
+// usage of a process is computed from the CPU time it used since the
+// previous snapshot, so a Collector should be reused between snapshots.
+type Collector struct {
+	statter       *clistat.Statter
+	topN          int
+	listProcesses func() ([]process, error)
+	now           func() time.Time
+
+	mu          sync.Mutex // Protects following.
+	prevCPUTime map[processKey]time.Duration
+	prevAt      time.Time
+}
+
+// processKey identifies a process, PIDs are reused so the start time is
+// part of the key.
+type processKey struct {
+	pid       int
+	startedAt time.Time
+}
+
+// NewCollector creates a collector that reports the topN processes by
+// CPU and by memory usage.
+func NewCollector(topN int) (*Collector, error) {
+	statter, err := clistat.New()
+	if err != nil {
+		return nil, xerrors.Errorf("create statter: %w", err)
+	}
+	if topN <= 0 {
+		topN = DefaultTopN
+	}
+	return &Collector{
+		statter:       statter,
+		topN:          topN,
+		listProcesses: listProcesses,
+		now:           time.Now,
+	}, nil
+}
+
+// Collect takes a snapshot of the CPU and memory totals, the resource usage
+// of the sessions and the processes using the most resources.
+func (c *Collector) Collect(sessions []Session) (codersdk.WorkspaceAgentProcesses, error) {
+	cpu, err := c.statter.ContainerCPU()
+	if err != nil || cpu == nil {
+		cpu, err = c.statter.HostCPU()
+		if err != nil {
+			return codersdk.WorkspaceAgentProcesses{}, xerrors.Errorf("get cpu usage: %w", err)
+		}
+	}
+	memory, err := c.statter.ContainerMemory(clistat.PrefixDefault)
+	if err != nil || memory == nil {
+		memory, err = c.statter.HostMemory(clistat.PrefixDefault)
+		if err != nil {
+			return codersdk.WorkspaceAgentProcesses{}, xerrors.Errorf("get memory usage: %w", err)
+		}
+	}
+	processes, err := c.listProcesses()
+	if err != nil {
+		return codersdk.WorkspaceAgentProcesses{}, xerrors.Errorf("list processes: %w", err)
+	}
+
+	snapshot := c.snapshot(processes, sessions)
+	snapshot.CPU = convertResult(cpu)
+	snapshot.Memory = convertResult(memory)
+	return snapshot, nil
+}
+
+// snapshot computes the usage of the sessions and selects the processes to
+// report.
+func (c *Collector) snapshot(processes []process, sessions []Session) codersdk.WorkspaceAgentProcesses {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+
+	byPID := make(map[int]process, len(processes))
+	cpuCores := make(map[int]float64, len(processes))
+	cpuTimes := make(map[processKey]time.Duration, len(processes))
+	for _, proc := range processes {
+		byPID[proc.pid] = proc
+		key := processKey{pid: proc.pid, startedAt: proc.startedAt}
+		cpuTimes[key] = proc.cpuTime
+
+		used := proc.cpuTime
+		since := c.prevAt
+		if prev, ok := c.prevCPUTime[key]; ok {
+			used -= prev
+		} else if since.IsZero() || proc.startedAt.After(since) {
+			// The process started after the previous snapshot, or this is
+			// the first snapshot.
+			since = proc.startedAt
+		}
+		if elapsed := now.Sub(since); elapsed > 0 && used > 0 && !since.IsZero() {
+			cpuCores[proc.pid] = used.Seconds() / elapsed.Seconds()
+		}
+	}
+	c.prevCPUTime = cpuTimes
+	c.prevAt = now
+
+	sessionPIDs := make(map[int]struct{}, len(sessions))
+	for _, session := range sessions {
+		sessionPIDs[session.PID] = struct{}{}
+	}
+	// sessionOf memoizes the session each process belongs to, zero if none.
+	sessionOf := make(map[int]int, len(processes))
+	var findSession func(pid int, depth int) int
+	findSession = func(pid int, depth int) int {
+		if session, ok := sessionOf[pid]; ok {
+			return session
+		}
+		session := 0
+		if _, ok := sessionPIDs[pid]; ok {
+			session = pid
+		} else if proc, ok := byPID[pid]; ok && proc.ppid != pid && depth < len(processes) {
+			session = findSession(proc.ppid, depth+1)
+		}
+		sessionOf[pid] = session
+		return session
+	}
+
+	usage := make(map[int]*codersdk.WorkspaceAgentSessionUsage, len(sessions))
+	snapshot := codersdk.WorkspaceAgentProcesses{
+		CollectedAt:  now,
+		ProcessCount: len(processes),
+		Sessions:     make([]codersdk.WorkspaceAgentSessionUsage, 0, len(sessions)),
+		Processes:    []codersdk.WorkspaceAgentProcess{},
+	}
+	for _, session := range sessions {
+		snapshot.Sessions = append(snapshot.Sessions, codersdk.WorkspaceAgentSessionUsage{
+			Type:      session.Type,
+			PID:       int32(session.PID),
+			StartedAt: session.StartedAt,
+		})
+	}
+	for i := range snapshot.Sessions {
+		usage[int(snapshot.Sessions[i].PID)] = &snapshot.Sessions[i]
+	}
+	for _, proc := range processes {
+		session, ok := usage[findSession(proc.pid, 0)]
+		if !ok {
+			continue
+		}
+		session.ProcessCount++
+		session.CPUCores += cpuCores[proc.pid]
+		session.MemoryBytes += int64(proc.memory)
+	}
+
+	// Report the top processes by CPU and by memory usage, along with
+	// their ancestors.
+	selected := map[int]struct{}{}
+	selectTop := func(less func(a, b process) bool) {
+		sorted := append([]process(nil), processes...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return less(sorted[i], sorted[j])
+		})
+		for i := 0; i < len(sorted) && i < c.topN; i++ {
+			pid := sorted[i].pid
+			for depth := 0; depth < len(processes); depth++ {
+				if _, ok := selected[pid]; ok {
+					break
+				}
+				proc, ok := byPID[pid]
+				if !ok {
+					break
+				}
+				selected[pid] = struct{}{}
+				pid = proc.ppid
+			}
+		}
+	}
+	selectTop(func(a, b process) bool {
+		return cpuCores[a.pid] > cpuCores[b.pid]
+	})
+	selectTop(func(a, b process) bool {
+		return a.memory > b.memory
+	})
+	for _, proc := range processes {
+		if _, ok := selected[proc.pid]; !ok {
+			continue
+		}
+		snapshot.Processes = append(snapshot.Processes, codersdk.WorkspaceAgentProcess{
+			PID:         int32(proc.pid),
+			PPID:        int32(proc.ppid),
+			Name:        proc.name,
+			Cmdline:     proc.cmdline,
+			StartedAt:   proc.startedAt,
+			CPUCores:    cpuCores[proc.pid],
+			MemoryBytes: int64(proc.memory),
+			SessionPID:  int32(findSession(proc.pid, 0)),
+		})
+	}
+	sort.Slice(snapshot.Processes, func(i, j int) bool {
+		return snapshot.Processes[i].PID < snapshot.Processes[j].PID
+	})
+	return snapshot
+}
+
+// Kill sends a signal to the process with the given PID. Windows doesn't
+// support SIGTERM, so processes are always killed.
+func Kill(pid int, signal codersdk.WorkspaceAgentProcessSignal) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	var sig os.Signal = syscall.SIGTERM
+	if signal == codersdk.WorkspaceAgentProcessSignalKill || runtime.GOOS == "windows" {
+		sig = os.Kill
+	}
+	return proc.Signal(sig)
+}
+
+// listProcesses lists the processes visible to the agent. Processes that
+// exit while they're listed are skipped.
+func listProcesses() ([]process, error) {
+	procs, err := sysinfo.Processes()
+	if err != nil {
+		return nil, err
+	}
+	processes := make([]process, 0, len(procs))
+	for _, proc := range procs {
+		info, err := proc.Info()
+		if err != nil {
+			continue
+		}
+		p := process{
+			pid:       info.PID,
+			ppid:      info.PPID,
+			name:      info.Name,
+			cmdline:   strings.Join(info.Args, " "),
+			startedAt: info.StartTime,
+		}
+		if len(p.cmdline) > maxCmdlineLength {
+			p.cmdline = p.cmdline[:maxCmdlineLength]
+		}
+		if cpu, err := proc.CPUTime(); err == nil {
+			p.cpuTime = cpu.User + cpu.System
+		}
+		if memory, err := proc.Memory(); err == nil {
+			p.memory = memory.Resident
+		}
+		processes = append(processes, p)
+	}
+	return processes, nil
+}
+
+func convertResult(r *clistat.Result) codersdk.WorkspaceAgentResourceUsage {
+	return codersdk.WorkspaceAgentResourceUsage{
+		Used:  r.Used,
+		Total: r.Total,
+		Unit:  r.Unit,
+	}
+}
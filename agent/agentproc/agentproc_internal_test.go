@@ -0,0 +1,84 @@
+package agentproc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectorSnapshot(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Second)
+	collector := &Collector{
+		topN: 1,
+		now: func() time.Time {
+			return now
+		},
+	}
+	processes := []process{
+		{pid: 1, ppid: 0, name: "init", startedAt: start, memory: 10},
+		{pid: 10, ppid: 1, name: "sshd", startedAt: start, memory: 20},
+		{pid: 20, ppid: 10, name: "bash", startedAt: start, cpuTime: time.Second, memory: 30},
+		{pid: 21, ppid: 20, name: "node", startedAt: start, cpuTime: 10 * time.Second, memory: 500},
+		{pid: 30, ppid: 1, name: "cron", startedAt: start, cpuTime: 5 * time.Second, memory: 40},
+	}
+	sessions := []Session{{Type: "ssh", PID: 20, StartedAt: start}}
+
+	snapshot := collector.snapshot(processes, sessions)
+	require.Equal(t, now, snapshot.CollectedAt)
+	require.Equal(t, 5, snapshot.ProcessCount)
+
+	// The session owns its process and all descendants.
+	require.Len(t, snapshot.Sessions, 1)
+	session := snapshot.Sessions[0]
+	require.EqualValues(t, 20, session.PID)
+	require.Equal(t, 2, session.ProcessCount)
+	require.EqualValues(t, 530, session.MemoryBytes)
+	require.InDelta(t, 1.1, session.CPUCores, 0.001)
+
+	// node is the top process by CPU and memory, it's reported with its
+	// ancestors.
+	pids := make([]int32, 0, len(snapshot.Processes))
+	for _, proc := range snapshot.Processes {
+		pids = append(pids, proc.PID)
+	}
+	require.Equal(t, []int32{1, 10, 20, 21}, pids)
+	require.EqualValues(t, 20, snapshot.Processes[3].SessionPID)
+	require.Zero(t, snapshot.Processes[1].SessionPID)
+
+	// The next snapshot only counts CPU time used since the previous one.
+	now = now.Add(10 * time.Second)
+	processes[2].cpuTime += 10 * time.Second
+	processes[3].cpuTime += 5 * time.Second
+	// A reused PID is a new process.
+	processes[4] = process{pid: 30, ppid: 1, name: "make", startedAt: now.Add(-2 * time.Second), cpuTime: 2 * time.Second}
+
+	snapshot = collector.snapshot(processes, sessions)
+	require.InDelta(t, 1.5, snapshot.Sessions[0].CPUCores, 0.001)
+	pids = pids[:0]
+	for _, proc := range snapshot.Processes {
+		pids = append(pids, proc.PID)
+	}
+	// bash is now the top process by CPU and node by memory.
+	require.Equal(t, []int32{1, 10, 20, 21}, pids)
+	require.InDelta(t, 1.0, snapshot.Processes[2].CPUCores, 0.001)
+	require.InDelta(t, 0.5, snapshot.Processes[3].CPUCores, 0.001)
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewTracker()
+	untrack := tracker.Track(Session{Type: "ssh", PID: 2})
+	tracker.Track(Session{Type: "reconnecting_pty", PID: 1})
+	sessions := tracker.Sessions()
+	require.Len(t, sessions, 2)
+	require.Equal(t, 1, sessions[0].PID)
+	require.Equal(t, 2, sessions[1].PID)
+
+	untrack()
+	require.Len(t, tracker.Sessions(), 1)
+}
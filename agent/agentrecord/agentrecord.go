@@ -0,0 +1,405 @@
+// Package agentrecord records terminal sessions in the asciicast v2 format
+// and uploads them to coderd in chunks.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/ for the format.
+package agentrecord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+const (
+	// DefaultFlushInterval is how often buffered events are uploaded.
+	DefaultFlushInterval = 5 * time.Second
+	// DefaultChunkSize is the buffer size that triggers an upload before
+	// the flush interval has passed.
+	DefaultChunkSize = 64 << 10
+	// DefaultMaxPendingSize is the size of the chunks that failed to
+	// upload above which the oldest ones are dropped.
+	DefaultMaxPendingSize = 16 << 20
+
+	// uploadTimeout bounds a single chunk upload.
+	uploadTimeout = 30 * time.Second
+)
+
+// Event codes of asciicast v2.
+const (
+	eventOutput = "o"
+	eventInput  = "i"
+	eventResize = "r"
+	eventMarker = "m"
+)
+
+type Options struct {
+	Logger slog.Logger
+	Type   codersdk.SessionRecordingType
+	Width  uint16
+	Height uint16
+	// Env is included in the header, typically SHELL and TERM.
+	Env map[string]string
+	// UserID is the user that connected to the session, the zero UUID if
+	// it is unknown.
+	UserID uuid.UUID
+	// Upload sends a chunk of the recording to coderd.
+	Upload func(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error
+
+	// FlushInterval defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+	// ChunkSize defaults to DefaultChunkSize.
+	ChunkSize int
+	// MaxPendingSize defaults to DefaultMaxPendingSize.
+	MaxPendingSize int
+}
+
+// Recorder captures the input, output and resizes of a terminal session.
+// Events are buffered and uploaded in order from a background goroutine.
+// All methods are safe for concurrent use.
+type Recorder struct {
+	opts      Options
+	id        uuid.UUID
+	startedAt time.Time
+
+	mu sync.Mutex // Protects following.
+	// buf holds encoded events that haven't been queued for upload yet.
+	buf bytes.Buffer
+	// partial holds trailing bytes of an incomplete UTF-8 sequence per
+	// event code, they are prepended to the next event of that code.
+	partial map[string][]byte
+	// pending holds chunks that failed to upload, they are retried on the
+	// next flush to keep the recording in order.
+	pending [][]byte
+	// gap describes the chunks dropped from pending, if any.
+	gap      *gap
+	sequence int32
+	closed   bool
+
+	flush     chan struct{}
+	closing   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New starts a recording. Close must be called to upload the remainder of
+// the recording and stop the upload goroutine.
+func New(opts Options) *Recorder {
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	if opts.MaxPendingSize == 0 {
+		opts.MaxPendingSize = DefaultMaxPendingSize
+	}
+	r := &Recorder{
+		opts:      opts,
+		id:        uuid.New(),
+		startedAt: time.Now(),
+		partial:   map[string][]byte{},
+		flush:     make(chan struct{}, 1),
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	header, _ := json.Marshal(header{
+		Version:   2,
+		Width:     opts.Width,
+		Height:    opts.Height,
+		Timestamp: r.startedAt.Unix(),
+		Env:       opts.Env,
+	})
+	r.buf.Write(header)
+	r.buf.WriteByte('\n')
+	go r.run()
+	return r
+}
+
+type header struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// ID is the unique identifier of the recording.
+func (r *Recorder) ID() uuid.UUID {
+	return r.id
+}
+
+// Output records data written to the terminal.
+func (r *Recorder) Output(p []byte) {
+	r.record(eventOutput, p)
+}
+
+// Input records data typed into the terminal.
+func (r *Recorder) Input(p []byte) {
+	r.record(eventInput, p)
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width, height uint16) {
+	if width == 0 || height == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeEventLocked(eventResize, []byte(formatSize(width, height)))
+}
+
+// OutputWriter returns a writer that records everything written to it as
+// output, for use with io.MultiWriter and io.TeeReader.
+func (r *Recorder) OutputWriter() *Writer {
+	return &Writer{r: r, code: eventOutput}
+}
+
+// InputWriter returns a writer that records everything written to it as
+// input.
+func (r *Recorder) InputWriter() *Writer {
+	return &Writer{r: r, code: eventInput}
+}
+
+// Writer records writes as events of a single type. Writes never fail.
+type Writer struct {
+	r    *Recorder
+	code string
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.r.record(w.code, p)
+	return len(p), nil
+}
+
+func (r *Recorder) record(code string, p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	data := append(r.partial[code], p...)
+	// Hold back an incomplete UTF-8 sequence at the end of the data, the
+	// remainder is likely in the next read. Event data is a JSON string so
+	// splitting a rune would corrupt it.
+	cut := incompleteSuffix(data)
+	r.partial[code] = append([]byte(nil), data[cut:]...)
+	r.writeEventLocked(code, data[:cut])
+}
+
+func (r *Recorder) writeEventLocked(code string, data []byte) {
+	if r.closed || len(data) == 0 {
+		return
+	}
+	elapsed := time.Since(r.startedAt).Seconds()
+	event, _ := json.Marshal([]interface{}{elapsed, code, string(data)})
+	r.buf.Write(event)
+	r.buf.WriteByte('\n')
+	if r.buf.Len() >= r.opts.ChunkSize {
+		select {
+		case r.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close stops recording and uploads the remainder of the recording in the
+// background. It doesn't block on the upload, use Done to wait for it.
+func (r *Recorder) Close() error {
+	r.closeOnce.Do(func() {
+		r.mu.Lock()
+		for code, data := range r.partial {
+			r.writeEventLocked(code, data)
+		}
+		r.closed = true
+		r.mu.Unlock()
+		close(r.closing)
+	})
+	return nil
+}
+
+// Done is closed after Close once the final chunk has been uploaded, or
+// failed to upload.
+func (r *Recorder) Done() <-chan struct{} {
+	return r.done
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.closing:
+			r.upload(true)
+			return
+		case <-ticker.C:
+		case <-r.flush:
+		}
+		r.upload(false)
+	}
+}
+
+// upload queues the buffered events as a chunk and uploads all pending
+// chunks in order. Failed chunks are kept for the next attempt, except
+// for the final upload which is attempted only once.
+func (r *Recorder) upload(final bool) {
+	r.mu.Lock()
+	if r.buf.Len() > 0 {
+		r.pending = append(r.pending, append([]byte(nil), r.buf.Bytes()...))
+		r.buf.Reset()
+	}
+	r.trimPendingLocked()
+	pending := r.pending
+	sequence := r.sequence
+	r.mu.Unlock()
+
+	if final && len(pending) == 0 {
+		// Everything was uploaded, mark the recording as complete.
+		pending = [][]byte{{}}
+	}
+
+	uploaded := 0
+	for i, data := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+		err := r.opts.Upload(ctx, agentsdk.PostSessionRecordingRequest{
+			ID:        r.id,
+			Type:      r.opts.Type,
+			StartedAt: r.startedAt,
+			UserID:    r.opts.UserID,
+			Sequence:  sequence,
+			Data:      data,
+			Final:     final && i == len(pending)-1,
+		})
+		cancel()
+		if err != nil {
+			r.opts.Logger.Warn(context.Background(), "upload session recording chunk",
+				slog.F("recording_id", r.id),
+				slog.F("sequence", sequence),
+				slog.Error(err),
+			)
+			break
+		}
+		uploaded++
+		sequence++
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if uploaded > len(r.pending) {
+		// Only the synthetic final chunk was uploaded.
+		uploaded = len(r.pending)
+	}
+	r.pending = r.pending[uploaded:]
+	r.sequence = sequence
+	if r.gap != nil {
+		if uploaded > r.gap.index {
+			r.gap = nil
+		} else {
+			r.gap.index -= uploaded
+		}
+	}
+}
+
+// gap is a run of chunks dropped because coderd was unreachable for too
+// long. A marker event in pending[index] takes their place.
+type gap struct {
+	index int
+	at    float64
+	size  int
+}
+
+// trimPendingLocked drops the oldest pending chunks while they exceed
+// MaxPendingSize, so the agent doesn't buffer a whole session in memory
+// while uploads fail. The chunk with the header and the newest chunk are
+// always kept.
+func (r *Recorder) trimPendingLocked() {
+	size := 0
+	for _, chunk := range r.pending {
+		size += len(chunk)
+	}
+	if size <= r.opts.MaxPendingSize {
+		return
+	}
+
+	base := 0
+	if r.sequence == 0 {
+		base = 1
+	}
+	from := base
+	if r.gap != nil {
+		from = r.gap.index + 1
+	}
+	to := from
+	for size > r.opts.MaxPendingSize && to < len(r.pending)-1 {
+		size -= len(r.pending[to])
+		to++
+	}
+	if to == from {
+		return
+	}
+	if r.gap == nil {
+		r.gap = &gap{index: base, at: firstEventTime(r.pending[from])}
+	}
+	dropped := 0
+	for _, chunk := range r.pending[from:to] {
+		dropped += len(chunk)
+	}
+	r.gap.size += dropped
+	r.opts.Logger.Warn(context.Background(), "dropping session recording chunks that failed to upload",
+		slog.F("recording_id", r.id),
+		slog.F("dropped_bytes", dropped),
+	)
+
+	marker, _ := json.Marshal([]interface{}{r.gap.at, eventMarker, fmt.Sprintf("%d bytes of the recording were dropped", r.gap.size)})
+	marker = append(marker, '\n')
+	pending := make([][]byte, 0, base+1+len(r.pending)-to)
+	pending = append(pending, r.pending[:base]...)
+	pending = append(pending, marker)
+	r.pending = append(pending, r.pending[to:]...)
+}
+
+// firstEventTime returns the time of the first event of a chunk.
+func firstEventTime(chunk []byte) float64 {
+	line, _, _ := bytes.Cut(chunk, []byte{'\n'})
+	var (
+		event []json.RawMessage
+		at    float64
+	)
+	if json.Unmarshal(line, &event) == nil && len(event) > 0 {
+		_ = json.Unmarshal(event[0], &at)
+	}
+	return at
+}
+
+// incompleteSuffix returns the index at which a trailing incomplete UTF-8
+// sequence starts, or len(p) if there is none.
+func incompleteSuffix(p []byte) int {
+	// A UTF-8 sequence is at most 4 bytes long, so only the last 3 bytes
+	// can belong to an incomplete one.
+	for i := len(p) - 1; i >= 0 && i >= len(p)-3; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}
+
+func formatSize(width, height uint16) string {
+	return fmt.Sprintf("%dx%d", width, height)
+}
@@ -0,0 +1,232 @@
+package agentrecord_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent/agentrecord"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Events", func(t *testing.T) {
+		t.Parallel()
+		uploader := &fakeUploader{}
+		recorder := agentrecord.New(agentrecord.Options{
+			Logger: slogtest.Make(t, nil),
+			Type:   codersdk.SessionRecordingTypeSSH,
+			Width:  80,
+			Height: 24,
+			Env:    map[string]string{"TERM": "xterm"},
+			Upload: uploader.upload,
+		})
+		_, _ = recorder.OutputWriter().Write([]byte("hello\r\n"))
+		recorder.Input([]byte("ls\r"))
+		recorder.Resize(120, 40)
+		// A rune split across writes is recorded as a single event.
+		recorder.Output([]byte("\xe2\x82"))
+		recorder.Output([]byte("\xac"))
+		require.NoError(t, recorder.Close())
+		waitDone(t, recorder)
+
+		chunks := uploader.requests()
+		require.NotEmpty(t, chunks)
+		require.True(t, chunks[len(chunks)-1].Final)
+		for i, chunk := range chunks {
+			require.Equal(t, recorder.ID(), chunk.ID)
+			require.Equal(t, codersdk.SessionRecordingTypeSSH, chunk.Type)
+			require.EqualValues(t, i, chunk.Sequence)
+		}
+
+		lines := uploader.lines(t)
+		require.Len(t, lines, 5)
+		var header map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+		require.EqualValues(t, 2, header["version"])
+		require.EqualValues(t, 80, header["width"])
+		require.EqualValues(t, 24, header["height"])
+
+		events := make([][3]interface{}, 0, len(lines)-1)
+		for _, line := range lines[1:] {
+			var event [3]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &event))
+			events = append(events, event)
+		}
+		require.Equal(t, []interface{}{"o", "hello\r\n"}, events[0][1:])
+		require.Equal(t, []interface{}{"i", "ls\r"}, events[1][1:])
+		require.Equal(t, []interface{}{"r", "120x40"}, events[2][1:])
+		require.Equal(t, []interface{}{"o", "€"}, events[3][1:])
+	})
+
+	t.Run("Chunks", func(t *testing.T) {
+		t.Parallel()
+		uploader := &fakeUploader{}
+		recorder := agentrecord.New(agentrecord.Options{
+			Logger:    slogtest.Make(t, nil),
+			Type:      codersdk.SessionRecordingTypeReconnectingPTY,
+			Upload:    uploader.upload,
+			ChunkSize: 64,
+		})
+		for i := 0; i < 10; i++ {
+			recorder.Output(bytes.Repeat([]byte("a"), 64))
+			// Filling a chunk uploads it before the flush interval.
+			require.Eventually(t, func() bool {
+				return len(uploader.requests()) > i
+			}, testutil.WaitShort, testutil.IntervalFast)
+		}
+		require.NoError(t, recorder.Close())
+		waitDone(t, recorder)
+		require.Len(t, uploader.lines(t), 11)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+		uploader := &fakeUploader{failures: 2}
+		recorder := agentrecord.New(agentrecord.Options{
+			Logger:        slogtest.Make(t, nil),
+			Type:          codersdk.SessionRecordingTypeSSH,
+			Upload:        uploader.upload,
+			FlushInterval: testutil.IntervalFast,
+		})
+		recorder.Output([]byte("first"))
+		require.Eventually(t, func() bool {
+			return len(uploader.requests()) > 0
+		}, testutil.WaitShort, testutil.IntervalFast)
+		recorder.Output([]byte("second"))
+		require.NoError(t, recorder.Close())
+		waitDone(t, recorder)
+
+		// Failed chunks are uploaded in order once the upload succeeds.
+		lines := uploader.lines(t)
+		require.Len(t, lines, 3)
+		require.Contains(t, lines[1], "first")
+		require.Contains(t, lines[2], "second")
+	})
+
+	t.Run("MaxPendingSize", func(t *testing.T) {
+		t.Parallel()
+		uploader := &fakeUploader{failures: 1000}
+		recorder := agentrecord.New(agentrecord.Options{
+			Logger:         slogtest.Make(t, nil),
+			Type:           codersdk.SessionRecordingTypeReconnectingPTY,
+			Upload:         uploader.upload,
+			ChunkSize:      64,
+			MaxPendingSize: 512,
+		})
+		for i := 0; i < 20; i++ {
+			recorder.Output([]byte(fmt.Sprintf("%02d%s", i, bytes.Repeat([]byte("a"), 62))))
+			require.Eventually(t, func() bool {
+				return uploader.attemptCount() > i
+			}, testutil.WaitShort, testutil.IntervalFast)
+		}
+		uploader.succeed()
+		require.NoError(t, recorder.Close())
+		waitDone(t, recorder)
+
+		size := 0
+		for _, chunk := range uploader.requests()[1:] {
+			size += len(chunk.Data)
+		}
+		// The chunks after the header one fit in the limit, with the marker
+		// of the dropped ones.
+		require.LessOrEqual(t, size, 512+100)
+
+		lines := uploader.lines(t)
+		events := make([][3]interface{}, 0, len(lines)-1)
+		for _, line := range lines[1:] {
+			var event [3]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &event))
+			events = append(events, event)
+		}
+		require.Equal(t, "o", events[0][1])
+		require.True(t, strings.HasPrefix(events[0][2].(string), "00"))
+		require.Equal(t, "m", events[1][1])
+		require.Contains(t, events[1][2], "bytes of the recording were dropped")
+		last := events[len(events)-1]
+		require.True(t, strings.HasPrefix(last[2].(string), "19"))
+		require.Less(t, len(events), 20)
+	})
+}
+
+func waitDone(t *testing.T, recorder *agentrecord.Recorder) {
+	t.Helper()
+	select {
+	case <-recorder.Done():
+	case <-time.After(testutil.WaitShort):
+		t.Fatal("timed out waiting for recording upload")
+	}
+}
+
+type fakeUploader struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	chunks   []agentsdk.PostSessionRecordingRequest
+}
+
+func (f *fakeUploader) upload(_ context.Context, req agentsdk.PostSessionRecordingRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if f.failures > 0 {
+		f.failures--
+		return xerrors.New("upload failed")
+	}
+	f.chunks = append(f.chunks, req)
+	return nil
+}
+
+func (f *fakeUploader) attemptCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts
+}
+
+// succeed makes the following uploads succeed.
+func (f *fakeUploader) succeed() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = 0
+}
+
+func (f *fakeUploader) requests() []agentsdk.PostSessionRecordingRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]agentsdk.PostSessionRecordingRequest(nil), f.chunks...)
+}
+
+// lines returns the lines of the uploaded recording.
+func (f *fakeUploader) lines(t *testing.T) []string {
+	t.Helper()
+	var data []byte
+	for _, chunk := range f.requests() {
+		data = append(data, chunk.Data...)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
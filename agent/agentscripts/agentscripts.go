@@ -0,0 +1,317 @@
+package agentscripts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/afero"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/coderd/schedule"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// Options are a set of options for the runner.
+type Options struct {
+	LogDir     string
+	Logger     slog.Logger
+	SSHServer  *agentssh.Server
+	Filesystem afero.Fs
+	PatchLogs  func(ctx context.Context, req agentsdk.PatchStartupLogs) error
+	PostStatus func(ctx context.Context, req agentsdk.PostScriptStatusRequest) error
+	// PostMetadata reports the result of scheduled script runs.
+	PostMetadata func(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
+}
+
+// New creates a runner for the provided scripts.
+func New(opts Options) *Runner {
+	if opts.Filesystem == nil {
+		opts.Filesystem = afero.NewOsFs()
+	}
+	cronCtx, cronCtxCancel := context.WithCancel(context.Background())
+	return &Runner{
+		Options:       opts,
+		closed:        make(chan struct{}),
+		cronCtx:       cronCtx,
+		cronCtxCancel: cronCtxCancel,
+		cron: cron.New(cron.WithChain(
+			// A run that overlaps the next scheduled run skips it
+			// rather than piling up processes.
+			cron.SkipIfStillRunning(cron.DiscardLogger),
+		)),
+	}
+}
+
+// Runner executes the scripts defined for a workspace agent and
+// reports their output and status back to coderd.
+type Runner struct {
+	Options
+
+	closeMutex    sync.Mutex
+	closed        chan struct{}
+	cronCtx       context.Context
+	cronCtxCancel context.CancelFunc
+	cron          *cron.Cron
+	cmdCloseWait  sync.WaitGroup
+	scripts       []codersdk.WorkspaceAgentScript
+}
+
+// Init initializes the runner with the provided scripts.
+// It must only be called once.
+func (r *Runner) Init(scripts []codersdk.WorkspaceAgentScript) error {
+	if r.isClosed() {
+		return xerrors.New("init: runner is closed")
+	}
+	r.scripts = scripts
+
+	for _, script := range scripts {
+		if script.Cron == "" {
+			continue
+		}
+		script := script
+		sched, err := schedule.Standard(script.Cron)
+		if err != nil {
+			// A bad schedule shouldn't prevent the agent from starting,
+			// the schedule is validated when the template is imported.
+			r.Logger.Warn(r.cronCtx, "parse script cron schedule",
+				slog.F("log_source", script.DisplayName), slog.F("cron", script.Cron), slog.Error(err))
+			continue
+		}
+		r.cron.Schedule(sched, cron.FuncJob(func() {
+			r.runScheduled(script)
+		}))
+	}
+	return nil
+}
+
+// StartCron starts executing scripts on their cron schedules. It should
+// be called once the startup scripts have completed.
+func (r *Runner) StartCron() {
+	r.closeMutex.Lock()
+	defer r.closeMutex.Unlock()
+	if r.isClosed() {
+		return
+	}
+	r.cron.Start()
+}
+
+// Execute runs a set of scripts according to a filter. Scripts are
+// executed concurrently and the first error encountered is returned
+// once all of them have completed.
+func (r *Runner) Execute(ctx context.Context, filter func(script codersdk.WorkspaceAgentScript) bool) error {
+	if filter == nil {
+		// Execute the startup scripts by default.
+		filter = func(script codersdk.WorkspaceAgentScript) bool {
+			return script.RunOnStart
+		}
+	}
+	var eg errgroup.Group
+	for _, script := range r.scripts {
+		if !filter(script) {
+			continue
+		}
+		script := script
+		eg.Go(func() error {
+			err := r.run(ctx, script)
+			if err != nil {
+				return xerrors.Errorf("run agent script %q: %w", script.LogSourceID, err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// run executes the provided script with its timeout. If the timeout is
+// exceeded, the process is killed and the script is reported as timed out.
+func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript) (err error) {
+	logPath := script.LogPath
+	if logPath == "" {
+		logPath = fmt.Sprintf("coder-script-%s.log", script.LogSourceID)
+	}
+	if !filepath.IsAbs(logPath) {
+		logPath = filepath.Join(r.LogDir, logPath)
+	}
+	logger := r.Logger.With(slog.F("log_source", script.DisplayName), slog.F("log_path", logPath))
+	logger.Info(ctx, "running agent script", slog.F("script", script.Script))
+
+	// Scheduled scripts write to the same file on every run, only the
+	// output of the latest one is kept.
+	fileWriter, err := r.Filesystem.OpenFile(logPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o600)
+	if err != nil {
+		return xerrors.Errorf("open %s script log file: %w", logPath, err)
+	}
+	defer func() {
+		err := fileWriter.Close()
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("close %s script log file", logPath), slog.Error(err))
+		}
+	}()
+
+	cmdCtx := ctx
+	if script.Timeout > 0 {
+		var ctxCancel context.CancelFunc
+		cmdCtx, ctxCancel = context.WithTimeout(ctx, script.Timeout)
+		defer ctxCancel()
+	}
+	cmdPty, err := r.SSHServer.CreateCommand(cmdCtx, script.Script, nil)
+	if err != nil {
+		return xerrors.Errorf("%s script: create command: %w", logPath, err)
+	}
+	cmd := cmdPty.AsExec()
+	// Background processes spawned by the script may keep the output
+	// pipes open, don't wait on them forever.
+	cmd.WaitDelay = 10 * time.Second
+
+	send, flushAndClose := agentsdk.StartupLogsSender(func(ctx context.Context, req agentsdk.PatchStartupLogs) error {
+		req.LogSourceID = script.LogSourceID
+		return r.PatchLogs(ctx, req)
+	}, logger)
+	// If ctx is canceled here (or in a writer below), we may be
+	// discarding logs, but that's okay because we're shutting down
+	// anyway. We could consider creating a new context here if we
+	// want better control over flush during shutdown.
+	defer func() {
+		if err := flushAndClose(ctx); err != nil {
+			logger.Warn(ctx, "flush startup logs failed", slog.Error(err))
+		}
+	}()
+
+	infoW := agentsdk.StartupLogsWriter(ctx, send, codersdk.LogLevelInfo)
+	defer infoW.Close()
+	errW := agentsdk.StartupLogsWriter(ctx, send, codersdk.LogLevelError)
+	defer errW.Close()
+
+	cmd.Stdout = io.MultiWriter(fileWriter, infoW)
+	cmd.Stderr = io.MultiWriter(fileWriter, errW)
+
+	start := time.Now()
+	r.postStatus(ctx, logger, agentsdk.PostScriptStatusRequest{
+		LogSourceID: script.LogSourceID,
+		Status:      codersdk.WorkspaceAgentScriptStatusRunning,
+		StartedAt:   start,
+	})
+	defer func() {
+		end := time.Now()
+		execTime := end.Sub(start)
+		exitCode := 0
+		status := codersdk.WorkspaceAgentScriptStatusSucceeded
+		if err != nil {
+			exitCode = 255 // Unknown status.
+			var exitError *exec.ExitError
+			if xerrors.As(err, &exitError) {
+				exitCode = exitError.ExitCode()
+			}
+			status = codersdk.WorkspaceAgentScriptStatusFailed
+			if errors.Is(err, context.DeadlineExceeded) {
+				status = codersdk.WorkspaceAgentScriptStatusTimedOut
+			}
+			logger.Warn(ctx, fmt.Sprintf("%s script failed", logPath), slog.F("execution_time", execTime), slog.F("exit_code", exitCode), slog.Error(err))
+		} else {
+			logger.Info(ctx, fmt.Sprintf("%s script completed", logPath), slog.F("execution_time", execTime), slog.F("exit_code", exitCode))
+		}
+		r.postStatus(ctx, logger, agentsdk.PostScriptStatusRequest{
+			LogSourceID: script.LogSourceID,
+			Status:      status,
+			ExitCode:    int32(exitCode),
+			StartedAt:   start,
+			EndedAt:     end,
+		})
+	}()
+
+	err = cmd.Run()
+	if err != nil {
+		// cmd.Run does not return a context error, it returns "signal: killed".
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if cmdCtx.Err() != nil {
+			return xerrors.Errorf("script timed out after %s: %w", script.Timeout, cmdCtx.Err())
+		}
+		return xerrors.Errorf("%s script: run: %w", logPath, err)
+	}
+	return nil
+}
+
+// runScheduled executes a script triggered by its cron schedule and
+// reports the outcome as agent metadata.
+func (r *Runner) runScheduled(script codersdk.WorkspaceAgentScript) {
+	r.closeMutex.Lock()
+	if r.isClosed() {
+		r.closeMutex.Unlock()
+		return
+	}
+	r.cmdCloseWait.Add(1)
+	r.closeMutex.Unlock()
+	defer r.cmdCloseWait.Done()
+
+	ctx := r.cronCtx
+	err := r.run(ctx, script)
+	if ctx.Err() != nil || r.PostMetadata == nil {
+		return
+	}
+	result := agentsdk.PostMetadataRequest{
+		CollectedAt: time.Now(),
+		Value:       string(codersdk.WorkspaceAgentScriptStatusSucceeded),
+	}
+	if err != nil {
+		result.Value = string(codersdk.WorkspaceAgentScriptStatusFailed)
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Value = string(codersdk.WorkspaceAgentScriptStatusTimedOut)
+		}
+		result.Error = err.Error()
+	}
+	err = r.PostMetadata(ctx, codersdk.WorkspaceAgentScriptMetadataKey(script.LogSourceID), result)
+	if err != nil && ctx.Err() == nil {
+		r.Logger.Warn(ctx, "post scheduled script metadata failed",
+			slog.F("log_source", script.DisplayName), slog.Error(err))
+	}
+}
+
+func (r *Runner) postStatus(ctx context.Context, logger slog.Logger, req agentsdk.PostScriptStatusRequest) {
+	if r.PostStatus == nil {
+		return
+	}
+	err := r.PostStatus(ctx, req)
+	if err != nil && ctx.Err() == nil {
+		logger.Warn(ctx, "post script status failed", slog.F("status", req.Status), slog.Error(err))
+	}
+}
+
+func (r *Runner) Close() error {
+	r.closeMutex.Lock()
+	if r.isClosed() {
+		r.closeMutex.Unlock()
+		return nil
+	}
+	close(r.closed)
+	r.cronCtxCancel()
+	// Scheduled runs take the lock when they start, and stopping the cron
+	// waits for them.
+	r.closeMutex.Unlock()
+	<-r.cron.Stop().Done()
+	r.cmdCloseWait.Wait()
+	return nil
+}
+
+func (r *Runner) isClosed() bool {
+	select {
+	case <-r.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,193 @@
+package agentscripts
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestCronScripts(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts use sh")
+	}
+
+	t.Run("Schedule", func(t *testing.T) {
+		t.Parallel()
+		runner := setup(t, nil)
+		err := runner.Init([]codersdk.WorkspaceAgentScript{{
+			LogSourceID: uuid.New(),
+			Script:      "echo hello",
+			RunOnStart:  true,
+		}, {
+			LogSourceID: uuid.New(),
+			Script:      "echo hello",
+			Cron:        "*/5 * * * *",
+		}, {
+			LogSourceID: uuid.New(),
+			Script:      "echo hello",
+			Cron:        "not a schedule",
+		}})
+		require.NoError(t, err)
+		// Only the valid cron schedule is registered.
+		require.Len(t, runner.cron.Entries(), 1)
+	})
+
+	t.Run("ReportsMetadata", func(t *testing.T) {
+		t.Parallel()
+		var (
+			mu       sync.Mutex
+			metadata = map[string]agentsdk.PostMetadataRequest{}
+		)
+		runner := setup(t, func(_ context.Context, key string, req agentsdk.PostMetadataRequest) error {
+			mu.Lock()
+			defer mu.Unlock()
+			metadata[key] = req
+			return nil
+		})
+		succeeds := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			Script:      "echo hello",
+			Cron:        "* * * * *",
+		}
+		fails := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			Script:      "exit 1",
+			Cron:        "* * * * *",
+		}
+		require.NoError(t, runner.Init([]codersdk.WorkspaceAgentScript{succeeds, fails}))
+
+		// Run the jobs directly rather than waiting on the schedule.
+		runner.runScheduled(succeeds)
+		runner.runScheduled(fails)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, metadata, 2)
+		got := metadata[codersdk.WorkspaceAgentScriptMetadataKey(succeeds.LogSourceID)]
+		require.Equal(t, string(codersdk.WorkspaceAgentScriptStatusSucceeded), got.Value)
+		require.Empty(t, got.Error)
+		got = metadata[codersdk.WorkspaceAgentScriptMetadataKey(fails.LogSourceID)]
+		require.Equal(t, string(codersdk.WorkspaceAgentScriptStatusFailed), got.Value)
+		require.NotEmpty(t, got.Error)
+	})
+
+	t.Run("TruncatesLog", func(t *testing.T) {
+		t.Parallel()
+		runner := setup(t, nil)
+		script := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			Script:      "echo a-longer-first-run",
+			Cron:        "* * * * *",
+		}
+		require.NoError(t, runner.Init([]codersdk.WorkspaceAgentScript{script}))
+		runner.runScheduled(script)
+		script.Script = "echo second"
+		runner.runScheduled(script)
+
+		logPath := filepath.Join(runner.LogDir, fmt.Sprintf("coder-script-%s.log", script.LogSourceID))
+		content, err := afero.ReadFile(runner.Filesystem, logPath)
+		require.NoError(t, err)
+		require.Equal(t, "second", strings.TrimSpace(string(content)))
+	})
+
+	t.Run("CloseWhileStarting", func(t *testing.T) {
+		t.Parallel()
+		runner := setup(t, nil)
+		script := codersdk.WorkspaceAgentScript{
+			LogSourceID: uuid.New(),
+			Script:      "echo hello",
+			Cron:        "* * * * *",
+		}
+		require.NoError(t, runner.Init([]codersdk.WorkspaceAgentScript{script}))
+
+		// The job starts its run once the runner is closing, like a
+		// scheduled run that fires just as the agent shuts down.
+		var (
+			started     = make(chan struct{})
+			startedOnce sync.Once
+		)
+		runner.cron.Schedule(everyMillisecond{}, cron.FuncJob(func() {
+			startedOnce.Do(func() { close(started) })
+			for !runner.isClosed() {
+				time.Sleep(time.Millisecond)
+			}
+			runner.runScheduled(script)
+		}))
+		runner.StartCron()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		select {
+		case <-started:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the scheduled job")
+		}
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			_ = runner.Close()
+		}()
+		select {
+		case <-closed:
+		case <-ctx.Done():
+			t.Fatal("timed out closing the runner")
+		}
+	})
+}
+
+type everyMillisecond struct{}
+
+func (everyMillisecond) Next(t time.Time) time.Time {
+	return t.Add(time.Millisecond)
+}
+
+func setup(t *testing.T, postMetadata func(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error) *Runner {
+	t.Helper()
+	logger := slogtest.Make(t, nil)
+	fs := afero.NewMemMapFs()
+	sshServer, err := agentssh.NewServer(context.Background(), logger, prometheus.NewRegistry(), fs, 0, "")
+	require.NoError(t, err)
+	sshServer.AgentToken = func() string { return "" }
+	sshServer.Manifest = atomic.NewPointer(&agentsdk.Manifest{})
+	t.Cleanup(func() {
+		_ = sshServer.Close()
+	})
+	runner := New(Options{
+		LogDir:     t.TempDir(),
+		Logger:     logger,
+		SSHServer:  sshServer,
+		Filesystem: fs,
+		PatchLogs: func(_ context.Context, _ agentsdk.PatchStartupLogs) error {
+			return nil
+		},
+		PostMetadata: postMetadata,
+	})
+	t.Cleanup(func() {
+		_ = runner.Close()
+	})
+	return runner
+}
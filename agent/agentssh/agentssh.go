@@ -28,7 +28,11 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/agent/agentactivity"
+	"github.com/coder/coder/agent/agentproc"
+	"github.com/coder/coder/agent/agentrecord"
 	"github.com/coder/coder/agent/usershell"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/pty"
 )
@@ -66,6 +70,13 @@ type Server struct {
 	Env        map[string]string
 	AgentToken func() string
 	Manifest   *atomic.Pointer[agentsdk.Manifest]
+	// NewSessionRecorder starts a recording of a PTY session of the client
+	// connected from remoteAddr. It returns nil if sessions aren't recorded.
+	NewSessionRecorder func(typ codersdk.SessionRecordingType, width, height uint16, term string, remoteAddr net.Addr) *agentrecord.Recorder
+	// Sessions tracks the processes started by sessions, it may be nil.
+	Sessions *agentproc.Tracker
+	// Activity records input typed into PTY sessions, it may be nil.
+	Activity *agentactivity.Tracker
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -104,6 +115,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 
 		metrics: metrics,
 	}
+	gpgForwardHandler := &gpgAgentHandler{log: logger, socketPath: s.gpgAgentSocket}
 
 	srv := &ssh.Server{
 		ChannelHandlers: map[string]ssh.ChannelHandler{
@@ -148,6 +160,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 			"cancel-tcpip-forward":                   forwardHandler.HandleSSHRequest,
 			"streamlocal-forward@openssh.com":        unixForwardHandler.HandleSSHRequest,
 			"cancel-streamlocal-forward@openssh.com": unixForwardHandler.HandleSSHRequest,
+			GPGAgentRequestType:                      gpgForwardHandler.HandleSSHRequest,
 		},
 		X11Callback: s.x11Callback,
 		ServerConfigCallback: func(ctx ssh.Context) *gossh.ServerConfig {
@@ -325,6 +338,7 @@ func (s *Server) startNonPTYSession(session ssh.Session, magicTypeLabel string,
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "no", "start_command").Add(1)
 		return xerrors.Errorf("start: %w", err)
 	}
+	defer s.trackSessionProcess(magicTypeLabel, cmd.Process.Pid)()
 	return cmd.Wait()
 }
 
@@ -369,6 +383,7 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "start_command").Add(1)
 		return xerrors.Errorf("start command: %w", err)
 	}
+	defer s.trackSessionProcess(magicTypeLabel, process.PID())()
 	defer func() {
 		closeErr := ptty.Close()
 		if closeErr != nil {
@@ -379,8 +394,26 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 			}
 		}
 	}()
+
+	var (
+		input            = s.Activity.Reader(session)
+		output io.Writer = session
+	)
+	var recorder *agentrecord.Recorder
+	if s.NewSessionRecorder != nil {
+		recorder = s.NewSessionRecorder(codersdk.SessionRecordingTypeSSH, uint16(sshPty.Window.Width), uint16(sshPty.Window.Height), sshPty.Term, ctx.RemoteAddr())
+	}
+	if recorder != nil {
+		defer recorder.Close()
+		input = io.TeeReader(input, recorder.InputWriter())
+		output = io.MultiWriter(session, recorder.OutputWriter())
+	}
+
 	go func() {
 		for win := range windowSize {
+			if recorder != nil {
+				recorder.Resize(uint16(win.Width), uint16(win.Height))
+			}
 			resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
 			// If the pty is closed, then command has exited, no need to log.
 			if resizeErr != nil && !errors.Is(resizeErr, pty.ErrClosed) {
@@ -391,7 +424,7 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	}()
 
 	go func() {
-		_, err := io.Copy(ptty.InputWriter(), session)
+		_, err := io.Copy(ptty.InputWriter(), input)
 		if err != nil {
 			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "input_io_copy").Add(1)
 		}
@@ -405,7 +438,7 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	n, err := io.Copy(output, ptty.OutputReader())
 	s.logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
@@ -428,6 +461,19 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	return nil
 }
 
+// trackSessionProcess registers the process started by a session, the
+// returned function unregisters it.
+func (s *Server) trackSessionProcess(magicTypeLabel string, pid int) func() {
+	if s.Sessions == nil {
+		return func() {}
+	}
+	return s.Sessions.Track(agentproc.Session{
+		Type:      magicTypeLabel,
+		PID:       pid,
+		StartedAt: time.Now(),
+	})
+}
+
 func (s *Server) sftpHandler(session ssh.Session) {
 	s.metrics.sftpConnectionsTotal.Add(1)
 
@@ -5,7 +5,11 @@ package agentssh_test
 import (
 	"bytes"
 	"context"
+	"io"
 	"net"
+	"os"
+	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -122,6 +126,94 @@ func TestNewServer_CloseActiveConnections(t *testing.T) {
 	wg.Wait()
 }
 
+func TestNewServer_ForwardGPGAgent(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("GPG agent forwarding is not supported on Windows")
+	}
+	if _, err := exec.LookPath("gpgconf"); err != nil {
+		t.Skip("gpgconf not found in PATH")
+	}
+
+	ctx := context.Background()
+	logger := slogtest.Make(t, nil)
+	s, err := agentssh.NewServer(ctx, logger, prometheus.NewRegistry(), afero.NewMemMapFs(), 0, "")
+	require.NoError(t, err)
+	defer s.Close()
+
+	s.AgentToken = func() string { return "" }
+	// The workspace has its own GPG home directory, which must be kept.
+	gnupgHome := t.TempDir()
+	s.Manifest = atomic.NewPointer(&agentsdk.Manifest{
+		EnvironmentVariables: map[string]string{"GNUPGHOME": gnupgHome},
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := s.Serve(ln)
+		assert.Error(t, err) // Server is closed.
+	}()
+
+	c := sshClient(t, ln.Addr().String())
+
+	// Stand in for the local GPG agent.
+	channels := c.HandleChannelOpen(agentssh.GPGAgentChannelType)
+	require.NotNil(t, channels)
+	go func() {
+		for newChannel := range channels {
+			ch, reqs, err := newChannel.Accept()
+			if !assert.NoError(t, err) {
+				return
+			}
+			go ssh.DiscardRequests(reqs)
+			_, _ = ch.Write([]byte("OK Pleased to meet you"))
+			_ = ch.Close()
+		}
+	}()
+
+	ok, _, err := c.SendRequest(agentssh.GPGAgentRequestType, true, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Requesting forwarding again on the same connection is a no-op.
+	ok, _, err = c.SendRequest(agentssh.GPGAgentRequestType, true, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The forwarded socket is the agent socket of the workspace GNUPGHOME.
+	var b bytes.Buffer
+	sess, err := c.NewSession()
+	require.NoError(t, err)
+	sess.Stdout = &b
+	err = sess.Run(`echo "$GNUPGHOME" && gpgconf --list-dir agent-socket`)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 2, b.String())
+	require.Equal(t, gnupgHome, lines[0])
+	socketPath := lines[1]
+
+	cmd := exec.Command("gpgconf", "--list-dir", "agent-socket")
+	cmd.Env = append(os.Environ(), "GNUPGHOME="+gnupgHome)
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimSpace(string(out)), socketPath)
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+	got, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, "OK Pleased to meet you", string(got))
+
+	err = s.Close()
+	require.NoError(t, err)
+	<-done
+}
+
 func sshClient(t *testing.T, addr string) *ssh.Client {
 	conn, err := net.Dial("tcp", addr)
 	require.NoError(t, err)
@@ -0,0 +1,138 @@
+package agentssh
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+const (
+	// GPGAgentRequestType is the global request a client sends to have
+	// connections to the workspace's GPG agent socket forwarded to it. Agents
+	// that don't know the request reject it, and clients fall back to a
+	// streamlocal forward of the same socket.
+	GPGAgentRequestType = "gpg-agent-req@coder.com"
+	// GPGAgentChannelType is the channel the server opens to the client for
+	// each connection to the forwarded GPG agent socket.
+	GPGAgentChannelType = "gpg-agent@coder.com"
+)
+
+// gpgAgentHandler forwards GPG agent connections to the client, similar to
+// how ssh.ForwardAgentConnections forwards SSH agent connections. The socket
+// is the one gpgconf reports for the workspace environment, so GNUPGHOME is
+// left as configured.
+type gpgAgentHandler struct {
+	sync.Mutex
+	log slog.Logger
+	// socketPath returns the path of the GPG agent socket in the workspace.
+	socketPath func(ctx context.Context) (string, error)
+	forwards   map[*gossh.ServerConn]net.Listener
+}
+
+func (h *gpgAgentHandler) HandleSSHRequest(ctx ssh.Context, _ *ssh.Server, _ *gossh.Request) (bool, []byte) {
+	if runtime.GOOS == "windows" {
+		h.log.Warn(ctx, "GPG agent forwarding is not supported on Windows")
+		return false, nil
+	}
+	conn, ok := ctx.Value(ssh.ContextKeyConn).(*gossh.ServerConn)
+	if !ok {
+		h.log.Warn(ctx, "GPG agent forward request from client with no gossh connection")
+		return false, nil
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	if h.forwards == nil {
+		h.forwards = make(map[*gossh.ServerConn]net.Listener)
+	}
+	if _, ok := h.forwards[conn]; ok {
+		// Already forwarding for this connection.
+		return true, nil
+	}
+
+	socketPath, err := h.socketPath(ctx)
+	if err != nil {
+		h.log.Warn(ctx, "get GPG agent socket path for GPG agent forward request", slog.Error(err))
+		return false, nil
+	}
+	err = os.MkdirAll(filepath.Dir(socketPath), 0o700)
+	if err != nil {
+		h.log.Warn(ctx, "create parent dir for GPG agent socket",
+			slog.F("socket_path", socketPath),
+			slog.Error(err),
+		)
+		return false, nil
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		h.log.Warn(ctx, "listen on GPG agent socket",
+			slog.F("socket_path", socketPath),
+			slog.Error(err),
+		)
+		return false, nil
+	}
+	h.forwards[conn] = ln
+
+	// The context is canceled when the SSH connection is closed.
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+		h.Lock()
+		delete(h.forwards, conn)
+		h.Unlock()
+	}()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				if !xerrors.Is(err, net.ErrClosed) {
+					h.log.Warn(ctx, "accept on GPG agent socket",
+						slog.F("socket_path", socketPath),
+						slog.Error(err),
+					)
+				}
+				return
+			}
+			go func() {
+				ch, reqs, err := conn.OpenChannel(GPGAgentChannelType, nil)
+				if err != nil {
+					h.log.Warn(ctx, "open SSH channel to forward GPG agent connection to client", slog.Error(err))
+					_ = c.Close()
+					return
+				}
+				go gossh.DiscardRequests(reqs)
+				Bicopy(ctx, ch, c)
+			}()
+		}
+	}()
+
+	return true, nil
+}
+
+// gpgAgentSocket returns the GPG agent socket path reported by gpgconf in the
+// environment sessions get, so a GNUPGHOME set for the workspace is honored.
+func (s *Server) gpgAgentSocket(ctx context.Context) (string, error) {
+	cmd, err := s.CreateCommand(ctx, "gpgconf --list-dir agent-socket", nil)
+	if err != nil {
+		return "", xerrors.Errorf("create command: %w", err)
+	}
+	out, err := cmd.AsExec().Output()
+	if err != nil {
+		return "", xerrors.Errorf("run gpgconf: %w", err)
+	}
+	socketPath := string(bytes.TrimSpace(out))
+	if socketPath == "" {
+		return "", xerrors.New("gpgconf returned an empty agent socket path")
+	}
+	return socketPath, nil
+}
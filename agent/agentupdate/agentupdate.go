@@ -0,0 +1,115 @@
+// Package agentupdate replaces the running agent binary with the one served
+// by coderd. The startup script only downloads the agent once, so without
+// this a long-lived workspace keeps running the agent it started with after
+// the deployment is upgraded.
+package agentupdate
+
+import (
+	"context"
+	"crypto/sha1" //#nosec // Matches the checksums coderd serves.
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/xerrors"
+)
+
+// NeedsUpdate returns true if the agent version differs from the server
+// version. Unlike buildinfo.VersionsMatch, patch versions and build
+// metadata are compared too. Invalid versions never need an update.
+func NeedsUpdate(agentVersion, serverVersion string) bool {
+	if !semver.IsValid(agentVersion) || !semver.IsValid(serverVersion) {
+		return false
+	}
+	return agentVersion != serverVersion
+}
+
+// BinaryName returns the name of the binary coderd serves for the platform
+// under /bin, e.g. "coder-linux-amd64".
+func BinaryName(goos, goarch string) string {
+	if goarch == "arm" {
+		goarch = "armv7"
+	}
+	name := "coder-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// BinaryURL returns the URL of the binary for the platform on the
+// deployment with the given access URL.
+func BinaryURL(accessURL *url.URL, goos, goarch string) *url.URL {
+	return accessURL.ResolveReference(&url.URL{Path: "/bin/" + BinaryName(goos, goarch)})
+}
+
+// Download fetches the binary at binaryURL and atomically replaces the file
+// at dest with it. The binary's SHA1 checksum must equal checksum, which
+// should come from the agent manifest rather than the download itself. It
+// is verified before dest is touched, so a tampered, truncated or corrupted
+// download never replaces a working binary.
+func Download(ctx context.Context, client *http.Client, binaryURL *url.URL, checksum, dest string) error {
+	want := strings.ToLower(checksum)
+	if want == "" {
+		return xerrors.New("no checksum to verify the binary against")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, binaryURL.String(), nil)
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("get %s: %w", binaryURL.String(), err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return xerrors.Errorf("get %s: unexpected status code %d", binaryURL.String(), res.StatusCode)
+	}
+
+	// The temporary file must be in the same directory as dest for the
+	// rename to be atomic.
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".update-*")
+	if err != nil {
+		return xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer func() {
+		// Removing fails harmlessly if the file was renamed.
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha1.New() //#nosec // Matches the checksums coderd serves.
+	_, err = io.Copy(io.MultiWriter(tmp, hash), res.Body)
+	if err != nil {
+		return xerrors.Errorf("download binary: %w", err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return xerrors.Errorf("checksum mismatch: got %s, want %s", got, want)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return xerrors.Errorf("close temporary file: %w", err)
+	}
+
+	mode := os.FileMode(0o755)
+	if fi, err := os.Stat(dest); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	err = os.Chmod(tmp.Name(), mode)
+	if err != nil {
+		return xerrors.Errorf("chmod temporary file: %w", err)
+	}
+	// Renaming over a running executable is fine on Unix, the process
+	// keeps the old inode open.
+	err = os.Rename(tmp.Name(), dest)
+	if err != nil {
+		return xerrors.Errorf("replace %s: %w", dest, err)
+	}
+	return nil
+}
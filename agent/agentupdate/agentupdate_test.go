@@ -0,0 +1,122 @@
+package agentupdate_test
+
+import (
+	"context"
+	"crypto/sha1" //#nosec // Matches the checksums coderd serves.
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/agent/agentupdate"
+	"github.com/coder/coder/testutil"
+)
+
+func TestNeedsUpdate(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		Agent  string
+		Server string
+		Want   bool
+	}{
+		{Agent: "v2.1.0+abc1234", Server: "v2.1.0+abc1234", Want: false},
+		{Agent: "v2.1.0+abc1234", Server: "v2.1.1+def5678", Want: true},
+		{Agent: "v2.1.0+abc1234", Server: "v2.1.0+def5678", Want: true},
+		{Agent: "v2.1.0", Server: "", Want: false},
+		{Agent: "", Server: "v2.1.0", Want: false},
+		{Agent: "v2.1.0", Server: "latest", Want: false},
+	} {
+		assert.Equal(t, tt.Want, agentupdate.NeedsUpdate(tt.Agent, tt.Server), "agent %q server %q", tt.Agent, tt.Server)
+	}
+}
+
+func TestBinaryURL(t *testing.T) {
+	t.Parallel()
+
+	accessURL, err := url.Parse("https://coder.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://coder.example.com/bin/coder-linux-amd64", agentupdate.BinaryURL(accessURL, "linux", "amd64").String())
+	assert.Equal(t, "https://coder.example.com/bin/coder-linux-armv7", agentupdate.BinaryURL(accessURL, "linux", "arm").String())
+	assert.Equal(t, "https://coder.example.com/bin/coder-windows-amd64.exe", agentupdate.BinaryURL(accessURL, "windows", "amd64").String())
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+
+	binary := []byte("new binary")
+	//nolint:gosec // Matches the checksums coderd serves.
+	hash := sha1.Sum(binary)
+	checksum := hex.EncodeToString(hash[:])
+
+	serve := func(t *testing.T) *url.URL {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			// The checksum in the response must not be trusted.
+			rw.Header().Set("ETag", `"0000000000000000000000000000000000000000"`)
+			_, _ = rw.Write(binary)
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL + "/bin/coder-linux-amd64")
+		require.NoError(t, err)
+		return u
+	}
+	executable := func(t *testing.T) string {
+		path := filepath.Join(t.TempDir(), "coder")
+		err := os.WriteFile(path, []byte("old binary"), 0o750)
+		require.NoError(t, err)
+		return path
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		dest := executable(t)
+		err := agentupdate.Download(ctx, http.DefaultClient, serve(t), checksum, dest)
+		require.NoError(t, err)
+
+		got, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		require.Equal(t, binary, got)
+		fi, err := os.Stat(dest)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o750), fi.Mode().Perm())
+		// The temporary file is gone.
+		entries, err := os.ReadDir(filepath.Dir(dest))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		dest := executable(t)
+		err := agentupdate.Download(ctx, http.DefaultClient, serve(t), "0000000000000000000000000000000000000000", dest)
+		require.ErrorContains(t, err, "checksum mismatch")
+
+		got, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		require.Equal(t, []byte("old binary"), got)
+		entries, err := os.ReadDir(filepath.Dir(dest))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("NoChecksum", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		err := agentupdate.Download(ctx, http.DefaultClient, serve(t), "", executable(t))
+		require.ErrorContains(t, err, "no checksum")
+	})
+}
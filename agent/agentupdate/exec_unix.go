@@ -0,0 +1,20 @@
+//go:build !windows
+
+package agentupdate
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/xerrors"
+)
+
+// Exec replaces the current process with the binary at path, keeping the
+// PID, arguments and environment. Processes started by the agent stay its
+// children. Extra environment variables are appended to the current ones.
+// It only returns on failure.
+func Exec(path string, env ...string) error {
+	//nolint:gosec // The binary was downloaded from coderd and verified.
+	err := syscall.Exec(path, os.Args, append(os.Environ(), env...))
+	return xerrors.Errorf("exec %s: %w", path, err)
+}
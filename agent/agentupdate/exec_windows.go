@@ -0,0 +1,9 @@
+package agentupdate
+
+import "golang.org/x/xerrors"
+
+// Exec is not supported on Windows, a running executable can't be
+// replaced.
+func Exec(path string, _ ...string) error {
+	return xerrors.Errorf("exec %s: updating the agent is not supported on Windows", path)
+}
@@ -29,6 +29,19 @@ func (a *agent) apiHandler() http.Handler {
 	lp := &listeningPortsHandler{ignorePorts: cpy}
 	r.Get("/api/v0/listening-ports", lp.handler)
 
+	files := &filesHandler{
+		logger:     a.logger.Named("files"),
+		filesystem: a.filesystem,
+	}
+	r.Get("/api/v0/files", files.list)
+	r.Get("/api/v0/files/contents", files.read)
+	r.Put("/api/v0/files/contents", files.write)
+	r.Get("/api/v0/files/tar", files.downloadTar)
+	r.Put("/api/v0/files/tar", files.uploadTar)
+
+	r.Post("/api/v0/processes/{pid}/kill", a.killProcess)
+	r.Post("/api/v0/activity", a.reportActivity)
+
 	return r
 }
 
@@ -0,0 +1,218 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/afero"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentfiles"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+// filesHandler serves the file transfer API of the agent. Paths are
+// resolved relative to the home directory of the user running the
+// agent, in the same way as the workspace directory.
+type filesHandler struct {
+	logger     slog.Logger
+	filesystem afero.Fs
+}
+
+// list returns the file at the provided path and, if it is a directory,
+// the files within it.
+func (h *filesHandler) list(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.resolvePath(rw, r)
+	if !ok {
+		return
+	}
+
+	info, err := h.filesystem.Stat(path)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	resp := codersdk.WorkspaceAgentListFilesResponse{
+		File:  convertFileInfo(path, info),
+		Files: []codersdk.WorkspaceAgentFile{},
+	}
+	if info.IsDir() {
+		infos, err := afero.ReadDir(h.filesystem, path)
+		if err != nil {
+			h.writeError(rw, r, path, err)
+			return
+		}
+		for _, info := range infos {
+			resp.Files = append(resp.Files, convertFileInfo(filepath.Join(path, info.Name()), info))
+		}
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// read streams the contents of a regular file.
+func (h *filesHandler) read(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.resolvePath(rw, r)
+	if !ok {
+		return
+	}
+
+	file, err := h.filesystem.Open(path)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	if !info.Mode().IsRegular() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is not a regular file.", path),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	rw.WriteHeader(http.StatusOK)
+	_, err = io.Copy(rw, file)
+	if err != nil && ctx.Err() == nil {
+		h.logger.Warn(ctx, "copy file to response", slog.F("path", path), slog.Error(err))
+	}
+}
+
+// write replaces the contents of a file with the request body. Parent
+// directories are created as needed.
+func (h *filesHandler) write(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.resolvePath(rw, r)
+	if !ok {
+		return
+	}
+	mode := os.FileMode(0o644)
+	if raw := r.URL.Query().Get("mode"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 8, 32)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid file mode.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		mode = os.FileMode(parsed).Perm()
+	}
+
+	err := agentfiles.WriteFile(h.filesystem, path, mode, r.Body)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	info, err := h.filesystem.Stat(path)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertFileInfo(path, info))
+}
+
+// downloadTar streams the file or directory at the provided path as a
+// tar archive. Entries are named relative to the path.
+func (h *filesHandler) downloadTar(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.resolvePath(rw, r)
+	if !ok {
+		return
+	}
+	// Stat first so that a missing path results in an error response
+	// rather than an empty archive.
+	_, err := h.filesystem.Stat(path)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-tar")
+	rw.WriteHeader(http.StatusOK)
+	// The status has already been written, so errors can only be logged.
+	// The client notices the truncated archive.
+	err = agentfiles.Tar(h.filesystem, rw, path)
+	if err != nil && ctx.Err() == nil {
+		h.logger.Warn(ctx, "write tar archive", slog.F("path", path), slog.Error(err))
+	}
+}
+
+// uploadTar extracts the tar archive in the request body into the
+// directory at the provided path, creating it if needed.
+func (h *filesHandler) uploadTar(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := h.resolvePath(rw, r)
+	if !ok {
+		return
+	}
+	err := agentfiles.Untar(h.filesystem, path, r.Body)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	info, err := h.filesystem.Stat(path)
+	if err != nil {
+		h.writeError(rw, r, path, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertFileInfo(path, info))
+}
+
+func (h *filesHandler) resolvePath(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	raw := r.URL.Query().Get("path")
+	if raw == "" {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message: `The "path" query parameter is required.`,
+		})
+		return "", false
+	}
+	path, err := expandDirectory(raw)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to resolve path.",
+			Detail:  err.Error(),
+		})
+		return "", false
+	}
+	return filepath.Clean(path), true
+}
+
+func (*filesHandler) writeError(rw http.ResponseWriter, r *http.Request, path string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		status = http.StatusForbidden
+	}
+	httpapi.Write(r.Context(), rw, status, codersdk.Response{
+		Message: fmt.Sprintf("Failed to access %q.", path),
+		Detail:  err.Error(),
+	})
+}
+
+func convertFileInfo(path string, info os.FileInfo) codersdk.WorkspaceAgentFile {
+	return codersdk.WorkspaceAgentFile{
+		Name:    info.Name(),
+		Path:    path,
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+}
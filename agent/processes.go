@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentproc"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+// killProcess sends a signal to a process in the workspace. The agent
+// can only signal processes its user is permitted to.
+func (a *agent) killProcess(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	pid, err := strconv.Atoi(chi.URLParam(r, "pid"))
+	if err != nil || pid <= 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Process ID must be a positive integer.",
+		})
+		return
+	}
+	if pid == os.Getpid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The agent process can't be killed.",
+		})
+		return
+	}
+	var req codersdk.KillWorkspaceAgentProcessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	switch req.Signal {
+	case "":
+		req.Signal = codersdk.WorkspaceAgentProcessSignalTerminate
+	case codersdk.WorkspaceAgentProcessSignalTerminate, codersdk.WorkspaceAgentProcessSignalKill:
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid signal %q.", req.Signal),
+		})
+		return
+	}
+
+	err = agentproc.Kill(pid, req.Signal)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, os.ErrProcessDone):
+			status = http.StatusNotFound
+		case errors.Is(err, fs.ErrPermission):
+			status = http.StatusForbidden
+		}
+		httpapi.Write(ctx, rw, status, codersdk.Response{
+			Message: fmt.Sprintf("Failed to signal process %d.", pid),
+			Detail:  err.Error(),
+		})
+		return
+	}
+	a.logger.Info(ctx, "signaled process", slog.F("pid", pid), slog.F("signal", req.Signal))
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentupdate"
+	"github.com/coder/coder/buildinfo"
+	"github.com/coder/coder/codersdk"
+)
+
+// updateCheckInterval is how often the agent checks whether it can
+// restart after downloading an update.
+const updateCheckInterval = 5 * time.Second
+
+// startUpdate updates the agent to the server version in the background.
+// An update to each version is only attempted once, so a failing download
+// doesn't repeat on every reconnect.
+func (a *agent) startUpdate(ctx context.Context, version, checksum string) {
+	if old := a.updateVersion.Swap(&version); old != nil && *old == version {
+		return
+	}
+	go a.update(ctx, version, checksum)
+}
+
+// update replaces the agent binary, waits for startup to finish and for
+// active sessions to end, and restarts the agent. Startup and shutdown
+// scripts are not run, and the tailnet connection is only down while the
+// new binary starts. The agent never restarts while users are connected;
+// if it stops first, the new binary is used on the next start.
+func (a *agent) update(ctx context.Context, version, checksum string) {
+	logger := a.logger.With(
+		slog.F("agent_version", buildinfo.Version()),
+		slog.F("server_version", version),
+	)
+	logger.Info(ctx, "agent version differs from server, updating",
+		slog.F("url", a.updateOptions.BinaryURL.String()),
+		slog.F("executable", a.updateOptions.Executable),
+	)
+	err := agentupdate.Download(ctx, a.updateOptions.HTTPClient, a.updateOptions.BinaryURL, checksum, a.updateOptions.Executable)
+	if err != nil {
+		logger.Error(ctx, "download agent binary", slog.Error(err))
+		return
+	}
+	logger.Info(ctx, "downloaded agent binary, waiting to restart")
+
+	ticker := time.NewTicker(updateCheckInterval)
+	defer ticker.Stop()
+	for !a.canRestart() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	lifecycle, ok := a.closeForRestart()
+	if !ok {
+		return
+	}
+	logger.Info(ctx, "restarting agent", slog.F("lifecycle", lifecycle))
+	err = a.updateOptions.Restart(lifecycle)
+	if err != nil {
+		logger.Error(ctx, "restart agent", slog.Error(err))
+	}
+}
+
+// canRestart returns true once the startup scripts have completed and no
+// user is connected to the workspace.
+func (a *agent) canRestart() bool {
+	a.lifecycleMu.RLock()
+	lifecycle := a.lifecycleStates[len(a.lifecycleStates)-1].State
+	a.lifecycleMu.RUnlock()
+	switch lifecycle {
+	case codersdk.WorkspaceAgentLifecycleCreated, codersdk.WorkspaceAgentLifecycleStarting:
+		return false
+	}
+
+	stats := a.sshServer.ConnStats()
+	return stats.Sessions+stats.VSCode+stats.JetBrains+a.connCountReconnectingPTY.Load() == 0
+}
+
+// closeForRestart closes the agent without running the shutdown scripts
+// or reporting a lifecycle change, since the agent continues to run in the
+// new binary. It returns the current lifecycle state, or false if the
+// agent was already closed.
+func (a *agent) closeForRestart() (codersdk.WorkspaceAgentLifecycle, bool) {
+	a.closeMutex.Lock()
+	defer a.closeMutex.Unlock()
+	if a.isClosed() {
+		return "", false
+	}
+
+	a.logger.Info(context.Background(), "shutting down agent to restart after update")
+	a.lifecycleMu.RLock()
+	lifecycle := a.lifecycleStates[len(a.lifecycleStates)-1].State
+	a.lifecycleMu.RUnlock()
+
+	a.closeResources()
+	return lifecycle, true
+}
@@ -28,6 +28,7 @@ import (
 	"cdr.dev/slog/sloggers/slogjson"
 	"cdr.dev/slog/sloggers/slogstackdriver"
 	"github.com/coder/coder/agent"
+	"github.com/coder/coder/agent/agentupdate"
 	"github.com/coder/coder/agent/reaper"
 	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/cli/clibase"
@@ -41,6 +42,7 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		logDir              string
 		pprofAddress        string
 		noReap              bool
+		noUpdate            bool
 		sshMaxTimeout       time.Duration
 		tailnetListenPort   int64
 		prometheusAddress   string
@@ -252,6 +254,31 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				return xerrors.Errorf("add executable to $PATH: %w", err)
 			}
 
+			// Development builds are never updated, and a running executable
+			// can't be replaced on Windows.
+			var (
+				update     *agent.UpdateOptions
+				restartErr = make(chan error, 1)
+			)
+			if !noUpdate && !buildinfo.IsDev() && runtime.GOOS != "windows" {
+				update = &agent.UpdateOptions{
+					Executable: executablePath,
+					BinaryURL:  agentupdate.BinaryURL(r.agentURL, runtime.GOOS, runtime.GOARCH),
+					// The client timeout is too short to download the binary.
+					HTTPClient: &http.Client{Transport: client.SDK.HTTPClient.Transport},
+					Restart: func(lifecycle codersdk.WorkspaceAgentLifecycle) error {
+						err := agentupdate.Exec(executablePath, agent.EnvAgentRestartLifecycle+"="+string(lifecycle))
+						restartErr <- err
+						return err
+					},
+				}
+			}
+
+			// Set when the agent restarted into an updated binary. It's
+			// unset so it doesn't leak into workspace processes.
+			restartLifecycle := codersdk.WorkspaceAgentLifecycle(inv.Environ.Get(agent.EnvAgentRestartLifecycle))
+			_ = os.Unsetenv(agent.EnvAgentRestartLifecycle)
+
 			prometheusRegistry := prometheus.NewRegistry()
 			subsystem := inv.Environ.Get(agent.EnvAgentSubsystem)
 			agnt := agent.New(agent.Options{
@@ -273,9 +300,11 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				EnvironmentVariables: map[string]string{
 					"GIT_ASKPASS": executablePath,
 				},
-				IgnorePorts:   ignorePorts,
-				SSHMaxTimeout: sshMaxTimeout,
-				Subsystem:     codersdk.AgentSubsystem(subsystem),
+				IgnorePorts:      ignorePorts,
+				SSHMaxTimeout:    sshMaxTimeout,
+				Subsystem:        codersdk.AgentSubsystem(subsystem),
+				Update:           update,
+				RestartLifecycle: restartLifecycle,
 
 				PrometheusRegistry: prometheusRegistry,
 			})
@@ -286,7 +315,11 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			debugSrvClose := ServeHandler(ctx, logger, agnt.HTTPDebug(), debugAddress, "debug")
 			defer debugSrvClose()
 
-			<-ctx.Done()
+			select {
+			case <-ctx.Done():
+			case err := <-restartErr:
+				return xerrors.Errorf("restart agent after update: %w", err)
+			}
 			return agnt.Close()
 		},
 	}
@@ -320,6 +353,12 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Description: "Do not start a process reaper.",
 			Value:       clibase.BoolOf(&noReap),
 		},
+		{
+			Flag:        "no-update",
+			Env:         "CODER_AGENT_NO_UPDATE",
+			Description: "Do not replace the agent binary when the Coder server version differs.",
+			Value:       clibase.BoolOf(&noUpdate),
+		},
 		{
 			Flag: "ssh-max-timeout",
 			// tcpip.KeepaliveIdleOption = 72h + 1min (forwardTCPSockOpts() in tailnet/conn.go)
@@ -0,0 +1,847 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisionersdk"
+)
+
+// applyManifest is the declarative description of a deployment read by
+// `coder apply`. Resources that aren't in the manifest are left alone, and
+// fields that are omitted keep their current value.
+type applyManifest struct {
+	Templates        []applyTemplate       `yaml:"templates"`
+	Groups           []applyGroup          `yaml:"groups"`
+	WorkspaceProxies []applyWorkspaceProxy `yaml:"workspace_proxies"`
+}
+
+type applyTemplate struct {
+	Name string `yaml:"name"`
+	// Directory is the template source, relative to the manifest.
+	Directory   string            `yaml:"directory"`
+	DisplayName *string           `yaml:"display_name"`
+	Description *string           `yaml:"description"`
+	Icon        *string           `yaml:"icon"`
+	Variables   map[string]string `yaml:"variables"`
+
+	DefaultTTL         *clibase.Duration `yaml:"default_ttl"`
+	MaxTTL             *clibase.Duration `yaml:"max_ttl"`
+	IdleThreshold      *clibase.Duration `yaml:"idle_threshold"`
+	AllowUserAutostart *bool             `yaml:"allow_user_autostart"`
+	AllowUserAutostop  *bool             `yaml:"allow_user_autostop"`
+
+	// ACL replaces the users and groups with access to the template when
+	// set.
+	ACL *applyTemplateACL `yaml:"acl"`
+}
+
+type applyTemplateACL struct {
+	// Users and Groups map usernames and group names to a role.
+	Users  map[string]codersdk.TemplateRole `yaml:"users"`
+	Groups map[string]codersdk.TemplateRole `yaml:"groups"`
+}
+
+type applyGroup struct {
+	Name           string  `yaml:"name"`
+	AvatarURL      *string `yaml:"avatar_url"`
+	QuotaAllowance *int    `yaml:"quota_allowance"`
+	// Members replaces the members of the group when set.
+	Members *[]string `yaml:"members"`
+}
+
+type applyWorkspaceProxy struct {
+	Name        string  `yaml:"name"`
+	DisplayName *string `yaml:"display_name"`
+	Icon        *string `yaml:"icon"`
+}
+
+// applyChange is a planned change to a single resource.
+type applyChange struct {
+	create  bool
+	kind    string
+	name    string
+	details []string
+	apply   func(inv *clibase.Invocation) error
+}
+
+func (c applyChange) String() string {
+	var sb strings.Builder
+	if c.create {
+		_, _ = fmt.Fprintf(&sb, "+ create %s %q", c.kind, c.name)
+	} else {
+		_, _ = fmt.Fprintf(&sb, "~ update %s %q", c.kind, c.name)
+	}
+	for _, detail := range c.details {
+		_, _ = fmt.Fprintf(&sb, "\n    %s", detail)
+	}
+	return sb.String()
+}
+
+// applyDiff collects the fields that differ between the current and the
+// desired state of a resource.
+type applyDiff []string
+
+func (d *applyDiff) field(name string, current, desired any) {
+	if current == desired {
+		return
+	}
+	if _, ok := current.(string); ok {
+		*d = append(*d, fmt.Sprintf("%s: %q => %q", name, current, desired))
+		return
+	}
+	*d = append(*d, fmt.Sprintf("%s: %v => %v", name, current, desired))
+}
+
+func (r *RootCmd) apply() *clibase.Cmd {
+	var (
+		manifestPath string
+		dryRun       bool
+		provisioner  string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "apply",
+		Short: "Create or update templates, groups and workspace proxies from a manifest",
+		Long: "The manifest is compared with the deployment and only the differences are " +
+			"applied. Resources that aren't in the manifest are left alone, and fields " +
+			"that are omitted keep their current value.\n\n" + formatExamples(
+			example{
+				Description: "Show what would change without changing anything",
+				Command:     "coder apply -f coder.yaml --dry-run",
+			},
+			example{
+				Description: "Apply a manifest from CI without prompting",
+				Command:     "coder apply -f coder.yaml --yes",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if manifestPath == "" {
+				return xerrors.New("a manifest must be specified with --file")
+			}
+			manifest, dir, err := readApplyManifest(inv, manifestPath)
+			if err != nil {
+				return err
+			}
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+
+			planner := &applyPlanner{
+				client:       client,
+				organization: organization,
+				dir:          dir,
+				provisioner:  database.ProvisionerType(provisioner),
+			}
+			changes, err := planner.plan(inv.Context(), manifest)
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				_, _ = fmt.Fprintln(inv.Stdout, "No changes.")
+				return nil
+			}
+			for _, change := range changes {
+				_, _ = fmt.Fprintln(inv.Stdout, change.String())
+			}
+			_, _ = fmt.Fprintln(inv.Stdout)
+			if dryRun {
+				return nil
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Apply %d change(s)?", len(changes)),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+			for _, change := range changes {
+				err = change.apply(inv)
+				if err != nil {
+					return xerrors.Errorf("%s %q: %w", change.kind, change.name, err)
+				}
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Applied %d change(s) at %s!\n", len(changes), cliui.DefaultStyles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "file",
+			FlagShorthand: "f",
+			Description:   "Path to the manifest, use '-' to read it from stdin.",
+			Value:         clibase.StringOf(&manifestPath),
+		},
+		{
+			Flag:        "dry-run",
+			Description: "Show the planned changes without applying them.",
+			Value:       clibase.BoolOf(&dryRun),
+		},
+		{
+			Flag:        "test.provisioner",
+			Description: "Customize the provisioner backend.",
+			Default:     "terraform",
+			Value:       clibase.StringOf(&provisioner),
+			// This is for testing!
+			Hidden: true,
+		},
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
+
+// readApplyManifest reads and validates a manifest. It returns the
+// directory template sources are relative to.
+func readApplyManifest(inv *clibase.Invocation, path string) (applyManifest, string, error) {
+	var (
+		manifest applyManifest
+		reader   io.Reader
+		dir      string
+	)
+	if path == "-" {
+		reader = inv.Stdin
+		wd, err := os.Getwd()
+		if err != nil {
+			return manifest, "", xerrors.Errorf("get working directory: %w", err)
+		}
+		dir = wd
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return manifest, "", xerrors.Errorf("open manifest: %w", err)
+		}
+		defer f.Close()
+		reader = f
+		dir = filepath.Dir(path)
+	}
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+	err := decoder.Decode(&manifest)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return manifest, "", xerrors.Errorf("parse manifest: %w", err)
+	}
+	return manifest, dir, manifest.validate()
+}
+
+func (m applyManifest) validate() error {
+	seen := map[string]bool{}
+	unique := func(kind, name string) error {
+		if name == "" {
+			return xerrors.Errorf("every %s must have a name", kind)
+		}
+		if seen[kind+"/"+name] {
+			return xerrors.Errorf("%s %q is specified more than once", kind, name)
+		}
+		seen[kind+"/"+name] = true
+		return nil
+	}
+	for _, template := range m.Templates {
+		err := unique("template", template.Name)
+		if err != nil {
+			return err
+		}
+		if template.Directory == "" {
+			return xerrors.Errorf("template %q must have a directory", template.Name)
+		}
+		if template.ACL == nil {
+			continue
+		}
+		for name, role := range template.ACL.Users {
+			if role != codersdk.TemplateRoleUse && role != codersdk.TemplateRoleAdmin {
+				return xerrors.Errorf("template %q: user %q has invalid role %q, must be %q or %q", template.Name, name, role, codersdk.TemplateRoleUse, codersdk.TemplateRoleAdmin)
+			}
+		}
+		for name, role := range template.ACL.Groups {
+			if role != codersdk.TemplateRoleUse && role != codersdk.TemplateRoleAdmin {
+				return xerrors.Errorf("template %q: group %q has invalid role %q, must be %q or %q", template.Name, name, role, codersdk.TemplateRoleUse, codersdk.TemplateRoleAdmin)
+			}
+		}
+	}
+	for _, group := range m.Groups {
+		err := unique("group", group.Name)
+		if err != nil {
+			return err
+		}
+	}
+	for _, proxy := range m.WorkspaceProxies {
+		err := unique("workspace proxy", proxy.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyPlanner compares a manifest with the deployment.
+type applyPlanner struct {
+	client       *codersdk.Client
+	organization codersdk.Organization
+	dir          string
+	provisioner  database.ProvisionerType
+}
+
+// plan returns the changes needed to reconcile the deployment with the
+// manifest. Groups come first since template ACLs may refer to them.
+func (p *applyPlanner) plan(ctx context.Context, manifest applyManifest) ([]applyChange, error) {
+	var changes []applyChange
+	for _, group := range manifest.Groups {
+		change, err := p.planGroup(ctx, group)
+		if err != nil {
+			return nil, xerrors.Errorf("group %q: %w", group.Name, err)
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	for _, proxy := range manifest.WorkspaceProxies {
+		change, err := p.planWorkspaceProxy(ctx, proxy)
+		if err != nil {
+			return nil, xerrors.Errorf("workspace proxy %q: %w", proxy.Name, err)
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	for _, template := range manifest.Templates {
+		change, err := p.planTemplate(ctx, template)
+		if err != nil {
+			return nil, xerrors.Errorf("template %q: %w", template.Name, err)
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+func (p *applyPlanner) planTemplate(ctx context.Context, desired applyTemplate) (*applyChange, error) {
+	dir := desired.Directory
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(p.dir, dir)
+	}
+	var archive bytes.Buffer
+	err := provisionersdk.Tar(&archive, dir, provisionersdk.TemplateArchiveLimit)
+	if err != nil {
+		return nil, xerrors.Errorf("archive %s: %w", prettyDirectoryPath(dir), err)
+	}
+
+	template, err := p.client.TemplateByName(ctx, p.organization.ID, desired.Name)
+	if isNotFound(err) {
+		return &applyChange{
+			create:  true,
+			kind:    "template",
+			name:    desired.Name,
+			details: []string{fmt.Sprintf("source: %s", prettyDirectoryPath(dir))},
+			apply: func(inv *clibase.Invocation) error {
+				version, err := p.createTemplateVersion(inv, archive.Bytes(), desired, nil)
+				if err != nil {
+					return err
+				}
+				template, err := p.client.CreateTemplate(inv.Context(), p.organization.ID, codersdk.CreateTemplateRequest{
+					Name:      desired.Name,
+					VersionID: version.ID,
+				})
+				if err != nil {
+					return xerrors.Errorf("create template: %w", err)
+				}
+				return p.updateTemplate(inv.Context(), template, desired)
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get template: %w", err)
+	}
+
+	var diff applyDiff
+	sourceChanged, err := p.templateSourceChanged(ctx, template, archive.Bytes(), desired.Variables, &diff)
+	if err != nil {
+		return nil, err
+	}
+	current, wanted := templateMetaRequest(template, applyTemplate{}), templateMetaRequest(template, desired)
+	diff.field("display_name", current.DisplayName, wanted.DisplayName)
+	diff.field("description", current.Description, wanted.Description)
+	diff.field("icon", current.Icon, wanted.Icon)
+	diff.field("default_ttl", millisDuration(current.DefaultTTLMillis), millisDuration(wanted.DefaultTTLMillis))
+	diff.field("max_ttl", millisDuration(current.MaxTTLMillis), millisDuration(wanted.MaxTTLMillis))
+	diff.field("idle_threshold", millisDuration(current.IdleThresholdMillis), millisDuration(wanted.IdleThresholdMillis))
+	diff.field("allow_user_autostart", current.AllowUserAutostart, wanted.AllowUserAutostart)
+	diff.field("allow_user_autostop", current.AllowUserAutostop, wanted.AllowUserAutostop)
+	if desired.ACL != nil {
+		acl, err := p.client.TemplateACL(ctx, template.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template ACL: %w", err)
+		}
+		currentUsers := map[string]codersdk.TemplateRole{}
+		for _, user := range acl.Users {
+			currentUsers[user.Username] = user.Role
+		}
+		currentGroups := map[string]codersdk.TemplateRole{}
+		for _, group := range acl.Groups {
+			currentGroups[group.Name] = group.Role
+		}
+		diffRoles(&diff, "user", currentUsers, desired.ACL.Users)
+		diffRoles(&diff, "group", currentGroups, desired.ACL.Groups)
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	return &applyChange{
+		kind:    "template",
+		name:    desired.Name,
+		details: diff,
+		apply: func(inv *clibase.Invocation) error {
+			if sourceChanged {
+				version, err := p.createTemplateVersion(inv, archive.Bytes(), desired, &template)
+				if err != nil {
+					return err
+				}
+				err = p.client.UpdateActiveTemplateVersion(inv.Context(), template.ID, codersdk.UpdateActiveTemplateVersion{
+					ID: version.ID,
+				})
+				if err != nil {
+					return xerrors.Errorf("update active version: %w", err)
+				}
+			}
+			return p.updateTemplate(inv.Context(), template, desired)
+		},
+	}, nil
+}
+
+// templateSourceChanged compares the archive and variables with the active
+// version of the template. Sensitive variables can't be compared, so they
+// only change along with the source.
+func (p *applyPlanner) templateSourceChanged(ctx context.Context, template codersdk.Template, archive []byte, variables map[string]string, diff *applyDiff) (bool, error) {
+	version, err := p.client.TemplateVersion(ctx, template.ActiveVersionID)
+	if err != nil {
+		return false, xerrors.Errorf("get active version: %w", err)
+	}
+	changed := false
+	activeArchive, contentType, err := p.client.Download(ctx, version.Job.FileID)
+	if err != nil {
+		return false, xerrors.Errorf("download active version: %w", err)
+	}
+	if contentType != codersdk.ContentTypeTar {
+		changed = true
+	} else {
+		changed, err = archivesDiffer(activeArchive, archive)
+		if err != nil {
+			return false, err
+		}
+	}
+	if changed {
+		*diff = append(*diff, fmt.Sprintf("source: %s => new version", version.Name))
+	}
+
+	activeVariables, err := p.client.TemplateVersionVariables(ctx, version.ID)
+	if err != nil {
+		return false, xerrors.Errorf("get active version variables: %w", err)
+	}
+	current := map[string]codersdk.TemplateVersionVariable{}
+	for _, variable := range activeVariables {
+		current[variable.Name] = variable
+	}
+	for _, name := range sortedKeys(variables) {
+		variable, ok := current[name]
+		switch {
+		case !ok:
+			*diff = append(*diff, fmt.Sprintf("variable %s: (unset) => %q", name, variables[name]))
+			changed = true
+		case variable.Sensitive:
+		case variable.Value != variables[name]:
+			*diff = append(*diff, fmt.Sprintf("variable %s: %q => %q", name, variable.Value, variables[name]))
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+func (p *applyPlanner) createTemplateVersion(inv *clibase.Invocation, archive []byte, desired applyTemplate, template *codersdk.Template) (*codersdk.TemplateVersion, error) {
+	upload, err := p.client.Upload(inv.Context(), codersdk.ContentTypeTar, bytes.NewReader(archive))
+	if err != nil {
+		return nil, xerrors.Errorf("upload: %w", err)
+	}
+	var variables []string
+	for _, name := range sortedKeys(desired.Variables) {
+		variables = append(variables, name+"="+desired.Variables[name])
+	}
+	return createValidTemplateVersion(inv, createValidTemplateVersionArgs{
+		Client:          p.client,
+		Organization:    p.organization,
+		Provisioner:     p.provisioner,
+		FileID:          upload.ID,
+		Variables:       variables,
+		Template:        template,
+		ReuseParameters: true,
+	})
+}
+
+// updateTemplate updates the metadata and ACL of a template if they
+// differ from the manifest.
+func (p *applyPlanner) updateTemplate(ctx context.Context, template codersdk.Template, desired applyTemplate) error {
+	req := templateMetaRequest(template, desired)
+	if req != templateMetaRequest(template, applyTemplate{}) {
+		_, err := p.client.UpdateTemplateMeta(ctx, template.ID, req)
+		if err != nil {
+			return xerrors.Errorf("update template metadata: %w", err)
+		}
+	}
+	if desired.ACL == nil {
+		return nil
+	}
+
+	acl, err := p.client.TemplateACL(ctx, template.ID)
+	if err != nil {
+		return xerrors.Errorf("get template ACL: %w", err)
+	}
+	update := codersdk.UpdateTemplateACL{
+		UserPerms:  map[string]codersdk.TemplateRole{},
+		GroupPerms: map[string]codersdk.TemplateRole{},
+	}
+	for _, user := range acl.Users {
+		if _, ok := desired.ACL.Users[user.Username]; !ok {
+			update.UserPerms[user.ID.String()] = codersdk.TemplateRoleDeleted
+		}
+	}
+	for _, group := range acl.Groups {
+		if _, ok := desired.ACL.Groups[group.Name]; !ok {
+			update.GroupPerms[group.ID.String()] = codersdk.TemplateRoleDeleted
+		}
+	}
+	for name, role := range desired.ACL.Users {
+		user, err := p.client.User(ctx, name)
+		if err != nil {
+			return xerrors.Errorf("get user %q: %w", name, err)
+		}
+		update.UserPerms[user.ID.String()] = role
+	}
+	for name, role := range desired.ACL.Groups {
+		group, err := p.client.GroupByOrgAndName(ctx, p.organization.ID, name)
+		if err != nil {
+			return xerrors.Errorf("get group %q: %w", name, err)
+		}
+		update.GroupPerms[group.ID.String()] = role
+	}
+	err = p.client.UpdateTemplateACL(ctx, template.ID, update)
+	if err != nil {
+		return xerrors.Errorf("update template ACL: %w", err)
+	}
+	return nil
+}
+
+// templateMetaRequest returns the metadata of the template with the fields
+// set in the manifest replaced. coderd replaces all fields on update, so
+// the request must include the ones that aren't changing.
+func templateMetaRequest(template codersdk.Template, desired applyTemplate) codersdk.UpdateTemplateMeta {
+	req := codersdk.UpdateTemplateMeta{
+		Name:                         template.Name,
+		DisplayName:                  template.DisplayName,
+		Description:                  template.Description,
+		Icon:                         template.Icon,
+		DefaultTTLMillis:             template.DefaultTTLMillis,
+		MaxTTLMillis:                 template.MaxTTLMillis,
+		AllowUserAutostart:           template.AllowUserAutostart,
+		AllowUserAutostop:            template.AllowUserAutostop,
+		AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+		FailureTTLMillis:             template.FailureTTLMillis,
+		InactivityTTLMillis:          template.InactivityTTLMillis,
+		LockedTTLMillis:              template.LockedTTLMillis,
+		IdleThresholdMillis:          template.IdleThresholdMillis,
+	}
+	if desired.DisplayName != nil {
+		req.DisplayName = *desired.DisplayName
+	}
+	if desired.Description != nil {
+		req.Description = *desired.Description
+	}
+	if desired.Icon != nil {
+		req.Icon = *desired.Icon
+	}
+	if desired.DefaultTTL != nil {
+		req.DefaultTTLMillis = desired.DefaultTTL.Value().Milliseconds()
+	}
+	if desired.MaxTTL != nil {
+		req.MaxTTLMillis = desired.MaxTTL.Value().Milliseconds()
+	}
+	if desired.IdleThreshold != nil {
+		req.IdleThresholdMillis = desired.IdleThreshold.Value().Milliseconds()
+	}
+	if desired.AllowUserAutostart != nil {
+		req.AllowUserAutostart = *desired.AllowUserAutostart
+	}
+	if desired.AllowUserAutostop != nil {
+		req.AllowUserAutostop = *desired.AllowUserAutostop
+	}
+	return req
+}
+
+func (p *applyPlanner) planGroup(ctx context.Context, desired applyGroup) (*applyChange, error) {
+	group, err := p.client.GroupByOrgAndName(ctx, p.organization.ID, desired.Name)
+	if isNotFound(err) {
+		return &applyChange{
+			create: true,
+			kind:   "group",
+			name:   desired.Name,
+			apply: func(inv *clibase.Invocation) error {
+				req := codersdk.CreateGroupRequest{Name: desired.Name}
+				if desired.AvatarURL != nil {
+					req.AvatarURL = *desired.AvatarURL
+				}
+				if desired.QuotaAllowance != nil {
+					req.QuotaAllowance = *desired.QuotaAllowance
+				}
+				group, err := p.client.CreateGroup(inv.Context(), p.organization.ID, req)
+				if err != nil {
+					return xerrors.Errorf("create group: %w", err)
+				}
+				if desired.Members == nil || len(*desired.Members) == 0 {
+					return nil
+				}
+				return p.updateGroup(inv.Context(), group, desired)
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get group: %w", err)
+	}
+
+	var diff applyDiff
+	if desired.AvatarURL != nil {
+		diff.field("avatar_url", group.AvatarURL, *desired.AvatarURL)
+	}
+	if desired.QuotaAllowance != nil {
+		diff.field("quota_allowance", group.QuotaAllowance, *desired.QuotaAllowance)
+	}
+	if desired.Members != nil {
+		added, removed := groupMemberChanges(group, *desired.Members)
+		for _, username := range added {
+			diff = append(diff, fmt.Sprintf("add member %s", username))
+		}
+		for _, member := range removed {
+			diff = append(diff, fmt.Sprintf("remove member %s", member.Username))
+		}
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+	return &applyChange{
+		kind:    "group",
+		name:    desired.Name,
+		details: diff,
+		apply: func(inv *clibase.Invocation) error {
+			return p.updateGroup(inv.Context(), group, desired)
+		},
+	}, nil
+}
+
+func (p *applyPlanner) updateGroup(ctx context.Context, group codersdk.Group, desired applyGroup) error {
+	req := codersdk.PatchGroupRequest{
+		AvatarURL:      desired.AvatarURL,
+		QuotaAllowance: desired.QuotaAllowance,
+	}
+	if desired.Members != nil {
+		added, removed := groupMemberChanges(group, *desired.Members)
+		for _, username := range added {
+			user, err := p.client.User(ctx, username)
+			if err != nil {
+				return xerrors.Errorf("get user %q: %w", username, err)
+			}
+			req.AddUsers = append(req.AddUsers, user.ID.String())
+		}
+		for _, member := range removed {
+			req.RemoveUsers = append(req.RemoveUsers, member.ID.String())
+		}
+	}
+	_, err := p.client.PatchGroup(ctx, group.ID, req)
+	if err != nil {
+		return xerrors.Errorf("update group: %w", err)
+	}
+	return nil
+}
+
+// groupMemberChanges returns the usernames to add to the group and the
+// members to remove from it.
+func groupMemberChanges(group codersdk.Group, usernames []string) ([]string, []codersdk.User) {
+	desired := map[string]bool{}
+	for _, username := range usernames {
+		desired[username] = true
+	}
+	current := map[string]bool{}
+	var removed []codersdk.User
+	for _, member := range group.Members {
+		current[member.Username] = true
+		if !desired[member.Username] {
+			removed = append(removed, member)
+		}
+	}
+	var added []string
+	for _, username := range usernames {
+		if !current[username] {
+			added = append(added, username)
+		}
+	}
+	return added, removed
+}
+
+func (p *applyPlanner) planWorkspaceProxy(ctx context.Context, desired applyWorkspaceProxy) (*applyChange, error) {
+	proxy, err := p.client.WorkspaceProxyByName(ctx, desired.Name)
+	if isNotFound(err) {
+		return &applyChange{
+			create: true,
+			kind:   "workspace proxy",
+			name:   desired.Name,
+			apply: func(inv *clibase.Invocation) error {
+				req := codersdk.CreateWorkspaceProxyRequest{Name: desired.Name}
+				if desired.DisplayName != nil {
+					req.DisplayName = *desired.DisplayName
+				}
+				if desired.Icon != nil {
+					req.Icon = *desired.Icon
+				}
+				resp, err := p.client.CreateWorkspaceProxy(inv.Context(), req)
+				if err != nil {
+					return xerrors.Errorf("create workspace proxy: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Workspace proxy %q created, its token is only shown once:\n\n\t%s\n\n", desired.Name, resp.ProxyToken)
+				return nil
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace proxy: %w", err)
+	}
+
+	req := codersdk.PatchWorkspaceProxy{
+		ID:          proxy.ID,
+		Name:        proxy.Name,
+		DisplayName: proxy.DisplayName,
+		Icon:        proxy.Icon,
+	}
+	var diff applyDiff
+	if desired.DisplayName != nil {
+		diff.field("display_name", proxy.DisplayName, *desired.DisplayName)
+		req.DisplayName = *desired.DisplayName
+	}
+	if desired.Icon != nil {
+		diff.field("icon", proxy.Icon, *desired.Icon)
+		req.Icon = *desired.Icon
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+	return &applyChange{
+		kind:    "workspace proxy",
+		name:    desired.Name,
+		details: diff,
+		apply: func(inv *clibase.Invocation) error {
+			_, err := p.client.PatchWorkspaceProxy(inv.Context(), req)
+			if err != nil {
+				return xerrors.Errorf("update workspace proxy: %w", err)
+			}
+			return nil
+		},
+	}, nil
+}
+
+func diffRoles(diff *applyDiff, kind string, current, desired map[string]codersdk.TemplateRole) {
+	for _, name := range sortedKeys(current) {
+		if _, ok := desired[name]; !ok {
+			*diff = append(*diff, fmt.Sprintf("%s %s: %s => (none)", kind, name, current[name]))
+		}
+	}
+	for _, name := range sortedKeys(desired) {
+		role, ok := current[name]
+		switch {
+		case !ok:
+			*diff = append(*diff, fmt.Sprintf("%s %s: (none) => %s", kind, name, desired[name]))
+		case role != desired[name]:
+			*diff = append(*diff, fmt.Sprintf("%s %s: %s => %s", kind, name, role, desired[name]))
+		}
+	}
+}
+
+// archivesDiffer compares the files in two tar archives, ignoring metadata
+// like modification times that change when a repository is cloned.
+func archivesDiffer(a, b []byte) (bool, error) {
+	digestA, err := archiveDigest(a)
+	if err != nil {
+		return false, err
+	}
+	digestB, err := archiveDigest(b)
+	if err != nil {
+		return false, err
+	}
+	if len(digestA) != len(digestB) {
+		return true, nil
+	}
+	for name, sum := range digestA {
+		if digestB[name] != sum {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// archiveDigest returns the SHA256 sum of each file in a tar archive.
+func archiveDigest(archive []byte) (map[string]string, error) {
+	digest := map[string]string{}
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return digest, nil
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read archive: %w", err)
+		}
+		switch header.Typeflag {
+		case tar.TypeReg:
+			hash := sha256.New()
+			_, err = io.Copy(hash, reader)
+			if err != nil {
+				return nil, xerrors.Errorf("read archive: %w", err)
+			}
+			digest[filepath.Clean(header.Name)] = hex.EncodeToString(hash.Sum(nil))
+		case tar.TypeSymlink:
+			digest[filepath.Clean(header.Name)] = "-> " + header.Linkname
+		}
+	}
+}
+
+func millisDuration(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	// writeManifest writes a manifest with a single template.
+	writeManifest := func(t *testing.T, name, description string) (string, string) {
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ProvisionComplete,
+		})
+		manifest := filepath.Join(t.TempDir(), "coder.yaml")
+		err := os.WriteFile(manifest, []byte(fmt.Sprintf(`templates:
+  - name: %s
+    directory: %s
+    description: %s
+    default_ttl: 2h
+`, name, source, description)), 0o600)
+		require.NoError(t, err)
+		return manifest, source
+	}
+
+	run := func(t *testing.T, client *codersdk.Client, args ...string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, append([]string{"apply", "--test.provisioner", string(database.ProvisionerTypeEcho), "-y"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		return stdout.String(), err
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		manifest, _ := writeManifest(t, "docker", "Develop in Docker")
+
+		out, err := run(t, client, "-f", manifest)
+		require.NoError(t, err)
+		require.Contains(t, out, `+ create template "docker"`)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		template, err := client.TemplateByName(ctx, user.OrganizationID, "docker")
+		require.NoError(t, err)
+		require.Equal(t, "Develop in Docker", template.Description)
+		require.Equal(t, 2*time.Hour, time.Duration(template.DefaultTTLMillis)*time.Millisecond)
+
+		// Applying the same manifest again doesn't change anything.
+		out, err = run(t, client, "-f", manifest)
+		require.NoError(t, err)
+		require.Contains(t, out, "No changes.")
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		manifest, _ := writeManifest(t, "docker", "before")
+		_, err := run(t, client, "-f", manifest)
+		require.NoError(t, err)
+
+		manifest, _ = writeManifest(t, "docker", "after")
+		out, err := run(t, client, "-f", manifest, "--dry-run")
+		require.NoError(t, err)
+		require.Contains(t, out, `~ update template "docker"`)
+		require.Contains(t, out, `description: "before" => "after"`)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		template, err := client.TemplateByName(ctx, user.OrganizationID, "docker")
+		require.NoError(t, err)
+		require.Equal(t, "before", template.Description)
+	})
+
+	t.Run("SourceChanged", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		manifest, source := writeManifest(t, "docker", "Develop in Docker")
+		_, err := run(t, client, "-f", manifest)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		before, err := client.TemplateByName(ctx, user.OrganizationID, "docker")
+		require.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(source, "README.md"), []byte("# Docker"), 0o600)
+		require.NoError(t, err)
+		out, err := run(t, client, "-f", manifest)
+		require.NoError(t, err)
+		require.Contains(t, out, "source:")
+
+		after, err := client.TemplateByName(ctx, user.OrganizationID, "docker")
+		require.NoError(t, err)
+		require.NotEqual(t, before.ActiveVersionID, after.ActiveVersionID)
+	})
+
+	t.Run("InvalidManifest", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		manifest := filepath.Join(t.TempDir(), "coder.yaml")
+		err := os.WriteFile(manifest, []byte("templates:\n  - name: docker\n    unknown: true\n"), 0o600)
+		require.NoError(t, err)
+
+		_, err = run(t, client, "-f", manifest)
+		require.ErrorContains(t, err, "field unknown not found")
+	})
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+// bulkWorkspaces is the --query mode of the commands that build a workspace,
+// which builds all workspaces matching a search query instead.
+type bulkWorkspaces struct {
+	query    string
+	parallel int64
+}
+
+// bulkAction is the build a command runs in each workspace.
+type bulkAction struct {
+	// Verb is the action in the confirmation, e.g. "Stop".
+	Verb string
+	// Done is the action in the summary, e.g. "Stopped".
+	Done string
+	// Skip returns true for workspaces that don't need the build, which
+	// are summarized with SkipReason, e.g. "are already stopped".
+	Skip       func(codersdk.Workspace) bool
+	SkipReason string
+	// ConfirmDefault is the default of the confirmation prompt.
+	ConfirmDefault string
+	Build          func(ctx context.Context, workspace codersdk.Workspace) (codersdk.WorkspaceBuild, error)
+}
+
+type bulkWorkspaceRow struct {
+	Workspace string `table:"workspace,default_sort"`
+	Template  string `table:"template"`
+	Status    string `table:"status"`
+	Outdated  bool   `table:"outdated"`
+}
+
+func (b *bulkWorkspaces) options() clibase.OptionSet {
+	return clibase.OptionSet{
+		{
+			Flag:        "query",
+			Description: "Build all workspaces matching a search query, like the one of coder list, instead of a single workspace.",
+			Value:       clibase.StringOf(&b.query),
+		},
+		{
+			Flag:        "parallel",
+			Description: "The number of workspaces to build at the same time when using --query.",
+			Default:     "4",
+			Value:       clibase.Int64Of(&b.parallel),
+		},
+	}
+}
+
+// requireArgs requires a workspace argument, unless --query is set.
+func (b *bulkWorkspaces) requireArgs() clibase.MiddlewareFunc {
+	return func(next clibase.HandlerFunc) clibase.HandlerFunc {
+		return func(inv *clibase.Invocation) error {
+			if b.query != "" {
+				return clibase.RequireNArgs(0)(next)(inv)
+			}
+			return clibase.RequireNArgs(1)(next)(inv)
+		}
+	}
+}
+
+// run shows the workspaces matching the query, and once confirmed builds
+// them with at most --parallel builds at a time. Failed builds don't stop
+// the others, and are summarized at the end.
+func (b *bulkWorkspaces) run(inv *clibase.Invocation, client *codersdk.Client, action bulkAction) error {
+	ctx := inv.Context()
+	if b.parallel < 1 {
+		return xerrors.New("--parallel must be at least 1")
+	}
+
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: b.query})
+	if err != nil {
+		return xerrors.Errorf("search workspaces: %w", err)
+	}
+	var (
+		workspaces []codersdk.Workspace
+		skipped    int
+	)
+	for _, workspace := range res.Workspaces {
+		if action.Skip != nil && action.Skip(workspace) {
+			skipped++
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	if skipped > 0 {
+		cliui.Infof(inv.Stderr, "Skipping %d workspace(s) that %s.\n", skipped, action.SkipReason)
+	}
+	if len(workspaces) == 0 {
+		cliui.Infof(inv.Stderr, "No workspaces to %s match %q.\n", strings.ToLower(action.Verb), b.query)
+		return nil
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		return bulkWorkspaceName(workspaces[i]) < bulkWorkspaceName(workspaces[j])
+	})
+
+	rows := make([]bulkWorkspaceRow, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		rows = append(rows, bulkWorkspaceRow{
+			Workspace: bulkWorkspaceName(workspace),
+			Template:  workspace.TemplateName,
+			Status:    codersdk.WorkspaceDisplayStatus(workspace.LatestBuild.Job.Status, workspace.LatestBuild.Transition),
+			Outdated:  workspace.Outdated,
+		})
+	}
+	table, err := cliui.DisplayTable(rows, "workspace", nil)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(inv.Stdout, table)
+
+	_, err = cliui.Prompt(inv, cliui.PromptOptions{
+		Text:      fmt.Sprintf("%s %d workspace(s)?", action.Verb, len(workspaces)),
+		IsConfirm: true,
+		Default:   action.ConfirmDefault,
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		mu       sync.Mutex
+		finished int
+		failures = map[string]error{}
+		eg       errgroup.Group
+	)
+	eg.SetLimit(int(b.parallel))
+	for _, workspace := range workspaces {
+		workspace := workspace
+		eg.Go(func() error {
+			name := bulkWorkspaceName(workspace)
+			build, err := action.Build(ctx, workspace)
+			if err == nil {
+				// The logs are only of interest for failed builds, which
+				// are reported with their error.
+				err = cliui.WorkspaceBuild(ctx, io.Discard, client, build.ID)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			finished++
+			progress := fmt.Sprintf("[%d/%d]", finished, len(workspaces))
+			if err != nil {
+				failures[name] = err
+				_, _ = fmt.Fprintf(inv.Stdout, "%s %s %s: %s\n", progress, cliui.DefaultStyles.Error.Render("✘"), name, err)
+				return nil
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "%s %s %s\n", progress, cliui.DefaultStyles.Keyword.Render("✔"), name)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	_, _ = fmt.Fprintf(inv.Stdout, "\n%s %d of %d workspace(s) at %s.\n", action.Done, len(workspaces)-len(failures), len(workspaces),
+		cliui.DefaultStyles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
+	if len(failures) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var msg strings.Builder
+	_, _ = fmt.Fprintf(&msg, "%d workspace(s) failed:", len(failures))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&msg, "\n\t%s: %s", name, failures[name])
+	}
+	return xerrors.New(msg.String())
+}
+
+func bulkWorkspaceName(workspace codersdk.Workspace) string {
+	return workspace.OwnerName + "/" + workspace.Name
+}
+
+// workspaceBuilt returns whether the last build of the workspace was the
+// transition and succeeded.
+func workspaceBuilt(workspace codersdk.Workspace, transition codersdk.WorkspaceTransition) bool {
+	return workspace.LatestBuild.Transition == transition &&
+		workspace.LatestBuild.Job.Status == codersdk.ProvisionerJobSucceeded
+}
@@ -0,0 +1,85 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestBulkWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	createTemplate := func() codersdk.Template {
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		return coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	}
+	createWorkspace := func(template codersdk.Template) codersdk.Workspace {
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		return workspace
+	}
+	template, other := createTemplate(), createTemplate()
+	workspaces := []codersdk.Workspace{createWorkspace(template), createWorkspace(template)}
+	otherWorkspace := createWorkspace(other)
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		inv, root := clitest.New(t, args...)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+		return stdout.String()
+	}
+	transition := func(t *testing.T, workspace codersdk.Workspace) codersdk.WorkspaceTransition {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		workspace, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		return workspace.LatestBuild.Transition
+	}
+
+	out := run(t, "stop", "--query", "template:"+template.Name, "--yes")
+	require.Contains(t, out, workspaces[0].Name)
+	require.Contains(t, out, workspaces[1].Name)
+	require.NotContains(t, out, otherWorkspace.Name)
+	require.Contains(t, out, "Stopped 2 of 2 workspace(s)")
+	for _, workspace := range workspaces {
+		require.Equal(t, codersdk.WorkspaceTransitionStop, transition(t, workspace))
+	}
+	require.Equal(t, codersdk.WorkspaceTransitionStart, transition(t, otherWorkspace))
+
+	// Stopped workspaces are skipped.
+	out = run(t, "stop", "--query", "template:"+template.Name, "--yes")
+	require.NotContains(t, out, "Stopped")
+
+	out = run(t, "start", "--query", "template:"+template.Name, "--yes", "--parallel", "1")
+	require.Contains(t, out, "[2/2]")
+	require.Contains(t, out, "Started 2 of 2 workspace(s)")
+	for _, workspace := range workspaces {
+		require.Equal(t, codersdk.WorkspaceTransitionStart, transition(t, workspace))
+	}
+
+	out = run(t, "delete", "--query", "template:"+template.Name, "--yes")
+	require.Contains(t, out, "Deleted 2 of 2 workspace(s)")
+
+	t.Run("WorkspaceAndQuery", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "stop", otherWorkspace.Name, "--query", "template:"+other.Name, "--yes")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/config"
+)
+
+// errNoCache is returned when there's no cached data for the deployment.
+var errNoCache = xerrors.New("nothing cached for this deployment")
+
+// cachedList is the format of the files in the cache directory. Only the
+// latest list is stored, along with the deployment it's from so logging in
+// to another deployment doesn't show stale data.
+type cachedList[T any] struct {
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Items     []T       `json:"items"`
+}
+
+func readCache[T any](file config.File, deploymentURL *url.URL) (cachedList[T], error) {
+	var list cachedList[T]
+	raw, err := file.Read()
+	if errors.Is(err, os.ErrNotExist) {
+		return list, errNoCache
+	}
+	if err != nil {
+		return list, xerrors.Errorf("read cache: %w", err)
+	}
+	err = json.Unmarshal([]byte(raw), &list)
+	if err != nil {
+		return list, xerrors.Errorf("decode cache: %w", err)
+	}
+	if list.URL != deploymentURL.String() {
+		return cachedList[T]{}, errNoCache
+	}
+	return list, nil
+}
+
+func writeCache[T any](file config.File, deploymentURL *url.URL, items []T) error {
+	raw, err := json.Marshal(cachedList[T]{
+		URL:       deploymentURL.String(),
+		UpdatedAt: time.Now(),
+		Items:     items,
+	})
+	if err != nil {
+		return xerrors.Errorf("encode cache: %w", err)
+	}
+	return file.Write(string(raw))
+}
@@ -50,6 +50,9 @@ type Cmd struct {
 	Middleware  MiddlewareFunc
 	Handler     HandlerFunc
 	HelpHandler HandlerFunc
+	// CompletionHandler returns the candidates for a positional argument
+	// during shell completion. See Complete.
+	CompletionHandler CompletionHandlerFunc
 }
 
 // AddSubcommands adds the given subcommands, setting their
@@ -0,0 +1,164 @@
+package clibase
+
+import (
+	"strings"
+)
+
+// CompletionHandlerFunc returns the candidates for the positional argument
+// being completed. inv.Args holds the positional arguments before it.
+type CompletionHandlerFunc func(inv *Invocation) []string
+
+// Completion is a candidate for the word being completed.
+type Completion struct {
+	Value       string
+	Description string
+}
+
+// Complete returns the candidates for the last of words, the arguments
+// following the name of c on a command line. The last word is empty when
+// completing a new argument. inv provides the context and environment for
+// completion handlers.
+func (c *Cmd) Complete(inv *Invocation, words []string) []Completion {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cmd := c
+	var (
+		positional []string
+		// valueFor is the option awaiting a value from the next word.
+		valueFor *Option
+		// rest is set after "--", when no more flags are parsed.
+		rest bool
+	)
+	for _, word := range words[:len(words)-1] {
+		switch {
+		case valueFor != nil:
+			valueFor = nil
+		case rest:
+			positional = append(positional, word)
+		case word == "--":
+			rest = true
+		case strings.HasPrefix(word, "-") && word != "-":
+			if !strings.Contains(word, "=") {
+				valueFor = completionFlag(cmd, word)
+			}
+		default:
+			if len(positional) == 0 && !cmd.RawArgs {
+				if child := completionChild(cmd, word); child != nil {
+					cmd = child
+					continue
+				}
+			}
+			positional = append(positional, word)
+		}
+	}
+
+	word := words[len(words)-1]
+	var completions []Completion
+	switch {
+	case valueFor != nil:
+		completions = completionValues(valueFor, "")
+	case !rest && strings.HasPrefix(word, "--") && strings.Contains(word, "="):
+		flag, _, _ := strings.Cut(word, "=")
+		if opt := completionFlag(cmd, flag); opt != nil {
+			completions = completionValues(opt, flag+"=")
+		}
+	case !rest && strings.HasPrefix(word, "-"):
+		for _, opt := range cmd.FullOptions() {
+			if opt.Flag == "" || opt.Hidden {
+				continue
+			}
+			completions = append(completions, Completion{
+				Value:       "--" + opt.Flag,
+				Description: opt.Description,
+			})
+		}
+	default:
+		if len(positional) == 0 && !cmd.RawArgs {
+			for _, child := range cmd.Children {
+				if child.Hidden {
+					continue
+				}
+				completions = append(completions, Completion{
+					Value:       child.Name(),
+					Description: child.Short,
+				})
+			}
+		}
+		if cmd.CompletionHandler != nil {
+			for _, value := range cmd.CompletionHandler(&Invocation{
+				ctx:     inv.ctx,
+				Command: cmd,
+				Args:    positional,
+				Environ: inv.Environ,
+				Stdout:  inv.Stdout,
+				Stderr:  inv.Stderr,
+				Stdin:   inv.Stdin,
+			}) {
+				completions = append(completions, Completion{Value: value})
+			}
+		}
+	}
+
+	matches := completions[:0]
+	for _, completion := range completions {
+		if strings.HasPrefix(completion.Value, word) {
+			matches = append(matches, completion)
+		}
+	}
+	return matches
+}
+
+func completionChild(cmd *Cmd, name string) *Cmd {
+	for _, child := range cmd.Children {
+		child.Parent = cmd
+		if child.Name() == name {
+			return child
+		}
+		for _, alias := range child.Aliases {
+			if alias == name {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// completionFlag returns the option of the flag if it takes its value from
+// the next argument.
+func completionFlag(cmd *Cmd, flag string) *Option {
+	var long, short string
+	if strings.HasPrefix(flag, "--") {
+		long = strings.TrimPrefix(flag, "--")
+	} else {
+		short = strings.TrimPrefix(flag, "-")
+	}
+	for _, opt := range cmd.FullOptions() {
+		opt := opt
+		if opt.Flag == "" || opt.Value == nil {
+			continue
+		}
+		if (long != "" && opt.Flag == long) || (short != "" && opt.FlagShorthand == short) {
+			if no, ok := opt.Value.(NoOptDefValuer); ok && no.NoOptDefValue() != "" {
+				return nil
+			}
+			return &opt
+		}
+	}
+	return nil
+}
+
+func completionValues(opt *Option, prefix string) []Completion {
+	var choices []string
+	switch v := opt.Value.(type) {
+	case *Enum:
+		choices = v.Choices
+	case *Bool:
+		choices = []string{"true", "false"}
+	}
+	completions := make([]Completion, 0, len(choices))
+	for _, choice := range choices {
+		completions = append(completions, Completion{Value: prefix + choice})
+	}
+	return completions
+}
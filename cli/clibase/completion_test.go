@@ -0,0 +1,115 @@
+package clibase_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clibase"
+)
+
+func TestComplete(t *testing.T) {
+	t.Parallel()
+
+	cmd := func() *clibase.Cmd {
+		var (
+			verbose bool
+			format  string
+			name    string
+		)
+		return &clibase.Cmd{
+			Use: "root",
+			Options: clibase.OptionSet{
+				{
+					Flag:        "verbose",
+					Description: "Log more.",
+					Value:       clibase.BoolOf(&verbose),
+				},
+				{
+					Flag:   "secret",
+					Hidden: true,
+					Value:  clibase.StringOf(&name),
+				},
+			},
+			Children: []*clibase.Cmd{
+				{
+					Use:     "greet <name>",
+					Short:   "Greets someone",
+					Aliases: []string{"hi"},
+					Options: clibase.OptionSet{
+						{
+							Flag:          "format",
+							FlagShorthand: "f",
+							Value:         clibase.EnumOf(&format, "plain", "fancy", "formal"),
+						},
+						{
+							Flag:  "name",
+							Value: clibase.StringOf(&name),
+						},
+					},
+					CompletionHandler: func(inv *clibase.Invocation) []string {
+						if len(inv.Args) > 0 {
+							return nil
+						}
+						return []string{"alice", "bob"}
+					},
+				},
+				{
+					Use:   "gone",
+					Short: "Says goodbye",
+				},
+				{
+					Use:    "ghost",
+					Hidden: true,
+				},
+			},
+		}
+	}
+
+	values := func(completions []clibase.Completion) []string {
+		var values []string
+		for _, completion := range completions {
+			values = append(values, completion.Value)
+		}
+		return values
+	}
+
+	for _, tt := range []struct {
+		name  string
+		words string
+		want  []string
+	}{
+		{"Subcommands", "", []string{"greet", "gone"}},
+		{"SubcommandPrefix", "gr", []string{"greet"}},
+		{"Flags", "-", []string{"--verbose"}},
+		{"InheritedFlags", "greet --", []string{"--verbose", "--format", "--name"}},
+		{"Args", "greet ", []string{"alice", "bob"}},
+		{"Alias", "hi --verbose a", []string{"alice"}},
+		{"SecondArg", "greet alice ", nil},
+		{"EnumValue", "greet --format f", []string{"fancy", "formal"}},
+		{"EnumShorthand", "greet -f ", []string{"plain", "fancy", "formal"}},
+		{"EnumEquals", "greet --format=p", []string{"--format=plain"}},
+		{"FlagValueSkipped", "greet --name greet ", []string{"alice", "bob"}},
+		{"StringValue", "greet --name ", nil},
+		{"AfterDash", "greet -- -", nil},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := cmd()
+			got := root.Complete(root.Invoke(), strings.Split(tt.words, " "))
+			require.Equal(t, tt.want, values(got))
+		})
+	}
+
+	t.Run("Descriptions", func(t *testing.T) {
+		t.Parallel()
+		root := cmd()
+		got := root.Complete(root.Invoke(), []string{"g"})
+		require.Equal(t, []clibase.Completion{
+			{Value: "greet", Description: "Greets someone"},
+			{Value: "gone", Description: "Says goodbye"},
+		}, got)
+	})
+}
@@ -0,0 +1,130 @@
+package clistat
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-sysinfo"
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	"tailscale.com/types/ptr"
+
+	sysinfotypes "github.com/elastic/go-sysinfo/types"
+)
+
+const procNetDev = "/proc/net/dev"
+
+// HostLoad returns the one minute load average of the host.
+// Total is the number of cores, so the percentage indicates
+// how saturated the host is.
+// This is not supported on Windows.
+func (s *Statter) HostLoad() (*Result, error) {
+	la, ok := s.hi.(sysinfotypes.LoadAverage)
+	if !ok {
+		return nil, xerrors.New("load average is not supported on this platform")
+	}
+	info, err := la.LoadAverage()
+	if err != nil {
+		return nil, xerrors.Errorf("get load average: %w", err)
+	}
+	return &Result{
+		Unit:   "load",
+		Used:   info.One,
+		Total:  ptr.To(float64(s.nproc)),
+		Prefix: PrefixDefault,
+	}, nil
+}
+
+// ProcessCount returns the number of processes visible to the statter.
+// In a container, this is usually the number of processes in the container.
+func (*Statter) ProcessCount() (*Result, error) {
+	procs, err := sysinfo.Processes()
+	if err != nil {
+		return nil, xerrors.Errorf("list processes: %w", err)
+	}
+	return &Result{
+		Unit:   "processes",
+		Used:   float64(len(procs)),
+		Prefix: PrefixDefault,
+	}, nil
+}
+
+// Network returns the combined receive and transmit throughput of the
+// given network interface in bytes per second. This is calculated by
+// taking two samples of the counters in /proc/net/dev.
+// If iface is empty, all interfaces except loopback are summed.
+// This is only supported on Linux.
+func (s *Statter) Network(p Prefix, iface string) (*Result, error) {
+	b1, err := s.netDevBytes(iface)
+	if err != nil {
+		return nil, xerrors.Errorf("get first network sample: %w", err)
+	}
+	s.wait(s.sampleInterval)
+	b2, err := s.netDevBytes(iface)
+	if err != nil {
+		return nil, xerrors.Errorf("get second network sample: %w", err)
+	}
+	r := &Result{
+		Unit:   "B/s",
+		Prefix: p,
+	}
+	if b2 < b1 {
+		// The counters wrapped or the interface was recreated.
+		b1 = 0
+	}
+	if s.sampleInterval > 0 {
+		r.Used = float64(b2-b1) / s.sampleInterval.Seconds()
+	}
+	return r, nil
+}
+
+// netDevBytes returns the sum of received and transmitted bytes
+// reported by /proc/net/dev for the given interface.
+func (s *Statter) netDevBytes(iface string) (uint64, error) {
+	data, err := afero.ReadFile(s.fs, procNetDev)
+	if err != nil {
+		return 0, xerrors.Errorf("read %s: %w", procNetDev, err)
+	}
+
+	var (
+		total uint64
+		found bool
+	)
+	scn := bufio.NewScanner(bytes.NewReader(data))
+	for scn.Scan() {
+		// Lines look like:
+		//   eth0: 1234 10 0 0 0 0 0 0 5678 20 0 0 0 0 0 0
+		// The first two lines are headers and have no colon.
+		name, counters, ok := strings.Cut(scn.Text(), ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if iface == "" && name == "lo" {
+			continue
+		}
+		if iface != "" && name != iface {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			return 0, xerrors.Errorf("unexpected format for interface %q in %s", name, procNetDev)
+		}
+		rx, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, xerrors.Errorf("parse received bytes for %q: %w", name, err)
+		}
+		tx, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return 0, xerrors.Errorf("parse transmitted bytes for %q: %w", name, err)
+		}
+		total += rx + tx
+		found = true
+	}
+	if iface != "" && !found {
+		return 0, xerrors.Errorf("network interface %q not found", iface)
+	}
+	return total, nil
+}
@@ -1,6 +1,7 @@
 package clistat
 
 import (
+	"runtime"
 	"testing"
 	"time"
 
@@ -91,6 +92,25 @@ func TestStatter(t *testing.T) {
 			assert.NotZero(t, disk.Total)
 			assert.Equal(t, "B", disk.Unit)
 		})
+
+		t.Run("HostLoad", func(t *testing.T) {
+			t.Parallel()
+			if runtime.GOOS == "windows" {
+				t.Skip("load average is not supported on Windows")
+			}
+			load, err := s.HostLoad()
+			require.NoError(t, err)
+			assert.NotZero(t, load.Total)
+			assert.Equal(t, "load", load.Unit)
+		})
+
+		t.Run("ProcessCount", func(t *testing.T) {
+			t.Parallel()
+			procs, err := s.ProcessCount()
+			require.NoError(t, err)
+			assert.NotZero(t, procs.Used)
+			assert.Nil(t, procs.Total)
+		})
 	})
 
 	// Sometimes we do need to "fake" some stuff
@@ -222,6 +242,58 @@ func TestStatter(t *testing.T) {
 	})
 }
 
+func TestStatterNetwork(t *testing.T) {
+	t.Parallel()
+
+	const (
+		header = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed`
+		before = header + `
+    lo:  500000     100    0    0    0     0          0         0   500000     100    0    0    0     0       0          0
+  eth0:    1000      10    0    0    0     0          0         0     2000      20    0    0    0     0       0          0
+  eth1:     100       1    0    0    0     0          0         0      100       1    0    0    0     0       0          0`
+		after = header + `
+    lo:  900000     200    0    0    0     0          0         0   900000     200    0    0    0     0       0          0
+  eth0:    3000      30    0    0    0     0          0         0     4000      40    0    0    0     0       0          0
+  eth1:     600       2    0    0    0     0          0         0      600       2    0    0    0     0       0          0`
+	)
+
+	newStatter := func(t *testing.T) *Statter {
+		fs := afero.NewMemMapFs()
+		mungeFS(t, fs, procNetDev, before)
+		s, err := New(WithFS(fs), WithSampleInterval(time.Second), func(s *Statter) {
+			s.wait = func(time.Duration) {
+				mungeFS(t, fs, procNetDev, after)
+			}
+		})
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("AllInterfaces", func(t *testing.T) {
+		t.Parallel()
+		net, err := newStatter(t).Network(PrefixDefault, "")
+		require.NoError(t, err)
+		// Loopback is excluded.
+		assert.Equal(t, 5000.0, net.Used)
+		assert.Nil(t, net.Total)
+		assert.Equal(t, "B/s", net.Unit)
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		t.Parallel()
+		net, err := newStatter(t).Network(PrefixDefault, "eth1")
+		require.NoError(t, err)
+		assert.Equal(t, 1000.0, net.Used)
+	})
+
+	t.Run("UnknownInterface", func(t *testing.T) {
+		t.Parallel()
+		_, err := newStatter(t).Network(PrefixDefault, "wlan0")
+		require.ErrorContains(t, err, `network interface "wlan0" not found`)
+	})
+}
+
 func TestIsContainerized(t *testing.T) {
 	t.Parallel()
 
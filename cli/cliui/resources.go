@@ -110,6 +110,23 @@ func WorkspaceResources(writer io.Writer, resources []codersdk.WorkspaceResource
 				row = append(row, sshCommand)
 			}
 			tableWriter.AppendRow(row)
+			if !options.HideAgentState {
+				// Display the status of each script beneath the agent.
+				indent := "│"
+				if index == len(resource.Agents)-1 {
+					indent = " "
+				}
+				for scriptIndex, script := range agent.Scripts {
+					scriptPipe := "├"
+					if scriptIndex == len(agent.Scripts)-1 {
+						scriptPipe = "└"
+					}
+					tableWriter.AppendRow(table.Row{
+						fmt.Sprintf("%s  %s─ %s", indent, scriptPipe, script.DisplayName),
+						renderScriptStatus(script),
+					})
+				}
+			}
 		}
 		tableWriter.AppendSeparator()
 	}
@@ -141,6 +158,22 @@ func renderAgentStatus(agent codersdk.WorkspaceAgent) string {
 	}
 }
 
+func renderScriptStatus(script codersdk.WorkspaceAgentScript) string {
+	switch script.Status {
+	case codersdk.WorkspaceAgentScriptStatusRunning:
+		return DefaultStyles.Warn.Render("⦾ running")
+	case codersdk.WorkspaceAgentScriptStatusSucceeded:
+		return DefaultStyles.Keyword.Render("⦿ succeeded")
+	case codersdk.WorkspaceAgentScriptStatusFailed:
+		return DefaultStyles.Error.Render("⦾ failed") + " " +
+			DefaultStyles.Placeholder.Render("[exit "+strconv.Itoa(int(script.ExitCode))+"]")
+	case codersdk.WorkspaceAgentScriptStatusTimedOut:
+		return DefaultStyles.Error.Render("⦾ timed out")
+	default:
+		return DefaultStyles.Placeholder.Render("○ pending")
+	}
+}
+
 func renderAgentVersion(agentVersion, serverVersion string) string {
 	if agentVersion == "" {
 		agentVersion = "(unknown)"
@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/config"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// completionCacheMaxAge is how long cached workspaces and templates are
+	// completed before they're fetched again.
+	completionCacheMaxAge = time.Minute
+	// completionFetchTimeout bounds fetching from the deployment during
+	// completion, after which the stale cache is used so the shell doesn't
+	// hang.
+	completionFetchTimeout = 2 * time.Second
+)
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for coder
+_coder() {
+	local line="${COMP_LINE:0:COMP_POINT}"
+	local -a words
+	read -ra words <<< "$line"
+	if [[ $line == *[[:space:]] ]]; then
+		words+=("")
+	fi
+	# Bash splits words on characters like "=" and ":" and only replaces
+	# the part after them, so strip it from the candidates.
+	local cur="${words[${#words[@]}-1]}"
+	local prefix="${cur%"${COMP_WORDS[COMP_CWORD]}"}"
+	local IFS=$'\n'
+	COMPREPLY=($(coder __complete "${words[@]:1}" 2>/dev/null | cut -f1))
+	COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+}
+complete -o default -F _coder coder
+`,
+	"zsh": `#compdef coder
+# zsh completion for coder
+_coder() {
+	local -a completions
+	local line
+	for line in "${(@f)$(coder __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+		[[ -z $line ]] && continue
+		completions+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+	done
+	if (( ${#completions} )); then
+		_describe coder completions
+	else
+		_files
+	fi
+}
+
+if [ "$funcstack[1]" = "_coder" ]; then
+	_coder "$@"
+else
+	compdef _coder coder
+fi
+`,
+	"fish": `# fish completion for coder
+function __coder_complete
+	set -l words (commandline -opc)
+	set -e words[1]
+	coder __complete $words (commandline -ct) 2>/dev/null
+end
+complete -c coder -f -a '(__coder_complete)'
+`,
+	"powershell": `# PowerShell completion for coder
+Register-ArgumentCompleter -Native -CommandName coder -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+	$words = @($commandAst.CommandElements |
+		Where-Object { $_.Extent.StartOffset -lt $cursorPosition } |
+		Select-Object -Skip 1 |
+		ForEach-Object { $_.ToString() })
+	if ($wordToComplete -eq '') {
+		# Legacy argument passing drops empty arguments to native commands.
+		if ($PSNativeCommandArgumentPassing -in $null, 'Legacy') {
+			$words += '""'
+		} else {
+			$words += ''
+		}
+	}
+	coder __complete @words 2>$null | ForEach-Object {
+		$value, $description = $_ -split "` + "`" + `t", 2
+		if (-not $description) {
+			$description = $value
+		}
+		[System.Management.Automation.CompletionResult]::new($value, $value, 'ParameterValue', $description)
+	}
+}
+`,
+}
+
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+func (*RootCmd) completion() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Output a shell completion script",
+		Long: "Commands, flags, and the names of your workspaces and templates are completed. " +
+			"Workspaces and templates are cached in the config directory so completion stays fast.\n\n" +
+			formatExamples(
+				example{
+					Description: "Enable completion in bash by adding this to ~/.bashrc",
+					Command:     "source <(coder completion bash)",
+				},
+				example{
+					Description: "Enable completion in zsh by adding this to ~/.zshrc after compinit",
+					Command:     "source <(coder completion zsh)",
+				},
+				example{
+					Description: "Enable completion in fish",
+					Command:     "coder completion fish > ~/.config/fish/completions/coder.fish",
+				},
+				example{
+					Description: "Enable completion in PowerShell by adding this to $PROFILE",
+					Command:     "coder completion powershell | Out-String | Invoke-Expression",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+		),
+		CompletionHandler: func(inv *clibase.Invocation) []string {
+			if len(inv.Args) > 0 {
+				return nil
+			}
+			return completionShells
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			script, ok := completionScripts[inv.Args[0]]
+			if !ok {
+				return xerrors.Errorf("unsupported shell %q, must be one of: %s", inv.Args[0], strings.Join(completionShells, ", "))
+			}
+			_, err := fmt.Fprint(inv.Stdout, script)
+			return err
+		},
+	}
+}
+
+// complete is called by the completion scripts with the words on the command
+// line after "coder", and prints a line for each candidate for the last word
+// with its description after a tab.
+func (*RootCmd) complete() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:     "__complete [words...]",
+		Short:   "Print the shell completions for a command line",
+		Hidden:  true,
+		RawArgs: true,
+		Handler: func(inv *clibase.Invocation) error {
+			root := inv.Command.Parent
+			for _, completion := range root.Complete(inv, inv.Args) {
+				description := strings.Join(strings.Fields(completion.Description), " ")
+				_, err := fmt.Fprintf(inv.Stdout, "%s\t%s\n", completion.Value, description)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// addCompletionHandlers completes workspace and template names for commands
+// whose first argument is one.
+func (r *RootCmd) addCompletionHandlers(cmd *clibase.Cmd) {
+	cmd.Walk(func(cmd *clibase.Cmd) {
+		if cmd.CompletionHandler != nil {
+			return
+		}
+		args := strings.Fields(cmd.Use)[1:]
+		if len(args) == 0 {
+			return
+		}
+		arg := strings.TrimPrefix(args[0], "[")
+		switch {
+		case strings.HasPrefix(arg, "<workspace"):
+			cmd.CompletionHandler = r.completeWorkspaces
+		case arg == "<template>" || arg == "template]":
+			cmd.CompletionHandler = r.completeTemplates
+		}
+	})
+}
+
+func (r *RootCmd) completeWorkspaces(inv *clibase.Invocation) []string {
+	if len(inv.Args) > 0 {
+		return nil
+	}
+	workspaces := completionItems(r, inv, config.Root.WorkspacesCache, func(inv *clibase.Invocation, client *codersdk.Client) ([]codersdk.Workspace, error) {
+		res, err := client.Workspaces(inv.Context(), codersdk.WorkspaceFilter{
+			FilterQuery: "owner:me",
+		})
+		return res.Workspaces, err
+	})
+	names := make([]string, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		names = append(names, workspace.Name)
+	}
+	return names
+}
+
+func (r *RootCmd) completeTemplates(inv *clibase.Invocation) []string {
+	if len(inv.Args) > 0 {
+		return nil
+	}
+	templates := completionItems(r, inv, config.Root.TemplatesCache, func(inv *clibase.Invocation, client *codersdk.Client) ([]codersdk.Template, error) {
+		organization, err := CurrentOrganization(inv, client)
+		if err != nil {
+			return nil, err
+		}
+		return client.TemplatesByOrganization(inv.Context(), organization.ID)
+	})
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		names = append(names, template.Name)
+	}
+	return names
+}
+
+// completionItems returns the items cached in the cache file of the current
+// context, fetching them first if the cache is older than
+// completionCacheMaxAge. The stale cache is returned if the deployment can't
+// be reached in time.
+func completionItems[T any](r *RootCmd, inv *clibase.Invocation, cacheFile func(config.Root) config.File, fetch func(*clibase.Invocation, *codersdk.Client) ([]T, error)) []T {
+	clientURL, token, err := r.session()
+	if err != nil {
+		return nil
+	}
+	conf, err := r.contextConfig()
+	if err != nil {
+		return nil
+	}
+	file := cacheFile(conf)
+	list, err := readCache[T](file, clientURL)
+	if err == nil && time.Since(list.UpdatedAt) < completionCacheMaxAge {
+		return list.Items
+	}
+
+	client := new(codersdk.Client)
+	err = r.setClient(client, clientURL)
+	if err != nil {
+		return list.Items
+	}
+	client.SetSessionToken(token)
+	ctx, cancel := context.WithTimeout(inv.Context(), completionFetchTimeout)
+	defer cancel()
+	items, err := fetch(inv.WithContext(ctx), client)
+	if err != nil {
+		return list.Items
+	}
+	_ = writeCache(file, clientURL, items)
+	return items
+}
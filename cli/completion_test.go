@@ -0,0 +1,65 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Script", func(t *testing.T) {
+		t.Parallel()
+		for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+			inv, _ := clitest.New(t, "completion", shell)
+			var stdout bytes.Buffer
+			inv.Stdout = &stdout
+			require.NoError(t, inv.Run())
+			require.Contains(t, stdout.String(), "coder __complete")
+		}
+
+		inv, _ := clitest.New(t, "completion", "tcsh")
+		require.ErrorContains(t, inv.Run(), `unsupported shell "tcsh"`)
+	})
+
+	t.Run("Names", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		complete := func(t *testing.T, words ...string) []string {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+			inv, root := clitest.New(t, append([]string{"__complete"}, words...)...)
+			clitest.SetupConfig(t, client, root)
+			var stdout bytes.Buffer
+			inv.Stdout = &stdout
+			require.NoError(t, inv.WithContext(ctx).Run())
+			var values []string
+			for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+				value, _, _ := strings.Cut(line, "\t")
+				values = append(values, value)
+			}
+			return values
+		}
+
+		require.Equal(t, []string{workspace.Name}, complete(t, "ssh", ""))
+		require.Equal(t, []string{workspace.Name}, complete(t, "schedule", "show", workspace.Name[:1]))
+		require.Equal(t, []string{template.Name}, complete(t, "templates", "edit", ""))
+		require.Contains(t, complete(t, "templates", ""), "edit")
+		require.Contains(t, complete(t, "ssh", "--"), "--stdio")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/kirsle/configdir"
 	"golang.org/x/xerrors"
@@ -11,6 +12,9 @@ import (
 
 const (
 	FlagName = "global-config"
+	// DefaultContext is the name of the context stored in the root of the
+	// configuration directory, which is used unless another is selected.
+	DefaultContext = "default"
 )
 
 // Root represents the configuration directory.
@@ -51,6 +55,62 @@ func (r Root) DotfilesURL() File {
 	return File(filepath.Join(string(r), "dotfilesurl"))
 }
 
+// CachePath is the directory of data cached from the deployment, which is
+// used when it can't be reached and for shell completion.
+func (r Root) CachePath() string {
+	r.mustNotEmpty()
+	return filepath.Join(string(r), "cache")
+}
+
+func (r Root) WorkspacesCache() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(r.CachePath(), "workspaces.json"))
+}
+
+func (r Root) TemplatesCache() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(r.CachePath(), "templates.json"))
+}
+
+// CurrentContext is the name of the context selected with "coder context use".
+func (r Root) CurrentContext() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "context"))
+}
+
+func (r Root) ContextsPath() string {
+	r.mustNotEmpty()
+	return filepath.Join(string(r), "contexts")
+}
+
+// Context returns the configuration directory of the named context, which
+// stores its own URL and session. The default context is the root itself.
+func (r Root) Context(name string) Root {
+	r.mustNotEmpty()
+	if name == "" || name == DefaultContext {
+		return r
+	}
+	return Root(filepath.Join(r.ContextsPath(), name))
+}
+
+// Contexts returns the names of the contexts in the configuration directory
+// in alphabetical order, starting with the default context.
+func (r Root) Contexts() ([]string, error) {
+	r.mustNotEmpty()
+	entries, err := os.ReadDir(r.ContextsPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultContext {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultContext}, names...), nil
+}
+
 func (r Root) PostgresPath() string {
 	r.mustNotEmpty()
 	return filepath.Join(string(r), "postgres")
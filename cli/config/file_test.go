@@ -36,3 +36,28 @@ func TestFile(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestContexts(t *testing.T) {
+	t.Parallel()
+
+	root := config.Root(t.TempDir())
+	require.Equal(t, root, root.Context(config.DefaultContext))
+
+	names, err := root.Contexts()
+	require.NoError(t, err)
+	require.Equal(t, []string{config.DefaultContext}, names)
+
+	err = root.Context("staging").Session().Write("staging")
+	require.NoError(t, err)
+	err = root.Context("prod").Session().Write("prod")
+	require.NoError(t, err)
+	names, err = root.Contexts()
+	require.NoError(t, err)
+	require.Equal(t, []string{config.DefaultContext, "prod", "staging"}, names)
+
+	session, err := root.Context("prod").Session().Read()
+	require.NoError(t, err)
+	require.Equal(t, "prod", session)
+	_, err = root.Session().Read()
+	require.Error(t, err)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/coder/coder/cli/clibase"
 	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/cli/config"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -213,6 +214,10 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 				Description: "You can use --dry-run (or -n) to see the changes that would be made",
 				Command:     "coder config-ssh --dry-run",
 			},
+			example{
+				Description: "Each context has its own hosts, so you can add the workspaces of another deployment with --context",
+				Command:     "coder config-ssh --context prod",
+			},
 		),
 		Middleware: clibase.Chain(
 			clibase.RequireNArgs(0),
@@ -242,6 +247,10 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 				return xerrors.Errorf("escape coder binary for ssh failed: %w", err)
 			}
 
+			contextName, err := r.currentContext()
+			if err != nil {
+				return err
+			}
 			root := r.createConfig()
 			escapedGlobalConfig, err := sshConfigExecEscape(string(root), forceUnixSeparators)
 			if err != nil {
@@ -270,7 +279,7 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 			// Parse the previous configuration only if config-ssh
 			// has been run previously.
 			var lastConfig *sshConfigOptions
-			section, ok, err := sshConfigGetCoderSection(configRaw)
+			section, ok, err := sshConfigGetCoderSection(configRaw, contextName)
 			if err != nil {
 				return err
 			}
@@ -324,7 +333,7 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 			configModified := configRaw
 
 			buf := &bytes.Buffer{}
-			before, _, after, err := sshConfigSplitOnCoderSection(configModified)
+			before, _, after, err := sshConfigSplitOnCoderSection(configModified, contextName)
 			if err != nil {
 				return err
 			}
@@ -334,7 +343,7 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 			// Write comment and store the provided options as part
 			// of the config for future (re)use.
 			newline := len(before) > 0
-			sshConfigWriteSectionHeader(buf, newline, sshConfigOpts)
+			sshConfigWriteSectionHeader(buf, newline, contextName, sshConfigOpts)
 
 			workspaceConfigs, err := recvWorkspaceConfigs()
 			if err != nil {
@@ -357,6 +366,9 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 			if sshConfigOpts.userHostPrefix != "" {
 				// Override with user flag.
 				coderdConfig.HostnamePrefix = sshConfigOpts.userHostPrefix
+			} else if contextName != config.DefaultContext {
+				// Keep the hosts of contexts apart, e.g. coder.prod.workspace.
+				coderdConfig.HostnamePrefix += contextName + "."
 			}
 
 			// Ensure stable sorting of output.
@@ -385,9 +397,11 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 						if sshConfigOpts.waitEnum != "auto" {
 							flags += " --wait=" + sshConfigOpts.waitEnum
 						}
+						// The context is pinned so the host keeps connecting
+						// to the same deployment after switching contexts.
 						defaultOptions = append(defaultOptions, fmt.Sprintf(
-							"ProxyCommand %s --global-config %s ssh --stdio%s %s",
-							escapedCoderBinary, escapedGlobalConfig, flags, workspaceHostname,
+							"ProxyCommand %s --global-config %s --context %s ssh --stdio%s %s",
+							escapedCoderBinary, escapedGlobalConfig, contextName, flags, workspaceHostname,
 						))
 					}
 
@@ -430,7 +444,7 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 				}
 			}
 
-			sshConfigWriteSectionEnd(buf)
+			sshConfigWriteSectionEnd(buf, contextName)
 
 			// Write the remainder of the users config file to buf.
 			_, _ = buf.Write(after)
@@ -561,12 +575,13 @@ func (r *RootCmd) configSSH() *clibase.Cmd {
 }
 
 //nolint:revive
-func sshConfigWriteSectionHeader(w io.Writer, addNewline bool, o sshConfigOptions) {
+func sshConfigWriteSectionHeader(w io.Writer, addNewline bool, contextName string, o sshConfigOptions) {
 	nl := "\n"
 	if !addNewline {
 		nl = ""
 	}
-	_, _ = fmt.Fprint(w, nl+sshStartToken+"\n")
+	startToken, _ := sshConfigSectionTokens(contextName)
+	_, _ = fmt.Fprint(w, nl+startToken+"\n")
 	_, _ = fmt.Fprint(w, sshConfigSectionHeader)
 	_, _ = fmt.Fprint(w, sshConfigDocsHeader)
 
@@ -588,8 +603,20 @@ func sshConfigWriteSectionHeader(w io.Writer, addNewline bool, o sshConfigOption
 	_, _ = fmt.Fprint(w, "#\n")
 }
 
-func sshConfigWriteSectionEnd(w io.Writer) {
-	_, _ = fmt.Fprint(w, sshEndToken+"\n")
+func sshConfigWriteSectionEnd(w io.Writer, contextName string) {
+	_, endToken := sshConfigSectionTokens(contextName)
+	_, _ = fmt.Fprint(w, endToken+"\n")
+}
+
+// sshConfigSectionTokens returns the tokens around the section of the CLI
+// context. Each context has its own section, so the workspaces of several
+// deployments can be configured at once.
+func sshConfigSectionTokens(contextName string) (startToken, endToken string) {
+	if contextName == "" || contextName == config.DefaultContext {
+		return sshStartToken, sshEndToken
+	}
+	return fmt.Sprintf("# ------------START-CODER-%s-----------", contextName),
+		fmt.Sprintf("# ------------END-CODER-%s------------", contextName)
 }
 
 func sshConfigParseLastOptions(r io.Reader) (o sshConfigOptions) {
@@ -622,9 +649,9 @@ func sshConfigParseLastOptions(r io.Reader) (o sshConfigOptions) {
 }
 
 // sshConfigGetCoderSection is a helper function that only returns the coder
-// section of the SSH config and a boolean if it exists.
-func sshConfigGetCoderSection(data []byte) (section []byte, ok bool, err error) {
-	_, section, _, err = sshConfigSplitOnCoderSection(data)
+// section of the context in the SSH config and a boolean if it exists.
+func sshConfigGetCoderSection(data []byte, contextName string) (section []byte, ok bool, err error) {
+	_, section, _, err = sshConfigSplitOnCoderSection(data, contextName)
 	if err != nil {
 		return nil, false, err
 	}
@@ -633,17 +660,18 @@ func sshConfigGetCoderSection(data []byte) (section []byte, ok bool, err error)
 }
 
 // sshConfigSplitOnCoderSection splits the SSH config into 3 sections.
-// All lines before sshStartToken, the coder section, and all lines after
-// sshEndToken.
-func sshConfigSplitOnCoderSection(data []byte) (before, section []byte, after []byte, err error) {
-	startCount := bytes.Count(data, []byte(sshStartToken))
-	endCount := bytes.Count(data, []byte(sshEndToken))
+// All lines before the start token of the context, the coder section, and
+// all lines after the end token.
+func sshConfigSplitOnCoderSection(data []byte, contextName string) (before, section []byte, after []byte, err error) {
+	startToken, endToken := sshConfigSectionTokens(contextName)
+	startCount := bytes.Count(data, []byte(startToken))
+	endCount := bytes.Count(data, []byte(endToken))
 	if startCount > 1 || endCount > 1 {
 		return nil, nil, nil, xerrors.New("Malformed config: ssh config has multiple coder sections, please remove all but one")
 	}
 
-	startIndex := bytes.Index(data, []byte(sshStartToken))
-	endIndex := bytes.Index(data, []byte(sshEndToken))
+	startIndex := bytes.Index(data, []byte(startToken))
+	endIndex := bytes.Index(data, []byte(endToken))
 	if startIndex == -1 && endIndex != -1 {
 		return nil, nil, nil, xerrors.New("Malformed config: ssh config has end header, but missing start header")
 	}
@@ -660,7 +688,7 @@ func sshConfigSplitOnCoderSection(data []byte) (before, section []byte, after []
 		if start > 0 {
 			start--
 		}
-		end := endIndex + len(sshEndToken)
+		end := endIndex + len(endToken)
 		if end < len(data) {
 			end++
 		}
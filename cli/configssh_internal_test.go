@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/config"
 )
 
 func init() {
@@ -122,7 +124,7 @@ func Test_sshConfigSplitOnCoderSection(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			before, section, after, err := sshConfigSplitOnCoderSection([]byte(tc.Input))
+			before, section, after, err := sshConfigSplitOnCoderSection([]byte(tc.Input), config.DefaultContext)
 			if tc.Err {
 				require.Error(t, err)
 				return
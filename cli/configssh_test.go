@@ -23,6 +23,7 @@ import (
 
 	"github.com/coder/coder/agent"
 	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/cli/config"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -754,6 +755,43 @@ func TestConfigSSH_Hostnames(t *testing.T) {
 	}
 }
 
+func TestConfigSSH_Contexts(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionApplyWithAgent(authToken),
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	root := config.Root(t.TempDir())
+	clitest.SetupConfig(t, client, root)
+	clitest.SetupConfig(t, client, root.Context("prod"))
+	sshConfigFile := sshConfigFileName(t)
+
+	// Each context has its own section, so configuring one keeps the hosts
+	// of the other.
+	for _, contextName := range []string{"default", "prod", "default"} {
+		inv, _ := clitest.New(t, "config-ssh", "--ssh-config-file", sshConfigFile, "--yes", "--global-config", string(root), "--context", contextName)
+		err := inv.Run()
+		require.NoError(t, err)
+	}
+
+	hosts := sshConfigFileParseHosts(t, sshConfigFile)
+	require.Subset(t, hosts, []string{"coder." + workspace.Name, "coder.prod." + workspace.Name})
+	sshConfig := sshConfigFileRead(t, sshConfigFile)
+	require.Contains(t, sshConfig, "# ------------START-CODER-prod-----------")
+	require.Contains(t, sshConfig, "--context prod ssh --stdio "+workspace.Name)
+	require.Contains(t, sshConfig, "--context default ssh --stdio "+workspace.Name)
+}
+
 // sshConfigFileParseHosts reads a file in the format of .ssh/config and extracts
 // the hostnames that are listed in "Host" directives.
 func sshConfigFileParseHosts(t *testing.T, name string) []string {
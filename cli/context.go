@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/cli/config"
+	"github.com/coder/coder/coderd/httpapi"
+)
+
+func (r *RootCmd) contexts() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "context",
+		Short: "Manage the deployments the CLI is logged in to",
+		Long: "Each context stores the URL and session of a deployment, so you can stay logged in to several. " +
+			"Commands use the context selected with \"coder context use\", unless --context or " + envContext + " is set.\n\n" +
+			formatExamples(
+				example{
+					Description: "Log in to a deployment in the prod context",
+					Command:     "coder login --context prod https://prod.coder.example.com",
+				},
+				example{
+					Description: "Use the prod context by default",
+					Command:     "coder context use prod",
+				},
+				example{
+					Description: "Run a single command in the staging context",
+					Command:     "coder list --context staging",
+				},
+			),
+		Aliases: []string{"contexts"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.contextDelete(),
+			r.contextList(),
+			r.contextUse(),
+		},
+	}
+}
+
+type contextListRow struct {
+	Name    string `json:"name" table:"name,default_sort"`
+	URL     string `json:"url" table:"url"`
+	Current bool   `json:"current" table:"current"`
+}
+
+func (r *RootCmd) contextList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]contextListRow{}, nil),
+		cliui.JSONFormat(),
+	)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List contexts",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			current, err := r.currentContext()
+			if err != nil {
+				return err
+			}
+			root := r.createConfig()
+			names, err := root.Contexts()
+			if err != nil {
+				return xerrors.Errorf("list contexts: %w", err)
+			}
+			rows := make([]contextListRow, 0, len(names))
+			for _, name := range names {
+				rawURL, err := root.Context(name).URL().Read()
+				if err != nil && !os.IsNotExist(err) {
+					return xerrors.Errorf("read URL of context %q: %w", name, err)
+				}
+				rows = append(rows, contextListRow{
+					Name:    name,
+					URL:     strings.TrimSpace(rawURL),
+					Current: name == current,
+				})
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) contextUse() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "use <context>",
+		Short: "Select the context commands use by default",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+		),
+		CompletionHandler: r.completeContexts,
+		Handler: func(inv *clibase.Invocation) error {
+			name := inv.Args[0]
+			root := r.createConfig()
+			if name == config.DefaultContext {
+				err := root.CurrentContext().Delete()
+				if err != nil && !os.IsNotExist(err) {
+					return xerrors.Errorf("select context: %w", err)
+				}
+			} else {
+				err := r.existingContext(name)
+				if err != nil {
+					return err
+				}
+				err = root.CurrentContext().Write(name)
+				if err != nil {
+					return xerrors.Errorf("select context: %w", err)
+				}
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Switched to context %s.\n", cliui.DefaultStyles.Keyword.Render(name))
+			return nil
+		},
+	}
+}
+
+func (r *RootCmd) contextDelete() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "delete <context>",
+		Short: "Delete a context and the session stored in it",
+		Long:  "The session isn't revoked, log out of the context with \"coder logout --context <context>\" first to revoke it.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+		),
+		CompletionHandler: r.completeContexts,
+		Handler: func(inv *clibase.Invocation) error {
+			name := inv.Args[0]
+			if name == config.DefaultContext {
+				return xerrors.New("the default context can't be deleted, log out of it with \"coder logout --context default\"")
+			}
+			err := r.existingContext(name)
+			if err != nil {
+				return err
+			}
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete context %s?", cliui.DefaultStyles.Keyword.Render(name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			root := r.createConfig()
+			err = os.RemoveAll(string(root.Context(name)))
+			if err != nil {
+				return xerrors.Errorf("delete context: %w", err)
+			}
+			current, err := root.CurrentContext().Read()
+			if err == nil && strings.TrimSpace(current) == name {
+				err = root.CurrentContext().Delete()
+				if err != nil {
+					return xerrors.Errorf("unselect context: %w", err)
+				}
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted context %s.\n", cliui.DefaultStyles.Keyword.Render(name))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
+
+// existingContext returns an error if the named context is invalid or hasn't
+// been logged in to.
+func (r *RootCmd) existingContext(name string) error {
+	err := httpapi.NameValid(name)
+	if err != nil {
+		return xerrors.Errorf("invalid context name %q: %w", name, err)
+	}
+	_, err = os.Stat(string(r.createConfig().Context(name)))
+	if os.IsNotExist(err) {
+		return xerrors.Errorf("context %q doesn't exist, create it with \"coder login --context %s <url>\"", name, name)
+	}
+	return err
+}
+
+func (r *RootCmd) completeContexts(inv *clibase.Invocation) []string {
+	if len(inv.Args) > 0 {
+		return nil
+	}
+	names, err := r.createConfig().Contexts()
+	if err != nil {
+		return nil
+	}
+	return names
+}
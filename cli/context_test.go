@@ -0,0 +1,75 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/cli/config"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestContext(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	admin := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+	root := config.Root(t.TempDir())
+	clitest.SetupConfig(t, client, root)
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		inv, _ := clitest.New(t, append(args, "--global-config", string(root))...)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+		return stdout.String()
+	}
+	whoami := func(t *testing.T, args ...string) string {
+		t.Helper()
+		var user codersdk.User
+		out := run(t, append([]string{"users", "show", "me", "-o", "json"}, args...)...)
+		require.NoError(t, json.Unmarshal([]byte(out), &user))
+		return user.Username
+	}
+
+	// Log in to the deployment as another user in a second context, which
+	// keeps using the default context until it's selected.
+	run(t, "login", "--context", "member", client.URL.String(), "--token", member.SessionToken())
+	require.Equal(t, "testuser", whoami(t))
+	require.NotEqual(t, "testuser", whoami(t, "--context", "member"))
+
+	run(t, "context", "use", "member")
+	require.NotEqual(t, "testuser", whoami(t))
+	require.Equal(t, "testuser", whoami(t, "--context", "default"))
+
+	var contexts []map[string]any
+	out := run(t, "context", "ls", "-o", "json")
+	require.NoError(t, json.Unmarshal([]byte(out), &contexts))
+	require.Equal(t, []map[string]any{
+		{"name": "default", "url": client.URL.String(), "current": false},
+		{"name": "member", "url": client.URL.String(), "current": true},
+	}, contexts)
+
+	// Deleting the current context goes back to the default one.
+	run(t, "context", "rm", "member", "--yes")
+	require.Equal(t, "testuser", whoami(t))
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		inv, _ := clitest.New(t, "context", "use", "missing", "--global-config", string(root))
+		err := inv.Run()
+		require.ErrorContains(t, err, "doesn't exist")
+
+		inv, _ = clitest.New(t, "users", "show", "me", "--context", "../escape", "--global-config", string(root))
+		err = inv.Run()
+		require.ErrorContains(t, err, "invalid context name")
+	})
+}
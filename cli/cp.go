@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/agent/agentfiles"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) cp() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files and directories to or from a workspace",
+		Long: "Exactly one of source or destination must refer to a workspace " +
+			"as <workspace>[.<agent>]:<path>. Relative workspace paths are resolved " +
+			"from the home directory of the workspace user. Directories are copied " +
+			"recursively.\n\n" + formatExamples(
+			example{
+				Description: "Upload a file to the home directory of a workspace",
+				Command:     "coder cp ./config.yaml my-workspace:config.yaml",
+			},
+			example{
+				Description: "Download a directory from a workspace",
+				Command:     "coder cp my-workspace:/var/log/app ./logs",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			src := parseCopyTarget(inv.Args[0])
+			dst := parseCopyTarget(inv.Args[1])
+			if src.workspace != "" && dst.workspace != "" {
+				return xerrors.New("copying between workspaces is not supported")
+			}
+			if src.workspace == "" && dst.workspace == "" {
+				return xerrors.New("source or destination must refer to a workspace as <workspace>:<path>")
+			}
+			remote := src
+			if dst.workspace != "" {
+				remote = dst
+			}
+
+			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, remote.workspace)
+			if err != nil {
+				return err
+			}
+			err = cliui.Agent(ctx, inv.Stderr, cliui.AgentOptions{
+				WorkspaceName: workspace.Name,
+				Fetch: func(ctx context.Context) (codersdk.WorkspaceAgent, error) {
+					return client.WorkspaceAgent(ctx, workspaceAgent.ID)
+				},
+				Wait: false,
+			})
+			if err != nil && !xerrors.Is(err, cliui.AgentStartError) {
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			logger, ok := LoggerFromContext(ctx)
+			if !ok {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr))
+			}
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger: logger,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial workspace agent: %w", err)
+			}
+			defer conn.Close()
+
+			if dst.workspace != "" {
+				return uploadFiles(ctx, conn, src.path, dst.path)
+			}
+			return downloadFiles(ctx, conn, src.path, dst.path)
+		},
+	}
+	return cmd
+}
+
+// copyTarget is a source or destination of "coder cp". The workspace is
+// empty for local paths.
+type copyTarget struct {
+	workspace string
+	path      string
+}
+
+// parseCopyTarget splits a "<workspace>:<path>" argument. Arguments
+// without a workspace, including Windows paths such as "C:\foo", are
+// treated as local paths.
+func parseCopyTarget(arg string) copyTarget {
+	workspace, remotePath, ok := strings.Cut(arg, ":")
+	if !ok || len(workspace) <= 1 || strings.ContainsAny(workspace, `/\`) {
+		return copyTarget{path: arg}
+	}
+	if remotePath == "" {
+		// Like scp, an empty path refers to the home directory.
+		remotePath = "."
+	}
+	return copyTarget{workspace: workspace, path: remotePath}
+}
+
+func uploadFiles(ctx context.Context, conn *codersdk.WorkspaceAgentConn, src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	// Copy into the destination if it is an existing directory.
+	existing, err := conn.ListFiles(ctx, dst)
+	if err != nil && !isNotFound(err) {
+		return xerrors.Errorf("stat %s: %w", dst, err)
+	}
+	if err == nil && existing.File.IsDir {
+		dst = path.Join(existing.File.Path, filepath.Base(src))
+	}
+
+	if !info.IsDir() {
+		file, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = conn.WriteFile(ctx, dst, info.Mode(), file)
+		if err != nil {
+			return xerrors.Errorf("write %s: %w", dst, err)
+		}
+		return nil
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(agentfiles.Tar(afero.NewOsFs(), writer, src))
+	}()
+	defer reader.Close()
+	_, err = conn.UploadTar(ctx, dst, reader)
+	if err != nil {
+		return xerrors.Errorf("upload %s: %w", dst, err)
+	}
+	return nil
+}
+
+func downloadFiles(ctx context.Context, conn *codersdk.WorkspaceAgentConn, src, dst string) error {
+	remote, err := conn.ListFiles(ctx, src)
+	if err != nil {
+		return xerrors.Errorf("stat %s: %w", src, err)
+	}
+	// Copy into the destination if it is an existing directory.
+	info, err := os.Stat(dst)
+	existingDir := err == nil && info.IsDir()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if existingDir && remote.File.IsDir {
+		dst = filepath.Join(dst, remote.File.Name)
+	}
+
+	archive, err := conn.DownloadTar(ctx, src)
+	if err != nil {
+		return xerrors.Errorf("download %s: %w", src, err)
+	}
+	defer archive.Close()
+
+	if remote.File.IsDir || existingDir {
+		// Archives of files contain a single entry named after the file,
+		// so extracting it into a directory copies the file into it.
+		return agentfiles.Untar(afero.NewOsFs(), dst, archive)
+	}
+
+	// The destination is a new file name, extract the single entry to a
+	// temporary directory and move it into place.
+	tmp, err := os.MkdirTemp(filepath.Dir(dst), ".coder-cp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	err = agentfiles.Untar(afero.NewOsFs(), tmp, archive)
+	if err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(tmp, remote.File.Name), dst)
+}
+
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,91 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	run := func(t *testing.T, args ...string) error {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, append([]string{"cp"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		return inv.WithContext(ctx).Run()
+	}
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+		local := t.TempDir()
+		remote := t.TempDir()
+		src := filepath.Join(local, "hello.txt")
+		require.NoError(t, os.WriteFile(src, []byte("hello"), 0o600))
+
+		// Upload into an existing directory keeps the file name.
+		require.NoError(t, run(t, src, workspace.Name+":"+remote))
+		data, err := os.ReadFile(filepath.Join(remote, "hello.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+
+		// Download to a new file name.
+		dst := filepath.Join(local, "downloaded.txt")
+		require.NoError(t, run(t, workspace.Name+":"+filepath.Join(remote, "hello.txt"), dst))
+		data, err = os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+		local := t.TempDir()
+		remote := t.TempDir()
+		src := filepath.Join(local, "project")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "main.go"), []byte("package main"), 0o600))
+
+		require.NoError(t, run(t, src, workspace.Name+":"+filepath.Join(remote, "copy")))
+		data, err := os.ReadFile(filepath.Join(remote, "copy", "sub", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(data))
+
+		// Download into an existing directory keeps the directory name.
+		dst := t.TempDir()
+		require.NoError(t, run(t, workspace.Name+":"+filepath.Join(remote, "copy"), dst))
+		data, err = os.ReadFile(filepath.Join(dst, "copy", "sub", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(data))
+	})
+
+	t.Run("NoWorkspace", func(t *testing.T) {
+		t.Parallel()
+		err := run(t, "a.txt", "b.txt")
+		require.ErrorContains(t, err, "must refer to a workspace")
+	})
+}
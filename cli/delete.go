@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,17 +12,40 @@ import (
 
 // nolint
 func (r *RootCmd) deleteWorkspace() *clibase.Cmd {
-	var orphan bool
+	var (
+		orphan bool
+		bulk   bulkWorkspaces
+	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
 		Use:         "delete <workspace>",
 		Short:       "Delete a workspace",
+		Long: formatExamples(
+			example{
+				Description: "Delete all workspaces of a user",
+				Command:     "coder delete --query 'owner:alice'",
+			},
+		),
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			bulk.requireArgs(),
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
+			if bulk.query != "" {
+				return bulk.run(inv, client, bulkAction{
+					Verb:           "Delete",
+					Done:           "Deleted",
+					ConfirmDefault: cliui.ConfirmNo,
+					Build: func(ctx context.Context, workspace codersdk.Workspace) (codersdk.WorkspaceBuild, error) {
+						return client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+							Transition: codersdk.WorkspaceTransitionDelete,
+							Orphan:     orphan,
+						})
+					},
+				})
+			}
+
 			_, err := cliui.Prompt(inv, cliui.PromptOptions{
 				Text:      "Confirm delete workspace?",
 				IsConfirm: true,
@@ -55,14 +79,14 @@ func (r *RootCmd) deleteWorkspace() *clibase.Cmd {
 			return nil
 		},
 	}
-	cmd.Options = clibase.OptionSet{
-		{
+	cmd.Options = append(bulk.options(),
+		clibase.Option{
 			Flag:        "orphan",
 			Description: "Delete a workspace without deleting its resources. This can delete a workspace in a broken state, but may also lead to unaccounted cloud resources.",
 
 			Value: clibase.BoolOf(&orphan),
 		},
 		cliui.SkipPromptOption(),
-	}
+	)
 	return cmd
 }
@@ -0,0 +1,405 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+// execExitCodeError is the exit code used when a command couldn't be run
+// in a workspace, the same as OpenSSH.
+const execExitCodeError = 255
+
+// exitError makes the CLI exit with the code instead of 1, without printing
+// an error.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// execResult is the result of running a command in a workspace.
+type execResult struct {
+	Workspace string `json:"workspace"`
+	Agent     string `json:"agent"`
+	ExitCode  int    `json:"exit_code"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Error     string `json:"error,omitempty"`
+}
+
+// execTarget is a workspace agent to run a command in.
+type execTarget struct {
+	name      string
+	workspace codersdk.Workspace
+	agent     codersdk.WorkspaceAgent
+	err       error
+}
+
+func (r *RootCmd) exec() *clibase.Cmd {
+	var (
+		searchQuery  string
+		agentName    string
+		parallel     int64
+		timeout      time.Duration
+		outputFormat string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "exec [<workspace>[.<agent>]] -- <command> [args...]",
+		Short:       "Run a command in one or many workspaces",
+		Long: "The arguments are passed to the command unchanged, run a shell explicitly to use " +
+			"pipes or variables. Stdin isn't forwarded. With --search, the output of each " +
+			"workspace is prefixed with its name. The exit code is the one of the command, " +
+			"or the highest one across workspaces, and 255 if the command couldn't be run.\n\n" + formatExamples(
+			example{
+				Description: "Run a command in a workspace",
+				Command:     "coder exec my-workspace -- git -C project status",
+			},
+			example{
+				Description: "Use a shell for pipes and variables",
+				Command:     "coder exec my-workspace -- sh -c 'df -h $HOME | tail -1'",
+			},
+			example{
+				Description: "Run a command in all running workspaces of a template, 10 at a time",
+				Command:     "coder exec --search 'template:docker status:running' --parallel 10 -- uptime",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(1, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			if parallel < 1 {
+				return xerrors.New("--parallel must be at least 1")
+			}
+
+			var (
+				targets []execTarget
+				args    = inv.Args
+			)
+			if searchQuery == "" {
+				if len(args) < 2 {
+					return xerrors.New("a workspace and a command must be specified")
+				}
+				me, err := client.User(ctx, codersdk.Me)
+				if err != nil {
+					return err
+				}
+				workspace, agent, err := getWorkspaceAndAgent(ctx, inv, client, me.ID.String(), args[0])
+				if err != nil {
+					return err
+				}
+				err = cliui.Agent(ctx, inv.Stderr, cliui.AgentOptions{
+					WorkspaceName: workspace.Name,
+					Fetch: func(ctx context.Context) (codersdk.WorkspaceAgent, error) {
+						return client.WorkspaceAgent(ctx, agent.ID)
+					},
+				})
+				if err != nil && !xerrors.Is(err, cliui.AgentStartError) {
+					return xerrors.Errorf("await agent: %w", err)
+				}
+				targets = append(targets, execTarget{name: workspace.Name, workspace: workspace, agent: agent})
+				args = args[1:]
+			} else {
+				res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: searchQuery})
+				if err != nil {
+					return xerrors.Errorf("search workspaces: %w", err)
+				}
+				if len(res.Workspaces) == 0 {
+					cliui.Infof(inv.Stderr, "No workspaces match %q.\n", searchQuery)
+					return nil
+				}
+				for _, workspace := range res.Workspaces {
+					targets = append(targets, execTargetFromWorkspace(workspace, agentName))
+				}
+			}
+
+			var (
+				logger     = slog.Make() // empty logger
+				mu         sync.Mutex
+				prefix     = searchQuery != ""
+				jsonOutput = outputFormat == "json"
+			)
+			if r.verbose {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			results := make([]execResult, len(targets))
+			var eg errgroup.Group
+			eg.SetLimit(int(parallel))
+			for i, target := range targets {
+				i, target := i, target
+				eg.Go(func() error {
+					var (
+						stdout, stderr       bytes.Buffer
+						stdoutW, stderrW     io.Writer = &stdout, &stderr
+						stdoutPre, stderrPre *prefixWriter
+					)
+					if !jsonOutput {
+						stdoutW, stderrW = inv.Stdout, inv.Stderr
+						if prefix {
+							stdoutPre = &prefixWriter{mu: &mu, w: inv.Stdout, prefix: target.name + ": "}
+							stderrPre = &prefixWriter{mu: &mu, w: inv.Stderr, prefix: target.name + ": "}
+							stdoutW, stderrW = stdoutPre, stderrPre
+						}
+					}
+
+					result := execResult{
+						Workspace: target.name,
+						Agent:     target.agent.Name,
+					}
+					err := target.err
+					if err == nil {
+						ctx := ctx
+						if timeout > 0 {
+							var cancel context.CancelFunc
+							ctx, cancel = context.WithTimeout(ctx, timeout)
+							defer cancel()
+						}
+						result.ExitCode, err = execCommand(ctx, client, logger, r.disableDirect, target.agent, args, stdoutW, stderrW)
+						if err != nil && xerrors.Is(ctx.Err(), context.DeadlineExceeded) {
+							err = xerrors.Errorf("timed out after %s", timeout)
+						}
+					}
+					if stdoutPre != nil {
+						stdoutPre.Flush()
+						stderrPre.Flush()
+					}
+					if err != nil {
+						result.ExitCode = execExitCodeError
+						result.Error = err.Error()
+						if !jsonOutput {
+							mu.Lock()
+							if prefix {
+								cliui.Errorf(inv.Stderr, "%s: %s\n", target.name, err)
+							} else {
+								cliui.Errorf(inv.Stderr, "%s\n", err)
+							}
+							mu.Unlock()
+						}
+					}
+					result.Stdout = stdout.String()
+					result.Stderr = stderr.String()
+					results[i] = result
+					return nil
+				})
+			}
+			_ = eg.Wait()
+
+			code := 0
+			failed := 0
+			for _, result := range results {
+				if result.ExitCode != 0 {
+					failed++
+				}
+				if result.ExitCode > code {
+					code = result.ExitCode
+				}
+			}
+			if jsonOutput {
+				out, err := cliui.JSONFormat().Format(ctx, results)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(inv.Stdout, out)
+			} else if prefix {
+				_, _ = fmt.Fprintf(inv.Stderr, "\nRan in %d workspace(s), %d failed.\n", len(results), failed)
+			}
+			if code != 0 {
+				return &exitError{code: code}
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "search",
+			Description: "Run the command in all workspaces matching a query, like the one of coder list.",
+			Value:       clibase.StringOf(&searchQuery),
+		},
+		{
+			Flag:        "agent",
+			Description: "The agent to run the command in when using --search. Required for workspaces with several agents.",
+			Value:       clibase.StringOf(&agentName),
+		},
+		{
+			Flag:        "parallel",
+			Description: "The number of workspaces to run the command in at the same time.",
+			Default:     "4",
+			Value:       clibase.Int64Of(&parallel),
+		},
+		{
+			Flag:        "timeout",
+			Description: "Stop the command if it runs longer than this in a workspace. Zero means no timeout.",
+			Default:     "0s",
+			Value:       clibase.DurationOf(&timeout),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "Output format. With json, the output and exit code of each workspace is printed once all have finished. Available formats: text, json.",
+			Default:       "text",
+			Value:         clibase.EnumOf(&outputFormat, "text", "json"),
+		},
+	}
+	return cmd
+}
+
+// execTargetFromWorkspace finds the agent to run a command in. Workspaces
+// that aren't running are returned with an error, so they're reported as
+// failed.
+func execTargetFromWorkspace(workspace codersdk.Workspace, agentName string) execTarget {
+	target := execTarget{
+		name:      workspace.OwnerName + "/" + workspace.Name,
+		workspace: workspace,
+	}
+	if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart ||
+		workspace.LatestBuild.Job.Status != codersdk.ProvisionerJobSucceeded {
+		target.err = xerrors.Errorf("workspace is not running")
+		return target
+	}
+
+	var agents []codersdk.WorkspaceAgent
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			if agentName == "" || agent.Name == agentName {
+				agents = append(agents, agent)
+			}
+		}
+	}
+	switch {
+	case len(agents) == 0 && agentName != "":
+		target.err = xerrors.Errorf("agent %q not found", agentName)
+	case len(agents) == 0:
+		target.err = xerrors.New("workspace has no agents")
+	case len(agents) > 1:
+		target.err = xerrors.New("workspace has several agents, specify one with --agent")
+	case agents[0].Status != codersdk.WorkspaceAgentConnected:
+		target.agent = agents[0]
+		target.err = xerrors.Errorf("agent is %s", agents[0].Status)
+	default:
+		target.agent = agents[0]
+	}
+	return target
+}
+
+// execCommand runs a command in a workspace agent over SSH and returns its
+// exit code.
+func execCommand(ctx context.Context, client *codersdk.Client, logger slog.Logger, disableDirect bool, agent codersdk.WorkspaceAgent, args []string, stdout, stderr io.Writer) (int, error) {
+	conn, err := client.DialWorkspaceAgent(ctx, agent.ID, &codersdk.DialWorkspaceAgentOptions{
+		Logger:         logger,
+		BlockEndpoints: disableDirect,
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("dial agent: %w", err)
+	}
+	defer conn.Close()
+	if !conn.AwaitReachable(ctx) {
+		return 0, xerrors.Errorf("agent is unreachable: %w", ctx.Err())
+	}
+
+	sshClient, err := conn.SSHClient(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("ssh client: %w", err)
+	}
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return 0, xerrors.Errorf("ssh session: %w", err)
+	}
+	defer session.Close()
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	// The agent runs the command with the shell of the user, so the
+	// arguments are quoted for it.
+	command := shellquote.Join(args...)
+	if agent.OperatingSystem == "windows" {
+		command = strings.Join(args, " ")
+	}
+	err = session.Start(command)
+	if err != nil {
+		return 0, xerrors.Errorf("start command: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+	select {
+	case <-ctx.Done():
+		_ = session.Close()
+		return 0, ctx.Err()
+	case err = <-done:
+	}
+
+	var exitErr *gossh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), nil
+	}
+	var exitMissingErr *gossh.ExitMissingError
+	if errors.As(err, &exitMissingErr) {
+		return 0, xerrors.New("SSH connection ended unexpectedly")
+	}
+	if err != nil {
+		return 0, xerrors.Errorf("run command: %w", err)
+	}
+	return 0, nil
+}
+
+// prefixWriter prefixes each line written to it. Whole lines are written
+// at once, so the output of concurrent commands doesn't interleave.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+		p.writeLine(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// Flush writes the last line if it doesn't end with a newline.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) == 0 {
+		return
+	}
+	p.writeLine(append(p.buf, '\n'))
+	p.buf = nil
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+}
@@ -0,0 +1,94 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The commands use sh")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent"),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	run := func(t *testing.T, args ...string) (string, string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, append([]string{"exec"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		var stdout, stderr bytes.Buffer
+		inv.Stdout = &stdout
+		inv.Stderr = &stderr
+		err := inv.WithContext(ctx).Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		// Arguments aren't interpreted by the shell.
+		stdout, _, err := run(t, workspace.Name, "--", "echo", "hello  $USER")
+		require.NoError(t, err)
+		require.Equal(t, "hello  $USER\n", stdout)
+	})
+
+	t.Run("ExitCode", func(t *testing.T) {
+		t.Parallel()
+		stdout, _, err := run(t, workspace.Name, "--", "sh", "-c", "echo failing; exit 3")
+		require.ErrorContains(t, err, "exit code 3")
+		require.Equal(t, "failing\n", stdout)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+		stdout, stderr, err := run(t, "--search", "owner:me", "--", "sh", "-c", "echo one; echo two")
+		require.NoError(t, err)
+		require.Equal(t, "testuser/"+workspace.Name+": one\ntestuser/"+workspace.Name+": two\n", stdout)
+		require.Contains(t, stderr, "Ran in 1 workspace(s), 0 failed.")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		stdout, _, err := run(t, "--search", "owner:me", "-o", "json", "--", "sh", "-c", "echo out; echo err >&2; exit 2")
+		require.ErrorContains(t, err, "exit code 2")
+
+		var results []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &results))
+		require.Len(t, results, 1)
+		require.Equal(t, "testuser/"+workspace.Name, results[0]["workspace"])
+		require.EqualValues(t, 2, results[0]["exit_code"])
+		require.Equal(t, "out\n", results[0]["stdout"])
+		require.Equal(t, "err\n", results[0]["stderr"])
+	})
+
+	t.Run("AgentNotFound", func(t *testing.T) {
+		t.Parallel()
+		stdout, _, err := run(t, "--search", "owner:me", "--agent", "missing", "-o", "json", "--", "true")
+		require.ErrorContains(t, err, "exit code 255")
+		require.Contains(t, stdout, `agent \"missing\" not found`)
+	})
+}
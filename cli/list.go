@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/clibase"
 	"github.com/coder/coder/cli/cliui"
@@ -67,6 +69,7 @@ func workspaceListRowFromWorkspace(now time.Time, usersByID map[uuid.UUID]coders
 func (r *RootCmd) list() *clibase.Cmd {
 	var (
 		all               bool
+		cached            bool
 		defaultQuery      = "owner:me"
 		searchQuery       string
 		displayWorkspaces []workspaceListRow
@@ -80,10 +83,26 @@ func (r *RootCmd) list() *clibase.Cmd {
 		Annotations: workspaceCommand,
 		Use:         "list",
 		Short:       "List workspaces",
-		Aliases:     []string{"ls"},
+		Long: "Your workspaces are cached in the config directory each time they're listed. " +
+			"If the deployment can't be reached, the cached workspaces are shown instead.\n\n" +
+			formatExamples(
+				example{
+					Description: "List your workspaces without contacting the deployment",
+					Command:     "coder list --cached",
+				},
+			),
+		Aliases: []string{"ls"},
 		Middleware: clibase.Chain(
 			clibase.RequireNArgs(0),
-			r.InitClient(client),
+			func(next clibase.HandlerFunc) clibase.HandlerFunc {
+				withClient := r.InitClient(client)(next)
+				return func(inv *clibase.Invocation) error {
+					if cached {
+						return next(inv)
+					}
+					return withClient(inv)
+				}
+			},
 		),
 		Handler: func(inv *clibase.Invocation) error {
 			filter := codersdk.WorkspaceFilter{
@@ -92,11 +111,61 @@ func (r *RootCmd) list() *clibase.Cmd {
 			if all && searchQuery == defaultQuery {
 				filter.FilterQuery = ""
 			}
-
-			res, err := client.Workspaces(inv.Context(), filter)
+			// Only the default listing is cached, so it's always the same
+			// set of workspaces.
+			cacheable := filter.FilterQuery == defaultQuery
+			conf, err := r.contextConfig()
 			if err != nil {
 				return err
 			}
+			cache := conf.WorkspacesCache()
+
+			var (
+				res       codersdk.WorkspacesResponse
+				fromCache bool
+			)
+			if cached {
+				if !cacheable {
+					return xerrors.New("only your own workspaces are cached, --cached can't be used with --all or --search")
+				}
+				clientURL, _, err := r.session()
+				if err != nil {
+					return err
+				}
+				list, err := readCache[codersdk.Workspace](cache, clientURL)
+				if errors.Is(err, errNoCache) {
+					return xerrors.New("no workspaces are cached, run \"coder list\" while the deployment is reachable")
+				}
+				if err != nil {
+					return err
+				}
+				res.Workspaces = list.Items
+				fromCache = true
+			} else {
+				var err error
+				res, err = client.Workspaces(inv.Context(), filter)
+				var sdkErr *codersdk.Error
+				switch {
+				case err == nil:
+					if cacheable {
+						// The cache is best effort, listing shouldn't fail
+						// because of it.
+						_ = writeCache(cache, client.URL, res.Workspaces)
+					}
+				case cacheable && inv.Context().Err() == nil && !errors.As(err, &sdkErr):
+					// The deployment couldn't be reached.
+					list, cacheErr := readCache[codersdk.Workspace](cache, client.URL)
+					if cacheErr != nil {
+						return err
+					}
+					cliui.Warnf(inv.Stderr, "Couldn't reach %s, showing the workspaces cached %s ago: %s",
+						client.URL, durationDisplay(time.Since(list.UpdatedAt).Truncate(time.Second)), err)
+					res.Workspaces = list.Items
+					fromCache = true
+				default:
+					return err
+				}
+			}
 			if len(res.Workspaces) == 0 {
 				_, _ = fmt.Fprintln(inv.Stderr, cliui.DefaultStyles.Prompt.String()+"No workspaces found! Create one:")
 				_, _ = fmt.Fprintln(inv.Stderr)
@@ -105,14 +174,19 @@ func (r *RootCmd) list() *clibase.Cmd {
 				return nil
 			}
 
-			userRes, err := client.Users(inv.Context(), codersdk.UsersRequest{})
-			if err != nil {
-				return err
-			}
-
 			usersByID := map[uuid.UUID]codersdk.User{}
-			for _, user := range userRes.Users {
-				usersByID[user.ID] = user
+			if fromCache {
+				for _, workspace := range res.Workspaces {
+					usersByID[workspace.OwnerID] = codersdk.User{Username: workspace.OwnerName}
+				}
+			} else {
+				userRes, err := client.Users(inv.Context(), codersdk.UsersRequest{})
+				if err != nil {
+					return err
+				}
+				for _, user := range userRes.Users {
+					usersByID[user.ID] = user
+				}
 			}
 
 			now := time.Now()
@@ -144,6 +218,11 @@ func (r *RootCmd) list() *clibase.Cmd {
 			Default:     defaultQuery,
 			Value:       clibase.StringOf(&searchQuery),
 		},
+		{
+			Flag:        "cached",
+			Description: "List the workspaces cached the last time they were listed, without contacting the deployment.",
+			Value:       clibase.BoolOf(&cached),
+		},
 	}
 
 	formatter.AttachOptions(&cmd.Options)
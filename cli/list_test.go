@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"net/http/httptest"
+	"net/http/httputil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/cli/config"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/pty/ptytest"
@@ -70,4 +73,62 @@ func TestList(t *testing.T) {
 		require.NoError(t, json.Unmarshal(out.Bytes(), &templates))
 		require.Len(t, templates, 1)
 	})
+	t.Run("Cached", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		// The deployment is reached through a proxy so it can be made
+		// unreachable.
+		proxy := httptest.NewServer(httputil.NewSingleHostReverseProxy(client.URL))
+		defer proxy.Close()
+		root := config.Root(t.TempDir())
+		require.NoError(t, root.URL().Write(proxy.URL))
+		require.NoError(t, root.Session().Write(client.SessionToken()))
+
+		list := func(args ...string) ([]codersdk.Workspace, string, error) {
+			ctx, cancelFunc := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancelFunc()
+			inv, _ := clitest.New(t, append([]string{"--global-config", string(root), "list", "--output=json"}, args...)...)
+			var stdout, stderr bytes.Buffer
+			inv.Stdout = &stdout
+			inv.Stderr = &stderr
+			err := inv.WithContext(ctx).Run()
+			if err != nil {
+				return nil, stderr.String(), err
+			}
+			var workspaces []codersdk.Workspace
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &workspaces))
+			return workspaces, stderr.String(), nil
+		}
+
+		_, _, err := list("--cached")
+		require.ErrorContains(t, err, "no workspaces are cached")
+
+		workspaces, _, err := list()
+		require.NoError(t, err)
+		require.Len(t, workspaces, 1)
+
+		_, _, err = list("--cached", "--all")
+		require.ErrorContains(t, err, "--cached can't be used")
+
+		proxy.Close()
+		workspaces, _, err = list("--cached")
+		require.NoError(t, err)
+		require.Len(t, workspaces, 1)
+		require.Equal(t, workspace.ID, workspaces[0].ID)
+
+		workspaces, stderr, err := list()
+		require.NoError(t, err)
+		require.Len(t, workspaces, 1)
+		require.Contains(t, stderr, "showing the workspaces cached")
+
+		_, _, err = list("--all")
+		require.Error(t, err)
+	})
 }
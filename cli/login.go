@@ -47,10 +47,26 @@ func (r *RootCmd) login() *clibase.Cmd {
 		trial    bool
 	)
 	cmd := &clibase.Cmd{
-		Use:        "login <url>",
-		Short:      "Authenticate with Coder deployment",
+		Use:   "login <url>",
+		Short: "Authenticate with Coder deployment",
+		Long: "The URL and session are stored in the current context, so use --context to stay logged in to several deployments.\n\n" +
+			formatExamples(
+				example{
+					Description: "Log in to a deployment",
+					Command:     "coder login https://coder.example.com",
+				},
+				example{
+					Description: "Log in to another deployment in the prod context",
+					Command:     "coder login --context prod https://prod.coder.example.com",
+				},
+			),
 		Middleware: clibase.RequireRangeArgs(0, 1),
 		Handler: func(inv *clibase.Invocation) error {
+			conf, err := r.contextConfig()
+			if err != nil {
+				return err
+			}
+
 			rawURL := ""
 			if len(inv.Args) == 0 {
 				rawURL = r.clientURL.String()
@@ -200,12 +216,11 @@ func (r *RootCmd) login() *clibase.Cmd {
 				}
 
 				sessionToken := resp.SessionToken
-				config := r.createConfig()
-				err = config.Session().Write(sessionToken)
+				err = conf.Session().Write(sessionToken)
 				if err != nil {
 					return xerrors.Errorf("write session token: %w", err)
 				}
-				err = config.URL().Write(serverURL.String())
+				err = conf.URL().Write(serverURL.String())
 				if err != nil {
 					return xerrors.Errorf("write server url: %w", err)
 				}
@@ -254,12 +269,11 @@ func (r *RootCmd) login() *clibase.Cmd {
 				return xerrors.Errorf("get user: %w", err)
 			}
 
-			config := r.createConfig()
-			err = config.Session().Write(sessionToken)
+			err = conf.Session().Write(sessionToken)
 			if err != nil {
 				return xerrors.Errorf("write session token: %w", err)
 			}
-			err = config.URL().Write(serverURL.String())
+			err = conf.URL().Write(serverURL.String())
 			if err != nil {
 				return xerrors.Errorf("write server url: %w", err)
 			}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	logLineTypeBuild = "build"
+	logLineTypeAgent = "agent"
+)
+
+// logLine is a provisioner job or agent startup log line, it's the type
+// provided to the OutputFormatter.
+type logLine struct {
+	Time  time.Time         `json:"time"`
+	Level codersdk.LogLevel `json:"level"`
+	// Type is either "build" or "agent".
+	Type  string `json:"type"`
+	Agent string `json:"agent,omitempty"`
+	// Source is the stage of the build, or the log source of the agent.
+	Source string `json:"source"`
+	Output string `json:"output"`
+}
+
+func (l logLine) String() string {
+	source := l.Source
+	if l.Type == logLineTypeAgent {
+		source = l.Agent
+		if l.Source != "" {
+			source += "/" + l.Source
+		}
+	}
+	return fmt.Sprintf("%s [%s] %s: %s", l.Time.Local().Format("2006-01-02 15:04:05.000"), l.Level, source, l.Output)
+}
+
+func logLineFromBuildLog(log codersdk.ProvisionerJobLog) logLine {
+	return logLine{
+		Time:   log.CreatedAt,
+		Level:  log.Level,
+		Type:   logLineTypeBuild,
+		Source: log.Stage,
+		Output: log.Output,
+	}
+}
+
+func logLineFromStartupLog(agent codersdk.WorkspaceAgent, log codersdk.WorkspaceAgentStartupLog) logLine {
+	var source string
+	for _, s := range agent.LogSources {
+		if s.ID == log.SourceID {
+			source = s.DisplayName
+			break
+		}
+	}
+	return logLine{
+		Time:   log.CreatedAt,
+		Level:  log.Level,
+		Type:   logLineTypeAgent,
+		Agent:  agent.Name,
+		Source: source,
+		Output: log.Output,
+	}
+}
+
+func (r *RootCmd) logs() *clibase.Cmd {
+	var (
+		buildNumber int64
+		agentName   string
+		follow      bool
+		since       time.Duration
+		formatter   = cliui.NewOutputFormatter(
+			cliui.TextFormat(),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "logs <workspace>",
+		Short:       "Show the build and agent startup logs of a workspace",
+		Long: "Build logs are printed first, followed by the startup logs of each agent. " +
+			"With --output json, each line is printed as a separate JSON object.\n\n" + formatExamples(
+			example{
+				Description: "Show the logs of the latest build",
+				Command:     "coder logs my-workspace",
+			},
+			example{
+				Description: "Show the logs of the third build of the workspace",
+				Command:     "coder logs my-workspace --build 3",
+			},
+			example{
+				Description: "Follow the startup logs of an agent that is starting",
+				Command:     "coder logs my-workspace --agent main --follow",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			build := workspace.LatestBuild
+			if buildNumber > 0 {
+				build, err = client.WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx, workspace.OwnerName, workspace.Name, strconv.FormatInt(buildNumber, 10))
+				if err != nil {
+					return xerrors.Errorf("get build %d: %w", buildNumber, err)
+				}
+			}
+
+			var (
+				cutoff time.Time
+				mu     sync.Mutex
+			)
+			if since > 0 {
+				cutoff = time.Now().Add(-since)
+			}
+			write := func(line logLine) error {
+				if line.Time.Before(cutoff) {
+					return nil
+				}
+				out, err := formatter.Format(ctx, line)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				_, err = fmt.Fprintln(inv.Stdout, out)
+				return err
+			}
+
+			return writeWorkspaceLogs(ctx, client, build, agentName, follow, write)
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "build",
+			Description: "The number of the build to show logs for. Defaults to the latest build.",
+			Value:       clibase.Int64Of(&buildNumber),
+		},
+		{
+			Flag:        "agent",
+			Description: "Only show the startup logs of the agent with this name.",
+			Value:       clibase.StringOf(&agentName),
+		},
+		{
+			Flag:          "follow",
+			FlagShorthand: "f",
+			Description:   "Wait for new logs until the build has completed and the agents have started.",
+			Value:         clibase.BoolOf(&follow),
+		},
+		{
+			Flag:        "since",
+			Description: "Only show logs newer than a relative duration like 5m or 1h.",
+			Value:       clibase.DurationOf(&since),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// writeWorkspaceLogs writes the logs of the build, followed by the startup
+// logs of its agents, or only of the agent named agentName if it's set.
+func writeWorkspaceLogs(ctx context.Context, client *codersdk.Client, build codersdk.WorkspaceBuild, agentName string, follow bool, write func(logLine) error) error {
+	err := writeBuildLogs(ctx, client, build.ID, follow, write)
+	if err != nil {
+		return err
+	}
+	if follow {
+		// The build may have been running, so the agents weren't
+		// known yet.
+		build, err = client.WorkspaceBuild(ctx, build.ID)
+		if err != nil {
+			return xerrors.Errorf("get build: %w", err)
+		}
+	}
+
+	var agents []codersdk.WorkspaceAgent
+	for _, resource := range build.Resources {
+		for _, agent := range resource.Agents {
+			if agentName == "" || agent.Name == agentName {
+				agents = append(agents, agent)
+			}
+		}
+	}
+	if agentName != "" && len(agents) == 0 {
+		return xerrors.Errorf("agent %q not found in build %d", agentName, build.BuildNumber)
+	}
+
+	// Agents start concurrently, so their logs are followed
+	// concurrently too.
+	var eg errgroup.Group
+	for _, agent := range agents {
+		agent := agent
+		if !follow {
+			err = writeStartupLogs(ctx, client, agent, follow, write)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		eg.Go(func() error {
+			return writeStartupLogs(ctx, client, agent, follow, write)
+		})
+	}
+	return eg.Wait()
+}
+
+func writeBuildLogs(ctx context.Context, client *codersdk.Client, buildID uuid.UUID, follow bool, write func(logLine) error) error {
+	if !follow {
+		logs, err := client.WorkspaceBuildLogs(ctx, buildID)
+		if err != nil {
+			return xerrors.Errorf("get build logs: %w", err)
+		}
+		for _, log := range logs {
+			err = write(logLineFromBuildLog(log))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	logs, closer, err := client.WorkspaceBuildLogsAfter(ctx, buildID, 0)
+	if err != nil {
+		return xerrors.Errorf("follow build logs: %w", err)
+	}
+	defer closer.Close()
+	// The channel is closed once the build has completed.
+	for log := range logs {
+		err = write(logLineFromBuildLog(log))
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func writeStartupLogs(ctx context.Context, client *codersdk.Client, agent codersdk.WorkspaceAgent, follow bool, write func(logLine) error) error {
+	if !follow {
+		logs, err := client.WorkspaceAgentStartupLogs(ctx, agent.ID)
+		if err != nil {
+			return xerrors.Errorf("get startup logs of agent %q: %w", agent.Name, err)
+		}
+		for _, log := range logs {
+			err = write(logLineFromStartupLog(agent, log))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	chunks, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, agent.ID, 0)
+	if err != nil {
+		return xerrors.Errorf("follow startup logs of agent %q: %w", agent.Name, err)
+	}
+	defer closer.Close()
+	// The channel is closed once the agent has started.
+	for logs := range chunks {
+		for _, log := range logs {
+			err = write(logLineFromStartupLog(agent, log))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return ctx.Err()
+}
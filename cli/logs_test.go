@@ -0,0 +1,156 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestLogs(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	agentToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Log{
+				Log: &proto.Log{Level: proto.LogLevel_INFO, Output: "creating instance"},
+			},
+		}, {
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "dev",
+						Type: "google_compute_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: "main",
+							Auth: &proto.Agent_Token{
+								Token: agentToken,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	err := agentClient.PatchStartupLogs(ctx, agentsdk.PatchStartupLogs{
+		Logs: []agentsdk.StartupLog{{
+			CreatedAt: time.Now(),
+			Output:    "cloning repository",
+			Level:     codersdk.LogLevelWarn,
+		}},
+	})
+	require.NoError(t, err)
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t, "logs", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, stdout.String(), "[info] Starting workspace: creating instance")
+		require.Contains(t, stdout.String(), "[warn] main: cloning repository")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--agent", "main", "--build", "1", "-o", "json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.WithContext(ctx).Run())
+
+		var lines []map[string]any
+		decoder := json.NewDecoder(&stdout)
+		for decoder.More() {
+			var line map[string]any
+			require.NoError(t, decoder.Decode(&line))
+			lines = append(lines, line)
+		}
+		require.NotEmpty(t, lines)
+		last := lines[len(lines)-1]
+		require.Equal(t, "agent", last["type"])
+		require.Equal(t, "main", last["agent"])
+		require.Equal(t, "warn", last["level"])
+		require.Equal(t, "cloning repository", last["output"])
+	})
+
+	t.Run("Follow", func(t *testing.T) {
+		t.Parallel()
+
+		// Following ends once the build has completed and the agent is
+		// ready.
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, "logs", workspace.Name, "--follow")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := agentClient.PostLifecycle(ctx, agentsdk.PostLifecycleRequest{
+			State:     codersdk.WorkspaceAgentLifecycleReady,
+			ChangedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, stdout.String(), "creating instance")
+		require.Contains(t, stdout.String(), "cloning repository")
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--since", "1ns")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Empty(t, stdout.String())
+	})
+
+	t.Run("AgentNotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--agent", "missing")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, `agent "missing" not found in build 1`)
+	})
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) open() *clibase.Cmd {
+	var vscode bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "open <workspace>[.<agent>] [app | directory]",
+		Short:       "Open a workspace app in your browser, or list the apps of a workspace",
+		Long: "Apps are matched by slug or display name. The URL is printed instead of " +
+			"opened when no browser is available. With --vscode, the workspace is opened " +
+			"in VS Code Desktop instead, which requires the Coder extension for VS Code. " +
+			"The directory defaults to the agent's working directory.\n\n" + formatExamples(
+			example{
+				Description: "List the apps of a workspace",
+				Command:     "coder open my-workspace",
+			},
+			example{
+				Description: "Open code-server",
+				Command:     "coder open my-workspace code-server",
+			},
+			example{
+				Description: "Open a project in VS Code Desktop",
+				Command:     "coder open --vscode my-workspace /home/coder/project",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(1, 2),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			{
+				Flag:        "vscode",
+				Description: "Open the workspace in VS Code Desktop.",
+				Value:       clibase.BoolOf(&vscode),
+			},
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			if vscode {
+				return openVSCode(inv, client)
+			}
+
+			ctx := inv.Context()
+			workspaceName, agentName, _ := strings.Cut(inv.Args[0], ".")
+			workspace, err := namedWorkspace(ctx, client, workspaceName)
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			var apps []workspaceAppRow
+			for _, resource := range workspace.LatestBuild.Resources {
+				for _, agent := range resource.Agents {
+					if agentName != "" && agent.Name != agentName {
+						continue
+					}
+					for _, app := range agent.Apps {
+						apps = append(apps, workspaceAppRowFromApp(agent, app))
+					}
+				}
+			}
+
+			if len(inv.Args) == 1 {
+				if len(apps) == 0 {
+					cliui.Infof(inv.Stderr, "Workspace %q has no apps.\n", workspace.Name)
+					return nil
+				}
+				out, err := cliui.DisplayTable(apps, "", nil)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(inv.Stdout, out)
+				return err
+			}
+
+			var matches []workspaceAppRow
+			for _, row := range apps {
+				if row.app.Slug == inv.Args[1] || strings.EqualFold(row.app.DisplayName, inv.Args[1]) {
+					matches = append(matches, row)
+				}
+			}
+			if len(matches) == 0 {
+				return xerrors.Errorf("app %q not found in workspace %q", inv.Args[1], workspace.Name)
+			}
+			if len(matches) > 1 {
+				return xerrors.Errorf("app %q exists on several agents, specify one with %s.<agent>", inv.Args[1], workspace.Name)
+			}
+			match := matches[0]
+
+			var appHost codersdk.AppHostResponse
+			if !match.app.External {
+				appHost, err = client.AppHost(ctx)
+				if err != nil {
+					return xerrors.Errorf("get app host: %w", err)
+				}
+			}
+			appURL, err := workspaceAppURL(client.URL, appHost, workspace, match.agent, match.app)
+			if err != nil {
+				return err
+			}
+			if match.app.Health == codersdk.WorkspaceAppHealthUnhealthy {
+				cliui.Warnf(inv.Stderr, "App %q is unhealthy.\n", match.app.DisplayName)
+			}
+			openOrPrintURL(inv, appURL)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// openVSCode opens the workspace in the first argument in VS Code Desktop.
+func openVSCode(inv *clibase.Invocation, client *codersdk.Client) error {
+	ctx := inv.Context()
+	me, err := client.User(ctx, codersdk.Me)
+	if err != nil {
+		return err
+	}
+	workspace, agent, err := getWorkspaceAndAgent(ctx, inv, client, me.ID.String(), inv.Args[0])
+	if err != nil {
+		return err
+	}
+	directory := agent.ExpandedDirectory
+	if len(inv.Args) > 1 {
+		directory = inv.Args[1]
+	}
+
+	// The extension authenticates to the deployment with the session
+	// token of the CLI. It's only passed to VS Code directly, never
+	// printed, in which case the extension asks the user to log in.
+	err = openURL(inv, vscodeURL(client.URL, client.SessionToken(), workspace, agent, directory))
+	if err != nil {
+		_, _ = fmt.Fprintf(inv.Stdout, "Open the following in VS Code, and log in when the Coder extension asks you to:\n\n\t%s\n\n",
+			vscodeURL(client.URL, "", workspace, agent, directory))
+	}
+	return nil
+}
+
+// workspaceAppRow is the type provided to DisplayTable.
+type workspaceAppRow struct {
+	agent codersdk.WorkspaceAgent
+	app   codersdk.WorkspaceApp
+
+	Agent  string `table:"agent"`
+	Slug   string `table:"slug,default_sort"`
+	Name   string `table:"name"`
+	Health string `table:"health"`
+}
+
+func workspaceAppRowFromApp(agent codersdk.WorkspaceAgent, app codersdk.WorkspaceApp) workspaceAppRow {
+	return workspaceAppRow{
+		agent:  agent,
+		app:    app,
+		Agent:  agent.Name,
+		Slug:   app.Slug,
+		Name:   app.DisplayName,
+		Health: string(app.Health),
+	}
+}
+
+// workspaceAppURL returns the URL of an app the same way the dashboard
+// builds it. appHost.Host is the wildcard hostname for subdomain apps, e.g.
+// "*.apps.example.com", and is empty if the deployment doesn't have one.
+func workspaceAppURL(accessURL *url.URL, appHost codersdk.AppHostResponse, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent, app codersdk.WorkspaceApp) (string, error) {
+	if app.External {
+		return app.URL, nil
+	}
+
+	slug := app.Slug
+	if slug == "" {
+		slug = app.DisplayName
+	}
+	if app.Subdomain {
+		if appHost.Host == "" {
+			return "", xerrors.Errorf("app %q requires subdomain app access, which is not configured on this deployment", slug)
+		}
+		subdomain := httpapi.ApplicationURL{
+			AppSlugOrPort: slug,
+			AgentName:     agent.Name,
+			WorkspaceName: workspace.Name,
+			Username:      workspace.OwnerName,
+		}.String()
+		return fmt.Sprintf("%s://%s/", accessURL.Scheme, strings.Replace(appHost.Host, "*", subdomain, 1)), nil
+	}
+
+	if appHost.DisablePathApps {
+		return "", xerrors.Errorf("app %q is path-based, which is disabled on this deployment", slug)
+	}
+	base := fmt.Sprintf("/@%s/%s.%s", workspace.OwnerName, workspace.Name, agent.Name)
+	if app.Command != "" {
+		return accessURL.ResolveReference(&url.URL{
+			Path:     base + "/terminal",
+			RawQuery: url.Values{"command": {app.Command}}.Encode(),
+		}).String(), nil
+	}
+	// The trailing slash avoids a redirect.
+	return accessURL.ResolveReference(&url.URL{Path: base + "/apps/" + slug + "/"}).String(), nil
+}
+
+// vscodeURL returns the deep link the Coder extension for VS Code handles,
+// the same as the dashboard's "VS Code Desktop" button. The token is omitted
+// if empty.
+func vscodeURL(accessURL *url.URL, token string, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent, directory string) string {
+	query := url.Values{
+		"owner":     {workspace.OwnerName},
+		"workspace": {workspace.Name},
+		"url":       {accessURL.String()},
+		"agent":     {agent.Name},
+	}
+	if token != "" {
+		query.Set("token", token)
+	}
+	if directory != "" {
+		query.Set("folder", directory)
+	}
+	return (&url.URL{
+		Scheme:   "vscode",
+		Host:     "coder.coder-remote",
+		Path:     "/open",
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// openOrPrintURL opens the URL in the browser, or prints it if that isn't
+// possible.
+func openOrPrintURL(inv *clibase.Invocation, u string) {
+	if err := openURL(inv, u); err != nil {
+		_, _ = fmt.Fprintf(inv.Stdout, "Open the following in your browser:\n\n\t%s\n\n", u)
+	}
+}
@@ -0,0 +1,110 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, _ := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = "main"
+		agents[0].Directory = "/home/coder"
+		agents[0].Apps = []*proto.App{
+			{Slug: "code-server", DisplayName: "code-server", Url: "http://localhost:8080"},
+			{Slug: "htop", DisplayName: "Htop", Command: "htop"},
+			{Slug: "docs", DisplayName: "Docs", Url: "https://coder.com/docs", External: true},
+			{Slug: "jupyter", DisplayName: "Jupyter", Url: "http://localhost:8888", Subdomain: true},
+		}
+		return agents
+	})
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, append([]string{"--no-open", "open"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.WithContext(ctx).Run()
+		return stdout.String(), err
+	}
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, workspace.Name)
+		require.NoError(t, err)
+		require.Contains(t, out, "code-server")
+		require.Contains(t, out, "Jupyter")
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, workspace.Name+".main", "code-server")
+		require.NoError(t, err)
+		require.Contains(t, out, client.URL.String()+"/@testuser/"+workspace.Name+".main/apps/code-server/")
+	})
+
+	t.Run("Command", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, workspace.Name, "htop")
+		require.NoError(t, err)
+		require.Contains(t, out, "/@testuser/"+workspace.Name+".main/terminal?command=htop")
+	})
+
+	t.Run("External", func(t *testing.T) {
+		t.Parallel()
+		// Display names match case-insensitively.
+		out, err := run(t, workspace.Name, "DOCS")
+		require.NoError(t, err)
+		require.Contains(t, out, "https://coder.com/docs")
+	})
+
+	t.Run("SubdomainNotConfigured", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, workspace.Name, "jupyter")
+		require.ErrorContains(t, err, "subdomain app access")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, workspace.Name, "missing")
+		require.ErrorContains(t, err, `app "missing" not found`)
+	})
+
+	t.Run("VSCode", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "--vscode", workspace.Name, "/home/coder/project")
+		require.NoError(t, err)
+
+		var link *url.URL
+		for _, field := range strings.Fields(out) {
+			if strings.HasPrefix(field, "vscode://") {
+				link, err = url.Parse(field)
+				require.NoError(t, err)
+			}
+		}
+		require.NotNil(t, link, "no vscode link in output: %s", out)
+		require.Equal(t, "coder.coder-remote", link.Host)
+		require.Equal(t, "/open", link.Path)
+		query := link.Query()
+		require.Equal(t, "testuser", query.Get("owner"))
+		require.Equal(t, workspace.Name, query.Get("workspace"))
+		require.Equal(t, "main", query.Get("agent"))
+		require.Equal(t, "/home/coder/project", query.Get("folder"))
+		require.Equal(t, client.URL.String(), query.Get("url"))
+		// The session token is never printed.
+		require.False(t, query.Has("token"))
+		require.NotContains(t, out, client.SessionToken())
+	})
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+)
+
+// pluginPrefix is the prefix of executables on PATH that are added as
+// subcommands, the same as git and kubectl plugins.
+const pluginPrefix = "coder-"
+
+// pluginCommands returns a subcommand for each coder-<name> executable on
+// PATH. Built-in commands take precedence over plugins, and plugins in
+// earlier PATH entries over later ones.
+func (r *RootCmd) pluginCommands(path string, builtin []*clibase.Cmd) []*clibase.Cmd {
+	taken := map[string]bool{}
+	for _, cmd := range builtin {
+		taken[cmd.Name()] = true
+		for _, alias := range cmd.Aliases {
+			taken[alias] = true
+		}
+	}
+
+	var cmds []*clibase.Cmd
+	for _, dir := range filepath.SplitList(path) {
+		// An empty entry is the working directory, which isn't a place
+		// plugins should be picked up from.
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), pluginPrefix) {
+				continue
+			}
+			file := filepath.Join(dir, entry.Name())
+			// Stat follows symlinks, which package managers commonly
+			// install executables as.
+			info, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+			name, ok := pluginName(info)
+			if !ok || taken[name] || isReleaseBinary(name) {
+				continue
+			}
+			taken[name] = true
+			cmds = append(cmds, r.plugin(name, file))
+		}
+	}
+	return cmds
+}
+
+// releaseArchitectures are the architectures Coder is released for.
+var releaseArchitectures = map[string]bool{
+	"386":   true,
+	"amd64": true,
+	"arm":   true,
+	"arm64": true,
+	"armv7": true,
+}
+
+// isReleaseBinary returns true if the plugin name is that of a Coder
+// release binary, e.g. "linux-amd64" for coder-linux-amd64. These are
+// commonly downloaded onto PATH as is, and aren't plugins.
+func isReleaseBinary(name string) bool {
+	goos, goarch, ok := strings.Cut(name, "-")
+	if !ok {
+		return false
+	}
+	switch goos {
+	case "darwin", "freebsd", "linux", "netbsd", "openbsd", "windows":
+		return releaseArchitectures[goarch]
+	}
+	return false
+}
+
+func (r *RootCmd) plugin(name, file string) *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   name,
+		Short: fmt.Sprintf("Run the plugin at %s", file),
+		// Arguments and flags are all passed to the plugin.
+		RawArgs: true,
+		Handler: func(inv *clibase.Invocation) error {
+			env, err := r.pluginEnv()
+			if err != nil {
+				return err
+			}
+			//nolint:gosec
+			cmd := exec.CommandContext(inv.Context(), file, inv.Args...)
+			cmd.Stdin = inv.Stdin
+			cmd.Stdout = inv.Stdout
+			cmd.Stderr = inv.Stderr
+			cmd.Env = append(inv.Environ.ToOS(), env...)
+			err = cmd.Run()
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code := exitErr.ExitCode()
+				// The plugin was killed by a signal.
+				if code < 0 {
+					code = 1
+				}
+				return &exitError{code: code}
+			}
+			if err != nil {
+				return xerrors.Errorf("run plugin %q: %w", name, err)
+			}
+			return nil
+		},
+	}
+}
+
+// pluginEnv returns the environment variables that let plugins use the
+// deployment the CLI is logged in to, the same way the global flags and
+// environment variables configure the CLI. They're only set if the CLI is
+// logged in, so plugins can check for them.
+func (r *RootCmd) pluginEnv() ([]string, error) {
+	context, err := r.currentContext()
+	if err != nil {
+		return nil, err
+	}
+	env := []string{
+		"CODER_CONFIG_DIR=" + string(r.createConfig()),
+		envContext + "=" + context,
+	}
+	if len(r.header) > 0 {
+		env = append(env, "CODER_HEADER="+strings.TrimSpace(clibase.StringArray(r.header).String()))
+	}
+
+	clientURL, token, err := r.session()
+	if errors.Is(err, errUnauthenticated) {
+		return env, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(env,
+		envURL+"="+clientURL.String(),
+		envSessionToken+"="+token,
+	), nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/config"
+)
+
+func TestPluginCommands(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The plugins are shell scripts")
+	}
+
+	writePlugin := func(t *testing.T, dir, name, script string, mode os.FileMode) {
+		t.Helper()
+		err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode)
+		require.NoError(t, err)
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "coder-hello", `echo "hello $CODER_URL $CODER_SESSION_TOKEN $*"; exit 3`, 0o755)
+	writePlugin(t, second, "coder-hello", "echo shadowed", 0o755)
+	writePlugin(t, second, "coder-bye", "echo bye", 0o755)
+	writePlugin(t, second, "coder-list", "echo builtin", 0o755)
+	writePlugin(t, second, "coder-noexec", "echo noexec", 0o644)
+	writePlugin(t, second, "coder-linux-amd64", "echo release", 0o755)
+
+	var r RootCmd
+	builtin := r.AGPL()
+	plugins := r.pluginCommands(first+string(os.PathListSeparator)+second, builtin)
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		names = append(names, plugin.Name())
+	}
+	require.Equal(t, []string{"hello", "bye"}, names)
+
+	t.Run("Env", func(t *testing.T) {
+		t.Parallel()
+
+		var r RootCmd
+		builtin := r.AGPL()
+		cmd, err := r.Command(append(builtin, r.pluginCommands(first, builtin)...))
+		require.NoError(t, err)
+
+		conf := config.Root(t.TempDir())
+		require.NoError(t, conf.URL().Write("https://coder.example.com"))
+		require.NoError(t, conf.Session().Write("session-token"))
+
+		inv := cmd.Invoke("--global-config", string(conf), "hello", "--flag", "arg")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.Run()
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 3, exitErr.code)
+		require.Equal(t, "hello https://coder.example.com session-token --flag arg\n", stdout.String())
+	})
+
+	t.Run("ExitCode", func(t *testing.T) {
+		t.Parallel()
+
+		//nolint:gosec
+		cmd := exec.Command(os.Args[0], "hello")
+		cmd.Env = append(os.Environ(),
+			envTestRunMain+"=1",
+			"PATH="+first,
+			"CODER_CONFIG_DIR="+t.TempDir(),
+		)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 3, exitErr.ExitCode())
+		require.Equal(t, "hello", strings.TrimSpace(stdout.String()))
+		require.Empty(t, stderr.String())
+	})
+}
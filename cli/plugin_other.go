@@ -0,0 +1,17 @@
+//go:build !windows
+
+package cli
+
+import (
+	"os"
+	"strings"
+)
+
+// pluginName returns the subcommand name of a plugin executable.
+func pluginName(info os.FileInfo) (string, bool) {
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+		return "", false
+	}
+	name := strings.TrimPrefix(info.Name(), pluginPrefix)
+	return name, name != "" && !strings.HasPrefix(name, "-")
+}
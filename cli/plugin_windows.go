@@ -0,0 +1,34 @@
+//go:build windows
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pluginName returns the subcommand name of a plugin executable. Windows
+// has no executable bit, so the extension must be one of PATHEXT.
+func pluginName(info os.FileInfo) (string, bool) {
+	if !info.Mode().IsRegular() {
+		return "", false
+	}
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = ".com;.exe;.bat;.cmd"
+	}
+	ext := filepath.Ext(info.Name())
+	executable := false
+	for _, e := range filepath.SplitList(pathExt) {
+		if ext != "" && strings.EqualFold(e, ext) {
+			executable = true
+			break
+		}
+	}
+	if !executable {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(info.Name(), pluginPrefix), ext)
+	return name, name != "" && !strings.HasPrefix(name, "-")
+}
@@ -28,11 +28,13 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 	var (
 		tcpForwards []string // <port>:<port>
 		udpForwards []string // <port>:<port>
+		// remoteForwards are in the format of the OpenSSH -R flag.
+		remoteForwards []string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Use:     "port-forward <workspace>",
-		Short:   "Forward ports from machine to a workspace",
+		Short:   "Forward ports between your machine and a workspace",
 		Aliases: []string{"tunnel"},
 		Long: formatExamples(
 			example{
@@ -55,6 +57,14 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				Description: "Port forward specifying the local address to bind to",
 				Command:     "coder port-forward <workspace> --tcp 1.2.3.4:8080:8080",
 			},
+			example{
+				Description: "Expose port 3000 on your local machine as port 8080 in the workspace",
+				Command:     "coder port-forward <workspace> --remote 8080:3000",
+			},
+			example{
+				Description: "Expose your local Docker socket in the workspace",
+				Command:     "coder port-forward <workspace> --remote /tmp/docker.sock:/var/run/docker.sock",
+			},
 		),
 		Middleware: clibase.Chain(
 			clibase.RequireNArgs(1),
@@ -68,7 +78,11 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("parse port-forward specs: %w", err)
 			}
-			if len(specs) == 0 {
+			remoteSpecs, err := parseRemoteForwards(remoteForwards)
+			if err != nil {
+				return err
+			}
+			if len(specs) == 0 && len(remoteSpecs) == 0 {
 				err = inv.Command.HelpHandler(inv)
 				if err != nil {
 					return xerrors.Errorf("generate help output: %w", err)
@@ -141,6 +155,25 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				listeners[i] = l
 			}
 
+			if len(remoteSpecs) > 0 {
+				sshClient, err := conn.SSHClient(ctx)
+				if err != nil {
+					return xerrors.Errorf("ssh client: %w", err)
+				}
+				defer sshClient.Close()
+
+				for _, spec := range remoteSpecs {
+					_, _ = fmt.Fprintf(inv.Stderr, "Forwarding %s\n", formatRemoteForward(spec))
+					l, err := sshForwardRemote(ctx, inv.Stderr, sshClient, spec)
+					if err != nil {
+						return xerrors.Errorf("remote forward %s: %w", formatRemoteForward(spec), err)
+					}
+					// The remote listeners are closed with the others on
+					// exit.
+					listeners = append(listeners, l)
+				}
+			}
+
 			// Wait for the context to be canceled or for a signal and close
 			// all listeners.
 			var closeErr error
@@ -183,6 +216,12 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			Description: "Forward UDP port(s) from the workspace to the local machine. The UDP connection has TCP-like semantics to support stateful UDP protocols.",
 			Value:       clibase.StringArrayOf(&udpForwards),
 		},
+		{
+			Flag:        "remote",
+			Env:         "CODER_PORT_FORWARD_REMOTE",
+			Description: "Forward a port or Unix socket in the workspace to the local machine, in the same format as the -R flag of OpenSSH.",
+			Value:       clibase.StringArrayOf(&remoteForwards),
+		},
 	}
 
 	return cmd
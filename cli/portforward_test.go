@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 
@@ -288,6 +290,67 @@ func TestPortForward(t *testing.T) {
 	})
 }
 
+func TestPortForward_Remote(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client    = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user      = coderdtest.CreateFirstUser(t, client)
+		workspace = runAgent(t, client, user.UserID)
+	)
+
+	// The service on the "local machine".
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	localPort := setupTestListener(t, l)
+
+	// Reserve a port for the workspace side of the TCP forward.
+	rl, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	remoteAddr := rl.Addr().String()
+	_, remotePort, err := net.SplitHostPort(remoteAddr)
+	require.NoError(t, err)
+	_ = rl.Close()
+
+	flags := []string{"--remote", remotePort + ":" + localPort}
+	var remoteSocket string
+	if runtime.GOOS != "windows" {
+		remoteSocket = filepath.Join(tempDirUnixSocket(t), "remote.sock")
+		flags = append(flags, "--remote", remoteSocket+":127.0.0.1:"+localPort)
+	}
+
+	inv, root := clitest.New(t, append([]string{"port-forward", workspace.Name}, flags...)...)
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t)
+	inv.Stdin = pty.Input()
+	inv.Stdout = pty.Output()
+	inv.Stderr = pty.Output()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	errC := make(chan error)
+	go func() {
+		errC <- inv.WithContext(ctx).Run()
+	}()
+	pty.ExpectMatchContext(ctx, "Ready!")
+
+	d := net.Dialer{Timeout: testutil.WaitShort}
+	c1, err := d.DialContext(ctx, "tcp", remoteAddr)
+	require.NoError(t, err, "dial forwarded port in workspace")
+	defer c1.Close()
+	testDial(t, c1)
+
+	if remoteSocket != "" {
+		c2, err := d.DialContext(ctx, "unix", remoteSocket)
+		require.NoError(t, err, "dial forwarded socket in workspace")
+		defer c2.Close()
+		testDial(t, c2)
+	}
+
+	cancel()
+	err = <-errC
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 // runAgent creates a fake workspace and starts an agent locally for that
 // workspace. The agent will be cleaned up on test completion.
 // nolint:unused
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) processes() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "processes",
+		Short:       "Inspect and kill processes running in a workspace",
+		Long: "The agent periodically reports the processes using the most CPU and memory, " +
+			"along with the resource usage of each SSH and terminal session.\n\n" + formatExamples(
+			example{
+				Description: "List the top processes of a workspace",
+				Command:     "coder processes list my-workspace",
+			},
+			example{
+				Description: "Kill a runaway process",
+				Command:     "coder processes kill my-workspace 1234 --signal kill",
+			},
+		),
+		Aliases: []string{"process", "ps"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.listProcesses(),
+			r.killProcess(),
+		},
+	}
+	return cmd
+}
+
+// processListRow is the type provided to the OutputFormatter.
+type processListRow struct {
+	// For JSON format:
+	codersdk.WorkspaceAgentProcess `table:"-"`
+
+	// For table format:
+	PID     int32  `json:"-" table:"pid,default_sort"`
+	PPID    int32  `json:"-" table:"ppid"`
+	Name    string `json:"-" table:"name"`
+	CPU     string `json:"-" table:"cpu"`
+	Memory  string `json:"-" table:"memory"`
+	Session string `json:"-" table:"session"`
+	Command string `json:"-" table:"command"`
+}
+
+func processListRowFromProcess(process codersdk.WorkspaceAgentProcess, sessions map[int32]codersdk.WorkspaceAgentSessionUsage) processListRow {
+	session := ""
+	if s, ok := sessions[process.SessionPID]; ok {
+		session = fmt.Sprintf("%s (%d)", s.Type, s.PID)
+	}
+	return processListRow{
+		WorkspaceAgentProcess: process,
+		PID:                   process.PID,
+		PPID:                  process.PPID,
+		Name:                  process.Name,
+		CPU:                   fmt.Sprintf("%.2f cores", process.CPUCores),
+		Memory:                formatByteSize(process.MemoryBytes),
+		Session:               session,
+		Command:               process.Cmdline,
+	}
+}
+
+func (r *RootCmd) listProcesses() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]processListRow{}, []string{"pid", "ppid", "name", "cpu", "memory", "session", "command"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the top processes of a workspace by CPU and memory usage",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			processes, err := client.WorkspaceAgentProcesses(ctx, workspaceAgent.ID)
+			if err != nil {
+				return xerrors.Errorf("get processes: %w", err)
+			}
+
+			cliui.Infof(inv.Stderr, "Collected %s ago: %d processes, CPU %s, memory %s\n",
+				time.Since(processes.CollectedAt).Round(time.Second),
+				processes.ProcessCount,
+				formatResourceUsage(processes.CPU, func(v float64) string { return fmt.Sprintf("%.2f", v) }),
+				formatResourceUsage(processes.Memory, func(v float64) string { return formatByteSize(int64(v)) }),
+			)
+
+			sessions := make(map[int32]codersdk.WorkspaceAgentSessionUsage, len(processes.Sessions))
+			for _, session := range processes.Sessions {
+				sessions[session.PID] = session
+			}
+			rows := make([]processListRow, len(processes.Processes))
+			for i, process := range processes.Processes {
+				rows[i] = processListRowFromProcess(process, sessions)
+			}
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func formatResourceUsage(usage codersdk.WorkspaceAgentResourceUsage, format func(float64) string) string {
+	if usage.Total == nil {
+		return format(usage.Used)
+	}
+	return fmt.Sprintf("%s/%s", format(usage.Used), format(*usage.Total))
+}
+
+func (r *RootCmd) killProcess() *clibase.Cmd {
+	var signal string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "kill <workspace> <pid>",
+		Short: "Send a signal to a process in a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			{
+				Flag:          "signal",
+				FlagShorthand: "s",
+				Description:   "The signal to send to the process.",
+				Default:       string(codersdk.WorkspaceAgentProcessSignalTerminate),
+				Value: clibase.EnumOf(&signal,
+					string(codersdk.WorkspaceAgentProcessSignalTerminate),
+					string(codersdk.WorkspaceAgentProcessSignalKill),
+				),
+			},
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			pid, err := strconv.ParseInt(inv.Args[1], 10, 32)
+			if err != nil || pid <= 0 {
+				return xerrors.Errorf("invalid process ID %q", inv.Args[1])
+			}
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			err = client.WorkspaceAgentKillProcess(ctx, workspaceAgent.ID, int32(pid), codersdk.KillWorkspaceAgentProcessRequest{
+				Signal: codersdk.WorkspaceAgentProcessSignal(signal),
+			})
+			if err != nil {
+				return xerrors.Errorf("kill process: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Sent %s to process %d\n", signal, pid)
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,53 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestProcesses(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	err := agentClient.PostProcesses(ctx, codersdk.WorkspaceAgentProcesses{
+		CollectedAt:  time.Now(),
+		CPU:          codersdk.WorkspaceAgentResourceUsage{Used: 1.5, Unit: "cores"},
+		Memory:       codersdk.WorkspaceAgentResourceUsage{Used: 1 << 30, Unit: "B"},
+		ProcessCount: 2,
+		Sessions: []codersdk.WorkspaceAgentSessionUsage{{
+			Type:         "ssh",
+			PID:          42,
+			ProcessCount: 1,
+		}},
+		Processes: []codersdk.WorkspaceAgentProcess{
+			{PID: 1, Name: "init"},
+			{PID: 42, PPID: 1, Name: "runaway", Cmdline: "runaway --forever", CPUCores: 1.5, MemoryBytes: 1 << 30, SessionPID: 42},
+		},
+	})
+	require.NoError(t, err)
+
+	inv, root := clitest.New(t, "processes", "list", workspace.Name)
+	clitest.SetupConfig(t, client, root)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	require.NoError(t, inv.WithContext(ctx).Run())
+	require.Contains(t, stdout.String(), "runaway --forever")
+	require.Contains(t, stdout.String(), "1.50 cores")
+	require.Contains(t, stdout.String(), "1.0 GiB")
+	require.Contains(t, stdout.String(), "ssh (42)")
+}
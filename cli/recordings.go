@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) recordings() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "recordings",
+		Short:       "List and download recorded terminal sessions of a workspace",
+		Long: "Sessions are recorded when the deployment enables --session-recording. " +
+			"Recordings are in asciicast v2 format and can be replayed with asciinema.\n\n" + formatExamples(
+			example{
+				Description: "List the recorded sessions of a workspace",
+				Command:     "coder recordings list my-workspace",
+			},
+			example{
+				Description: "Download a recording and replay it",
+				Command:     "coder recordings download my-workspace <id> -o session.cast && asciinema play session.cast",
+			},
+		),
+		Aliases: []string{"recording"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.listRecordings(),
+			r.downloadRecording(),
+		},
+	}
+	return cmd
+}
+
+// recordingListRow is the type provided to the OutputFormatter.
+type recordingListRow struct {
+	// For JSON format:
+	codersdk.SessionRecording `table:"-"`
+
+	// For table format:
+	ID        string    `json:"-" table:"id"`
+	Type      string    `json:"-" table:"type"`
+	StartedAt time.Time `json:"-" table:"started at,default_sort"`
+	Duration  string    `json:"-" table:"duration"`
+	Size      string    `json:"-" table:"size"`
+}
+
+func recordingListRowFromRecording(recording codersdk.SessionRecording) recordingListRow {
+	duration := "in progress"
+	if recording.EndedAt != nil {
+		duration = recording.EndedAt.Sub(recording.StartedAt).Round(time.Second).String()
+	}
+	return recordingListRow{
+		SessionRecording: recording,
+		ID:               recording.ID.String(),
+		Type:             string(recording.Type),
+		StartedAt:        recording.StartedAt,
+		Duration:         duration,
+		Size:             formatByteSize(recording.Size),
+	}
+}
+
+func (r *RootCmd) listRecordings() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]recordingListRow{}, []string{"id", "type", "started at", "duration", "size"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the recorded sessions of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			recordings, err := client.WorkspaceSessionRecordings(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list session recordings: %w", err)
+			}
+			if len(recordings) == 0 {
+				cliui.Infof(inv.Stderr, "No session recordings found.\n")
+				return nil
+			}
+
+			rows := make([]recordingListRow, len(recordings))
+			for i, recording := range recordings {
+				rows[i] = recordingListRowFromRecording(recording)
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) downloadRecording() *clibase.Cmd {
+	var output string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "download <workspace> <id>",
+		Short: "Download a session recording in asciicast v2 format",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			recordingID, err := uuid.Parse(inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("parse recording id: %w", err)
+			}
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			recording, err := client.WorkspaceSessionRecording(inv.Context(), workspace.ID, recordingID)
+			if err != nil {
+				return xerrors.Errorf("download session recording: %w", err)
+			}
+			defer recording.Close()
+
+			if output == "-" {
+				_, err = io.Copy(inv.Stdout, recording)
+				return err
+			}
+			if output == "" {
+				output = recordingID.String() + ".cast"
+			}
+			file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return xerrors.Errorf("create %s: %w", output, err)
+			}
+			defer file.Close()
+			_, err = io.Copy(file, recording)
+			if err != nil {
+				return xerrors.Errorf("write %s: %w", output, err)
+			}
+			_, _ = fmt.Fprintf(inv.Stderr, "Wrote recording to %s\n", output)
+			return file.Close()
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   `File to write the recording to, "-" writes to stdout. Defaults to "<id>.cast".`,
+			Value:         clibase.StringOf(&output),
+		},
+	}
+	return cmd
+}
+
+func formatByteSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestRecordings(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	recordingID := uuid.New()
+	recording := "{\"version\":2,\"width\":80,\"height\":24,\"timestamp\":0}\n[0.1,\"o\",\"hello\"]\n"
+	err := agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+		ID:        recordingID,
+		Type:      codersdk.SessionRecordingTypeSSH,
+		StartedAt: time.Now(),
+		Data:      []byte(recording),
+		Final:     true,
+	})
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, "recordings", "list", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, stdout.String(), recordingID.String())
+		require.Contains(t, stdout.String(), "ssh")
+	})
+
+	t.Run("DownloadStdout", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, "recordings", "download", workspace.Name, recordingID.String(), "-o", "-")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Equal(t, recording, stdout.String())
+	})
+
+	t.Run("DownloadFile", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		output := filepath.Join(t.TempDir(), "session.cast")
+		inv, root := clitest.New(t, "recordings", "download", workspace.Name, recordingID.String(), "-o", output)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		require.Equal(t, recording, string(data))
+	})
+}
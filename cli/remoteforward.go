@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/agent/agentssh"
+)
+
+// remoteForwardSpec describes a listener in the workspace whose connections
+// are forwarded to an address on the local machine.
+type remoteForwardSpec struct {
+	remote net.Addr // Listened on in the workspace.
+	local  net.Addr // Dialed on the local machine.
+}
+
+// parseRemoteForward parses a remote forward in the same format as the
+// OpenSSH -R flag:
+//
+//	[bind_address:]port:host:hostport
+//	[bind_address:]port:local_socket
+//	remote_socket:host:hostport
+//	remote_socket:local_socket
+//
+// As a shorthand, "port:hostport" forwards to hostport on localhost. Paths
+// must be absolute to be recognized as Unix sockets, and IPv6 addresses are
+// not supported.
+func parseRemoteForward(in string) (remoteForwardSpec, error) {
+	parts := strings.Split(in, ":")
+	isSocket := func(s string) bool {
+		return strings.HasPrefix(s, "/")
+	}
+	tcpAddr := func(host, port string) (net.Addr, error) {
+		p, err := parsePort(port)
+		if err != nil {
+			return nil, err
+		}
+		if host == "" || host == "localhost" {
+			host = "127.0.0.1"
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return nil, xerrors.Errorf("invalid ip %q", host)
+		}
+		return &net.TCPAddr{IP: ip, Port: int(p)}, nil
+	}
+	unixAddr := func(path string) net.Addr {
+		return &net.UnixAddr{Name: path, Net: "unix"}
+	}
+
+	var (
+		spec remoteForwardSpec
+		err  error
+	)
+	switch len(parts) {
+	case 2:
+		// remote_socket:local_socket, port:local_socket,
+		// remote_socket:hostport or port:hostport.
+		if isSocket(parts[0]) {
+			spec.remote = unixAddr(parts[0])
+		} else {
+			spec.remote, err = tcpAddr("", parts[0])
+		}
+		if err != nil {
+			break
+		}
+		if isSocket(parts[1]) {
+			spec.local = unixAddr(parts[1])
+		} else {
+			spec.local, err = tcpAddr("", parts[1])
+		}
+	case 3:
+		switch {
+		case isSocket(parts[0]):
+			spec.remote = unixAddr(parts[0])
+			spec.local, err = tcpAddr(parts[1], parts[2])
+		case isSocket(parts[2]):
+			spec.remote, err = tcpAddr(parts[0], parts[1])
+			spec.local = unixAddr(parts[2])
+		default:
+			spec.remote, err = tcpAddr("", parts[0])
+			if err != nil {
+				break
+			}
+			spec.local, err = tcpAddr(parts[1], parts[2])
+		}
+	case 4:
+		spec.remote, err = tcpAddr(parts[0], parts[1])
+		if err != nil {
+			break
+		}
+		spec.local, err = tcpAddr(parts[2], parts[3])
+	default:
+		err = xerrors.New("expected 2 to 4 colon separated parts")
+	}
+	if err != nil {
+		return remoteForwardSpec{}, xerrors.Errorf("invalid remote forward specification %q: %w", in, err)
+	}
+	return spec, nil
+}
+
+// parseRemoteForwards parses every specification and checks that no
+// workspace address is listened on twice.
+func parseRemoteForwards(in []string) ([]remoteForwardSpec, error) {
+	specs := make([]remoteForwardSpec, 0, len(in))
+	remotes := map[string]struct{}{}
+	for _, s := range in {
+		spec, err := parseRemoteForward(s)
+		if err != nil {
+			return nil, err
+		}
+		key := spec.remote.Network() + ":" + spec.remote.String()
+		if _, ok := remotes[key]; ok {
+			return nil, xerrors.Errorf("remote %v %v is specified twice", spec.remote.Network(), spec.remote.String())
+		}
+		remotes[key] = struct{}{}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// sshForwardRemote starts forwarding connections from a listener in the
+// workspace to a local address via SSH in a goroutine. Closing the returned
+// listener stops forwarding.
+func sshForwardRemote(ctx context.Context, stderr io.Writer, sshClient *gossh.Client, spec remoteForwardSpec) (net.Listener, error) {
+	var (
+		listener net.Listener
+		err      error
+	)
+	switch addr := spec.remote.(type) {
+	case *net.UnixAddr:
+		listener, err = sshClient.ListenUnix(addr.Name)
+	default:
+		listener, err = sshClient.Listen(addr.Network(), addr.String())
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("listen on remote SSH address %s: %w", spec.remote.String(), err)
+	}
+
+	go func() {
+		for {
+			remoteConn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() == nil && !xerrors.Is(err, io.EOF) {
+					_, _ = fmt.Fprintf(stderr, "Accept SSH listener connection: %+v\n", err)
+				}
+				return
+			}
+
+			go func() {
+				defer remoteConn.Close()
+
+				localConn, err := dialLocal(spec.local)
+				if err != nil {
+					_, _ = fmt.Fprintf(stderr, "%+v\n", err)
+					return
+				}
+				defer localConn.Close()
+
+				agentssh.Bicopy(ctx, localConn, remoteConn)
+			}()
+		}
+	}()
+
+	return listener, nil
+}
+
+// dialLocal dials the local address of a forward. If it's a `cookieAddr`, the
+// cookie is written to the connection before it's returned.
+func dialLocal(addr net.Addr) (net.Conn, error) {
+	conn, err := net.Dial(addr.Network(), addr.String())
+	if err != nil {
+		return nil, xerrors.Errorf("dial local address %s: %w", addr.String(), err)
+	}
+	if c, ok := addr.(cookieAddr); ok {
+		_, err = conn.Write(c.cookie)
+		if err != nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("write cookie to local connection: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func formatRemoteForward(spec remoteForwardSpec) string {
+	return fmt.Sprintf("'%v://%v' in the workspace to '%v://%v' locally",
+		spec.remote.Network(), spec.remote.String(),
+		spec.local.Network(), spec.local.String())
+}
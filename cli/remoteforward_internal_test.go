@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRemoteForward(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		remote  string
+		local   string
+		wantErr bool
+	}{
+		{in: "8080:3000", remote: "tcp://127.0.0.1:8080", local: "tcp://127.0.0.1:3000"},
+		{in: "8080:localhost:3000", remote: "tcp://127.0.0.1:8080", local: "tcp://127.0.0.1:3000"},
+		{in: "0.0.0.0:8080:10.0.0.1:3000", remote: "tcp://0.0.0.0:8080", local: "tcp://10.0.0.1:3000"},
+		{in: "/tmp/docker.sock:/var/run/docker.sock", remote: "unix:///tmp/docker.sock", local: "unix:///var/run/docker.sock"},
+		{in: "/tmp/app.sock:127.0.0.1:3000", remote: "unix:///tmp/app.sock", local: "tcp://127.0.0.1:3000"},
+		{in: "8080:/var/run/docker.sock", remote: "tcp://127.0.0.1:8080", local: "unix:///var/run/docker.sock"},
+		{in: "0.0.0.0:8080:/var/run/docker.sock", remote: "tcp://0.0.0.0:8080", local: "unix:///var/run/docker.sock"},
+		{in: "8080", wantErr: true},
+		{in: "0:3000", wantErr: true},
+		{in: "8080:example.com:3000", wantErr: true},
+		{in: "a:b:c:d:e", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			spec, err := parseRemoteForward(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.remote, spec.remote.Network()+"://"+spec.remote.String())
+			require.Equal(t, tt.local, spec.local.Network()+"://"+spec.local.String())
+		})
+	}
+
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseRemoteForwards([]string{"8080:3000", "8080:4000"})
+		require.ErrorContains(t, err, "specified twice")
+	})
+}
@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) roles() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "roles",
+		Short: "Manage custom roles",
+		Long: "Custom roles grant permissions on resources in addition to the built-in roles. " +
+			"Permissions are specified as \"<resource>:<action>\", and are denied instead of allowed when prefixed with \"!\".\n" + formatExamples(
+			example{
+				Description: "Create a site role that can read the audit logs",
+				Command:     "coder roles create auditor-lite --site-permission audit_log:read",
+			},
+			example{
+				Description: "Create an organization role that can start and stop any workspace of the organization, but not delete it",
+				Command:     "coder roles create restarter --org --org-permission workspace:read --org-permission workspace:update",
+			},
+			example{
+				Description: "List the custom roles of the current organization",
+				Command:     "coder roles ls --org",
+			},
+		),
+		Aliases: []string{"role"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.createRole(),
+			r.editRole(),
+			r.listRoles(),
+			r.deleteRole(),
+		},
+	}
+	return cmd
+}
+
+// roleOrganizationOption is the flag to manage the roles of the current
+// organization instead of the site roles.
+func roleOrganizationOption(org *bool) clibase.Option {
+	return clibase.Option{
+		Flag:        "org",
+		Description: "Manage the custom roles of the current organization instead of the site roles.",
+		Value:       clibase.BoolOf(org),
+	}
+}
+
+// roleOrganizationID returns the ID of the current organization if org is
+// set, and uuid.Nil for site roles otherwise.
+func roleOrganizationID(inv *clibase.Invocation, client *codersdk.Client, org bool) (uuid.UUID, error) {
+	if !org {
+		return uuid.Nil, nil
+	}
+	organization, err := CurrentOrganization(inv, client)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get current organization: %w", err)
+	}
+	return organization.ID, nil
+}
+
+// parsePermissions parses permissions in the "[!]<resource>:<action>" form.
+func parsePermissions(values []string) ([]codersdk.Permission, error) {
+	perms := make([]codersdk.Permission, 0, len(values))
+	for _, value := range values {
+		negate := strings.HasPrefix(value, "!")
+		resource, action, ok := strings.Cut(strings.TrimPrefix(value, "!"), ":")
+		if !ok || resource == "" || action == "" {
+			return nil, xerrors.Errorf("invalid permission %q, must be in the form [!]<resource>:<action>", value)
+		}
+		perms = append(perms, codersdk.Permission{
+			Negate:       negate,
+			ResourceType: codersdk.RBACResource(resource),
+			Action:       action,
+		})
+	}
+	return perms, nil
+}
+
+func formatPermissions(perms []codersdk.Permission) string {
+	formatted := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		value := fmt.Sprintf("%s:%s", perm.ResourceType, perm.Action)
+		if perm.Negate {
+			value = "!" + value
+		}
+		formatted = append(formatted, value)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// rolePermissionOptions are the flags setting the permissions of a role.
+func rolePermissionOptions(site, org, user *[]string) clibase.OptionSet {
+	return clibase.OptionSet{
+		{
+			Flag:        "site-permission",
+			Description: "A permission granted site wide, in the form [!]<resource>:<action>. Site roles only.",
+			Value:       clibase.StringArrayOf(site),
+		},
+		{
+			Flag:        "org-permission",
+			Description: "A permission granted in the organization of the role, in the form [!]<resource>:<action>. Organization roles only.",
+			Value:       clibase.StringArrayOf(org),
+		},
+		{
+			Flag:        "user-permission",
+			Description: "A permission granted on the resources owned by the user the role is assigned to, in the form [!]<resource>:<action>. Site roles only.",
+			Value:       clibase.StringArrayOf(user),
+		},
+	}
+}
+
+func (r *RootCmd) createRole() *clibase.Cmd {
+	var (
+		org             bool
+		displayName     string
+		sitePermissions []string
+		orgPermissions  []string
+		userPermissions []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a custom role",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			organizationID, err := roleOrganizationID(inv, client, org)
+			if err != nil {
+				return err
+			}
+			req := codersdk.CreateCustomRoleRequest{
+				Name:        inv.Args[0],
+				DisplayName: displayName,
+			}
+			if req.SitePermissions, err = parsePermissions(sitePermissions); err != nil {
+				return err
+			}
+			if req.OrganizationPermissions, err = parsePermissions(orgPermissions); err != nil {
+				return err
+			}
+			if req.UserPermissions, err = parsePermissions(userPermissions); err != nil {
+				return err
+			}
+
+			role, err := client.CreateCustomRole(inv.Context(), organizationID, req)
+			if err != nil {
+				return xerrors.Errorf("create custom role: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Created role %s! Assign it as %q.\n", cliui.DefaultStyles.Keyword.Render(role.Name), role.RoleName())
+			return nil
+		},
+	}
+
+	cmd.Options = append(clibase.OptionSet{
+		roleOrganizationOption(&org),
+		{
+			Flag:        "display-name",
+			Description: "The display name of the role. Defaults to the name.",
+			Value:       clibase.StringOf(&displayName),
+		},
+	}, rolePermissionOptions(&sitePermissions, &orgPermissions, &userPermissions)...)
+	return cmd
+}
+
+func (r *RootCmd) editRole() *clibase.Cmd {
+	var (
+		org             bool
+		displayName     string
+		sitePermissions []string
+		orgPermissions  []string
+		userPermissions []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name>",
+		Short: "Edit the display name or the permissions of a custom role",
+		Long:  "The permissions given replace the current permissions of the same level. Permissions of levels without flags are kept.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			organizationID, err := roleOrganizationID(inv, client, org)
+			if err != nil {
+				return err
+			}
+			role, err := client.CustomRole(inv.Context(), organizationID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get custom role: %w", err)
+			}
+
+			req := codersdk.UpdateCustomRoleRequest{
+				DisplayName:             role.DisplayName,
+				SitePermissions:         role.SitePermissions,
+				OrganizationPermissions: role.OrganizationPermissions,
+				UserPermissions:         role.UserPermissions,
+			}
+			flags := inv.ParsedFlags()
+			if flags.Changed("display-name") {
+				req.DisplayName = displayName
+			}
+			if flags.Changed("site-permission") {
+				if req.SitePermissions, err = parsePermissions(sitePermissions); err != nil {
+					return err
+				}
+			}
+			if flags.Changed("org-permission") {
+				if req.OrganizationPermissions, err = parsePermissions(orgPermissions); err != nil {
+					return err
+				}
+			}
+			if flags.Changed("user-permission") {
+				if req.UserPermissions, err = parsePermissions(userPermissions); err != nil {
+					return err
+				}
+			}
+
+			_, err = client.UpdateCustomRole(inv.Context(), organizationID, role.Name, req)
+			if err != nil {
+				return xerrors.Errorf("update custom role: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated role %s at %s!\n", cliui.DefaultStyles.Keyword.Render(role.Name), cliui.DefaultStyles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
+			return nil
+		},
+	}
+
+	cmd.Options = append(clibase.OptionSet{
+		roleOrganizationOption(&org),
+		{
+			Flag:        "display-name",
+			Description: "The display name of the role.",
+			Value:       clibase.StringOf(&displayName),
+		},
+	}, rolePermissionOptions(&sitePermissions, &orgPermissions, &userPermissions)...)
+	return cmd
+}
+
+// roleListRow is the type provided to the OutputFormatter.
+type roleListRow struct {
+	// For JSON format:
+	codersdk.CustomRole `table:"-"`
+
+	// For table format:
+	Name                    string `json:"-" table:"name,default_sort"`
+	DisplayName             string `json:"-" table:"display name"`
+	AssignedAs              string `json:"-" table:"assigned as"`
+	SitePermissions         string `json:"-" table:"site permissions"`
+	OrganizationPermissions string `json:"-" table:"org permissions"`
+	UserPermissions         string `json:"-" table:"user permissions"`
+}
+
+func roleListRowFromRole(role codersdk.CustomRole) roleListRow {
+	return roleListRow{
+		CustomRole:              role,
+		Name:                    role.Name,
+		DisplayName:             role.DisplayName,
+		AssignedAs:              role.RoleName(),
+		SitePermissions:         formatPermissions(role.SitePermissions),
+		OrganizationPermissions: formatPermissions(role.OrganizationPermissions),
+		UserPermissions:         formatPermissions(role.UserPermissions),
+	}
+}
+
+func (r *RootCmd) listRoles() *clibase.Cmd {
+	var (
+		org       bool
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]roleListRow{}, []string{"name", "display name", "site permissions", "org permissions", "user permissions"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List custom roles",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			organizationID, err := roleOrganizationID(inv, client, org)
+			if err != nil {
+				return err
+			}
+			roles, err := client.CustomRoles(inv.Context(), organizationID)
+			if err != nil {
+				return xerrors.Errorf("list custom roles: %w", err)
+			}
+			if len(roles) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No custom roles found.\n",
+				)
+			}
+
+			rows := make([]roleListRow, len(roles))
+			for i, role := range roles {
+				rows[i] = roleListRowFromRole(role)
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		roleOrganizationOption(&org),
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) deleteRole() *clibase.Cmd {
+	var org bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a custom role",
+		Long:  "Deleting a role removes it from all users it is assigned to.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			roleOrganizationOption(&org),
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			organizationID, err := roleOrganizationID(inv, client, org)
+			if err != nil {
+				return err
+			}
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete role %s? It will be removed from all users it is assigned to.", cliui.DefaultStyles.Code.Render(inv.Args[0])),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteCustomRole(inv.Context(), organizationID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("delete custom role: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted role %s at %s!\n", cliui.DefaultStyles.Keyword.Render(inv.Args[0]), cliui.DefaultStyles.DateTimeStamp.Render(time.Now().Format(time.Stamp)))
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,119 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SiteRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "roles", "ls")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "No custom roles found")
+
+		inv, root = clitest.New(t, "roles", "create", "auditor-lite", "--site-permission", "audit_log:read", "--user-permission", "!workspace:delete")
+		clitest.SetupConfig(t, client, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "auditor-lite")
+
+		inv, root = clitest.New(t, "roles", "edit", "auditor-lite", "--display-name", "Auditor Lite", "--site-permission", "audit_log:read", "--site-permission", "user:read")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		role, err := client.CustomRole(ctx, uuid.Nil, "auditor-lite")
+		require.NoError(t, err)
+		require.Equal(t, "Auditor Lite", role.DisplayName)
+		require.Equal(t, []codersdk.Permission{
+			{ResourceType: codersdk.ResourceAuditLog, Action: "read"},
+			{ResourceType: codersdk.ResourceUser, Action: "read"},
+		}, role.SitePermissions)
+		// Permissions without flags are kept.
+		require.Equal(t, []codersdk.Permission{
+			{Negate: true, ResourceType: codersdk.ResourceWorkspace, Action: "delete"},
+		}, role.UserPermissions)
+
+		inv, root = clitest.New(t, "roles", "ls")
+		clitest.SetupConfig(t, client, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "audit_log:read, user:read")
+		require.Contains(t, buf.String(), "!workspace:delete")
+
+		inv, root = clitest.New(t, "roles", "rm", "auditor-lite", "--yes")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		roles, err := client.CustomRoles(ctx, uuid.Nil)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+
+	t.Run("OrganizationRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "roles", "create", "restarter", "--org", "--org-permission", "workspace:read", "--org-permission", "workspace:update")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		inv, root = clitest.New(t, "roles", "ls", "--org", "--output=json")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var roles []codersdk.CustomRole
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &roles))
+		require.Len(t, roles, 1)
+		require.Equal(t, "restarter:"+first.OrganizationID.String(), roles[0].RoleName())
+
+		// The role is not a site role.
+		roles, err = client.CustomRoles(ctx, uuid.Nil)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+
+	t.Run("InvalidPermission", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "roles", "create", "broken", "--site-permission", "audit_log")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "must be in the form")
+	})
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/coder/coder/cli/config"
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/gitauth"
+	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -61,6 +62,7 @@ const (
 	varForceTty         = "force-tty"
 	varVerbose          = "verbose"
 	varDisableDirect    = "disable-direct-connections"
+	varContext          = "context"
 	notLoggedInMessage  = "You are not logged in. Try logging in using 'coder login <url>'."
 
 	envNoVersionCheck   = "CODER_NO_VERSION_WARNING"
@@ -69,6 +71,7 @@ const (
 	//nolint:gosec
 	envAgentToken = "CODER_AGENT_TOKEN"
 	envURL        = "CODER_URL"
+	envContext    = "CODER_CONTEXT"
 )
 
 var errUnauthenticated = xerrors.New(notLoggedInMessage)
@@ -76,12 +79,16 @@ var errUnauthenticated = xerrors.New(notLoggedInMessage)
 func (r *RootCmd) Core() []*clibase.Cmd {
 	// Please re-sort this list alphabetically if you change it!
 	return []*clibase.Cmd{
+		r.apply(),
+		r.completion(),
+		r.contexts(),
 		r.dotfiles(),
 		r.login(),
 		r.logout(),
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
+		r.roles(),
 		r.state(),
 		r.templates(),
 		r.users(),
@@ -90,10 +97,16 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 
 		// Workspace Commands
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.exec(),
 		r.list(),
+		r.logs(),
+		r.open(),
 		r.ping(),
+		r.processes(),
+		r.recordings(),
 		r.rename(),
 		r.scaletest(),
 		r.schedules(),
@@ -102,11 +115,13 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.ssh(),
 		r.start(),
 		r.stop(),
+		r.tui(),
 		r.update(),
 		r.restart(),
 		r.stat(),
 
 		// Hidden
+		r.complete(),
 		r.gitssh(),
 		r.netcheck(),
 		r.vscodeSSH(),
@@ -126,6 +141,7 @@ func (r *RootCmd) AGPL() []*clibase.Cmd {
 func (r *RootCmd) RunMain(subcommands []*clibase.Cmd) {
 	rand.Seed(time.Now().UnixMicro())
 
+	subcommands = append(subcommands, r.pluginCommands(os.Getenv("PATH"), subcommands)...)
 	cmd, err := r.Command(subcommands)
 	if err != nil {
 		panic(err)
@@ -133,6 +149,13 @@ func (r *RootCmd) RunMain(subcommands []*clibase.Cmd) {
 
 	err = cmd.Invoke().WithOS().Run()
 	if err != nil {
+		// The command already reported the failure, e.g. the exit code of
+		// a command run in a workspace.
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			//nolint:revive
+			os.Exit(exitErr.code)
+		}
 		if errors.Is(err, cliui.Canceled) {
 			//nolint:revive
 			os.Exit(1)
@@ -174,6 +197,7 @@ func (r *RootCmd) Command(subcommands []*clibase.Cmd) (*clibase.Cmd, error) {
 	}
 
 	cmd.AddSubcommands(subcommands...)
+	r.addCompletionHandlers(cmd)
 
 	// Set default help handler for all commands.
 	cmd.Walk(func(c *clibase.Cmd) {
@@ -390,6 +414,13 @@ func (r *RootCmd) Command(subcommands []*clibase.Cmd) (*clibase.Cmd, error) {
 			Value:       clibase.StringOf(&r.globalConfig),
 			Group:       globalGroup,
 		},
+		{
+			Flag:        varContext,
+			Env:         envContext,
+			Description: "The context in the config directory to use, which stores the URL and session of a deployment. Defaults to the context selected with \"coder context use\".",
+			Value:       clibase.StringOf(&r.context),
+			Group:       globalGroup,
+		},
 	}
 
 	err := cmd.PrepareAll()
@@ -424,6 +455,7 @@ type RootCmd struct {
 	clientURL     *url.URL
 	token         string
 	globalConfig  string
+	context       string
 	header        []string
 	agentToken    string
 	agentURL      *url.URL
@@ -479,6 +511,40 @@ func addTelemetryHeader(client *codersdk.Client, inv *clibase.Invocation) {
 	transport.header.Add(codersdk.CLITelemetryHeader, s)
 }
 
+// session returns the URL and session token of the deployment the CLI is
+// logged in to without contacting it.
+func (r *RootCmd) session() (*url.URL, string, error) {
+	conf, err := r.contextConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	clientURL := r.clientURL
+	if clientURL == nil || clientURL.String() == "" {
+		rawURL, err := conf.URL().Read()
+		if os.IsNotExist(err) {
+			return nil, "", errUnauthenticated
+		}
+		if err != nil {
+			return nil, "", xerrors.Errorf("read URL: %w", err)
+		}
+		clientURL, err = url.Parse(strings.TrimSpace(rawURL))
+		if err != nil {
+			return nil, "", xerrors.Errorf("parse URL: %w", err)
+		}
+	}
+	token := r.token
+	if token == "" {
+		token, err = conf.Session().Read()
+		if os.IsNotExist(err) {
+			return nil, "", errUnauthenticated
+		}
+		if err != nil {
+			return nil, "", xerrors.Errorf("read session token: %w", err)
+		}
+	}
+	return clientURL, strings.TrimSpace(token), nil
+}
+
 // InitClient sets client to a new client.
 // It reads from global configuration files if flags are not set.
 func (r *RootCmd) InitClient(client *codersdk.Client) clibase.MiddlewareFunc {
@@ -490,8 +556,10 @@ func (r *RootCmd) InitClient(client *codersdk.Client) clibase.MiddlewareFunc {
 	}
 	return func(next clibase.HandlerFunc) clibase.HandlerFunc {
 		return func(inv *clibase.Invocation) error {
-			conf := r.createConfig()
-			var err error
+			conf, err := r.contextConfig()
+			if err != nil {
+				return err
+			}
 			if r.clientURL == nil || r.clientURL.String() == "" {
 				rawURL, err := conf.URL().Read()
 				// If the configuration files are absent, the user is logged out
@@ -640,6 +708,37 @@ func (r *RootCmd) createConfig() config.Root {
 	return config.Root(r.globalConfig)
 }
 
+// currentContext returns the name of the context set with the global flag,
+// or else the one selected with "coder context use".
+func (r *RootCmd) currentContext() (string, error) {
+	name := r.context
+	if name == "" {
+		raw, err := r.createConfig().CurrentContext().Read()
+		if err != nil && !os.IsNotExist(err) {
+			return "", xerrors.Errorf("read current context: %w", err)
+		}
+		name = strings.TrimSpace(raw)
+	}
+	if name == "" {
+		return config.DefaultContext, nil
+	}
+	err := httpapi.NameValid(name)
+	if err != nil {
+		return "", xerrors.Errorf("invalid context name %q: %w", name, err)
+	}
+	return name, nil
+}
+
+// contextConfig returns the config root of the current context, which stores
+// the URL and session of the deployment the CLI is logged in to.
+func (r *RootCmd) contextConfig() (config.Root, error) {
+	name, err := r.currentContext()
+	if err != nil {
+		return "", err
+	}
+	return r.createConfig().Context(name), nil
+}
+
 // isTTY returns whether the passed reader is a TTY or not.
 func isTTY(inv *clibase.Invocation) bool {
 	// If the `--force-tty` command is available, and set,
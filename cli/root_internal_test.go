@@ -1,10 +1,17 @@
 package cli
 
 import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
 )
 
 func Test_formatExamples(t *testing.T) {
@@ -66,7 +73,41 @@ func Test_formatExamples(t *testing.T) {
 	}
 }
 
+// envTestRunMain makes the test binary run RunMain instead of the tests,
+// so exit codes can be tested.
+const envTestRunMain = "CODER_TEST_RUN_MAIN"
+
+func TestRunMain_ExitCode(t *testing.T) {
+	t.Parallel()
+
+	//nolint:gosec
+	cmd := exec.Command(os.Args[0], "exit", "42")
+	cmd.Env = append(os.Environ(), envTestRunMain+"=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 42, exitErr.ExitCode())
+	require.Empty(t, stderr.String())
+}
+
 func TestMain(m *testing.M) {
+	if os.Getenv(envTestRunMain) != "" {
+		var r RootCmd
+		r.RunMain([]*clibase.Cmd{{
+			Use: "exit <code>",
+			Handler: func(inv *clibase.Invocation) error {
+				code, err := strconv.Atoi(inv.Args[0])
+				if err != nil {
+					return err
+				}
+				return xerrors.Errorf("run: %w", &exitError{code: code})
+			},
+		}})
+		return
+	}
+
 	goleak.VerifyTestMain(m,
 		// The lumberjack library is used by by agent and seems to leave
 		// goroutines after Close(), fails TestGitSSH tests.
@@ -74,6 +74,7 @@ import (
 	"github.com/coder/coder/coderd/gitsshkey"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/prometheusmetrics"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
@@ -81,6 +82,7 @@ import (
 	"github.com/coder/coder/coderd/unhanger"
 	"github.com/coder/coder/coderd/updatecheck"
 	"github.com/coder/coder/coderd/util/slice"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/cryptorand"
@@ -583,6 +585,8 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 					IgnoreUserInfo:      cfg.OIDC.IgnoreUserInfo.Value(),
 					GroupField:          cfg.OIDC.GroupField.String(),
 					GroupMapping:        cfg.OIDC.GroupMapping.Value,
+					UserRoleField:       cfg.OIDC.UserRoleField.String(),
+					UserRoleMapping:     cfg.OIDC.UserRoleMapping.Value,
 					SignInText:          cfg.OIDC.SignInText.String(),
 					IconURL:             cfg.OIDC.IconURL.String(),
 					IgnoreEmailVerified: cfg.OIDC.IgnoreEmailVerified.Value(),
@@ -763,6 +767,42 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.SwaggerEndpoint = cfg.Swagger.Enable.Value()
 			}
 
+			if cfg.Notifications.Enabled() {
+				var (
+					dispatchers = map[database.NotificationMethod]notifications.Dispatcher{}
+					methods     []database.NotificationMethod
+				)
+				if cfg.Notifications.Email.Enabled() {
+					dispatcher, err := notifications.NewSMTPDispatcher(cfg.Notifications.Email)
+					if err != nil {
+						return xerrors.Errorf("configure email notifications: %w", err)
+					}
+					dispatchers[database.NotificationMethodEmail] = dispatcher
+					methods = append(methods, database.NotificationMethodEmail)
+				}
+				if cfg.Notifications.Webhook.Enabled() {
+					dispatchers[database.NotificationMethodWebhook] = notifications.NewWebhookDispatcher(cfg.Notifications.Webhook.Endpoint.Value(), httpClient)
+					methods = append(methods, database.NotificationMethodWebhook)
+				}
+				options.NotificationsEnqueuer = notifications.NewStoreEnqueuer(options.Database, logger, methods...)
+
+				notificationsTicker := time.NewTicker(notifications.FetchInterval)
+				defer notificationsTicker.Stop()
+				notificationsManager := notifications.NewManager(
+					ctx, options.Database, logger, cfg.AccessURL.Value(), dispatchers,
+					int(cfg.Notifications.MaxSendAttempts.Value()), notificationsTicker.C,
+				)
+				notificationsManager.Start()
+				defer notificationsManager.Close()
+			}
+
+			options.WebhooksEnqueuer = webhooks.NewStoreEnqueuer(options.Database, options.Pubsub, logger)
+			webhooksTicker := time.NewTicker(webhooks.FetchInterval)
+			defer webhooksTicker.Stop()
+			webhooksManager := webhooks.NewManager(ctx, options.Database, options.Pubsub, logger, httpClient, webhooksTicker.C)
+			webhooksManager.Start()
+			defer webhooksManager.Close()
+
 			// We use a separate coderAPICloser so the Enterprise API
 			// can have it's own close functions. This is cleaner
 			// than abstracting the Coder API itself.
@@ -901,7 +941,8 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 			autobuildTicker := time.NewTicker(cfg.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
-			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C)
+			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C).
+				WithNotificationsEnqueuer(options.NotificationsEnqueuer)
 			autobuildExecutor.Run()
 
 			hangDetectorTicker := time.NewTicker(cfg.JobHangDetectorInterval.Value())
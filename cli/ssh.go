@@ -53,6 +53,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		waitEnum       string
 		noWait         bool
 		logDirPath     string
+		remoteForwards []string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -122,6 +123,14 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				client.Logger = logger
 			}
 
+			remoteForwardSpecs, err := parseRemoteForwards(remoteForwards)
+			if err != nil {
+				return err
+			}
+			if stdio && len(remoteForwardSpecs) > 0 {
+				return xerrors.New("remote forwarding is not supported with --stdio, use the RemoteForward option of your SSH client instead")
+			}
+
 			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
 			if err != nil {
 				return err
@@ -309,6 +318,20 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				defer closer.Close()
 			}
 
+			remoteForwardClosers := make([]io.Closer, 0, len(remoteForwardSpecs))
+			defer func() {
+				for _, closer := range remoteForwardClosers {
+					_ = closer.Close()
+				}
+			}()
+			for _, spec := range remoteForwardSpecs {
+				closer, err := sshForwardRemote(ctx, inv.Stderr, sshClient, spec)
+				if err != nil {
+					return xerrors.Errorf("remote forward %s: %w", formatRemoteForward(spec), err)
+				}
+				remoteForwardClosers = append(remoteForwardClosers, closer)
+			}
+
 			stdoutFile, validOut := inv.Stdout.(*os.File)
 			stdinFile, validIn := inv.Stdin.(*os.File)
 			if validOut && validIn && isatty.IsTerminal(stdoutFile.Fd()) {
@@ -433,6 +456,13 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			FlagShorthand: "l",
 			Value:         clibase.StringOf(&logDirPath),
 		},
+		{
+			Flag:          "remote-forward",
+			FlagShorthand: "R",
+			Env:           "CODER_SSH_REMOTE_FORWARD",
+			Description:   "Forward a port or Unix socket in the workspace to the local machine, in the same format as the -R flag of OpenSSH, e.g. \"8080:3000\" or \"/var/run/docker.sock:/var/run/docker.sock\".",
+			Value:         clibase.StringArrayOf(&remoteForwards),
+		},
 	}
 	return cmd
 }
@@ -762,55 +792,79 @@ func remoteGPGAgentSocket(sshClient *gossh.Client) (string, error) {
 	return string(bytes.TrimSpace(remoteSocket)), nil
 }
 
-// cookieAddr is a special net.Addr accepted by sshForward() which includes a
+// cookieAddr is a special net.Addr accepted by dialLocal() which includes a
 // cookie which is written to the connection before forwarding.
 type cookieAddr struct {
 	net.Addr
 	cookie []byte
 }
 
-// sshForwardRemote starts forwarding connections from a remote listener to a
-// local address via SSH in a goroutine.
+// closerFunc is an io.Closer that calls the function.
+type closerFunc func()
+
+func (f closerFunc) Close() error {
+	f()
+	return nil
+}
+
+// sshForwardGPGAgent starts forwarding connections to the GPG agent socket in
+// the workspace to a local address via SSH in a goroutine. The workspace
+// agent is asked to forward the connections over dedicated channels, and if
+// it rejects the request (e.g. it's outdated), the socket is forwarded with
+// sshForwardRemote() instead.
 //
 // Accepts a `cookieAddr` as the local address.
-func sshForwardRemote(ctx context.Context, stderr io.Writer, sshClient *gossh.Client, localAddr, remoteAddr net.Addr) (io.Closer, error) {
-	listener, err := sshClient.Listen(remoteAddr.Network(), remoteAddr.String())
+func sshForwardGPGAgent(ctx context.Context, stderr io.Writer, sshClient *gossh.Client, localAddr net.Addr) (io.Closer, error) {
+	channels := sshClient.HandleChannelOpen(agentssh.GPGAgentChannelType)
+	if channels == nil {
+		return nil, xerrors.New("GPG agent forwarding is already set up for this connection")
+	}
+	ok, _, err := sshClient.SendRequest(agentssh.GPGAgentRequestType, true, nil)
 	if err != nil {
-		return nil, xerrors.Errorf("listen on remote SSH address %s: %w", remoteAddr.String(), err)
+		return nil, xerrors.Errorf("request GPG agent forwarding: %w", err)
+	}
+	if !ok {
+		remoteSocket, err := remoteGPGAgentSocket(sshClient)
+		if err != nil {
+			return nil, err
+		}
+		return sshForwardRemote(ctx, stderr, sshClient, remoteForwardSpec{
+			remote: &net.UnixAddr{
+				Name: remoteSocket,
+				Net:  "unix",
+			},
+			local: localAddr,
+		})
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
 	go func() {
-		for {
-			remoteConn, err := listener.Accept()
+		for newChannel := range channels {
+			if ctx.Err() != nil {
+				_ = newChannel.Reject(gossh.Prohibited, "GPG agent forwarding is closed")
+				continue
+			}
+			channel, reqs, err := newChannel.Accept()
 			if err != nil {
-				if ctx.Err() == nil {
-					_, _ = fmt.Fprintf(stderr, "Accept SSH listener connection: %+v\n", err)
-				}
-				return
+				_, _ = fmt.Fprintf(stderr, "Accept GPG agent channel: %+v\n", err)
+				continue
 			}
+			go gossh.DiscardRequests(reqs)
 
 			go func() {
-				defer remoteConn.Close()
+				defer channel.Close()
 
-				localConn, err := net.Dial(localAddr.Network(), localAddr.String())
+				localConn, err := dialLocal(localAddr)
 				if err != nil {
-					_, _ = fmt.Fprintf(stderr, "Dial local address %s: %+v\n", localAddr.String(), err)
+					_, _ = fmt.Fprintf(stderr, "%+v\n", err)
 					return
 				}
 				defer localConn.Close()
 
-				if c, ok := localAddr.(cookieAddr); ok {
-					_, err = localConn.Write(c.cookie)
-					if err != nil {
-						_, _ = fmt.Fprintf(stderr, "Write cookie to local connection: %+v\n", err)
-						return
-					}
-				}
-
-				agentssh.Bicopy(ctx, localConn, remoteConn)
+				agentssh.Bicopy(ctx, localConn, channel)
 			}()
 		}
 	}()
 
-	return listener, nil
+	return closerFunc(cancel), nil
 }
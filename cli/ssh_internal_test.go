@@ -1,13 +1,29 @@
 package cli
 
 import (
+	"context"
+	"io"
+	"net"
 	"net/url"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/gliderlabs/ssh"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	gossh "golang.org/x/crypto/ssh"
 
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent/agentssh"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
 )
 
 const (
@@ -56,3 +72,166 @@ func TestBuildWorkspaceLink(t *testing.T) {
 
 	assert.Equal(t, workspaceLink.String(), fakeServerURL+"/@"+fakeOwnerName+"/"+fakeWorkspaceName)
 }
+
+func TestSSHForwardGPGAgent(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("GPG agent forwarding is not supported on Windows workspaces")
+	}
+
+	t.Run("Channel", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("gpgconf"); err != nil {
+			t.Skip("gpgconf not found in PATH")
+		}
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		srv, err := agentssh.NewServer(ctx, slogtest.Make(t, nil), prometheus.NewRegistry(), afero.NewMemMapFs(), 0, "")
+		require.NoError(t, err)
+		defer srv.Close()
+		srv.AgentToken = func() string { return "" }
+		srv.Manifest = atomic.NewPointer(&agentsdk.Manifest{
+			EnvironmentVariables: map[string]string{"GNUPGHOME": t.TempDir()},
+		})
+		sshClient := serveSSH(t, srv.Serve)
+
+		localAddr, conns := listenLocalGPGAgent(t)
+		closer, err := sshForwardGPGAgent(ctx, io.Discard, sshClient, localAddr)
+		require.NoError(t, err)
+		defer closer.Close()
+
+		remoteSocket, err := remoteGPGAgentSocket(sshClient)
+		require.NoError(t, err)
+		remoteConn, err := net.Dial("unix", remoteSocket)
+		require.NoError(t, err)
+		defer remoteConn.Close()
+		requireForwarded(ctx, t, remoteConn, conns, "")
+	})
+
+	t.Run("FallbackToRemoteForward", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Stand in for an agent that doesn't know the GPG agent request.
+		remoteSocket := filepath.Join(t.TempDir(), "S.gpg-agent")
+		serverConns := make(chan *gossh.ServerConn, 1)
+		srv := &ssh.Server{
+			Handler: func(s ssh.Session) {
+				assert.Equal(t, "gpgconf --list-dir agent-socket", s.RawCommand())
+				_, _ = io.WriteString(s, remoteSocket+"\n")
+			},
+			RequestHandlers: map[string]ssh.RequestHandler{
+				"streamlocal-forward@openssh.com": func(ctx ssh.Context, _ *ssh.Server, req *gossh.Request) (bool, []byte) {
+					var payload struct{ SocketPath string }
+					if !assert.NoError(t, gossh.Unmarshal(req.Payload, &payload)) {
+						return false, nil
+					}
+					assert.Equal(t, remoteSocket, payload.SocketPath)
+					serverConns <- ctx.Value(ssh.ContextKeyConn).(*gossh.ServerConn)
+					return true, nil
+				},
+			},
+		}
+		defer srv.Close()
+		sshClient := serveSSH(t, srv.Serve)
+
+		localAddr, conns := listenLocalGPGAgent(t)
+		cookie := "0123456789abcdef"
+		closer, err := sshForwardGPGAgent(ctx, io.Discard, sshClient, cookieAddr{
+			Addr:   localAddr,
+			cookie: []byte(cookie),
+		})
+		require.NoError(t, err)
+		defer closer.Close()
+
+		// Connect to the forwarded socket in the "workspace".
+		var serverConn *gossh.ServerConn
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for streamlocal forward request")
+		case serverConn = <-serverConns:
+		}
+		remoteConn, reqs, err := serverConn.OpenChannel("forwarded-streamlocal@openssh.com", gossh.Marshal(&struct {
+			SocketPath string
+			Reserved   string
+		}{SocketPath: remoteSocket}))
+		require.NoError(t, err)
+		go gossh.DiscardRequests(reqs)
+		defer remoteConn.Close()
+		requireForwarded(ctx, t, remoteConn, conns, cookie)
+	})
+}
+
+// serveSSH serves SSH on a local listener and returns a client connected to
+// it.
+func serveSSH(t *testing.T, serve func(net.Listener) error) *gossh.Client {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = serve(ln)
+	}()
+
+	sshClient, err := gossh.Dial("tcp", ln.Addr().String(), &gossh.ClientConfig{
+		User:            "test",
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(), //nolint:gosec // Test server.
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = sshClient.Close()
+	})
+	return sshClient
+}
+
+// listenLocalGPGAgent stands in for the local GPG agent and returns the
+// connections made to it.
+func listenLocalGPGAgent(t *testing.T) (net.Addr, <-chan net.Conn) {
+	t.Helper()
+
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "S.gpg-agent.extra"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+	conns := make(chan net.Conn, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+			conns <- conn
+		}
+	}()
+	return ln.Addr(), conns
+}
+
+// requireForwarded checks that the remote connection is forwarded to a new
+// connection to the local GPG agent, which first receives the cookie.
+func requireForwarded(ctx context.Context, t *testing.T, remoteConn io.ReadWriter, conns <-chan net.Conn, cookie string) {
+	t.Helper()
+
+	_, err := io.WriteString(remoteConn, "GETINFO version\n")
+	require.NoError(t, err)
+	var localConn net.Conn
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for connection to local GPG agent")
+	case localConn = <-conns:
+	}
+	got := make([]byte, len(cookie)+len("GETINFO version\n"))
+	_, err = io.ReadFull(localConn, got)
+	require.NoError(t, err)
+	require.Equal(t, cookie+"GETINFO version\n", string(got))
+
+	_, err = io.WriteString(localConn, "OK\n")
+	require.NoError(t, err)
+	got = make([]byte, len("OK\n"))
+	_, err = io.ReadFull(remoteConn, got)
+	require.NoError(t, err)
+	require.Equal(t, "OK\n", string(got))
+}
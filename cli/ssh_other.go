@@ -30,19 +30,10 @@ func forwardGPGAgent(ctx context.Context, stderr io.Writer, sshClient *gossh.Cli
 		return nil, err
 	}
 
-	remoteSocket, err := remoteGPGAgentSocket(sshClient)
-	if err != nil {
-		return nil, err
-	}
-
 	localAddr := &net.UnixAddr{
 		Name: localSocket,
 		Net:  "unix",
 	}
-	remoteAddr := &net.UnixAddr{
-		Name: remoteSocket,
-		Net:  "unix",
-	}
 
-	return sshForwardRemote(ctx, stderr, sshClient, localAddr, remoteAddr)
+	return sshForwardGPGAgent(ctx, stderr, sshClient, localAddr)
 }
@@ -408,6 +408,62 @@ func TestSSH(t *testing.T) {
 		<-cmdDone
 	})
 
+	t.Run("RemoteForward", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		defer agentCloser.Close()
+
+		// The service on the "local machine".
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		localPort := setupTestListener(t, l)
+
+		// Reserve a port for the workspace side of the forward.
+		rl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		remoteAddr := rl.Addr().String()
+		_, remotePort, err := net.SplitHostPort(remoteAddr)
+		require.NoError(t, err)
+		_ = rl.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t,
+			"ssh",
+			workspace.Name,
+			"--remote-forward", remotePort+":"+localPort,
+		)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		inv.Stderr = pty.Output()
+		cmdDone := tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err, "ssh command failed")
+		})
+
+		// The forward is set up before the shell is started.
+		_ = pty.Peek(ctx, 1)
+
+		d := net.Dialer{Timeout: testutil.WaitShort}
+		c, err := d.DialContext(ctx, "tcp", remoteAddr)
+		require.NoError(t, err, "dial forwarded port in workspace")
+		defer c.Close()
+		testDial(t, c)
+
+		// And we're done.
+		pty.WriteLine("exit")
+		<-cmdDone
+	})
+
 	t.Run("FileLogging", func(t *testing.T) {
 		t.Parallel()
 
@@ -84,11 +84,6 @@ func forwardGPGAgent(ctx context.Context, stderr io.Writer, sshClient *gossh.Cli
 		return nil, xerrors.Errorf("parse gpg-agent-extra socket file: %q: %w", localSocket, err)
 	}
 
-	remoteSocket, err := remoteGPGAgentSocket(sshClient)
-	if err != nil {
-		return nil, err
-	}
-
 	localAddr := cookieAddr{
 		Addr: &net.TCPAddr{
 			IP:   net.IPv4(127, 0, 0, 1),
@@ -96,10 +91,6 @@ func forwardGPGAgent(ctx context.Context, stderr io.Writer, sshClient *gossh.Cli
 		},
 		cookie: cookie,
 	}
-	remoteAddr := &net.UnixAddr{
-		Name: remoteSocket,
-		Net:  "unix",
-	}
 
-	return sshForwardRemote(ctx, stderr, sshClient, localAddr, remoteAddr)
+	return sshForwardGPGAgent(ctx, stderr, sshClient, localAddr)
 }
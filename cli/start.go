@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,19 +11,40 @@ import (
 )
 
 func (r *RootCmd) start() *clibase.Cmd {
+	var bulk bulkWorkspaces
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
 		Use:         "start <workspace>",
 		Short:       "Start a workspace",
+		Long: formatExamples(
+			example{
+				Description: "Start all your stopped workspaces",
+				Command:     "coder start --query 'owner:me status:stopped'",
+			},
+		),
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			bulk.requireArgs(),
 			r.InitClient(client),
 		),
-		Options: clibase.OptionSet{
-			cliui.SkipPromptOption(),
-		},
+		Options: append(bulk.options(), cliui.SkipPromptOption()),
 		Handler: func(inv *clibase.Invocation) error {
+			if bulk.query != "" {
+				return bulk.run(inv, client, bulkAction{
+					Verb: "Start",
+					Done: "Started",
+					Skip: func(workspace codersdk.Workspace) bool {
+						return workspaceBuilt(workspace, codersdk.WorkspaceTransitionStart)
+					},
+					SkipReason: "are already started",
+					Build: func(ctx context.Context, workspace codersdk.Workspace) (codersdk.WorkspaceBuild, error) {
+						return client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+							Transition: codersdk.WorkspaceTransitionStart,
+						})
+					},
+				})
+			}
+
 			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,19 +11,40 @@ import (
 )
 
 func (r *RootCmd) stop() *clibase.Cmd {
+	var bulk bulkWorkspaces
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
 		Use:         "stop <workspace>",
 		Short:       "Stop a workspace",
+		Long: formatExamples(
+			example{
+				Description: "Stop all running workspaces of a template, 10 at a time",
+				Command:     "coder stop --query 'template:docker status:running' --parallel 10",
+			},
+		),
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			bulk.requireArgs(),
 			r.InitClient(client),
 		),
-		Options: clibase.OptionSet{
-			cliui.SkipPromptOption(),
-		},
+		Options: append(bulk.options(), cliui.SkipPromptOption()),
 		Handler: func(inv *clibase.Invocation) error {
+			if bulk.query != "" {
+				return bulk.run(inv, client, bulkAction{
+					Verb: "Stop",
+					Done: "Stopped",
+					Skip: func(workspace codersdk.Workspace) bool {
+						return workspaceBuilt(workspace, codersdk.WorkspaceTransitionStop)
+					},
+					SkipReason: "are already stopped",
+					Build: func(ctx context.Context, workspace codersdk.Workspace) (codersdk.WorkspaceBuild, error) {
+						return client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+							Transition: codersdk.WorkspaceTransitionStop,
+						})
+					},
+				})
+			}
+
 			_, err := cliui.Prompt(inv, cliui.PromptOptions{
 				Text:      "Confirm stop workspace?",
 				IsConfirm: true,
@@ -32,6 +32,7 @@ func (r *RootCmd) templateCreate() *clibase.Cmd {
 		defaultTTL      time.Duration
 		failureTTL      time.Duration
 		inactivityTTL   time.Duration
+		idleThreshold   time.Duration
 
 		uploadFlags templateUploadFlags
 	)
@@ -132,6 +133,7 @@ func (r *RootCmd) templateCreate() *clibase.Cmd {
 				DefaultTTLMillis:           ptr.Ref(defaultTTL.Milliseconds()),
 				FailureTTLMillis:           ptr.Ref(failureTTL.Milliseconds()),
 				InactivityTTLMillis:        ptr.Ref(inactivityTTL.Milliseconds()),
+				IdleThresholdMillis:        ptr.Ref(idleThreshold.Milliseconds()),
 				DisableEveryoneGroupAccess: disableEveryone,
 			}
 
@@ -190,6 +192,12 @@ func (r *RootCmd) templateCreate() *clibase.Cmd {
 			Default:     "0h",
 			Value:       clibase.DurationOf(&inactivityTTL),
 		},
+		{
+			Flag:        "idle-threshold",
+			Description: "Stop workspaces created from this template when their agents report no user activity, like terminal input or app usage, for this long. Open connections alone don't keep workspaces running. The default is 0h (off).",
+			Default:     "0h",
+			Value:       clibase.DurationOf(&idleThreshold),
+		},
 		{
 			Flag:        "test.provisioner",
 			Description: "Customize the provisioner backend.",
@@ -0,0 +1,497 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/diff"
+	"github.com/pkg/diff/write"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisionersdk"
+)
+
+// templateDiffSide is one side of a template diff, either an existing
+// template version or a local directory.
+type templateDiffSide struct {
+	label string
+	files map[string][]byte
+	// version is nil for a directory, unless its variables and parameters
+	// are compared.
+	version *codersdk.TemplateVersion
+}
+
+func (r *RootCmd) templateDiff() *clibase.Cmd {
+	var (
+		parameters    bool
+		variablesFile string
+		variables     []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "diff <template> [version-a] [version-b|directory]",
+		Short: "Show the changes between two versions of a template, or a version and a directory",
+		Long: "The files of the versions are diffed, followed by a summary of the changes to their variables and parameters. " +
+			"Version A defaults to the active version and version B to the current directory. " +
+			"An argument is read as a directory if it contains a path separator or is \".\", " +
+			"and a single directory is compared with the active version. " +
+			"Only the files of a directory are compared, unless --parameters is set.\n\n" +
+			formatExamples(
+				example{
+					Description: "Show the changes in the current directory that haven't been pushed",
+					Command:     "coder templates diff my-template",
+				},
+				example{
+					Description: "Show the changes between two versions",
+					Command:     "coder templates diff my-template happy_turing3 brave_lovelace7",
+				},
+				example{
+					Description: "Show the changes between the active version and a directory",
+					Command:     "coder templates diff my-template ./my-template",
+				},
+				example{
+					Description: "Include the changes to the variables and parameters of a directory",
+					Command:     "coder templates diff my-template ./my-template --parameters",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(1, 3),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) (err error) {
+			ctx := inv.Context()
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+
+			// A single directory is compared with the active version.
+			versionA, target := "", "."
+			switch rest := inv.Args[1:]; {
+			case len(rest) == 1 && isTemplateDiffDirectory(rest[0]):
+				target = rest[0]
+			case len(rest) == 1:
+				versionA = rest[0]
+			case len(rest) == 2:
+				versionA, target = rest[0], rest[1]
+			}
+
+			var (
+				a, b     templateDiffSide
+				aVersion codersdk.TemplateVersion
+			)
+			if versionA != "" {
+				aVersion, err = client.TemplateVersionByName(ctx, template.ID, versionA)
+			} else {
+				aVersion, err = client.TemplateVersion(ctx, template.ActiveVersionID)
+			}
+			if err != nil {
+				return xerrors.Errorf("get template version: %w", err)
+			}
+			a, err = templateVersionDiffSide(inv, client, aVersion)
+			if err != nil {
+				return err
+			}
+
+			if isTemplateDiffDirectory(target) {
+				b.label = filepath.ToSlash(filepath.Clean(target))
+				b.files, err = templateDirectoryFiles(target)
+				if err != nil {
+					return err
+				}
+				if parameters {
+					version, parseErr := parseTemplateDirectory(inv, client, organization, template, aVersion, target, variablesFile, variables)
+					if parseErr != nil {
+						return parseErr
+					}
+					// The version only exists to read the directory, so it
+					// must not outlive the diff.
+					defer func() {
+						deleteErr := client.DeleteTemplateVersion(ctx, version.ID)
+						if deleteErr != nil && err == nil {
+							err = xerrors.Errorf("delete temporary template version: %w", deleteErr)
+						}
+					}()
+					b.version = &version
+				}
+			} else {
+				var bVersion codersdk.TemplateVersion
+				bVersion, err = client.TemplateVersionByName(ctx, template.ID, target)
+				if err != nil {
+					return xerrors.Errorf("get template version: %w", err)
+				}
+				b, err = templateVersionDiffSide(inv, client, bVersion)
+				if err != nil {
+					return err
+				}
+				b.label = target
+			}
+
+			changed, err := diffTemplateFiles(inv, a, b)
+			if err != nil {
+				return err
+			}
+			var summary []string
+			if b.version != nil {
+				summary, err = diffTemplateInputs(inv, client, *a.version, *b.version)
+				if err != nil {
+					return err
+				}
+			}
+			if len(summary) > 0 {
+				if changed {
+					_, _ = fmt.Fprintln(inv.Stdout)
+				}
+				changed = true
+				for _, line := range summary {
+					_, _ = fmt.Fprintln(inv.Stdout, line)
+				}
+			}
+			if !changed {
+				_, _ = fmt.Fprintf(inv.Stdout, "No changes between %s and %s.\n", a.label, b.label)
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag: "parameters",
+			Description: "Compare the variables and parameters of a directory too. This creates a template version " +
+				"that doesn't belong to the template, which is deleted afterwards.",
+			Value: clibase.BoolOf(&parameters),
+		},
+		{
+			Flag:        "variables-file",
+			Description: "Specify a file path with values for Terraform-managed variables when reading a directory with --parameters.",
+			Value:       clibase.StringOf(&variablesFile),
+		},
+		{
+			Flag:        "variable",
+			Description: "Specify a set of values for Terraform-managed variables when reading a directory with --parameters.",
+			Value:       clibase.StringArrayOf(&variables),
+		},
+	}
+	return cmd
+}
+
+// isTemplateDiffDirectory reports whether the argument is a directory rather
+// than the name of a version, which can't contain path separators.
+func isTemplateDiffDirectory(arg string) bool {
+	return arg == "." || strings.ContainsAny(arg, `/\`)
+}
+
+// parseTemplateDirectory creates a version from the directory that doesn't
+// belong to the template, so the variables and parameters it declares can be
+// compared without adding to the template's history. The caller must delete
+// the version. Variables without a value are set to their value in base.
+func parseTemplateDirectory(inv *clibase.Invocation, client *codersdk.Client, organization codersdk.Organization, template codersdk.Template, base codersdk.TemplateVersion, directory, variablesFile string, variables []string) (codersdk.TemplateVersion, error) {
+	ctx := inv.Context()
+	var archive bytes.Buffer
+	err := provisionersdk.Tar(&archive, directory, provisionersdk.TemplateArchiveLimit)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("archive %s: %w", prettyDirectoryPath(directory), err)
+	}
+	upload, err := client.Upload(ctx, codersdk.ContentTypeTar, &archive)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("upload: %w", err)
+	}
+
+	fromFile, err := loadVariableValuesFromFile(variablesFile)
+	if err != nil {
+		return codersdk.TemplateVersion{}, err
+	}
+	fromOptions, err := loadVariableValuesFromOptions(variables)
+	if err != nil {
+		return codersdk.TemplateVersion{}, err
+	}
+	baseVariables, err := client.TemplateVersionVariables(ctx, base.ID)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("get template version variables: %w", err)
+	}
+	values := map[string]string{}
+	for _, variable := range baseVariables {
+		// Sensitive values are redacted.
+		if !variable.Sensitive {
+			values[variable.Name] = variable.Value
+		}
+	}
+	for _, value := range append(fromFile, fromOptions...) {
+		values[value.Name] = value.Value
+	}
+	var userVariableValues []codersdk.VariableValue
+	for _, name := range sortedKeys(values) {
+		userVariableValues = append(userVariableValues, codersdk.VariableValue{Name: name, Value: values[name]})
+	}
+
+	version, err := client.CreateTemplateVersion(ctx, organization.ID, codersdk.CreateTemplateVersionRequest{
+		StorageMethod:      codersdk.ProvisionerStorageMethodFile,
+		FileID:             upload.ID,
+		Provisioner:        template.Provisioner,
+		UserVariableValues: userVariableValues,
+	})
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("create template version: %w", err)
+	}
+	err = cliui.ProvisionerJob(ctx, inv.Stderr, cliui.ProvisionerJobOptions{
+		Fetch: func() (codersdk.ProvisionerJob, error) {
+			version, err := client.TemplateVersion(ctx, version.ID)
+			return version.Job, err
+		},
+		Cancel: func() error {
+			return client.CancelTemplateVersion(ctx, version.ID)
+		},
+		Logs: func() (<-chan codersdk.ProvisionerJobLog, io.Closer, error) {
+			return client.TemplateVersionLogsAfter(ctx, version.ID, 0)
+		},
+		Silent: true,
+	})
+	if err != nil {
+		// The job has completed unless it was canceled, in which case the
+		// version can't be deleted yet.
+		_ = client.DeleteTemplateVersion(ctx, version.ID)
+		return codersdk.TemplateVersion{}, xerrors.Errorf("read %s: %w", prettyDirectoryPath(directory), err)
+	}
+	parsed, err := client.TemplateVersion(ctx, version.ID)
+	if err != nil {
+		_ = client.DeleteTemplateVersion(ctx, version.ID)
+		return codersdk.TemplateVersion{}, xerrors.Errorf("get template version: %w", err)
+	}
+	return parsed, nil
+}
+
+// diffTemplateFiles prints a unified diff of each file that differs between
+// the sides, and reports whether any did.
+func diffTemplateFiles(inv *clibase.Invocation, a, b templateDiffSide) (bool, error) {
+	aFiles, bFiles := a.files, b.files
+
+	names := map[string]struct{}{}
+	for name := range aFiles {
+		names[name] = struct{}{}
+	}
+	for name := range bFiles {
+		names[name] = struct{}{}
+	}
+	var opts []write.Option
+	if isTTYOut(inv) {
+		opts = append(opts, write.TerminalColor())
+	}
+
+	var changed bool
+	for _, name := range sortedKeys(names) {
+		aData, inA := aFiles[name]
+		bData, inB := bFiles[name]
+		if inA && inB && bytes.Equal(aData, bData) {
+			continue
+		}
+		changed = true
+		aName, bName := a.label+"/"+name, b.label+"/"+name
+		if !inA {
+			aName = os.DevNull
+		}
+		if !inB {
+			bName = os.DevNull
+		}
+		if bytes.IndexByte(aData, 0) != -1 || bytes.IndexByte(bData, 0) != -1 {
+			_, _ = fmt.Fprintf(inv.Stdout, "Binary files %s and %s differ\n", aName, bName)
+			continue
+		}
+		err := diff.Text(aName, bName, aData, bData, inv.Stdout, opts...)
+		if err != nil {
+			return false, xerrors.Errorf("diff %s: %w", name, err)
+		}
+	}
+	return changed, nil
+}
+
+// templateVersionDiffSide downloads the source of the version.
+func templateVersionDiffSide(inv *clibase.Invocation, client *codersdk.Client, version codersdk.TemplateVersion) (templateDiffSide, error) {
+	raw, ctype, err := client.Download(inv.Context(), version.Job.FileID)
+	if err != nil {
+		return templateDiffSide{}, xerrors.Errorf("download template version %s: %w", version.Name, err)
+	}
+	if ctype != codersdk.ContentTypeTar {
+		return templateDiffSide{}, xerrors.Errorf("unexpected Content-Type %q, expecting %q", ctype, codersdk.ContentTypeTar)
+	}
+	files, err := templateArchiveFiles(raw)
+	if err != nil {
+		return templateDiffSide{}, xerrors.Errorf("read template version %s: %w", version.Name, err)
+	}
+	return templateDiffSide{
+		label:   version.Name,
+		files:   files,
+		version: &version,
+	}, nil
+}
+
+// templateDirectoryFiles returns the files of the directory that would be
+// pushed, the same as the source of a version created from it.
+func templateDirectoryFiles(directory string) (map[string][]byte, error) {
+	var archive bytes.Buffer
+	err := provisionersdk.Tar(&archive, directory, provisionersdk.TemplateArchiveLimit)
+	if err != nil {
+		return nil, xerrors.Errorf("archive %s: %w", prettyDirectoryPath(directory), err)
+	}
+	files, err := templateArchiveFiles(archive.Bytes())
+	if err != nil {
+		return nil, xerrors.Errorf("read %s: %w", prettyDirectoryPath(directory), err)
+	}
+	return files, nil
+}
+
+// templateArchiveFiles returns the contents of the files in the template
+// archive by their slash-separated path.
+func templateArchiveFiles(raw []byte) (map[string][]byte, error) {
+	dir, err := os.MkdirTemp("", "coder-template-diff-")
+	if err != nil {
+		return nil, xerrors.Errorf("create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	err = provisionersdk.Untar(dir, bytes.NewReader(raw))
+	if err != nil {
+		return nil, xerrors.Errorf("extract: %w", err)
+	}
+
+	files := map[string][]byte{}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// diffTemplateInputs returns a line for each variable and rich parameter
+// that was added, removed or changed between the versions.
+func diffTemplateInputs(inv *clibase.Invocation, client *codersdk.Client, a, b codersdk.TemplateVersion) ([]string, error) {
+	ctx := inv.Context()
+	aVariables, err := client.TemplateVersionVariables(ctx, a.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version variables: %w", err)
+	}
+	bVariables, err := client.TemplateVersionVariables(ctx, b.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version variables: %w", err)
+	}
+	aParameters, err := client.TemplateVersionRichParameters(ctx, a.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	bParameters, err := client.TemplateVersionRichParameters(ctx, b.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template version parameters: %w", err)
+	}
+
+	var lines []string
+	lines = append(lines, diffTemplateInput("variable", aVariables, bVariables, func(v codersdk.TemplateVersionVariable) (string, []templateInputField) {
+		return v.Name, []templateInputField{
+			{"type", v.Type},
+			{"default", v.DefaultValue},
+			{"required", fmt.Sprint(v.Required)},
+			{"sensitive", fmt.Sprint(v.Sensitive)},
+			{"description", v.Description},
+		}
+	})...)
+	lines = append(lines, diffTemplateInput("parameter", aParameters, bParameters, func(p codersdk.TemplateVersionParameter) (string, []templateInputField) {
+		options := make([]string, 0, len(p.Options))
+		for _, option := range p.Options {
+			options = append(options, option.Value)
+		}
+		return p.Name, []templateInputField{
+			{"type", p.Type},
+			{"default", p.DefaultValue},
+			{"required", fmt.Sprint(p.Required)},
+			{"mutable", fmt.Sprint(p.Mutable)},
+			{"options", strings.Join(options, ", ")},
+			{"validation", templateParameterValidation(p)},
+			{"display name", p.DisplayName},
+			{"description", p.Description},
+		}
+	})...)
+	return lines, nil
+}
+
+type templateInputField struct {
+	name  string
+	value string
+}
+
+func diffTemplateInput[T any](kind string, a, b []T, fields func(T) (string, []templateInputField)) []string {
+	aByName := map[string][]templateInputField{}
+	for _, input := range a {
+		name, f := fields(input)
+		aByName[name] = f
+	}
+	var lines []string
+	bNames := map[string]struct{}{}
+	for _, input := range b {
+		name, bFields := fields(input)
+		bNames[name] = struct{}{}
+		aFields, ok := aByName[name]
+		if !ok {
+			lines = append(lines, fmt.Sprintf("+ %s %s", kind, name))
+			continue
+		}
+		var changes []string
+		for i := range bFields {
+			if aFields[i].value != bFields[i].value {
+				changes = append(changes, fmt.Sprintf("%s: %q => %q", bFields[i].name, aFields[i].value, bFields[i].value))
+			}
+		}
+		if len(changes) > 0 {
+			lines = append(lines, fmt.Sprintf("~ %s %s (%s)", kind, name, strings.Join(changes, ", ")))
+		}
+	}
+	for _, input := range a {
+		name, _ := fields(input)
+		if _, ok := bNames[name]; !ok {
+			lines = append(lines, fmt.Sprintf("- %s %s", kind, name))
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	return lines
+}
+
+func templateParameterValidation(p codersdk.TemplateVersionParameter) string {
+	var rules []string
+	if p.ValidationRegex != "" {
+		rules = append(rules, "regex "+p.ValidationRegex)
+	}
+	if p.ValidationMin != nil {
+		rules = append(rules, fmt.Sprintf("min %d", *p.ValidationMin))
+	}
+	if p.ValidationMax != nil {
+		rules = append(rules, fmt.Sprintf("max %d", *p.ValidationMax))
+	}
+	if p.ValidationMonotonic != "" {
+		rules = append(rules, string(p.ValidationMonotonic))
+	}
+	return strings.Join(rules, ", ")
+}
@@ -0,0 +1,131 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisionersdk"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTemplateDiff(t *testing.T) {
+	t.Parallel()
+
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	source := func(t *testing.T, main string, variables ...*proto.TemplateVariable) string {
+		dir := clitest.CreateTemplateVersionSource(t, createEchoResponsesWithTemplateVariables(variables))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(main), 0o600))
+		return dir
+	}
+	createVersion := func(t *testing.T, name, dir string, templateID uuid.UUID) codersdk.TemplateVersion {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		var archive bytes.Buffer
+		require.NoError(t, provisionersdk.Tar(&archive, dir, provisionersdk.TemplateArchiveLimit))
+		upload, err := client.Upload(ctx, codersdk.ContentTypeTar, &archive)
+		require.NoError(t, err)
+		version, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			Name:          name,
+			TemplateID:    templateID,
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			FileID:        upload.ID,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+		return coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	}
+
+	first := createVersion(t, "first", source(t, "one\ntwo\n", &proto.TemplateVariable{
+		Name:         "region",
+		Type:         "string",
+		DefaultValue: "us",
+	}), uuid.Nil)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, first.ID)
+	changedSource := source(t, "one\nthree\n", &proto.TemplateVariable{
+		Name:         "region",
+		Type:         "string",
+		DefaultValue: "eu",
+	}, &proto.TemplateVariable{
+		Name:         "zone",
+		Type:         "string",
+		DefaultValue: "a",
+	})
+	_ = createVersion(t, "second", changedSource, template.ID)
+
+	run := func(t *testing.T, args ...string) string {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, append([]string{"templates", "diff", template.Name}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		require.NoError(t, inv.WithContext(ctx).Run())
+		return stdout.String()
+	}
+
+	t.Run("Versions", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "first", "second")
+		require.Contains(t, out, "--- first/main.tf\n+++ second/main.tf\n")
+		require.Contains(t, out, " one\n-two\n+three\n")
+		require.Contains(t, out, `~ variable region (default: "us" => "eu")`)
+		require.Contains(t, out, "+ variable zone")
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "first", changedSource)
+		require.Contains(t, out, "+++ "+filepath.ToSlash(changedSource)+"/main.tf\n")
+		require.Contains(t, out, " one\n-two\n+three\n")
+		// Only the files of a directory are compared by default.
+		require.NotContains(t, out, "variable")
+	})
+
+	t.Run("DirectoryParameters", func(t *testing.T) {
+		t.Parallel()
+		before := database.Now()
+		out := run(t, "first", changedSource, "--parameters")
+		require.Contains(t, out, " one\n-two\n+three\n")
+		require.Contains(t, out, "+ variable zone")
+
+		// The version created to read the directory is deleted.
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		//nolint:gocritic // Versions without a template can't be listed.
+		versions, err := api.Database.GetTemplateVersionsCreatedAfter(dbauthz.AsSystemRestricted(ctx), before)
+		require.NoError(t, err)
+		require.Empty(t, versions)
+	})
+
+	t.Run("ActiveVersion", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, changedSource)
+		require.Contains(t, out, "--- first/main.tf\n")
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "second", "first")
+		require.Contains(t, out, "- variable zone")
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "first", "first")
+		require.Equal(t, "No changes between first and first.\n", out)
+	})
+}
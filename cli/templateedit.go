@@ -22,6 +22,7 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		maxTTL                       time.Duration
 		failureTTL                   time.Duration
 		inactivityTTL                time.Duration
+		idleThreshold                time.Duration
 		allowUserCancelWorkspaceJobs bool
 		allowUserAutostart           bool
 		allowUserAutostop            bool
@@ -71,6 +72,12 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				return xerrors.Errorf("get workspace template: %w", err)
 			}
 
+			// Keep the current idle threshold unless it's changed, since
+			// zero turns idle detection off.
+			if !inv.ParsedFlags().Changed("idle-threshold") {
+				idleThreshold = time.Duration(template.IdleThresholdMillis) * time.Millisecond
+			}
+
 			// NOTE: coderd will ignore empty fields.
 			req := codersdk.UpdateTemplateMeta{
 				Name:                         name,
@@ -81,6 +88,7 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				MaxTTLMillis:                 maxTTL.Milliseconds(),
 				FailureTTLMillis:             failureTTL.Milliseconds(),
 				InactivityTTLMillis:          inactivityTTL.Milliseconds(),
+				IdleThresholdMillis:          idleThreshold.Milliseconds(),
 				AllowUserCancelWorkspaceJobs: allowUserCancelWorkspaceJobs,
 				AllowUserAutostart:           allowUserAutostart,
 				AllowUserAutostop:            allowUserAutostop,
@@ -138,6 +146,11 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Default:     "0h",
 			Value:       clibase.DurationOf(&inactivityTTL),
 		},
+		{
+			Flag:        "idle-threshold",
+			Description: "Edit how long workspaces created from this template can go without user activity, like terminal input or app usage, before they are stopped. Open connections alone don't keep workspaces running. Set to 0h to turn idle detection off.",
+			Value:       clibase.DurationOf(&idleThreshold),
+		},
 		{
 			Flag:        "allow-user-cancel-workspace-jobs",
 			Description: "Allow users to cancel in-progress workspace jobs.",
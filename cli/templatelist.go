@@ -33,6 +33,10 @@ func (r *RootCmd) templateList() *clibase.Cmd {
 			if err != nil {
 				return err
 			}
+			// The cache is used for shell completion, so it's best effort.
+			if conf, err := r.contextConfig(); err == nil {
+				_ = writeCache(conf.TemplatesCache(), client.URL, templates)
+			}
 
 			if len(templates) == 0 {
 				_, _ = fmt.Fprintf(inv.Stderr, "%s No templates found in %s! Create one:\n\n", Caret, color.HiWhiteString(organization.Name))
@@ -41,6 +41,7 @@ func (r *RootCmd) templates() *clibase.Cmd {
 			r.templatePush(),
 			r.templateVersions(),
 			r.templateDelete(),
+			r.templateDiff(),
 			r.templatePull(),
 		},
 	}
@@ -0,0 +1,495 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) tui() *clibase.Cmd {
+	var searchQuery string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "tui",
+		Short:       "Open an interactive dashboard of your workspaces",
+		Long: "Workspaces are updated live as they're built, along with the status and metadata of their agents. " +
+			"Select a workspace with the arrow keys, then press s to start it, x to stop it, " +
+			"enter to open a shell in it, or l to follow its logs.\n\n" + formatExamples(
+			example{
+				Description: "Show the workspaces of every user you can see",
+				Command:     "coder tui --search \"\"",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if !isTTY(inv) || !isTTYOut(inv) {
+				return xerrors.New("the dashboard must be run in a terminal")
+			}
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+				FilterQuery: searchQuery,
+			})
+			if err != nil {
+				return xerrors.Errorf("list workspaces: %w", err)
+			}
+			coderPath, err := os.Executable()
+			if err != nil {
+				return xerrors.Errorf("get executable path: %w", err)
+			}
+			env, err := r.pluginEnv()
+			if err != nil {
+				return err
+			}
+
+			model := newTUIModel(ctx, client, searchQuery, res.Workspaces)
+			model.sshCommand = func(workspace codersdk.Workspace) *exec.Cmd {
+				args := []string{"ssh", workspace.OwnerName + "/" + workspace.Name}
+				if r.disableDirect {
+					args = append(args, "--disable-direct-connections")
+				}
+				//nolint:gosec
+				cmd := exec.Command(coderPath, args...)
+				// The shell runs with the same deployment and session as
+				// the dashboard, the same as plugins.
+				cmd.Env = append(inv.Environ.ToOS(), env...)
+				return cmd
+			}
+			_, err = tea.NewProgram(model,
+				tea.WithContext(ctx),
+				tea.WithInput(inv.Stdin),
+				tea.WithOutput(inv.Stdout),
+				tea.WithAltScreen(),
+			).Run()
+			if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "search",
+			Description: "Search for the workspaces to show with a query.",
+			Default:     "owner:me",
+			Value:       clibase.StringOf(&searchQuery),
+		},
+	}
+	return cmd
+}
+
+type (
+	// tuiEvent wraps messages sent from the goroutines that watch the
+	// deployment.
+	tuiEvent struct {
+		msg tea.Msg
+	}
+	tuiWorkspaceMsg  codersdk.Workspace
+	tuiWorkspacesMsg []codersdk.Workspace
+	tuiMetadataMsg   struct {
+		agentID  uuid.UUID
+		metadata []codersdk.WorkspaceAgentMetadata
+	}
+	// tuiStatusMsg is shown below the workspaces until the next one.
+	tuiStatusMsg struct {
+		text string
+		err  error
+	}
+	tuiLogMsg struct {
+		// view is the logs view the line belongs to, since lines of a
+		// closed view may still be in flight.
+		view int
+		line string
+	}
+)
+
+// tuiLogs is the view of the logs of a workspace, which replaces the list of
+// workspaces until it's closed.
+type tuiLogs struct {
+	id        int
+	workspace string
+	cancel    context.CancelFunc
+	lines     []string
+	viewport  viewport.Model
+}
+
+type tuiModel struct {
+	ctx         context.Context
+	client      *codersdk.Client
+	searchQuery string
+	events      chan tea.Msg
+	// sshCommand returns the command that opens a shell in the workspace.
+	sshCommand func(codersdk.Workspace) *exec.Cmd
+
+	workspaces []codersdk.Workspace
+	metadata   map[uuid.UUID][]codersdk.WorkspaceAgentMetadata
+	// watching holds the workspaces and agents that are watched for
+	// changes.
+	watching map[uuid.UUID]bool
+	selected int
+	status   tuiStatusMsg
+	logs     *tuiLogs
+	logViews int
+	width    int
+	height   int
+}
+
+func newTUIModel(ctx context.Context, client *codersdk.Client, searchQuery string, workspaces []codersdk.Workspace) *tuiModel {
+	m := &tuiModel{
+		ctx:         ctx,
+		client:      client,
+		searchQuery: searchQuery,
+		events:      make(chan tea.Msg, 64),
+		metadata:    map[uuid.UUID][]codersdk.WorkspaceAgentMetadata{},
+		watching:    map[uuid.UUID]bool{},
+	}
+	m.setWorkspaces(workspaces)
+	return m
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return m.listen()
+}
+
+// listen waits for the next message from the watchers.
+func (m *tuiModel) listen() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-m.events:
+			return tuiEvent{msg: msg}
+		case <-m.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m *tuiModel) send(msg tea.Msg) {
+	select {
+	case m.events <- msg:
+	case <-m.ctx.Done():
+	}
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tuiEvent:
+		m.handleEvent(msg.msg)
+		return m, m.listen()
+	case tuiStatusMsg:
+		m.status = msg
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		if m.logs != nil {
+			m.logs.viewport.Width, m.logs.viewport.Height = m.width, m.logsHeight()
+		}
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.logs != nil {
+			return m, m.updateLogs(msg)
+		}
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *tuiModel) handleEvent(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tuiWorkspaceMsg:
+		workspaces := make([]codersdk.Workspace, 0, len(m.workspaces))
+		for _, workspace := range m.workspaces {
+			if workspace.ID != msg.ID {
+				workspaces = append(workspaces, workspace)
+			}
+		}
+		m.setWorkspaces(append(workspaces, codersdk.Workspace(msg)))
+	case tuiWorkspacesMsg:
+		m.setWorkspaces(msg)
+	case tuiMetadataMsg:
+		m.metadata[msg.agentID] = msg.metadata
+	case tuiStatusMsg:
+		m.status = msg
+	case tuiLogMsg:
+		if m.logs == nil || m.logs.id != msg.view {
+			return
+		}
+		follow := m.logs.viewport.AtBottom()
+		m.logs.lines = append(m.logs.lines, msg.line)
+		m.logs.viewport.SetContent(strings.Join(m.logs.lines, "\n"))
+		if follow {
+			m.logs.viewport.GotoBottom()
+		}
+	}
+}
+
+func (m *tuiModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "esc":
+		return tea.Quit
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+		return nil
+	case "down", "j":
+		if m.selected < len(m.workspaces)-1 {
+			m.selected++
+		}
+		return nil
+	case "r":
+		m.status = tuiStatusMsg{text: "Refreshing..."}
+		go func() {
+			res, err := m.client.Workspaces(m.ctx, codersdk.WorkspaceFilter{
+				FilterQuery: m.searchQuery,
+			})
+			if err != nil {
+				m.send(tuiStatusMsg{err: xerrors.Errorf("list workspaces: %w", err)})
+				return
+			}
+			m.send(tuiWorkspacesMsg(res.Workspaces))
+			m.send(tuiStatusMsg{})
+		}()
+		return nil
+	}
+
+	if len(m.workspaces) == 0 {
+		return nil
+	}
+	workspace := m.workspaces[m.selected]
+	name := workspace.OwnerName + "/" + workspace.Name
+	switch msg.String() {
+	case "s":
+		m.build(workspace, codersdk.WorkspaceTransitionStart)
+	case "x":
+		m.build(workspace, codersdk.WorkspaceTransitionStop)
+	case "enter":
+		if m.sshCommand == nil {
+			return nil
+		}
+		return tea.ExecProcess(m.sshCommand(workspace), func(err error) tea.Msg {
+			if err != nil {
+				return tuiStatusMsg{err: xerrors.Errorf("ssh %s: %w", name, err)}
+			}
+			return tuiStatusMsg{}
+		})
+	case "l":
+		m.openLogs(workspace)
+	}
+	return nil
+}
+
+func (m *tuiModel) build(workspace codersdk.Workspace, transition codersdk.WorkspaceTransition) {
+	name := workspace.OwnerName + "/" + workspace.Name
+	action := "Starting"
+	if transition == codersdk.WorkspaceTransitionStop {
+		action = "Stopping"
+	}
+	m.status = tuiStatusMsg{text: fmt.Sprintf("%s %s...", action, name)}
+	go func() {
+		_, err := m.client.CreateWorkspaceBuild(m.ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: transition,
+		})
+		if err != nil {
+			m.send(tuiStatusMsg{err: xerrors.Errorf("%s %s: %w", strings.ToLower(action), name, err)})
+		}
+	}()
+}
+
+func (m *tuiModel) openLogs(workspace codersdk.Workspace) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.logViews++
+	m.logs = &tuiLogs{
+		id:        m.logViews,
+		workspace: workspace.OwnerName + "/" + workspace.Name,
+		cancel:    cancel,
+		viewport:  viewport.New(m.width, m.logsHeight()),
+	}
+	view := m.logs.id
+	go func() {
+		err := writeWorkspaceLogs(ctx, m.client, workspace.LatestBuild, "", true, func(line logLine) error {
+			m.send(tuiLogMsg{view: view, line: line.String()})
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			m.send(tuiStatusMsg{err: xerrors.Errorf("follow logs: %w", err)})
+		}
+	}()
+}
+
+func (m *tuiModel) updateLogs(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "esc":
+		m.logs.cancel()
+		m.logs = nil
+		return nil
+	}
+	var cmd tea.Cmd
+	m.logs.viewport, cmd = m.logs.viewport.Update(msg)
+	return cmd
+}
+
+// logsHeight is the height of the logs, below the title and above the help.
+func (m *tuiModel) logsHeight() int {
+	if m.height > 2 {
+		return m.height - 2
+	}
+	return 1
+}
+
+// setWorkspaces replaces the workspaces, keeping the selected one, and
+// watches any that are new.
+func (m *tuiModel) setWorkspaces(workspaces []codersdk.Workspace) {
+	var selectedID uuid.UUID
+	if m.selected < len(m.workspaces) {
+		selectedID = m.workspaces[m.selected].ID
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].OwnerName+"/"+workspaces[i].Name < workspaces[j].OwnerName+"/"+workspaces[j].Name
+	})
+	m.workspaces = workspaces
+	m.selected = 0
+	for i, workspace := range workspaces {
+		if workspace.ID == selectedID {
+			m.selected = i
+		}
+		m.watchWorkspace(workspace)
+	}
+}
+
+func (m *tuiModel) watchWorkspace(workspace codersdk.Workspace) {
+	if !m.watching[workspace.ID] {
+		m.watching[workspace.ID] = true
+		go func() {
+			updates, err := m.client.WatchWorkspace(m.ctx, workspace.ID)
+			if err != nil {
+				m.send(tuiStatusMsg{err: xerrors.Errorf("watch workspace %s: %w", workspace.Name, err)})
+				return
+			}
+			for update := range updates {
+				m.send(tuiWorkspaceMsg(update))
+			}
+		}()
+	}
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			if m.watching[agent.ID] {
+				continue
+			}
+			m.watching[agent.ID] = true
+			agentID := agent.ID
+			go func() {
+				updates, errs := m.client.WatchWorkspaceAgentMetadata(m.ctx, agentID)
+				for {
+					select {
+					case metadata := <-updates:
+						m.send(tuiMetadataMsg{agentID: agentID, metadata: metadata})
+					case <-errs:
+						// The agent was replaced by a new build, or the
+						// dashboard was closed.
+						return
+					}
+				}
+			}()
+		}
+	}
+}
+
+func (m *tuiModel) View() string {
+	styles := cliui.DefaultStyles
+	if m.logs != nil {
+		return styles.Bold.Render("Logs of "+m.logs.workspace) + "\n" +
+			m.logs.viewport.View() + "\n" +
+			styles.Placeholder.Render("↑/↓ scroll · esc back · ctrl+c quit")
+	}
+
+	var b strings.Builder
+	title := "Workspaces"
+	if m.searchQuery != "" {
+		title += " matching " + m.searchQuery
+	}
+	_, _ = fmt.Fprintln(&b, styles.Bold.Render(title))
+	_, _ = fmt.Fprintln(&b)
+	if len(m.workspaces) == 0 {
+		_, _ = fmt.Fprintln(&b, styles.Placeholder.Render("No workspaces found."))
+	}
+
+	nameWidth := len("WORKSPACE")
+	for _, workspace := range m.workspaces {
+		if width := len(workspace.OwnerName) + 1 + len(workspace.Name); width > nameWidth {
+			nameWidth = width
+		}
+	}
+	row := func(prefix, name, template, status, agents string) string {
+		return fmt.Sprintf("%s%-*s  %-20s  %-10s  %s", prefix, nameWidth, name, template, status, agents)
+	}
+	_, _ = fmt.Fprintln(&b, styles.Placeholder.Render(row("  ", "WORKSPACE", "TEMPLATE", "STATUS", "AGENTS")))
+	for i, workspace := range m.workspaces {
+		status := codersdk.WorkspaceDisplayStatus(workspace.LatestBuild.Job.Status, workspace.LatestBuild.Transition)
+		var agents []string
+		for _, resource := range workspace.LatestBuild.Resources {
+			for _, agent := range resource.Agents {
+				agents = append(agents, agent.Name+": "+tuiAgentStatus(agent))
+			}
+		}
+		line := row("  ", workspace.OwnerName+"/"+workspace.Name, workspace.TemplateName, status, strings.Join(agents, ", "))
+		if i == m.selected {
+			line = styles.Keyword.Render(row("> ", workspace.OwnerName+"/"+workspace.Name, workspace.TemplateName, status, strings.Join(agents, ", ")))
+		}
+		_, _ = fmt.Fprintln(&b, line)
+	}
+
+	if m.selected < len(m.workspaces) {
+		workspace := m.workspaces[m.selected]
+		for _, resource := range workspace.LatestBuild.Resources {
+			for _, agent := range resource.Agents {
+				_, _ = fmt.Fprintln(&b)
+				_, _ = fmt.Fprintf(&b, "%s %s (%s, %s)\n", styles.Bold.Render("Agent"), agent.Name, tuiAgentStatus(agent), agent.OperatingSystem)
+				for _, item := range m.metadata[agent.ID] {
+					value := item.Result.Value
+					if item.Result.Error != "" {
+						value = styles.Error.Render(item.Result.Error)
+					}
+					_, _ = fmt.Fprintf(&b, "  %s: %s\n", item.Description.DisplayName, strings.TrimSpace(value))
+				}
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintln(&b)
+	switch {
+	case m.status.err != nil:
+		_, _ = fmt.Fprintln(&b, styles.Error.Render(m.status.err.Error()))
+	case m.status.text != "":
+		_, _ = fmt.Fprintln(&b, m.status.text)
+	}
+	_, _ = fmt.Fprint(&b, styles.Placeholder.Render("↑/↓ select · s start · x stop · enter ssh · l logs · r refresh · q quit"))
+	return b.String()
+}
+
+// tuiAgentStatus is the lifecycle state of a connected agent, such as
+// "ready", or else whether it's connecting or disconnected.
+func tuiAgentStatus(agent codersdk.WorkspaceAgent) string {
+	if agent.Status != codersdk.WorkspaceAgentConnected {
+		return string(agent.Status)
+	}
+	return string(agent.LifecycleState)
+}
@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTUI(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The input of the pseudo-terminal isn't a file on Windows")
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	inv, root := clitest.New(t, "tui", "--force-tty")
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t).Attach(inv)
+	// The terminal is made raw by the dashboard if it's a file, so keys are
+	// read without waiting for a newline.
+	inv.Stdin = pty.Input().Reader
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, inv.WithContext(ctx).Run())
+	}()
+
+	pty.ExpectMatch("testuser/" + workspace.Name)
+	pty.ExpectMatch("Started")
+	// Stopping the workspace is shown as the build progresses.
+	pty.Write('x')
+	pty.ExpectMatch("Stopping testuser/" + workspace.Name)
+	pty.ExpectMatch("Stopped")
+	pty.Write('l')
+	pty.ExpectMatch("Logs of testuser/" + workspace.Name)
+	// Escape closes the logs, then quits.
+	pty.Write('\x1b')
+	pty.ExpectMatch("select · s start")
+	pty.Write('q')
+	<-done
+}
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 
+	"golang.org/x/xerrors"
+
 	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -11,6 +15,7 @@ func (r *RootCmd) update() *clibase.Cmd {
 	var (
 		richParameterFile string
 		alwaysPrompt      bool
+		bulk              bulkWorkspaces
 	)
 
 	client := new(codersdk.Client)
@@ -18,12 +23,41 @@ func (r *RootCmd) update() *clibase.Cmd {
 		Annotations: workspaceCommand,
 		Use:         "update <workspace>",
 		Short:       "Will update and start a given workspace if it is out of date",
-		Long:        "Use --always-prompt to change the parameter values of the workspace.",
+		Long: "Use --always-prompt to change the parameter values of the workspace. " +
+			"With --query, the workspaces keep their parameter values and new parameters use their defaults.\n\n" +
+			formatExamples(
+				example{
+					Description: "Update all outdated workspaces of a template after publishing a new version",
+					Command:     "coder update --query 'template:docker'",
+				},
+			),
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			bulk.requireArgs(),
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
+			if bulk.query != "" {
+				if alwaysPrompt || richParameterFile != "" {
+					return xerrors.New("--always-prompt and --rich-parameter-file can't be used with --query")
+				}
+				return bulk.run(inv, client, bulkAction{
+					Verb:       "Update",
+					Done:       "Updated",
+					Skip:       func(workspace codersdk.Workspace) bool { return !workspace.Outdated },
+					SkipReason: "are up to date",
+					Build: func(ctx context.Context, workspace codersdk.Workspace) (codersdk.WorkspaceBuild, error) {
+						template, err := client.Template(ctx, workspace.TemplateID)
+						if err != nil {
+							return codersdk.WorkspaceBuild{}, xerrors.Errorf("get template: %w", err)
+						}
+						return client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+							TemplateVersionID: template.ActiveVersionID,
+							Transition:        codersdk.WorkspaceTransitionStart,
+						})
+					},
+				})
+			}
+
 			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
@@ -82,19 +116,20 @@ func (r *RootCmd) update() *clibase.Cmd {
 		},
 	}
 
-	cmd.Options = clibase.OptionSet{
-		{
+	cmd.Options = append(bulk.options(),
+		clibase.Option{
 			Flag:        "always-prompt",
 			Description: "Always prompt all parameters. Does not pull parameter values from existing workspace.",
 
 			Value: clibase.BoolOf(&alwaysPrompt),
 		},
-		{
+		clibase.Option{
 			Flag:        "rich-parameter-file",
 			Description: "Specify a file path with values for rich parameters defined in the template.",
 			Env:         "CODER_RICH_PARAMETER_FILE",
 			Value:       clibase.StringOf(&richParameterFile),
 		},
-	}
+		cliui.SkipPromptOption(),
+	)
 	return cmd
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +12,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 )
 
 // activityBumpWorkspace automatically bumps the workspace's auto-off timer
@@ -99,3 +101,134 @@ func activityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Sto
 		slog.F("workspace_id", workspaceID),
 	)
 }
+
+// idleStopWorkspace moves the deadline of the workspace's latest build to
+// now if the user hasn't interacted with it for longer than idleThreshold,
+// so the lifecycle executor stops it on its next tick. Activity from before
+// the build started doesn't count. A zero lastActivity means the activity is
+// unknown, in which case the workspace is never stopped.
+func idleStopWorkspace(ctx context.Context, log slog.Logger, db database.Store, workspaceID uuid.UUID, lastActivity time.Time, idleThreshold time.Duration) {
+	if lastActivity.IsZero() {
+		return
+	}
+
+	// We set a short timeout so if the app is under load, these
+	// low priority operations fail first.
+	ctx, cancel := context.WithTimeout(ctx, time.Second*15)
+	defer cancel()
+
+	var stopped bool
+	err := db.InTx(func(s database.Store) error {
+		build, err := s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+
+		job, err := s.GetProvisionerJobByID(ctx, build.JobID)
+		if err != nil {
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+
+		if build.Transition != database.WorkspaceTransitionStart || !job.CompletedAt.Valid {
+			return nil
+		}
+
+		idleSince := lastActivity
+		if job.CompletedAt.Time.After(idleSince) {
+			idleSince = job.CompletedAt.Time
+		}
+		now := database.Now()
+		if now.Sub(idleSince) < idleThreshold {
+			return nil
+		}
+		if !build.Deadline.IsZero() && !build.Deadline.After(now) {
+			// The workspace is already due to stop.
+			return nil
+		}
+
+		if _, err := s.UpdateWorkspaceBuildByID(ctx, database.UpdateWorkspaceBuildByIDParams{
+			ID:               build.ID,
+			UpdatedAt:        now,
+			ProvisionerState: build.ProvisionerState,
+			Deadline:         now,
+			MaxDeadline:      build.MaxDeadline,
+		}); err != nil {
+			return xerrors.Errorf("update workspace build: %w", err)
+		}
+		stopped = true
+		return nil
+	}, nil)
+	if err != nil {
+		if !xerrors.Is(err, context.Canceled) && !database.IsQueryCanceledError(err) {
+			log.Error(ctx, "idle stop failed", slog.Error(err),
+				slog.F("workspace_id", workspaceID),
+			)
+		}
+		return
+	}
+
+	if stopped {
+		log.Info(ctx, "workspace is idle, moved deadline to now",
+			slog.F("workspace_id", workspaceID),
+			slog.F("last_activity", lastActivity),
+		)
+	}
+}
+
+// idleThresholdCacheTTL is how long the idle threshold of a template is
+// cached. Changes made through another replica take this long to apply.
+const idleThresholdCacheTTL = time.Minute
+
+// idleThresholdCache caches the idle thresholds of templates, so agents
+// reporting stats don't fetch their template on every report.
+type idleThresholdCache struct {
+	db database.Store
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]idleThresholdEntry
+}
+
+type idleThresholdEntry struct {
+	threshold time.Duration
+	fetchedAt time.Time
+}
+
+func newIdleThresholdCache(db database.Store) *idleThresholdCache {
+	return &idleThresholdCache{
+		db:      db,
+		entries: map[uuid.UUID]idleThresholdEntry{},
+	}
+}
+
+// Get returns the idle threshold of the template.
+func (c *idleThresholdCache) Get(ctx context.Context, templateID uuid.UUID) (time.Duration, error) {
+	now := database.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[templateID]
+	c.mu.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < idleThresholdCacheTTL {
+		return entry.threshold, nil
+	}
+
+	// The agent isn't allowed to read the template.
+	//nolint:gocritic // Only the idle threshold is used.
+	template, err := c.db.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), templateID)
+	if err != nil {
+		return 0, err
+	}
+	threshold := time.Duration(template.IdleThreshold)
+	c.mu.Lock()
+	c.entries[templateID] = idleThresholdEntry{threshold: threshold, fetchedAt: now}
+	c.mu.Unlock()
+	return threshold, nil
+}
+
+// Forget drops the cached idle threshold of the template, it's called when
+// the template is updated.
+func (c *idleThresholdCache) Forget(templateID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, templateID)
+}
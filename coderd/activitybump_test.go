@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog/sloggers/slogtest"
@@ -13,6 +14,7 @@ import (
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/schedule"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/provisioner/echo"
@@ -212,3 +214,91 @@ func TestWorkspaceActivityBump(t *testing.T) {
 		require.Equal(t, workspace.LatestBuild.Deadline.Time, workspace.LatestBuild.MaxDeadline.Time)
 	})
 }
+
+func TestWorkspaceIdleThreshold(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	agentToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(agentToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+		ctr.IdleThresholdMillis = ptr.Ref(time.Hour.Milliseconds())
+	})
+	require.Equal(t, time.Hour.Milliseconds(), template.IdleThresholdMillis)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	firstDeadline := workspace.LatestBuild.Deadline.Time
+	require.False(t, firstDeadline.IsZero())
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+
+	// Recent activity keeps the workspace running.
+	_, err = agentClient.PostStats(ctx, &agentsdk.Stats{
+		ConnectionsByProto: map[string]int64{"tcp": 1},
+		ConnectionCount:    1,
+		LastActivityAt:     time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.False(t, workspace.LatestBuild.Deadline.Time.Before(firstDeadline))
+
+	template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		Name:                         template.Name,
+		DisplayName:                  template.DisplayName,
+		Description:                  template.Description,
+		Icon:                         template.Icon,
+		DefaultTTLMillis:             template.DefaultTTLMillis,
+		AllowUserAutostart:           template.AllowUserAutostart,
+		AllowUserAutostop:            template.AllowUserAutostop,
+		AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+		IdleThresholdMillis:          time.Second.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Second.Milliseconds(), template.IdleThresholdMillis)
+
+	// Agents report no activity after they restarted, which must not be
+	// mistaken for an idle workspace.
+	_, err = agentClient.PostStats(ctx, &agentsdk.Stats{
+		ConnectionsByProto: map[string]int64{"tcp": 1},
+		ConnectionCount:    1,
+	})
+	require.NoError(t, err)
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.False(t, workspace.LatestBuild.Deadline.Time.Before(firstDeadline))
+
+	// Open connections without recent activity don't keep the workspace
+	// running, so its deadline moves to now.
+	require.Eventually(t, func() bool {
+		_, err = agentClient.PostStats(ctx, &agentsdk.Stats{
+			ConnectionsByProto: map[string]int64{"tcp": 1},
+			ConnectionCount:    1,
+			LastActivityAt:     time.Now().Add(-time.Minute),
+		})
+		if !assert.NoError(t, err) {
+			return false
+		}
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return workspace.LatestBuild.Deadline.Time.Before(firstDeadline)
+	}, testutil.WaitLong, testutil.IntervalMedium)
+	require.WithinDuration(t, time.Now(), workspace.LatestBuild.Deadline.Time, testutil.WaitLong)
+}
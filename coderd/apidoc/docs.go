@@ -878,6 +878,294 @@ const docTemplate = `{
                 }
             }
         },
+        "/oauth2-provider/apps": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Get OAuth2 applications",
+                "operationId": "get-oauth2-applications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by applications authorized for a user",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Create OAuth2 application",
+                "operationId": "create-oauth2-application",
+                "parameters": [
+                    {
+                        "description": "The OAuth2 application to create",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateOAuth2ProviderAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2-provider/apps/{app}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Get OAuth2 application",
+                "operationId": "get-oauth2-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Delete OAuth2 application",
+                "operationId": "delete-oauth2-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Update OAuth2 application",
+                "operationId": "update-oauth2-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update an OAuth2 application",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateOAuth2ProviderAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2-provider/apps/{app}/secrets": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Get OAuth2 application secrets",
+                "operationId": "get-oauth2-application-secrets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecret"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Create OAuth2 application secret",
+                "operationId": "create-oauth2-application-secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecretFull"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2-provider/apps/{app}/secrets/{secretID}": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Delete OAuth2 application secret",
+                "operationId": "delete-oauth2-application-secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Secret ID",
+                        "name": "secretID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
         "/organizations": {
             "post": {
                 "security": [
@@ -1288,7 +1576,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/{organization}/templates": {
+        "/organizations/{organization}/roles": {
             "get": {
                 "security": [
                     {
@@ -1299,10 +1587,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get templates by organization",
-                "operationId": "get-templates-by-organization",
+                "summary": "Get custom organization roles",
+                "operationId": "get-custom-organization-roles",
                 "parameters": [
                     {
                         "type": "string",
@@ -1319,7 +1607,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Template"
+                                "$ref": "#/definitions/codersdk.CustomRole"
                             }
                         }
                     }
@@ -1338,28 +1626,245 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Create template by organization",
-                "operationId": "create-template-by-organization",
+                "summary": "Create custom organization role",
+                "operationId": "create-custom-organization-role",
                 "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
-                        }
-                    },
                     {
                         "type": "string",
+                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    }
-                ],
+                    },
+                    {
+                        "description": "Create custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/roles/{role}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get custom organization role by name",
+                "operationId": "get-custom-organization-role-by-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Delete custom organization role",
+                "operationId": "delete-custom-organization-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Update custom organization role",
+                "operationId": "update-custom-organization-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/templates": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get templates by organization",
+                "operationId": "get-templates-by-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Template"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template by organization",
+                "operationId": "create-template-by-organization",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1648,7 +2153,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/scim/v2/Users": {
+        "/roles": {
             "get": {
                 "security": [
                     {
@@ -1656,16 +2161,22 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Members"
                 ],
-                "summary": "SCIM 2.0: Get users",
-                "operationId": "scim-get-users",
+                "summary": "Get custom site roles",
+                "operationId": "get-custom-site-roles",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.CustomRole"
+                            }
+                        }
                     }
                 }
             },
@@ -1675,36 +2186,39 @@ const docTemplate = `{
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Members"
                 ],
-                "summary": "SCIM 2.0: Create new user",
-                "operationId": "scim-create-new-user",
+                "summary": "Create custom site role",
+                "operationId": "create-custom-site-role",
                 "parameters": [
                     {
-                        "description": "New user",
+                        "description": "Create custom role request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.CreateCustomRoleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users/{id}": {
+        "/roles/{role}": {
             "get": {
                 "security": [
                     {
@@ -1712,40 +2226,206 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Members"
                 ],
-                "summary": "SCIM 2.0: Get user by ID",
-                "operationId": "scim-get-user-by-id",
+                "summary": "Get custom site role by name",
+                "operationId": "get-custom-site-role-by-name",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Role name",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "404": {
-                        "description": "Not Found"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Members"
+                ],
+                "summary": "Delete custom site role",
+                "operationId": "delete-custom-site-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Update custom site role",
+                "operationId": "update-custom-site-role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update custom role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CustomRole"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get users",
+                "operationId": "scim-get-users",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Create new user",
+                "operationId": "scim-create-new-user",
+                "parameters": [
+                    {
+                        "description": "New user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get user by ID",
+                "operationId": "scim-get-user-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
                 ],
                 "summary": "SCIM 2.0: Update user account",
                 "operationId": "scim-update-user-status",
@@ -2178,6 +2858,40 @@ const docTemplate = `{
                     }
                 }
             },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Only versions that don't belong to a template can be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Delete template version by ID",
+                "operationId": "delete-template-version-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
             "patch": {
                 "security": [
                     {
@@ -3488,7 +4202,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user}/organizations": {
+        "/users/{user}/notifications/preferences": {
             "get": {
                 "security": [
                     {
@@ -3501,8 +4215,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get organizations by user",
-                "operationId": "get-organizations-by-user",
+                "summary": "Get user notification preferences",
+                "operationId": "get-user-notification-preferences",
                 "parameters": [
                     {
                         "type": "string",
@@ -3518,28 +4232,29 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Organization"
+                                "$ref": "#/definitions/codersdk.NotificationPreference"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/organizations/{organizationname}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get organization by user and organization name",
-                "operationId": "get-organization-by-user-and-organization-name",
+                "summary": "Update user notification preferences",
+                "operationId": "update-user-notification-preferences",
                 "parameters": [
                     {
                         "type": "string",
@@ -3549,38 +4264,43 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Organization name",
-                        "name": "organizationname",
-                        "in": "path",
-                        "required": true
+                        "description": "Notification preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateNotificationPreferencesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.NotificationPreference"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/password": {
-            "put": {
+        "/users/{user}/organizations": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Update user password",
-                "operationId": "update-user-password",
+                "summary": "Get organizations by user",
+                "operationId": "get-organizations-by-user",
                 "parameters": [
                     {
                         "type": "string",
@@ -3588,42 +4308,36 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update password request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Organization"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/profile": {
-            "put": {
+        "/users/{user}/organizations/{organizationname}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Update user profile",
-                "operationId": "update-user-profile",
+                "summary": "Get organization by user and organization name",
+                "operationId": "get-organization-by-user-and-organization-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -3633,8 +4347,92 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated profile",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Organization name",
+                        "name": "organizationname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/password": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user password",
+                "operationId": "update-user-password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/profile": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user profile",
+                "operationId": "update-user-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated profile",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
@@ -3894,7 +4692,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspace-quota/{user}": {
+        "/webhooks": {
             "get": {
                 "security": [
                     {
@@ -3905,30 +4703,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Get workspace quota by user",
-                "operationId": "get-workspace-quota-by-user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Webhooks"
                 ],
+                "summary": "Get webhooks",
+                "operationId": "get-webhooks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Webhook"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/workspaceagents/aws-instance-identity": {
+            },
             "post": {
                 "security": [
                     {
@@ -3942,72 +4732,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Webhooks"
                 ],
-                "summary": "Authenticate agent on AWS instance",
-                "operationId": "authenticate-agent-on-aws-instance",
+                "summary": "Create webhook",
+                "operationId": "create-webhook",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "description": "Create webhook request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.CreateWebhookRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.Webhook"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/azure-instance-identity": {
-            "post": {
+        "/webhooks/{webhook}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Webhooks"
                 ],
-                "summary": "Authenticate agent on Azure instance",
-                "operationId": "authenticate-agent-on-azure-instance",
+                "summary": "Get webhook by ID",
+                "operationId": "get-webhook-by-id",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.Webhook"
                         }
                     }
                 }
-            }
-        },
-        "/workspaceagents/connection": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4017,25 +4801,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook",
+                "operationId": "delete-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get connection info for workspace agent generic",
-                "operationId": "get-connection-info-for-workspace-agent-generic",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
-            }
-        },
-        "/workspaceagents/google-instance-identity": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4048,18 +4837,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Webhooks"
                 ],
-                "summary": "Authenticate agent on Google Cloud instance",
-                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "summary": "Update webhook",
+                "operationId": "update-webhook",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update webhook request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.UpdateWebhookRequest"
                         }
                     }
                 ],
@@ -4067,83 +4864,312 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.Webhook"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/app-health": {
-            "post": {
+        "/webhooks/{webhook}/deliveries": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Webhooks"
                 ],
-                "summary": "Submit workspace agent application health",
-                "operationId": "submit-workspace-agent-application-health",
+                "summary": "Get webhook deliveries",
+                "operationId": "get-webhook-deliveries",
                 "parameters": [
                     {
-                        "description": "Application health request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WebhookDelivery"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/coordinate": {
+        "/workspace-quota/{user}": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
+                ],
+                "summary": "Get workspace quota by user",
+                "operationId": "get-workspace-quota-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Coordinate workspace agent via Tailnet",
-                "operationId": "coordinate-workspace-agent-via-tailnet",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitauth": {
-            "get": {
+        "/workspaceagents/aws-instance-identity": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get workspace agent Git auth",
-                "operationId": "get-workspace-agent-git-auth",
+                "summary": "Authenticate agent on AWS instance",
+                "operationId": "authenticate-agent-on-aws-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/azure-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on Azure instance",
+                "operationId": "authenticate-agent-on-azure-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent generic",
+                "operationId": "get-connection-info-for-workspace-agent-generic",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/google-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on Google Cloud instance",
+                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/app-health": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent application health",
+                "operationId": "submit-workspace-agent-application-health",
+                "parameters": [
+                    {
+                        "description": "Application health request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent via Tailnet",
+                "operationId": "coordinate-workspace-agent-via-tailnet",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitauth": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent Git auth",
+                "operationId": "get-workspace-agent-git-auth",
                 "parameters": [
                     {
                         "type": "string",
@@ -4264,6 +5290,42 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaceagents/me/processes": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent processes",
+                "operationId": "submit-workspace-agent-processes",
+                "parameters": [
+                    {
+                        "description": "Processes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentProcesses"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
         "/workspaceagents/me/report-lifecycle": {
             "post": {
                 "security": [
@@ -4333,63 +5395,382 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/script-status": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent script status",
+                "operationId": "submit-workspace-agent-script-status",
+                "parameters": [
+                    {
+                        "description": "Script status request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostScriptStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/session-recordings": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Upload workspace agent session recording chunk",
+                "operationId": "upload-workspace-agent-session-recording-chunk",
+                "parameters": [
+                    {
+                        "description": "Session recording chunk",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostSessionRecordingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent startup",
+                "operationId": "submit-workspace-agent-startup",
+                "parameters": [
+                    {
+                        "description": "Startup request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup-logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Patch workspace agent startup logs",
+                "operationId": "patch-workspace-agent-startup-logs",
+                "parameters": [
+                    {
+                        "description": "Startup logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchStartupLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent by ID",
+                "operationId": "get-workspace-agent-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/activity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Report user activity in workspace agent",
+                "operationId": "report-user-activity-in-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent",
+                "operationId": "get-connection-info-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent",
+                "operationId": "coordinate-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/files": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "List files in workspace agent",
+                "operationId": "list-files-in-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File or directory path, relative paths are resolved from the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentListFilesResponse"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/startup": {
-            "post": {
+        "/workspaceagents/{workspaceagent}/files/contents": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent startup",
-                "operationId": "submit-workspace-agent-startup",
+                "summary": "Read file from workspace agent",
+                "operationId": "read-file-from-workspace-agent",
                 "parameters": [
                     {
-                        "description": "Startup request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path, relative paths are resolved from the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
-            }
-        },
-        "/workspaceagents/me/startup-logs": {
-            "patch": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Swagger notice: Swagger 2.0 doesn't support file upload with a ` + "`" + `content-type` + "`" + ` different than ` + "`" + `application/x-www-form-urlencoded` + "`" + `.",
                 "consumes": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "produces": [
                     "application/json"
@@ -4397,47 +5778,101 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Patch workspace agent startup logs",
-                "operationId": "patch-workspace-agent-startup-logs",
+                "summary": "Write file to workspace agent",
+                "operationId": "write-file-to-workspace-agent",
                 "parameters": [
                     {
-                        "description": "Startup logs",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchStartupLogs"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File contents",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path, relative paths are resolved from the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Octal file permissions, defaults to 644",
+                        "name": "mode",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentFile"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}": {
+        "/workspaceagents/{workspaceagent}/files/tar": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Download tar archive from workspace agent",
+                "operationId": "download-tar-archive-from-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File or directory path, relative paths are resolved from the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Swagger notice: Swagger 2.0 doesn't support file upload with a ` + "`" + `content-type` + "`" + ` different than ` + "`" + `application/x-www-form-urlencoded` + "`" + `.",
+                "consumes": [
+                    "application/x-tar"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get workspace agent by ID",
-                "operationId": "get-workspace-agent-by-id",
+                "summary": "Upload tar archive to workspace agent",
+                "operationId": "upload-tar-archive-to-workspace-agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -4446,19 +5881,33 @@ const docTemplate = `{
                         "name": "workspaceagent",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Tar archive",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Directory to extract into, relative paths are resolved from the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentFile"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/connection": {
+        "/workspaceagents/{workspaceagent}/listening-ports": {
             "get": {
                 "security": [
                     {
@@ -4471,8 +5920,8 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get connection info for workspace agent",
-                "operationId": "get-connection-info-for-workspace-agent",
+                "summary": "Get listening ports for workspace agent",
+                "operationId": "get-listening-ports-for-workspace-agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -4487,24 +5936,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/coordinate": {
+        "/workspaceagents/{workspaceagent}/processes": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Coordinate workspace agent",
-                "operationId": "coordinate-workspace-agent",
+                "summary": "Get processes of workspace agent",
+                "operationId": "get-processes-of-workspace-agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -4516,27 +5968,30 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentProcesses"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/listening-ports": {
-            "get": {
+        "/workspaceagents/{workspaceagent}/processes/{pid}/kill": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get listening ports for workspace agent",
-                "operationId": "get-listening-ports-for-workspace-agent",
+                "summary": "Kill process in workspace agent",
+                "operationId": "kill-process-in-workspace-agent",
                 "parameters": [
                     {
                         "type": "string",
@@ -4545,14 +6000,27 @@ const docTemplate = `{
                         "name": "workspaceagent",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Process ID",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Kill request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.KillWorkspaceAgentProcessRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
@@ -5558,6 +7026,81 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaces/{workspace}/session-recordings": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get session recordings of workspace",
+                "operationId": "get-session-recordings-of-workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.SessionRecording"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/session-recordings/{recording}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Download session recording of workspace",
+                "operationId": "download-session-recording-of-workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Session recording ID",
+                        "name": "recording",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
         "/workspaces/{workspace}/ttl": {
             "put": {
                 "security": [
@@ -5806,6 +7349,28 @@ const docTemplate = `{
                 "motd_file": {
                     "type": "string"
                 },
+                "process_report_interval": {
+                    "description": "ProcessReportInterval is how often the agent reports the resource\nusage of its processes. Zero disables reporting.",
+                    "type": "integer"
+                },
+                "record_sessions": {
+                    "description": "RecordSessions enables recording of SSH and reconnecting PTY sessions.",
+                    "type": "boolean"
+                },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
+                },
+                "server_binary_checksum": {
+                    "description": "ServerBinaryChecksum is the SHA1 checksum of the agent binary coderd\nserves for the agent's operating system and architecture. The agent\nverifies updates against it, and doesn't update if it's empty.",
+                    "type": "string"
+                },
+                "server_version": {
+                    "description": "ServerVersion is the version of coderd. The agent updates itself\nto match it if self-updates are enabled.",
+                    "type": "string"
+                },
                 "shutdown_script": {
                     "type": "string"
                 },
@@ -5826,6 +7391,11 @@ const docTemplate = `{
         "agentsdk.PatchStartupLogs": {
             "type": "object",
             "properties": {
+                "log_source_id": {
+                    "description": "LogSourceID is the log source the logs belong to. It is the zero\nUUID for the legacy startup script.",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "logs": {
                     "type": "array",
                     "items": {
@@ -5876,6 +7446,63 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.PostScriptStatusRequest": {
+            "type": "object",
+            "properties": {
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "log_source_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentScriptStatus"
+                }
+            }
+        },
+        "agentsdk.PostSessionRecordingRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "final": {
+                    "description": "Final is set on the last chunk of a recording, after the session has\nended.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "type": {
+                    "$ref": "#/definitions/codersdk.SessionRecordingType"
+                },
+                "user_id": {
+                    "description": "UserID is the user that connected to the session, as reported by the\ncoordinator. It is the zero UUID if the connection is unattributed.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "agentsdk.PostStartupRequest": {
             "type": "object",
             "properties": {
@@ -5922,6 +7549,11 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "last_activity_at": {
+                    "description": "LastActivityAt is the last time a user interacted with the workspace,\ne.g. typed into a terminal, used an app or had VS Code focused. It's\nzero if there has been no activity since the agent started.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "metrics": {
                     "description": "Metrics collected by the agent",
                     "type": "array",
@@ -6271,7 +7903,8 @@ const docTemplate = `{
                         "password",
                         "github",
                         "oidc",
-                        "token"
+                        "token",
+                        "oauth2_provider_app"
                     ],
                     "allOf": [
                         {
@@ -6337,6 +7970,10 @@ const docTemplate = `{
         "codersdk.AppHostResponse": {
             "type": "object",
             "properties": {
+                "disable_path_apps": {
+                    "description": "DisablePathApps is true if apps can only be accessed on a subdomain.",
+                    "type": "boolean"
+                },
                 "host": {
                     "description": "Host is the externally accessible URL for the Coder instance.",
                     "type": "string"
@@ -6617,6 +8254,39 @@ const docTemplate = `{
                 "BuildReasonAutostop"
             ]
         },
+        "codersdk.CreateCustomRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "display_name": {
+                    "description": "DisplayName defaults to the name.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
         "codersdk.CreateFirstUserRequest": {
             "type": "object",
             "required": [
@@ -6666,6 +8336,25 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "callback_url",
+                "name"
+            ],
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateOrganizationRequest": {
             "type": "object",
             "required": [
@@ -6720,6 +8409,10 @@ const docTemplate = `{
                     "description": "Icon is a relative path or external URL that specifies\nan icon to be displayed in the dashboard.",
                     "type": "string"
                 },
+                "idle_threshold_ms": {
+                    "description": "IdleThresholdMillis allows optionally stopping workspaces created from\nthis template once their agents report no user activity for this long.",
+                    "type": "integer"
+                },
                 "inactivity_ttl_ms": {
                     "description": "InactivityTTLMillis allows optionally specifying the max lifetime before Coder\nlocks inactive workspaces created from this template.",
                     "type": "integer"
@@ -6926,6 +8619,34 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "endpoint",
+                "events",
+                "name",
+                "secret"
+            ],
+            "properties": {
+                "endpoint": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret is the key deliveries are signed with.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateWorkspaceBuildRequest": {
             "type": "object",
             "required": [
@@ -7028,6 +8749,51 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CustomRole": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
         "codersdk.DAUEntry": {
             "type": "object",
             "properties": {
@@ -7254,6 +9020,9 @@ const docTemplate = `{
                 "metrics_cache_refresh_interval": {
                     "type": "integer"
                 },
+                "notifications": {
+                    "$ref": "#/definitions/codersdk.NotificationsConfig"
+                },
                 "oauth2": {
                     "$ref": "#/definitions/codersdk.OAuth2Config"
                 },
@@ -7299,6 +9068,9 @@ const docTemplate = `{
                 "secure_auth_cookie": {
                     "type": "boolean"
                 },
+                "session_recording": {
+                    "type": "boolean"
+                },
                 "ssh_keygen_algorithm": {
                     "type": "string"
                 },
@@ -7591,6 +9363,23 @@ const docTemplate = `{
                 "RequiredTemplateVariables"
             ]
         },
+        "codersdk.KillWorkspaceAgentProcessRequest": {
+            "type": "object",
+            "properties": {
+                "signal": {
+                    "description": "Signal defaults to terminate.",
+                    "enum": [
+                        "terminate",
+                        "kill"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentProcessSignal"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.License": {
             "type": "object",
             "properties": {
@@ -7675,14 +9464,16 @@ const docTemplate = `{
                 "github",
                 "oidc",
                 "token",
-                "none"
+                "none",
+                "oauth2_provider_app"
             ],
             "x-enum-varnames": [
                 "LoginTypePassword",
                 "LoginTypeGithub",
                 "LoginTypeOIDC",
                 "LoginTypeToken",
-                "LoginTypeNone"
+                "LoginTypeNone",
+                "LoginTypeOAuth2ProviderApp"
             ]
         },
         "codersdk.LoginWithPasswordRequest": {
@@ -7712,6 +9503,99 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                },
+                "template": {
+                    "enum": [
+                        "workspace_autostopped",
+                        "workspace_build_failed",
+                        "workspace_marked_for_deletion",
+                        "user_created",
+                        "user_suspended"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NotificationTemplate"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.NotificationTemplate": {
+            "type": "string",
+            "enum": [
+                "workspace_autostopped",
+                "workspace_build_failed",
+                "workspace_marked_for_deletion",
+                "user_created",
+                "user_suspended"
+            ],
+            "x-enum-varnames": [
+                "NotificationTemplateWorkspaceAutostopped",
+                "NotificationTemplateWorkspaceBuildFailed",
+                "NotificationTemplateWorkspaceMarkedForDeletion",
+                "NotificationTemplateUserCreated",
+                "NotificationTemplateUserSuspended"
+            ]
+        },
+        "codersdk.NotificationsConfig": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "$ref": "#/definitions/codersdk.NotificationsEmailConfig"
+                },
+                "max_send_attempts": {
+                    "description": "MaxSendAttempts is how often sending a notification is attempted\nbefore it's marked as failed.",
+                    "type": "integer"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/codersdk.NotificationsWebhookConfig"
+                }
+            }
+        },
+        "codersdk.NotificationsEmailConfig": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "hello": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "smarthost": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.NotificationsWebhookConfig": {
+            "type": "object",
+            "properties": {
+                "endpoint": {
+                    "$ref": "#/definitions/clibase.URL"
+                }
+            }
+        },
+        "codersdk.OAuth2AppEndpoints": {
+            "type": "object",
+            "properties": {
+                "authorization": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.OAuth2Config": {
             "type": "object",
             "properties": {
@@ -7744,11 +9628,69 @@ const docTemplate = `{
                 "client_id": {
                     "type": "string"
                 },
-                "client_secret": {
-                    "type": "string"
+                "client_secret": {
+                    "type": "string"
+                },
+                "enterprise_base_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderApp": {
+            "type": "object",
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "endpoints": {
+                    "description": "Endpoints are included in the app response for easier discovery. The\nOAuth2 spec does not have a defined place to find these (for comparison,\nOIDC has a '/.well-known/openid-configuration' endpoint).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.OAuth2AppEndpoints"
+                        }
+                    ]
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecret": {
+            "type": "object",
+            "properties": {
+                "client_secret_truncated": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "enterprise_base_url": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecretFull": {
+            "type": "object",
+            "properties": {
+                "client_secret_full": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -7817,6 +9759,12 @@ const docTemplate = `{
                 "sign_in_text": {
                     "type": "string"
                 },
+                "user_role_field": {
+                    "type": "string"
+                },
+                "user_role_mapping": {
+                    "type": "object"
+                },
                 "username_field": {
                     "type": "string"
                 }
@@ -7910,6 +9858,26 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.Permission": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "read",
+                        "update",
+                        "delete"
+                    ]
+                },
+                "negate": {
+                    "type": "boolean"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.RBACResource"
+                }
+            }
+        },
         "codersdk.PprofConfig": {
             "type": "object",
             "properties": {
@@ -8210,7 +10178,11 @@ const docTemplate = `{
                 "deployment_stats",
                 "replicas",
                 "debug_info",
-                "system"
+                "system",
+                "webhook",
+                "custom_role",
+                "oauth2_app",
+                "oauth2_app_secret"
             ],
             "x-enum-varnames": [
                 "ResourceWorkspace",
@@ -8234,7 +10206,11 @@ const docTemplate = `{
                 "ResourceDeploymentStats",
                 "ResourceReplicas",
                 "ResourceDebugInfo",
-                "ResourceSystem"
+                "ResourceSystem",
+                "ResourceWebhook",
+                "ResourceCustomRole",
+                "ResourceOAuth2ProviderApp",
+                "ResourceOAuth2ProviderAppSecret"
             ]
         },
         "codersdk.RateLimitConfig": {
@@ -8334,7 +10310,9 @@ const docTemplate = `{
                 "git_ssh_key",
                 "api_key",
                 "group",
-                "license"
+                "license",
+                "oauth2_provider_app",
+                "oauth2_provider_app_secret"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -8345,7 +10323,9 @@ const docTemplate = `{
                 "ResourceTypeGitSSHKey",
                 "ResourceTypeAPIKey",
                 "ResourceTypeGroup",
-                "ResourceTypeLicense"
+                "ResourceTypeLicense",
+                "ResourceTypeOAuth2ProviderApp",
+                "ResourceTypeOAuth2ProviderAppSecret"
             ]
         },
         "codersdk.Response": {
@@ -8440,6 +10420,67 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SessionRecording": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ended_at": {
+                    "description": "EndedAt is unset while the session is in progress.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "size": {
+                    "description": "Size is the size of the recording in bytes.",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "type": {
+                    "enum": [
+                        "ssh",
+                        "reconnecting_pty"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.SessionRecordingType"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "UserID is the user that connected to the session. It is unset if the\nagent could not attribute the connection.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_build_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.SessionRecordingType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "reconnecting_pty"
+            ],
+            "x-enum-varnames": [
+                "SessionRecordingTypeSSH",
+                "SessionRecordingTypeReconnectingPTY"
+            ]
+        },
         "codersdk.SupportConfig": {
             "type": "object",
             "properties": {
@@ -8566,6 +10607,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "idle_threshold_ms": {
+                    "description": "IdleThresholdMillis is how long workspaces can go without user\nactivity reported by their agents before they are stopped. Zero\ndisables idle detection.",
+                    "type": "integer"
+                },
                 "inactivity_ttl_ms": {
                     "type": "integer"
                 },
@@ -8978,6 +11023,67 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateCustomRoleRequest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "description": "DisplayName defaults to the name.",
+                    "type": "string"
+                },
+                "organization_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "site_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                },
+                "user_permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Permission"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "description": "Preferences to change, templates that are left out keep their\npreference.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.NotificationPreference"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "callback_url",
+                "name"
+            ],
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.UpdateRoles": {
             "type": "object",
             "properties": {
@@ -9031,6 +11137,33 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "endpoint",
+                "events",
+                "name"
+            ],
+            "properties": {
+                "endpoint": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret replaces the key deliveries are signed with, the key is kept\nwhen empty.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceAutostartRequest": {
             "type": "object",
             "properties": {
@@ -9108,68 +11241,173 @@ const docTemplate = `{
                 },
                 "status": {
                     "enum": [
-                        "active",
-                        "suspended"
+                        "active",
+                        "suspended"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UserStatus"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UserStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "suspended"
+            ],
+            "x-enum-varnames": [
+                "UserStatusActive",
+                "UserStatusSuspended"
+            ]
+        },
+        "codersdk.ValidationError": {
+            "type": "object",
+            "required": [
+                "detail",
+                "field"
+            ],
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ValidationMonotonicOrder": {
+            "type": "string",
+            "enum": [
+                "increasing",
+                "decreasing"
+            ],
+            "x-enum-varnames": [
+                "MonotonicOrderIncreasing",
+                "MonotonicOrderDecreasing"
+            ]
+        },
+        "codersdk.VariableValue": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/codersdk.WebhookEvent"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/codersdk.UserStatus"
+                            "$ref": "#/definitions/codersdk.WebhookDeliveryStatus"
                         }
                     ]
                 },
-                "username": {
-                    "type": "string"
+                "status_code": {
+                    "description": "StatusCode is the HTTP status code of the response to the last\nattempt, it's unset when no response was received.",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "webhook_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.UserStatus": {
+        "codersdk.WebhookDeliveryStatus": {
             "type": "string",
             "enum": [
-                "active",
-                "suspended"
+                "pending",
+                "succeeded",
+                "failed"
             ],
             "x-enum-varnames": [
-                "UserStatusActive",
-                "UserStatusSuspended"
+                "WebhookDeliveryStatusPending",
+                "WebhookDeliveryStatusSucceeded",
+                "WebhookDeliveryStatusFailed"
             ]
         },
-        "codersdk.ValidationError": {
-            "type": "object",
-            "required": [
-                "detail",
-                "field"
-            ],
-            "properties": {
-                "detail": {
-                    "type": "string"
-                },
-                "field": {
-                    "type": "string"
-                }
-            }
-        },
-        "codersdk.ValidationMonotonicOrder": {
+        "codersdk.WebhookEvent": {
             "type": "string",
             "enum": [
-                "increasing",
-                "decreasing"
+                "workspace_build_started",
+                "workspace_build_succeeded",
+                "workspace_build_failed",
+                "template_version_pushed",
+                "user_created"
             ],
             "x-enum-varnames": [
-                "MonotonicOrderIncreasing",
-                "MonotonicOrderDecreasing"
+                "WebhookEventWorkspaceBuildStarted",
+                "WebhookEventWorkspaceBuildSucceeded",
+                "WebhookEventWorkspaceBuildFailed",
+                "WebhookEventTemplateVersionPushed",
+                "WebhookEventUserCreated"
             ]
         },
-        "codersdk.VariableValue": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
-                }
-            }
-        },
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
@@ -9298,6 +11536,12 @@ const docTemplate = `{
                 "lifecycle_state": {
                     "$ref": "#/definitions/codersdk.WorkspaceAgentLifecycle"
                 },
+                "log_sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentLogSource"
+                    }
+                },
                 "login_before_ready": {
                     "description": "Deprecated: Use StartupScriptBehavior instead.",
                     "type": "boolean"
@@ -9316,6 +11560,12 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
+                },
                 "shutdown_script": {
                     "type": "string"
                 },
@@ -9371,6 +11621,31 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentFile": {
+            "type": "object",
+            "properties": {
+                "is_dir": {
+                    "type": "boolean"
+                },
+                "mod_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is the absolute path of the file.",
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentLifecycle": {
             "type": "string",
             "enum": [
@@ -9396,6 +11671,26 @@ const docTemplate = `{
                 "WorkspaceAgentLifecycleOff"
             ]
         },
+        "codersdk.WorkspaceAgentListFilesResponse": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "description": "File is the file or directory that was listed.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentFile"
+                        }
+                    ]
+                },
+                "files": {
+                    "description": "Files are the contents of the directory. It is empty if File is\nnot a directory.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentFile"
+                    }
+                }
+            }
+        },
         "codersdk.WorkspaceAgentListeningPort": {
             "type": "object",
             "properties": {
@@ -9424,9 +11719,36 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentLogSource": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentMetadataDescription": {
             "type": "object",
             "properties": {
+                "collector": {
+                    "description": "Collector is the name of a built-in collector the agent evaluates\nin-process instead of running Script, e.g. \"cpu\" or \"disk:/home\".",
+                    "type": "string"
+                },
                 "display_name": {
                     "type": "string"
                 },
@@ -9444,6 +11766,192 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentProcess": {
+            "type": "object",
+            "properties": {
+                "cmdline": {
+                    "type": "string"
+                },
+                "cpu_cores": {
+                    "description": "CPUCores is the number of cores used since the previous report.",
+                    "type": "number"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "ppid": {
+                    "type": "integer"
+                },
+                "session_pid": {
+                    "description": "SessionPID is the PID of the session the process belongs to, or\nzero if it wasn't started by a session.",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentProcessSignal": {
+            "type": "string",
+            "enum": [
+                "terminate",
+                "kill"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentProcessSignalTerminate",
+                "WorkspaceAgentProcessSignalKill"
+            ]
+        },
+        "codersdk.WorkspaceAgentProcesses": {
+            "type": "object",
+            "properties": {
+                "collected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "cpu": {
+                    "description": "CPU and Memory are the totals of the container cgroup if the agent\nruns in a container, otherwise of the host.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentResourceUsage"
+                        }
+                    ]
+                },
+                "memory": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentResourceUsage"
+                },
+                "process_count": {
+                    "description": "ProcessCount is the number of processes visible to the agent.",
+                    "type": "integer"
+                },
+                "processes": {
+                    "description": "Processes are the processes using the most CPU and memory, along\nwith their ancestors so the process tree can be rebuilt from PPID.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentProcess"
+                    }
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentSessionUsage"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentResourceUsage": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "description": "Total is unset if the resource is unlimited.",
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "used": {
+                    "type": "number"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentScript": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "log_path": {
+                    "type": "string"
+                },
+                "log_source_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "run_on_start": {
+                    "type": "boolean"
+                },
+                "run_on_stop": {
+                    "type": "boolean"
+                },
+                "script": {
+                    "type": "string"
+                },
+                "start_blocks_login": {
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentScriptStatus"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentScriptStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "succeeded",
+                "failed",
+                "timed_out"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentScriptStatusPending",
+                "WorkspaceAgentScriptStatusRunning",
+                "WorkspaceAgentScriptStatusSucceeded",
+                "WorkspaceAgentScriptStatusFailed",
+                "WorkspaceAgentScriptStatusTimedOut"
+            ]
+        },
+        "codersdk.WorkspaceAgentSessionUsage": {
+            "type": "object",
+            "properties": {
+                "cpu_cores": {
+                    "description": "CPUCores is the number of cores used since the previous report.",
+                    "type": "number"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "pid": {
+                    "description": "PID is the process the session was started with.",
+                    "type": "integer"
+                },
+                "process_count": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "type": {
+                    "description": "Type is one of \"ssh\", \"vscode\", \"jetbrains\" or \"reconnecting_pty\".",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentStartupLog": {
             "type": "object",
             "properties": {
@@ -9459,6 +11967,10 @@ const docTemplate = `{
                 },
                 "output": {
                     "type": "string"
+                },
+                "source_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -319,7 +319,7 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 	aReq.Old = key
 	defer commitAudit()
 
-	err = api.Database.DeleteAPIKeyByID(ctx, keyID)
+	_, err = api.Database.DeleteAPIKeyByID(ctx, keyID)
 	if httpapi.Is404Error(err) {
 		httpapi.ResourceNotFound(rw)
 		return
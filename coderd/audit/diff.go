@@ -17,7 +17,10 @@ type Auditable interface {
 		database.WorkspaceBuild |
 		database.AuditableGroup |
 		database.License |
-		database.WorkspaceProxy
+		database.WorkspaceProxy |
+		database.Webhook |
+		database.OAuth2ProviderApp |
+		database.OAuth2ProviderAppSecret
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
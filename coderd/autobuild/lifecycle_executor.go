@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/db2sdk"
 	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/wsbuilder"
 	"github.com/coder/coder/codersdk"
@@ -28,8 +29,16 @@ type Executor struct {
 	log                   slog.Logger
 	tick                  <-chan time.Time
 	statsCh               chan<- Stats
+	notifications         notifications.Enqueuer
+	// lastDeletionScan is when workspaces marked for deletion were last
+	// notified, which happens at most every deletionScanInterval.
+	lastDeletionScan time.Time
 }
 
+// deletionScanInterval is how often owners of workspaces marked for deletion
+// are notified. Each is only notified once for a deletion date.
+const deletionScanInterval = time.Hour
+
 // Stats contains information about one run of Executor.
 type Stats struct {
 	Transitions map[uuid.UUID]database.WorkspaceTransition
@@ -46,6 +55,7 @@ func NewExecutor(ctx context.Context, db database.Store, tss *atomic.Pointer[sch
 		templateScheduleStore: tss,
 		tick:                  tick,
 		log:                   log.Named("autobuild"),
+		notifications:         notifications.NewNoopEnqueuer(),
 	}
 	return le
 }
@@ -57,6 +67,15 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithNotificationsEnqueuer will cause Executor to notify owners of
+// workspaces it stops, and of workspaces marked for deletion.
+func (e *Executor) WithNotificationsEnqueuer(enqueuer notifications.Enqueuer) *Executor {
+	if enqueuer != nil {
+		e.notifications = enqueuer
+	}
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -127,6 +146,11 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		log := e.log.With(slog.F("workspace_id", wsID))
 
 		eg.Go(func() error {
+			var (
+				stopped  database.Workspace
+				template database.Template
+				build    *database.WorkspaceBuild
+			)
 			err := e.db.InTx(func(tx database.Store) error {
 				// Re-check eligibility since the first check was outside the
 				// transaction and the workspace settings may have changed.
@@ -165,13 +189,22 @@ func (e *Executor) runOnce(t time.Time) Stats {
 					SetLastWorkspaceBuildJobInTx(&latestJob).
 					Reason(reason)
 
-				if _, _, err := builder.Build(e.ctx, tx, nil); err != nil {
+				newBuild, _, err := builder.Build(e.ctx, tx, nil)
+				if err != nil {
 					log.Error(e.ctx, "workspace build error",
 						slog.F("transition", nextTransition),
 						slog.Error(err),
 					)
 					return nil
 				}
+				if reason == database.BuildReasonAutostop {
+					template, err = tx.GetTemplateByID(e.ctx, ws.TemplateID)
+					if err != nil {
+						log.Warn(e.ctx, "get template", slog.Error(err))
+					} else {
+						stopped, build = ws, newBuild
+					}
+				}
 				statsMu.Lock()
 				stats.Transitions[ws.ID] = nextTransition
 				statsMu.Unlock()
@@ -185,6 +218,17 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 			if err != nil {
 				log.Error(e.ctx, "workspace scheduling failed", slog.Error(err))
+				return nil
+			}
+			if build != nil {
+				err = e.notifications.Enqueue(e.ctx, stopped.OwnerID, codersdk.NotificationTemplateWorkspaceAutostopped, map[string]string{
+					"workspace": stopped.Name,
+					"template":  template.Name,
+					"build_id":  build.ID.String(),
+				})
+				if err != nil {
+					log.Warn(e.ctx, "notify workspace autostopped", slog.Error(err))
+				}
 			}
 			return nil
 		})
@@ -196,9 +240,59 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		e.log.Error(e.ctx, "workspace scheduling errgroup failed", slog.Error(err))
 	}
 
+	if t.Sub(e.lastDeletionScan) >= deletionScanInterval {
+		e.lastDeletionScan = t
+		e.notifyMarkedForDeletion(t)
+	}
+
 	return stats
 }
 
+// notifyMarkedForDeletion notifies the owners of workspaces that will be
+// deleted for inactivity. Workspaces are marked for deletion when their
+// template has an inactivity TTL and they aren't running. Once inactive for
+// the inactivity TTL they're locked, and if the template has a locked TTL
+// they're only deleted after being locked for that long too.
+func (e *Executor) notifyMarkedForDeletion(t time.Time) {
+	workspaces, err := e.db.GetWorkspacesMarkedForDeletion(e.ctx)
+	if err != nil {
+		e.log.Error(e.ctx, "get workspaces marked for deletion", slog.Error(err))
+		return
+	}
+	for _, ws := range workspaces {
+		log := e.log.With(slog.F("workspace_id", ws.ID))
+		templateSchedule, err := (*(e.templateScheduleStore.Load())).GetTemplateScheduleOptions(e.ctx, e.db, ws.TemplateID)
+		if err != nil {
+			log.Warn(e.ctx, "get template schedule options", slog.Error(err))
+			continue
+		}
+		// The inactivity TTL is only set when the license allows it.
+		if templateSchedule.InactivityTTL <= 0 {
+			continue
+		}
+		deletingAt := ws.LastUsedAt.Add(templateSchedule.InactivityTTL + templateSchedule.LockedTTL)
+		if deletingAt.Before(t) {
+			continue
+		}
+		template, err := e.db.GetTemplateByID(e.ctx, ws.TemplateID)
+		if err != nil {
+			log.Warn(e.ctx, "get template", slog.Error(err))
+			continue
+		}
+		// The deletion date is a label, so owners are notified again when it
+		// changes.
+		err = e.notifications.Enqueue(e.ctx, ws.OwnerID, codersdk.NotificationTemplateWorkspaceMarkedForDeletion, map[string]string{
+			"workspace":    ws.Name,
+			"template":     template.Name,
+			"workspace_id": ws.ID.String(),
+			"deleting_at":  deletingAt.UTC().Format(time.RFC1123),
+		})
+		if err != nil {
+			log.Warn(e.ctx, "notify workspace marked for deletion", slog.Error(err))
+		}
+	}
+}
+
 func getNextTransition(
 	ws database.Workspace,
 	latestBuild database.WorkspaceBuild,
@@ -16,6 +16,7 @@ import (
 	"github.com/coder/coder/coderd/autobuild"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
@@ -213,6 +214,48 @@ func TestExecutorAutostopOK(t *testing.T) {
 	assert.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
 }
 
+func TestExecutorAutostopNotifies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tickCh   = make(chan time.Time)
+		statsCh  = make(chan autobuild.Stats)
+		enqueuer = notifications.NewMock()
+		client   = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+			NotificationsEnqueuer:    enqueuer,
+		})
+		// Given: we have a user with a running workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+
+	// When: the autobuild executor ticks after the deadline
+	go func() {
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(time.Minute)
+		close(tickCh)
+	}()
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Transitions, 1)
+
+	// Then: the owner should be notified about the autostop
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	var sent []notifications.MockNotification
+	for _, n := range enqueuer.Notifications() {
+		if n.Template == codersdk.NotificationTemplateWorkspaceAutostopped {
+			sent = append(sent, n)
+		}
+	}
+	require.Len(t, sent, 1)
+	assert.Equal(t, workspace.OwnerID, sent[0].UserID)
+	assert.Equal(t, workspace.Name, sent[0].Labels["workspace"])
+	assert.Equal(t, workspace.TemplateName, sent[0].Labels["template"])
+	assert.Equal(t, workspace.LatestBuild.ID.String(), sent[0].Labels["build_id"])
+}
+
 func TestExecutorAutostopExtend(t *testing.T) {
 	t.Parallel()
 
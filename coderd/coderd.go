@@ -52,6 +52,7 @@ import (
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/metricscache"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
@@ -59,6 +60,7 @@ import (
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/coderd/updatecheck"
 	"github.com/coder/coder/coderd/util/slice"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/coderd/wsconncache"
 	"github.com/coder/coder/codersdk"
@@ -150,6 +152,13 @@ type Options struct {
 	HTTPClient *http.Client
 
 	UpdateAgentMetrics func(ctx context.Context, username, workspaceName, agentName string, metrics []agentsdk.AgentMetric)
+
+	// NotificationsEnqueuer enqueues notifications to users, they're dropped
+	// when unset.
+	NotificationsEnqueuer notifications.Enqueuer
+	// WebhooksEnqueuer enqueues deliveries of events to webhooks, they're
+	// dropped when unset.
+	WebhooksEnqueuer webhooks.Enqueuer
 }
 
 // @title Coder API
@@ -238,6 +247,12 @@ func New(options *Options) *API {
 	if options.Auditor == nil {
 		options.Auditor = audit.NewNop()
 	}
+	if options.NotificationsEnqueuer == nil {
+		options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
+	}
+	if options.WebhooksEnqueuer == nil {
+		options.WebhooksEnqueuer = webhooks.NewNoopEnqueuer()
+	}
 	if options.SSHConfig.HostnamePrefix == "" {
 		options.SSHConfig.HostnamePrefix = "coder."
 	}
@@ -336,6 +351,7 @@ func New(options *Options) *API {
 		TemplateScheduleStore: options.TemplateScheduleStore,
 		Experiments:           experiments,
 		healthCheckGroup:      &singleflight.Group[string, *healthcheck.Report]{},
+		idleThresholds:        newIdleThresholdCache(options.Database),
 	}
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -377,6 +393,7 @@ func New(options *Options) *API {
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
 		Optional:                    false,
+		SessionTokenFunc:            httpmw.APITokenOrBearerFromRequest,
 	})
 	// Same as above but it redirects to the login page.
 	apiKeyMiddlewareRedirect := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -466,6 +483,21 @@ func New(options *Options) *API {
 			})
 		}
 	})
+	// OAuth2 provider endpoints of the apps authenticating users against
+	// Coder. They are not under /api as apps use them as defined by RFC 6749.
+	r.Route("/oauth2", func(r chi.Router) {
+		r.Use(apiRateLimiter)
+		r.Route("/authorize", func(r chi.Router) {
+			r.Use(apiKeyMiddlewareRedirect)
+			r.Get("/", api.getOAuth2ProviderAppAuthorize)
+			r.Post("/", api.postOAuth2ProviderAppAuthorize)
+		})
+		r.Route("/tokens", func(r chi.Router) {
+			// Apps authenticate with their client secret.
+			r.Post("/", api.postOAuth2ProviderAppToken)
+			r.With(apiKeyMiddleware).Delete("/", api.deleteOAuth2ProviderAppTokens)
+		})
+	})
 	r.Route("/api/v2", func(r chi.Router) {
 		api.APIHandler = r
 
@@ -536,6 +568,16 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/roles", func(r chi.Router) {
+					r.Get("/", api.customOrganizationRoles)
+					r.Post("/", api.postCustomOrganizationRole)
+					r.Route("/{role}", func(r chi.Router) {
+						r.Use(httpmw.ExtractCustomRoleParam(options.Database))
+						r.Get("/", api.customOrganizationRole)
+						r.Patch("/", api.patchCustomOrganizationRole)
+						r.Delete("/", api.deleteCustomOrganizationRole)
+					})
+				})
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
@@ -571,6 +613,7 @@ func New(options *Options) *API {
 			)
 			r.Get("/", api.templateVersion)
 			r.Patch("/", api.patchTemplateVersion)
+			r.Delete("/", api.deleteTemplateVersion)
 			r.Patch("/cancel", api.patchCancelTemplateVersion)
 			// Old agents may expect a non-error response from /schema and /parameters endpoints.
 			// The idea is to return an empty [], so that the coder CLI won't get blocked accidentally.
@@ -665,6 +708,10 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Route("/notifications/preferences", func(r chi.Router) {
+						r.Get("/", api.userNotificationPreferences)
+						r.Put("/", api.putUserNotificationPreferences)
+					})
 				})
 			})
 		})
@@ -688,6 +735,9 @@ func New(options *Options) *API {
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
+				r.Post("/script-status", api.workspaceAgentPostScriptStatus)
+				r.Post("/session-recordings", api.workspaceAgentPostSessionRecording)
+				r.Post("/processes", api.workspaceAgentPostProcesses)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -706,6 +756,14 @@ func New(options *Options) *API {
 				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadata)
 				r.Get("/startup-logs", api.workspaceAgentStartupLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/files", api.workspaceAgentListFiles)
+				r.Get("/files/contents", api.workspaceAgentReadFile)
+				r.Put("/files/contents", api.workspaceAgentWriteFile)
+				r.Get("/files/tar", api.workspaceAgentDownloadTar)
+				r.Put("/files/tar", api.workspaceAgentUploadTar)
+				r.Get("/processes", api.workspaceAgentProcesses)
+				r.Post("/activity", api.workspaceAgentReportActivity)
+				r.Post("/processes/{pid}/kill", api.workspaceAgentKillProcess)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
@@ -735,6 +793,10 @@ func New(options *Options) *API {
 				})
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
+				r.Route("/session-recordings", func(r chi.Router) {
+					r.Get("/", api.workspaceSessionRecordings)
+					r.Get("/{recording}", api.workspaceSessionRecording)
+				})
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -750,6 +812,56 @@ func New(options *Options) *API {
 			r.Get("/resources", api.workspaceBuildResources)
 			r.Get("/state", api.workspaceBuildState)
 		})
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Get("/", api.customSiteRoles)
+			r.Post("/", api.postCustomSiteRole)
+			r.Route("/{role}", func(r chi.Router) {
+				r.Use(httpmw.ExtractCustomRoleParam(options.Database))
+				r.Get("/", api.customSiteRole)
+				r.Patch("/", api.patchCustomSiteRole)
+				r.Delete("/", api.deleteCustomSiteRole)
+			})
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Use(httpmw.ExtractWebhookParam(options.Database))
+				r.Get("/", api.webhook)
+				r.Patch("/", api.patchWebhook)
+				r.Delete("/", api.deleteWebhook)
+				r.Get("/deliveries", api.webhookDeliveries)
+			})
+		})
+		r.Route("/oauth2-provider", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Route("/apps", func(r chi.Router) {
+				r.Get("/", api.oAuth2ProviderApps)
+				r.Post("/", api.postOAuth2ProviderApp)
+				r.Route("/{app}", func(r chi.Router) {
+					r.Use(httpmw.ExtractOAuth2ProviderAppParam(options.Database))
+					r.Get("/", api.oAuth2ProviderApp)
+					r.Patch("/", api.patchOAuth2ProviderApp)
+					r.Delete("/", api.deleteOAuth2ProviderApp)
+					r.Route("/secrets", func(r chi.Router) {
+						r.Get("/", api.oAuth2ProviderAppSecrets)
+						r.Post("/", api.postOAuth2ProviderAppSecret)
+						r.Route("/{secretID}", func(r chi.Router) {
+							r.Use(httpmw.ExtractOAuth2ProviderAppSecretParam(options.Database))
+							r.Delete("/", api.deleteOAuth2ProviderAppSecret)
+						})
+					})
+				})
+			})
+		})
 		r.Route("/authcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.checkAuthorization)
@@ -885,6 +997,8 @@ type API struct {
 
 	healthCheckGroup *singleflight.Group[string, *healthcheck.Report]
 	healthCheckCache atomic.Pointer[healthcheck.Report]
+
+	idleThresholds *idleThresholdCache
 }
 
 // Close waits for all WebSocket connections to drain before returning.
@@ -979,6 +1093,8 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		Tags:                  tags,
 		QuotaCommitter:        &api.QuotaCommitter,
 		Auditor:               &api.Auditor,
+		NotificationsEnqueuer: api.NotificationsEnqueuer,
+		WebhooksEnqueuer:      api.WebhooksEnqueuer,
 		TemplateScheduleStore: api.TemplateScheduleStore,
 		AcquireJobDebounce:    debounce,
 		Logger:                api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
@@ -65,12 +65,14 @@ import (
 	"github.com/coder/coder/coderd/healthcheck"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/unhanger"
 	"github.com/coder/coder/coderd/updatecheck"
 	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -105,6 +107,8 @@ type Options struct {
 	AutobuildTicker       <-chan time.Time
 	AutobuildStats        chan<- autobuild.Stats
 	Auditor               audit.Auditor
+	NotificationsEnqueuer notifications.Enqueuer
+	WebhooksEnqueuer      webhooks.Enqueuer
 	TLSCertificates       []tls.Certificate
 	GitAuthConfigs        []*gitauth.Config
 	TrialGenerator        func(context.Context, string) error
@@ -254,7 +258,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		&templateScheduleStore,
 		slogtest.Make(t, nil).Named("autobuild.executor").Leveled(slog.LevelDebug),
 		options.AutobuildTicker,
-	).WithStatsChannel(options.AutobuildStats)
+	).WithStatsChannel(options.AutobuildStats).WithNotificationsEnqueuer(options.NotificationsEnqueuer)
 	lifecycleExecutor.Run()
 
 	hangDetectorTicker := time.NewTicker(options.DeploymentValues.JobHangDetectorInterval.Value())
@@ -367,6 +371,8 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			GitAuthConfigs:                 options.GitAuthConfigs,
 
 			Auditor:                     options.Auditor,
+			NotificationsEnqueuer:       options.NotificationsEnqueuer,
+			WebhooksEnqueuer:            options.WebhooksEnqueuer,
 			AWSCertificates:             options.AWSCertificates,
 			AzureCertificates:           options.AzureCertificates,
 			GithubOAuth2Config:          options.GithubOAuth2Config,
@@ -0,0 +1,462 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get custom site roles
+// @ID get-custom-site-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Success 200 {array} codersdk.CustomRole
+// @Router /roles [get]
+func (api *API) customSiteRoles(rw http.ResponseWriter, r *http.Request) {
+	api.listCustomRoles(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Create custom site role
+// @ID create-custom-site-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /roles [post]
+func (api *API) postCustomSiteRole(rw http.ResponseWriter, r *http.Request) {
+	api.createCustomRole(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Get custom site role by name
+// @ID get-custom-site-role-by-name
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role name"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [get]
+func (api *API) customSiteRole(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, convertCustomRole(httpmw.CustomRoleParam(r)))
+}
+
+// @Summary Update custom site role
+// @ID update-custom-site-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role name"
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [patch]
+func (api *API) patchCustomSiteRole(rw http.ResponseWriter, r *http.Request) {
+	api.updateCustomRole(rw, r)
+}
+
+// @Summary Delete custom site role
+// @ID delete-custom-site-role
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role name"
+// @Success 200 {object} codersdk.Response
+// @Router /roles/{role} [delete]
+func (api *API) deleteCustomSiteRole(rw http.ResponseWriter, r *http.Request) {
+	api.deleteCustomRole(rw, r)
+}
+
+// @Summary Get custom organization roles
+// @ID get-custom-organization-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.CustomRole
+// @Router /organizations/{organization}/roles [get]
+func (api *API) customOrganizationRoles(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.listCustomRoles(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+// @Summary Create custom organization role
+// @ID create-custom-organization-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /organizations/{organization}/roles [post]
+func (api *API) postCustomOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.createCustomRole(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+// @Summary Get custom organization role by name
+// @ID get-custom-organization-role-by-name
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param role path string true "Role name"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /organizations/{organization}/roles/{role} [get]
+func (api *API) customOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, convertCustomRole(httpmw.CustomRoleParam(r)))
+}
+
+// @Summary Update custom organization role
+// @ID update-custom-organization-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param role path string true "Role name"
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /organizations/{organization}/roles/{role} [patch]
+func (api *API) patchCustomOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	api.updateCustomRole(rw, r)
+}
+
+// @Summary Delete custom organization role
+// @ID delete-custom-organization-role
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param role path string true "Role name"
+// @Success 200 {object} codersdk.Response
+// @Router /organizations/{organization}/roles/{role} [delete]
+func (api *API) deleteCustomOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	api.deleteCustomRole(rw, r)
+}
+
+func (api *API) listCustomRoles(rw http.ResponseWriter, r *http.Request, organizationID uuid.NullUUID) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, customRoleObject(organizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	roles, err := api.Database.GetCustomRoles(ctx, organizationID.UUID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.CustomRole, 0, len(roles))
+	for _, role := range roles {
+		converted = append(converted, convertCustomRole(role))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+func (api *API) createCustomRole(rw http.ResponseWriter, r *http.Request, organizationID uuid.NullUUID) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionCreate, customRoleObject(organizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if rbac.IsBuiltInRoleName(req.Name) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is the name of a built-in role.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "reserved by a built-in role",
+			}},
+		})
+		return
+	}
+	perms, ok := api.customRolePermissions(rw, r, organizationID, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+	if !ok {
+		return
+	}
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Name
+	}
+
+	now := database.Now()
+	role, err := api.Database.InsertCustomRole(ctx, database.InsertCustomRoleParams{
+		ID:              uuid.New(),
+		Name:            req.Name,
+		DisplayName:     displayName,
+		OrganizationID:  organizationID,
+		SitePermissions: perms.site,
+		OrgPermissions:  perms.org,
+		UserPermissions: perms.user,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Custom role with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, convertCustomRole(role))
+}
+
+func (api *API) updateCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		role = httpmw.CustomRoleParam(r)
+	)
+	if !api.Authorize(r, rbac.ActionUpdate, role) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	perms, ok := api.customRolePermissions(rw, r, role.OrganizationID, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+	if !ok {
+		return
+	}
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = role.Name
+	}
+
+	updated, err := api.Database.UpdateCustomRoleByID(ctx, database.UpdateCustomRoleByIDParams{
+		ID:              role.ID,
+		DisplayName:     displayName,
+		SitePermissions: perms.site,
+		OrgPermissions:  perms.org,
+		UserPermissions: perms.user,
+		UpdatedAt:       database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertCustomRole(updated))
+}
+
+func (api *API) deleteCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		role = httpmw.CustomRoleParam(r)
+	)
+	if !api.Authorize(r, rbac.ActionDelete, role) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		// Assignments are validated under the same lock, so the role can't be
+		// assigned while it's removed from the users it's assigned to.
+		err := tx.AcquireLock(ctx, database.LockIDRoleAssignments)
+		if err != nil {
+			return xerrors.Errorf("acquire role assignments lock: %w", err)
+		}
+		err = tx.DeleteCustomRoleByID(ctx, role.ID)
+		if err != nil {
+			return xerrors.Errorf("delete custom role: %w", err)
+		}
+		if role.OrganizationID.Valid {
+			err = tx.RemoveRoleFromOrganizationMembers(ctx, database.RemoveRoleFromOrganizationMembersParams{
+				OrganizationID: role.OrganizationID.UUID,
+				RoleName:       role.RoleName(),
+			})
+		} else {
+			err = tx.RemoveRoleFromUsers(ctx, role.RoleName())
+		}
+		if err != nil {
+			return xerrors.Errorf("remove role from assignments: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Custom role has been deleted!",
+	})
+}
+
+type customRolePermissions struct {
+	site database.CustomRolePermissions
+	org  database.CustomRolePermissions
+	user database.CustomRolePermissions
+}
+
+// customRolePermissions validates the permissions of a custom role. Site roles
+// have site and user permissions, organization roles only have permissions in
+// their organization. Actors can only grant the permissions they have
+// themselves, so custom roles cannot be used to escalate privileges.
+func (api *API) customRolePermissions(rw http.ResponseWriter, r *http.Request, organizationID uuid.NullUUID, site, org, user []codersdk.Permission) (customRolePermissions, bool) {
+	ctx := r.Context()
+	var validations []codersdk.ValidationError
+	if organizationID.Valid {
+		if len(site) > 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "site_permissions",
+				Detail: "organization roles cannot have site permissions",
+			})
+		}
+		if len(user) > 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "user_permissions",
+				Detail: "organization roles cannot have user permissions",
+			})
+		}
+	} else if len(org) > 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "organization_permissions",
+			Detail: "site roles cannot have organization permissions",
+		})
+	}
+
+	var denied []string
+	convert := func(field string, perms []codersdk.Permission) database.CustomRolePermissions {
+		converted := make(database.CustomRolePermissions, 0, len(perms))
+		for _, perm := range perms {
+			action := rbac.Action(perm.Action)
+			switch {
+			case !customRoleResourceType(string(perm.ResourceType)):
+				validations = append(validations, codersdk.ValidationError{
+					Field:  field,
+					Detail: fmt.Sprintf("unknown resource type %q", perm.ResourceType),
+				})
+				continue
+			case !slices.Contains(rbac.AllActions(), action):
+				validations = append(validations, codersdk.ValidationError{
+					Field:  field,
+					Detail: fmt.Sprintf("unknown action %q", perm.Action),
+				})
+				continue
+			}
+
+			object := rbac.Object{Type: string(perm.ResourceType)}
+			if organizationID.Valid {
+				object = object.InOrg(organizationID.UUID)
+			}
+			// Negated permissions only take permissions away.
+			if !perm.Negate && !api.Authorize(r, action, object) {
+				denied = append(denied, fmt.Sprintf("%s %s", perm.Action, perm.ResourceType))
+			}
+			converted = append(converted, rbac.Permission{
+				Negate:       perm.Negate,
+				ResourceType: string(perm.ResourceType),
+				Action:       action,
+			})
+		}
+		return converted
+	}
+	perms := customRolePermissions{
+		site: convert("site_permissions", site),
+		org:  convert("organization_permissions", org),
+		user: convert("user_permissions", user),
+	}
+
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid custom role permissions.",
+			Validations: validations,
+		})
+		return customRolePermissions{}, false
+	}
+	if len(denied) > 0 {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You cannot grant permissions you do not have.",
+			Detail:  fmt.Sprintf("denied: %v", denied),
+		})
+		return customRolePermissions{}, false
+	}
+	return perms, true
+}
+
+// customRoleResourceType returns true if custom roles can have permissions on
+// the resource type. Wildcard and system permissions are reserved for the
+// built-in roles.
+func customRoleResourceType(resourceType string) bool {
+	if resourceType == rbac.ResourceWildcard.Type || resourceType == rbac.ResourceSystem.Type {
+		return false
+	}
+	for _, resource := range rbac.AllResources() {
+		if resource.Type == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+func customRoleObject(organizationID uuid.NullUUID) rbac.Object {
+	if organizationID.Valid {
+		return rbac.ResourceCustomRole.InOrg(organizationID.UUID)
+	}
+	return rbac.ResourceCustomRole
+}
+
+func convertCustomRole(role database.CustomRole) codersdk.CustomRole {
+	converted := codersdk.CustomRole{
+		ID:                      role.ID,
+		Name:                    role.Name,
+		DisplayName:             role.DisplayName,
+		SitePermissions:         convertCustomRolePermissions(role.SitePermissions),
+		OrganizationPermissions: convertCustomRolePermissions(role.OrgPermissions),
+		UserPermissions:         convertCustomRolePermissions(role.UserPermissions),
+		CreatedAt:               role.CreatedAt,
+		UpdatedAt:               role.UpdatedAt,
+	}
+	if role.OrganizationID.Valid {
+		converted.OrganizationID = &role.OrganizationID.UUID
+	}
+	return converted
+}
+
+func convertCustomRolePermissions(perms database.CustomRolePermissions) []codersdk.Permission {
+	converted := make([]codersdk.Permission, 0, len(perms))
+	for _, perm := range perms {
+		converted = append(converted, codersdk.Permission{
+			Negate:       perm.Negate,
+			ResourceType: codersdk.RBACResource(perm.ResourceType),
+			Action:       string(perm.Action),
+		})
+	}
+	return converted
+}
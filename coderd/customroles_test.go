@@ -0,0 +1,296 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.CreateCustomRole(ctx, uuid.Nil, codersdk.CreateCustomRoleRequest{
+			Name: "audit-reader",
+			SitePermissions: []codersdk.Permission{{
+				ResourceType: codersdk.ResourceAuditLog,
+				Action:       "read",
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "audit-reader", role.Name)
+		require.Equal(t, "audit-reader", role.DisplayName, "display name defaults to the name")
+		require.Nil(t, role.OrganizationID)
+		require.Len(t, role.SitePermissions, 1)
+
+		roles, err := client.CustomRoles(ctx, uuid.Nil)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.CustomRole{role}, roles)
+
+		updated, err := client.UpdateCustomRole(ctx, uuid.Nil, role.Name, codersdk.UpdateCustomRoleRequest{
+			DisplayName: "Audit Reader",
+			SitePermissions: []codersdk.Permission{{
+				ResourceType: codersdk.ResourceAuditLog,
+				Action:       "read",
+			}, {
+				ResourceType: codersdk.ResourceUser,
+				Action:       "read",
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Audit Reader", updated.DisplayName)
+		require.Len(t, updated.SitePermissions, 2)
+
+		got, err := client.CustomRole(ctx, uuid.Nil, role.Name)
+		require.NoError(t, err)
+		require.Equal(t, updated, got)
+
+		// Custom roles are assignable.
+		assignable, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, assignable, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: "audit-reader", DisplayName: "Audit Reader"},
+			Assignable: true,
+		})
+
+		err = client.DeleteCustomRole(ctx, uuid.Nil, role.Name)
+		require.NoError(t, err)
+		_, err = client.CustomRole(ctx, uuid.Nil, role.Name)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("SiteRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+		require.Error(t, err)
+
+		role, err := client.CreateCustomRole(ctx, uuid.Nil, codersdk.CreateCustomRoleRequest{
+			Name:        "auditor-lite",
+			DisplayName: "Auditor Lite",
+			SitePermissions: []codersdk.Permission{{
+				ResourceType: codersdk.ResourceAuditLog,
+				Action:       "read",
+			}},
+		})
+		require.NoError(t, err)
+		updated, err := client.UpdateUserRoles(ctx, user.Username, codersdk.UpdateRoles{
+			Roles: []string{role.RoleName()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.Role{{Name: "auditor-lite", DisplayName: "auditor-lite"}}, updated.Roles)
+
+		// The permissions of the role are evaluated like those of built-in
+		// roles.
+		_, err = member.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+		require.NoError(t, err)
+
+		// Deleting the role removes it from the user.
+		err = client.DeleteCustomRole(ctx, uuid.Nil, role.Name)
+		require.NoError(t, err)
+		got, err := client.User(ctx, user.Username)
+		require.NoError(t, err)
+		require.Empty(t, got.Roles)
+		_, err = member.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("OrganizationRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, client)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleOrgAdmin(first.OrganizationID))
+		member, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, first.OrganizationID, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.Workspace(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Organization admins manage the roles of their organization.
+		role, err := orgAdmin.CreateCustomRole(ctx, first.OrganizationID, codersdk.CreateCustomRoleRequest{
+			Name: "workspace-reader",
+			OrganizationPermissions: []codersdk.Permission{{
+				ResourceType: codersdk.ResourceWorkspace,
+				Action:       "read",
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, first.OrganizationID, *role.OrganizationID)
+		require.Equal(t, "workspace-reader:"+first.OrganizationID.String(), role.RoleName())
+
+		// Organization roles are not site roles.
+		_, err = client.CustomRole(ctx, uuid.Nil, role.Name)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		_, err = orgAdmin.UpdateOrganizationMemberRoles(ctx, first.OrganizationID, user.Username, codersdk.UpdateRoles{
+			Roles: []string{role.RoleName()},
+		})
+		require.NoError(t, err)
+		got, err := member.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, got.ID)
+
+		// Organization roles cannot be assigned as site roles.
+		_, err = client.UpdateUserRoles(ctx, user.Username, codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Deleting the role removes it from the organization members.
+		err = orgAdmin.DeleteCustomRole(ctx, first.OrganizationID, role.Name)
+		require.NoError(t, err)
+		roles, err := client.UserRoles(ctx, user.Username)
+		require.NoError(t, err)
+		require.NotContains(t, roles.OrganizationRoles[first.OrganizationID], role.RoleName())
+		_, err = member.Workspace(ctx, workspace.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Escalation", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleOrgAdmin(first.OrganizationID))
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Organization admins cannot exec into workspaces, so they cannot
+		// grant it either.
+		_, err := orgAdmin.CreateCustomRole(ctx, first.OrganizationID, codersdk.CreateCustomRoleRequest{
+			Name: "exec",
+			OrganizationPermissions: []codersdk.Permission{{
+				ResourceType: codersdk.ResourceWorkspaceExecution,
+				Action:       "create",
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = orgAdmin.CreateCustomRole(ctx, uuid.Nil, codersdk.CreateCustomRoleRequest{
+			Name: "site",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, tc := range []struct {
+			name           string
+			organizationID uuid.UUID
+			req            codersdk.CreateCustomRoleRequest
+		}{{
+			name: "BuiltInName",
+			req:  codersdk.CreateCustomRoleRequest{Name: "owner"},
+		}, {
+			name: "SiteRoleOrganizationPermissions",
+			req: codersdk.CreateCustomRoleRequest{
+				Name:                    "site",
+				OrganizationPermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceWorkspace, Action: "read"}},
+			},
+		}, {
+			name:           "OrganizationRoleSitePermissions",
+			organizationID: first.OrganizationID,
+			req: codersdk.CreateCustomRoleRequest{
+				Name:            "org",
+				SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceWorkspace, Action: "read"}},
+			},
+		}, {
+			name: "UnknownResourceType",
+			req: codersdk.CreateCustomRoleRequest{
+				Name:            "unknown",
+				SitePermissions: []codersdk.Permission{{ResourceType: "unknown", Action: "read"}},
+			},
+		}, {
+			name: "SystemResourceType",
+			req: codersdk.CreateCustomRoleRequest{
+				Name:            "system-reader",
+				SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceSystem, Action: "read"}},
+			},
+		}, {
+			name: "UnknownAction",
+			req: codersdk.CreateCustomRoleRequest{
+				Name:            "restarter",
+				SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceWorkspace, Action: "restart"}},
+			},
+		}} {
+			_, err := client.CreateCustomRole(ctx, tc.organizationID, tc.req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, tc.name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), tc.name)
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateCustomRole(ctx, uuid.Nil, codersdk.CreateCustomRoleRequest{Name: "restarter"})
+		require.NoError(t, err)
+		// The same name may be used by a role of an organization.
+		_, err = client.CreateCustomRole(ctx, first.OrganizationID, codersdk.CreateCustomRoleRequest{Name: "restarter"})
+		require.NoError(t, err)
+		_, err = client.CreateCustomRole(ctx, uuid.Nil, codersdk.CreateCustomRoleRequest{Name: "restarter"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CustomRoles(ctx, uuid.Nil)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.CustomRoles(ctx, first.OrganizationID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
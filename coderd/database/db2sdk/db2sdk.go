@@ -3,6 +3,7 @@ package db2sdk
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -118,8 +119,7 @@ func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, Role(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, RoleFromName(roleName))
 	}
 
 	return convertedUser
@@ -131,3 +131,17 @@ func Role(role rbac.Role) codersdk.Role {
 		Name:        role.Name,
 	}
 }
+
+// RoleFromName converts the role with the name. Custom roles are defined in
+// the database, so they are displayed by their name without the organization.
+func RoleFromName(name string) codersdk.Role {
+	rbacRole, err := rbac.RoleByName(name)
+	if err != nil && !rbac.IsBuiltInRoleName(name) {
+		displayName, _, _ := strings.Cut(name, ":")
+		return codersdk.Role{
+			Name:        name,
+			DisplayName: displayName,
+		}
+	}
+	return Role(rbacRole)
+}
@@ -202,19 +202,20 @@ var (
 				Name:        "system",
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]rbac.Action{
-					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
-					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate},
-					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
-					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
-					rbac.ResourceOrganizationMember.Type: {rbac.ActionCreate},
-					rbac.ResourceOrgRoleAssignment.Type:  {rbac.ActionCreate},
-					rbac.ResourceUser.Type:               {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceUserData.Type:           {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceWorkspace.Type:          {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceExecution.Type: {rbac.ActionCreate},
-					rbac.ResourceWorkspaceProxy.Type:     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWildcard.Type:                {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:                  {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceGroup.Type:                   {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceOAuth2ProviderAppSecret.Type: {rbac.ActionUpdate},
+					rbac.ResourceRoleAssignment.Type:          {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceSystem.Type:                  {rbac.WildcardSymbol},
+					rbac.ResourceOrganization.Type:            {rbac.ActionCreate},
+					rbac.ResourceOrganizationMember.Type:      {rbac.ActionCreate},
+					rbac.ResourceOrgRoleAssignment.Type:       {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceUser.Type:                    {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceUserData.Type:                {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceWorkspace.Type:               {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceExecution.Type:      {rbac.ActionCreate},
+					rbac.ResourceWorkspaceProxy.Type:          {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -521,6 +522,21 @@ func (q *querier) authorizeUpdateFileTemplate(ctx context.Context, file database
 	}
 }
 
+// authorizeSessionRecordingUpdate checks that the actor may update the
+// workspace a session recording belongs to, agents upload recordings of
+// their own workspace.
+func (q *querier) authorizeSessionRecordingUpdate(ctx context.Context, recordingID uuid.UUID) error {
+	recording, err := q.db.GetSessionRecordingByID(ctx, recordingID)
+	if err != nil {
+		return err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, recording.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+}
+
 func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, removed []string) error {
 	actor, ok := ActorFromContext(ctx)
 	if !ok {
@@ -535,6 +551,7 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	}
 
 	grantedRoles := append(added, removed...)
+	customRoles := make([]string, 0)
 	// Validate that the roles being assigned are valid.
 	for _, r := range grantedRoles {
 		_, isOrgRole := rbac.IsOrgRole(r)
@@ -547,7 +564,27 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 
 		// All roles should be valid roles
 		if _, err := rbac.RoleByName(r); err != nil {
-			return xerrors.Errorf("%q is not a supported role", r)
+			if rbac.IsBuiltInRoleName(r) {
+				return xerrors.Errorf("%q is not a supported role", r)
+			}
+			customRoles = append(customRoles, r)
+		}
+	}
+
+	// Roles that aren't built-in must be custom roles.
+	if len(customRoles) > 0 {
+		found, err := q.db.GetCustomRolesByNames(ctx, customRoles)
+		if err != nil {
+			return xerrors.Errorf("get custom roles: %w", err)
+		}
+		exists := make(map[string]bool, len(found))
+		for _, role := range found {
+			exists[role.RoleName()] = true
+		}
+		for _, r := range customRoles {
+			if !exists[r] {
+				return xerrors.Errorf("%q is not a supported role", r)
+			}
 		}
 	}
 
@@ -700,6 +737,13 @@ func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
 
+func (q *querier) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireNotificationMessages(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -708,6 +752,13 @@ func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.Acquir
 	return q.db.AcquireProvisionerJob(ctx, arg)
 }
 
+func (q *querier) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -715,8 +766,8 @@ func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	return q.db.CleanTailnetCoordinators(ctx)
 }
 
-func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) error {
-	return deleteQ(q.log, q.auth, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByID)(ctx, id)
+func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByID)(ctx, id)
 }
 
 func (q *querier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
@@ -746,6 +797,10 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteCustomRoleByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetCustomRoleByID, q.db.DeleteCustomRoleByID)(ctx, id)
+}
+
 func (q *querier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetGitSSHKey, q.db.DeleteGitSSHKey)(ctx, userID)
 }
@@ -783,6 +838,53 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppByID, q.db.DeleteOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetOAuth2ProviderAppCodeByID, q.db.DeleteOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceAPIKey.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID, q.db.DeleteOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceAPIKey.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+}
+
+func (q *querier) DeleteOldNotificationMessages(ctx context.Context, before time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNotificationMessages(ctx, before)
+}
+
+func (q *querier) DeleteOldSessionRecordings(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldSessionRecordings(ctx)
+}
+
+func (q *querier) DeleteOldWebhookDeliveries(ctx context.Context, before time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWebhookDeliveries(ctx, before)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -818,6 +920,38 @@ func (q *querier) DeleteTailnetClient(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetClient(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateVersionWithoutTemplate(ctx context.Context, id uuid.UUID) error {
+	tv, err := q.db.GetTemplateVersionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// The query never deletes versions of templates, so only versions
+	// without one need to be authorized. Deleting them is the same
+	// permission as creating them.
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTemplate.InOrg(tv.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateVersionWithoutTemplate(ctx, id)
+}
+
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetWebhookByID, q.db.DeleteWebhookByID)(ctx, id)
+}
+
+func (q *querier) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.EnqueueNotificationMessage(ctx, arg)
+}
+
+func (q *querier) EnqueueWebhookDeliveries(ctx context.Context, arg database.EnqueueWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.EnqueueWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -845,6 +979,10 @@ func (q *querier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	return q.db.GetActiveUserCount(ctx)
 }
 
+func (q *querier) GetActiveUsersByRoleForUpdate(ctx context.Context, roleName string) ([]database.User, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetActiveUsersByRoleForUpdate)(ctx, roleName)
+}
+
 func (q *querier) GetAppSecurityKey(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetAppSecurityKey(ctx)
@@ -867,6 +1005,23 @@ func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUI
 	return q.db.GetAuthorizationUserRoles(ctx, userID)
 }
 
+func (q *querier) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (database.CustomRole, error) {
+	return fetch(q.log, q.auth, q.db.GetCustomRoleByID)(ctx, id)
+}
+
+func (q *querier) GetCustomRoleByName(ctx context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	return fetch(q.log, q.auth, q.db.GetCustomRoleByName)(ctx, arg)
+}
+
+func (q *querier) GetCustomRoles(ctx context.Context, organizationID uuid.UUID) ([]database.CustomRole, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetCustomRoles)(ctx, organizationID)
+}
+
+func (q *querier) GetCustomRolesByNames(ctx context.Context, names []string) ([]database.CustomRole, error) {
+	// Roles the actor cannot read are omitted, as if they did not exist.
+	return fetchWithPostFilter(q.auth, q.db.GetCustomRolesByNames)(ctx, names)
+}
+
 func (q *querier) GetDERPMeshKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1038,6 +1193,67 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationMessagesByUserID(ctx, userID)
+}
+
+func (q *querier) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationPreferencesByUserID(ctx, userID)
+}
+
+func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByHashedSecret)(ctx, hashedSecret)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByHashedSecret)(ctx, hashedSecret)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetOAuth2ProviderAppSecretsByAppID)(ctx, appID)
+}
+
+func (q *querier) GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx context.Context, hashedRefreshToken []byte) (database.OAuth2ProviderAppToken, error) {
+	// Refresh tokens are only looked up by the token endpoint, which
+	// authenticates the app instead of a user.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx, hashedRefreshToken)
+}
+
+func (q *querier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderApps(ctx)
+	})(ctx, nil)
+}
+
+func (q *querier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	// Listing the apps a user authorized reveals which API keys they have.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAPIKey.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return fetchWithPostFilter(q.auth, q.db.GetOAuth2ProviderAppsByUserID)(ctx, userID)
+}
+
 func (q *querier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationByID)(ctx, id)
 }
@@ -1198,6 +1414,41 @@ func (q *querier) GetServiceBanner(ctx context.Context) (string, error) {
 	return q.db.GetServiceBanner(ctx)
 }
 
+func (q *querier) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	recording, err := q.db.GetSessionRecordingByID(ctx, id)
+	if err != nil {
+		return database.SessionRecording{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, recording.WorkspaceID)
+	if err != nil {
+		return database.SessionRecording{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return database.SessionRecording{}, err
+	}
+	return recording, nil
+}
+
+func (q *querier) GetSessionRecordingChunksByRecordingID(ctx context.Context, recordingID uuid.UUID) ([]database.SessionRecordingChunk, error) {
+	// Authorized by fetching the recording.
+	_, err := q.GetSessionRecordingByID(ctx, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetSessionRecordingChunksByRecordingID(ctx, recordingID)
+}
+
+func (q *querier) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetSessionRecordingsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return nil, err
@@ -1433,6 +1684,24 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	return fetch(q.log, q.auth, q.db.GetWebhookByID)(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	// Deliveries can be read by anyone who can read the webhook.
+	if _, err := q.GetWebhookByID(ctx, arg.WebhookID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.Webhook, error) {
+		return q.db.GetWebhooks(ctx)
+	})(ctx, nil)
+}
+
 // GetWorkspaceAgentByAuthToken is used in http middleware to get the workspace agent.
 // This should only be used by a system user in that middleware.
 func (q *querier) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken uuid.UUID) (database.WorkspaceAgent, error) {
@@ -1473,6 +1742,13 @@ func (q *querier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uu
 	return q.db.GetWorkspaceAgentLifecycleStateByID(ctx, id)
 }
 
+func (q *querier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentMetadatum, error) {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, workspaceAgentID)
 	if err != nil {
@@ -1487,6 +1763,27 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentI
 	return q.db.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
 }
 
+func (q *querier) GetWorkspaceAgentProcesses(ctx context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentProcess, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, workspaceAgentID)
+	if err != nil {
+		return database.WorkspaceAgentProcess{}, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace)
+	if err != nil {
+		return database.WorkspaceAgentProcess{}, err
+	}
+
+	return q.db.GetWorkspaceAgentProcesses(ctx, workspaceAgentID)
+}
+
+func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, arg.AgentID)
 	if err != nil {
@@ -1761,6 +2058,13 @@ func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now ti
 	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
 
+func (q *querier) GetWorkspacesMarkedForDeletion(ctx context.Context) ([]database.Workspace, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacesMarkedForDeletion(ctx)
+}
+
 func (q *querier) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	return insert(q.log, q.auth,
 		rbac.ResourceAPIKey.WithOwner(arg.UserID.String()),
@@ -1776,6 +2080,14 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	obj := rbac.ResourceCustomRole
+	if arg.OrganizationID.Valid {
+		obj = obj.InOrg(arg.OrganizationID.UUID)
+	}
+	return insert(q.log, q.auth, obj, q.db.InsertCustomRole)(ctx, arg)
+}
+
 func (q *querier) InsertDERPMeshKey(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -1820,6 +2132,25 @@ func (q *querier) InsertLicense(ctx context.Context, arg database.InsertLicenseP
 	return q.db.InsertLicense(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderApp, q.db.InsertOAuth2ProviderApp)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	return insert(q.log, q.auth, rbac.ResourceAPIKey.WithOwner(arg.UserID.String()), q.db.InsertOAuth2ProviderAppCode)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppSecret, q.db.InsertOAuth2ProviderAppSecret)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppToken(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -1867,6 +2198,24 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertSessionRecording(ctx context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.SessionRecording{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.SessionRecording{}, err
+	}
+	return q.db.InsertSessionRecording(ctx, arg)
+}
+
+func (q *querier) InsertSessionRecordingChunk(ctx context.Context, arg database.InsertSessionRecordingChunkParams) error {
+	if err := q.authorizeSessionRecordingUpdate(ctx, arg.RecordingID); err != nil {
+		return err
+	}
+	return q.db.InsertSessionRecordingChunk(ctx, arg)
+}
+
 func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) (database.Template, error) {
 	obj := rbac.ResourceTemplate.InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertTemplate)(ctx, arg)
@@ -1938,6 +2287,10 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	return insert(q.log, q.auth, rbac.ResourceWebhook, q.db.InsertWebhook)(ctx, arg)
+}
+
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
@@ -1952,6 +2305,13 @@ func (q *querier) InsertWorkspaceAgent(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceAgent(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertWorkspaceAgentLogSources(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	// We don't check for workspace ownership here since the agent metadata may
 	// be associated with an orphaned agent used by a dry run build.
@@ -1962,6 +2322,13 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertWorkspaceAgentScripts(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	return q.db.InsertWorkspaceAgentStartupLogs(ctx, arg)
 }
@@ -2043,6 +2410,20 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) MarkNotificationMessageFailed(ctx context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkNotificationMessageFailed(ctx, arg)
+}
+
+func (q *querier) MarkNotificationMessageSent(ctx context.Context, arg database.MarkNotificationMessageSentParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkNotificationMessageSent(ctx, arg)
+}
+
 func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2050,6 +2431,21 @@ func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.Regis
 	return updateWithReturn(q.log, q.auth, fetch, q.db.RegisterWorkspaceProxy)(ctx, arg)
 }
 
+func (q *querier) RemoveRoleFromOrganizationMembers(ctx context.Context, arg database.RemoveRoleFromOrganizationMembersParams) error {
+	// Removing a role from all members counts as deleting role assignments.
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceRoleAssignment.InOrg(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.RemoveRoleFromOrganizationMembers(ctx, arg)
+}
+
+func (q *querier) RemoveRoleFromUsers(ctx context.Context, roleName string) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceRoleAssignment); err != nil {
+		return err
+	}
+	return q.db.RemoveRoleFromUsers(ctx, roleName)
+}
+
 func (q *querier) TryAcquireLock(ctx context.Context, id int64) (bool, error) {
 	return q.db.TryAcquireLock(ctx, id)
 }
@@ -2061,6 +2457,13 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateCustomRoleByID(ctx context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	fetch := func(ctx context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+		return q.db.GetCustomRoleByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateCustomRoleByID)(ctx, arg)
+}
+
 func (q *querier) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 		return q.db.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -2103,6 +2506,20 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderAppByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppByID)(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppSecretLastUsedAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams) (database.OAuth2ProviderAppSecret, error) {
+		return q.db.GetOAuth2ProviderAppSecretByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppSecretLastUsedAtByID)(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -2193,6 +2610,13 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateSessionRecordingByID(ctx context.Context, arg database.UpdateSessionRecordingByIDParams) error {
+	if err := q.authorizeSessionRecordingUpdate(ctx, arg.ID); err != nil {
+		return err
+	}
+	return q.db.UpdateSessionRecordingByID(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 	// UpdateTemplateACL uses the ActionCreate action. Only users that can create the template
 	// may update the ACL.
@@ -2389,6 +2813,20 @@ func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserS
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserStatus)(ctx, arg)
 }
 
+func (q *querier) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+		return q.db.GetWebhookByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWebhookByID)(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookDeliveryByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2430,6 +2868,20 @@ func (q *querier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentScriptStatus(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusParams) (database.WorkspaceAgentScript, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	return q.db.UpdateWorkspaceAgentScriptStatus(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	agent, err := q.db.GetWorkspaceAgentByID(ctx, arg.ID)
 	if err != nil {
@@ -2584,6 +3036,13 @@ func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	return q.db.UpsertLogoURL(ctx, value)
 }
 
+func (q *querier) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.UpsertNotificationPreference(ctx, arg)
+}
+
 func (q *querier) UpsertServiceBanner(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceDeploymentValues); err != nil {
 		return err
@@ -2611,3 +3070,17 @@ func (q *querier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (d
 	}
 	return q.db.UpsertTailnetCoordinator(ctx, id)
 }
+
+func (q *querier) UpsertWorkspaceAgentProcesses(ctx context.Context, arg database.UpsertWorkspaceAgentProcessesParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentProcesses(ctx, arg)
+}
@@ -163,7 +163,7 @@ func must[T any](value T, err error) T {
 func (s *MethodTestSuite) TestAPIKey() {
 	s.Run("DeleteAPIKeyByID", s.Subtest(func(db database.Store, check *expects) {
 		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
-		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns()
+		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns(key)
 	}))
 	s.Run("GetAPIKeyByID", s.Subtest(func(db database.Store, check *expects) {
 		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
@@ -428,6 +428,16 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 		check.Args(database.UpdateProvisionerJobWithCancelByIDParams{ID: j.ID}).
 			Asserts(v.RBACObject(tpl), []rbac.Action{rbac.ActionRead, rbac.ActionUpdate}).Returns()
 	}))
+	s.Run("GetWorkspaceAgentLogSourcesByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead).
+			Returns([]database.WorkspaceAgentLogSource{})
+	}))
+	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead).
+			Returns([]database.WorkspaceAgentScript{})
+	}))
 	s.Run("GetProvisionerJobsByIDs", s.Subtest(func(db database.Store, check *expects) {
 		a := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
 		b := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
@@ -499,6 +509,254 @@ func (s *MethodTestSuite) TestLicense() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWebhook() {
+	s.Run("GetWebhooks", s.Subtest(func(db database.Store, check *expects) {
+		a := dbgen.Webhook(s.T(), db, database.Webhook{Name: "a"})
+		b := dbgen.Webhook(s.T(), db, database.Webhook{Name: "b"})
+		check.Args().Asserts(a, rbac.ActionRead, b, rbac.ActionRead).
+			Returns([]database.Webhook{a, b})
+	}))
+	s.Run("GetWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionRead).Returns(w)
+	}))
+	s.Run("InsertWebhook", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookParams{
+			ID:     uuid.New(),
+			Events: []database.WebhookEvent{database.WebhookEventUserCreated},
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("UpdateWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.UpdateWebhookByIDParams{
+			ID:     w.ID,
+			Name:   w.Name,
+			Events: []database.WebhookEvent{database.WebhookEventUserCreated},
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: w.ID,
+		}).Asserts(w, rbac.ActionRead).Returns([]database.WebhookDelivery{})
+	}))
+}
+
+func (s *MethodTestSuite) TestCustomRole() {
+	s.Run("GetCustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		a := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "a", OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true}})
+		b := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "b", OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true}})
+		_ = dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "site"})
+		check.Args(o.ID).Asserts(a, rbac.ActionRead, b, rbac.ActionRead).
+			Returns([]database.CustomRole{a, b})
+	}))
+	s.Run("GetCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(r.ID).Asserts(r, rbac.ActionRead).Returns(r)
+	}))
+	s.Run("GetCustomRoleByName", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true}})
+		check.Args(database.GetCustomRoleByNameParams{
+			Name:           r.Name,
+			OrganizationID: o.ID,
+		}).Asserts(r, rbac.ActionRead).Returns(r)
+	}))
+	s.Run("GetCustomRolesByNames", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		site := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		org := dbgen.CustomRole(s.T(), db, database.CustomRole{OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true}})
+		check.Args([]string{site.RoleName(), org.RoleName()}).
+			Asserts(site, rbac.ActionRead, org, rbac.ActionRead).
+			Returns([]database.CustomRole{site, org})
+	}))
+	s.Run("InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.InsertCustomRoleParams{
+			ID:             uuid.New(),
+			Name:           "restarter",
+			OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true},
+		}).Asserts(rbac.ResourceCustomRole.InOrg(o.ID), rbac.ActionCreate)
+	}))
+	s.Run("UpdateCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(database.UpdateCustomRoleByIDParams{
+			ID:          r.ID,
+			DisplayName: "Restarter",
+		}).Asserts(r, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(r.ID).Asserts(r, rbac.ActionDelete).Returns()
+	}))
+	s.Run("RemoveRoleFromUsers", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("restarter").Asserts(rbac.ResourceRoleAssignment, rbac.ActionDelete).Returns()
+	}))
+	s.Run("RemoveRoleFromOrganizationMembers", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.RemoveRoleFromOrganizationMembersParams{
+			OrganizationID: o.ID,
+			RoleName:       "restarter:" + o.ID.String(),
+		}).Asserts(rbac.ResourceRoleAssignment.InOrg(o.ID), rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderApps() {
+	s.Run("GetOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
+		a := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "a"})
+		b := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "b"})
+		check.Args().Asserts(a, rbac.ActionRead, b, rbac.ActionRead).
+			Returns([]database.OAuth2ProviderApp{a, b})
+	}))
+	s.Run("GetOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionRead).Returns(app)
+	}))
+	s.Run("GetOAuth2ProviderAppsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(user.ID).
+			Asserts(rbac.ResourceAPIKey.WithOwner(user.ID.String()), rbac.ActionRead, app, rbac.ActionRead).
+			Returns([]database.OAuth2ProviderApp{app})
+	}))
+	s.Run("InsertOAuth2ProviderApp", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertOAuth2ProviderAppParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		app.Name = "updated"
+		check.Args(database.UpdateOAuth2ProviderAppByIDParams{
+			ID:          app.ID,
+			UpdatedAt:   app.UpdatedAt,
+			Name:        app.Name,
+			Icon:        app.Icon,
+			CallbackURL: app.CallbackURL,
+		}).Asserts(app, rbac.ActionUpdate).Returns(app)
+	}))
+	s.Run("DeleteOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppSecrets() {
+	s.Run("GetOAuth2ProviderAppSecretsByAppID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(app.ID).Asserts(secret, rbac.ActionRead).
+			Returns([]database.OAuth2ProviderAppSecret{secret})
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.HashedSecret).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("InsertOAuth2ProviderAppSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppSecretParams{
+			ID:    uuid.New(),
+			AppID: app.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppSecretLastUsedAtByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(database.UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams{
+			ID:         secret.ID,
+			LastUsedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		}).Asserts(secret, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppCodes() {
+	s.Run("GetOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.HashedSecret).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("InsertOAuth2ProviderAppCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppCodeParams{
+			ID:     uuid.New(),
+			AppID:  app.ID,
+			UserID: user.ID,
+			Scope:  database.APIKeyScopeAll,
+		}).Asserts(rbac.ResourceAPIKey.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionDelete).Returns(code)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodesByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceAPIKey.WithOwner(user.ID.String()), rbac.ActionDelete).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
+	s.Run("GetOAuth2ProviderAppTokenByHashedRefreshToken", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(token.HashedRefreshToken).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(token)
+	}))
+	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppTokenParams{
+			ID:       uuid.New(),
+			AppID:    app.ID,
+			APIKeyID: key.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokensByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{AppID: app.ID, APIKeyID: key.ID})
+		check.Args(database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceAPIKey.WithOwner(user.ID.String()), rbac.ActionDelete).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestOrganization() {
 	s.Run("GetGroupsByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -764,6 +1022,13 @@ func (s *MethodTestSuite) TestTemplate() {
 			OrganizationID: t1.OrganizationID,
 		}).Asserts(t1, rbac.ActionRead, t1, rbac.ActionCreate)
 	}))
+	s.Run("DeleteTemplateVersionWithoutTemplate", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			OrganizationID: o.ID,
+		})
+		check.Args(tv.ID).Asserts(rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionDelete)
+	}))
 	s.Run("SoftDeleteTemplateByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, rbac.ActionDelete)
@@ -869,6 +1134,13 @@ func (s *MethodTestSuite) TestUser() {
 			Asserts(a, rbac.ActionRead, b, rbac.ActionRead).
 			Returns(slice.New(a, b))
 	}))
+	s.Run("GetActiveUsersByRoleForUpdate", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{RBACRoles: []string{rbac.RoleOwner()}})
+		_ = dbgen.User(s.T(), db, database.User{})
+		check.Args(rbac.RoleOwner()).
+			Asserts(u, rbac.ActionRead).
+			Returns([]database.User{u})
+	}))
 	s.Run("GetAuthorizedUserCount", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.User(s.T(), db, database.User{})
 		check.Args(database.GetFilteredUserCountParams{}, emptyPreparedAuthorized{}).Asserts().Returns(int64(1))
@@ -1012,6 +1284,22 @@ func (s *MethodTestSuite) TestUser() {
 			rbac.ResourceRoleAssignment, rbac.ActionDelete,
 		).Returns(o)
 	}))
+	s.Run("GetNotificationPreferencesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.NotificationPreference{})
+	}))
+	s.Run("UpsertNotificationPreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertNotificationPreferenceParams{
+			UserID:   u.ID,
+			Template: "workspace_autostopped",
+			Disabled: true,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetNotificationMessagesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.NotificationMessage{})
+	}))
 }
 
 func (s *MethodTestSuite) TestWorkspace() {
@@ -1085,6 +1373,95 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Subsystem: database.WorkspaceAgentSubsystemNone,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAgentScriptStatus", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		scripts, err := db.InsertWorkspaceAgentScripts(context.Background(), database.InsertWorkspaceAgentScriptsParams{
+			WorkspaceAgentID: agt.ID,
+			CreatedAt:        database.Now(),
+			LogSourceID:      []uuid.UUID{uuid.New()},
+			LogPath:          []string{""},
+			Script:           []string{"echo hello"},
+			Cron:             []string{""},
+			StartBlocksLogin: []bool{false},
+			RunOnStart:       []bool{true},
+			RunOnStop:        []bool{false},
+			TimeoutSeconds:   []int32{0},
+		})
+		require.NoError(s.T(), err)
+		script := scripts[0]
+		script.Status = database.WorkspaceAgentScriptStatusRunning
+		check.Args(database.UpdateWorkspaceAgentScriptStatusParams{
+			WorkspaceAgentID: agt.ID,
+			LogSourceID:      script.LogSourceID,
+			Status:           database.WorkspaceAgentScriptStatusRunning,
+		}).Asserts(ws, rbac.ActionUpdate).Returns(script)
+	}))
+	s.Run("UpsertWorkspaceAgentProcesses", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpsertWorkspaceAgentProcessesParams{
+			WorkspaceAgentID: agt.ID,
+			Processes:        []byte("{}"),
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceAgentProcesses", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		err := db.UpsertWorkspaceAgentProcesses(context.Background(), database.UpsertWorkspaceAgentProcessesParams{
+			WorkspaceAgentID: agt.ID,
+			Processes:        []byte("{}"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(agt.ID).Asserts(ws, rbac.ActionRead).Returns(database.WorkspaceAgentProcess{
+			WorkspaceAgentID: agt.ID,
+			Processes:        []byte("{}"),
+		})
+	}))
+	s.Run("InsertSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertSessionRecordingParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			Type:        database.SessionRecordingTypeSSH,
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("InsertSessionRecordingChunk", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.SessionRecording(s.T(), db, database.SessionRecording{WorkspaceID: ws.ID})
+		check.Args(database.InsertSessionRecordingChunkParams{
+			RecordingID: rec.ID,
+			Data:        []byte("{}"),
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.SessionRecording(s.T(), db, database.SessionRecording{WorkspaceID: ws.ID})
+		check.Args(database.UpdateSessionRecordingByIDParams{
+			ID: rec.ID,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.SessionRecording(s.T(), db, database.SessionRecording{WorkspaceID: ws.ID})
+		check.Args(rec.ID).Asserts(ws, rbac.ActionRead).Returns(rec)
+	}))
+	s.Run("GetSessionRecordingsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.SessionRecording(s.T(), db, database.SessionRecording{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns([]database.SessionRecording{rec})
+	}))
+	s.Run("GetSessionRecordingChunksByRecordingID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		rec := dbgen.SessionRecording(s.T(), db, database.SessionRecording{WorkspaceID: ws.ID})
+		check.Args(rec.ID).Asserts(ws, rbac.ActionRead).Returns([]database.SessionRecordingChunk{})
+	}))
 	s.Run("GetWorkspaceAgentStartupLogsAfter", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1422,6 +1799,71 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("DeleteOldSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("EnqueueNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.EnqueueNotificationMessageParams{
+			ID:         uuid.New(),
+			UserID:     u.ID,
+			Method:     database.NotificationMethodEmail,
+			DedupeHash: "hash",
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate).Returns()
+	}))
+	s.Run("EnqueueWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.EnqueueWebhookDeliveriesParams{
+			Event:   database.WebhookEventUserCreated,
+			Payload: json.RawMessage("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireWebhookDeliveriesParams{
+			Now:   database.Now(),
+			Count: 10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{
+			Events: []database.WebhookEvent{database.WebhookEventUserCreated},
+		})
+		deliveries, err := db.EnqueueWebhookDeliveries(context.Background(), database.EnqueueWebhookDeliveriesParams{
+			Event:   database.WebhookEventUserCreated,
+			Payload: json.RawMessage("{}"),
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), deliveries, 1)
+		require.Equal(s.T(), w.ID, deliveries[0].WebhookID)
+		check.Args(database.UpdateWebhookDeliveryByIDParams{
+			ID:     deliveries[0].ID,
+			Status: database.WebhookDeliveryStatusSucceeded,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteOldWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete).Returns()
+	}))
+	s.Run("AcquireNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireNotificationMessagesParams{
+			Now:   database.Now(),
+			Count: 10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("MarkNotificationMessageSent", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessageSentParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("MarkNotificationMessageFailed", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessageFailedParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteOldNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetWorkspacesMarkedForDeletion", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
 		_ = dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
@@ -1504,6 +1946,16 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			StartupScriptBehavior: database.StartupScriptBehaviorNonBlocking,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentLogSources", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentLogSourcesParams{
+			WorkspaceAgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertWorkspaceAgentScripts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentScriptsParams{
+			WorkspaceAgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceApp", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAppParams{
 			ID:           uuid.New(),
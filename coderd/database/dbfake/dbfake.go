@@ -1,6 +1,7 @@
 package dbfake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -114,24 +115,38 @@ type data struct {
 	// New tables
 	workspaceAgentStats       []database.WorkspaceAgentStat
 	auditLogs                 []database.AuditLog
+	customRoles               []database.CustomRole
 	files                     []database.File
 	gitAuthLinks              []database.GitAuthLink
 	gitSSHKey                 []database.GitSSHKey
 	groupMembers              []database.GroupMember
 	groups                    []database.Group
 	licenses                  []database.License
+	notificationMessages      []database.NotificationMessage
+	notificationPreferences   []database.NotificationPreference
+	oauth2ProviderApps        []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets  []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes    []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens   []database.OAuth2ProviderAppToken
 	parameterSchemas          []database.ParameterSchema
 	provisionerDaemons        []database.ProvisionerDaemon
 	provisionerJobLogs        []database.ProvisionerJobLog
 	provisionerJobs           []database.ProvisionerJob
 	replicas                  []database.Replica
+	sessionRecordings         []database.SessionRecording
+	sessionRecordingChunks    []database.SessionRecordingChunk
 	templateVersions          []database.TemplateVersion
 	templateVersionParameters []database.TemplateVersionParameter
 	templateVersionVariables  []database.TemplateVersionVariable
 	templates                 []database.Template
+	webhooks                  []database.Webhook
+	webhookDeliveries         []database.WebhookDelivery
 	workspaceAgents           []database.WorkspaceAgent
 	workspaceAgentMetadata    []database.WorkspaceAgentMetadatum
+	workspaceAgentProcesses   []database.WorkspaceAgentProcess
 	workspaceAgentLogs        []database.WorkspaceAgentStartupLog
+	workspaceAgentLogSources  []database.WorkspaceAgentLogSource
+	workspaceAgentScripts     []database.WorkspaceAgentScript
 	workspaceApps             []database.WorkspaceApp
 	workspaceBuilds           []database.WorkspaceBuild
 	workspaceBuildParameters  []database.WorkspaceBuildParameter
@@ -968,6 +983,17 @@ func isNotNull(v interface{}) bool {
 	return reflect.ValueOf(v).FieldByName("Valid").Bool()
 }
 
+// removeRole is like array_remove in postgres.
+func removeRole(roles []string, name string) []string {
+	removed := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role != name {
+			removed = append(removed, role)
+		}
+	}
+	return removed
+}
+
 // ErrUnimplemented is returned by methods only used by the enterprise/tailnet.pgCoord.  This coordinator explicitly
 // depends on  postgres triggers that announce changes on the pubsub.  Implementing support for this in the fake
 // database would  strongly couple the fakeQuerier to the pubsub, which is undesirable.  Furthermore, it makes little
@@ -980,6 +1006,44 @@ func (*fakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+func (q *fakeQuerier) AcquireNotificationMessages(_ context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var indexes []int
+	for i, message := range q.notificationMessages {
+		switch {
+		case message.Status == database.NotificationMessageStatusPending && !message.SendAfter.After(arg.Now):
+		case message.Status == database.NotificationMessageStatusLeased && message.LeasedUntil.Valid && message.LeasedUntil.Time.Before(arg.Now):
+		default:
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return q.notificationMessages[indexes[i]].CreatedAt.Before(q.notificationMessages[indexes[j]].CreatedAt)
+	})
+	if len(indexes) > int(arg.Count) {
+		indexes = indexes[:arg.Count]
+	}
+
+	acquired := make([]database.NotificationMessage, 0, len(indexes))
+	for _, i := range indexes {
+		message := q.notificationMessages[i]
+		message.Status = database.NotificationMessageStatusLeased
+		message.Attempts++
+		message.UpdatedAt = arg.Now
+		message.LeasedUntil = sql.NullTime{Time: arg.LeasedUntil, Valid: true}
+		q.notificationMessages[i] = message
+		acquired = append(acquired, message)
+	}
+	return acquired, nil
+}
+
 func (q *fakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -1035,11 +1099,49 @@ func (q *fakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) AcquireWebhookDeliveries(_ context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var indexes []int
+	for i, delivery := range q.webhookDeliveries {
+		switch {
+		case delivery.Status == database.WebhookDeliveryStatusPending && !delivery.SendAfter.After(arg.Now):
+		case delivery.Status == database.WebhookDeliveryStatusLeased && delivery.LeasedUntil.Valid && delivery.LeasedUntil.Time.Before(arg.Now):
+		default:
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return q.webhookDeliveries[indexes[i]].CreatedAt.Before(q.webhookDeliveries[indexes[j]].CreatedAt)
+	})
+	if len(indexes) > int(arg.Count) {
+		indexes = indexes[:arg.Count]
+	}
+
+	acquired := make([]database.WebhookDelivery, 0, len(indexes))
+	for _, i := range indexes {
+		delivery := q.webhookDeliveries[i]
+		delivery.Status = database.WebhookDeliveryStatusLeased
+		delivery.Attempts++
+		delivery.UpdatedAt = arg.Now
+		delivery.LeasedUntil = sql.NullTime{Time: arg.LeasedUntil, Valid: true}
+		q.webhookDeliveries[i] = delivery
+		acquired = append(acquired, delivery)
+	}
+	return acquired, nil
+}
+
 func (*fakeQuerier) CleanTailnetCoordinators(_ context.Context) error {
 	return ErrUnimplemented
 }
 
-func (q *fakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
+func (q *fakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -1049,9 +1151,9 @@ func (q *fakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 		}
 		q.apiKeys[index] = q.apiKeys[len(q.apiKeys)-1]
 		q.apiKeys = q.apiKeys[:len(q.apiKeys)-1]
-		return nil
+		return apiKey, nil
 	}
-	return sql.ErrNoRows
+	return database.APIKey{}, sql.ErrNoRows
 }
 
 func (q *fakeQuerier) DeleteAPIKeysByUserID(_ context.Context, userID uuid.UUID) error {
@@ -1084,6 +1186,20 @@ func (*fakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *fakeQuerier) DeleteCustomRoleByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, role := range q.customRoles {
+		if role.ID != id {
+			continue
+		}
+		q.customRoles = append(q.customRoles[:index], q.customRoles[index+1:]...)
+		return nil
+	}
+	return nil
+}
+
 func (q *fakeQuerier) DeleteGitSSHKey(_ context.Context, userID uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1169,6 +1285,194 @@ func (q *fakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := slices.IndexFunc(q.oauth2ProviderApps, func(app database.OAuth2ProviderApp) bool {
+		return app.ID == id
+	})
+	if index < 0 {
+		return nil
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps[:index], q.oauth2ProviderApps[index+1:]...)
+
+	secrets := q.oauth2ProviderAppSecrets[:0]
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID != id {
+			secrets = append(secrets, secret)
+		}
+	}
+	q.oauth2ProviderAppSecrets = secrets
+
+	codes := q.oauth2ProviderAppCodes[:0]
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.AppID != id {
+			codes = append(codes, code)
+		}
+	}
+	q.oauth2ProviderAppCodes = codes
+
+	tokens := q.oauth2ProviderAppTokens[:0]
+	keyIDs := make(map[string]bool)
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.AppID != id {
+			tokens = append(tokens, token)
+			continue
+		}
+		keyIDs[token.APIKeyID] = true
+	}
+	q.oauth2ProviderAppTokens = tokens
+
+	apiKeys := q.apiKeys[:0]
+	for _, apiKey := range q.apiKeys {
+		if !keyIDs[apiKey.ID] {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	q.apiKeys = apiKeys
+	return nil
+}
+
+func (q *fakeQuerier) DeleteOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes[:index], q.oauth2ProviderAppCodes[index+1:]...)
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(_ context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	codes := q.oauth2ProviderAppCodes[:0]
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.AppID != arg.AppID || code.UserID != arg.UserID {
+			codes = append(codes, code)
+		}
+	}
+	q.oauth2ProviderAppCodes = codes
+	return nil
+}
+
+func (q *fakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets[:index], q.oauth2ProviderAppSecrets[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	keyIDs := make(map[string]bool)
+	for _, apiKey := range q.apiKeys {
+		if apiKey.UserID == arg.UserID {
+			keyIDs[apiKey.ID] = true
+		}
+	}
+	tokens := q.oauth2ProviderAppTokens[:0]
+	revoked := make(map[string]bool)
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.AppID == arg.AppID && keyIDs[token.APIKeyID] {
+			revoked[token.APIKeyID] = true
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	q.oauth2ProviderAppTokens = tokens
+
+	apiKeys := q.apiKeys[:0]
+	for _, apiKey := range q.apiKeys {
+		if !revoked[apiKey.ID] {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	q.apiKeys = apiKeys
+	return nil
+}
+
+func (q *fakeQuerier) DeleteOldNotificationMessages(_ context.Context, before time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	messages := q.notificationMessages[:0]
+	for _, message := range q.notificationMessages {
+		if (message.Status == database.NotificationMessageStatusSent || message.Status == database.NotificationMessageStatusFailed) &&
+			message.UpdatedAt.Before(before) {
+			continue
+		}
+		messages = append(messages, message)
+	}
+	q.notificationMessages = messages
+	return nil
+}
+
+func (q *fakeQuerier) DeleteOldSessionRecordings(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	before := database.Now().Add(-30 * 24 * time.Hour)
+	deleted := map[uuid.UUID]struct{}{}
+	recordings := q.sessionRecordings[:0]
+	for _, recording := range q.sessionRecordings {
+		if recording.StartedAt.Before(before) {
+			deleted[recording.ID] = struct{}{}
+			continue
+		}
+		recordings = append(recordings, recording)
+	}
+	q.sessionRecordings = recordings
+	chunks := q.sessionRecordingChunks[:0]
+	for _, chunk := range q.sessionRecordingChunks {
+		if _, ok := deleted[chunk.RecordingID]; ok {
+			continue
+		}
+		chunks = append(chunks, chunk)
+	}
+	q.sessionRecordingChunks = chunks
+	return nil
+}
+
+func (q *fakeQuerier) DeleteOldWebhookDeliveries(_ context.Context, before time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deliveries := q.webhookDeliveries[:0]
+	for _, delivery := range q.webhookDeliveries {
+		if (delivery.Status == database.WebhookDeliveryStatusSucceeded || delivery.Status == database.WebhookDeliveryStatusFailed) &&
+			delivery.UpdatedAt.Before(before) {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	q.webhookDeliveries = deliveries
+	return nil
+}
+
 func (*fakeQuerier) DeleteOldWorkspaceAgentStartupLogs(_ context.Context) error {
 	// noop
 	return nil
@@ -1200,6 +1504,119 @@ func (*fakeQuerier) DeleteTailnetClient(context.Context, database.DeleteTailnetC
 	return database.DeleteTailnetClientRow{}, ErrUnimplemented
 }
 
+func (q *fakeQuerier) DeleteTemplateVersionWithoutTemplate(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, version := range q.templateVersions {
+		if version.ID != id || version.TemplateID.Valid {
+			continue
+		}
+		q.templateVersions = append(q.templateVersions[:i], q.templateVersions[i+1:]...)
+		for j, job := range q.provisionerJobs {
+			if job.ID == version.JobID {
+				q.provisionerJobs = append(q.provisionerJobs[:j], q.provisionerJobs[j+1:]...)
+				break
+			}
+		}
+		parameters := q.templateVersionParameters[:0]
+		for _, parameter := range q.templateVersionParameters {
+			if parameter.TemplateVersionID != id {
+				parameters = append(parameters, parameter)
+			}
+		}
+		q.templateVersionParameters = parameters
+		variables := q.templateVersionVariables[:0]
+		for _, variable := range q.templateVersionVariables {
+			if variable.TemplateVersionID != id {
+				variables = append(variables, variable)
+			}
+		}
+		q.templateVersionVariables = variables
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) DeleteWebhookByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, webhook := range q.webhooks {
+		if webhook.ID != id {
+			continue
+		}
+		q.webhooks = append(q.webhooks[:index], q.webhooks[index+1:]...)
+
+		deliveries := q.webhookDeliveries[:0]
+		for _, delivery := range q.webhookDeliveries {
+			if delivery.WebhookID != id {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		q.webhookDeliveries = deliveries
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) EnqueueNotificationMessage(_ context.Context, arg database.EnqueueNotificationMessageParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, message := range q.notificationMessages {
+		if message.DedupeHash == arg.DedupeHash {
+			return nil
+		}
+	}
+	q.notificationMessages = append(q.notificationMessages, database.NotificationMessage{
+		ID:         arg.ID,
+		UserID:     arg.UserID,
+		Template:   arg.Template,
+		Method:     arg.Method,
+		Labels:     arg.Labels,
+		Status:     database.NotificationMessageStatusPending,
+		DedupeHash: arg.DedupeHash,
+		CreatedAt:  arg.CreatedAt,
+		UpdatedAt:  arg.CreatedAt,
+		SendAfter:  arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *fakeQuerier) EnqueueWebhookDeliveries(_ context.Context, arg database.EnqueueWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var deliveries []database.WebhookDelivery
+	for _, webhook := range q.webhooks {
+		if !slices.Contains(webhook.Events, arg.Event) {
+			continue
+		}
+		delivery := database.WebhookDelivery{
+			ID:        uuid.New(),
+			WebhookID: webhook.ID,
+			Event:     arg.Event,
+			Payload:   arg.Payload,
+			Status:    database.WebhookDeliveryStatusPending,
+			CreatedAt: arg.CreatedAt,
+			UpdatedAt: arg.CreatedAt,
+			SendAfter: arg.CreatedAt,
+		}
+		q.webhookDeliveries = append(q.webhookDeliveries, delivery)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
 func (q *fakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1283,6 +1700,19 @@ func (q *fakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 	return active, nil
 }
 
+func (q *fakeQuerier) GetActiveUsersByRoleForUpdate(_ context.Context, roleName string) ([]database.User, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	users := make([]database.User, 0)
+	for _, user := range q.users {
+		if user.Status == database.UserStatusActive && !user.Deleted && slices.Contains(user.RBACRoles, roleName) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
 func (q *fakeQuerier) GetAppSecurityKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1426,6 +1856,63 @@ func (q *fakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 	}, nil
 }
 
+func (q *fakeQuerier) GetCustomRoleByID(_ context.Context, id uuid.UUID) (database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, role := range q.customRoles {
+		if role.ID == id {
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetCustomRoleByName(_ context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, role := range q.customRoles {
+		if role.Name == arg.Name && role.OrganizationID.UUID == arg.OrganizationID {
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetCustomRoles(_ context.Context, organizationID uuid.UUID) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if role.OrganizationID.UUID == organizationID {
+			roles = append(roles, role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
+func (q *fakeQuerier) GetCustomRolesByNames(_ context.Context, names []string) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if slice.Contains(names, role.RoleName()) {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
 func (q *fakeQuerier) GetDERPMeshKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1868,6 +2355,172 @@ func (q *fakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *fakeQuerier) GetNotificationMessagesByUserID(_ context.Context, userID uuid.UUID) ([]database.NotificationMessage, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	messages := []database.NotificationMessage{}
+	for _, message := range q.notificationMessages {
+		if message.UserID == userID {
+			messages = append(messages, message)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.After(messages[j].CreatedAt)
+	})
+	return messages, nil
+}
+
+func (q *fakeQuerier) GetNotificationPreferencesByUserID(_ context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	preferences := []database.NotificationPreference{}
+	for _, preference := range q.notificationPreferences {
+		if preference.UserID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	sort.Slice(preferences, func(i, j int) bool {
+		return preferences[i].Template < preferences[j].Template
+	})
+	return preferences, nil
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == id {
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppCodeByHashedSecret(_ context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if bytes.Equal(code.HashedSecret, hashedSecret) {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppSecretByHashedSecret(_ context.Context, hashedSecret []byte) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if bytes.Equal(secret.HashedSecret, hashedSecret) {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppSecretsByAppID(_ context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.OAuth2ProviderAppSecret, 0)
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == appID {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return secrets[i].CreatedAt.Before(secrets[j].CreatedAt)
+	})
+	return secrets, nil
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppTokenByHashedRefreshToken(_ context.Context, hashedRefreshToken []byte) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if !bytes.Equal(token.HashedRefreshToken, hashedRefreshToken) {
+			continue
+		}
+		// Tokens are removed with their API key by the database.
+		for _, apiKey := range q.apiKeys {
+			if apiKey.ID == token.APIKeyID {
+				return token, nil
+			}
+		}
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderApps(_ context.Context) ([]database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apps := make([]database.OAuth2ProviderApp, len(q.oauth2ProviderApps))
+	copy(apps, q.oauth2ProviderApps)
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppsByUserID(_ context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	keyIDs := make(map[string]bool)
+	for _, apiKey := range q.apiKeys {
+		if apiKey.UserID == userID {
+			keyIDs[apiKey.ID] = true
+		}
+	}
+	appIDs := make(map[uuid.UUID]bool)
+	for _, token := range q.oauth2ProviderAppTokens {
+		if keyIDs[token.APIKeyID] {
+			appIDs[token.AppID] = true
+		}
+	}
+	apps := make([]database.OAuth2ProviderApp, 0)
+	for _, app := range q.oauth2ProviderApps {
+		if appIDs[app.ID] {
+			apps = append(apps, app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
 func (q *fakeQuerier) GetOrganizationByID(_ context.Context, id uuid.UUID) (database.Organization, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2224,6 +2877,50 @@ func (q *fakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
 	return string(q.serviceBanner), nil
 }
 
+func (q *fakeQuerier) GetSessionRecordingByID(_ context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.sessionRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.SessionRecording{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetSessionRecordingChunksByRecordingID(_ context.Context, recordingID uuid.UUID) ([]database.SessionRecordingChunk, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	chunks := make([]database.SessionRecordingChunk, 0)
+	for _, chunk := range q.sessionRecordingChunks {
+		if chunk.RecordingID == recordingID {
+			chunks = append(chunks, chunk)
+		}
+	}
+	slices.SortFunc(chunks, func(a, b database.SessionRecordingChunk) bool {
+		return a.Sequence < b.Sequence
+	})
+	return chunks, nil
+}
+
+func (q *fakeQuerier) GetSessionRecordingsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	recordings := make([]database.SessionRecording, 0)
+	for _, recording := range q.sessionRecordings {
+		if recording.WorkspaceID == workspaceID {
+			recordings = append(recordings, recording)
+		}
+	}
+	slices.SortFunc(recordings, func(a, b database.SessionRecording) bool {
+		return a.StartedAt.After(b.StartedAt)
+	})
+	return recordings, nil
+}
+
 func (*fakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
 	return nil, ErrUnimplemented
 }
@@ -2760,6 +3457,60 @@ func (q *fakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *fakeQuerier) GetWebhookByID(_ context.Context, id uuid.UUID) (database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWebhookDeliveriesByWebhookID(_ context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deliveries := make([]database.WebhookDelivery, 0)
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(deliveries) {
+			return []database.WebhookDelivery{}, nil
+		}
+		deliveries = deliveries[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(deliveries) {
+		deliveries = deliveries[:arg.LimitOpt]
+	}
+	return deliveries, nil
+}
+
+func (q *fakeQuerier) GetWebhooks(_ context.Context) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := make([]database.Webhook, len(q.webhooks))
+	copy(webhooks, q.webhooks)
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Name < webhooks[j].Name
+	})
+	return webhooks, nil
+}
+
 func (q *fakeQuerier) GetWorkspaceAgentByAuthToken(_ context.Context, authToken uuid.UUID) (database.WorkspaceAgent, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2810,6 +3561,22 @@ func (q *fakeQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, i
 	}, nil
 }
 
+func (q *fakeQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logSources := make([]database.WorkspaceAgentLogSource, 0)
+	for _, logSource := range q.workspaceAgentLogSources {
+		for _, id := range ids {
+			if logSource.WorkspaceAgentID == id {
+				logSources = append(logSources, logSource)
+				break
+			}
+		}
+	}
+	return logSources, nil
+}
+
 func (q *fakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentMetadatum, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2823,6 +3590,34 @@ func (q *fakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, workspaceAgen
 	return metadata, nil
 }
 
+func (q *fakeQuerier) GetWorkspaceAgentProcesses(_ context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentProcess, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, processes := range q.workspaceAgentProcesses {
+		if processes.WorkspaceAgentID == workspaceAgentID {
+			return processes, nil
+		}
+	}
+	return database.WorkspaceAgentProcess{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	scripts := make([]database.WorkspaceAgentScript, 0)
+	for _, script := range q.workspaceAgentScripts {
+		for _, id := range ids {
+			if script.WorkspaceAgentID == id {
+				scripts = append(scripts, script)
+				break
+			}
+		}
+	}
+	return scripts, nil
+}
+
 func (q *fakeQuerier) GetWorkspaceAgentStartupLogsAfter(_ context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -3505,6 +4300,37 @@ func (q *fakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, no
 	return workspaces, nil
 }
 
+func (q *fakeQuerier) GetWorkspacesMarkedForDeletion(ctx context.Context) ([]database.Workspace, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaces := []database.Workspace{}
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		template, err := q.getTemplateByIDNoLock(ctx, workspace.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template by ID: %w", err)
+		}
+		if template.InactivityTTL <= 0 {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			return nil, err
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, xerrors.Errorf("get provisioner job by ID: %w", err)
+		}
+		if build.Transition == database.WorkspaceTransitionStop || job.CanceledAt.Valid || job.Error.String != "" {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	return workspaces, nil
+}
+
 func (q *fakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.APIKey{}, err
@@ -3568,6 +4394,35 @@ func (q *fakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 	return alog, nil
 }
 
+func (q *fakeQuerier) InsertCustomRole(_ context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, role := range q.customRoles {
+		if role.Name == arg.Name && role.OrganizationID.UUID == arg.OrganizationID.UUID {
+			return database.CustomRole{}, errDuplicateKey
+		}
+	}
+
+	role := database.CustomRole{
+		ID:              arg.ID,
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
 func (q *fakeQuerier) InsertDERPMeshKey(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -3709,15 +4564,116 @@ func (q *fakeQuerier) InsertLicense(
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	l := database.License{
-		ID:         q.lastLicenseID + 1,
-		UploadedAt: arg.UploadedAt,
-		JWT:        arg.JWT,
-		Exp:        arg.Exp,
+	l := database.License{
+		ID:         q.lastLicenseID + 1,
+		UploadedAt: arg.UploadedAt,
+		JWT:        arg.JWT,
+		Exp:        arg.Exp,
+	}
+	q.lastLicenseID = l.ID
+	q.licenses = append(q.licenses, l)
+	return l, nil
+}
+
+func (q *fakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	app := database.OAuth2ProviderApp{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		Name:        arg.Name,
+		Icon:        arg.Icon,
+		CallbackURL: arg.CallbackURL,
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
+	return app, nil
+}
+
+func (q *fakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	code := database.OAuth2ProviderAppCode{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		ExpiresAt:     arg.ExpiresAt,
+		HashedSecret:  arg.HashedSecret,
+		UserID:        arg.UserID,
+		AppID:         arg.AppID,
+		RedirectURI:   arg.RedirectURI,
+		Scope:         arg.Scope,
+		CodeChallenge: arg.CodeChallenge,
+	}
+	q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+	return code, nil
+}
+
+func (q *fakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if bytes.Equal(secret.HashedSecret, arg.HashedSecret) {
+			return database.OAuth2ProviderAppSecret{}, errDuplicateKey
+		}
+	}
+
+	secret := database.OAuth2ProviderAppSecret{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		HashedSecret:  arg.HashedSecret,
+		DisplaySecret: arg.DisplaySecret,
+		AppID:         arg.AppID,
+	}
+	q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets, secret)
+	return secret, nil
+}
+
+func (q *fakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	token := database.OAuth2ProviderAppToken{
+		ID:                 arg.ID,
+		CreatedAt:          arg.CreatedAt,
+		ExpiresAt:          arg.ExpiresAt,
+		HashedRefreshToken: arg.HashedRefreshToken,
+		AppID:              arg.AppID,
+		APIKeyID:           arg.APIKeyID,
 	}
-	q.lastLicenseID = l.ID
-	q.licenses = append(q.licenses, l)
-	return l, nil
+	q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
+	return token, nil
 }
 
 func (q *fakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
@@ -3854,6 +4810,53 @@ func (q *fakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplic
 	return replica, nil
 }
 
+func (q *fakeQuerier) InsertSessionRecording(_ context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.SessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, recording := range q.sessionRecordings {
+		if recording.ID == arg.ID {
+			return database.SessionRecording{}, errDuplicateKey
+		}
+	}
+	recording := database.SessionRecording{
+		ID:               arg.ID,
+		WorkspaceID:      arg.WorkspaceID,
+		WorkspaceBuildID: arg.WorkspaceBuildID,
+		AgentID:          arg.AgentID,
+		UserID:           arg.UserID,
+		Type:             arg.Type,
+		StartedAt:        arg.StartedAt,
+	}
+	q.sessionRecordings = append(q.sessionRecordings, recording)
+	return recording, nil
+}
+
+func (q *fakeQuerier) InsertSessionRecordingChunk(_ context.Context, arg database.InsertSessionRecordingChunkParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, chunk := range q.sessionRecordingChunks {
+		if chunk.RecordingID == arg.RecordingID && chunk.Sequence == arg.Sequence {
+			return nil
+		}
+	}
+	q.sessionRecordingChunks = append(q.sessionRecordingChunks, database.SessionRecordingChunk{
+		RecordingID: arg.RecordingID,
+		Sequence:    arg.Sequence,
+		Data:        arg.Data,
+	})
+	return nil
+}
+
 func (q *fakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTemplateParams) (database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Template{}, err
@@ -3880,6 +4883,7 @@ func (q *fakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		AllowUserCancelWorkspaceJobs: arg.AllowUserCancelWorkspaceJobs,
 		AllowUserAutostart:           true,
 		AllowUserAutostop:            true,
+		IdleThreshold:                arg.IdleThreshold,
 	}
 	q.templates = append(q.templates, template)
 	return template.DeepCopy(), nil
@@ -4049,6 +5053,33 @@ func (q *fakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *fakeQuerier) InsertWebhook(_ context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.Name == arg.Name {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	webhook := database.Webhook{
+		ID:        arg.ID,
+		Name:      arg.Name,
+		Endpoint:  arg.Endpoint,
+		Secret:    arg.Secret,
+		Events:    arg.Events,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	q.webhooks = append(q.webhooks, webhook)
+	return webhook, nil
+}
+
 func (q *fakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -4109,6 +5140,30 @@ func (q *fakeQuerier) InsertWorkspaceAgent(_ context.Context, arg database.Inser
 	return agent, nil
 }
 
+func (q *fakeQuerier) InsertWorkspaceAgentLogSources(_ context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logSources := make([]database.WorkspaceAgentLogSource, 0)
+	for index, source := range arg.ID {
+		logSource := database.WorkspaceAgentLogSource{
+			ID:               source,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			CreatedAt:        arg.CreatedAt,
+			DisplayName:      arg.DisplayName[index],
+			Icon:             arg.Icon[index],
+		}
+		logSources = append(logSources, logSource)
+	}
+	q.workspaceAgentLogSources = append(q.workspaceAgentLogSources, logSources...)
+	return logSources, nil
+}
+
 func (q *fakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4121,12 +5176,43 @@ func (q *fakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 		Key:              arg.Key,
 		Timeout:          arg.Timeout,
 		Interval:         arg.Interval,
+		Collector:        arg.Collector,
 	}
 
 	q.workspaceAgentMetadata = append(q.workspaceAgentMetadata, metadatum)
 	return nil
 }
 
+func (q *fakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	scripts := make([]database.WorkspaceAgentScript, 0)
+	for index, source := range arg.LogSourceID {
+		script := database.WorkspaceAgentScript{
+			LogSourceID:      source,
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			LogPath:          arg.LogPath[index],
+			Script:           arg.Script[index],
+			Cron:             arg.Cron[index],
+			StartBlocksLogin: arg.StartBlocksLogin[index],
+			RunOnStart:       arg.RunOnStart[index],
+			RunOnStop:        arg.RunOnStop[index],
+			TimeoutSeconds:   arg.TimeoutSeconds[index],
+			CreatedAt:        arg.CreatedAt,
+			Status:           database.WorkspaceAgentScriptStatusPending,
+		}
+		scripts = append(scripts, script)
+	}
+	q.workspaceAgentScripts = append(q.workspaceAgentScripts, scripts...)
+	return scripts, nil
+}
+
 func (q *fakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -4144,11 +5230,12 @@ func (q *fakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg dat
 	for index, output := range arg.Output {
 		id++
 		logs = append(logs, database.WorkspaceAgentStartupLog{
-			ID:        id,
-			AgentID:   arg.AgentID,
-			CreatedAt: arg.CreatedAt[index],
-			Level:     arg.Level[index],
-			Output:    output,
+			ID:          id,
+			AgentID:     arg.AgentID,
+			CreatedAt:   arg.CreatedAt[index],
+			Level:       arg.Level[index],
+			Output:      output,
+			LogSourceID: arg.LogSourceID,
 		})
 		outputLength += int32(len(output))
 	}
@@ -4359,6 +5446,54 @@ func (q *fakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *fakeQuerier) MarkNotificationMessageFailed(_ context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, message := range q.notificationMessages {
+		if message.ID != arg.ID {
+			continue
+		}
+		message.Status = database.NotificationMessageStatusFailed
+		if arg.Retry {
+			message.Status = database.NotificationMessageStatusPending
+		}
+		message.UpdatedAt = arg.UpdatedAt
+		message.SendAfter = arg.SendAfter
+		message.LeasedUntil = sql.NullTime{}
+		message.LastError = arg.LastError
+		q.notificationMessages[i] = message
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) MarkNotificationMessageSent(_ context.Context, arg database.MarkNotificationMessageSentParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, message := range q.notificationMessages {
+		if message.ID != arg.ID {
+			continue
+		}
+		message.Status = database.NotificationMessageStatusSent
+		message.UpdatedAt = arg.UpdatedAt
+		message.LeasedUntil = sql.NullTime{}
+		message.LastError = sql.NullString{}
+		q.notificationMessages[i] = message
+		return nil
+	}
+	return nil
+}
+
 func (q *fakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4375,6 +5510,32 @@ func (q *fakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.Reg
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) RemoveRoleFromOrganizationMembers(_ context.Context, arg database.RemoveRoleFromOrganizationMembersParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, member := range q.organizationMembers {
+		if member.OrganizationID == arg.OrganizationID {
+			q.organizationMembers[i].Roles = removeRole(member.Roles, arg.RoleName)
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) RemoveRoleFromUsers(_ context.Context, roleName string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, user := range q.users {
+		q.users[i].RBACRoles = removeRole(user.RBACRoles, roleName)
+	}
+	return nil
+}
+
 func (*fakeQuerier) TryAcquireLock(_ context.Context, _ int64) (bool, error) {
 	return false, xerrors.New("TryAcquireLock must only be called within a transaction")
 }
@@ -4400,6 +5561,29 @@ func (q *fakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateCustomRoleByID(_ context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, role := range q.customRoles {
+		if role.ID != arg.ID {
+			continue
+		}
+		role.DisplayName = arg.DisplayName
+		role.SitePermissions = arg.SitePermissions
+		role.OrgPermissions = arg.OrgPermissions
+		role.UserPermissions = arg.UserPermissions
+		role.UpdatedAt = arg.UpdatedAt
+		q.customRoles[index] = role
+		return role, nil
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateGitAuthLink(_ context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.GitAuthLink{}, err
@@ -4496,6 +5680,53 @@ func (q *fakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID != arg.ID && app.Name == arg.Name {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+	for index, app := range q.oauth2ProviderApps {
+		if app.ID != arg.ID {
+			continue
+		}
+		app.UpdatedAt = arg.UpdatedAt
+		app.Name = arg.Name
+		app.Icon = arg.Icon
+		app.CallbackURL = arg.CallbackURL
+		q.oauth2ProviderApps[index] = app
+		return app, nil
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateOAuth2ProviderAppSecretLastUsedAtByID(_ context.Context, arg database.UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == arg.ID {
+			secret.LastUsedAt = arg.LastUsedAt
+			q.oauth2ProviderAppSecrets[index] = secret
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4584,6 +5815,32 @@ func (q *fakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateSessionRecordingByID(_ context.Context, arg database.UpdateSessionRecordingByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var size int64
+	for _, chunk := range q.sessionRecordingChunks {
+		if chunk.RecordingID == arg.ID {
+			size += int64(len(chunk.Data))
+		}
+	}
+	for index, recording := range q.sessionRecordings {
+		if recording.ID != arg.ID {
+			continue
+		}
+		recording.EndedAt = arg.EndedAt
+		recording.Size = size
+		q.sessionRecordings[index] = recording
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Template{}, err
@@ -4662,6 +5919,7 @@ func (q *fakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.IdleThreshold = arg.IdleThreshold
 		q.templates[idx] = tpl
 		return tpl.DeepCopy(), nil
 	}
@@ -4688,6 +5946,7 @@ func (q *fakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database
 		tpl.MaxTTL = arg.MaxTTL
 		tpl.FailureTTL = arg.FailureTTL
 		tpl.InactivityTTL = arg.InactivityTTL
+		tpl.LockedTTL = arg.LockedTTL
 		q.templates[idx] = tpl
 		return tpl.DeepCopy(), nil
 	}
@@ -4945,6 +6204,58 @@ func (q *fakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWebhookByID(_ context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID != arg.ID && webhook.Name == arg.Name {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+	for index, webhook := range q.webhooks {
+		if webhook.ID != arg.ID {
+			continue
+		}
+		webhook.Name = arg.Name
+		webhook.Endpoint = arg.Endpoint
+		webhook.Secret = arg.Secret
+		webhook.Events = arg.Events
+		webhook.UpdatedAt = arg.UpdatedAt
+		q.webhooks[index] = webhook
+		return webhook, nil
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWebhookDeliveryByID(_ context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, delivery := range q.webhookDeliveries {
+		if delivery.ID != arg.ID {
+			continue
+		}
+		delivery.Status = arg.Status
+		delivery.StatusCode = arg.StatusCode
+		delivery.LastError = arg.LastError
+		delivery.UpdatedAt = arg.UpdatedAt
+		delivery.SendAfter = arg.SendAfter
+		delivery.LeasedUntil = sql.NullTime{}
+		q.webhookDeliveries[index] = delivery
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -5039,6 +6350,29 @@ func (q *fakeQuerier) UpdateWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *fakeQuerier) UpdateWorkspaceAgentScriptStatus(_ context.Context, arg database.UpdateWorkspaceAgentScriptStatusParams) (database.WorkspaceAgentScript, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, script := range q.workspaceAgentScripts {
+		if script.WorkspaceAgentID != arg.WorkspaceAgentID || script.LogSourceID != arg.LogSourceID {
+			continue
+		}
+		script.Status = arg.Status
+		script.ExitCode = arg.ExitCode
+		script.StartedAt = arg.StartedAt
+		script.EndedAt = arg.EndedAt
+		q.workspaceAgentScripts[index] = script
+		return script, nil
+	}
+	return database.WorkspaceAgentScript{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceAgentStartupByID(_ context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5307,6 +6641,32 @@ func (q *fakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
 	return nil
 }
 
+func (q *fakeQuerier) UpsertNotificationPreference(_ context.Context, arg database.UpsertNotificationPreferenceParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, preference := range q.notificationPreferences {
+		if preference.UserID == arg.UserID && preference.Template == arg.Template {
+			preference.Disabled = arg.Disabled
+			preference.UpdatedAt = arg.UpdatedAt
+			q.notificationPreferences[i] = preference
+			return nil
+		}
+	}
+	//nolint:gosimple
+	q.notificationPreferences = append(q.notificationPreferences, database.NotificationPreference{
+		UserID:    arg.UserID,
+		Template:  arg.Template,
+		Disabled:  arg.Disabled,
+		UpdatedAt: arg.UpdatedAt,
+	})
+	return nil
+}
+
 func (q *fakeQuerier) UpsertServiceBanner(_ context.Context, data string) error {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5326,3 +6686,28 @@ func (*fakeQuerier) UpsertTailnetClient(context.Context, database.UpsertTailnetC
 func (*fakeQuerier) UpsertTailnetCoordinator(context.Context, uuid.UUID) (database.TailnetCoordinator, error) {
 	return database.TailnetCoordinator{}, ErrUnimplemented
 }
+
+func (q *fakeQuerier) UpsertWorkspaceAgentProcesses(_ context.Context, arg database.UpsertWorkspaceAgentProcessesParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	processes := database.WorkspaceAgentProcess{
+		WorkspaceAgentID: arg.WorkspaceAgentID,
+		CollectedAt:      arg.CollectedAt,
+		Processes:        arg.Processes,
+	}
+	for i, p := range q.workspaceAgentProcesses {
+		if p.WorkspaceAgentID == arg.WorkspaceAgentID {
+			q.workspaceAgentProcesses[i] = processes
+			return nil
+		}
+	}
+	q.workspaceAgentProcesses = append(q.workspaceAgentProcesses, processes)
+	return nil
+}
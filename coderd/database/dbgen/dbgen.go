@@ -77,6 +77,7 @@ func Template(t testing.TB, db database.Store, seed database.Template) database.
 		GroupACL:                     seed.GroupACL,
 		DisplayName:                  takeFirst(seed.DisplayName, namesgenerator.GetRandomName(1)),
 		AllowUserCancelWorkspaceJobs: seed.AllowUserCancelWorkspaceJobs,
+		IdleThreshold:                seed.IdleThreshold,
 	})
 	require.NoError(t, err, "insert template")
 	return template
@@ -527,6 +528,110 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 	return scheme
 }
 
+func SessionRecording(t testing.TB, db database.Store, orig database.SessionRecording) database.SessionRecording {
+	recording, err := db.InsertSessionRecording(genCtx, database.InsertSessionRecordingParams{
+		ID:               takeFirst(orig.ID, uuid.New()),
+		WorkspaceID:      takeFirst(orig.WorkspaceID, uuid.New()),
+		WorkspaceBuildID: takeFirst(orig.WorkspaceBuildID, uuid.New()),
+		AgentID:          takeFirst(orig.AgentID, uuid.New()),
+		UserID:           orig.UserID,
+		Type:             takeFirst(orig.Type, database.SessionRecordingTypeSSH),
+		StartedAt:        takeFirst(orig.StartedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert session recording")
+	return recording
+}
+
+func Webhook(t testing.TB, db database.Store, orig database.Webhook) database.Webhook {
+	webhook, err := db.InsertWebhook(genCtx, database.InsertWebhookParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		Name:      takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		Endpoint:  takeFirst(orig.Endpoint, "https://hooks.example.com"),
+		Secret:    takeFirst(orig.Secret, namesgenerator.GetRandomName(1)),
+		Events:    takeFirstSlice(orig.Events, []database.WebhookEvent{database.WebhookEventWorkspaceBuildSucceeded}),
+		CreatedAt: takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt: takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert webhook")
+	return webhook
+}
+
+func CustomRole(t testing.TB, db database.Store, orig database.CustomRole) database.CustomRole {
+	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
+		ID:              takeFirst(orig.ID, uuid.New()),
+		Name:            takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		DisplayName:     takeFirst(orig.DisplayName, namesgenerator.GetRandomName(1)),
+		OrganizationID:  orig.OrganizationID,
+		SitePermissions: takeFirstSlice(orig.SitePermissions, database.CustomRolePermissions{}),
+		OrgPermissions:  takeFirstSlice(orig.OrgPermissions, database.CustomRolePermissions{}),
+		UserPermissions: takeFirstSlice(orig.UserPermissions, database.CustomRolePermissions{}),
+		CreatedAt:       takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt:       takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert custom role")
+	return role
+}
+
+func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
+	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		Name:        takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		CreatedAt:   takeFirst(seed.CreatedAt, database.Now()),
+		UpdatedAt:   takeFirst(seed.UpdatedAt, database.Now()),
+		Icon:        takeFirst(seed.Icon, ""),
+		CallbackURL: takeFirst(seed.CallbackURL, "http://localhost"),
+	})
+	require.NoError(t, err, "insert oauth2 app")
+	return app
+}
+
+func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAuth2ProviderAppSecret) database.OAuth2ProviderAppSecret {
+	secret, _ := cryptorand.String(40)
+	hashed := sha256.Sum256([]byte(secret))
+	appSecret, err := db.InsertOAuth2ProviderAppSecret(genCtx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, database.Now()),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, hashed[:]),
+		DisplaySecret: takeFirst(seed.DisplaySecret, secret[len(secret)-6:]),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 app secret")
+	return appSecret
+}
+
+func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
+	secret, _ := cryptorand.String(40)
+	hashed := sha256.Sum256([]byte(secret))
+	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, database.Now()),
+		ExpiresAt:     takeFirst(seed.ExpiresAt, database.Now().Add(10*time.Minute)),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, hashed[:]),
+		UserID:        takeFirst(seed.UserID, uuid.New()),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+		RedirectURI:   takeFirst(seed.RedirectURI, "http://localhost"),
+		Scope:         takeFirst(seed.Scope, database.APIKeyScopeAll),
+		CodeChallenge: takeFirst(seed.CodeChallenge, ""),
+	})
+	require.NoError(t, err, "insert oauth2 app code")
+	return code
+}
+
+func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
+	refresh, _ := cryptorand.String(40)
+	hashed := sha256.Sum256([]byte(refresh))
+	token, err := db.InsertOAuth2ProviderAppToken(genCtx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:                 takeFirst(seed.ID, uuid.New()),
+		CreatedAt:          takeFirst(seed.CreatedAt, database.Now()),
+		ExpiresAt:          takeFirst(seed.ExpiresAt, database.Now().Add(time.Hour)),
+		HashedRefreshToken: takeFirstSlice(seed.HashedRefreshToken, hashed[:]),
+		AppID:              takeFirst(seed.AppID, uuid.New()),
+		APIKeyID:           takeFirst(seed.APIKeyID),
+	})
+	require.NoError(t, err, "insert oauth2 app token")
+	return token
+}
+
 func must[V any](v V, err error) V {
 	if err != nil {
 		panic(err)
@@ -115,6 +115,13 @@ func (m metricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64)
 	return err
 }
 
+func (m metricsStore) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireNotificationMessages(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireNotificationMessages").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	provisionerJob, err := m.s.AcquireProvisionerJob(ctx, arg)
@@ -122,6 +129,13 @@ func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.Ac
 	return provisionerJob, err
 }
 
+func (m metricsStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.CleanTailnetCoordinators(ctx)
@@ -129,11 +143,11 @@ func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	return err
 }
 
-func (m metricsStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
+func (m metricsStore) DeleteAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
-	err := m.s.DeleteAPIKeyByID(ctx, id)
+	apiKey, err := m.s.DeleteAPIKeyByID(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteAPIKeyByID").Observe(time.Since(start).Seconds())
-	return err
+	return apiKey, err
 }
 
 func (m metricsStore) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
@@ -156,6 +170,13 @@ func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	return m.s.DeleteCoordinator(ctx, id)
 }
 
+func (m metricsStore) DeleteCustomRoleByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteCustomRoleByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteCustomRoleByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteGitSSHKey(ctx, userID)
@@ -191,6 +212,64 @@ func (m metricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error
 	return licenseID, err
 }
 
+func (m metricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	code, err := m.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return code, err
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodesByAppAndUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppTokensByAppAndUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldNotificationMessages(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNotificationMessages(ctx, before)
+	m.queryLatencies.WithLabelValues("DeleteOldNotificationMessages").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldSessionRecordings(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldSessionRecordings(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldSessionRecordings").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldWebhookDeliveries(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWebhookDeliveries(ctx, before)
+	m.queryLatencies.WithLabelValues("DeleteOldWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.DeleteOldWorkspaceAgentStartupLogs(ctx)
@@ -224,6 +303,34 @@ func (m metricsStore) DeleteTailnetClient(ctx context.Context, arg database.Dele
 	return m.s.DeleteTailnetClient(ctx, arg)
 }
 
+func (m metricsStore) DeleteTemplateVersionWithoutTemplate(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateVersionWithoutTemplate(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteTemplateVersionWithoutTemplate").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebhookByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
+	start := time.Now()
+	r0 := m.s.EnqueueNotificationMessage(ctx, arg)
+	m.queryLatencies.WithLabelValues("EnqueueNotificationMessage").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) EnqueueWebhookDeliveries(ctx context.Context, arg database.EnqueueWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.EnqueueWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("EnqueueWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
@@ -266,6 +373,13 @@ func (m metricsStore) GetActiveUserCount(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+func (m metricsStore) GetActiveUsersByRoleForUpdate(ctx context.Context, roleName string) ([]database.User, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveUsersByRoleForUpdate(ctx, roleName)
+	m.queryLatencies.WithLabelValues("GetActiveUsersByRoleForUpdate").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAppSecurityKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetAppSecurityKey(ctx)
@@ -287,6 +401,34 @@ func (m metricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid
 	return row, err
 }
 
+func (m metricsStore) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoleByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetCustomRoleByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRoleByName(ctx context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoleByName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetCustomRoleByName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRoles(ctx context.Context, organizationID uuid.UUID) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoles(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetCustomRoles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRolesByNames(ctx context.Context, names []string) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRolesByNames(ctx, names)
+	m.queryLatencies.WithLabelValues("GetCustomRolesByNames").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetDERPMeshKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetDERPMeshKey(ctx)
@@ -455,6 +597,83 @@ func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m metricsStore) GetNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationMessagesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetNotificationMessagesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationPreferencesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetNotificationPreferencesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByHashedSecret(ctx, hashedSecret)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByHashedSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretByHashedSecret(ctx, hashedSecret)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByHashedSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretsByAppID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx context.Context, hashedRefreshToken []byte) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx, hashedRefreshToken)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByHashedRefreshToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderApps(ctx)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByID(ctx, id)
@@ -588,6 +807,27 @@ func (m metricsStore) GetServiceBanner(ctx context.Context) (string, error) {
 	return banner, err
 }
 
+func (m metricsStore) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	start := time.Now()
+	recording, err := m.s.GetSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return recording, err
+}
+
+func (m metricsStore) GetSessionRecordingChunksByRecordingID(ctx context.Context, recordingID uuid.UUID) ([]database.SessionRecordingChunk, error) {
+	start := time.Now()
+	chunks, err := m.s.GetSessionRecordingChunksByRecordingID(ctx, recordingID)
+	m.queryLatencies.WithLabelValues("GetSessionRecordingChunksByRecordingID").Observe(time.Since(start).Seconds())
+	return chunks, err
+}
+
+func (m metricsStore) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	start := time.Now()
+	recordings, err := m.s.GetSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetSessionRecordingsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return recordings, err
+}
+
 func (m metricsStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	defer m.queryLatencies.WithLabelValues("GetTailnetAgents").Observe(time.Since(start).Seconds())
@@ -754,6 +994,27 @@ func (m metricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]dat
 	return users, err
 }
 
+func (m metricsStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveriesByWebhookID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooks(ctx)
+	m.queryLatencies.WithLabelValues("GetWebhooks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken uuid.UUID) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByAuthToken(ctx, authToken)
@@ -782,6 +1043,13 @@ func (m metricsStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, i
 	return r0, r1
 }
 
+func (m metricsStore) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	start := time.Now()
+	logSources, err := m.s.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogSourcesByAgentIDs").Observe(time.Since(start).Seconds())
+	return logSources, err
+}
+
 func (m metricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID uuid.UUID) ([]database.WorkspaceAgentMetadatum, error) {
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
@@ -789,6 +1057,20 @@ func (m metricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAg
 	return metadata, err
 }
 
+func (m metricsStore) GetWorkspaceAgentProcesses(ctx context.Context, workspaceAgentID uuid.UUID) (database.WorkspaceAgentProcess, error) {
+	start := time.Now()
+	processes, err := m.s.GetWorkspaceAgentProcesses(ctx, workspaceAgentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentProcesses").Observe(time.Since(start).Seconds())
+	return processes, err
+}
+
+func (m metricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	scripts, err := m.s.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptsByAgentIDs").Observe(time.Since(start).Seconds())
+	return scripts, err
+}
+
 func (m metricsStore) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
 	start := time.Now()
 	logs, err := m.s.GetWorkspaceAgentStartupLogsAfter(ctx, arg)
@@ -1013,6 +1295,13 @@ func (m metricsStore) GetWorkspacesEligibleForTransition(ctx context.Context, no
 	return workspaces, err
 }
 
+func (m metricsStore) GetWorkspacesMarkedForDeletion(ctx context.Context) ([]database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacesMarkedForDeletion(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspacesMarkedForDeletion").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertAPIKey(ctx, arg)
@@ -1034,6 +1323,13 @@ func (m metricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAud
 	return log, err
 }
 
+func (m metricsStore) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertCustomRole").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertDERPMeshKey(ctx context.Context, value string) error {
 	start := time.Now()
 	err := m.s.InsertDERPMeshKey(ctx, value)
@@ -1090,6 +1386,34 @@ func (m metricsStore) InsertLicense(ctx context.Context, arg database.InsertLice
 	return license, err
 }
 
+func (m metricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderApp").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1132,6 +1456,20 @@ func (m metricsStore) InsertReplica(ctx context.Context, arg database.InsertRepl
 	return replica, err
 }
 
+func (m metricsStore) InsertSessionRecording(ctx context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	start := time.Now()
+	recording, err := m.s.InsertSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSessionRecording").Observe(time.Since(start).Seconds())
+	return recording, err
+}
+
+func (m metricsStore) InsertSessionRecordingChunk(ctx context.Context, arg database.InsertSessionRecordingChunkParams) error {
+	start := time.Now()
+	err := m.s.InsertSessionRecordingChunk(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSessionRecordingChunk").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.InsertTemplate(ctx, arg)
@@ -1181,6 +1519,13 @@ func (m metricsStore) InsertUserLink(ctx context.Context, arg database.InsertUse
 	return link, err
 }
 
+func (m metricsStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhook").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
@@ -1195,6 +1540,13 @@ func (m metricsStore) InsertWorkspaceAgent(ctx context.Context, arg database.Ins
 	return agent, err
 }
 
+func (m metricsStore) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	start := time.Now()
+	logSources, err := m.s.InsertWorkspaceAgentLogSources(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogSources").Observe(time.Since(start).Seconds())
+	return logSources, err
+}
+
 func (m metricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceAgentMetadata(ctx, arg)
@@ -1202,6 +1554,13 @@ func (m metricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	scripts, err := m.s.InsertWorkspaceAgentScripts(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentScripts").Observe(time.Since(start).Seconds())
+	return scripts, err
+}
+
 func (m metricsStore) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	start := time.Now()
 	logs, err := m.s.InsertWorkspaceAgentStartupLogs(ctx, arg)
@@ -1258,6 +1617,20 @@ func (m metricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg d
 	return metadata, err
 }
 
+func (m metricsStore) MarkNotificationMessageFailed(ctx context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	start := time.Now()
+	r0 := m.s.MarkNotificationMessageFailed(ctx, arg)
+	m.queryLatencies.WithLabelValues("MarkNotificationMessageFailed").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) MarkNotificationMessageSent(ctx context.Context, arg database.MarkNotificationMessageSentParams) error {
+	start := time.Now()
+	r0 := m.s.MarkNotificationMessageSent(ctx, arg)
+	m.queryLatencies.WithLabelValues("MarkNotificationMessageSent").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.RegisterWorkspaceProxy(ctx, arg)
@@ -1265,6 +1638,20 @@ func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.R
 	return proxy, err
 }
 
+func (m metricsStore) RemoveRoleFromOrganizationMembers(ctx context.Context, arg database.RemoveRoleFromOrganizationMembersParams) error {
+	start := time.Now()
+	r0 := m.s.RemoveRoleFromOrganizationMembers(ctx, arg)
+	m.queryLatencies.WithLabelValues("RemoveRoleFromOrganizationMembers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) RemoveRoleFromUsers(ctx context.Context, roleName string) error {
+	start := time.Now()
+	r0 := m.s.RemoveRoleFromUsers(ctx, roleName)
+	m.queryLatencies.WithLabelValues("RemoveRoleFromUsers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
 	start := time.Now()
 	ok, err := m.s.TryAcquireLock(ctx, pgTryAdvisoryXactLock)
@@ -1279,6 +1666,13 @@ func (m metricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateA
 	return err
 }
 
+func (m metricsStore) UpdateCustomRoleByID(ctx context.Context, arg database.UpdateCustomRoleByIDParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateCustomRoleByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCustomRoleByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateGitAuthLink(ctx, arg)
@@ -1307,6 +1701,21 @@ func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.Update
 	return member, err
 }
 
+func (m metricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateOAuth2ProviderAppSecretLastUsedAtByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams) error {
+
+	start := time.Now()
+	r0 := m.s.UpdateOAuth2ProviderAppSecretLastUsedAtByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppSecretLastUsedAtByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
@@ -1335,6 +1744,13 @@ func (m metricsStore) UpdateReplica(ctx context.Context, arg database.UpdateRepl
 	return replica, err
 }
 
+func (m metricsStore) UpdateSessionRecordingByID(ctx context.Context, arg database.UpdateSessionRecordingByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateSessionRecordingByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.UpdateTemplateACLByID(ctx, arg)
@@ -1447,6 +1863,21 @@ func (m metricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateU
 	return user, err
 }
 
+func (m metricsStore) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWebhookByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+
+	start := time.Now()
+	r0 := m.s.UpdateWebhookDeliveryByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
@@ -1475,6 +1906,13 @@ func (m metricsStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceAgentScriptStatus(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusParams) (database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	script, err := m.s.UpdateWorkspaceAgentScriptStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentScriptStatus").Observe(time.Since(start).Seconds())
+	return script, err
+}
+
 func (m metricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
@@ -1587,6 +2025,13 @@ func (m metricsStore) UpsertLogoURL(ctx context.Context, value string) error {
 	return r0
 }
 
+func (m metricsStore) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertNotificationPreference(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNotificationPreference").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpsertServiceBanner(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertServiceBanner(ctx, value)
@@ -1611,3 +2056,10 @@ func (m metricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID
 	defer m.queryLatencies.WithLabelValues("UpsertTailnetCoordinator").Observe(time.Since(start).Seconds())
 	return m.s.UpsertTailnetCoordinator(ctx, id)
 }
+
+func (m metricsStore) UpsertWorkspaceAgentProcesses(ctx context.Context, arg database.UpsertWorkspaceAgentProcessesParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceAgentProcesses(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentProcesses").Observe(time.Since(start).Seconds())
+	return err
+}
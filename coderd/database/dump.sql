@@ -46,11 +46,24 @@ CREATE TYPE login_type AS ENUM (
     'github',
     'oidc',
     'token',
-    'none'
+    'none',
+    'oauth2_provider_app'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
 
+CREATE TYPE notification_message_status AS ENUM (
+    'pending',
+    'leased',
+    'sent',
+    'failed'
+);
+
+CREATE TYPE notification_method AS ENUM (
+    'email',
+    'webhook'
+);
+
 CREATE TYPE parameter_destination_scheme AS ENUM (
     'none',
     'environment_variable',
@@ -99,7 +112,15 @@ CREATE TYPE resource_type AS ENUM (
     'group',
     'workspace_build',
     'license',
-    'workspace_proxy'
+    'workspace_proxy',
+    'webhook',
+    'oauth2_provider_app',
+    'oauth2_provider_app_secret'
+);
+
+CREATE TYPE session_recording_type AS ENUM (
+    'ssh',
+    'reconnecting_pty'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -112,6 +133,21 @@ CREATE TYPE user_status AS ENUM (
     'suspended'
 );
 
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'pending',
+    'leased',
+    'succeeded',
+    'failed'
+);
+
+CREATE TYPE webhook_event AS ENUM (
+    'workspace_build_started',
+    'workspace_build_succeeded',
+    'workspace_build_failed',
+    'template_version_pushed',
+    'user_created'
+);
+
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'created',
     'starting',
@@ -124,6 +160,14 @@ CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'off'
 );
 
+CREATE TYPE workspace_agent_script_status AS ENUM (
+    'pending',
+    'running',
+    'succeeded',
+    'failed',
+    'timed_out'
+);
+
 CREATE TYPE workspace_agent_subsystem AS ENUM (
     'envbuilder',
     'envbox',
@@ -245,6 +289,28 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE custom_roles (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    organization_id uuid,
+    site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    org_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    user_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by admins, in addition to the built-in roles.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'The organization of an organization role, or null for a site role. Organization roles are assigned to members as "name:organization_id".';
+
+COMMENT ON COLUMN custom_roles.site_permissions IS 'The site wide permissions of the role, only site roles have them.';
+
+COMMENT ON COLUMN custom_roles.org_permissions IS 'The permissions of the role in its organization, only organization roles have them.';
+
+COMMENT ON COLUMN custom_roles.user_permissions IS 'The permissions of the role on resources owned by the user, only site roles have them.';
+
 CREATE TABLE files (
     hash character varying(64) NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -305,6 +371,92 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE notification_messages (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    method notification_method NOT NULL,
+    labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    status notification_message_status DEFAULT 'pending'::notification_message_status NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text,
+    dedupe_hash text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    send_after timestamp with time zone NOT NULL,
+    leased_until timestamp with time zone
+);
+
+COMMENT ON COLUMN notification_messages.labels IS 'The values the template of the notification is rendered with.';
+
+COMMENT ON COLUMN notification_messages.dedupe_hash IS 'Hash of the recipient, template, method and labels, so the same notification is only sent once.';
+
+COMMENT ON COLUMN notification_messages.leased_until IS 'Set while a coderd replica sends the message, messages whose lease expired are sent again.';
+
+CREATE TABLE notification_preferences (
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE oauth2_provider_app_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    redirect_uri text NOT NULL,
+    scope api_key_scope NOT NULL,
+    code_challenge text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes, which are exchanged once for an access and a refresh token.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect URI given in the authorization request, the token request must give the same URI. Empty if the request did not give one.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE code challenge, or empty if the app did not use PKCE.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone,
+    hashed_secret bytea NOT NULL,
+    display_secret text NOT NULL,
+    app_id uuid NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.hashed_secret IS 'A SHA256 hash of the client secret, the secret itself is only shown when it is created.';
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail of the client secret, so admins can tell secrets apart.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hashed_refresh_token bytea NOT NULL,
+    app_id uuid NOT NULL,
+    api_key_id text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'The API keys issued to apps, with the refresh tokens that rotate them.';
+
+COMMENT ON COLUMN oauth2_provider_app_tokens.expires_at IS 'When the refresh token expires, the API key expires independently.';
+
+CREATE TABLE oauth2_provider_apps (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name character varying(64) NOT NULL,
+    icon character varying(256) NOT NULL,
+    callback_url text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Apps registered by admins to authenticate users against Coder, which acts as their OAuth2 provider.';
+
+COMMENT ON COLUMN oauth2_provider_apps.callback_url IS 'Users are redirected to the callback URL, or to a URL under it, after they authorized the app.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -417,6 +569,30 @@ CREATE TABLE replicas (
     error text DEFAULT ''::text NOT NULL
 );
 
+CREATE TABLE session_recording_chunks (
+    recording_id uuid NOT NULL,
+    sequence integer NOT NULL,
+    data bytea NOT NULL
+);
+
+COMMENT ON TABLE session_recording_chunks IS 'Chunks of asciicast v2 recordings, concatenated in sequence order they form the recording.';
+
+CREATE TABLE session_recordings (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    user_id uuid,
+    type session_recording_type NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    size bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON COLUMN session_recordings.user_id IS 'The user that connected to the session, null if the agent could not attribute the connection.';
+
+COMMENT ON COLUMN session_recordings.ended_at IS 'Set when the agent uploads the final chunk of the recording.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value character varying(8192) NOT NULL
@@ -556,7 +732,8 @@ CREATE TABLE templates (
     allow_user_autostop boolean DEFAULT true NOT NULL,
     failure_ttl bigint DEFAULT 0 NOT NULL,
     inactivity_ttl bigint DEFAULT 0 NOT NULL,
-    locked_ttl bigint DEFAULT 0 NOT NULL
+    locked_ttl bigint DEFAULT 0 NOT NULL,
+    idle_threshold bigint DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -569,6 +746,8 @@ COMMENT ON COLUMN templates.allow_user_autostart IS 'Allow users to specify an a
 
 COMMENT ON COLUMN templates.allow_user_autostop IS 'Allow users to specify custom autostop values for workspaces (enterprise).';
 
+COMMENT ON COLUMN templates.idle_threshold IS 'The duration without user activity reported by the agent after which workspaces are stopped. Zero disables idle detection.';
+
 CREATE TABLE user_links (
     user_id uuid NOT NULL,
     login_type login_type NOT NULL,
@@ -593,6 +772,49 @@ CREATE TABLE users (
     last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL
 );
 
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    event webhook_event NOT NULL,
+    payload jsonb NOT NULL,
+    status webhook_delivery_status DEFAULT 'pending'::webhook_delivery_status NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    status_code integer,
+    last_error text,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    send_after timestamp with time zone NOT NULL,
+    leased_until timestamp with time zone
+);
+
+COMMENT ON COLUMN webhook_deliveries.payload IS 'The data of the event, which is sent in the data field of the request body.';
+
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'The HTTP status code the endpoint responded with on the last attempt.';
+
+COMMENT ON COLUMN webhook_deliveries.leased_until IS 'Set while a coderd replica sends the delivery, deliveries whose lease expired are sent again.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    endpoint text NOT NULL,
+    secret text NOT NULL,
+    events webhook_event[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN webhooks.secret IS 'The key deliveries are signed with using HMAC-SHA256.';
+
+COMMENT ON COLUMN webhooks.events IS 'The events that are delivered to the endpoint.';
+
+CREATE TABLE workspace_agent_log_sources (
+    workspace_agent_id uuid NOT NULL,
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    display_name character varying(127) NOT NULL,
+    icon text NOT NULL
+);
+
 CREATE UNLOGGED TABLE workspace_agent_metadata (
     workspace_agent_id uuid NOT NULL,
     display_name character varying(127) NOT NULL,
@@ -602,15 +824,48 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
     error character varying(65535) DEFAULT ''::character varying NOT NULL,
     timeout bigint NOT NULL,
     "interval" bigint NOT NULL,
-    collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
+    collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
+    collector character varying(127) DEFAULT ''::character varying NOT NULL
+);
+
+COMMENT ON COLUMN workspace_agent_metadata.collector IS 'The name of a built-in collector evaluated by the agent instead of running script. Empty means the script is run.';
+
+CREATE UNLOGGED TABLE workspace_agent_processes (
+    workspace_agent_id uuid NOT NULL,
+    collected_at timestamp with time zone NOT NULL,
+    processes jsonb NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_processes IS 'The latest snapshot of the resource usage of processes reported by each workspace agent.';
+
+CREATE TABLE workspace_agent_scripts (
+    workspace_agent_id uuid NOT NULL,
+    log_source_id uuid NOT NULL,
+    log_path text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    script text NOT NULL,
+    cron text NOT NULL,
+    start_blocks_login boolean NOT NULL,
+    run_on_start boolean NOT NULL,
+    run_on_stop boolean NOT NULL,
+    timeout_seconds integer NOT NULL,
+    status workspace_agent_script_status DEFAULT 'pending'::workspace_agent_script_status NOT NULL,
+    exit_code integer DEFAULT 0 NOT NULL,
+    started_at timestamp with time zone,
+    ended_at timestamp with time zone
 );
 
+COMMENT ON COLUMN workspace_agent_scripts.status IS 'The status of the most recent execution of the script, reported by the agent.';
+
+COMMENT ON COLUMN workspace_agent_scripts.exit_code IS 'The exit code of the most recent execution of the script, only meaningful when status is succeeded or failed.';
+
 CREATE TABLE workspace_agent_startup_logs (
     agent_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
     output character varying(1024) NOT NULL,
     id bigint NOT NULL,
-    level log_level DEFAULT 'info'::log_level NOT NULL
+    level log_level DEFAULT 'info'::log_level NOT NULL,
+    log_source_id uuid DEFAULT '00000000-0000-0000-0000-000000000000'::uuid NOT NULL
 );
 
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
@@ -839,6 +1094,9 @@ ALTER TABLE ONLY audit_logs
 ALTER TABLE ONLY files
     ADD CONSTRAINT files_hash_created_by_key UNIQUE (hash, created_by);
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY files
     ADD CONSTRAINT files_pkey PRIMARY KEY (id);
 
@@ -863,6 +1121,39 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_dedupe_hash_key UNIQUE (dedupe_hash);
+
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, template);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_hashed_secret_key UNIQUE (hashed_secret);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_hashed_secret_key UNIQUE (hashed_secret);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_hashed_refresh_token_key UNIQUE (hashed_refresh_token);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -893,6 +1184,12 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY session_recording_chunks
+    ADD CONSTRAINT session_recording_chunks_pkey PRIMARY KEY (recording_id, sequence);
+
+ALTER TABLE ONLY session_recordings
+    ADD CONSTRAINT session_recordings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -926,9 +1223,27 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_name_key UNIQUE (name);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_log_sources
+    ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_processes
+    ADD CONSTRAINT workspace_agent_processes_pkey PRIMARY KEY (workspace_agent_id);
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_pkey PRIMARY KEY (workspace_agent_id, log_source_id);
+
 ALTER TABLE ONLY workspace_agent_startup_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -984,6 +1299,10 @@ CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
+CREATE UNIQUE INDEX idx_custom_roles_name_organization_id ON custom_roles USING btree (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+CREATE INDEX idx_notification_messages_status ON notification_messages USING btree (status, send_after);
+
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
 
 CREATE INDEX idx_organization_member_user_id_uuid ON organization_members USING btree (user_id);
@@ -992,6 +1311,8 @@ CREATE UNIQUE INDEX idx_organization_name ON organizations USING btree (name);
 
 CREATE UNIQUE INDEX idx_organization_name_lower ON organizations USING btree (lower(name));
 
+CREATE INDEX idx_session_recordings_workspace_id ON session_recordings USING btree (workspace_id);
+
 CREATE INDEX idx_tailnet_agents_coordinator ON tailnet_agents USING btree (coordinator_id);
 
 CREATE INDEX idx_tailnet_clients_agent ON tailnet_clients USING btree (agent_id);
@@ -1002,6 +1323,10 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries USING btree (status, send_after);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
@@ -1037,6 +1362,9 @@ CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
@@ -1049,6 +1377,27 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -1064,6 +1413,21 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY session_recording_chunks
+    ADD CONSTRAINT session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES session_recordings(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY session_recordings
+    ADD CONSTRAINT session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY session_recordings
+    ADD CONSTRAINT session_recordings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY session_recordings
+    ADD CONSTRAINT session_recordings_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY session_recordings
+    ADD CONSTRAINT session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -1094,9 +1458,24 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_log_sources
+    ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_processes
+    ADD CONSTRAINT workspace_agent_processes_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_log_source_id_fkey FOREIGN KEY (workspace_agent_id, log_source_id) REFERENCES workspace_agent_log_sources(workspace_agent_id, id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_startup_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -9,6 +9,10 @@ const (
 	// Keep the unused iota here so we don't need + 1 every time
 	lockIDUnused = iota
 	LockIDDeploymentSetup
+	// LockIDRoleAssignments is held while roles are assigned to users and
+	// while custom roles are deleted, so a custom role can't be assigned
+	// while it's removed from the users it's assigned to.
+	LockIDRoleAssignments
 )
 
 // GenLockID generates a unique and consistent lock ID from a given string.
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_startup_logs DROP COLUMN log_source_id;
+
+DROP TABLE workspace_agent_scripts;
+
+DROP TYPE workspace_agent_script_status;
+
+DROP TABLE workspace_agent_log_sources;
+
+COMMIT;
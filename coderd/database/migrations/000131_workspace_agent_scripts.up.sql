@@ -0,0 +1,41 @@
+BEGIN;
+
+CREATE TABLE workspace_agent_log_sources (
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	id uuid NOT NULL,
+	created_at timestamptz NOT NULL,
+	display_name varchar(127) NOT NULL,
+	icon text NOT NULL,
+	PRIMARY KEY (workspace_agent_id, id)
+);
+
+CREATE TYPE workspace_agent_script_status AS ENUM ('pending', 'running', 'succeeded', 'failed', 'timed_out');
+
+CREATE TABLE workspace_agent_scripts (
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	log_source_id uuid NOT NULL,
+	log_path text NOT NULL,
+	created_at timestamptz NOT NULL,
+	script text NOT NULL,
+	cron text NOT NULL,
+	start_blocks_login boolean NOT NULL,
+	run_on_start boolean NOT NULL,
+	run_on_stop boolean NOT NULL,
+	timeout_seconds integer NOT NULL,
+	status workspace_agent_script_status NOT NULL DEFAULT 'pending',
+	exit_code integer NOT NULL DEFAULT 0,
+	started_at timestamptz,
+	ended_at timestamptz,
+	PRIMARY KEY (workspace_agent_id, log_source_id),
+	FOREIGN KEY (workspace_agent_id, log_source_id) REFERENCES workspace_agent_log_sources(workspace_agent_id, id) ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN workspace_agent_scripts.status IS 'The status of the most recent execution of the script, reported by the agent.';
+
+COMMENT ON COLUMN workspace_agent_scripts.exit_code IS 'The exit code of the most recent execution of the script, only meaningful when status is succeeded or failed.';
+
+-- Logs that predate log sources belong to the nil source, which is
+-- displayed as the legacy startup script.
+ALTER TABLE workspace_agent_startup_logs ADD COLUMN log_source_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE session_recording_chunks;
+
+DROP TABLE session_recordings;
+
+DROP TYPE session_recording_type;
+
+COMMIT;
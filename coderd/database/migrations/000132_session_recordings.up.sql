@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TYPE session_recording_type AS ENUM ('ssh', 'reconnecting_pty');
+
+CREATE TABLE session_recordings (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds(id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	type session_recording_type NOT NULL,
+	started_at timestamptz NOT NULL,
+	ended_at timestamptz,
+	size bigint NOT NULL DEFAULT 0
+);
+
+COMMENT ON COLUMN session_recordings.user_id IS 'The owner of the workspace at the time of the recording.';
+
+COMMENT ON COLUMN session_recordings.ended_at IS 'Set when the agent uploads the final chunk of the recording.';
+
+CREATE INDEX idx_session_recordings_workspace_id ON session_recordings (workspace_id);
+
+CREATE TABLE session_recording_chunks (
+	recording_id uuid NOT NULL REFERENCES session_recordings(id) ON DELETE CASCADE,
+	sequence integer NOT NULL,
+	data bytea NOT NULL,
+	PRIMARY KEY (recording_id, sequence)
+);
+
+COMMENT ON TABLE session_recording_chunks IS 'Chunks of asciicast v2 recordings, concatenated in sequence order they form the recording.';
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Process snapshots are replaced on every report and can be lost without
+-- harm, so the table is unlogged like workspace_agent_metadata.
+CREATE UNLOGGED TABLE workspace_agent_processes (
+	workspace_agent_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	collected_at timestamptz NOT NULL,
+	processes jsonb NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_processes IS 'The latest snapshot of the resource usage of processes reported by each workspace agent.';
+
+COMMIT;
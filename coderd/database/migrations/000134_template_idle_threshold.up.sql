@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN idle_threshold bigint DEFAULT 0 NOT NULL;
+
+COMMENT ON COLUMN templates.idle_threshold IS 'The duration without user activity reported by the agent after which workspaces are stopped. Zero disables idle detection.';
+
+COMMIT;
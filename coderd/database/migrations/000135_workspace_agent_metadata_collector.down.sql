@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_metadata DROP COLUMN collector;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_metadata ADD COLUMN collector character varying(127) DEFAULT '' NOT NULL;
+
+COMMENT ON COLUMN workspace_agent_metadata.collector IS 'The name of a built-in collector evaluated by the agent instead of running script. Empty means the script is run.';
+
+COMMIT;
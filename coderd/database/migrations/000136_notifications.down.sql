@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE notification_preferences;
+
+DROP TABLE notification_messages;
+
+DROP TYPE notification_message_status;
+
+DROP TYPE notification_method;
+
+COMMIT;
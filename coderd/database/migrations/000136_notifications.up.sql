@@ -0,0 +1,39 @@
+BEGIN;
+
+CREATE TYPE notification_method AS ENUM ('email', 'webhook');
+
+CREATE TYPE notification_message_status AS ENUM ('pending', 'leased', 'sent', 'failed');
+
+CREATE TABLE notification_messages (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	template text NOT NULL,
+	method notification_method NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}'::jsonb,
+	status notification_message_status NOT NULL DEFAULT 'pending'::notification_message_status,
+	attempts integer NOT NULL DEFAULT 0,
+	last_error text,
+	dedupe_hash text NOT NULL UNIQUE,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	send_after timestamptz NOT NULL,
+	leased_until timestamptz
+);
+
+COMMENT ON COLUMN notification_messages.labels IS 'The values the template of the notification is rendered with.';
+
+COMMENT ON COLUMN notification_messages.dedupe_hash IS 'Hash of the recipient, template, method and labels, so the same notification is only sent once.';
+
+COMMENT ON COLUMN notification_messages.leased_until IS 'Set while a coderd replica sends the message, messages whose lease expired are sent again.';
+
+CREATE INDEX idx_notification_messages_status ON notification_messages (status, send_after);
+
+CREATE TABLE notification_preferences (
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	template text NOT NULL,
+	disabled boolean NOT NULL DEFAULT false,
+	updated_at timestamptz NOT NULL,
+	PRIMARY KEY (user_id, template)
+);
+
+COMMIT;
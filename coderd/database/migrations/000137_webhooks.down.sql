@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE webhook_deliveries;
+
+DROP TABLE webhooks;
+
+DROP TYPE webhook_delivery_status;
+
+DROP TYPE webhook_event;
+
+COMMIT;
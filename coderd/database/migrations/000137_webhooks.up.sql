@@ -0,0 +1,52 @@
+BEGIN;
+
+CREATE TYPE webhook_event AS ENUM (
+	'workspace_build_started',
+	'workspace_build_succeeded',
+	'workspace_build_failed',
+	'template_version_pushed',
+	'user_created'
+);
+
+CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'leased', 'succeeded', 'failed');
+
+CREATE TABLE webhooks (
+	id uuid NOT NULL PRIMARY KEY,
+	name text NOT NULL UNIQUE,
+	endpoint text NOT NULL,
+	secret text NOT NULL,
+	events webhook_event[] NOT NULL,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+COMMENT ON COLUMN webhooks.secret IS 'The key deliveries are signed with using HMAC-SHA256.';
+
+COMMENT ON COLUMN webhooks.events IS 'The events that are delivered to the endpoint.';
+
+CREATE TABLE webhook_deliveries (
+	id uuid NOT NULL PRIMARY KEY,
+	webhook_id uuid NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+	event webhook_event NOT NULL,
+	payload jsonb NOT NULL,
+	status webhook_delivery_status NOT NULL DEFAULT 'pending'::webhook_delivery_status,
+	attempts integer NOT NULL DEFAULT 0,
+	status_code integer,
+	last_error text,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	send_after timestamptz NOT NULL,
+	leased_until timestamptz
+);
+
+COMMENT ON COLUMN webhook_deliveries.payload IS 'The data of the event, which is sent in the data field of the request body.';
+
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'The HTTP status code the endpoint responded with on the last attempt.';
+
+COMMENT ON COLUMN webhook_deliveries.leased_until IS 'Set while a coderd replica sends the delivery, deliveries whose lease expired are sent again.';
+
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries (status, send_after);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE custom_roles (
+	id uuid NOT NULL PRIMARY KEY,
+	name text NOT NULL,
+	display_name text NOT NULL,
+	organization_id uuid REFERENCES organizations(id) ON DELETE CASCADE,
+	site_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	org_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	user_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by admins, in addition to the built-in roles.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'The organization of an organization role, or null for a site role. Organization roles are assigned to members as "name:organization_id".';
+
+COMMENT ON COLUMN custom_roles.site_permissions IS 'The site wide permissions of the role, only site roles have them.';
+
+COMMENT ON COLUMN custom_roles.org_permissions IS 'The permissions of the role in its organization, only organization roles have them.';
+
+COMMENT ON COLUMN custom_roles.user_permissions IS 'The permissions of the role on resources owned by the user, only site roles have them.';
+
+CREATE UNIQUE INDEX idx_custom_roles_name_organization_id ON custom_roles (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+COMMIT;
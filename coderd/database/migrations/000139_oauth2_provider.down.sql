@@ -0,0 +1,15 @@
+BEGIN;
+
+-- It's not possible to drop enum values from enum types, so the API keys of
+-- apps are removed and the 'oauth2_provider_app' login type is left behind.
+DELETE FROM api_keys WHERE login_type = 'oauth2_provider_app';
+
+DROP TABLE oauth2_provider_app_tokens;
+
+DROP TABLE oauth2_provider_app_codes;
+
+DROP TABLE oauth2_provider_app_secrets;
+
+DROP TABLE oauth2_provider_apps;
+
+COMMIT;
@@ -0,0 +1,64 @@
+BEGIN;
+
+-- API keys issued to OAuth2 provider apps act on behalf of the user that
+-- authorized the app.
+ALTER TYPE login_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app';
+
+CREATE TABLE oauth2_provider_apps (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	name varchar(64) NOT NULL UNIQUE,
+	icon varchar(256) NOT NULL,
+	callback_url text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Apps registered by admins to authenticate users against Coder, which acts as their OAuth2 provider.';
+
+COMMENT ON COLUMN oauth2_provider_apps.callback_url IS 'Users are redirected to the callback URL, or to a URL under it, after they authorized the app.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	last_used_at timestamptz NULL DEFAULT NULL,
+	hashed_secret bytea NOT NULL UNIQUE,
+	display_secret text NOT NULL,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.hashed_secret IS 'A SHA256 hash of the client secret, the secret itself is only shown when it is created.';
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail of the client secret, so admins can tell secrets apart.';
+
+CREATE TABLE oauth2_provider_app_codes (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	expires_at timestamptz NOT NULL,
+	hashed_secret bytea NOT NULL UNIQUE,
+	user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE,
+	redirect_uri text NOT NULL,
+	scope api_key_scope NOT NULL,
+	code_challenge text NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes, which are exchanged once for an access and a refresh token.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect URI the code was issued for, the token request must give the same URI.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE code challenge, or empty if the app did not use PKCE.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	expires_at timestamptz NOT NULL,
+	hashed_refresh_token bytea NOT NULL UNIQUE,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE,
+	api_key_id text NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'The API keys issued to apps, with the refresh tokens that rotate them.';
+
+COMMENT ON COLUMN oauth2_provider_app_tokens.expires_at IS 'When the refresh token expires, the API key expires independently.';
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+DELETE FROM session_recordings WHERE user_id IS NULL;
+
+ALTER TABLE session_recordings DROP CONSTRAINT session_recordings_user_id_fkey;
+
+ALTER TABLE session_recordings ADD CONSTRAINT session_recordings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE session_recordings ALTER COLUMN user_id SET NOT NULL;
+
+COMMENT ON COLUMN session_recordings.user_id IS 'The owner of the workspace at the time of the recording.';
+
+COMMIT;
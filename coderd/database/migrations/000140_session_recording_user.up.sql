@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Recordings used to be attributed to the workspace owner. They are now
+-- attributed to the user that connected, which is unknown for agents that
+-- don't report it.
+ALTER TABLE session_recordings ALTER COLUMN user_id DROP NOT NULL;
+
+ALTER TABLE session_recordings DROP CONSTRAINT session_recordings_user_id_fkey;
+
+ALTER TABLE session_recordings ADD CONSTRAINT session_recordings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN session_recordings.user_id IS 'The user that connected to the session, null if the agent could not attribute the connection.';
+
+COMMIT;
@@ -0,0 +1,2 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app_secret';
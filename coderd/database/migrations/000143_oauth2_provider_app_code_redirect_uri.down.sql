@@ -0,0 +1 @@
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect URI the code was issued for, the token request must give the same URI.';
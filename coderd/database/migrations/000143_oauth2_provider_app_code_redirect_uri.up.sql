@@ -0,0 +1 @@
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect URI given in the authorization request, the token request must give the same URI. Empty if the request did not give one.';
@@ -0,0 +1,43 @@
+INSERT INTO
+	workspace_agent_log_sources (
+		workspace_agent_id,
+		id,
+		created_at,
+		display_name,
+		icon
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'0ff953d5-0ba9-4d2a-9ac3-b8bd7e3e2d4e',
+		'2023-06-29 10:00:00+00',
+		'Dotfiles',
+		'/icon/dotfiles.svg'
+	);
+
+INSERT INTO
+	workspace_agent_scripts (
+		workspace_agent_id,
+		log_source_id,
+		log_path,
+		created_at,
+		script,
+		cron,
+		start_blocks_login,
+		run_on_start,
+		run_on_stop,
+		timeout_seconds
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'0ff953d5-0ba9-4d2a-9ac3-b8bd7e3e2d4e',
+		'',
+		'2023-06-29 10:00:00+00',
+		'coder dotfiles -y',
+		'',
+		true,
+		true,
+		false,
+		60
+	);
@@ -0,0 +1,35 @@
+INSERT INTO
+	session_recordings (
+		id,
+		workspace_id,
+		workspace_build_id,
+		agent_id,
+		user_id,
+		type,
+		started_at,
+		ended_at,
+		size
+	)
+VALUES
+	(
+		'c9b1f0a8-3e5d-4d2b-9f31-6a2f0e8b7d41',
+		'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+		'a8c0b8c5-c9a8-4f33-93a4-8142e6858244',
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'ssh',
+		'2023-07-10 10:00:00+00',
+		'2023-07-10 10:05:00+00',
+		84
+	);
+
+INSERT INTO
+	session_recording_chunks (recording_id, sequence, data)
+VALUES
+	(
+		'c9b1f0a8-3e5d-4d2b-9f31-6a2f0e8b7d41',
+		0,
+		'{"version":2,"width":80,"height":24,"timestamp":1688983200}
+[0.1,"o","hello\r\n"]
+'
+	);
@@ -0,0 +1,12 @@
+INSERT INTO
+	workspace_agent_processes (
+		workspace_agent_id,
+		collected_at,
+		processes
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'2023-07-10 10:00:00+00',
+		'{"process_count":1,"sessions":[],"processes":[{"pid":1,"ppid":0,"name":"init"}]}'
+	);
@@ -0,0 +1,38 @@
+INSERT INTO
+	notification_messages (
+		id,
+		user_id,
+		template,
+		method,
+		labels,
+		status,
+		attempts,
+		dedupe_hash,
+		created_at,
+		updated_at,
+		send_after
+	)
+VALUES
+	(
+		'5d6f0a4e-8c1b-4a51-9b7e-2f3c8d9a1e60',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'workspace_autostopped',
+		'email',
+		'{"workspace":"dev","template":"docker"}',
+		'sent',
+		1,
+		'0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0',
+		'2023-07-10 10:00:00+00',
+		'2023-07-10 10:00:05+00',
+		'2023-07-10 10:00:00+00'
+	);
+
+INSERT INTO
+	notification_preferences (user_id, template, disabled, updated_at)
+VALUES
+	(
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'workspace_build_failed',
+		true,
+		'2023-07-10 10:00:00+00'
+	);
@@ -0,0 +1,39 @@
+INSERT INTO
+	webhooks (id, name, endpoint, secret, events, created_at, updated_at)
+VALUES
+	(
+		'8f0c5c1e-2b4a-4d7e-9f3a-6c1d2e3f4a5b',
+		'builds',
+		'https://hooks.example.com/coder',
+		'supersecret',
+		'{workspace_build_succeeded,workspace_build_failed}',
+		'2023-07-10 10:00:00+00',
+		'2023-07-10 10:00:00+00'
+	);
+
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
+		status,
+		attempts,
+		status_code,
+		created_at,
+		updated_at,
+		send_after
+	)
+VALUES
+	(
+		'2a7e9d4c-6b3f-4e1a-8c5d-0f9e8d7c6b5a',
+		'8f0c5c1e-2b4a-4d7e-9f3a-6c1d2e3f4a5b',
+		'workspace_build_succeeded',
+		'{"workspace_name":"dev"}',
+		'succeeded',
+		1,
+		204,
+		'2023-07-10 10:00:00+00',
+		'2023-07-10 10:00:01+00',
+		'2023-07-10 10:00:00+00'
+	);
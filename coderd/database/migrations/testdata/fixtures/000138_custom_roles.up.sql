@@ -0,0 +1,35 @@
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	(
+		'5b4f7c2e-8d1a-4f3b-9e6c-2a7d8e9f0a1b',
+		'auditor-lite',
+		'Auditor Lite',
+		NULL,
+		'[{"negate":false,"resource_type":"audit_log","action":"read"}]',
+		'[]',
+		'[]',
+		'2023-07-17 10:00:00+00',
+		'2023-07-17 10:00:00+00'
+	),
+	(
+		'9c3e1d5f-2a4b-4c6d-8e0f-1a2b3c4d5e6f',
+		'restarter',
+		'Restarter',
+		'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+		'[]',
+		'[{"negate":false,"resource_type":"workspace","action":"update"}]',
+		'[]',
+		'2023-07-17 10:00:00+00',
+		'2023-07-17 10:00:00+00'
+	);
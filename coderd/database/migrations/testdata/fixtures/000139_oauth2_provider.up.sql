@@ -0,0 +1,30 @@
+INSERT INTO
+	oauth2_provider_apps (id, created_at, updated_at, name, icon, callback_url)
+VALUES
+	(
+		'b0a5c3d1-7e2f-4a6b-9c8d-1e2f3a4b5c6d',
+		'2023-07-10 10:00:00+00',
+		'2023-07-10 10:00:00+00',
+		'grafana',
+		'/icon/grafana.svg',
+		'https://grafana.example.com/login/generic_oauth'
+	);
+
+INSERT INTO
+	oauth2_provider_app_secrets (
+		id,
+		created_at,
+		last_used_at,
+		hashed_secret,
+		display_secret,
+		app_id
+	)
+VALUES
+	(
+		'c1b6d4e2-8f3a-4b7c-8d9e-2f3a4b5c6d7e',
+		'2023-07-10 10:00:00+00',
+		NULL,
+		'\xdeadbeef',
+		'abcdef',
+		'b0a5c3d1-7e2f-4a6b-9c8d-1e2f3a4b5c6d'
+	);
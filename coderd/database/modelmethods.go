@@ -225,6 +225,41 @@ func (u UserLink) RBACObject() rbac.Object {
 	return rbac.ResourceUserData.WithOwner(u.UserID.String()).WithID(u.UserID)
 }
 
+func (r CustomRole) RBACObject() rbac.Object {
+	obj := rbac.ResourceCustomRole.WithID(r.ID)
+	if r.OrganizationID.Valid {
+		obj = obj.InOrg(r.OrganizationID.UUID)
+	}
+	return obj
+}
+
+// RoleName returns the name the role is assigned with, which is scoped to its
+// organization for organization roles.
+func (r CustomRole) RoleName() string {
+	if !r.OrganizationID.Valid {
+		return r.Name
+	}
+	return r.Name + ":" + r.OrganizationID.UUID.String()
+}
+
+func (w Webhook) RBACObject() rbac.Object {
+	return rbac.ResourceWebhook.WithID(w.ID)
+}
+
+func (a OAuth2ProviderApp) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderApp.WithID(a.ID)
+}
+
+func (s OAuth2ProviderAppSecret) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppSecret.WithID(s.ID)
+}
+
+// RBACObject returns the object of the API keys the code is exchanged for, so
+// codes are authorized like the API keys of the user.
+func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
+	return rbac.ResourceAPIKey.WithOwner(c.UserID.String())
+}
+
 func (l License) RBACObject() rbac.Object {
 	return rbac.ResourceLicense.WithIDString(strconv.FormatInt(int64(l.ID), 10))
 }
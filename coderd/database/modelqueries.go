@@ -83,6 +83,7 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.FailureTTL,
 			&i.InactivityTTL,
 			&i.LockedTTL,
+			&i.IdleThreshold,
 		); err != nil {
 			return nil, xerrors.Errorf("scan: %w", err)
 		}
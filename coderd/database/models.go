@@ -401,11 +401,12 @@ func AllLogSourceValues() []LogSource {
 type LoginType string
 
 const (
-	LoginTypePassword LoginType = "password"
-	LoginTypeGithub   LoginType = "github"
-	LoginTypeOIDC     LoginType = "oidc"
-	LoginTypeToken    LoginType = "token"
-	LoginTypeNone     LoginType = "none"
+	LoginTypePassword          LoginType = "password"
+	LoginTypeGithub            LoginType = "github"
+	LoginTypeOIDC              LoginType = "oidc"
+	LoginTypeToken             LoginType = "token"
+	LoginTypeNone              LoginType = "none"
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -449,7 +450,8 @@ func (e LoginType) Valid() bool {
 		LoginTypeGithub,
 		LoginTypeOIDC,
 		LoginTypeToken,
-		LoginTypeNone:
+		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp:
 		return true
 	}
 	return false
@@ -462,6 +464,129 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+	}
+}
+
+type NotificationMessageStatus string
+
+const (
+	NotificationMessageStatusPending NotificationMessageStatus = "pending"
+	NotificationMessageStatusLeased  NotificationMessageStatus = "leased"
+	NotificationMessageStatusSent    NotificationMessageStatus = "sent"
+	NotificationMessageStatusFailed  NotificationMessageStatus = "failed"
+)
+
+func (e *NotificationMessageStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMessageStatus(s)
+	case string:
+		*e = NotificationMessageStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMessageStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMessageStatus struct {
+	NotificationMessageStatus NotificationMessageStatus
+	Valid                     bool // Valid is true if NotificationMessageStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMessageStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMessageStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMessageStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMessageStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMessageStatus), nil
+}
+
+func (e NotificationMessageStatus) Valid() bool {
+	switch e {
+	case NotificationMessageStatusPending,
+		NotificationMessageStatusLeased,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMessageStatusValues() []NotificationMessageStatus {
+	return []NotificationMessageStatus{
+		NotificationMessageStatusPending,
+		NotificationMessageStatusLeased,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusFailed,
+	}
+}
+
+type NotificationMethod string
+
+const (
+	NotificationMethodEmail   NotificationMethod = "email"
+	NotificationMethodWebhook NotificationMethod = "webhook"
+)
+
+func (e *NotificationMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMethod(s)
+	case string:
+		*e = NotificationMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMethod: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMethod struct {
+	NotificationMethod NotificationMethod
+	Valid              bool // Valid is true if NotificationMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMethod), nil
+}
+
+func (e NotificationMethod) Valid() bool {
+	switch e {
+	case NotificationMethodEmail,
+		NotificationMethodWebhook:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMethodValues() []NotificationMethod {
+	return []NotificationMethod{
+		NotificationMethodEmail,
+		NotificationMethodWebhook,
 	}
 }
 
@@ -880,17 +1005,20 @@ func AllProvisionerTypeValues() []ProvisionerType {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeGitSshKey       ResourceType = "git_ssh_key"
-	ResourceTypeApiKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
+	ResourceTypeOrganization            ResourceType = "organization"
+	ResourceTypeTemplate                ResourceType = "template"
+	ResourceTypeTemplateVersion         ResourceType = "template_version"
+	ResourceTypeUser                    ResourceType = "user"
+	ResourceTypeWorkspace               ResourceType = "workspace"
+	ResourceTypeGitSshKey               ResourceType = "git_ssh_key"
+	ResourceTypeApiKey                  ResourceType = "api_key"
+	ResourceTypeGroup                   ResourceType = "group"
+	ResourceTypeWorkspaceBuild          ResourceType = "workspace_build"
+	ResourceTypeLicense                 ResourceType = "license"
+	ResourceTypeWorkspaceProxy          ResourceType = "workspace_proxy"
+	ResourceTypeWebhook                 ResourceType = "webhook"
+	ResourceTypeOauth2ProviderApp       ResourceType = "oauth2_provider_app"
+	ResourceTypeOauth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -940,7 +1068,10 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeGroup,
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
-		ResourceTypeWorkspaceProxy:
+		ResourceTypeWorkspaceProxy,
+		ResourceTypeWebhook,
+		ResourceTypeOauth2ProviderApp,
+		ResourceTypeOauth2ProviderAppSecret:
 		return true
 	}
 	return false
@@ -959,6 +1090,67 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
+		ResourceTypeWebhook,
+		ResourceTypeOauth2ProviderApp,
+		ResourceTypeOauth2ProviderAppSecret,
+	}
+}
+
+type SessionRecordingType string
+
+const (
+	SessionRecordingTypeSSH             SessionRecordingType = "ssh"
+	SessionRecordingTypeReconnectingPTY SessionRecordingType = "reconnecting_pty"
+)
+
+func (e *SessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SessionRecordingType(s)
+	case string:
+		*e = SessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullSessionRecordingType struct {
+	SessionRecordingType SessionRecordingType
+	Valid                bool // Valid is true if SessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.SessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SessionRecordingType), nil
+}
+
+func (e SessionRecordingType) Valid() bool {
+	switch e {
+	case SessionRecordingTypeSSH,
+		SessionRecordingTypeReconnectingPTY:
+		return true
+	}
+	return false
+}
+
+func AllSessionRecordingTypeValues() []SessionRecordingType {
+	return []SessionRecordingType{
+		SessionRecordingTypeSSH,
+		SessionRecordingTypeReconnectingPTY,
 	}
 }
 
@@ -1078,6 +1270,137 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusLeased    WebhookDeliveryStatus = "leased"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus
+	Valid                 bool // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusLeased,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWebhookDeliveryStatusValues() []WebhookDeliveryStatus {
+	return []WebhookDeliveryStatus{
+		WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusLeased,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusFailed,
+	}
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted   WebhookEvent = "workspace_build_started"
+	WebhookEventWorkspaceBuildSucceeded WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build_failed"
+	WebhookEventTemplateVersionPushed   WebhookEvent = "template_version_pushed"
+	WebhookEventUserCreated             WebhookEvent = "user_created"
+)
+
+func (e *WebhookEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookEvent(s)
+	case string:
+		*e = WebhookEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookEvent: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookEvent struct {
+	WebhookEvent WebhookEvent
+	Valid        bool // Valid is true if WebhookEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookEvent), nil
+}
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventWorkspaceBuildStarted,
+		WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventTemplateVersionPushed,
+		WebhookEventUserCreated:
+		return true
+	}
+	return false
+}
+
+func AllWebhookEventValues() []WebhookEvent {
+	return []WebhookEvent{
+		WebhookEventWorkspaceBuildStarted,
+		WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventTemplateVersionPushed,
+		WebhookEventUserCreated,
+	}
+}
+
 type WorkspaceAgentLifecycleState string
 
 const (
@@ -1157,6 +1480,73 @@ func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
 	}
 }
 
+type WorkspaceAgentScriptStatus string
+
+const (
+	WorkspaceAgentScriptStatusPending   WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning   WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusSucceeded WorkspaceAgentScriptStatus = "succeeded"
+	WorkspaceAgentScriptStatusFailed    WorkspaceAgentScriptStatus = "failed"
+	WorkspaceAgentScriptStatusTimedOut  WorkspaceAgentScriptStatus = "timed_out"
+)
+
+func (e *WorkspaceAgentScriptStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptStatus(s)
+	case string:
+		*e = WorkspaceAgentScriptStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptStatus struct {
+	WorkspaceAgentScriptStatus WorkspaceAgentScriptStatus
+	Valid                      bool // Valid is true if WorkspaceAgentScriptStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptStatus), nil
+}
+
+func (e WorkspaceAgentScriptStatus) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusSucceeded,
+		WorkspaceAgentScriptStatusFailed,
+		WorkspaceAgentScriptStatusTimedOut:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptStatusValues() []WorkspaceAgentScriptStatus {
+	return []WorkspaceAgentScriptStatus{
+		WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusSucceeded,
+		WorkspaceAgentScriptStatusFailed,
+		WorkspaceAgentScriptStatusTimedOut,
+	}
+}
+
 type WorkspaceAgentSubsystem string
 
 const (
@@ -1377,6 +1767,23 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Roles defined by admins, in addition to the built-in roles.
+type CustomRole struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	// The organization of an organization role, or null for a site role. Organization roles are assigned to members as "name:organization_id".
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	// The site wide permissions of the role, only site roles have them.
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	// The permissions of the role in its organization, only organization roles have them.
+	OrgPermissions CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	// The permissions of the role on resources owned by the user, only site roles have them.
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+}
+
 type File struct {
 	Hash      string    `db:"hash" json:"hash"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
@@ -1426,6 +1833,80 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+type NotificationMessage struct {
+	ID       uuid.UUID          `db:"id" json:"id"`
+	UserID   uuid.UUID          `db:"user_id" json:"user_id"`
+	Template string             `db:"template" json:"template"`
+	Method   NotificationMethod `db:"method" json:"method"`
+	// The values the template of the notification is rendered with.
+	Labels    StringMap                 `db:"labels" json:"labels"`
+	Status    NotificationMessageStatus `db:"status" json:"status"`
+	Attempts  int32                     `db:"attempts" json:"attempts"`
+	LastError sql.NullString            `db:"last_error" json:"last_error"`
+	// Hash of the recipient, template, method and labels, so the same notification is only sent once.
+	DedupeHash string    `db:"dedupe_hash" json:"dedupe_hash"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	SendAfter  time.Time `db:"send_after" json:"send_after"`
+	// Set while a coderd replica sends the message, messages whose lease expired are sent again.
+	LeasedUntil sql.NullTime `db:"leased_until" json:"leased_until"`
+}
+
+type NotificationPreference struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Apps registered by admins to authenticate users against Coder, which acts as their OAuth2 provider.
+type OAuth2ProviderApp struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+	Icon      string    `db:"icon" json:"icon"`
+	// Users are redirected to the callback URL, or to a URL under it, after they authorized the app.
+	CallbackURL string `db:"callback_url" json:"callback_url"`
+}
+
+// Authorization codes, which are exchanged once for an access and a refresh token.
+type OAuth2ProviderAppCode struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	// The redirect URI given in the authorization request, the token request must give the same URI. Empty if the request did not give one.
+	RedirectURI string      `db:"redirect_uri" json:"redirect_uri"`
+	Scope       APIKeyScope `db:"scope" json:"scope"`
+	// The S256 PKCE code challenge, or empty if the app did not use PKCE.
+	CodeChallenge string `db:"code_challenge" json:"code_challenge"`
+}
+
+type OAuth2ProviderAppSecret struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	// A SHA256 hash of the client secret, the secret itself is only shown when it is created.
+	HashedSecret []byte `db:"hashed_secret" json:"hashed_secret"`
+	// The tail of the client secret, so admins can tell secrets apart.
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+// The API keys issued to apps, with the refresh tokens that rotate them.
+type OAuth2ProviderAppToken struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// When the refresh token expires, the API key expires independently.
+	ExpiresAt          time.Time `db:"expires_at" json:"expires_at"`
+	HashedRefreshToken []byte    `db:"hashed_refresh_token" json:"hashed_refresh_token"`
+	AppID              uuid.UUID `db:"app_id" json:"app_id"`
+	APIKeyID           string    `db:"api_key_id" json:"api_key_id"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1529,6 +2010,27 @@ type Replica struct {
 	Error           string       `db:"error" json:"error"`
 }
 
+type SessionRecording struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	AgentID          uuid.UUID `db:"agent_id" json:"agent_id"`
+	// The user that connected to the session, null if the agent could not attribute the connection.
+	UserID    uuid.NullUUID        `db:"user_id" json:"user_id"`
+	Type      SessionRecordingType `db:"type" json:"type"`
+	StartedAt time.Time            `db:"started_at" json:"started_at"`
+	// Set when the agent uploads the final chunk of the recording.
+	EndedAt sql.NullTime `db:"ended_at" json:"ended_at"`
+	Size    int64        `db:"size" json:"size"`
+}
+
+// Chunks of asciicast v2 recordings, concatenated in sequence order they form the recording.
+type SessionRecordingChunk struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	Sequence    int32     `db:"sequence" json:"sequence"`
+	Data        []byte    `db:"data" json:"data"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -1583,6 +2085,8 @@ type Template struct {
 	FailureTTL        int64 `db:"failure_ttl" json:"failure_ttl"`
 	InactivityTTL     int64 `db:"inactivity_ttl" json:"inactivity_ttl"`
 	LockedTTL         int64 `db:"locked_ttl" json:"locked_ttl"`
+	// The duration without user activity reported by the agent after which workspaces are stopped. Zero disables idle detection.
+	IdleThreshold int64 `db:"idle_threshold" json:"idle_threshold"`
 }
 
 type TemplateVersion struct {
@@ -1675,6 +2179,36 @@ type UserLink struct {
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
 }
 
+type Webhook struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	Name     string    `db:"name" json:"name"`
+	Endpoint string    `db:"endpoint" json:"endpoint"`
+	// The key deliveries are signed with using HMAC-SHA256.
+	Secret string `db:"secret" json:"secret"`
+	// The events that are delivered to the endpoint.
+	Events    []WebhookEvent `db:"events" json:"events"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	WebhookID uuid.UUID    `db:"webhook_id" json:"webhook_id"`
+	Event     WebhookEvent `db:"event" json:"event"`
+	// The data of the event, which is sent in the data field of the request body.
+	Payload  json.RawMessage       `db:"payload" json:"payload"`
+	Status   WebhookDeliveryStatus `db:"status" json:"status"`
+	Attempts int32                 `db:"attempts" json:"attempts"`
+	// The HTTP status code the endpoint responded with on the last attempt.
+	StatusCode sql.NullInt32  `db:"status_code" json:"status_code"`
+	LastError  sql.NullString `db:"last_error" json:"last_error"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+	SendAfter  time.Time      `db:"send_after" json:"send_after"`
+	// Set while a coderd replica sends the delivery, deliveries whose lease expired are sent again.
+	LeasedUntil sql.NullTime `db:"leased_until" json:"leased_until"`
+}
+
 type Workspace struct {
 	ID                uuid.UUID      `db:"id" json:"id"`
 	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
@@ -1739,6 +2273,14 @@ type WorkspaceAgent struct {
 	ReadyAt sql.NullTime `db:"ready_at" json:"ready_at"`
 }
 
+type WorkspaceAgentLogSource struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	ID               uuid.UUID `db:"id" json:"id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Icon             string    `db:"icon" json:"icon"`
+}
+
 type WorkspaceAgentMetadatum struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	DisplayName      string    `db:"display_name" json:"display_name"`
@@ -1749,14 +2291,43 @@ type WorkspaceAgentMetadatum struct {
 	Timeout          int64     `db:"timeout" json:"timeout"`
 	Interval         int64     `db:"interval" json:"interval"`
 	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
+	// The name of a built-in collector evaluated by the agent instead of running script. Empty means the script is run.
+	Collector string `db:"collector" json:"collector"`
+}
+
+// The latest snapshot of the resource usage of processes reported by each workspace agent.
+type WorkspaceAgentProcess struct {
+	WorkspaceAgentID uuid.UUID       `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CollectedAt      time.Time       `db:"collected_at" json:"collected_at"`
+	Processes        json.RawMessage `db:"processes" json:"processes"`
+}
+
+type WorkspaceAgentScript struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	LogPath          string    `db:"log_path" json:"log_path"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	Script           string    `db:"script" json:"script"`
+	Cron             string    `db:"cron" json:"cron"`
+	StartBlocksLogin bool      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       bool      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        bool      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	// The status of the most recent execution of the script, reported by the agent.
+	Status WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	// The exit code of the most recent execution of the script, only meaningful when status is succeeded or failed.
+	ExitCode  int32        `db:"exit_code" json:"exit_code"`
+	StartedAt sql.NullTime `db:"started_at" json:"started_at"`
+	EndedAt   sql.NullTime `db:"ended_at" json:"ended_at"`
 }
 
 type WorkspaceAgentStartupLog struct {
-	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	Output    string    `db:"output" json:"output"`
-	ID        int64     `db:"id" json:"id"`
-	Level     LogLevel  `db:"level" json:"level"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	Output      string    `db:"output" json:"output"`
+	ID          int64     `db:"id" json:"id"`
+	Level       LogLevel  `db:"level" json:"level"`
+	LogSourceID uuid.UUID `db:"log_source_id" json:"log_source_id"`
 }
 
 type WorkspaceAgentStat struct {
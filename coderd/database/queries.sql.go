@@ -15,16 +15,32 @@ import (
 	"github.com/tabbed/pqtype"
 )
 
-const deleteAPIKeyByID = `-- name: DeleteAPIKeyByID :exec
+const deleteAPIKeyByID = `-- name: DeleteAPIKeyByID :one
 DELETE FROM
 	api_keys
 WHERE
 	id = $1
+RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
 `
 
-func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteAPIKeyByID, id)
-	return err
+func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) (APIKey, error) {
+	row := q.db.QueryRowContext(ctx, deleteAPIKeyByID, id)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.LastUsed,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LoginType,
+		&i.LifetimeSeconds,
+		&i.IPAddress,
+		&i.Scope,
+		&i.TokenName,
+	)
+	return i, err
 }
 
 const deleteAPIKeysByUserID = `-- name: DeleteAPIKeysByUserID :exec
@@ -636,6 +652,308 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
+const deleteCustomRoleByID = `-- name: DeleteCustomRoleByID :exec
+DELETE FROM
+	custom_roles
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteCustomRoleByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCustomRoleByID, id)
+	return err
+}
+
+const getCustomRoleByID = `-- name: GetCustomRoleByID :one
+SELECT id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at FROM custom_roles WHERE id = $1
+`
+
+func (q *sqlQuerier) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, getCustomRoleByID, id)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCustomRoleByName = `-- name: GetCustomRoleByName :one
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	name = $1
+	AND COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid) = $2 :: uuid
+`
+
+type GetCustomRoleByNameParams struct {
+	Name           string    `db:"name" json:"name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Looks up a site role if the organization ID is the nil UUID, and a role of
+// the organization otherwise.
+func (q *sqlQuerier) GetCustomRoleByName(ctx context.Context, arg GetCustomRoleByNameParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, getCustomRoleByName, arg.Name, arg.OrganizationID)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCustomRoles = `-- name: GetCustomRoles :many
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid) = $1 :: uuid
+ORDER BY
+	name ASC
+`
+
+// Returns the site roles if the organization ID is the nil UUID, and the
+// roles of the organization otherwise.
+func (q *sqlQuerier) GetCustomRoles(ctx context.Context, organizationID uuid.UUID) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomRoles, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomRole
+	for rows.Next() {
+		var i CustomRole
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCustomRolesByNames = `-- name: GetCustomRolesByNames :many
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	(
+		CASE
+			WHEN organization_id IS NULL THEN name
+			ELSE name || ':' || organization_id :: text
+		END
+	) = ANY($1 :: text[])
+`
+
+// Looks up roles by the names they are assigned with, which are scoped to
+// their organization for organization roles.
+func (q *sqlQuerier) GetCustomRolesByNames(ctx context.Context, names []string) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomRolesByNames, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomRole
+	for rows.Next() {
+		var i CustomRole
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertCustomRole = `-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type InsertCustomRoleParams struct {
+	ID              uuid.UUID             `db:"id" json:"id"`
+	Name            string                `db:"name" json:"name"`
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, insertCustomRole,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const removeRoleFromOrganizationMembers = `-- name: RemoveRoleFromOrganizationMembers :exec
+UPDATE
+	organization_members
+SET
+	roles = array_remove(roles, $1 :: text)
+WHERE
+	organization_id = $2
+	AND $1 :: text = ANY(roles)
+`
+
+type RemoveRoleFromOrganizationMembersParams struct {
+	RoleName       string    `db:"role_name" json:"role_name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Removes an organization role from the members of the organization it is
+// assigned to.
+func (q *sqlQuerier) RemoveRoleFromOrganizationMembers(ctx context.Context, arg RemoveRoleFromOrganizationMembersParams) error {
+	_, err := q.db.ExecContext(ctx, removeRoleFromOrganizationMembers, arg.RoleName, arg.OrganizationID)
+	return err
+}
+
+const removeRoleFromUsers = `-- name: RemoveRoleFromUsers :exec
+UPDATE
+	users
+SET
+	rbac_roles = array_remove(rbac_roles, $1 :: text)
+WHERE
+	$1 :: text = ANY(rbac_roles)
+`
+
+// Removes a site role from the users it is assigned to.
+func (q *sqlQuerier) RemoveRoleFromUsers(ctx context.Context, roleName string) error {
+	_, err := q.db.ExecContext(ctx, removeRoleFromUsers, roleName)
+	return err
+}
+
+const updateCustomRoleByID = `-- name: UpdateCustomRoleByID :one
+UPDATE
+	custom_roles
+SET
+	display_name = $2,
+	site_permissions = $3,
+	org_permissions = $4,
+	user_permissions = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type UpdateCustomRoleByIDParams struct {
+	ID              uuid.UUID             `db:"id" json:"id"`
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateCustomRoleByID(ctx context.Context, arg UpdateCustomRoleByIDParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, updateCustomRoleByID,
+		arg.ID,
+		arg.DisplayName,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.UpdatedAt,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
 SELECT
 	hash, created_at, created_by, mimetype, data, id
@@ -1547,32 +1865,69 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
-const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
-SELECT
-    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
-FROM
-    organization_members
+const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
+UPDATE
+	notification_messages
+SET
+	status = 'leased'::notification_message_status,
+	attempts = attempts + 1,
+	updated_at = $1 :: timestamptz,
+	leased_until = $2 :: timestamptz
 WHERE
-    user_id = ANY($1 :: uuid [ ])
-GROUP BY
-    user_id
+	id IN (
+		SELECT
+			nm.id
+		FROM
+			notification_messages AS nm
+		WHERE
+			(
+				nm.status = 'pending'::notification_message_status AND
+				nm.send_after <= $1 :: timestamptz
+			) OR (
+				nm.status = 'leased'::notification_message_status AND
+				nm.leased_until < $1 :: timestamptz
+			)
+		ORDER BY
+			nm.created_at ASC
+		LIMIT
+			$3 :: integer
+		FOR UPDATE SKIP LOCKED
+	)
+RETURNING id, user_id, template, method, labels, status, attempts, last_error, dedupe_hash, created_at, updated_at, send_after, leased_until
 `
 
-type GetOrganizationIDsByMemberIDsRow struct {
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+type AcquireNotificationMessagesParams struct {
+	Now         time.Time `db:"now" json:"now"`
+	LeasedUntil time.Time `db:"leased_until" json:"leased_until"`
+	Count       int32     `db:"count" json:"count"`
 }
 
-func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+// Leases pending messages, and leased messages whose lease expired, to the
+// caller. Messages leased by other replicas at the same time are skipped.
+func (q *sqlQuerier) AcquireNotificationMessages(ctx context.Context, arg AcquireNotificationMessagesParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, acquireNotificationMessages, arg.Now, arg.LeasedUntil, arg.Count)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetOrganizationIDsByMemberIDsRow
+	var items []NotificationMessage
 	for rows.Next() {
-		var i GetOrganizationIDsByMemberIDsRow
-		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Method,
+			&i.Labels,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.DedupeHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SendAfter,
+			&i.LeasedUntil,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -1586,33 +1941,804 @@ func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uu
 	return items, nil
 }
 
-const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
-SELECT
-	user_id, organization_id, created_at, updated_at, roles
-FROM
-	organization_members
+const deleteOldNotificationMessages = `-- name: DeleteOldNotificationMessages :exec
+DELETE FROM
+	notification_messages
 WHERE
-	organization_id = $1
-	AND user_id = $2
-LIMIT
-	1
+	status IN ('sent'::notification_message_status, 'failed'::notification_message_status) AND
+	updated_at < $1 :: timestamptz
 `
 
-type GetOrganizationMemberByUserIDParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+func (q *sqlQuerier) DeleteOldNotificationMessages(ctx context.Context, before time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNotificationMessages, before)
+	return err
 }
 
-func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
-	var i OrganizationMember
-	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		pq.Array(&i.Roles),
-	)
+const enqueueNotificationMessage = `-- name: EnqueueNotificationMessage :exec
+INSERT INTO
+	notification_messages (id, user_id, template, method, labels, dedupe_hash, created_at, updated_at, send_after)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7, $7) ON CONFLICT (dedupe_hash) DO NOTHING
+`
+
+type EnqueueNotificationMessageParams struct {
+	ID         uuid.UUID          `db:"id" json:"id"`
+	UserID     uuid.UUID          `db:"user_id" json:"user_id"`
+	Template   string             `db:"template" json:"template"`
+	Method     NotificationMethod `db:"method" json:"method"`
+	Labels     StringMap          `db:"labels" json:"labels"`
+	DedupeHash string             `db:"dedupe_hash" json:"dedupe_hash"`
+	CreatedAt  time.Time          `db:"created_at" json:"created_at"`
+}
+
+// Notifications that were already enqueued, identified by their dedupe hash,
+// are ignored.
+func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg EnqueueNotificationMessageParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueNotificationMessage,
+		arg.ID,
+		arg.UserID,
+		arg.Template,
+		arg.Method,
+		arg.Labels,
+		arg.DedupeHash,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const getNotificationMessagesByUserID = `-- name: GetNotificationMessagesByUserID :many
+SELECT id, user_id, template, method, labels, status, attempts, last_error, dedupe_hash, created_at, updated_at, send_after, leased_until FROM notification_messages WHERE user_id = $1 ORDER BY created_at DESC
+`
+
+func (q *sqlQuerier) GetNotificationMessagesByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationMessagesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationMessage
+	for rows.Next() {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Method,
+			&i.Labels,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.DedupeHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SendAfter,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationPreferencesByUserID = `-- name: GetNotificationPreferencesByUserID :many
+SELECT user_id, template, disabled, updated_at FROM notification_preferences WHERE user_id = $1 ORDER BY template ASC
+`
+
+func (q *sqlQuerier) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationPreferencesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Template,
+			&i.Disabled,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationMessageFailed = `-- name: MarkNotificationMessageFailed :exec
+UPDATE
+	notification_messages
+SET
+	status = CASE WHEN $1 :: boolean THEN 'pending'::notification_message_status ELSE 'failed'::notification_message_status END,
+	updated_at = $2,
+	send_after = $3,
+	leased_until = NULL,
+	last_error = $4
+WHERE
+	id = $5
+`
+
+type MarkNotificationMessageFailedParams struct {
+	Retry     bool           `db:"retry" json:"retry"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	SendAfter time.Time      `db:"send_after" json:"send_after"`
+	LastError sql.NullString `db:"last_error" json:"last_error"`
+	ID        uuid.UUID      `db:"id" json:"id"`
+}
+
+// Messages that are retried are pending again once send_after is reached.
+func (q *sqlQuerier) MarkNotificationMessageFailed(ctx context.Context, arg MarkNotificationMessageFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markNotificationMessageFailed,
+		arg.Retry,
+		arg.UpdatedAt,
+		arg.SendAfter,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const markNotificationMessageSent = `-- name: MarkNotificationMessageSent :exec
+UPDATE
+	notification_messages
+SET
+	status = 'sent'::notification_message_status,
+	updated_at = $1,
+	leased_until = NULL,
+	last_error = NULL
+WHERE
+	id = $2
+`
+
+type MarkNotificationMessageSentParams struct {
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) MarkNotificationMessageSent(ctx context.Context, arg MarkNotificationMessageSentParams) error {
+	_, err := q.db.ExecContext(ctx, markNotificationMessageSent, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :exec
+INSERT INTO
+	notification_preferences (user_id, template, disabled, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT
+	(user_id, template)
+DO UPDATE SET
+	disabled = $3,
+	updated_at = $4
+`
+
+type UpsertNotificationPreferenceParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) error {
+	_, err := q.db.ExecContext(ctx, upsertNotificationPreference,
+		arg.UserID,
+		arg.Template,
+		arg.Disabled,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
+WITH revoked_keys AS (
+	DELETE FROM
+		api_keys
+	WHERE
+		id IN (SELECT api_key_id FROM oauth2_provider_app_tokens WHERE app_id = $1 :: uuid)
+)
+DELETE FROM oauth2_provider_apps WHERE id = $1 :: uuid
+`
+
+// Deleting an app revokes the API keys issued to it, which aren't removed by
+// the cascade from the app to its tokens.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :one
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING id, created_at, expires_at, hashed_secret, user_id, app_id, redirect_uri, scope, code_challenge
+`
+
+// Returns no rows if the code was already deleted, which is how codes are
+// redeemed only once.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const deleteOAuth2ProviderAppCodesByAppAndUserID = `-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
+DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2
+`
+
+type DeleteOAuth2ProviderAppCodesByAppAndUserIDParams struct {
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodesByAppAndUserID, arg.AppID, arg.UserID)
+	return err
+}
+
+const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1 :: uuid AND
+	id IN (SELECT api_key_id FROM oauth2_provider_app_tokens WHERE app_id = $2 :: uuid)
+`
+
+type DeleteOAuth2ProviderAppTokensByAppAndUserIDParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+// Revokes the API keys the user issued to the app, their tokens are removed
+// by the cascade.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppTokensByAppAndUserID, arg.UserID, arg.AppID)
+	return err
+}
+
+const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByHashedSecret = `-- name: GetOAuth2ProviderAppCodeByHashedSecret :one
+SELECT id, created_at, expires_at, hashed_secret, user_id, app_id, redirect_uri, scope, code_challenge FROM oauth2_provider_app_codes WHERE hashed_secret = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByHashedSecret, hashedSecret)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT id, created_at, expires_at, hashed_secret, user_id, app_id, redirect_uri, scope, code_challenge FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByHashedSecret = `-- name: GetOAuth2ProviderAppSecretByHashedSecret :one
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE hashed_secret = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByHashedSecret(ctx context.Context, hashedSecret []byte) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByHashedSecret, hashedSecret)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretsByAppID = `-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppSecretsByAppID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderAppSecret
+	for rows.Next() {
+		var i OAuth2ProviderAppSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.HashedSecret,
+			&i.DisplaySecret,
+			&i.AppID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOAuth2ProviderAppTokenByHashedRefreshToken = `-- name: GetOAuth2ProviderAppTokenByHashedRefreshToken :one
+SELECT id, created_at, expires_at, hashed_refresh_token, app_id, api_key_id FROM oauth2_provider_app_tokens WHERE hashed_refresh_token = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx context.Context, hashedRefreshToken []byte) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByHashedRefreshToken, hashedRefreshToken)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedRefreshToken,
+		&i.AppID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps ORDER BY (name, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderApps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderApp
+	for rows.Next() {
+		var i OAuth2ProviderApp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			&i.CallbackURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOAuth2ProviderAppsByUserID = `-- name: GetOAuth2ProviderAppsByUserID :many
+SELECT
+	oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.callback_url
+FROM
+	oauth2_provider_apps
+WHERE
+	oauth2_provider_apps.id IN (
+		SELECT
+			oauth2_provider_app_tokens.app_id
+		FROM
+			oauth2_provider_app_tokens
+		INNER JOIN
+			api_keys ON api_keys.id = oauth2_provider_app_tokens.api_key_id
+		WHERE
+			api_keys.user_id = $1 :: uuid
+	)
+ORDER BY (oauth2_provider_apps.name, oauth2_provider_apps.id) ASC
+`
+
+// Returns the apps the user authorized, which hold API keys of the user.
+func (q *sqlQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderApp
+	for rows.Next() {
+		var i OAuth2ProviderApp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			&i.CallbackURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
+INSERT INTO
+	oauth2_provider_apps (id, created_at, updated_at, name, icon, callback_url)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at, name, icon, callback_url
+`
+
+type InsertOAuth2ProviderAppParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderApp,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		arg.CallbackURL,
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppCode = `-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO
+	oauth2_provider_app_codes (id, created_at, expires_at, hashed_secret, user_id, app_id, redirect_uri, scope, code_challenge)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, expires_at, hashed_secret, user_id, app_id, redirect_uri, scope, code_challenge
+`
+
+type InsertOAuth2ProviderAppCodeParams struct {
+	ID            uuid.UUID   `db:"id" json:"id"`
+	CreatedAt     time.Time   `db:"created_at" json:"created_at"`
+	ExpiresAt     time.Time   `db:"expires_at" json:"expires_at"`
+	HashedSecret  []byte      `db:"hashed_secret" json:"hashed_secret"`
+	UserID        uuid.UUID   `db:"user_id" json:"user_id"`
+	AppID         uuid.UUID   `db:"app_id" json:"app_id"`
+	RedirectURI   string      `db:"redirect_uri" json:"redirect_uri"`
+	Scope         APIKeyScope `db:"scope" json:"scope"`
+	CodeChallenge string      `db:"code_challenge" json:"code_challenge"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.HashedSecret,
+		arg.UserID,
+		arg.AppID,
+		arg.RedirectURI,
+		arg.Scope,
+		arg.CodeChallenge,
+	)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.Scope,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO
+	oauth2_provider_app_secrets (id, created_at, hashed_secret, display_secret, app_id)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
+`
+
+type InsertOAuth2ProviderAppSecretParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppSecret,
+		arg.ID,
+		arg.CreatedAt,
+		arg.HashedSecret,
+		arg.DisplaySecret,
+		arg.AppID,
+	)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppToken = `-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO
+	oauth2_provider_app_tokens (id, created_at, expires_at, hashed_refresh_token, app_id, api_key_id)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, created_at, expires_at, hashed_refresh_token, app_id, api_key_id
+`
+
+type InsertOAuth2ProviderAppTokenParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt          time.Time `db:"expires_at" json:"expires_at"`
+	HashedRefreshToken []byte    `db:"hashed_refresh_token" json:"hashed_refresh_token"`
+	AppID              uuid.UUID `db:"app_id" json:"app_id"`
+	APIKeyID           string    `db:"api_key_id" json:"api_key_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppToken,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.HashedRefreshToken,
+		arg.AppID,
+		arg.APIKeyID,
+	)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashedRefreshToken,
+		&i.AppID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppByID = `-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE
+	oauth2_provider_apps
+SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	callback_url = $5
+WHERE
+	id = $1
+RETURNING id, created_at, updated_at, name, icon, callback_url
+`
+
+type UpdateOAuth2ProviderAppByIDParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		arg.CallbackURL,
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppSecretLastUsedAtByID = `-- name: UpdateOAuth2ProviderAppSecretLastUsedAtByID :exec
+UPDATE
+	oauth2_provider_app_secrets
+SET
+	last_used_at = $2
+WHERE
+	id = $1
+`
+
+type UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretLastUsedAtByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateOAuth2ProviderAppSecretLastUsedAtByID, arg.ID, arg.LastUsedAt)
+	return err
+}
+
+const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
+SELECT
+    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
+FROM
+    organization_members
+WHERE
+    user_id = ANY($1 :: uuid [ ])
+GROUP BY
+    user_id
+`
+
+type GetOrganizationIDsByMemberIDsRow struct {
+	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
+	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+}
+
+func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrganizationIDsByMemberIDsRow
+	for rows.Next() {
+		var i GetOrganizationIDsByMemberIDsRow
+		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
+SELECT
+	user_id, organization_id, created_at, updated_at, roles
+FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2
+LIMIT
+	1
+`
+
+type GetOrganizationMemberByUserIDParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Roles),
+	)
 	return i, err
 }
 
@@ -3145,6 +4271,189 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 	return i, err
 }
 
+const deleteOldSessionRecordings = `-- name: DeleteOldSessionRecordings :exec
+DELETE FROM session_recordings WHERE started_at < NOW() - INTERVAL '30 day'
+`
+
+// Chunks are deleted with their recording.
+func (q *sqlQuerier) DeleteOldSessionRecordings(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldSessionRecordings)
+	return err
+}
+
+const getSessionRecordingByID = `-- name: GetSessionRecordingByID :one
+SELECT id, workspace_id, workspace_build_id, agent_id, user_id, type, started_at, ended_at, size FROM session_recordings WHERE id = $1
+`
+
+func (q *sqlQuerier) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (SessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getSessionRecordingByID, id)
+	var i SessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.AgentID,
+		&i.UserID,
+		&i.Type,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Size,
+	)
+	return i, err
+}
+
+const getSessionRecordingChunksByRecordingID = `-- name: GetSessionRecordingChunksByRecordingID :many
+SELECT recording_id, sequence, data FROM session_recording_chunks WHERE recording_id = $1 ORDER BY sequence ASC
+`
+
+func (q *sqlQuerier) GetSessionRecordingChunksByRecordingID(ctx context.Context, recordingID uuid.UUID) ([]SessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionRecordingChunksByRecordingID, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionRecordingChunk
+	for rows.Next() {
+		var i SessionRecordingChunk
+		if err := rows.Scan(&i.RecordingID, &i.Sequence, &i.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionRecordingsByWorkspaceID = `-- name: GetSessionRecordingsByWorkspaceID :many
+SELECT id, workspace_id, workspace_build_id, agent_id, user_id, type, started_at, ended_at, size FROM session_recordings WHERE workspace_id = $1 ORDER BY started_at DESC
+`
+
+func (q *sqlQuerier) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]SessionRecording, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionRecordingsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionRecording
+	for rows.Next() {
+		var i SessionRecording
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.WorkspaceBuildID,
+			&i.AgentID,
+			&i.UserID,
+			&i.Type,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Size,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSessionRecording = `-- name: InsertSessionRecording :one
+INSERT INTO
+	session_recordings (id, workspace_id, workspace_build_id, agent_id, user_id, type, started_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, workspace_id, workspace_build_id, agent_id, user_id, type, started_at, ended_at, size
+`
+
+type InsertSessionRecordingParams struct {
+	ID               uuid.UUID            `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID            `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID            `db:"workspace_build_id" json:"workspace_build_id"`
+	AgentID          uuid.UUID            `db:"agent_id" json:"agent_id"`
+	UserID           uuid.NullUUID        `db:"user_id" json:"user_id"`
+	Type             SessionRecordingType `db:"type" json:"type"`
+	StartedAt        time.Time            `db:"started_at" json:"started_at"`
+}
+
+func (q *sqlQuerier) InsertSessionRecording(ctx context.Context, arg InsertSessionRecordingParams) (SessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, insertSessionRecording,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.WorkspaceBuildID,
+		arg.AgentID,
+		arg.UserID,
+		arg.Type,
+		arg.StartedAt,
+	)
+	var i SessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.AgentID,
+		&i.UserID,
+		&i.Type,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Size,
+	)
+	return i, err
+}
+
+const insertSessionRecordingChunk = `-- name: InsertSessionRecordingChunk :exec
+INSERT INTO
+	session_recording_chunks (recording_id, sequence, data)
+VALUES
+	($1, $2, $3) ON CONFLICT (recording_id, sequence) DO NOTHING
+`
+
+type InsertSessionRecordingChunkParams struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	Sequence    int32     `db:"sequence" json:"sequence"`
+	Data        []byte    `db:"data" json:"data"`
+}
+
+// Chunks may be retried by the agent, so duplicates are ignored.
+func (q *sqlQuerier) InsertSessionRecordingChunk(ctx context.Context, arg InsertSessionRecordingChunkParams) error {
+	_, err := q.db.ExecContext(ctx, insertSessionRecordingChunk, arg.RecordingID, arg.Sequence, arg.Data)
+	return err
+}
+
+const updateSessionRecordingByID = `-- name: UpdateSessionRecordingByID :exec
+UPDATE
+	session_recordings
+SET
+	ended_at = $1,
+	size = (
+		SELECT
+			COALESCE(SUM(length(data)), 0)
+		FROM
+			session_recording_chunks
+		WHERE
+			recording_id = $2
+	)
+WHERE
+	id = $2
+`
+
+type UpdateSessionRecordingByIDParams struct {
+	EndedAt sql.NullTime `db:"ended_at" json:"ended_at"`
+	ID      uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateSessionRecordingByID(ctx context.Context, arg UpdateSessionRecordingByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateSessionRecordingByID, arg.EndedAt, arg.ID)
+	return err
+}
+
 const getAppSecurityKey = `-- name: GetAppSecurityKey :one
 SELECT value FROM site_configs WHERE key = 'app_signing_key'
 `
@@ -3616,7 +4925,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 FROM
 	templates
 WHERE
@@ -3651,13 +4960,14 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.FailureTTL,
 		&i.InactivityTTL,
 		&i.LockedTTL,
+		&i.IdleThreshold,
 	)
 	return i, err
 }
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 FROM
 	templates
 WHERE
@@ -3700,12 +5010,13 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.FailureTTL,
 		&i.InactivityTTL,
 		&i.LockedTTL,
+		&i.IdleThreshold,
 	)
 	return i, err
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl FROM templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold FROM templates
 ORDER BY (name, id) ASC
 `
 
@@ -3741,6 +5052,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.FailureTTL,
 			&i.InactivityTTL,
 			&i.LockedTTL,
+			&i.IdleThreshold,
 		); err != nil {
 			return nil, err
 		}
@@ -3757,7 +5069,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 FROM
 	templates
 WHERE
@@ -3830,6 +5142,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.FailureTTL,
 			&i.InactivityTTL,
 			&i.LockedTTL,
+			&i.IdleThreshold,
 		); err != nil {
 			return nil, err
 		}
@@ -3860,10 +5173,11 @@ INSERT INTO
 		user_acl,
 		group_acl,
 		display_name,
-		allow_user_cancel_workspace_jobs
+		allow_user_cancel_workspace_jobs,
+		idle_threshold
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 `
 
 type InsertTemplateParams struct {
@@ -3881,6 +5195,7 @@ type InsertTemplateParams struct {
 	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
 	DisplayName                  string          `db:"display_name" json:"display_name"`
 	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	IdleThreshold                int64           `db:"idle_threshold" json:"idle_threshold"`
 }
 
 func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) (Template, error) {
@@ -3899,6 +5214,7 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		arg.GroupACL,
 		arg.DisplayName,
 		arg.AllowUserCancelWorkspaceJobs,
+		arg.IdleThreshold,
 	)
 	var i Template
 	err := row.Scan(
@@ -3924,6 +5240,7 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		&i.FailureTTL,
 		&i.InactivityTTL,
 		&i.LockedTTL,
+		&i.IdleThreshold,
 	)
 	return i, err
 }
@@ -3937,7 +5254,7 @@ SET
 WHERE
 	id = $3
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 `
 
 type UpdateTemplateACLByIDParams struct {
@@ -3972,6 +5289,7 @@ func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTempla
 		&i.FailureTTL,
 		&i.InactivityTTL,
 		&i.LockedTTL,
+		&i.IdleThreshold,
 	)
 	return i, err
 }
@@ -4027,11 +5345,12 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	idle_threshold = $8
 WHERE
 	id = $1
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 `
 
 type UpdateTemplateMetaByIDParams struct {
@@ -4042,6 +5361,7 @@ type UpdateTemplateMetaByIDParams struct {
 	Icon                         string    `db:"icon" json:"icon"`
 	DisplayName                  string    `db:"display_name" json:"display_name"`
 	AllowUserCancelWorkspaceJobs bool      `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	IdleThreshold                int64     `db:"idle_threshold" json:"idle_threshold"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) (Template, error) {
@@ -4053,6 +5373,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.Icon,
 		arg.DisplayName,
 		arg.AllowUserCancelWorkspaceJobs,
+		arg.IdleThreshold,
 	)
 	var i Template
 	err := row.Scan(
@@ -4078,6 +5399,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		&i.FailureTTL,
 		&i.InactivityTTL,
 		&i.LockedTTL,
+		&i.IdleThreshold,
 	)
 	return i, err
 }
@@ -4097,7 +5419,7 @@ SET
 WHERE
 	id = $1
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, idle_threshold
 `
 
 type UpdateTemplateScheduleByIDParams struct {
@@ -4148,6 +5470,7 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 		&i.FailureTTL,
 		&i.InactivityTTL,
 		&i.LockedTTL,
+		&i.IdleThreshold,
 	)
 	return i, err
 }
@@ -4297,6 +5620,29 @@ func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg Ins
 	return i, err
 }
 
+const deleteTemplateVersionWithoutTemplate = `-- name: DeleteTemplateVersionWithoutTemplate :exec
+WITH deleted AS (
+	DELETE FROM
+		template_versions
+	WHERE
+		template_versions.id = $1 :: uuid
+		AND template_versions.template_id IS NULL
+	RETURNING
+		template_versions.job_id
+)
+DELETE FROM
+	provisioner_jobs
+WHERE
+	provisioner_jobs.id IN (SELECT job_id FROM deleted)
+`
+
+// Deletes a version that doesn't belong to a template along with its
+// provisioner job. Versions of templates are never deleted.
+func (q *sqlQuerier) DeleteTemplateVersionWithoutTemplate(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateVersionWithoutTemplate, id)
+	return err
+}
+
 const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
 SELECT
 	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, git_auth_providers
@@ -5003,6 +6349,56 @@ func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const getActiveUsersByRoleForUpdate = `-- name: GetActiveUsersByRoleForUpdate :many
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+FROM
+	users
+WHERE
+	status = 'active'::user_status
+	AND deleted = false
+	AND $1 :: text = ANY(rbac_roles)
+FOR UPDATE
+`
+
+// Locks the active users with the site role, so none of them loses the role
+// or is suspended until the transaction ends.
+func (q *sqlQuerier) GetActiveUsersByRoleForUpdate(ctx context.Context, roleName string) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveUsersByRoleForUpdate, roleName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
 SELECT
 	-- username is returned just to help for logging purposes
@@ -5589,50 +6985,434 @@ func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesPar
 		&i.HashedPassword,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
+	users
+SET
+	status = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+`
+
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const acquireWebhookDeliveries = `-- name: AcquireWebhookDeliveries :many
+UPDATE
+	webhook_deliveries
+SET
+	status = 'leased'::webhook_delivery_status,
+	attempts = attempts + 1,
+	updated_at = $1 :: timestamptz,
+	leased_until = $2 :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			wd.id
+		FROM
+			webhook_deliveries AS wd
+		WHERE
+			(
+				wd.status = 'pending'::webhook_delivery_status AND
+				wd.send_after <= $1 :: timestamptz
+			) OR (
+				wd.status = 'leased'::webhook_delivery_status AND
+				wd.leased_until < $1 :: timestamptz
+			)
+		ORDER BY
+			wd.created_at ASC
+		LIMIT
+			$3 :: integer
+		FOR UPDATE SKIP LOCKED
+	)
+RETURNING id, webhook_id, event, payload, status, attempts, status_code, last_error, created_at, updated_at, send_after, leased_until
+`
+
+type AcquireWebhookDeliveriesParams struct {
+	Now         time.Time `db:"now" json:"now"`
+	LeasedUntil time.Time `db:"leased_until" json:"leased_until"`
+	Count       int32     `db:"count" json:"count"`
+}
+
+// Leases pending deliveries, and leased deliveries whose lease expired, to the
+// caller. Deliveries leased by other replicas at the same time are skipped.
+func (q *sqlQuerier) AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, acquireWebhookDeliveries, arg.Now, arg.LeasedUntil, arg.Count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.StatusCode,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SendAfter,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteOldWebhookDeliveries = `-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM
+	webhook_deliveries
+WHERE
+	status IN ('succeeded'::webhook_delivery_status, 'failed'::webhook_delivery_status) AND
+	updated_at < $1 :: timestamptz
+`
+
+func (q *sqlQuerier) DeleteOldWebhookDeliveries(ctx context.Context, before time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWebhookDeliveries, before)
+	return err
+}
+
+const deleteWebhookByID = `-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookByID, id)
+	return err
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :many
+INSERT INTO
+	webhook_deliveries (id, webhook_id, event, payload, created_at, updated_at, send_after)
+SELECT
+	gen_random_uuid(), webhooks.id, $1 :: webhook_event, $2 :: jsonb, $3 :: timestamptz, $3 :: timestamptz, $3 :: timestamptz
+FROM
+	webhooks
+WHERE
+	$1 :: webhook_event = ANY(webhooks.events)
+RETURNING id, webhook_id, event, payload, status, attempts, status_code, last_error, created_at, updated_at, send_after, leased_until
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	Event     WebhookEvent    `db:"event" json:"event"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Enqueues a delivery of the event to every webhook that subscribed to it.
+func (q *sqlQuerier) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, enqueueWebhookDeliveries, arg.Event, arg.Payload, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.StatusCode,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SendAfter,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT id, name, endpoint, secret, events, created_at, updated_at FROM webhooks WHERE id = $1
+`
+
+func (q *sqlQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Endpoint,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDeliveriesByWebhookID = `-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	id, webhook_id, event, payload, status, attempts, status_code, last_error, created_at, updated_at, send_after, leased_until
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = $1
+ORDER BY
+	created_at DESC
+OFFSET
+	$2
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($3 :: int, 0)
+`
+
+type GetWebhookDeliveriesByWebhookIDParams struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	OffsetOpt int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveriesByWebhookID, arg.WebhookID, arg.OffsetOpt, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.StatusCode,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SendAfter,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT id, name, endpoint, secret, events, created_at, updated_at FROM webhooks ORDER BY name ASC
+`
+
+func (q *sqlQuerier) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Endpoint,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWebhook = `-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (id, name, endpoint, secret, events, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, endpoint, secret, events, created_at, updated_at
+`
+
+type InsertWebhookParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	Name      string         `db:"name" json:"name"`
+	Endpoint  string         `db:"endpoint" json:"endpoint"`
+	Secret    string         `db:"secret" json:"secret"`
+	Events    []WebhookEvent `db:"events" json:"events"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
+		arg.ID,
+		arg.Name,
+		arg.Endpoint,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Endpoint,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateWebhookByID = `-- name: UpdateWebhookByID :one
+UPDATE
+	webhooks
+SET
+	name = $2,
+	endpoint = $3,
+	secret = $4,
+	events = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING id, name, endpoint, secret, events, created_at, updated_at
+`
+
+type UpdateWebhookByIDParams struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	Name      string         `db:"name" json:"name"`
+	Endpoint  string         `db:"endpoint" json:"endpoint"`
+	Secret    string         `db:"secret" json:"secret"`
+	Events    []WebhookEvent `db:"events" json:"events"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWebhookByID(ctx context.Context, arg UpdateWebhookByIDParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhookByID,
+		arg.ID,
+		arg.Name,
+		arg.Endpoint,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.UpdatedAt,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Endpoint,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
+const updateWebhookDeliveryByID = `-- name: UpdateWebhookDeliveryByID :exec
 UPDATE
-	users
+	webhook_deliveries
 SET
-	status = $2,
-	updated_at = $3
+	status = $1,
+	status_code = $2,
+	last_error = $3,
+	updated_at = $4,
+	send_after = $5,
+	leased_until = NULL
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at
+	id = $6
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpdateWebhookDeliveryByIDParams struct {
+	Status     WebhookDeliveryStatus `db:"status" json:"status"`
+	StatusCode sql.NullInt32         `db:"status_code" json:"status_code"`
+	LastError  sql.NullString        `db:"last_error" json:"last_error"`
+	UpdatedAt  time.Time             `db:"updated_at" json:"updated_at"`
+	SendAfter  time.Time             `db:"send_after" json:"send_after"`
+	ID         uuid.UUID             `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
+func (q *sqlQuerier) UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDeliveryByID,
+		arg.Status,
+		arg.StatusCode,
+		arg.LastError,
+		arg.UpdatedAt,
+		arg.SendAfter,
+		arg.ID,
 	)
-	return i, err
+	return err
 }
 
 const deleteOldWorkspaceAgentStartupLogs = `-- name: DeleteOldWorkspaceAgentStartupLogs :exec
@@ -5826,9 +7606,42 @@ func (q *sqlQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id
 	return i, err
 }
 
+const getWorkspaceAgentLogSourcesByAgentIDs = `-- name: GetWorkspaceAgentLogSourcesByAgentIDs :many
+SELECT workspace_agent_id, id, created_at, display_name, icon FROM workspace_agent_log_sources WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentLogSourcesByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLogSource
+	for rows.Next() {
+		var i WorkspaceAgentLogSource
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.DisplayName,
+			&i.Icon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentMetadata = `-- name: GetWorkspaceAgentMetadata :many
 SELECT
-	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at
+	workspace_agent_id, display_name, key, script, value, error, timeout, interval, collected_at, collector
 FROM
 	workspace_agent_metadata
 WHERE
@@ -5854,6 +7667,7 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAge
 			&i.Timeout,
 			&i.Interval,
 			&i.CollectedAt,
+			&i.Collector,
 		); err != nil {
 			return nil, err
 		}
@@ -5868,9 +7682,25 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAge
 	return items, nil
 }
 
+const getWorkspaceAgentProcesses = `-- name: GetWorkspaceAgentProcesses :one
+SELECT
+	workspace_agent_id, collected_at, processes
+FROM
+	workspace_agent_processes
+WHERE
+	workspace_agent_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentProcesses(ctx context.Context, workspaceAgentID uuid.UUID) (WorkspaceAgentProcess, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentProcesses, workspaceAgentID)
+	var i WorkspaceAgentProcess
+	err := row.Scan(&i.WorkspaceAgentID, &i.CollectedAt, &i.Processes)
+	return i, err
+}
+
 const getWorkspaceAgentStartupLogsAfter = `-- name: GetWorkspaceAgentStartupLogsAfter :many
 SELECT
-	agent_id, created_at, output, id, level
+	agent_id, created_at, output, id, level, log_source_id
 FROM
 	workspace_agent_startup_logs
 WHERE
@@ -5900,6 +7730,7 @@ func (q *sqlQuerier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg
 			&i.Output,
 			&i.ID,
 			&i.Level,
+			&i.LogSourceID,
 		); err != nil {
 			return nil, err
 		}
@@ -6235,6 +8066,61 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 	return i, err
 }
 
+const insertWorkspaceAgentLogSources = `-- name: InsertWorkspaceAgentLogSources :many
+INSERT INTO
+		workspace_agent_log_sources (workspace_agent_id, created_at, id, display_name, icon)
+	SELECT
+		$1 :: uuid AS workspace_agent_id,
+		$2 :: timestamptz AS created_at,
+		unnest($3 :: uuid [ ]) AS id,
+		unnest($4 :: VARCHAR(127) [ ]) AS display_name,
+		unnest($5 :: text [ ]) AS icon
+	RETURNING workspace_agent_log_sources.workspace_agent_id, workspace_agent_log_sources.id, workspace_agent_log_sources.created_at, workspace_agent_log_sources.display_name, workspace_agent_log_sources.icon
+`
+
+type InsertWorkspaceAgentLogSourcesParams struct {
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	ID               []uuid.UUID `db:"id" json:"id"`
+	DisplayName      []string    `db:"display_name" json:"display_name"`
+	Icon             []string    `db:"icon" json:"icon"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentLogSources,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.ID),
+		pq.Array(arg.DisplayName),
+		pq.Array(arg.Icon),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentLogSource
+	for rows.Next() {
+		var i WorkspaceAgentLogSource
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.ID,
+			&i.CreatedAt,
+			&i.DisplayName,
+			&i.Icon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertWorkspaceAgentMetadata = `-- name: InsertWorkspaceAgentMetadata :exec
 INSERT INTO
 	workspace_agent_metadata (
@@ -6243,10 +8129,11 @@ INSERT INTO
 		key,
 		script,
 		timeout,
-		interval
+		interval,
+		collector
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6)
+	($1, $2, $3, $4, $5, $6, $7)
 `
 
 type InsertWorkspaceAgentMetadataParams struct {
@@ -6256,6 +8143,7 @@ type InsertWorkspaceAgentMetadataParams struct {
 	Script           string    `db:"script" json:"script"`
 	Timeout          int64     `db:"timeout" json:"timeout"`
 	Interval         int64     `db:"interval" json:"interval"`
+	Collector        string    `db:"collector" json:"collector"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error {
@@ -6266,6 +8154,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg Inser
 		arg.Script,
 		arg.Timeout,
 		arg.Interval,
+		arg.Collector,
 	)
 	return err
 }
@@ -6273,16 +8162,17 @@ func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg Inser
 const insertWorkspaceAgentStartupLogs = `-- name: InsertWorkspaceAgentStartupLogs :many
 WITH new_length AS (
 	UPDATE workspace_agents SET
-	startup_logs_length = startup_logs_length + $5 WHERE workspace_agents.id = $1
+	startup_logs_length = startup_logs_length + $6 WHERE workspace_agents.id = $1
 )
 INSERT INTO
-		workspace_agent_startup_logs (agent_id, created_at, output, level)
+		workspace_agent_startup_logs (agent_id, created_at, output, level, log_source_id)
 	SELECT
 		$1 :: uuid AS agent_id,
 		unnest($2 :: timestamptz [ ]) AS created_at,
 		unnest($3 :: VARCHAR(1024) [ ]) AS output,
-		unnest($4 :: log_level [ ]) AS level
-	RETURNING workspace_agent_startup_logs.agent_id, workspace_agent_startup_logs.created_at, workspace_agent_startup_logs.output, workspace_agent_startup_logs.id, workspace_agent_startup_logs.level
+		unnest($4 :: log_level [ ]) AS level,
+		$5 :: uuid AS log_source_id
+	RETURNING workspace_agent_startup_logs.agent_id, workspace_agent_startup_logs.created_at, workspace_agent_startup_logs.output, workspace_agent_startup_logs.id, workspace_agent_startup_logs.level, workspace_agent_startup_logs.log_source_id
 `
 
 type InsertWorkspaceAgentStartupLogsParams struct {
@@ -6290,6 +8180,7 @@ type InsertWorkspaceAgentStartupLogsParams struct {
 	CreatedAt    []time.Time `db:"created_at" json:"created_at"`
 	Output       []string    `db:"output" json:"output"`
 	Level        []LogLevel  `db:"level" json:"level"`
+	LogSourceID  uuid.UUID   `db:"log_source_id" json:"log_source_id"`
 	OutputLength int32       `db:"output_length" json:"output_length"`
 }
 
@@ -6299,6 +8190,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg In
 		pq.Array(arg.CreatedAt),
 		pq.Array(arg.Output),
 		pq.Array(arg.Level),
+		arg.LogSourceID,
 		arg.OutputLength,
 	)
 	if err != nil {
@@ -6314,6 +8206,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg In
 			&i.Output,
 			&i.ID,
 			&i.Level,
+			&i.LogSourceID,
 		); err != nil {
 			return nil, err
 		}
@@ -6468,6 +8361,31 @@ func (q *sqlQuerier) UpdateWorkspaceAgentStartupLogOverflowByID(ctx context.Cont
 	return err
 }
 
+const upsertWorkspaceAgentProcesses = `-- name: UpsertWorkspaceAgentProcesses :exec
+INSERT INTO
+	workspace_agent_processes (
+		workspace_agent_id,
+		collected_at,
+		processes
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (workspace_agent_id) DO UPDATE SET
+	collected_at = $2,
+	processes = $3
+`
+
+type UpsertWorkspaceAgentProcessesParams struct {
+	WorkspaceAgentID uuid.UUID       `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CollectedAt      time.Time       `db:"collected_at" json:"collected_at"`
+	Processes        json.RawMessage `db:"processes" json:"processes"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentProcesses(ctx context.Context, arg UpsertWorkspaceAgentProcessesParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentProcesses, arg.WorkspaceAgentID, arg.CollectedAt, arg.Processes)
+	return err
+}
+
 const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
 DELETE FROM workspace_agent_stats WHERE created_at < NOW() - INTERVAL '30 days'
 `
@@ -8665,6 +10583,75 @@ func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now
 	return items, nil
 }
 
+const getWorkspacesMarkedForDeletion = `-- name: GetWorkspacesMarkedForDeletion :many
+SELECT
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at
+FROM
+	workspaces
+INNER JOIN
+	templates ON templates.id = workspaces.template_id
+INNER JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+	) AND
+	templates.inactivity_ttl > 0 AND
+	(
+		workspace_builds.transition = 'stop'::workspace_transition OR
+		provisioner_jobs.canceled_at IS NOT NULL OR
+		(
+			provisioner_jobs.error IS NOT NULL AND
+			provisioner_jobs.error != ''
+		)
+	) AND workspaces.deleted = 'false'
+`
+
+// Returns the workspaces that aren't running and whose template deletes
+// inactive workspaces. The caller must compute when each workspace is
+// deleted, since the template schedule depends on the license.
+func (q *sqlQuerier) GetWorkspacesMarkedForDeletion(ctx context.Context) ([]Workspace, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesMarkedForDeletion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Workspace
+	for rows.Next() {
+		var i Workspace
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.Deleted,
+			&i.Name,
+			&i.AutostartSchedule,
+			&i.Ttl,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertWorkspace = `-- name: InsertWorkspace :one
 INSERT INTO
 	workspaces (
@@ -8861,3 +10848,176 @@ func (q *sqlQuerier) UpdateWorkspaceTTLToBeWithinTemplateMax(ctx context.Context
 	_, err := q.db.ExecContext(ctx, updateWorkspaceTTLToBeWithinTemplateMax, arg.TemplateMaxTTL, arg.TemplateID)
 	return err
 }
+
+const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
+SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, status, exit_code, started_at, ended_at FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentScript
+	for rows.Next() {
+		var i WorkspaceAgentScript
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.LogPath,
+			&i.CreatedAt,
+			&i.Script,
+			&i.Cron,
+			&i.StartBlocksLogin,
+			&i.RunOnStart,
+			&i.RunOnStop,
+			&i.TimeoutSeconds,
+			&i.Status,
+			&i.ExitCode,
+			&i.StartedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAgentScripts = `-- name: InsertWorkspaceAgentScripts :many
+INSERT INTO
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds)
+SELECT
+	$1 :: uuid AS workspace_agent_id,
+	$2 :: timestamptz AS created_at,
+	unnest($3 :: uuid [ ]) AS log_source_id,
+	unnest($4 :: text [ ]) AS log_path,
+	unnest($5 :: text [ ]) AS script,
+	unnest($6 :: text [ ]) AS cron,
+	unnest($7 :: boolean [ ]) AS start_blocks_login,
+	unnest($8 :: boolean [ ]) AS run_on_start,
+	unnest($9 :: boolean [ ]) AS run_on_stop,
+	unnest($10 :: integer [ ]) AS timeout_seconds
+RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.status, workspace_agent_scripts.exit_code, workspace_agent_scripts.started_at, workspace_agent_scripts.ended_at
+`
+
+type InsertWorkspaceAgentScriptsParams struct {
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	LogSourceID      []uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	LogPath          []string    `db:"log_path" json:"log_path"`
+	Script           []string    `db:"script" json:"script"`
+	Cron             []string    `db:"cron" json:"cron"`
+	StartBlocksLogin []bool      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       []bool      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        []bool      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   []int32     `db:"timeout_seconds" json:"timeout_seconds"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error) {
+	rows, err := q.db.QueryContext(ctx, insertWorkspaceAgentScripts,
+		arg.WorkspaceAgentID,
+		arg.CreatedAt,
+		pq.Array(arg.LogSourceID),
+		pq.Array(arg.LogPath),
+		pq.Array(arg.Script),
+		pq.Array(arg.Cron),
+		pq.Array(arg.StartBlocksLogin),
+		pq.Array(arg.RunOnStart),
+		pq.Array(arg.RunOnStop),
+		pq.Array(arg.TimeoutSeconds),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentScript
+	for rows.Next() {
+		var i WorkspaceAgentScript
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.LogSourceID,
+			&i.LogPath,
+			&i.CreatedAt,
+			&i.Script,
+			&i.Cron,
+			&i.StartBlocksLogin,
+			&i.RunOnStart,
+			&i.RunOnStop,
+			&i.TimeoutSeconds,
+			&i.Status,
+			&i.ExitCode,
+			&i.StartedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkspaceAgentScriptStatus = `-- name: UpdateWorkspaceAgentScriptStatus :one
+UPDATE
+	workspace_agent_scripts
+SET
+	status = $1,
+	exit_code = $2,
+	started_at = $3,
+	ended_at = $4
+WHERE
+	workspace_agent_id = $5
+	AND log_source_id = $6
+RETURNING workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, status, exit_code, started_at, ended_at
+`
+
+type UpdateWorkspaceAgentScriptStatusParams struct {
+	Status           WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	ExitCode         int32                      `db:"exit_code" json:"exit_code"`
+	StartedAt        sql.NullTime               `db:"started_at" json:"started_at"`
+	EndedAt          sql.NullTime               `db:"ended_at" json:"ended_at"`
+	WorkspaceAgentID uuid.UUID                  `db:"workspace_agent_id" json:"workspace_agent_id"`
+	LogSourceID      uuid.UUID                  `db:"log_source_id" json:"log_source_id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentScriptStatus(ctx context.Context, arg UpdateWorkspaceAgentScriptStatusParams) (WorkspaceAgentScript, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceAgentScriptStatus,
+		arg.Status,
+		arg.ExitCode,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.WorkspaceAgentID,
+		arg.LogSourceID,
+	)
+	var i WorkspaceAgentScript
+	err := row.Scan(
+		&i.WorkspaceAgentID,
+		&i.LogSourceID,
+		&i.LogPath,
+		&i.CreatedAt,
+		&i.Script,
+		&i.Cron,
+		&i.StartBlocksLogin,
+		&i.RunOnStart,
+		&i.RunOnStop,
+		&i.TimeoutSeconds,
+		&i.Status,
+		&i.ExitCode,
+		&i.StartedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
@@ -65,11 +65,12 @@ SET
 WHERE
 	id = $1;
 
--- name: DeleteAPIKeyByID :exec
+-- name: DeleteAPIKeyByID :one
 DELETE FROM
 	api_keys
 WHERE
-	id = $1;
+	id = $1
+RETURNING *;
 
 -- name: DeleteApplicationConnectAPIKeysByUserID :exec
 DELETE FROM
@@ -0,0 +1,95 @@
+-- name: GetCustomRoles :many
+-- Returns the site roles if the organization ID is the nil UUID, and the
+-- roles of the organization otherwise.
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid) = @organization_id :: uuid
+ORDER BY
+	name ASC;
+
+-- name: GetCustomRoleByID :one
+SELECT * FROM custom_roles WHERE id = $1;
+
+-- name: GetCustomRoleByName :one
+-- Looks up a site role if the organization ID is the nil UUID, and a role of
+-- the organization otherwise.
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	name = @name
+	AND COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid) = @organization_id :: uuid;
+
+-- name: GetCustomRolesByNames :many
+-- Looks up roles by the names they are assigned with, which are scoped to
+-- their organization for organization roles.
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	(
+		CASE
+			WHEN organization_id IS NULL THEN name
+			ELSE name || ':' || organization_id :: text
+		END
+	) = ANY(@names :: text[]);
+
+-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateCustomRoleByID :one
+UPDATE
+	custom_roles
+SET
+	display_name = $2,
+	site_permissions = $3,
+	org_permissions = $4,
+	user_permissions = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteCustomRoleByID :exec
+DELETE FROM
+	custom_roles
+WHERE
+	id = $1;
+
+-- name: RemoveRoleFromUsers :exec
+-- Removes a site role from the users it is assigned to.
+UPDATE
+	users
+SET
+	rbac_roles = array_remove(rbac_roles, @role_name :: text)
+WHERE
+	@role_name :: text = ANY(rbac_roles);
+
+-- name: RemoveRoleFromOrganizationMembers :exec
+-- Removes an organization role from the members of the organization it is
+-- assigned to.
+UPDATE
+	organization_members
+SET
+	roles = array_remove(roles, @role_name :: text)
+WHERE
+	organization_id = @organization_id
+	AND @role_name :: text = ANY(roles);
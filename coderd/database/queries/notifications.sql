@@ -0,0 +1,87 @@
+-- name: EnqueueNotificationMessage :exec
+-- Notifications that were already enqueued, identified by their dedupe hash,
+-- are ignored.
+INSERT INTO
+	notification_messages (id, user_id, template, method, labels, dedupe_hash, created_at, updated_at, send_after)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7, $7) ON CONFLICT (dedupe_hash) DO NOTHING;
+
+-- name: AcquireNotificationMessages :many
+-- Leases pending messages, and leased messages whose lease expired, to the
+-- caller. Messages leased by other replicas at the same time are skipped.
+UPDATE
+	notification_messages
+SET
+	status = 'leased'::notification_message_status,
+	attempts = attempts + 1,
+	updated_at = @now :: timestamptz,
+	leased_until = @leased_until :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			nm.id
+		FROM
+			notification_messages AS nm
+		WHERE
+			(
+				nm.status = 'pending'::notification_message_status AND
+				nm.send_after <= @now :: timestamptz
+			) OR (
+				nm.status = 'leased'::notification_message_status AND
+				nm.leased_until < @now :: timestamptz
+			)
+		ORDER BY
+			nm.created_at ASC
+		LIMIT
+			@count :: integer
+		FOR UPDATE SKIP LOCKED
+	)
+RETURNING *;
+
+-- name: MarkNotificationMessageSent :exec
+UPDATE
+	notification_messages
+SET
+	status = 'sent'::notification_message_status,
+	updated_at = @updated_at,
+	leased_until = NULL,
+	last_error = NULL
+WHERE
+	id = @id;
+
+-- name: MarkNotificationMessageFailed :exec
+-- Messages that are retried are pending again once send_after is reached.
+UPDATE
+	notification_messages
+SET
+	status = CASE WHEN @retry :: boolean THEN 'pending'::notification_message_status ELSE 'failed'::notification_message_status END,
+	updated_at = @updated_at,
+	send_after = @send_after,
+	leased_until = NULL,
+	last_error = @last_error
+WHERE
+	id = @id;
+
+-- name: DeleteOldNotificationMessages :exec
+DELETE FROM
+	notification_messages
+WHERE
+	status IN ('sent'::notification_message_status, 'failed'::notification_message_status) AND
+	updated_at < @before :: timestamptz;
+
+-- name: GetNotificationMessagesByUserID :many
+SELECT * FROM notification_messages WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: GetNotificationPreferencesByUserID :many
+SELECT * FROM notification_preferences WHERE user_id = $1 ORDER BY template ASC;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO
+	notification_preferences (user_id, template, disabled, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT
+	(user_id, template)
+DO UPDATE SET
+	disabled = $3,
+	updated_at = $4;
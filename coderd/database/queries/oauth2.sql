@@ -0,0 +1,117 @@
+-- name: GetOAuth2ProviderApps :many
+SELECT * FROM oauth2_provider_apps ORDER BY (name, id) ASC;
+
+-- name: GetOAuth2ProviderAppByID :one
+SELECT * FROM oauth2_provider_apps WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppsByUserID :many
+-- Returns the apps the user authorized, which hold API keys of the user.
+SELECT
+	oauth2_provider_apps.*
+FROM
+	oauth2_provider_apps
+WHERE
+	oauth2_provider_apps.id IN (
+		SELECT
+			oauth2_provider_app_tokens.app_id
+		FROM
+			oauth2_provider_app_tokens
+		INNER JOIN
+			api_keys ON api_keys.id = oauth2_provider_app_tokens.api_key_id
+		WHERE
+			api_keys.user_id = @user_id :: uuid
+	)
+ORDER BY (oauth2_provider_apps.name, oauth2_provider_apps.id) ASC;
+
+-- name: InsertOAuth2ProviderApp :one
+INSERT INTO
+	oauth2_provider_apps (id, created_at, updated_at, name, icon, callback_url)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE
+	oauth2_provider_apps
+SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	callback_url = $5
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppByID :exec
+-- Deleting an app revokes the API keys issued to it, which aren't removed by
+-- the cascade from the app to its tokens.
+WITH revoked_keys AS (
+	DELETE FROM
+		api_keys
+	WHERE
+		id IN (SELECT api_key_id FROM oauth2_provider_app_tokens WHERE app_id = @id :: uuid)
+)
+DELETE FROM oauth2_provider_apps WHERE id = @id :: uuid;
+
+-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT * FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC;
+
+-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT * FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretByHashedSecret :one
+SELECT * FROM oauth2_provider_app_secrets WHERE hashed_secret = $1;
+
+-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO
+	oauth2_provider_app_secrets (id, created_at, hashed_secret, display_secret, app_id)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppSecretLastUsedAtByID :exec
+UPDATE
+	oauth2_provider_app_secrets
+SET
+	last_used_at = $2
+WHERE
+	id = $1;
+
+-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT * FROM oauth2_provider_app_codes WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByHashedSecret :one
+SELECT * FROM oauth2_provider_app_codes WHERE hashed_secret = $1;
+
+-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO
+	oauth2_provider_app_codes (id, created_at, expires_at, hashed_secret, user_id, app_id, redirect_uri, scope, code_challenge)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppCodeByID :one
+-- Returns no rows if the code was already deleted, which is how codes are
+-- redeemed only once.
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
+DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2;
+
+-- name: GetOAuth2ProviderAppTokenByHashedRefreshToken :one
+SELECT * FROM oauth2_provider_app_tokens WHERE hashed_refresh_token = $1;
+
+-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO
+	oauth2_provider_app_tokens (id, created_at, expires_at, hashed_refresh_token, app_id, api_key_id)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+-- Revokes the API keys the user issued to the app, their tokens are removed
+-- by the cascade.
+DELETE FROM
+	api_keys
+WHERE
+	user_id = @user_id :: uuid AND
+	id IN (SELECT api_key_id FROM oauth2_provider_app_tokens WHERE app_id = @app_id :: uuid);
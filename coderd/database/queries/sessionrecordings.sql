@@ -0,0 +1,41 @@
+-- name: InsertSessionRecording :one
+INSERT INTO
+	session_recordings (id, workspace_id, workspace_build_id, agent_id, user_id, type, started_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetSessionRecordingByID :one
+SELECT * FROM session_recordings WHERE id = $1;
+
+-- name: GetSessionRecordingsByWorkspaceID :many
+SELECT * FROM session_recordings WHERE workspace_id = $1 ORDER BY started_at DESC;
+
+-- name: InsertSessionRecordingChunk :exec
+-- Chunks may be retried by the agent, so duplicates are ignored.
+INSERT INTO
+	session_recording_chunks (recording_id, sequence, data)
+VALUES
+	($1, $2, $3) ON CONFLICT (recording_id, sequence) DO NOTHING;
+
+-- name: GetSessionRecordingChunksByRecordingID :many
+SELECT * FROM session_recording_chunks WHERE recording_id = $1 ORDER BY sequence ASC;
+
+-- name: UpdateSessionRecordingByID :exec
+UPDATE
+	session_recordings
+SET
+	ended_at = @ended_at,
+	size = (
+		SELECT
+			COALESCE(SUM(length(data)), 0)
+		FROM
+			session_recording_chunks
+		WHERE
+			recording_id = @id
+	)
+WHERE
+	id = @id;
+
+-- name: DeleteOldSessionRecordings :exec
+-- Chunks are deleted with their recording.
+DELETE FROM session_recordings WHERE started_at < NOW() - INTERVAL '30 day';
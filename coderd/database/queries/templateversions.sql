@@ -127,3 +127,20 @@ WHERE
 	AND template_id = $3
 ORDER BY created_at DESC
 LIMIT 1;
+
+-- name: DeleteTemplateVersionWithoutTemplate :exec
+-- Deletes a version that doesn't belong to a template along with its
+-- provisioner job. Versions of templates are never deleted.
+WITH deleted AS (
+	DELETE FROM
+		template_versions
+	WHERE
+		template_versions.id = @id :: uuid
+		AND template_versions.template_id IS NULL
+	RETURNING
+		template_versions.job_id
+)
+DELETE FROM
+	provisioner_jobs
+WHERE
+	provisioner_jobs.id IN (SELECT job_id FROM deleted);
@@ -41,6 +41,19 @@ FROM
 WHERE
     status = 'active'::user_status AND deleted = false;
 
+-- name: GetActiveUsersByRoleForUpdate :many
+-- Locks the active users with the site role, so none of them loses the role
+-- or is suspended until the transaction ends.
+SELECT
+	*
+FROM
+	users
+WHERE
+	status = 'active'::user_status
+	AND deleted = false
+	AND @role_name :: text = ANY(rbac_roles)
+FOR UPDATE;
+
 -- name: GetFilteredUserCount :one
 -- This will never count deleted users.
 SELECT
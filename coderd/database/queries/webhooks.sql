@@ -0,0 +1,106 @@
+-- name: GetWebhooks :many
+SELECT * FROM webhooks ORDER BY name ASC;
+
+-- name: GetWebhookByID :one
+SELECT * FROM webhooks WHERE id = $1;
+
+-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (id, name, endpoint, secret, events, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateWebhookByID :one
+UPDATE
+	webhooks
+SET
+	name = $2,
+	endpoint = $3,
+	secret = $4,
+	events = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks WHERE id = $1;
+
+-- name: EnqueueWebhookDeliveries :many
+-- Enqueues a delivery of the event to every webhook that subscribed to it.
+INSERT INTO
+	webhook_deliveries (id, webhook_id, event, payload, created_at, updated_at, send_after)
+SELECT
+	gen_random_uuid(), webhooks.id, @event :: webhook_event, @payload :: jsonb, @created_at :: timestamptz, @created_at :: timestamptz, @created_at :: timestamptz
+FROM
+	webhooks
+WHERE
+	@event :: webhook_event = ANY(webhooks.events)
+RETURNING *;
+
+-- name: AcquireWebhookDeliveries :many
+-- Leases pending deliveries, and leased deliveries whose lease expired, to the
+-- caller. Deliveries leased by other replicas at the same time are skipped.
+UPDATE
+	webhook_deliveries
+SET
+	status = 'leased'::webhook_delivery_status,
+	attempts = attempts + 1,
+	updated_at = @now :: timestamptz,
+	leased_until = @leased_until :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			wd.id
+		FROM
+			webhook_deliveries AS wd
+		WHERE
+			(
+				wd.status = 'pending'::webhook_delivery_status AND
+				wd.send_after <= @now :: timestamptz
+			) OR (
+				wd.status = 'leased'::webhook_delivery_status AND
+				wd.leased_until < @now :: timestamptz
+			)
+		ORDER BY
+			wd.created_at ASC
+		LIMIT
+			@count :: integer
+		FOR UPDATE SKIP LOCKED
+	)
+RETURNING *;
+
+-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = @status,
+	status_code = @status_code,
+	last_error = @last_error,
+	updated_at = @updated_at,
+	send_after = @send_after,
+	leased_until = NULL
+WHERE
+	id = @id;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC
+OFFSET
+	@offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM
+	webhook_deliveries
+WHERE
+	status IN ('succeeded'::webhook_delivery_status, 'failed'::webhook_delivery_status) AND
+	updated_at < @before :: timestamptz;
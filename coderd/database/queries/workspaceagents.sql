@@ -116,10 +116,11 @@ INSERT INTO
 		key,
 		script,
 		timeout,
-		interval
+		interval,
+		collector
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6);
+	($1, $2, $3, $4, $5, $6, $7);
 
 -- name: UpdateWorkspaceAgentMetadata :exec
 UPDATE
@@ -140,6 +141,27 @@ FROM
 WHERE
 	workspace_agent_id = $1;
 
+-- name: UpsertWorkspaceAgentProcesses :exec
+INSERT INTO
+	workspace_agent_processes (
+		workspace_agent_id,
+		collected_at,
+		processes
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (workspace_agent_id) DO UPDATE SET
+	collected_at = $2,
+	processes = $3;
+
+-- name: GetWorkspaceAgentProcesses :one
+SELECT
+	*
+FROM
+	workspace_agent_processes
+WHERE
+	workspace_agent_id = $1;
+
 -- name: UpdateWorkspaceAgentStartupLogOverflowByID :exec
 UPDATE
 	workspace_agents
@@ -165,12 +187,13 @@ WITH new_length AS (
 	startup_logs_length = startup_logs_length + @output_length WHERE workspace_agents.id = @agent_id
 )
 INSERT INTO
-		workspace_agent_startup_logs (agent_id, created_at, output, level)
+		workspace_agent_startup_logs (agent_id, created_at, output, level, log_source_id)
 	SELECT
 		@agent_id :: uuid AS agent_id,
 		unnest(@created_at :: timestamptz [ ]) AS created_at,
 		unnest(@output :: VARCHAR(1024) [ ]) AS output,
-		unnest(@level :: log_level [ ]) AS level
+		unnest(@level :: log_level [ ]) AS level,
+		@log_source_id :: uuid AS log_source_id
 	RETURNING workspace_agent_startup_logs.*;
 
 -- If an agent hasn't connected in the last 7 days, we purge it's logs.
@@ -199,3 +222,17 @@ WHERE
     	WHERE
 			wb.workspace_id = @workspace_id :: uuid
 	);
+
+-- name: InsertWorkspaceAgentLogSources :many
+INSERT INTO
+		workspace_agent_log_sources (workspace_agent_id, created_at, id, display_name, icon)
+	SELECT
+		@workspace_agent_id :: uuid AS workspace_agent_id,
+		@created_at :: timestamptz AS created_at,
+		unnest(@id :: uuid [ ]) AS id,
+		unnest(@display_name :: VARCHAR(127) [ ]) AS display_name,
+		unnest(@icon :: text [ ]) AS icon
+	RETURNING workspace_agent_log_sources.*;
+
+-- name: GetWorkspaceAgentLogSourcesByAgentIDs :many
+SELECT * FROM workspace_agent_log_sources WHERE workspace_agent_id = ANY(@ids :: uuid [ ]);
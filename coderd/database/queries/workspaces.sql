@@ -453,3 +453,36 @@ WHERE
 			workspace_builds.transition = 'start'::workspace_transition
 		)
 	) AND workspaces.deleted = 'false';
+
+-- name: GetWorkspacesMarkedForDeletion :many
+-- Returns the workspaces that aren't running and whose template deletes
+-- inactive workspaces. The caller must compute when each workspace is
+-- deleted, since the template schedule depends on the license.
+SELECT
+	workspaces.*
+FROM
+	workspaces
+INNER JOIN
+	templates ON templates.id = workspaces.template_id
+INNER JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+	) AND
+	templates.inactivity_ttl > 0 AND
+	(
+		workspace_builds.transition = 'stop'::workspace_transition OR
+		provisioner_jobs.canceled_at IS NOT NULL OR
+		(
+			provisioner_jobs.error IS NOT NULL AND
+			provisioner_jobs.error != ''
+		)
+	) AND workspaces.deleted = 'false';
@@ -45,6 +45,27 @@ func (t TemplateACL) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
 
+// CustomRolePermissions are the permissions of a custom role at one level.
+type CustomRolePermissions []rbac.Permission
+
+func (p *CustomRolePermissions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &p)
+	case []byte:
+		return json.Unmarshal(v, &p)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (p CustomRolePermissions) Value() (driver.Value, error) {
+	if p == nil {
+		// The columns are not nullable.
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p)
+}
+
 type StringMap map[string]string
 
 func (m *StringMap) Scan(src interface{}) error {
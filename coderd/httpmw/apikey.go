@@ -22,6 +22,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -371,12 +372,22 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	// Custom roles are defined in the database, so the roles are expanded
+	// here instead of by the authorizer.
+	expanded, err := rolestore.Expand(ctx, cfg.DB, roles.Roles)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding user's roles. %s", err.Error()),
+		})
+	}
+
 	// Actor is the user's authorization context.
 	authz := Authorization{
 		ActorName: roles.Username,
 		Actor: rbac.Subject{
 			ID:     key.UserID.String(),
-			Roles:  rbac.RoleNames(roles.Roles),
+			Roles:  expanded,
 			Groups: roles.Groups,
 			Scope:  rbac.ScopeName(key.Scope),
 		}.WithCachedASTValue(),
@@ -416,6 +427,23 @@ func APITokenFromRequest(r *http.Request) string {
 	return ""
 }
 
+// APITokenOrBearerFromRequest returns the api token from the request like
+// APITokenFromRequest, and falls back to the "Authorization: Bearer" header
+// OAuth2 provider apps send their access tokens in. Workspace apps may use the
+// header for themselves, so APITokenFromRequest doesn't read it.
+func APITokenOrBearerFromRequest(r *http.Request) string {
+	token := APITokenFromRequest(r)
+	if token != "" {
+		return token
+	}
+
+	scheme, bearer, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "bearer") {
+		return strings.TrimSpace(bearer)
+	}
+	return ""
+}
+
 // SplitAPIToken verifies the format of an API key and returns the split ID and
 // secret.
 //
@@ -0,0 +1,59 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+type customRoleParamContextKey struct{}
+
+// CustomRoleParam returns the custom role from the ExtractCustomRoleParam
+// handler.
+func CustomRoleParam(r *http.Request) database.CustomRole {
+	role, ok := r.Context().Value(customRoleParamContextKey{}).(database.CustomRole)
+	if !ok {
+		panic("developer error: custom role param middleware not provided")
+	}
+	return role
+}
+
+// ExtractCustomRoleParam grabs a custom role from the "role" URL parameter.
+// Below ExtractOrganizationParam it grabs a role of the organization, and a
+// site role otherwise.
+func ExtractCustomRoleParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			organizationID := uuid.Nil
+			if organization, ok := ctx.Value(organizationParamContextKey{}).(database.Organization); ok {
+				organizationID = organization.ID
+			}
+
+			role, err := db.GetCustomRoleByName(ctx, database.GetCustomRoleByNameParams{
+				Name:           chi.URLParam(r, "role"),
+				OrganizationID: organizationID,
+			})
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching custom role.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, customRoleParamContextKey{}, role)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -99,6 +100,10 @@ func getAgentSubject(ctx context.Context, db database.Store, agent database.Work
 	if err != nil {
 		return rbac.Subject{}, err
 	}
+	expanded, err := rolestore.Expand(ctx, db, roles.Roles)
+	if err != nil {
+		return rbac.Subject{}, err
+	}
 
 	// A user that creates a workspace can use this agent auth token and
 	// impersonate the workspace. So to prevent privilege escalation, the
@@ -107,7 +112,7 @@ func getAgentSubject(ctx context.Context, db database.Store, agent database.Work
 	// to only what the workspace agent needs.
 	return rbac.Subject{
 		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
+		Roles:  expanded,
 		Groups: roles.Groups,
 		Scope:  rbac.WorkspaceAgentScope(workspace.ID, user.ID),
 	}.WithCachedASTValue(), nil
@@ -44,6 +44,13 @@ func (api *API) putMemberRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if user.LoginType == database.LoginTypeOIDC && api.OIDCConfig.RoleSyncEnabled() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The roles of OIDC users are synced from the identity provider at each login, and cannot be changed in Coder.",
+		})
+		return
+	}
+
 	var params codersdk.UpdateRoles
 	if !httpapi.Read(ctx, rw, r, &params) {
 		return
@@ -82,12 +89,21 @@ func (api *API) updateOrganizationMemberRoles(ctx context.Context, args database
 			return database.OrganizationMember{}, xerrors.Errorf("Must only pass roles for org %q", args.OrgID.String())
 		}
 
-		if _, err := rbac.RoleByName(r); err != nil {
+		// Custom roles are looked up when they are assigned.
+		if _, err := rbac.RoleByName(r); err != nil && rbac.IsBuiltInRoleName(r) {
 			return database.OrganizationMember{}, xerrors.Errorf("%q is not a supported role", r)
 		}
 	}
 
-	updatedUser, err := api.Database.UpdateMemberRoles(ctx, args)
+	var updatedUser database.OrganizationMember
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.LockIDRoleAssignments)
+		if err != nil {
+			return xerrors.Errorf("acquire role assignments lock: %w", err)
+		}
+		updatedUser, err = tx.UpdateMemberRoles(ctx, args)
+		return err
+	}, nil)
 	if err != nil {
 		return database.OrganizationMember{}, xerrors.Errorf("Update site roles: %w", err)
 	}
@@ -104,8 +120,7 @@ func convertOrganizationMember(mem database.OrganizationMember) codersdk.Organiz
 	}
 
 	for _, roleName := range mem.Roles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedMember.Roles = append(convertedMember.Roles, db2sdk.Role(rbacRole))
+		convertedMember.Roles = append(convertedMember.Roles, db2sdk.RoleFromName(roleName))
 	}
 	return convertedMember
 }
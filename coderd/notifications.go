@@ -0,0 +1,120 @@
+package coderd
+
+import (
+	"net/http"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get user notification preferences
+// @ID get-user-notification-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [get]
+func (api *API) userNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+	preferences, err := api.Database.GetNotificationPreferencesByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(preferences))
+}
+
+// @Summary Update user notification preferences
+// @ID update-user-notification-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateNotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [put]
+func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+		req  codersdk.UpdateNotificationPreferencesRequest
+	)
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	for _, preference := range req.Preferences {
+		if !slices.Contains(codersdk.NotificationTemplates, preference.Template) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid notification preferences.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "template",
+					Detail: "unknown notification template " + string(preference.Template),
+				}},
+			})
+			return
+		}
+	}
+
+	var preferences []database.NotificationPreference
+	err := api.Database.InTx(func(tx database.Store) error {
+		for _, preference := range req.Preferences {
+			err := tx.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+				UserID:    user.ID,
+				Template:  string(preference.Template),
+				Disabled:  preference.Disabled,
+				UpdatedAt: database.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		preferences, err = tx.GetNotificationPreferencesByUserID(ctx, user.ID)
+		return err
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(preferences))
+}
+
+// convertNotificationPreferences returns the preference for every template,
+// templates without a stored preference are enabled.
+func convertNotificationPreferences(preferences []database.NotificationPreference) []codersdk.NotificationPreference {
+	disabled := map[string]bool{}
+	for _, preference := range preferences {
+		disabled[preference.Template] = preference.Disabled
+	}
+	converted := make([]codersdk.NotificationPreference, 0, len(codersdk.NotificationTemplates))
+	for _, template := range codersdk.NotificationTemplates {
+		converted = append(converted, codersdk.NotificationPreference{
+			Template: template,
+			Disabled: disabled[string(template)],
+		})
+	}
+	return converted
+}
@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// Dispatcher sends rendered notifications by a method, e.g. email.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, msg Message) error
+}
+
+// Message is a notification rendered for its recipient.
+type Message struct {
+	ID        uuid.UUID
+	Template  codersdk.NotificationTemplate
+	UserID    uuid.UUID
+	Username  string
+	Email     string
+	Title     string
+	Body      string
+	Labels    map[string]string
+	CreatedAt time.Time
+}
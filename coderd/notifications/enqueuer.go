@@ -0,0 +1,173 @@
+// Package notifications sends users notifications about their workspaces and
+// account by email and webhook. Notifications are enqueued in the database,
+// and sent by a Manager on any replica, so they survive restarts and are
+// retried when sending fails.
+package notifications
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/codersdk"
+)
+
+// Enqueuer enqueues notifications to be sent to users.
+type Enqueuer interface {
+	// Enqueue enqueues a notification to a user, unless the user disabled
+	// the template. The labels are the values the template is rendered with.
+	// Identical notifications are only sent once, so labels should identify
+	// the event, e.g. by a build ID.
+	Enqueue(ctx context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error
+	// EnqueueWithStore is like Enqueue, but enqueues using the store passed,
+	// so a notification can be enqueued in the transaction that caused it.
+	EnqueueWithStore(ctx context.Context, db database.Store, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error
+}
+
+// NewNoopEnqueuer returns an Enqueuer that drops all notifications, used when
+// no notification method is configured.
+func NewNoopEnqueuer() Enqueuer {
+	return noopEnqueuer{}
+}
+
+type noopEnqueuer struct{}
+
+func (noopEnqueuer) Enqueue(context.Context, uuid.UUID, codersdk.NotificationTemplate, map[string]string) error {
+	return nil
+}
+
+func (noopEnqueuer) EnqueueWithStore(context.Context, database.Store, uuid.UUID, codersdk.NotificationTemplate, map[string]string) error {
+	return nil
+}
+
+// NewMock returns an Enqueuer that records notifications, for tests.
+func NewMock() *MockEnqueuer {
+	return &MockEnqueuer{}
+}
+
+// MockNotification is a notification recorded by MockEnqueuer.
+type MockNotification struct {
+	UserID   uuid.UUID
+	Template codersdk.NotificationTemplate
+	Labels   map[string]string
+}
+
+type MockEnqueuer struct {
+	mutex         sync.Mutex
+	notifications []MockNotification
+}
+
+func (e *MockEnqueuer) Notifications() []MockNotification {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	notifications := make([]MockNotification, len(e.notifications))
+	copy(notifications, e.notifications)
+	return notifications
+}
+
+func (e *MockEnqueuer) Enqueue(_ context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.notifications = append(e.notifications, MockNotification{
+		UserID:   userID,
+		Template: template,
+		Labels:   labels,
+	})
+	return nil
+}
+
+func (e *MockEnqueuer) EnqueueWithStore(ctx context.Context, _ database.Store, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error {
+	return e.Enqueue(ctx, userID, template, labels)
+}
+
+// StoreEnqueuer enqueues notifications in the database, once for every
+// method notifications are sent by.
+type StoreEnqueuer struct {
+	db      database.Store
+	log     slog.Logger
+	methods []database.NotificationMethod
+}
+
+// NewStoreEnqueuer returns an Enqueuer that enqueues notifications in the
+// database to be sent by the methods.
+func NewStoreEnqueuer(db database.Store, log slog.Logger, methods ...database.NotificationMethod) *StoreEnqueuer {
+	return &StoreEnqueuer{
+		db:      db,
+		log:     log.Named("notifications"),
+		methods: methods,
+	}
+}
+
+func (e *StoreEnqueuer) Enqueue(ctx context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error {
+	return e.EnqueueWithStore(ctx, e.db, userID, template, labels)
+}
+
+func (e *StoreEnqueuer) EnqueueWithStore(ctx context.Context, db database.Store, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error {
+	if _, ok := templates[template]; !ok {
+		return xerrors.Errorf("unknown notification template %q", template)
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	//nolint:gocritic // Notifications are enqueued by the system on behalf of users.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	preferences, err := db.GetNotificationPreferencesByUserID(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf("get notification preferences: %w", err)
+	}
+	for _, preference := range preferences {
+		if preference.Template == string(template) && preference.Disabled {
+			e.log.Debug(ctx, "skip disabled notification", slog.F("user_id", userID), slog.F("template", template))
+			return nil
+		}
+	}
+
+	now := database.Now()
+	for _, method := range e.methods {
+		err := db.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Template:   string(template),
+			Method:     method,
+			Labels:     labels,
+			DedupeHash: dedupeHash(userID, template, method, labels),
+			CreatedAt:  now,
+		})
+		if err != nil {
+			return xerrors.Errorf("enqueue %s notification: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// dedupeHash identifies a notification by its recipient, template, method
+// and labels.
+func dedupeHash(userID uuid.UUID, template codersdk.NotificationTemplate, method database.NotificationMethod, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, part := range []string{userID.String(), string(template), string(method)} {
+		_, _ = h.Write([]byte(part))
+		_, _ = h.Write([]byte{0})
+	}
+	for _, key := range keys {
+		_, _ = h.Write([]byte(key))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(labels[key]))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,173 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/queue"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// FetchInterval is how often the manager sends pending notifications.
+	FetchInterval = 5 * time.Second
+	// LeasePeriod is how long a replica may take to send a notification
+	// before another replica sends it again.
+	LeasePeriod = 2 * time.Minute
+	// RetryInterval times the number of attempts is how long the manager
+	// waits before retrying a notification that failed to send.
+	RetryInterval = time.Minute
+	// Retention is how long sent and failed notifications are kept, within
+	// it identical notifications are only sent once.
+	Retention = 30 * 24 * time.Hour
+	// MaxMessagesPerRun is the maximum number of notifications sent on a
+	// tick.
+	MaxMessagesPerRun = 50
+
+	dispatchTimeout = 30 * time.Second
+)
+
+// permanentError is a failure that won't be fixed by retrying, e.g. a
+// template that can't be rendered.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Manager sends the notifications enqueued in the database with the
+// dispatcher of their method.
+type Manager = queue.Manager[database.NotificationMessage]
+
+// Stats contains the notifications handled in one run of the Manager.
+type Stats = queue.Stats
+
+// NewManager returns a Manager that sends notifications on every tick, and
+// gives up on a notification after maxAttempts.
+func NewManager(ctx context.Context, db database.Store, log slog.Logger, accessURL *url.URL, dispatchers map[database.NotificationMethod]Dispatcher, maxAttempts int, tick <-chan time.Time) *Manager {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	log = log.Named("notifications")
+	//nolint:gocritic // The manager sends the notifications of all users.
+	return queue.NewManager[database.NotificationMessage](dbauthz.AsSystemRestricted(ctx), log, &handler{
+		db:          db,
+		log:         log,
+		accessURL:   accessURL,
+		dispatchers: dispatchers,
+		maxAttempts: maxAttempts,
+	}, queue.Options{
+		LeasePeriod:    LeasePeriod,
+		Retention:      Retention,
+		MaxItemsPerRun: MaxMessagesPerRun,
+		Tick:           tick,
+	})
+}
+
+// handler sends notification messages for the queue.
+type handler struct {
+	db          database.Store
+	log         slog.Logger
+	accessURL   *url.URL
+	dispatchers map[database.NotificationMethod]Dispatcher
+	maxAttempts int
+}
+
+func (*handler) ID(message database.NotificationMessage) uuid.UUID {
+	return message.ID
+}
+
+func (h *handler) Acquire(ctx context.Context, now, leasedUntil time.Time, count int32) ([]database.NotificationMessage, error) {
+	return h.db.AcquireNotificationMessages(ctx, database.AcquireNotificationMessagesParams{
+		Now:         now,
+		LeasedUntil: leasedUntil,
+		Count:       count,
+	})
+}
+
+func (h *handler) Purge(ctx context.Context, before time.Time) error {
+	return h.db.DeleteOldNotificationMessages(ctx, before)
+}
+
+func (h *handler) Handle(ctx context.Context, message database.NotificationMessage) queue.Result {
+	log := h.log.With(slog.F("notification_id", message.ID), slog.F("template", message.Template), slog.F("method", message.Method))
+	sendErr := h.send(ctx, message)
+	if sendErr == nil {
+		err := h.db.MarkNotificationMessageSent(ctx, database.MarkNotificationMessageSentParams{
+			ID:        message.ID,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			log.Warn(ctx, "mark notification sent", slog.Error(err))
+		}
+		return queue.Succeeded
+	}
+
+	retry := int(message.Attempts) < h.maxAttempts && !xerrors.As(sendErr, &permanentError{})
+	now := database.Now()
+	err := h.db.MarkNotificationMessageFailed(ctx, database.MarkNotificationMessageFailedParams{
+		ID:        message.ID,
+		Retry:     retry,
+		UpdatedAt: now,
+		SendAfter: now.Add(time.Duration(message.Attempts) * RetryInterval),
+		LastError: sql.NullString{String: sendErr.Error(), Valid: true},
+	})
+	if err != nil {
+		log.Warn(ctx, "mark notification failed", slog.Error(err))
+	}
+	if retry {
+		log.Info(ctx, "send notification, will retry", slog.F("attempts", message.Attempts), slog.Error(sendErr))
+		return queue.Retried
+	}
+	log.Warn(ctx, "send notification", slog.F("attempts", message.Attempts), slog.Error(sendErr))
+	return queue.Failed
+}
+
+func (h *handler) send(ctx context.Context, message database.NotificationMessage) error {
+	dispatcher, ok := h.dispatchers[message.Method]
+	if !ok {
+		return permanentError{xerrors.Errorf("notification method %q is not configured", message.Method)}
+	}
+	user, err := h.db.GetUserByID(ctx, message.UserID)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	template := codersdk.NotificationTemplate(message.Template)
+	title, body, err := render(template, templateData{
+		Username:  user.Username,
+		AccessURL: strings.TrimSuffix(h.accessURL.String(), "/"),
+		Labels:    message.Labels,
+	})
+	if err != nil {
+		return permanentError{err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dispatchTimeout)
+	defer cancel()
+	return dispatcher.Dispatch(ctx, Message{
+		ID:        message.ID,
+		Template:  template,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Title:     title,
+		Body:      body,
+		Labels:    message.Labels,
+		CreatedAt: message.CreatedAt,
+	})
+}
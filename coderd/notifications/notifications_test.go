@@ -0,0 +1,309 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/coderd/notifications"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = testutil.Context(t, testutil.WaitLong)
+		db, _ = dbtestutil.NewDB(t)
+		log   = slogtest.Make(t, nil)
+		user  = dbgen.User(t, db, database.User{})
+	)
+	enqueuer := notifications.NewStoreEnqueuer(db, log, database.NotificationMethodEmail, database.NotificationMethodWebhook)
+	labels := map[string]string{"workspace": "dev", "template": "docker"}
+
+	// Identical notifications are only enqueued once.
+	for i := 0; i < 2; i++ {
+		err := enqueuer.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceAutostopped, labels)
+		require.NoError(t, err)
+	}
+	messages := notificationMessages(ctx, t, db, user.ID)
+	require.Len(t, messages, 2)
+	methods := []database.NotificationMethod{messages[0].Method, messages[1].Method}
+	require.ElementsMatch(t, []database.NotificationMethod{database.NotificationMethodEmail, database.NotificationMethodWebhook}, methods)
+	for _, message := range messages {
+		require.Equal(t, database.NotificationMessageStatusPending, message.Status)
+		require.EqualValues(t, labels, message.Labels)
+	}
+
+	// Disabled notifications aren't enqueued.
+	err := db.UpsertNotificationPreference(dbauthz.AsSystemRestricted(ctx), database.UpsertNotificationPreferenceParams{
+		UserID:    user.ID,
+		Template:  string(codersdk.NotificationTemplateWorkspaceBuildFailed),
+		Disabled:  true,
+		UpdatedAt: database.Now(),
+	})
+	require.NoError(t, err)
+	err = enqueuer.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceBuildFailed, labels)
+	require.NoError(t, err)
+	require.Len(t, notificationMessages(ctx, t, db, user.ID), 2)
+
+	err = enqueuer.Enqueue(ctx, user.ID, "unknown", labels)
+	require.ErrorContains(t, err, "unknown notification template")
+}
+
+func TestManager_Webhook(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = testutil.Context(t, testutil.WaitLong)
+		db, _    = dbtestutil.NewDB(t)
+		log      = slogtest.Make(t, nil)
+		tickCh   = make(chan time.Time)
+		statsCh  = make(chan notifications.Stats)
+		user     = dbgen.User(t, db, database.User{})
+		requests atomic.Int64
+		payloads = make(chan notifications.WebhookPayload, 1)
+	)
+	// The endpoint fails the first request, so the notification is retried.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload notifications.WebhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	endpoint, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	enqueuer := notifications.NewStoreEnqueuer(db, log, database.NotificationMethodWebhook)
+	err = enqueuer.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceAutostopped, map[string]string{
+		"workspace": "dev",
+		"template":  "docker",
+	})
+	require.NoError(t, err)
+
+	manager := notifications.NewManager(ctx, db, log, &url.URL{Scheme: "https", Host: "coder.example.com"}, map[database.NotificationMethod]notifications.Dispatcher{
+		database.NotificationMethodWebhook: notifications.NewWebhookDispatcher(endpoint, srv.Client()),
+	}, 3, tickCh).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	tickCh <- time.Now()
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Retried, 1)
+	require.Empty(t, stats.Succeeded)
+
+	// Retries wait for the retry interval.
+	tickCh <- time.Now()
+	stats = <-statsCh
+	require.Empty(t, stats.Succeeded)
+
+	tickCh <- time.Now().Add(2 * notifications.RetryInterval)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Succeeded, 1)
+
+	payload := <-payloads
+	require.Equal(t, stats.Succeeded[0], payload.ID)
+	require.Equal(t, codersdk.NotificationTemplateWorkspaceAutostopped, payload.Template)
+	require.Equal(t, user.Username, payload.Username)
+	require.Equal(t, user.Email, payload.Email)
+	require.Equal(t, `Workspace "dev" was stopped`, payload.Title)
+	require.Contains(t, payload.Body, "https://coder.example.com/@"+user.Username+"/dev")
+
+	messages := notificationMessages(ctx, t, db, user.ID)
+	require.Len(t, messages, 1)
+	require.Equal(t, database.NotificationMessageStatusSent, messages[0].Status)
+	require.EqualValues(t, 2, messages[0].Attempts)
+}
+
+func TestManager_SMTP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitLong)
+		db, _   = dbtestutil.NewDB(t)
+		log     = slogtest.Make(t, nil)
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan notifications.Stats)
+		user    = dbgen.User(t, db, database.User{})
+	)
+	smarthost, emails := smtpServer(t)
+	dispatcher, err := notifications.NewSMTPDispatcher(codersdk.NotificationsEmailConfig{
+		From:      "Coder <coder@example.com>",
+		Smarthost: clibase.String(smarthost),
+		Hello:     "coder.example.com",
+		Username:  "coder",
+		Password:  "hunter2",
+	})
+	require.NoError(t, err)
+
+	enqueuer := notifications.NewStoreEnqueuer(db, log, database.NotificationMethodEmail)
+	err = enqueuer.Enqueue(ctx, user.ID, codersdk.NotificationTemplateUserSuspended, nil)
+	require.NoError(t, err)
+
+	manager := notifications.NewManager(ctx, db, log, &url.URL{Scheme: "https", Host: "coder.example.com"}, map[database.NotificationMethod]notifications.Dispatcher{
+		database.NotificationMethodEmail: dispatcher,
+	}, 1, tickCh).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	tickCh <- time.Now()
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Succeeded, 1)
+
+	email := <-emails
+	require.Equal(t, "coder@example.com", email.from)
+	require.Equal(t, user.Email, email.to)
+	require.Equal(t, "\x00coder\x00hunter2", email.auth)
+	require.Contains(t, email.data, "Subject: Your Coder account was suspended\n")
+	require.Contains(t, email.data, "From: \"Coder\" <coder@example.com>\n")
+	_, encoded, _ := strings.Cut(email.data, "\n\n")
+	body, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(encoded)))
+	require.NoError(t, err)
+	require.Contains(t, string(body), "Your account "+user.Username+" at https://coder.example.com was suspended.")
+}
+
+func TestManager_PermanentFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitLong)
+		db, _   = dbtestutil.NewDB(t)
+		log     = slogtest.Make(t, nil)
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan notifications.Stats)
+		user    = dbgen.User(t, db, database.User{})
+	)
+	// The workspace label is missing, so the notification can't be
+	// rendered, which isn't retried.
+	enqueuer := notifications.NewStoreEnqueuer(db, log, database.NotificationMethodWebhook)
+	err := enqueuer.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceAutostopped, nil)
+	require.NoError(t, err)
+
+	manager := notifications.NewManager(ctx, db, log, &url.URL{Scheme: "https", Host: "coder.example.com"}, map[database.NotificationMethod]notifications.Dispatcher{
+		database.NotificationMethodWebhook: notifications.NewWebhookDispatcher(&url.URL{Scheme: "http", Host: "127.0.0.1:1"}, http.DefaultClient),
+	}, 5, tickCh).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	tickCh <- time.Now()
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Failed, 1)
+
+	messages := notificationMessages(ctx, t, db, user.ID)
+	require.Len(t, messages, 1)
+	require.Equal(t, database.NotificationMessageStatusFailed, messages[0].Status)
+	require.Contains(t, messages[0].LastError.String, "render title")
+}
+
+func notificationMessages(ctx context.Context, t *testing.T, db database.Store, userID uuid.UUID) []database.NotificationMessage {
+	t.Helper()
+	messages, err := db.GetNotificationMessagesByUserID(dbauthz.AsSystemRestricted(ctx), userID)
+	require.NoError(t, err)
+	return messages
+}
+
+type smtpEmail struct {
+	auth string
+	from string
+	to   string
+	data string
+}
+
+// smtpServer is a stand-in for an SMTP server that accepts a single email.
+func smtpServer(t *testing.T) (string, <-chan smtpEmail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	emails := make(chan smtpEmail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var email smtpEmail
+		reply := func(format string, args ...any) {
+			_ = tp.PrintfLine(format, args...)
+		}
+		reply("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				_, encoded, _ := strings.Cut(arg, " ")
+				auth, _ := base64.StdEncoding.DecodeString(encoded)
+				email.auth = string(auth)
+				reply("235 Authenticated")
+			case "MAIL":
+				email.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+				reply("250 OK")
+			case "RCPT":
+				email.to = strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+				reply("250 OK")
+			case "DATA":
+				reply("354 Go ahead")
+				// The dot reader converts line endings to \n.
+				data, err := io.ReadAll(tp.DotReader())
+				if err != nil {
+					return
+				}
+				email.data = string(data)
+				reply("250 Queued")
+			case "QUIT":
+				reply("221 Bye")
+				emails <- email
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), emails
+}
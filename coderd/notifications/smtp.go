@@ -0,0 +1,151 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// SMTPDispatcher sends notifications as plain text emails through an SMTP
+// server.
+type SMTPDispatcher struct {
+	from      *mail.Address
+	smarthost string
+	host      string
+	hello     string
+	username  string
+	password  string
+	// tlsConfig is used for STARTTLS, tests replace it to trust their
+	// server.
+	tlsConfig *tls.Config
+}
+
+// NewSMTPDispatcher returns a Dispatcher that sends emails as configured.
+func NewSMTPDispatcher(cfg codersdk.NotificationsEmailConfig) (*SMTPDispatcher, error) {
+	from, err := mail.ParseAddress(cfg.From.String())
+	if err != nil {
+		return nil, xerrors.Errorf("parse from address %q: %w", cfg.From.String(), err)
+	}
+	host, _, err := net.SplitHostPort(cfg.Smarthost.String())
+	if err != nil {
+		return nil, xerrors.Errorf("parse smarthost %q: %w", cfg.Smarthost.String(), err)
+	}
+	hello := cfg.Hello.String()
+	if hello == "" {
+		hello = "localhost"
+	}
+	return &SMTPDispatcher{
+		from:      from,
+		smarthost: cfg.Smarthost.String(),
+		host:      host,
+		hello:     hello,
+		username:  cfg.Username.String(),
+		password:  cfg.Password.String(),
+		tlsConfig: &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		},
+	}, nil
+}
+
+func (d *SMTPDispatcher) Dispatch(ctx context.Context, msg Message) error {
+	if msg.Email == "" {
+		return permanentError{xerrors.Errorf("user %q has no email address", msg.Username)}
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", d.smarthost)
+	if err != nil {
+		return xerrors.Errorf("dial smarthost: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, d.host)
+	if err != nil {
+		_ = conn.Close()
+		return xerrors.Errorf("connect to smarthost: %w", err)
+	}
+	defer client.Close()
+
+	err = client.Hello(d.hello)
+	if err != nil {
+		return xerrors.Errorf("hello: %w", err)
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(d.tlsConfig)
+		if err != nil {
+			return xerrors.Errorf("starttls: %w", err)
+		}
+	}
+	if d.username != "" {
+		// PlainAuth refuses to send the password without TLS, unless the
+		// smarthost is on localhost.
+		err = client.Auth(smtp.PlainAuth("", d.username, d.password, d.host))
+		if err != nil {
+			return xerrors.Errorf("authenticate: %w", err)
+		}
+	}
+	err = client.Mail(d.from.Address)
+	if err != nil {
+		return xerrors.Errorf("mail from: %w", err)
+	}
+	err = client.Rcpt(msg.Email)
+	if err != nil {
+		return xerrors.Errorf("rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return xerrors.Errorf("data: %w", err)
+	}
+	err = d.writeEmail(w, msg)
+	if err != nil {
+		_ = w.Close()
+		return xerrors.Errorf("write email: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return xerrors.Errorf("send email: %w", err)
+	}
+	return client.Quit()
+}
+
+func (d *SMTPDispatcher) writeEmail(w io.Writer, msg Message) error {
+	to := mail.Address{Name: msg.Username, Address: msg.Email}
+	headers := [][2]string{
+		{"From", d.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Title)},
+		{"Date", msg.CreatedAt.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", msg.ID, d.hello)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		_, err := fmt.Fprintf(w, "%s: %s\r\n", header[0], header[1])
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, "\r\n")
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(w)
+	_, err = qp.Write([]byte(msg.Body))
+	if err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// messageTemplate renders the title and body of a notification.
+type messageTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// templateData is what templates are rendered with.
+type templateData struct {
+	// Username is the recipient of the notification.
+	Username  string
+	AccessURL string
+	Labels    map[string]string
+}
+
+// templates are the notifications users can receive. The labels each template
+// uses are documented in docs/admin/notifications.md.
+var templates = map[codersdk.NotificationTemplate]messageTemplate{
+	codersdk.NotificationTemplateWorkspaceAutostopped: mustTemplate(
+		`Workspace "{{.Labels.workspace}}" was stopped`,
+		`Your workspace {{.Labels.workspace}} of template {{.Labels.template}} was stopped automatically.
+
+Start it again from {{.AccessURL}}/@{{.Username}}/{{.Labels.workspace}}, or with "coder start {{.Labels.workspace}}".`,
+	),
+	codersdk.NotificationTemplateWorkspaceBuildFailed: mustTemplate(
+		`Workspace "{{.Labels.workspace}}" failed to {{.Labels.transition}}`,
+		`Build #{{.Labels.build_number}} of your workspace {{.Labels.workspace}} failed to {{.Labels.transition}}:
+
+{{.Labels.error}}
+
+See the logs of the build at {{.AccessURL}}/@{{.Username}}/{{.Labels.workspace}}/builds/{{.Labels.build_number}}.`,
+	),
+	codersdk.NotificationTemplateWorkspaceMarkedForDeletion: mustTemplate(
+		`Workspace "{{.Labels.workspace}}" will be deleted`,
+		`Your workspace {{.Labels.workspace}} hasn't been used for a while, and template {{.Labels.template}} deletes inactive workspaces. It will be deleted on {{.Labels.deleting_at}}.
+
+Start the workspace before then to keep it: {{.AccessURL}}/@{{.Username}}/{{.Labels.workspace}}`,
+	),
+	codersdk.NotificationTemplateUserCreated: mustTemplate(
+		`Your Coder account was created`,
+		`An account with the username {{.Username}} was created for you at {{.AccessURL}}.`,
+	),
+	codersdk.NotificationTemplateUserSuspended: mustTemplate(
+		`Your Coder account was suspended`,
+		`Your account {{.Username}} at {{.AccessURL}} was suspended. Contact your administrator to activate it again.`,
+	),
+}
+
+func mustTemplate(title, body string) messageTemplate {
+	return messageTemplate{
+		title: template.Must(template.New("title").Option("missingkey=error").Parse(title)),
+		body:  template.Must(template.New("body").Option("missingkey=error").Parse(body)),
+	}
+}
+
+// render renders the title and body of a notification. Labels that are used
+// by the template but missing are an error.
+func render(name codersdk.NotificationTemplate, data templateData) (title string, body string, err error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return "", "", xerrors.Errorf("unknown notification template %q", name)
+	}
+	var sb strings.Builder
+	err = tmpl.title.Execute(&sb, data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render title: %w", err)
+	}
+	title = sb.String()
+	sb.Reset()
+	err = tmpl.body.Execute(&sb, data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render body: %w", err)
+	}
+	return title, sb.String(), nil
+}
@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// WebhookPayload is the JSON body of the requests sent by WebhookDispatcher.
+type WebhookPayload struct {
+	// ID identifies the notification, it's the same when sending is retried.
+	ID        uuid.UUID                     `json:"id"`
+	Template  codersdk.NotificationTemplate `json:"template"`
+	UserID    uuid.UUID                     `json:"user_id"`
+	Username  string                        `json:"username"`
+	Email     string                        `json:"email"`
+	Title     string                        `json:"title"`
+	Body      string                        `json:"body"`
+	Labels    map[string]string             `json:"labels"`
+	CreatedAt time.Time                     `json:"created_at"`
+}
+
+// WebhookDispatcher sends notifications as JSON in POST requests to an
+// endpoint. Responses other than 2xx are errors, and are retried.
+type WebhookDispatcher struct {
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewWebhookDispatcher returns a Dispatcher that sends notifications to the
+// endpoint with the client.
+func NewWebhookDispatcher(endpoint *url.URL, client *http.Client) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		endpoint: endpoint,
+		client:   client,
+	}
+}
+
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(WebhookPayload{
+		ID:        msg.ID,
+		Template:  msg.Template,
+		UserID:    msg.UserID,
+		Username:  msg.Username,
+		Email:     msg.Email,
+		Title:     msg.Title,
+		Body:      msg.Body,
+		Labels:    msg.Labels,
+		CreatedAt: msg.CreatedAt,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := d.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return xerrors.Errorf("unexpected status %d: %s", res.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/notifications"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		preferences, err := client.NotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, preferences, len(codersdk.NotificationTemplates))
+		for _, preference := range preferences {
+			require.False(t, preference.Disabled)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		preferences, err := client.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Template: codersdk.NotificationTemplateWorkspaceAutostopped,
+				Disabled: true,
+			}},
+		})
+		require.NoError(t, err)
+		for _, preference := range preferences {
+			require.Equal(t, preference.Template == codersdk.NotificationTemplateWorkspaceAutostopped, preference.Disabled)
+		}
+
+		preferences, err = client.NotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, preferences, codersdk.NotificationPreference{
+			Template: codersdk.NotificationTemplateWorkspaceAutostopped,
+			Disabled: true,
+		})
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Template: "unknown",
+				Disabled: true,
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := member.UpdateNotificationPreferences(ctx, first.UserID.String(), codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Template: codersdk.NotificationTemplateWorkspaceAutostopped,
+				Disabled: true,
+			}},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestNotifyUser(t *testing.T) {
+	t.Parallel()
+
+	enqueuer := notifications.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{NotificationsEnqueuer: enqueuer})
+	first := coderdtest.CreateFirstUser(t, client)
+	_, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	_, err := client.UpdateUserStatus(ctx, user.Username, codersdk.UserStatusSuspended)
+	require.NoError(t, err)
+
+	var sent []notifications.MockNotification
+	for _, n := range enqueuer.Notifications() {
+		if n.UserID == user.ID {
+			sent = append(sent, n)
+		}
+	}
+	require.Len(t, sent, 2)
+	require.Equal(t, codersdk.NotificationTemplateUserCreated, sent[0].Template)
+	require.Equal(t, codersdk.NotificationTemplateUserSuspended, sent[1].Template)
+	require.NotEmpty(t, sent[1].Labels["suspended_at"])
+}
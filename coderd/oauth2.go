@@ -0,0 +1,396 @@
+package coderd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+// @Summary Get OAuth2 applications
+// @ID get-oauth2-applications
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param user_id query string false "Filter by applications authorized for a user"
+// @Success 200 {array} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [get]
+func (api *API) oAuth2ProviderApps(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+		vals   = r.URL.Query()
+	)
+	p := httpapi.NewQueryParamParser()
+	userID := p.UUIDorMe(vals, uuid.Nil, apiKey.UserID, "user_id")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	var (
+		apps []database.OAuth2ProviderApp
+		err  error
+	)
+	if userID == uuid.Nil {
+		apps, err = api.Database.GetOAuth2ProviderApps(ctx)
+	} else {
+		apps, err = api.Database.GetOAuth2ProviderAppsByUserID(ctx, userID)
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 apps.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.OAuth2ProviderApp, 0, len(apps))
+	for _, app := range apps {
+		converted = append(converted, convertOAuth2ProviderApp(api.AccessURL, app))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get OAuth2 application
+// @ID get-oauth2-application
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [get]
+func (api *API) oAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	httpapi.Write(ctx, rw, http.StatusOK, convertOAuth2ProviderApp(api.AccessURL, httpmw.OAuth2ProviderApp(r)))
+}
+
+// @Summary Create OAuth2 application
+// @ID create-oauth2-application
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param request body codersdk.CreateOAuth2ProviderAppRequest true "The OAuth2 application to create"
+// @Success 201 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [post]
+func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceOAuth2ProviderApp) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	now := database.Now()
+	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
+		ID:          uuid.New(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Name:        req.Name,
+		Icon:        req.Icon,
+		CallbackURL: req.CallbackURL,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("OAuth2 app with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = app
+	httpapi.Write(ctx, rw, http.StatusCreated, convertOAuth2ProviderApp(api.AccessURL, app))
+}
+
+// @Summary Update OAuth2 application
+// @ID update-oauth2-application
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Param request body codersdk.UpdateOAuth2ProviderAppRequest true "Update an OAuth2 application"
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [patch]
+func (api *API) patchOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = app
+	if !api.Authorize(r, rbac.ActionUpdate, app) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	updated, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
+		ID:          app.ID,
+		UpdatedAt:   database.Now(),
+		Name:        req.Name,
+		Icon:        req.Icon,
+		CallbackURL: req.CallbackURL,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("OAuth2 app with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertOAuth2ProviderApp(api.AccessURL, updated))
+}
+
+// @Summary Delete OAuth2 application
+// @ID delete-oauth2-application
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 204
+// @Router /oauth2-provider/apps/{app} [delete]
+func (api *API) deleteOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = app
+	if !api.Authorize(r, rbac.ActionDelete, app) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// Deleting the app revokes the API keys issued to it.
+	err := api.Database.DeleteOAuth2ProviderAppByID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get OAuth2 application secrets
+// @ID get-oauth2-application-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {array} codersdk.OAuth2ProviderAppSecret
+// @Router /oauth2-provider/apps/{app}/secrets [get]
+func (api *API) oAuth2ProviderAppSecrets(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		app = httpmw.OAuth2ProviderApp(r)
+	)
+	secrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.OAuth2ProviderAppSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		converted = append(converted, convertOAuth2ProviderAppSecret(secret))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Create OAuth2 application secret
+// @ID create-oauth2-application-secret
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 201 {object} codersdk.OAuth2ProviderAppSecretFull
+// @Router /oauth2-provider/apps/{app}/secrets [post]
+func (api *API) postOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceOAuth2ProviderAppSecret) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	secret, hashed, err := generateOAuth2ProviderSecret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to generate OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	inserted, err := api.Database.InsertOAuth2ProviderAppSecret(ctx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		HashedSecret: hashed,
+		// Admins can tell secrets apart by their last characters.
+		DisplaySecret: secret[len(secret)-6:],
+		AppID:         app.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = inserted
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.OAuth2ProviderAppSecretFull{
+		ID:               inserted.ID,
+		ClientSecretFull: secret,
+	})
+}
+
+// @Summary Delete OAuth2 application secret
+// @ID delete-oauth2-application-secret
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Param secretID path string true "Secret ID" format(uuid)
+// @Success 204
+// @Router /oauth2-provider/apps/{app}/secrets/{secretID} [delete]
+func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		secret            = httpmw.OAuth2ProviderAppSecret(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = secret
+	if !api.Authorize(r, rbac.ActionDelete, secret) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// API keys issued with the secret stay valid, apps only need a secret to
+	// get new ones.
+	err := api.Database.DeleteOAuth2ProviderAppSecretByID(ctx, secret.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// generateOAuth2ProviderSecret generates a client secret, authorization code
+// or refresh token, which are only stored hashed.
+func generateOAuth2ProviderSecret() (secret string, hashed []byte, err error) {
+	secret, err = cryptorand.String(40)
+	if err != nil {
+		return "", nil, err
+	}
+	hash := sha256.Sum256([]byte(secret))
+	return secret, hash[:], nil
+}
+
+func convertOAuth2ProviderApp(accessURL *url.URL, app database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
+	return codersdk.OAuth2ProviderApp{
+		ID:          app.ID,
+		Name:        app.Name,
+		CallbackURL: app.CallbackURL,
+		Icon:        app.Icon,
+		Endpoints: codersdk.OAuth2AppEndpoints{
+			Authorization: accessURL.ResolveReference(&url.URL{Path: "/oauth2/authorize"}).String(),
+			Token:         accessURL.ResolveReference(&url.URL{Path: "/oauth2/tokens"}).String(),
+		},
+	}
+}
+
+func convertOAuth2ProviderAppSecret(secret database.OAuth2ProviderAppSecret) codersdk.OAuth2ProviderAppSecret {
+	converted := codersdk.OAuth2ProviderAppSecret{
+		ID:                    secret.ID,
+		CreatedAt:             secret.CreatedAt,
+		ClientSecretTruncated: secret.DisplaySecret,
+	}
+	if secret.LastUsedAt.Valid {
+		converted.LastUsedAt = &secret.LastUsedAt.Time
+	}
+	return converted
+}
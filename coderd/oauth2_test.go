@@ -0,0 +1,552 @@
+package coderd_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestOAuth2ProviderApps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		app, err := client.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/generic_oauth",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "grafana", app.Name)
+		require.Equal(t, client.URL.String()+"/oauth2/authorize", app.Endpoints.Authorization)
+		require.Equal(t, client.URL.String()+"/oauth2/tokens", app.Endpoints.Token)
+
+		apps, err := client.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.OAuth2ProviderApp{app}, apps)
+
+		updated, err := client.UpdateOAuth2ProviderApp(ctx, app.ID, codersdk.UpdateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/coder",
+			Icon:        "/icon/grafana.svg",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://grafana.example.com/login/coder", updated.CallbackURL)
+		require.Equal(t, "/icon/grafana.svg", updated.Icon)
+
+		got, err := client.OAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, got)
+
+		err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		_, err = client.OAuth2ProviderApp(ctx, app.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		req := codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/generic_oauth",
+		}
+		_, err := client.CreateOAuth2ProviderApp(ctx, req)
+		require.NoError(t, err)
+		_, err = client.CreateOAuth2ProviderApp(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "not a url",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotManage", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		app, err := client.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/generic_oauth",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		// Members can see the apps they may authorize, but not their secrets.
+		got, err := member.OAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		require.Equal(t, app, got)
+		secrets, err := member.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+
+		var apiErr *codersdk.Error
+		_, err = member.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "other",
+			CallbackURL: "https://other.example.com",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.CreateOAuth2ProviderAppSecret(ctx, app.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Secrets", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		app, err := client.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/generic_oauth",
+		})
+		require.NoError(t, err)
+
+		secret, err := client.CreateOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, secret.ClientSecretFull)
+
+		secrets, err := client.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, secret.ID, secrets[0].ID)
+		require.Equal(t, secret.ClientSecretFull[len(secret.ClientSecretFull)-6:], secrets[0].ClientSecretTruncated)
+		require.Nil(t, secrets[0].LastUsedAt)
+
+		err = client.DeleteOAuth2ProviderAppSecret(ctx, app.ID, secret.ID)
+		require.NoError(t, err)
+		secrets, err = client.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		app, err := client.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/generic_oauth",
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateOAuth2ProviderApp(ctx, app.ID, codersdk.UpdateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/oauth",
+		})
+		require.NoError(t, err)
+		secret, err := client.CreateOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+		err = client.DeleteOAuth2ProviderAppSecret(ctx, app.ID, secret.ID)
+		require.NoError(t, err)
+		err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+
+		var appActions, secretActions []database.AuditAction
+		for _, log := range auditor.AuditLogs() {
+			switch log.ResourceType {
+			case database.ResourceTypeOauth2ProviderApp:
+				require.Equal(t, app.ID, log.ResourceID)
+				require.Equal(t, "grafana", log.ResourceTarget)
+				appActions = append(appActions, log.Action)
+			case database.ResourceTypeOauth2ProviderAppSecret:
+				require.Equal(t, secret.ID, log.ResourceID)
+				require.NotContains(t, string(log.Diff), secret.ClientSecretFull)
+				secretActions = append(secretActions, log.Action)
+			}
+		}
+		require.Equal(t, []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionWrite,
+			database.AuditActionDelete,
+		}, appActions)
+		require.Equal(t, []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionDelete,
+		}, secretActions)
+	})
+}
+
+func TestOAuth2ProviderAuthorization(t *testing.T) {
+	t.Parallel()
+
+	// setup creates an app with a secret, and a member that authorizes it.
+	setup := func(t *testing.T) (admin, member *codersdk.Client, app codersdk.OAuth2ProviderApp, config *oauth2.Config) {
+		t.Helper()
+		admin = coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, admin)
+		member, _ = coderdtest.CreateAnotherUser(t, admin, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		app, err := admin.CreateOAuth2ProviderApp(ctx, codersdk.CreateOAuth2ProviderAppRequest{
+			Name:        "grafana",
+			CallbackURL: "https://grafana.example.com/login/generic_oauth",
+		})
+		require.NoError(t, err)
+		secret, err := admin.CreateOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		return admin, member, app, &oauth2.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  app.Endpoints.Authorization,
+				TokenURL: app.Endpoints.Token,
+			},
+			RedirectURL: app.CallbackURL,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		admin, member, app, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		verifier := "a-code-verifier-that-is-long-enough-to-be-valid"
+		challenge := sha256.Sum256([]byte(verifier))
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate",
+			oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		))
+		require.Equal(t, "somestate", location.Query().Get("state"))
+		code := location.Query().Get("code")
+		require.NotEmpty(t, code)
+
+		token, err := config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+		require.NotEmpty(t, token.AccessToken)
+		require.NotEmpty(t, token.RefreshToken)
+
+		// The token acts on behalf of the member, it's sent as a bearer token.
+		user := oauth2ProviderUser(ctx, t, config.Client(ctx, token), admin.URL)
+		me, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, me.ID, user.ID)
+
+		// Codes can only be used once.
+		_, err = config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusBadRequest, retrieveErr.Response.StatusCode)
+
+		apps, err := member.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{UserID: me.ID})
+		require.NoError(t, err)
+		require.Len(t, apps, 1)
+		require.Equal(t, app.ID, apps[0].ID)
+
+		secrets, err := admin.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.NotNil(t, secrets[0].LastUsedAt)
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		t.Parallel()
+		admin, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		token, err := config.Exchange(ctx, location.Query().Get("code"))
+		require.NoError(t, err)
+
+		// A token without an access token is refreshed.
+		refreshed, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+		require.NoError(t, err)
+		require.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+		require.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+		_ = oauth2ProviderUser(ctx, t, config.Client(ctx, refreshed), admin.URL)
+
+		// Refreshing revokes the old token.
+		oldClient := codersdk.New(admin.URL)
+		oldClient.SetSessionToken(token.AccessToken)
+		_, err = oldClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		_, err = config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+		require.Error(t, err)
+	})
+
+	t.Run("ConcurrentExchange", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		code := location.Query().Get("code")
+
+		// Only one of the requests that race to exchange the code gets a token.
+		var (
+			wg        sync.WaitGroup
+			exchanged atomic.Int32
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := config.Exchange(ctx, code)
+				if err == nil {
+					exchanged.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		require.EqualValues(t, 1, exchanged.Load())
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		t.Parallel()
+		admin, member, app, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		token, err := config.Exchange(ctx, location.Query().Get("code"))
+		require.NoError(t, err)
+
+		err = member.RevokeOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+
+		appClient := codersdk.New(admin.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		_, err = config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+		require.Error(t, err)
+	})
+
+	t.Run("DeleteAppRevokes", func(t *testing.T) {
+		t.Parallel()
+		admin, member, app, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		token, err := config.Exchange(ctx, location.Query().Get("code"))
+		require.NoError(t, err)
+
+		err = admin.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+
+		appClient := codersdk.New(admin.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("ApplicationConnectScope", func(t *testing.T) {
+		t.Parallel()
+		admin, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate",
+			oauth2.SetAuthURLParam("scope", "application_connect"),
+		))
+		token, err := config.Exchange(ctx, location.Query().Get("code"))
+		require.NoError(t, err)
+
+		// Scoped keys can only connect to workspace applications.
+		appClient := codersdk.New(admin.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidSecret", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		config.ClientSecret = "invalid"
+		_, err := config.Exchange(ctx, location.Query().Get("code"))
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusUnauthorized, retrieveErr.Response.StatusCode)
+	})
+
+	t.Run("InvalidCodeVerifier", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		challenge := sha256.Sum256([]byte("a-code-verifier-that-is-long-enough-to-be-valid"))
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate",
+			oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		))
+		_, err := config.Exchange(ctx, location.Query().Get("code"), oauth2.SetAuthURLParam("code_verifier", "another-code-verifier"))
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusBadRequest, retrieveErr.Response.StatusCode)
+	})
+
+	t.Run("InvalidRedirectURI", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		config.RedirectURL = "https://evil.example.com/login/generic_oauth"
+		res := requestOAuth2ProviderAuthorize(ctx, t, member, http.MethodGet, config.AuthCodeURL("somestate"))
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("SubpathRedirectURI", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		config.RedirectURL = "https://grafana.example.com/login/generic_oauth/callback"
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		require.Equal(t, "/login/generic_oauth/callback", location.Path)
+		_, err := config.Exchange(ctx, location.Query().Get("code"))
+		require.NoError(t, err)
+	})
+
+	t.Run("OmittedRedirectURI", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The redirect_uri given in the authorization request must be given
+		// again in the token request.
+		location := authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		config.RedirectURL = ""
+		_, err := config.Exchange(ctx, location.Query().Get("code"))
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusBadRequest, retrieveErr.Response.StatusCode)
+
+		// If it wasn't given, it can be omitted.
+		location = authorizeOAuth2ProviderApp(ctx, t, member, config.AuthCodeURL("somestate"))
+		_, err = config.Exchange(ctx, location.Query().Get("code"))
+		require.NoError(t, err)
+	})
+
+	t.Run("DotSegmentRedirectURI", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Browsers would resolve the path to /evil, outside of the callback.
+		config.RedirectURL = "https://grafana.example.com/login/generic_oauth/../../evil"
+		res := requestOAuth2ProviderAuthorize(ctx, t, member, http.MethodGet, config.AuthCodeURL("somestate"))
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("UnsupportedResponseType", func(t *testing.T) {
+		t.Parallel()
+		_, member, _, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		authURL, err := url.Parse(config.AuthCodeURL("somestate"))
+		require.NoError(t, err)
+		query := authURL.Query()
+		query.Set("response_type", "token")
+		authURL.RawQuery = query.Encode()
+
+		res := requestOAuth2ProviderAuthorize(ctx, t, member, http.MethodGet, authURL.String())
+		defer res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := res.Location()
+		require.NoError(t, err)
+		require.Equal(t, "unsupported_response_type", location.Query().Get("error"))
+		require.Equal(t, "somestate", location.Query().Get("state"))
+	})
+}
+
+// requestOAuth2ProviderAuthorize sends a request to the authorization endpoint
+// as the user of the client, without following redirects.
+func requestOAuth2ProviderAuthorize(ctx context.Context, t *testing.T, client *codersdk.Client, method, authURL string) *http.Response {
+	t.Helper()
+	client.HTTPClient.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	res, err := client.Request(ctx, method, authURL, nil)
+	require.NoError(t, err)
+	return res
+}
+
+// authorizeOAuth2ProviderApp allows the app as the user of the client, and
+// returns the callback URL the user is redirected to.
+func authorizeOAuth2ProviderApp(ctx context.Context, t *testing.T, client *codersdk.Client, authURL string) *url.URL {
+	t.Helper()
+	res := requestOAuth2ProviderAuthorize(ctx, t, client, http.MethodGet, authURL)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Allowing submits the form to the same URL.
+	res = requestOAuth2ProviderAuthorize(ctx, t, client, http.MethodPost, authURL)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+	location, err := res.Location()
+	require.NoError(t, err)
+	require.Empty(t, location.Query().Get("error"))
+	return location
+}
+
+// oauth2ProviderUser returns the user an OAuth2 client acts on behalf of.
+func oauth2ProviderUser(ctx context.Context, t *testing.T, httpClient *http.Client, serverURL *url.URL) codersdk.User {
+	t.Helper()
+	client := codersdk.New(serverURL)
+	client.HTTPClient = httpClient
+	user, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	return user
+}
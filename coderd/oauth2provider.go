@@ -0,0 +1,570 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/apikey"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/telemetry"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/site"
+)
+
+// oauth2ProviderCodeLifetime is how long an authorization code can be
+// exchanged for a token.
+const oauth2ProviderCodeLifetime = 10 * time.Minute
+
+// oauth2AuthorizeRequest is a validated request of the authorization
+// endpoint.
+type oauth2AuthorizeRequest struct {
+	app         database.OAuth2ProviderApp
+	redirectURL *url.URL
+	// redirectURI is the redirect_uri as given, or empty if the callback URL
+	// of the app is used.
+	redirectURI string
+	state       string
+	scope       database.APIKeyScope
+	// codeChallenge is the S256 PKCE code challenge, or empty if the app does
+	// not use PKCE.
+	codeChallenge string
+}
+
+// oauth2AuthorizeError is an error of the authorization endpoint that is
+// returned to the app by redirecting to it, as defined by RFC 6749.
+type oauth2AuthorizeError struct {
+	code        string
+	description string
+}
+
+// parseOAuth2AuthorizeRequest validates the query of an authorization request.
+// The app and the redirect URI are validated first, errors about them are
+// shown to the user as they can't be trusted to be redirected to. Errors about
+// the other parameters are returned to the app by redirecting to it.
+func (api *API) parseOAuth2AuthorizeRequest(r *http.Request) (oauth2AuthorizeRequest, *oauth2AuthorizeError, *site.ErrorPageData) {
+	var (
+		ctx   = r.Context()
+		query = r.URL.Query()
+	)
+	badRequest := func(description string) *site.ErrorPageData {
+		return &site.ErrorPageData{
+			Status:       http.StatusBadRequest,
+			Title:        "Invalid authorization request",
+			Description:  description,
+			DashboardURL: api.AccessURL.String(),
+		}
+	}
+
+	appID, err := uuid.Parse(query.Get("client_id"))
+	if err != nil {
+		return oauth2AuthorizeRequest{}, nil, badRequest("The client_id query parameter is missing or is not a valid application ID.")
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if httpapi.Is404Error(err) {
+		return oauth2AuthorizeRequest{}, nil, badRequest("The application requesting access does not exist.")
+	}
+	if err != nil {
+		return oauth2AuthorizeRequest{}, nil, &site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Internal Server Error",
+			Description:  "Failed to get the application: " + err.Error(),
+			RetryEnabled: true,
+			DashboardURL: api.AccessURL.String(),
+		}
+	}
+
+	redirectURI := query.Get("redirect_uri")
+	rawRedirect := redirectURI
+	if rawRedirect == "" {
+		rawRedirect = app.CallbackURL
+	}
+	redirectURL, err := url.Parse(rawRedirect)
+	if err != nil || !validOAuth2RedirectURI(app.CallbackURL, redirectURL) {
+		return oauth2AuthorizeRequest{}, nil, badRequest("The redirect_uri query parameter does not match the callback URL of the application.")
+	}
+
+	req := oauth2AuthorizeRequest{
+		app:         app,
+		redirectURL: redirectURL,
+		redirectURI: redirectURI,
+		state:       query.Get("state"),
+	}
+	if responseType := query.Get("response_type"); responseType != "code" {
+		return req, &oauth2AuthorizeError{
+			code:        "unsupported_response_type",
+			description: fmt.Sprintf("Response type %q is not supported, only \"code\" is.", responseType),
+		}, nil
+	}
+	switch scope := database.APIKeyScope(query.Get("scope")); scope {
+	case "", database.APIKeyScopeAll:
+		req.scope = database.APIKeyScopeAll
+	case database.APIKeyScopeApplicationConnect:
+		req.scope = database.APIKeyScopeApplicationConnect
+	default:
+		return req, &oauth2AuthorizeError{
+			code:        "invalid_scope",
+			description: fmt.Sprintf("Scope %q is not supported, use %q or %q.", scope, database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect),
+		}, nil
+	}
+	req.codeChallenge = query.Get("code_challenge")
+	if method := query.Get("code_challenge_method"); req.codeChallenge != "" && method != "S256" {
+		return req, &oauth2AuthorizeError{
+			code:        "invalid_request",
+			description: fmt.Sprintf("Code challenge method %q is not supported, only \"S256\" is.", method),
+		}, nil
+	}
+	return req, nil, nil
+}
+
+// validOAuth2RedirectURI returns whether redirect is the callback URL of an
+// app, or a path below it.
+func validOAuth2RedirectURI(callbackURL string, redirect *url.URL) bool {
+	callback, err := url.Parse(callbackURL)
+	if err != nil {
+		return false
+	}
+	if redirect.Scheme != callback.Scheme || redirect.Host != callback.Host || redirect.User != nil || redirect.Fragment != "" {
+		return false
+	}
+	// Browsers resolve dot segments, which would escape the callback path.
+	for _, segment := range strings.Split(redirect.Path, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+	prefix := strings.TrimSuffix(callback.Path, "/")
+	return redirect.Path == callback.Path || strings.HasPrefix(redirect.Path, prefix+"/")
+}
+
+// redirectOAuth2 redirects the user back to the app with the given query
+// parameters and the state of the request.
+func redirectOAuth2(rw http.ResponseWriter, r *http.Request, req oauth2AuthorizeRequest, params url.Values) {
+	u := *req.redirectURL
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if req.state != "" {
+		query.Set("state", req.state)
+	}
+	u.RawQuery = query.Encode()
+	http.Redirect(rw, r, u.String(), http.StatusFound)
+}
+
+// oauth2AuthorizeErrorURL returns the redirect URI with the error of an
+// authorization request.
+func oauth2AuthorizeErrorURL(req oauth2AuthorizeRequest, authErr oauth2AuthorizeError) string {
+	u := *req.redirectURL
+	query := u.Query()
+	query.Set("error", authErr.code)
+	if authErr.description != "" {
+		query.Set("error_description", authErr.description)
+	}
+	if req.state != "" {
+		query.Set("state", req.state)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// getOAuth2ProviderAppAuthorize asks the user whether they allow an app to act
+// on their behalf. Allowing submits the form to postOAuth2ProviderAppAuthorize.
+//
+// This endpoint is not under /api as it's used by browsers, errors are shown
+// on the static error page.
+func (api *API) getOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	req, authErr, pageErr := api.parseOAuth2AuthorizeRequest(r)
+	if pageErr != nil {
+		site.RenderStaticErrorPage(rw, r, *pageErr)
+		return
+	}
+	if authErr != nil {
+		http.Redirect(rw, r, oauth2AuthorizeErrorURL(req, *authErr), http.StatusFound)
+		return
+	}
+
+	site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+		AppIcon:    req.app.Icon,
+		AppName:    req.app.Name,
+		Username:   httpmw.UserAuthorization(r).ActorName,
+		FullAccess: req.scope == database.APIKeyScopeAll,
+		CancelURI: oauth2AuthorizeErrorURL(req, oauth2AuthorizeError{
+			code:        "access_denied",
+			description: "The user denied the authorization request.",
+		}),
+	})
+}
+
+// postOAuth2ProviderAppAuthorize issues an authorization code to an app the
+// user allowed, and redirects the user back to the app with it.
+func (api *API) postOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+	req, authErr, pageErr := api.parseOAuth2AuthorizeRequest(r)
+	if pageErr != nil {
+		site.RenderStaticErrorPage(rw, r, *pageErr)
+		return
+	}
+	if authErr != nil {
+		http.Redirect(rw, r, oauth2AuthorizeErrorURL(req, *authErr), http.StatusFound)
+		return
+	}
+	// Only sessions that can create API keys can issue them to apps, this
+	// prevents scoped API keys from escalating their scope.
+	if !api.Authorize(r, rbac.ActionCreate, apiKey) {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusForbidden,
+			Title:        "Forbidden",
+			Description:  "Your session is not allowed to authorize applications.",
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	code, hashed, err := generateOAuth2ProviderSecret()
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Internal Server Error",
+			Description:  "Failed to generate the authorization code: " + err.Error(),
+			RetryEnabled: true,
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+	now := database.Now()
+	_, err = api.Database.InsertOAuth2ProviderAppCode(ctx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            uuid.New(),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(oauth2ProviderCodeLifetime),
+		HashedSecret:  hashed,
+		UserID:        apiKey.UserID,
+		AppID:         req.app.ID,
+		RedirectURI:   req.redirectURI,
+		Scope:         req.scope,
+		CodeChallenge: req.codeChallenge,
+	})
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Internal Server Error",
+			Description:  "Failed to create the authorization code: " + err.Error(),
+			RetryEnabled: true,
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	redirectOAuth2(rw, r, req, url.Values{"code": {code}})
+}
+
+// writeOAuth2Error writes an error of the token endpoint, as defined by
+// RFC 6749.
+func writeOAuth2Error(ctx context.Context, rw http.ResponseWriter, status int, code, description string) {
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	httpapi.Write(ctx, rw, status, codersdk.OAuth2Error{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
+// postOAuth2ProviderAppToken is the token endpoint of RFC 6749. Apps exchange
+// authorization codes and refresh tokens for API keys of the user.
+func (api *API) postOAuth2ProviderAppToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", "Failed to parse the form: "+err.Error())
+		return
+	}
+
+	// Apps authenticate with either basic auth or the form.
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	//nolint:gocritic // The app is authenticated by its secret, not a user.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	app, ok := api.authenticateOAuth2ProviderApp(ctx, clientID, clientSecret)
+	if !ok {
+		writeOAuth2Error(ctx, rw, http.StatusUnauthorized, "invalid_client", "Invalid client ID or secret.")
+		return
+	}
+
+	var (
+		token  codersdk.OAuth2TokenResponse
+		status int
+		err    error
+	)
+	switch grantType := r.PostForm.Get("grant_type"); grantType {
+	case "authorization_code":
+		token, status, err = api.exchangeOAuth2ProviderAppCode(ctx, r, app)
+	case "refresh_token":
+		token, status, err = api.refreshOAuth2ProviderAppToken(ctx, r, app)
+	default:
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("Grant type %q is not supported.", grantType))
+		return
+	}
+	if err != nil {
+		code := "invalid_grant"
+		if status == http.StatusInternalServerError {
+			code = "server_error"
+		}
+		writeOAuth2Error(ctx, rw, status, code, err.Error())
+		return
+	}
+
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	httpapi.Write(ctx, rw, http.StatusOK, token)
+}
+
+// authenticateOAuth2ProviderApp returns the app if the secret is one of its
+// client secrets.
+func (api *API) authenticateOAuth2ProviderApp(ctx context.Context, clientID, clientSecret string) (database.OAuth2ProviderApp, bool) {
+	appID, err := uuid.Parse(clientID)
+	if err != nil || clientSecret == "" {
+		return database.OAuth2ProviderApp{}, false
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, false
+	}
+	hashed := sha256.Sum256([]byte(clientSecret))
+	secret, err := api.Database.GetOAuth2ProviderAppSecretByHashedSecret(ctx, hashed[:])
+	if err != nil || secret.AppID != app.ID {
+		return database.OAuth2ProviderApp{}, false
+	}
+	err = api.Database.UpdateOAuth2ProviderAppSecretLastUsedAtByID(ctx, database.UpdateOAuth2ProviderAppSecretLastUsedAtByIDParams{
+		ID:         secret.ID,
+		LastUsedAt: sql.NullTime{Time: database.Now(), Valid: true},
+	})
+	if err != nil {
+		// Not fatal, the secret is valid.
+		api.Logger.Warn(ctx, "update oauth2 app secret last used at", slog.F("secret_id", secret.ID), slog.Error(err))
+	}
+	return app, true
+}
+
+// exchangeOAuth2ProviderAppCode exchanges an authorization code for a token.
+// Codes can only be used once.
+func (api *API) exchangeOAuth2ProviderAppCode(ctx context.Context, r *http.Request, app database.OAuth2ProviderApp) (codersdk.OAuth2TokenResponse, int, error) {
+	hashed := sha256.Sum256([]byte(r.PostForm.Get("code")))
+	code, err := api.Database.GetOAuth2ProviderAppCodeByHashedSecret(ctx, hashed[:])
+	if httpapi.Is404Error(err) {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("Invalid authorization code.")
+	}
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, http.StatusInternalServerError, xerrors.Errorf("get authorization code: %w", err)
+	}
+	if code.AppID != app.ID {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("Invalid authorization code.")
+	}
+	if database.Now().After(code.ExpiresAt) {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The authorization code has expired.")
+	}
+	// The redirect_uri must be identical to the one given in the
+	// authorization request, as defined by RFC 6749 section 4.1.3.
+	redirectURI := r.PostForm.Get("redirect_uri")
+	if code.RedirectURI != "" && redirectURI != code.RedirectURI {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The redirect_uri does not match the one the code was issued for.")
+	}
+	if code.RedirectURI == "" && redirectURI != "" && redirectURI != app.CallbackURL {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The redirect_uri does not match the callback URL of the application.")
+	}
+	if code.CodeChallenge != "" {
+		verifier := r.PostForm.Get("code_verifier")
+		if verifier == "" {
+			return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The code_verifier is required.")
+		}
+		challenge := sha256.Sum256([]byte(verifier))
+		if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(challenge[:])), []byte(code.CodeChallenge)) != 1 {
+			return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The code_verifier does not match the code challenge.")
+		}
+	}
+
+	return api.issueOAuth2ProviderAppToken(ctx, r, app, code.UserID, code.Scope, func(tx database.Store) error {
+		_, err := tx.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return oauth2GrantError("The authorization code was already used.")
+		}
+		if err != nil {
+			return xerrors.Errorf("delete authorization code: %w", err)
+		}
+		return nil
+	})
+}
+
+// refreshOAuth2ProviderAppToken exchanges a refresh token for a new token,
+// which revokes the API key of the old one.
+func (api *API) refreshOAuth2ProviderAppToken(ctx context.Context, r *http.Request, app database.OAuth2ProviderApp) (codersdk.OAuth2TokenResponse, int, error) {
+	hashed := sha256.Sum256([]byte(r.PostForm.Get("refresh_token")))
+	token, err := api.Database.GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx, hashed[:])
+	if httpapi.Is404Error(err) {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("Invalid refresh token.")
+	}
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, http.StatusInternalServerError, xerrors.Errorf("get refresh token: %w", err)
+	}
+	if token.AppID != app.ID {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("Invalid refresh token.")
+	}
+	if database.Now().After(token.ExpiresAt) {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The refresh token has expired.")
+	}
+	key, err := api.Database.GetAPIKeyByID(ctx, token.APIKeyID)
+	if httpapi.Is404Error(err) {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, xerrors.New("The refresh token was already used.")
+	}
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, http.StatusInternalServerError, xerrors.Errorf("get API key: %w", err)
+	}
+
+	return api.issueOAuth2ProviderAppToken(ctx, r, app, key.UserID, key.Scope, func(tx database.Store) error {
+		// The old refresh token is removed with its API key.
+		_, err := tx.DeleteAPIKeyByID(ctx, key.ID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return oauth2GrantError("The refresh token was already used.")
+		}
+		if err != nil {
+			return xerrors.Errorf("delete API key: %w", err)
+		}
+		return nil
+	})
+}
+
+// oauth2GrantError is returned when an authorization code or refresh token
+// was redeemed by a concurrent request.
+type oauth2GrantError string
+
+func (e oauth2GrantError) Error() string {
+	return string(e)
+}
+
+// issueOAuth2ProviderAppToken creates an API key for the user and a refresh
+// token for the app. The grant is redeemed in the same transaction, so
+// concurrent requests can't exchange it twice.
+func (api *API) issueOAuth2ProviderAppToken(ctx context.Context, r *http.Request, app database.OAuth2ProviderApp, userID uuid.UUID, scope database.APIKeyScope, redeem func(tx database.Store) error) (codersdk.OAuth2TokenResponse, int, error) {
+	params, sessionToken, err := apikey.Generate(apikey.CreateParams{
+		UserID:           userID,
+		LoginType:        database.LoginTypeOAuth2ProviderApp,
+		DeploymentValues: api.DeploymentValues,
+		Scope:            scope,
+		RemoteAddr:       r.RemoteAddr,
+	})
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, http.StatusInternalServerError, xerrors.Errorf("generate API key: %w", err)
+	}
+	refreshToken, hashed, err := generateOAuth2ProviderSecret()
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, http.StatusInternalServerError, xerrors.Errorf("generate refresh token: %w", err)
+	}
+
+	var key database.APIKey
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := redeem(tx)
+		if err != nil {
+			return err
+		}
+		key, err = tx.InsertAPIKey(ctx, params)
+		if err != nil {
+			return xerrors.Errorf("insert API key: %w", err)
+		}
+		_, err = tx.InsertOAuth2ProviderAppToken(ctx, database.InsertOAuth2ProviderAppTokenParams{
+			ID:                 uuid.New(),
+			CreatedAt:          database.Now(),
+			ExpiresAt:          database.Now().Add(api.DeploymentValues.MaxTokenLifetime.Value()),
+			HashedRefreshToken: hashed,
+			AppID:              app.ID,
+			APIKeyID:           key.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert refresh token: %w", err)
+		}
+		return nil
+	}, nil)
+	var grantErr oauth2GrantError
+	if xerrors.As(err, &grantErr) {
+		return codersdk.OAuth2TokenResponse{}, http.StatusBadRequest, grantErr
+	}
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, http.StatusInternalServerError, err
+	}
+
+	api.Telemetry.Report(&telemetry.Snapshot{
+		APIKeys: []telemetry.APIKey{telemetry.ConvertAPIKey(key)},
+	})
+
+	return codersdk.OAuth2TokenResponse{
+		AccessToken:  sessionToken,
+		TokenType:    "Bearer",
+		RefreshToken: refreshToken,
+		ExpiresIn:    key.LifetimeSeconds,
+	}, http.StatusOK, nil
+}
+
+// deleteOAuth2ProviderAppTokens revokes the API keys and authorization codes
+// the user issued to an app.
+func (api *API) deleteOAuth2ProviderAppTokens(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+	appID, err := uuid.Parse(r.URL.Query().Get("client_id"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid client_id query parameter.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams{
+			AppID:  appID,
+			UserID: apiKey.UserID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete authorization codes: %w", err)
+		}
+		err = tx.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  appID,
+			UserID: apiKey.UserID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete tokens: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking OAuth2 app tokens.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -27,6 +27,7 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/agent/agentmetadata"
 	"github.com/coder/coder/coderd/apikey"
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
@@ -34,9 +35,11 @@ import (
 	"github.com/coder/coder/coderd/database/pubsub"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/tracing"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisioner"
 	"github.com/coder/coder/provisionerd/proto"
@@ -64,6 +67,8 @@ type Server struct {
 	Auditor               *atomic.Pointer[audit.Auditor]
 	TemplateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
 	DeploymentValues      *codersdk.DeploymentValues
+	NotificationsEnqueuer notifications.Enqueuer
+	WebhooksEnqueuer      webhooks.Enqueuer
 
 	AcquireJobDebounce time.Duration
 	OIDCConfig         httpmw.OAuth2Config
@@ -186,6 +191,7 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("publish workspace update: %s", err))
 		}
+		server.enqueueWorkspaceBuildWebhook(ctx, codersdk.WebhookEventWorkspaceBuildStarted, workspace, workspaceBuild, "")
 
 		var workspaceOwnerOIDCAccessToken string
 		if server.OIDCConfig != nil {
@@ -717,6 +723,8 @@ func (server *Server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*p
 					Status:           http.StatusInternalServerError,
 					AdditionalFields: wriBytes,
 				})
+				server.notifyWorkspaceBuildFailed(ctx, workspace, build, job)
+				server.enqueueWorkspaceBuildWebhook(ctx, codersdk.WebhookEventWorkspaceBuildFailed, workspace, build, job.Error.String)
 			}
 		}
 	}
@@ -733,6 +741,107 @@ func (server *Server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*p
 	return &proto.Empty{}, nil
 }
 
+// notifyWorkspaceBuildFailed notifies the owner of a workspace that a build
+// failed, unless they started the build themselves and saw it fail.
+func (server *Server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob) {
+	if server.NotificationsEnqueuer == nil {
+		return
+	}
+	if build.Reason == database.BuildReasonInitiator && build.InitiatorID == workspace.OwnerID {
+		return
+	}
+	template, err := server.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		server.Logger.Error(ctx, "notify build failed - get template", slog.Error(err))
+		return
+	}
+	err = server.NotificationsEnqueuer.Enqueue(ctx, workspace.OwnerID, codersdk.NotificationTemplateWorkspaceBuildFailed, map[string]string{
+		"workspace":    workspace.Name,
+		"template":     template.Name,
+		"transition":   string(build.Transition),
+		"build_id":     build.ID.String(),
+		"build_number": strconv.FormatInt(int64(build.BuildNumber), 10),
+		"error":        job.Error.String,
+	})
+	if err != nil {
+		server.Logger.Error(ctx, "notify build failed", slog.F("workspace_id", workspace.ID), slog.Error(err))
+	}
+}
+
+// enqueueWorkspaceBuildWebhook delivers a workspace build event to the
+// webhooks subscribed to it. buildError is set for failed builds.
+func (server *Server) enqueueWorkspaceBuildWebhook(ctx context.Context, event codersdk.WebhookEvent, workspace database.Workspace, build database.WorkspaceBuild, buildError string) {
+	if server.WebhooksEnqueuer == nil {
+		return
+	}
+	template, err := server.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		server.Logger.Error(ctx, "webhook - get template", slog.Error(err))
+		return
+	}
+	owner, err := server.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		server.Logger.Error(ctx, "webhook - get workspace owner", slog.Error(err))
+		return
+	}
+	err = server.WebhooksEnqueuer.Enqueue(ctx, event, codersdk.WebhookWorkspaceBuildData{
+		WorkspaceID:        workspace.ID,
+		WorkspaceName:      workspace.Name,
+		WorkspaceOwnerID:   owner.ID,
+		WorkspaceOwnerName: owner.Username,
+		TemplateID:         template.ID,
+		TemplateName:       template.Name,
+		TemplateVersionID:  build.TemplateVersionID,
+		BuildID:            build.ID,
+		BuildNumber:        build.BuildNumber,
+		Transition:         codersdk.WorkspaceTransition(build.Transition),
+		Reason:             codersdk.BuildReason(build.Reason),
+		InitiatorID:        build.InitiatorID,
+		Error:              buildError,
+	})
+	if err != nil {
+		server.Logger.Error(ctx, "enqueue webhook", slog.F("event", event), slog.F("workspace_build_id", build.ID), slog.Error(err))
+	}
+}
+
+// enqueueTemplateVersionWebhook delivers the push of the template version
+// imported by the job to the webhooks subscribed to it.
+func (server *Server) enqueueTemplateVersionWebhook(ctx context.Context, job database.ProvisionerJob) {
+	if server.WebhooksEnqueuer == nil {
+		return
+	}
+	version, err := server.Database.GetTemplateVersionByJobID(ctx, job.ID)
+	if err != nil {
+		server.Logger.Error(ctx, "webhook - get template version", slog.Error(err))
+		return
+	}
+	creator, err := server.Database.GetUserByID(ctx, version.CreatedBy)
+	if err != nil {
+		server.Logger.Error(ctx, "webhook - get template version creator", slog.Error(err))
+		return
+	}
+	data := codersdk.WebhookTemplateVersionData{
+		TemplateVersionID:   version.ID,
+		TemplateVersionName: version.Name,
+		OrganizationID:      version.OrganizationID,
+		CreatedByID:         creator.ID,
+		CreatedByName:       creator.Username,
+	}
+	if version.TemplateID.Valid {
+		template, err := server.Database.GetTemplateByID(ctx, version.TemplateID.UUID)
+		if err != nil {
+			server.Logger.Error(ctx, "webhook - get template", slog.Error(err))
+			return
+		}
+		data.TemplateID = &template.ID
+		data.TemplateName = template.Name
+	}
+	err = server.WebhooksEnqueuer.Enqueue(ctx, codersdk.WebhookEventTemplateVersionPushed, data)
+	if err != nil {
+		server.Logger.Error(ctx, "enqueue webhook", slog.F("event", codersdk.WebhookEventTemplateVersionPushed), slog.F("template_version_id", version.ID), slog.Error(err))
+	}
+}
+
 // CompleteJob is triggered by a provision daemon to mark a provisioner job as completed.
 //
 //nolint:gocyclo
@@ -876,6 +985,9 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 		if err != nil {
 			return nil, xerrors.Errorf("complete job: %w", err)
 		}
+		if !completedError.Valid {
+			server.enqueueTemplateVersionWebhook(ctx, job)
+		}
 	case *proto.CompletedJob_WorkspaceBuild_:
 		var input WorkspaceProvisionJob
 		err = json.Unmarshal(job.Input, &input)
@@ -1068,6 +1180,7 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 				Status:           http.StatusOK,
 				AdditionalFields: wriBytes,
 			})
+			server.enqueueWorkspaceBuildWebhook(ctx, codersdk.WebhookEventWorkspaceBuildSucceeded, workspace, workspaceBuild, "")
 		}
 
 		err = server.Pubsub.Publish(codersdk.WorkspaceNotifyChannel(workspaceBuild.WorkspaceID), []byte{})
@@ -1227,6 +1340,12 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		snapshot.WorkspaceAgents = append(snapshot.WorkspaceAgents, telemetry.ConvertWorkspaceAgent(dbAgent))
 
 		for _, md := range prAgent.Metadata {
+			if md.Collector != "" {
+				_, _, err := agentmetadata.Parse(md.Collector)
+				if err != nil {
+					return xerrors.Errorf("parse collector for metadata %q: %w", md.Key, err)
+				}
+			}
 			p := database.InsertWorkspaceAgentMetadataParams{
 				WorkspaceAgentID: agentID,
 				DisplayName:      md.DisplayName,
@@ -1234,6 +1353,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				Key:              md.Key,
 				Timeout:          md.Timeout,
 				Interval:         md.Interval,
+				Collector:        md.Collector,
 			}
 			err := db.InsertWorkspaceAgentMetadata(ctx, p)
 			if err != nil {
@@ -1241,6 +1361,82 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			}
 		}
 
+		if len(prAgent.Scripts) > 0 {
+			logSourceIDs := make([]uuid.UUID, 0, len(prAgent.Scripts))
+			logSourceDisplayNames := make([]string, 0, len(prAgent.Scripts))
+			logSourceIcons := make([]string, 0, len(prAgent.Scripts))
+			scriptLogPaths := make([]string, 0, len(prAgent.Scripts))
+			scriptSources := make([]string, 0, len(prAgent.Scripts))
+			scriptCron := make([]string, 0, len(prAgent.Scripts))
+			scriptStartBlocksLogin := make([]bool, 0, len(prAgent.Scripts))
+			scriptRunOnStart := make([]bool, 0, len(prAgent.Scripts))
+			scriptRunOnStop := make([]bool, 0, len(prAgent.Scripts))
+			scriptTimeout := make([]int32, 0, len(prAgent.Scripts))
+			for _, script := range prAgent.Scripts {
+				if script.Cron != "" {
+					_, err := schedule.Standard(script.Cron)
+					if err != nil {
+						return xerrors.Errorf("parse cron for script %q: %w", script.DisplayName, err)
+					}
+				}
+				logSourceIDs = append(logSourceIDs, uuid.New())
+				logSourceDisplayNames = append(logSourceDisplayNames, script.DisplayName)
+				logSourceIcons = append(logSourceIcons, script.Icon)
+				scriptLogPaths = append(scriptLogPaths, script.LogPath)
+				scriptSources = append(scriptSources, script.Script)
+				scriptCron = append(scriptCron, script.Cron)
+				scriptStartBlocksLogin = append(scriptStartBlocksLogin, script.StartBlocksLogin)
+				scriptRunOnStart = append(scriptRunOnStart, script.RunOnStart)
+				scriptRunOnStop = append(scriptRunOnStop, script.RunOnStop)
+				scriptTimeout = append(scriptTimeout, script.TimeoutSeconds)
+			}
+
+			_, err = db.InsertWorkspaceAgentLogSources(ctx, database.InsertWorkspaceAgentLogSourcesParams{
+				WorkspaceAgentID: agentID,
+				CreatedAt:        database.Now(),
+				ID:               logSourceIDs,
+				DisplayName:      logSourceDisplayNames,
+				Icon:             logSourceIcons,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent log sources: %w", err)
+			}
+
+			_, err = db.InsertWorkspaceAgentScripts(ctx, database.InsertWorkspaceAgentScriptsParams{
+				WorkspaceAgentID: agentID,
+				CreatedAt:        database.Now(),
+				LogSourceID:      logSourceIDs,
+				LogPath:          scriptLogPaths,
+				Script:           scriptSources,
+				Cron:             scriptCron,
+				StartBlocksLogin: scriptStartBlocksLogin,
+				RunOnStart:       scriptRunOnStart,
+				RunOnStop:        scriptRunOnStop,
+				TimeoutSeconds:   scriptTimeout,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent scripts: %w", err)
+			}
+
+			// The agent reports the result of the latest scheduled run of
+			// each cron script as metadata, so failures are visible
+			// without digging through the logs.
+			for i, script := range prAgent.Scripts {
+				if script.Cron == "" {
+					continue
+				}
+				p := database.InsertWorkspaceAgentMetadataParams{
+					WorkspaceAgentID: agentID,
+					DisplayName:      script.DisplayName,
+					Key:              codersdk.WorkspaceAgentScriptMetadataKey(logSourceIDs[i]),
+				}
+				err := db.InsertWorkspaceAgentMetadata(ctx, p)
+				if err != nil {
+					return xerrors.Errorf("insert agent script metadata: %w, params: %+v", err, p)
+				}
+			}
+		}
+
 		for _, app := range prAgent.Apps {
 			slug := app.Slug
 			if slug == "" {
@@ -1364,7 +1560,7 @@ func deleteSessionToken(ctx context.Context, db database.Store, workspace databa
 			TokenName: workspaceSessionTokenName(workspace),
 		})
 		if err == nil {
-			err = tx.DeleteAPIKeyByID(ctx, key.ID)
+			_, err = tx.DeleteAPIKeyByID(ctx, key.ID)
 		}
 
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
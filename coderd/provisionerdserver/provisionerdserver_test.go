@@ -23,9 +23,11 @@ import (
 	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/database/pubsub"
 	"github.com/coder/coder/coderd/gitauth"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
+	"github.com/coder/coder/coderd/webhooks"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisionerd/proto"
 	"github.com/coder/coder/provisionersdk"
@@ -796,6 +798,105 @@ func TestFailJob(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "some state", string(build.ProvisionerState))
 	})
+
+	t.Run("WorkspaceBuildNotification", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		enqueuer := notifications.NewMock()
+		srv.NotificationsEnqueuer = enqueuer
+
+		owner := dbgen.User(t, srv.Database, database.User{})
+		template := dbgen.Template(t, srv.Database, database.Template{})
+		workspace := dbgen.Workspace(t, srv.Database, database.Workspace{
+			OwnerID:    owner.ID,
+			TemplateID: template.ID,
+		})
+		job := dbgen.ProvisionerJob(t, srv.Database, database.ProvisionerJob{
+			Provisioner: database.ProvisionerTypeEcho,
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		build := dbgen.WorkspaceBuild(t, srv.Database, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Reason:      database.BuildReasonAutostart,
+		})
+		_, err := srv.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srv.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.FailJob(ctx, &proto.FailedJob{
+			JobId: job.ID.String(),
+			Error: "terraform apply failed",
+		})
+		require.NoError(t, err)
+
+		// The owner didn't start the build, so they're notified.
+		sent := enqueuer.Notifications()
+		require.Len(t, sent, 1)
+		require.Equal(t, owner.ID, sent[0].UserID)
+		require.Equal(t, codersdk.NotificationTemplateWorkspaceBuildFailed, sent[0].Template)
+		require.Equal(t, workspace.Name, sent[0].Labels["workspace"])
+		require.Equal(t, template.Name, sent[0].Labels["template"])
+		require.Equal(t, build.ID.String(), sent[0].Labels["build_id"])
+		require.Equal(t, "terraform apply failed", sent[0].Labels["error"])
+	})
+	t.Run("WorkspaceBuildWebhook", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		enqueuer := webhooks.NewMock()
+		srv.WebhooksEnqueuer = enqueuer
+
+		owner := dbgen.User(t, srv.Database, database.User{})
+		template := dbgen.Template(t, srv.Database, database.Template{})
+		workspace := dbgen.Workspace(t, srv.Database, database.Workspace{
+			OwnerID:    owner.ID,
+			TemplateID: template.ID,
+		})
+		job := dbgen.ProvisionerJob(t, srv.Database, database.ProvisionerJob{
+			Provisioner: database.ProvisionerTypeEcho,
+			Type:        database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		build := dbgen.WorkspaceBuild(t, srv.Database, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Reason:      database.BuildReasonInitiator,
+			InitiatorID: owner.ID,
+		})
+		_, err := srv.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srv.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.FailJob(ctx, &proto.FailedJob{
+			JobId: job.ID.String(),
+			Error: "terraform apply failed",
+		})
+		require.NoError(t, err)
+
+		// Unlike notifications, webhooks receive builds the owner started.
+		events := enqueuer.Events()
+		require.Len(t, events, 1)
+		require.Equal(t, codersdk.WebhookEventWorkspaceBuildFailed, events[0].Event)
+		data, ok := events[0].Data.(codersdk.WebhookWorkspaceBuildData)
+		require.True(t, ok)
+		require.Equal(t, workspace.Name, data.WorkspaceName)
+		require.Equal(t, owner.Username, data.WorkspaceOwnerName)
+		require.Equal(t, template.Name, data.TemplateName)
+		require.Equal(t, build.ID, data.BuildID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, data.Transition)
+		require.Equal(t, "terraform apply failed", data.Error)
+	})
 }
 
 func TestCompleteJob(t *testing.T) {
@@ -841,10 +942,14 @@ func TestCompleteJob(t *testing.T) {
 	t.Run("TemplateImport", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
+		enqueuer := webhooks.NewMock()
+		srv.WebhooksEnqueuer = enqueuer
+		user := dbgen.User(t, srv.Database, database.User{})
 		jobID := uuid.New()
 		version, err := srv.Database.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
-			ID:    uuid.New(),
-			JobID: jobID,
+			ID:        uuid.New(),
+			JobID:     jobID,
+			CreatedBy: user.ID,
 		})
 		require.NoError(t, err)
 		job, err := srv.Database.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
@@ -883,6 +988,8 @@ func TestCompleteJob(t *testing.T) {
 		job, err = srv.Database.GetProvisionerJobByID(ctx, job.ID)
 		require.NoError(t, err)
 		require.Contains(t, job.Error.String, `git auth provider "github" is not configured`)
+		// Only successfully imported versions are pushed.
+		require.Empty(t, enqueuer.Events())
 		srv.GitAuthConfigs = []*gitauth.Config{{
 			ID: "github",
 		}}
@@ -890,6 +997,15 @@ func TestCompleteJob(t *testing.T) {
 		job, err = srv.Database.GetProvisionerJobByID(ctx, job.ID)
 		require.NoError(t, err)
 		require.False(t, job.Error.Valid)
+
+		events := enqueuer.Events()
+		require.Len(t, events, 1)
+		require.Equal(t, codersdk.WebhookEventTemplateVersionPushed, events[0].Event)
+		data, ok := events[0].Data.(codersdk.WebhookTemplateVersionData)
+		require.True(t, ok)
+		require.Equal(t, version.ID, data.TemplateVersionID)
+		require.Equal(t, user.Username, data.CreatedByName)
+		require.Nil(t, data.TemplateID)
 	})
 
 	t.Run("WorkspaceBuild", func(t *testing.T) {
@@ -1252,6 +1368,137 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, want, got)
 	})
+	t.Run("Scripts", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Auth: &sdkproto.Agent_Token{
+					Token: uuid.NewString(),
+				},
+				Scripts: []*sdkproto.Script{{
+					DisplayName:      "Dotfiles",
+					Icon:             "/icon/dotfiles.svg",
+					Script:           "coder dotfiles",
+					RunOnStart:       true,
+					StartBlocksLogin: true,
+					TimeoutSeconds:   60,
+				}, {
+					DisplayName: "Cleanup",
+					Script:      "rm -rf /tmp/*",
+					RunOnStop:   true,
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		logSources, err := db.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, logSources, 2)
+		require.Equal(t, "Dotfiles", logSources[0].DisplayName)
+		require.Equal(t, "/icon/dotfiles.svg", logSources[0].Icon)
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 2)
+		require.Equal(t, logSources[0].ID, scripts[0].LogSourceID)
+		require.Equal(t, "coder dotfiles", scripts[0].Script)
+		require.True(t, scripts[0].RunOnStart)
+		require.True(t, scripts[0].StartBlocksLogin)
+		require.EqualValues(t, 60, scripts[0].TimeoutSeconds)
+		require.Equal(t, database.WorkspaceAgentScriptStatusPending, scripts[0].Status)
+		require.Equal(t, logSources[1].ID, scripts[1].LogSourceID)
+		require.True(t, scripts[1].RunOnStop)
+	})
+	t.Run("CronScripts", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Auth: &sdkproto.Agent_Token{
+					Token: uuid.NewString(),
+				},
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "Prune",
+					Script:      "docker system prune -f",
+					Cron:        "0 3 * * *",
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 1)
+		require.Equal(t, "0 3 * * *", scripts[0].Cron)
+		metadata, err := db.GetWorkspaceAgentMetadata(ctx, agents[0].ID)
+		require.NoError(t, err)
+		require.Len(t, metadata, 1)
+		require.Equal(t, codersdk.WorkspaceAgentScriptMetadataKey(scripts[0].LogSourceID), metadata[0].Key)
+		require.Equal(t, "Prune", metadata[0].DisplayName)
+		require.Empty(t, metadata[0].Script)
+	})
+	t.Run("InvalidCron", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Auth: &sdkproto.Agent_Token{
+					Token: uuid.NewString(),
+				},
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "Prune",
+					Script:      "docker system prune -f",
+					Cron:        "0 3 *",
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, "parse cron for script")
+	})
+	t.Run("InvalidMetadataCollector", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Auth: &sdkproto.Agent_Token{
+					Token: uuid.NewString(),
+				},
+				Metadata: []*sdkproto.Agent_Metadata{{
+					DisplayName: "GPU",
+					Key:         "gpu",
+					Collector:   "gpu",
+					Interval:    10,
+					Timeout:     1,
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, "parse collector for metadata")
+	})
 }
 
 func setup(t *testing.T, ignoreLogErrors bool) *provisionerdserver.Server {
@@ -0,0 +1,175 @@
+// Package queue runs the queues of work stored in the database, like
+// notifications and webhook deliveries. Managers on several replicas may run
+// at the same time, every item is leased to a single one while it's handled.
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+)
+
+// PurgeInterval is how often finished items are deleted.
+const PurgeInterval = time.Hour
+
+// Result is the outcome of handling an item.
+type Result int
+
+const (
+	// Succeeded items are done.
+	Succeeded Result = iota
+	// Retried items failed, and are handled again later.
+	Retried
+	// Failed items failed, and are given up on.
+	Failed
+)
+
+// Handler handles the items of a queue stored in the database.
+type Handler[T any] interface {
+	// ID identifies the item in Stats.
+	ID(item T) uuid.UUID
+	// Acquire leases up to count items that are due at now, until
+	// leasedUntil.
+	Acquire(ctx context.Context, now, leasedUntil time.Time, count int32) ([]T, error)
+	// Handle handles a leased item, and records the result in the database.
+	Handle(ctx context.Context, item T) Result
+	// Purge deletes the items that finished before the time.
+	Purge(ctx context.Context, before time.Time) error
+}
+
+// Options configure a Manager.
+type Options struct {
+	// LeasePeriod is how long a replica may take to handle an item before
+	// another replica handles it again.
+	LeasePeriod time.Duration
+	// Retention is how long finished items are kept.
+	Retention time.Duration
+	// MaxItemsPerRun is the maximum number of items handled on a tick.
+	MaxItemsPerRun int32
+	// Tick makes the manager handle due items. The manager stops when it's
+	// closed.
+	Tick <-chan time.Time
+	// Wake optionally makes the manager handle due items between ticks.
+	Wake <-chan struct{}
+}
+
+// Stats contains the items handled in one run of the Manager.
+type Stats struct {
+	Succeeded []uuid.UUID
+	Retried   []uuid.UUID
+	Failed    []uuid.UUID
+	Error     error
+}
+
+// Manager handles the items of a queue on every tick.
+type Manager[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	handler   Handler[T]
+	log       slog.Logger
+	opts      Options
+	stats     chan<- Stats
+	lastPurge time.Time
+}
+
+// NewManager returns a Manager that handles items with the handler.
+func NewManager[T any](ctx context.Context, log slog.Logger, handler Handler[T], opts Options) *Manager[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Manager[T]{
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		handler: handler,
+		log:     log,
+		opts:    opts,
+	}
+}
+
+// WithStatsChannel will cause Manager to push Stats to ch after every run.
+// This push is blocking, so if ch is not read, the manager will hang. This
+// should only be used in tests.
+func (m *Manager[T]) WithStatsChannel(ch chan<- Stats) *Manager[T] {
+	m.stats = ch
+	return m
+}
+
+// Start will cause the manager to handle items on every tick from its
+// channel, and when woken up. It will stop when its context is Done, or when
+// its channel is closed.
+//
+// Start should only be called once.
+func (m *Manager[T]) Start() {
+	go func() {
+		defer close(m.done)
+		defer m.cancel()
+
+		for {
+			var t time.Time
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-m.opts.Wake:
+				t = database.Now()
+			case tt, ok := <-m.opts.Tick:
+				if !ok {
+					return
+				}
+				t = tt
+			}
+			stats := m.run(t)
+			if stats.Error != nil {
+				m.log.Warn(m.ctx, "error handling queue", slog.Error(stats.Error))
+			}
+			if m.stats != nil {
+				select {
+				case <-m.ctx.Done():
+					return
+				case m.stats <- stats:
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the manager.
+func (m *Manager[T]) Close() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *Manager[T]) run(t time.Time) Stats {
+	stats := Stats{}
+	if t.Sub(m.lastPurge) >= PurgeInterval {
+		err := m.handler.Purge(m.ctx, t.Add(-m.opts.Retention))
+		if err != nil {
+			m.log.Warn(m.ctx, "purge queue", slog.Error(err))
+		} else {
+			m.lastPurge = t
+		}
+	}
+
+	items, err := m.handler.Acquire(m.ctx, t, t.Add(m.opts.LeasePeriod), m.opts.MaxItemsPerRun)
+	if err != nil {
+		stats.Error = xerrors.Errorf("acquire: %w", err)
+		return stats
+	}
+	for _, item := range items {
+		id := m.handler.ID(item)
+		switch m.handler.Handle(m.ctx, item) {
+		case Succeeded:
+			stats.Succeeded = append(stats.Succeeded, id)
+		case Retried:
+			stats.Retried = append(stats.Retried, id)
+		case Failed:
+			stats.Failed = append(stats.Failed, id)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,132 @@
+package queue_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/queue"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type fakeHandler struct {
+	mutex   sync.Mutex
+	items   map[uuid.UUID]queue.Result
+	leases  []time.Time
+	purges  []time.Time
+	handled []uuid.UUID
+}
+
+func (*fakeHandler) ID(id uuid.UUID) uuid.UUID {
+	return id
+}
+
+func (h *fakeHandler) Acquire(_ context.Context, _, leasedUntil time.Time, count int32) ([]uuid.UUID, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.leases = append(h.leases, leasedUntil)
+	ids := make([]uuid.UUID, 0, count)
+	for id := range h.items {
+		if len(ids) == int(count) {
+			break
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (h *fakeHandler) Handle(_ context.Context, id uuid.UUID) queue.Result {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.handled = append(h.handled, id)
+	result := h.items[id]
+	if result != queue.Retried {
+		delete(h.items, id)
+	}
+	return result
+}
+
+func (h *fakeHandler) Purge(_ context.Context, before time.Time) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.purges = append(h.purges, before)
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	var (
+		succeeded = uuid.New()
+		retried   = uuid.New()
+		failed    = uuid.New()
+		handler   = &fakeHandler{items: map[uuid.UUID]queue.Result{
+			succeeded: queue.Succeeded,
+			retried:   queue.Retried,
+			failed:    queue.Failed,
+		}}
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan queue.Stats)
+	)
+	manager := queue.NewManager[uuid.UUID](context.Background(), slogtest.Make(t, nil), handler, queue.Options{
+		LeasePeriod:    time.Minute,
+		Retention:      24 * time.Hour,
+		MaxItemsPerRun: 10,
+		Tick:           tickCh,
+	}).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	now := time.Now()
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{succeeded}, stats.Succeeded)
+	require.Equal(t, []uuid.UUID{retried}, stats.Retried)
+	require.Equal(t, []uuid.UUID{failed}, stats.Failed)
+
+	// Finished items are only purged once per interval.
+	tickCh <- now.Add(time.Minute)
+	stats = <-statsCh
+	require.Equal(t, []uuid.UUID{retried}, stats.Retried)
+	tickCh <- now.Add(queue.PurgeInterval)
+	<-statsCh
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	require.Equal(t, []time.Time{now.Add(-24 * time.Hour), now.Add(queue.PurgeInterval - 24*time.Hour)}, handler.purges)
+	require.Equal(t, now.Add(time.Minute), handler.leases[0])
+	require.Len(t, handler.handled, 5)
+}
+
+func TestManager_Wake(t *testing.T) {
+	t.Parallel()
+
+	var (
+		id      = uuid.New()
+		handler = &fakeHandler{items: map[uuid.UUID]queue.Result{id: queue.Succeeded}}
+		wake    = make(chan struct{})
+		statsCh = make(chan queue.Stats)
+	)
+	manager := queue.NewManager[uuid.UUID](context.Background(), slogtest.Make(t, nil), handler, queue.Options{
+		MaxItemsPerRun: 10,
+		Tick:           make(chan time.Time),
+		Wake:           wake,
+	}).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	wake <- struct{}{}
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{id}, stats.Succeeded)
+}
@@ -151,6 +151,44 @@ var (
 		Type: "license",
 	}
 
+	// ResourceCustomRole is a role defined by admins in the 'custom_roles'
+	// table. Custom site roles are site wide, custom organization roles are
+	// scoped to their organization.
+	//	create/delete = define or remove roles
+	//	read = view the permissions of roles
+	//	update = change the display name or permissions of roles
+	ResourceCustomRole = Object{
+		Type: "custom_role",
+	}
+
+	// ResourceWebhook is an outbound webhook in the 'webhooks' table.
+	// ResourceWebhook is site wide.
+	//	create/delete = register or remove webhook endpoints
+	//	read = view webhooks and their deliveries
+	//	update = change the endpoint, secret or events of a webhook
+	ResourceWebhook = Object{
+		Type: "webhook",
+	}
+
+	// ResourceOAuth2ProviderApp is an app registered in the
+	// 'oauth2_provider_apps' table to authenticate users against Coder.
+	// ResourceOAuth2ProviderApp is site wide.
+	//	create/delete = register or remove apps
+	//	read = view the name, icon and callback URL of apps
+	//	update = change the name, icon or callback URL of apps
+	ResourceOAuth2ProviderApp = Object{
+		Type: "oauth2_app",
+	}
+
+	// ResourceOAuth2ProviderAppSecret is a client secret of an OAuth2 provider
+	// app. Only the prefix of a secret can be read, the secret itself is
+	// only shown when it's created.
+	//	create/delete = generate or revoke client secrets
+	//	read = view the prefixes and last use of client secrets
+	ResourceOAuth2ProviderAppSecret = Object{
+		Type: "oauth2_app_secret",
+	}
+
 	// ResourceDeploymentValues
 	ResourceDeploymentValues = Object{
 		Type: "deployment_config",
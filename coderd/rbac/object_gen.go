@@ -5,12 +5,15 @@ func AllResources() []Object {
 	return []Object{
 		ResourceAPIKey,
 		ResourceAuditLog,
+		ResourceCustomRole,
 		ResourceDebugInfo,
 		ResourceDeploymentStats,
 		ResourceDeploymentValues,
 		ResourceFile,
 		ResourceGroup,
 		ResourceLicense,
+		ResourceOAuth2ProviderApp,
+		ResourceOAuth2ProviderAppSecret,
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
 		ResourceOrganizationMember,
@@ -22,6 +25,7 @@ func AllResources() []Object {
 		ResourceTemplate,
 		ResourceUser,
 		ResourceUserData,
+		ResourceWebhook,
 		ResourceWildcard,
 		ResourceWorkspace,
 		ResourceWorkspaceApplicationConnect,
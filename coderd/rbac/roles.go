@@ -150,6 +150,9 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			ResourceRoleAssignment.Type: {ActionRead},
 			// All users can see the provisioner daemons.
 			ResourceProvisionerDaemon.Type: {ActionRead},
+			// All users can see the apps they can authorize to act on their
+			// behalf.
+			ResourceOAuth2ProviderApp.Type: {ActionRead},
 		}),
 		Org:  map[string][]Permission{},
 		User: allPermsExcept(),
@@ -283,10 +286,13 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 //	map[actor_role][assign_role]<can_assign>
 var assignRoles = map[string]map[string]bool{
 	"system": {
-		owner:     true,
-		member:    true,
-		orgAdmin:  true,
-		orgMember: true,
+		owner:         true,
+		auditor:       true,
+		member:        true,
+		orgAdmin:      true,
+		orgMember:     true,
+		templateAdmin: true,
+		userAdmin:     true,
 	},
 	owner: {
 		owner:         true,
@@ -363,7 +369,7 @@ func (roles Roles) Expand() ([]Role, error) {
 func (roles Roles) Names() []string {
 	names := make([]string, 0, len(roles))
 	for _, r := range roles {
-		return append(names, r.Name)
+		names = append(names, r.Name)
 	}
 	return names
 }
@@ -379,6 +385,8 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 	if err != nil {
 		return false
 	}
+	// Roles that aren't built-in are custom roles defined by admins.
+	_, builtIn := builtInRoles[assigned]
 
 	for _, longRole := range roles {
 		role, orgID, err := roleSplit(longRole)
@@ -391,6 +399,15 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 			continue
 		}
 
+		if !builtIn {
+			// Custom roles can be assigned by owners and the system, and custom
+			// organization roles by the admins of their organization.
+			if role == owner || role == "system" || (assignedOrg != "" && role == orgAdmin) {
+				return true
+			}
+			continue
+		}
+
 		allowed, ok := assignRoles[role]
 		if !ok {
 			continue
@@ -431,6 +448,19 @@ func RoleByName(name string) (Role, error) {
 	return role, nil
 }
 
+// IsBuiltInRoleName returns true if the role name, without its organization
+// scope, is reserved by the built-in roles. Custom roles cannot use these
+// names.
+func IsBuiltInRoleName(name string) bool {
+	roleName, _, err := roleSplit(name)
+	if err != nil {
+		return false
+	}
+	_, builtIn := builtInRoles[roleName]
+	_, assigns := assignRoles[roleName]
+	return builtIn || assigns
+}
+
 func rolesByNames(roleNames []string) ([]Role, error) {
 	roles := make([]Role, 0, len(roleNames))
 	for _, n := range roleNames {
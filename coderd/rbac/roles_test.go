@@ -444,6 +444,82 @@ func TestListRoles(t *testing.T) {
 		orgRoleNames)
 }
 
+func TestCanAssignCustomRole(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	otherOrgID := uuid.New()
+	siteRole := "restarter"
+	orgRole := "restarter:" + orgID.String()
+
+	testCases := []struct {
+		Name      string
+		Actor     []string
+		CanAssign map[string]bool
+	}{
+		{
+			Name:      "Owner",
+			Actor:     []string{rbac.RoleOwner(), rbac.RoleMember()},
+			CanAssign: map[string]bool{siteRole: true, orgRole: true},
+		},
+		{
+			// Roles synced from identity providers are assigned by the system.
+			Name:      "System",
+			Actor:     []string{"system"},
+			CanAssign: map[string]bool{siteRole: true, orgRole: true},
+		},
+		{
+			Name:      "OrgAdmin",
+			Actor:     []string{rbac.RoleMember(), rbac.RoleOrgAdmin(orgID)},
+			CanAssign: map[string]bool{siteRole: false, orgRole: true},
+		},
+		{
+			Name:      "OtherOrgAdmin",
+			Actor:     []string{rbac.RoleMember(), rbac.RoleOrgAdmin(otherOrgID)},
+			CanAssign: map[string]bool{siteRole: false, orgRole: false},
+		},
+		{
+			Name:      "UserAdmin",
+			Actor:     []string{rbac.RoleMember(), rbac.RoleUserAdmin()},
+			CanAssign: map[string]bool{siteRole: false, orgRole: false},
+		},
+		{
+			// Custom roles cannot borrow the assignments of built-in roles.
+			Name:      "CustomRole",
+			Actor:     []string{rbac.RoleMember(), siteRole},
+			CanAssign: map[string]bool{siteRole: false, orgRole: false, rbac.RoleMember(): false},
+		},
+	}
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			for role, expected := range c.CanAssign {
+				require.Equal(t, expected, rbac.CanAssignRole(rbac.RoleNames(c.Actor), role), role)
+			}
+		})
+	}
+}
+
+func TestIsBuiltInRoleName(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, rbac.IsBuiltInRoleName(rbac.RoleOwner()))
+	require.True(t, rbac.IsBuiltInRoleName(rbac.RoleOrgAdmin(uuid.New())))
+	require.True(t, rbac.IsBuiltInRoleName("organization-member"))
+	// The system actor can assign roles, so the name is reserved as well.
+	require.True(t, rbac.IsBuiltInRoleName("system"))
+	require.False(t, rbac.IsBuiltInRoleName("restarter"))
+	require.False(t, rbac.IsBuiltInRoleName("restarter:"+uuid.NewString()))
+}
+
+func TestRolesNames(t *testing.T) {
+	t.Parallel()
+
+	roles := rbac.Roles{{Name: "a"}, {Name: "b"}}
+	require.Equal(t, []string{"a", "b"}, roles.Names())
+}
+
 func TestChangeSet(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
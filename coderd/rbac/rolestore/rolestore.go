@@ -0,0 +1,62 @@
+// Package rolestore expands role names into roles, looking up the custom roles
+// defined by admins in the database.
+package rolestore
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/rbac"
+)
+
+// Expand expands the role names of a user into roles. Built-in roles are
+// expanded by the rbac package, the others are looked up as custom roles.
+// Custom roles that no longer exist are skipped, as they grant nothing.
+func Expand(ctx context.Context, db database.Store, names []string) (rbac.Roles, error) {
+	roles := make(rbac.Roles, 0, len(names))
+	lookup := make([]string, 0)
+	for _, name := range names {
+		role, err := rbac.RoleByName(name)
+		if err == nil {
+			roles = append(roles, role)
+			continue
+		}
+		if rbac.IsBuiltInRoleName(name) {
+			return nil, xerrors.Errorf("expand role %q: %w", name, err)
+		}
+		lookup = append(lookup, name)
+	}
+	if len(lookup) == 0 {
+		return roles, nil
+	}
+
+	//nolint:gocritic // The roles of an actor are expanded before it exists.
+	customRoles, err := db.GetCustomRolesByNames(dbauthz.AsSystemRestricted(ctx), lookup)
+	if err != nil {
+		return nil, xerrors.Errorf("get custom roles: %w", err)
+	}
+	for _, role := range customRoles {
+		roles = append(roles, ConvertDBRole(role))
+	}
+	return roles, nil
+}
+
+// ConvertDBRole converts a custom role into the role evaluated by the rego
+// policy. Organization roles only grant their permissions in their
+// organization.
+func ConvertDBRole(role database.CustomRole) rbac.Role {
+	converted := rbac.Role{
+		Name:        role.RoleName(),
+		DisplayName: role.DisplayName,
+		Site:        append([]rbac.Permission{}, role.SitePermissions...),
+		Org:         map[string][]rbac.Permission{},
+		User:        append([]rbac.Permission{}, role.UserPermissions...),
+	}
+	if role.OrganizationID.Valid {
+		converted.Org[role.OrganizationID.UUID.String()] = append([]rbac.Permission{}, role.OrgPermissions...)
+	}
+	return converted
+}
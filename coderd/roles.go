@@ -3,7 +3,10 @@ package coderd
 import (
 	"net/http"
 
+	"github.com/google/uuid"
+
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/codersdk"
 
 	"github.com/coder/coder/coderd/httpapi"
@@ -28,6 +31,11 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.SiteRoles()
+	customRoles, ok := api.assignableCustomRoles(rw, r, uuid.Nil)
+	if !ok {
+		return
+	}
+	roles = append(roles, customRoles...)
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
@@ -52,9 +60,34 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.OrganizationRoles(organization.ID)
+	customRoles, ok := api.assignableCustomRoles(rw, r, organization.ID)
+	if !ok {
+		return
+	}
+	roles = append(roles, customRoles...)
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
+// assignableCustomRoles returns the custom site roles if the organization ID is
+// uuid.Nil, and the custom roles of the organization otherwise. Only the roles
+// the actor can read are returned.
+func (api *API) assignableCustomRoles(rw http.ResponseWriter, r *http.Request, organizationID uuid.UUID) ([]rbac.Role, bool) {
+	ctx := r.Context()
+	customRoles, err := api.Database.GetCustomRoles(ctx, organizationID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	roles := make([]rbac.Role, 0, len(customRoles))
+	for _, role := range customRoles {
+		roles = append(roles, rolestore.ConvertDBRole(role))
+	}
+	return roles, true
+}
+
 func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []codersdk.AssignableRoles {
 	assignable := make([]codersdk.AssignableRoles, 0)
 	for _, role := range roles {
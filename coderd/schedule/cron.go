@@ -42,6 +42,33 @@ func Weekly(raw string) (*Schedule, error) {
 		return nil, xerrors.Errorf("validate weekly schedule: %w", err)
 	}
 
+	return parse(raw)
+}
+
+// Standard parses a Schedule from spec using all five standard cron fields.
+// Unlike Weekly, the day of month and month fields may be set to any value.
+// Spec consists of the following space-delimited fields, in the following order:
+// - timezone e.g. CRON_TZ=US/Central (optional)
+// - minutes of hour e.g. 30 (required)
+// - hour of day e.g. 9 (required)
+// - day of month e.g. 1 (required)
+// - month e.g. 1-6 (required)
+// - day of week e.g. 1 (required)
+//
+// Example Usage:
+//
+//	sched, _ := schedule.Standard("0 3 1 * *")
+//	fmt.Println(sched.Next(time.Now()).Format(time.RFC3339))
+//	// Output: 2022-05-01T03:00:00Z
+func Standard(raw string) (*Schedule, error) {
+	if err := validateStandardSpec(raw); err != nil {
+		return nil, xerrors.Errorf("validate standard schedule: %w", err)
+	}
+
+	return parse(raw)
+}
+
+func parse(raw string) (*Schedule, error) {
 	// If schedule does not specify a timezone, default to UTC. Otherwise,
 	// the library will default to time.Local which we want to avoid.
 	if !strings.HasPrefix(raw, "CRON_TZ=") {
@@ -179,10 +206,10 @@ func (s Schedule) DaysOfWeek() string {
 // validateWeeklySpec ensures that the day-of-month and month options of
 // spec are both set to *
 func validateWeeklySpec(spec string) error {
-	parts := strings.Fields(spec)
-	if len(parts) < 5 {
-		return xerrors.Errorf("expected schedule to consist of 5 fields with an optional CRON_TZ=<timezone> prefix")
+	if err := validateStandardSpec(spec); err != nil {
+		return err
 	}
+	parts := strings.Fields(spec)
 	if len(parts) == 6 {
 		parts = parts[1:]
 	}
@@ -191,3 +218,13 @@ func validateWeeklySpec(spec string) error {
 	}
 	return nil
 }
+
+// validateStandardSpec ensures that spec consists of five fields with an
+// optional CRON_TZ prefix.
+func validateStandardSpec(spec string) error {
+	parts := strings.Fields(spec)
+	if len(parts) < 5 {
+		return xerrors.Errorf("expected schedule to consist of 5 fields with an optional CRON_TZ=<timezone> prefix")
+	}
+	return nil
+}
@@ -163,6 +163,67 @@ func Test_Weekly(t *testing.T) {
 	}
 }
 
+func Test_Standard(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name             string
+		spec             string
+		at               time.Time
+		expectedNext     time.Time
+		expectedError    string
+		expectedCron     string
+		expectedLocation *time.Location
+		expectedString   string
+	}{
+		{
+			name:             "every minute",
+			spec:             "* * * * *",
+			at:               time.Date(2022, 4, 1, 14, 29, 30, 0, time.UTC),
+			expectedNext:     time.Date(2022, 4, 1, 14, 30, 0, 0, time.UTC),
+			expectedCron:     "* * * * *",
+			expectedLocation: time.UTC,
+			expectedString:   "CRON_TZ=UTC * * * * *",
+		},
+		{
+			name:             "first of the month with timezone",
+			spec:             "CRON_TZ=US/Central 0 3 1 * *",
+			at:               time.Date(2022, 4, 1, 9, 0, 0, 0, time.UTC),
+			expectedNext:     time.Date(2022, 5, 1, 8, 0, 0, 0, time.UTC),
+			expectedCron:     "0 3 1 * *",
+			expectedLocation: mustLocation(t, "US/Central"),
+			expectedString:   "CRON_TZ=US/Central 0 3 1 * *",
+		},
+		{
+			name:          "invalid schedule with 3 fields",
+			spec:          "30 9 1-5",
+			expectedError: "validate standard schedule: expected schedule to consist of 5 fields with an optional CRON_TZ=<timezone> prefix",
+		},
+		{
+			name:          "invalid location",
+			spec:          "CRON_TZ=Fictional/Country 30 9 1 1 1-5",
+			expectedError: "parse schedule: provided bad location Fictional/Country: unknown time zone Fictional/Country",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := schedule.Standard(testCase.spec)
+			if testCase.expectedError == "" {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedNext, actual.Next(testCase.at))
+				require.Equal(t, testCase.expectedCron, actual.Cron())
+				require.Equal(t, testCase.expectedLocation, actual.Location())
+				require.Equal(t, testCase.expectedString, actual.String())
+			} else {
+				require.EqualError(t, err, testCase.expectedError)
+				require.Nil(t, actual)
+			}
+		})
+	}
+}
+
 func mustLocation(t *testing.T, s string) *time.Location {
 	t.Helper()
 	loc, err := time.LoadLocation(s)
@@ -0,0 +1,237 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// @Summary Upload workspace agent session recording chunk
+// @ID upload-workspace-agent-session-recording-chunk
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostSessionRecordingRequest true "Session recording chunk"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/session-recordings [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostSessionRecordingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	recordingType := database.SessionRecordingType(req.Type)
+	if !recordingType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid session recording type provided.",
+			Detail:  fmt.Sprintf("invalid session recording type: %q", req.Type),
+		})
+		return
+	}
+
+	recording, err := api.Database.GetSessionRecordingByID(ctx, req.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// The first chunk that arrives creates the recording.
+		recording, err = api.insertSessionRecording(ctx, workspaceAgent, recordingType, req)
+	}
+	// Recordings of other workspaces can't be read by the agent, and a
+	// recording of another agent of the same workspace isn't this agent's
+	// to append to either.
+	if dbauthz.IsNotAuthorizedError(err) || database.IsUniqueViolation(err) ||
+		(err == nil && recording.AgentID != workspaceAgent.ID) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Session recording not found.",
+			Detail:  fmt.Sprintf("Session recording %s belongs to another agent.", req.ID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if len(req.Data) > 0 {
+		err = api.Database.InsertSessionRecordingChunk(ctx, database.InsertSessionRecordingChunkParams{
+			RecordingID: recording.ID,
+			Sequence:    req.Sequence,
+			Data:        req.Data,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error inserting session recording chunk.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	endedAt := recording.EndedAt
+	if req.Final {
+		endedAt = sql.NullTime{Time: database.Now(), Valid: true}
+	}
+	// Also refreshes the size of the recording.
+	err = api.Database.UpdateSessionRecordingByID(ctx, database.UpdateSessionRecordingByIDParams{
+		ID:      recording.ID,
+		EndedAt: endedAt,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// insertSessionRecording creates a recording tied to the current build of
+// the agent's workspace and the user the agent attributed the session to.
+func (api *API) insertSessionRecording(ctx context.Context, workspaceAgent database.WorkspaceAgent, recordingType database.SessionRecordingType, req agentsdk.PostSessionRecordingRequest) (database.SessionRecording, error) {
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		return database.SessionRecording{}, xerrors.Errorf("get workspace resource: %w", err)
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		return database.SessionRecording{}, xerrors.Errorf("get workspace build: %w", err)
+	}
+	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return database.SessionRecording{}, xerrors.Errorf("get workspace: %w", err)
+	}
+	startedAt := req.StartedAt
+	if startedAt.IsZero() {
+		startedAt = database.Now()
+	}
+	recording, err := api.Database.InsertSessionRecording(ctx, database.InsertSessionRecordingParams{
+		ID:               req.ID,
+		WorkspaceID:      workspace.ID,
+		WorkspaceBuildID: build.ID,
+		AgentID:          workspaceAgent.ID,
+		UserID:           uuid.NullUUID{UUID: req.UserID, Valid: req.UserID != uuid.Nil},
+		Type:             recordingType,
+		StartedAt:        startedAt,
+	})
+	if err != nil {
+		return database.SessionRecording{}, xerrors.Errorf("insert session recording: %w", err)
+	}
+	return recording, nil
+}
+
+// @Summary Get session recordings of workspace
+// @ID get-session-recordings-of-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.SessionRecording
+// @Router /workspaces/{workspace}/session-recordings [get]
+func (api *API) workspaceSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	recordings, err := api.Database.GetSessionRecordingsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recordings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiRecordings := make([]codersdk.SessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		apiRecordings = append(apiRecordings, convertSessionRecording(recording))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiRecordings)
+}
+
+// @Summary Download session recording of workspace
+// @ID download-session-recording-of-workspace
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param recording path string true "Session recording ID" format(uuid)
+// @Success 200
+// @Router /workspaces/{workspace}/session-recordings/{recording} [get]
+func (api *API) workspaceSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	recordingID, err := uuid.Parse(chi.URLParam(r, "recording"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Session recording id must be a valid UUID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	recording, err := api.Database.GetSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) || (err == nil && recording.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	chunks, err := api.Database.GetSessionRecordingChunksByRecordingID(ctx, recording.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording chunks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-asciicast")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", recording.ID.String()+".cast"))
+	rw.WriteHeader(http.StatusOK)
+	for _, chunk := range chunks {
+		_, err = rw.Write(chunk.Data)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func convertSessionRecording(recording database.SessionRecording) codersdk.SessionRecording {
+	apiRecording := codersdk.SessionRecording{
+		ID:               recording.ID,
+		WorkspaceID:      recording.WorkspaceID,
+		WorkspaceBuildID: recording.WorkspaceBuildID,
+		AgentID:          recording.AgentID,
+		Type:             codersdk.SessionRecordingType(recording.Type),
+		StartedAt:        recording.StartedAt,
+		Size:             recording.Size,
+	}
+	if recording.UserID.Valid {
+		apiRecording.UserID = &recording.UserID.UUID
+	}
+	if recording.EndedAt.Valid {
+		apiRecording.EndedAt = &recording.EndedAt.Time
+	}
+	return apiRecording
+}
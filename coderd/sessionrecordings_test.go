@@ -0,0 +1,196 @@
+package coderd_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	id := uuid.New()
+	startedAt := time.Now().Add(-time.Minute)
+	chunks := []string{
+		"{\"version\":2,\"width\":80,\"height\":24,\"timestamp\":0}\n",
+		"[0.5,\"o\",\"hello\"]\n",
+	}
+	for i, chunk := range chunks {
+		err := agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+			ID:        id,
+			Type:      codersdk.SessionRecordingTypeSSH,
+			StartedAt: startedAt,
+			UserID:    user.UserID,
+			Sequence:  int32(i),
+			Data:      []byte(chunk),
+		})
+		require.NoError(t, err)
+	}
+
+	// The recording is listed while it's in progress.
+	recordings, err := client.WorkspaceSessionRecordings(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, recordings, 1)
+	require.Equal(t, id, recordings[0].ID)
+	require.Equal(t, build.ID, recordings[0].WorkspaceBuildID)
+	require.NotNil(t, recordings[0].UserID)
+	require.Equal(t, user.UserID, *recordings[0].UserID)
+	require.Equal(t, codersdk.SessionRecordingTypeSSH, recordings[0].Type)
+	require.Nil(t, recordings[0].EndedAt)
+
+	// Retried chunks are ignored.
+	err = agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+		ID:       id,
+		Type:     codersdk.SessionRecordingTypeSSH,
+		Sequence: 1,
+		Data:     []byte(chunks[1]),
+	})
+	require.NoError(t, err)
+	err = agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+		ID:       id,
+		Type:     codersdk.SessionRecordingTypeSSH,
+		Sequence: 2,
+		Final:    true,
+	})
+	require.NoError(t, err)
+
+	recordings, err = client.WorkspaceSessionRecordings(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, recordings, 1)
+	require.NotNil(t, recordings[0].EndedAt)
+	require.EqualValues(t, len(chunks[0])+len(chunks[1]), recordings[0].Size)
+
+	body, err := client.WorkspaceSessionRecording(ctx, workspace.ID, id)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, chunks[0]+chunks[1], string(data))
+
+	_, err = client.WorkspaceSessionRecording(ctx, workspace.ID, uuid.New())
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Agents can't append to the recordings of other agents.
+	otherAuthToken := uuid.NewString()
+	otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(otherAuthToken),
+	})
+	otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+	otherWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, otherTemplate.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, otherWorkspace.LatestBuild.ID)
+	otherAgentClient := agentsdk.New(client.URL)
+	otherAgentClient.SetSessionToken(otherAuthToken)
+	err = otherAgentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+		ID:       id,
+		Type:     codersdk.SessionRecordingTypeSSH,
+		Sequence: 3,
+		Data:     []byte("[1,\"o\",\"injected\"]\n"),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestWorkspaceSessionRecordings_ConnectingUser(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.SessionRecording = true
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		DeploymentValues:         dv,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	// The workspace belongs to the member, the owner connects to it.
+	workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, &codersdk.DialWorkspaceAgentOptions{
+		Logger: slogtest.Make(t, nil).Named("client"),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+	require.NoError(t, err)
+	_, err = session.Output("echo hello")
+	require.NoError(t, err)
+
+	var recordings []codersdk.SessionRecording
+	require.Eventually(t, func() bool {
+		recordings, err = client.WorkspaceSessionRecordings(ctx, workspace.ID)
+		return err == nil && len(recordings) == 1 && recordings[0].EndedAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.NotNil(t, recordings[0].UserID)
+	require.Equal(t, user.UserID, *recordings[0].UserID)
+}
@@ -227,6 +227,7 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 		maxTTL        time.Duration
 		failureTTL    time.Duration
 		inactivityTTL time.Duration
+		idleThreshold time.Duration
 	)
 	if createTemplate.DefaultTTLMillis != nil {
 		defaultTTL = time.Duration(*createTemplate.DefaultTTLMillis) * time.Millisecond
@@ -240,6 +241,9 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if createTemplate.InactivityTTLMillis != nil {
 		inactivityTTL = time.Duration(*createTemplate.InactivityTTLMillis) * time.Millisecond
 	}
+	if createTemplate.IdleThresholdMillis != nil {
+		idleThreshold = time.Duration(*createTemplate.IdleThresholdMillis) * time.Millisecond
+	}
 
 	var validErrs []codersdk.ValidationError
 	if defaultTTL < 0 {
@@ -257,6 +261,9 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if inactivityTTL < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "inactivity_ttl_ms", Detail: "Must be a positive integer."})
 	}
+	if idleThreshold < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "idle_threshold_ms", Detail: "Must be a positive integer."})
+	}
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid create template request.",
@@ -297,6 +304,7 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 			DisplayName:                  createTemplate.DisplayName,
 			Icon:                         createTemplate.Icon,
 			AllowUserCancelWorkspaceJobs: allowUserCancelWorkspaceJobs,
+			IdleThreshold:                int64(idleThreshold),
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template: %s", err)
@@ -503,6 +511,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.LockedTTLMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "locked_ttl_ms", Detail: "Must be a positive integer."})
 	}
+	if req.IdleThresholdMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "idle_threshold_ms", Detail: "Must be a positive integer."})
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -525,7 +536,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.MaxTTLMillis == time.Duration(template.MaxTTL).Milliseconds() &&
 			req.FailureTTLMillis == time.Duration(template.FailureTTL).Milliseconds() &&
 			req.InactivityTTLMillis == time.Duration(template.InactivityTTL).Milliseconds() &&
-			req.FailureTTLMillis == time.Duration(template.LockedTTL).Milliseconds() {
+			req.FailureTTLMillis == time.Duration(template.LockedTTL).Milliseconds() &&
+			req.IdleThresholdMillis == time.Duration(template.IdleThreshold).Milliseconds() {
 			return nil
 		}
 
@@ -544,6 +556,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			Description:                  req.Description,
 			Icon:                         req.Icon,
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
+			IdleThreshold:                int64(time.Duration(req.IdleThresholdMillis) * time.Millisecond),
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -592,6 +605,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 	aReq.New = updated
+	api.idleThresholds.Forget(updated.ID)
 
 	createdByNameMap, err := getCreatedByNamesByTemplateIDs(ctx, api.Database, []database.Template{updated})
 	if err != nil {
@@ -727,5 +741,6 @@ func (api *API) convertTemplate(
 		FailureTTLMillis:             time.Duration(template.FailureTTL).Milliseconds(),
 		InactivityTTLMillis:          time.Duration(template.InactivityTTL).Milliseconds(),
 		LockedTTLMillis:              time.Duration(template.LockedTTL).Milliseconds(),
+		IdleThresholdMillis:          time.Duration(template.IdleThreshold).Milliseconds(),
 	}
 }
@@ -3,6 +3,7 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -26,7 +27,9 @@ import (
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/coderd/userpassword"
+	"github.com/coder/coder/coderd/util/slice"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -131,9 +134,18 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expanded, err := rolestore.Expand(ctx, api.Database, roles.Roles)
+	if err != nil {
+		logger.Error(ctx, "unable to expand authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
+		Roles:  expanded,
 		Groups: roles.Groups,
 		Scope:  rbac.ScopeAll,
 	}
@@ -200,7 +212,7 @@ func (api *API) postLogout(rw http.ResponseWriter, r *http.Request) {
 
 	logger := api.Logger.Named(userAuthLoggerName)
 
-	err := api.Database.DeleteAPIKeyByID(ctx, apiKey.ID)
+	_, err := api.Database.DeleteAPIKeyByID(ctx, apiKey.ID)
 	if err != nil {
 		logger.Error(ctx, "unable to delete API key", slog.F("api_key", apiKey.ID), slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -495,12 +507,26 @@ type OIDCConfig struct {
 	// to groups within Coder.
 	// map[oidcGroupName]coderGroupName
 	GroupMapping map[string]string
+	// UserRoleField selects the claim field to be used as the user's site and
+	// organization roles, which are synced at each login. If the role field is
+	// the empty string, then roles are managed in Coder instead.
+	UserRoleField string
+	// UserRoleMapping controls how roles returned by the OIDC provider get
+	// mapped to roles within Coder. Roles that aren't mapped are used by name.
+	// map[oidcRoleName][]coderRoleName
+	UserRoleMapping map[string][]string
 	// SignInText is the text to display on the OIDC login button
 	SignInText string
 	// IconURL points to the URL of an icon to display on the OIDC login button
 	IconURL string
 }
 
+// RoleSyncEnabled returns whether the roles of OIDC users are synced from the
+// OIDC provider.
+func (cfg *OIDCConfig) RoleSyncEnabled() bool {
+	return cfg != nil && cfg.UserRoleField != ""
+}
+
 // @Summary OpenID Connect Callback
 // @ID openid-connect-callback
 // @Security CoderSessionToken
@@ -520,9 +546,19 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 			Request: r,
 			Action:  database.AuditActionLogin,
 		})
+		// Role changes made by role sync are audited separately from the
+		// login. Nothing is audited if the roles didn't change.
+		rolesAuditParams = &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		}
+		rolesAudit, commitRolesAudit = audit.InitRequest[database.User](rw, rolesAuditParams)
 	)
 	aReq.Old = database.APIKey{}
 	defer commitAudit()
+	defer commitRolesAudit()
 
 	// See the example here: https://github.com/coreos/go-oidc
 	rawIDToken, ok := state.Token.Extra("id_token").(string)
@@ -701,6 +737,47 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var usingRoles bool
+	var roles []string
+	// If the UserRoleField is the empty string, then roles from OIDC are not
+	// used. This is so we can support manual role assignment.
+	if api.OIDCConfig.RoleSyncEnabled() {
+		usingRoles = true
+		rolesRaw, ok := claims[api.OIDCConfig.UserRoleField]
+		switch typed := rolesRaw.(type) {
+		case []interface{}:
+			for _, roleInterface := range typed {
+				role, ok := roleInterface.(string)
+				if !ok {
+					httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+						Message: fmt.Sprintf("Invalid role type. Expected string, got: %T", roleInterface),
+					})
+					return
+				}
+				roles = append(roles, role)
+			}
+		case string:
+			// Some providers return a single role as a string.
+			roles = []string{typed}
+		default:
+			if ok {
+				// Syncing would remove the roles of everyone logging in, so
+				// this is most likely a misconfiguration.
+				usingRoles = false
+				logger.Warn(ctx, "role field was an unknown type, not syncing roles",
+					slog.F("field", api.OIDCConfig.UserRoleField),
+					slog.F("type", fmt.Sprintf("%T", rolesRaw)),
+				)
+			}
+			// Providers such as Azure AD omit the claim when a user has no
+			// roles, so a missing claim removes all synced roles.
+		}
+		logger.Debug(ctx, "roles returned in oidc claims",
+			slog.F("len", len(roles)),
+			slog.F("roles", roles),
+		)
+	}
+
 	// This conditional is purely to warn the user they might have misconfigured their OIDC
 	// configuration.
 	if _, groupClaimExists := claims["groups"]; !usingGroups && groupClaimExists {
@@ -791,6 +868,33 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	aReq.New = key
 	aReq.UserID = key.UserID
 
+	if usingRoles {
+		change, err := api.syncUserRoles(ctx, logger, key.UserID, roles)
+		if err != nil {
+			logger.Error(ctx, "oauth2: unable to sync user roles", slog.F("user_id", key.UserID), slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to sync user roles.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if change.Changed() {
+			rolesAudit.Old = change.Old
+			rolesAudit.New = change.New
+			rolesAudit.UserID = key.UserID
+			if len(change.OrganizationRoles) > 0 {
+				// Organization members aren't auditable resources, so their
+				// changes are attached to the audit log of the user.
+				rolesAuditParams.AdditionalFields, err = json.Marshal(map[string]interface{}{
+					"organization_roles": change.OrganizationRoles,
+				})
+				if err != nil {
+					logger.Warn(ctx, "marshal organization roles change", slog.Error(err))
+				}
+			}
+		}
+	}
+
 	http.SetCookie(rw, cookie)
 
 	redirect := state.Redirect
@@ -871,8 +975,9 @@ func (e httpError) Error() string {
 
 func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cookie, database.APIKey, error) {
 	var (
-		ctx  = r.Context()
-		user database.User
+		ctx     = r.Context()
+		user    database.User
+		created bool
 	)
 
 	err := api.Database.InTx(func(tx database.Store) error {
@@ -964,6 +1069,7 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 			if err != nil {
 				return xerrors.Errorf("create user: %w", err)
 			}
+			created = true
 		}
 
 		if link.UserID == uuid.Nil {
@@ -1049,6 +1155,9 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 	if err != nil {
 		return nil, database.APIKey{}, xerrors.Errorf("in tx: %w", err)
 	}
+	if created {
+		api.WebhookUserCreated(ctx, user)
+	}
 
 	//nolint:gocritic
 	cookie, key, err := api.createAPIKey(dbauthz.AsSystemRestricted(ctx), apikey.CreateParams{
@@ -1064,6 +1173,201 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 	return cookie, *key, nil
 }
 
+// rolesChange is the change of the roles of a user in an organization.
+type rolesChange struct {
+	Old []string `json:"old"`
+	New []string `json:"new"`
+}
+
+// userRolesChange is the change of the roles of a user by role sync.
+type userRolesChange struct {
+	Old database.User
+	New database.User
+	// OrganizationRoles holds the changes of the organizations the roles of
+	// the user changed in.
+	OrganizationRoles map[uuid.UUID]rolesChange
+}
+
+func (c userRolesChange) Changed() bool {
+	return !sameRoles(c.Old.RBACRoles, c.New.RBACRoles) || len(c.OrganizationRoles) > 0
+}
+
+// sameRoles returns whether a and b contain the same roles, in any order.
+func sameRoles(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, role := range a {
+		set[role] = true
+	}
+	other := make(map[string]bool, len(b))
+	for _, role := range b {
+		if !set[role] {
+			return false
+		}
+		other[role] = true
+	}
+	return len(set) == len(other)
+}
+
+// syncUserRoles replaces the site and organization roles of a user with the
+// roles claimed by the OIDC provider. Claimed roles are mapped by the
+// UserRoleMapping, and roles that don't exist in Coder are ignored.
+//
+// To prevent a misconfigured provider from locking everyone out, the owner
+// role is never removed by an empty claim, nor from the last active owner.
+func (api *API) syncUserRoles(ctx context.Context, logger slog.Logger, userID uuid.UUID, claimed []string) (userRolesChange, error) {
+	var names []string
+	for _, role := range claimed {
+		if role == "" {
+			continue
+		}
+		if mapped, ok := api.OIDCConfig.UserRoleMapping[role]; ok {
+			names = append(names, mapped...)
+			continue
+		}
+		names = append(names, role)
+	}
+
+	var change userRolesChange
+	err := api.Database.InTx(func(tx database.Store) error {
+		// The custom roles looked up below must still exist when they're
+		// assigned.
+		err := tx.AcquireLock(ctx, database.LockIDRoleAssignments)
+		if err != nil {
+			return xerrors.Errorf("acquire role assignments lock: %w", err)
+		}
+		user, err := tx.GetUserByID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("get user: %w", err)
+		}
+		change = userRolesChange{Old: user, New: user}
+
+		customNames := make([]string, 0)
+		for _, name := range names {
+			if _, err := rbac.RoleByName(name); err != nil && !rbac.IsBuiltInRoleName(name) {
+				customNames = append(customNames, name)
+			}
+		}
+		customRoles := make(map[string]bool)
+		if len(customNames) > 0 {
+			found, err := tx.GetCustomRolesByNames(ctx, customNames)
+			if err != nil {
+				return xerrors.Errorf("get custom roles: %w", err)
+			}
+			for _, role := range found {
+				customRoles[role.RoleName()] = true
+			}
+		}
+
+		siteRoles := make([]string, 0)
+		orgRoles := make(map[uuid.UUID][]string)
+		for _, name := range names {
+			if _, err := rbac.RoleByName(name); err != nil && !customRoles[name] {
+				logger.Debug(ctx, "ignoring unknown role returned in oidc claims", slog.F("role", name))
+				continue
+			}
+			orgID, isOrgRole := rbac.IsOrgRole(name)
+			if !isOrgRole {
+				// Everyone is a member implicitly.
+				if name != rbac.RoleMember() && !slice.Contains(siteRoles, name) {
+					siteRoles = append(siteRoles, name)
+				}
+				continue
+			}
+			organizationID, err := uuid.Parse(orgID)
+			if err != nil {
+				logger.Debug(ctx, "ignoring role of an invalid organization returned in oidc claims", slog.F("role", name))
+				continue
+			}
+			if name != rbac.RoleOrgMember(organizationID) && !slice.Contains(orgRoles[organizationID], name) {
+				orgRoles[organizationID] = append(orgRoles[organizationID], name)
+			}
+		}
+
+		if slice.Contains(user.RBACRoles, rbac.RoleOwner()) && len(names) == 0 {
+			// A dropped claim would otherwise demote every owner as they log
+			// in, so only a claim with roles can remove the owner role.
+			logger.Warn(ctx, "not removing the owner role of an owner with an empty role claim, check the role sync configuration",
+				slog.F("user_id", user.ID),
+				slog.F("username", user.Username),
+			)
+			siteRoles = append(siteRoles, rbac.RoleOwner())
+		}
+		if slice.Contains(user.RBACRoles, rbac.RoleOwner()) && !slice.Contains(siteRoles, rbac.RoleOwner()) {
+			// The owners are locked, so they can't be demoted or suspended
+			// concurrently, which could leave no owner at all.
+			owners, err := tx.GetActiveUsersByRoleForUpdate(ctx, rbac.RoleOwner())
+			if err != nil {
+				return xerrors.Errorf("get owners: %w", err)
+			}
+			lastOwner := true
+			for _, owner := range owners {
+				if owner.ID != user.ID {
+					lastOwner = false
+					break
+				}
+			}
+			if lastOwner {
+				logger.Warn(ctx, "not removing the owner role of the last active owner, check the role sync configuration",
+					slog.F("user_id", user.ID),
+					slog.F("username", user.Username),
+				)
+				siteRoles = append(siteRoles, rbac.RoleOwner())
+			}
+		}
+
+		if !sameRoles(user.RBACRoles, siteRoles) {
+			change.New, err = tx.UpdateUserRoles(ctx, database.UpdateUserRolesParams{
+				GrantedRoles: siteRoles,
+				ID:           user.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("update site roles: %w", err)
+			}
+		}
+
+		memberships, err := tx.GetOrganizationMembershipsByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("get organization memberships: %w", err)
+		}
+		for _, member := range memberships {
+			granted, ok := orgRoles[member.OrganizationID]
+			if !ok {
+				granted = []string{}
+			}
+			delete(orgRoles, member.OrganizationID)
+			current := make([]string, 0, len(member.Roles))
+			for _, role := range member.Roles {
+				if role != rbac.RoleOrgMember(member.OrganizationID) {
+					current = append(current, role)
+				}
+			}
+			if sameRoles(current, granted) {
+				continue
+			}
+			_, err = tx.UpdateMemberRoles(ctx, database.UpdateMemberRolesParams{
+				GrantedRoles: granted,
+				UserID:       user.ID,
+				OrgID:        member.OrganizationID,
+			})
+			if err != nil {
+				return xerrors.Errorf("update roles in organization %s: %w", member.OrganizationID, err)
+			}
+			if change.OrganizationRoles == nil {
+				change.OrganizationRoles = make(map[uuid.UUID]rolesChange)
+			}
+			change.OrganizationRoles[member.OrganizationID] = rolesChange{Old: current, New: granted}
+		}
+		for organizationID := range orgRoles {
+			logger.Debug(ctx, "ignoring roles of an organization the user isn't a member of", slog.F("organization_id", organizationID))
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return userRolesChange{}, err
+	}
+	return change, nil
+}
+
 // githubLinkedID returns the unique ID for a GitHub user.
 func githubLinkedID(u *github.User) string {
 	return strconv.FormatInt(u.GetID(), 10)
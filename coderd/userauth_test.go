@@ -26,6 +26,8 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/coderd/notifications"
+	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/testutil"
 )
@@ -263,8 +265,10 @@ func TestUserOAuth2Github(t *testing.T) {
 	t.Run("Signup", func(t *testing.T) {
 		t.Parallel()
 		auditor := audit.NewMock()
+		enqueuer := notifications.NewMock()
 		client := coderdtest.New(t, &coderdtest.Options{
-			Auditor: auditor,
+			Auditor:               auditor,
+			NotificationsEnqueuer: enqueuer,
 			GithubOAuth2Config: &coderd.GithubOAuth2Config{
 				OAuth2Config:       &testutil.OAuth2Config{},
 				AllowOrganizations: []string{"coder"},
@@ -310,6 +314,11 @@ func TestUserOAuth2Github(t *testing.T) {
 		require.Len(t, auditor.AuditLogs(), numLogs)
 		require.NotEqual(t, auditor.AuditLogs()[numLogs-1].UserID, uuid.Nil)
 		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
+
+		sent := enqueuer.Notifications()
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, codersdk.NotificationTemplateUserCreated, sent[0].Template)
 	})
 	t.Run("SignupAllowedTeam", func(t *testing.T) {
 		t.Parallel()
@@ -835,6 +844,183 @@ func TestUserOIDC(t *testing.T) {
 		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
 	})
 
+	t.Run("Roles", func(t *testing.T) {
+		t.Parallel()
+		t.Run("Sync", func(t *testing.T) {
+			t.Parallel()
+			auditor := audit.NewMock()
+			conf := coderdtest.NewOIDCConfig(t, "")
+
+			mapping := map[string][]string{}
+			config := conf.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+				cfg.UserRoleField = "roles"
+				cfg.UserRoleMapping = mapping
+			})
+			config.AllowSignups = true
+
+			client := coderdtest.New(t, &coderdtest.Options{
+				Auditor:    auditor,
+				OIDCConfig: config,
+			})
+			first := coderdtest.CreateFirstUser(t, client)
+			mapping["admins"] = []string{rbac.RoleTemplateAdmin(), rbac.RoleOrgAdmin(first.OrganizationID)}
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			login := func(roles interface{}) codersdk.User {
+				oidcClient := codersdk.New(client.URL)
+				resp := oidcCallback(t, oidcClient, conf.EncodeClaims(t, jwt.MapClaims{
+					"email": "alice@coder.com",
+					"roles": roles,
+				}))
+				require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+				user, err := client.User(ctx, "alice")
+				require.NoError(t, err)
+				return user
+			}
+			roleNames := func(roles []codersdk.Role) []string {
+				names := make([]string, 0, len(roles))
+				for _, role := range roles {
+					names = append(names, role.Name)
+				}
+				return names
+			}
+			memberRoles := func(user codersdk.User) []string {
+				roles, err := client.UserRoles(ctx, user.Username)
+				require.NoError(t, err)
+				return roles.OrganizationRoles[first.OrganizationID]
+			}
+			const auditorRole = "auditor"
+
+			// Mapped roles are expanded, and unknown roles are ignored.
+			user := login([]string{"admins", auditorRole, "unknown"})
+			require.ElementsMatch(t, []string{rbac.RoleTemplateAdmin(), auditorRole}, roleNames(user.Roles))
+			require.Equal(t, []string{rbac.RoleOrgAdmin(first.OrganizationID)}, memberRoles(user))
+
+			logs := auditor.AuditLogs()
+			log := logs[len(logs)-2]
+			require.Equal(t, database.AuditActionWrite, log.Action)
+			require.Equal(t, database.ResourceTypeUser, log.ResourceType)
+			require.Equal(t, user.ID, log.ResourceID)
+			require.Contains(t, string(log.AdditionalFields), "organization_roles")
+
+			// Roles are managed by the identity provider.
+			_, err := client.UpdateUserRoles(ctx, user.Username, codersdk.UpdateRoles{
+				Roles: []string{rbac.RoleUserAdmin()},
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+			_, err = client.UpdateOrganizationMemberRoles(ctx, first.OrganizationID, user.Username, codersdk.UpdateRoles{})
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+			// A single role may be returned as a string. Roles that aren't
+			// returned anymore are removed.
+			user = login(auditorRole)
+			require.Equal(t, []string{auditorRole}, roleNames(user.Roles))
+			require.Empty(t, memberRoles(user))
+
+			// Nothing is audited if the roles didn't change.
+			numLogs := len(auditor.AuditLogs())
+			user = login([]string{auditorRole})
+			require.Equal(t, []string{auditorRole}, roleNames(user.Roles))
+			require.Len(t, auditor.AuditLogs(), numLogs+1, "only the login is audited")
+
+			// A claim of an unknown type is likely a misconfiguration, so the
+			// roles are kept.
+			user = login(map[string]interface{}{"role": "admins"})
+			require.Equal(t, []string{auditorRole}, roleNames(user.Roles))
+		})
+
+		t.Run("LastOwner", func(t *testing.T) {
+			t.Parallel()
+			conf := coderdtest.NewOIDCConfig(t, "")
+
+			config := conf.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+				cfg.UserRoleField = "roles"
+			})
+			config.AllowSignups = true
+
+			client := coderdtest.New(t, &coderdtest.Options{
+				OIDCConfig: config,
+			})
+			_ = coderdtest.CreateFirstUser(t, client)
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			oidcClient := codersdk.New(client.URL)
+			resp := oidcCallback(t, oidcClient, conf.EncodeClaims(t, jwt.MapClaims{
+				"email": "alice@coder.com",
+				"roles": []string{rbac.RoleOwner()},
+			}))
+			require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+			oidcClient.SetSessionToken(authCookieValue(resp.Cookies()))
+
+			// Leave the OIDC user as the only active owner.
+			firstUser, err := client.User(ctx, codersdk.Me)
+			require.NoError(t, err)
+			_, err = oidcClient.UpdateUserRoles(ctx, firstUser.Username, codersdk.UpdateRoles{})
+			require.NoError(t, err)
+
+			// A misconfigured provider doesn't lock everyone out.
+			resp = oidcCallback(t, oidcClient, conf.EncodeClaims(t, jwt.MapClaims{
+				"email": "alice@coder.com",
+			}))
+			require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+			user, err := oidcClient.User(ctx, codersdk.Me)
+			require.NoError(t, err)
+			require.Equal(t, []codersdk.Role{{Name: rbac.RoleOwner(), DisplayName: "Owner"}}, user.Roles)
+		})
+
+		t.Run("EmptyClaimKeepsOwners", func(t *testing.T) {
+			t.Parallel()
+			conf := coderdtest.NewOIDCConfig(t, "")
+
+			config := conf.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+				cfg.UserRoleField = "roles"
+			})
+			config.AllowSignups = true
+
+			client := coderdtest.New(t, &coderdtest.Options{
+				OIDCConfig: config,
+			})
+			_ = coderdtest.CreateFirstUser(t, client)
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+
+			login := func(email string, roles interface{}) codersdk.User {
+				claims := jwt.MapClaims{"email": email}
+				if roles != nil {
+					claims["roles"] = roles
+				}
+				oidcClient := codersdk.New(client.URL)
+				resp := oidcCallback(t, oidcClient, conf.EncodeClaims(t, claims))
+				require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+				oidcClient.SetSessionToken(authCookieValue(resp.Cookies()))
+				user, err := oidcClient.User(ctx, codersdk.Me)
+				require.NoError(t, err)
+				return user
+			}
+			owner := []codersdk.Role{{Name: rbac.RoleOwner(), DisplayName: "Owner"}}
+
+			for _, email := range []string{"alice@coder.com", "bob@coder.com"} {
+				require.Equal(t, owner, login(email, []string{rbac.RoleOwner()}).Roles)
+			}
+
+			// Neither a dropped nor an empty claim demotes the owners, even
+			// though they aren't the last active owner.
+			for _, email := range []string{"alice@coder.com", "bob@coder.com"} {
+				require.Equal(t, owner, login(email, nil).Roles)
+				require.Equal(t, owner, login(email, []string{}).Roles)
+			}
+
+			// A claim with roles still removes the owner role.
+			user := login("alice@coder.com", []string{"auditor"})
+			require.Equal(t, []codersdk.Role{{Name: "auditor", DisplayName: "Auditor"}}, user.Roles)
+		})
+	})
+
 	t.Run("Disabled", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
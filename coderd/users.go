@@ -6,12 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/db2sdk"
@@ -146,6 +148,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Users: []telemetry.User{telemetryUser},
 	})
+	api.WebhookUserCreated(ctx, user)
 
 	// TODO: @emyrk this currently happens outside the database tx used to create
 	// 	the user. Maybe I add this ability to grant roles in the createUser api
@@ -403,6 +406,7 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Users: []telemetry.User{telemetry.ConvertUser(user)},
 	})
+	api.WebhookUserCreated(ctx, user)
 
 	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.User(user, []uuid.UUID{req.OrganizationID}))
 }
@@ -660,6 +664,11 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			return
 		}
 		aReq.New = suspendedUser
+		if status == database.UserStatusSuspended && user.Status != database.UserStatusSuspended {
+			api.NotifyUser(ctx, suspendedUser, codersdk.NotificationTemplateUserSuspended, map[string]string{
+				"suspended_at": suspendedUser.UpdatedAt.Format(time.RFC3339),
+			})
+		}
 
 		organizations, err := userOrganizationIDs(ctx, api, user)
 		if err != nil {
@@ -865,6 +874,13 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if user.LoginType == database.LoginTypeOIDC && api.OIDCConfig.RoleSyncEnabled() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The roles of OIDC users are synced from the identity provider at each login, and cannot be changed in Coder.",
+		})
+		return
+	}
+
 	var params codersdk.UpdateRoles
 	if !httpapi.Read(ctx, rw, r, &params) {
 		return
@@ -907,12 +923,21 @@ func (api *API) updateSiteUserRoles(ctx context.Context, args database.UpdateUse
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
 
-		if _, err := rbac.RoleByName(r); err != nil {
+		// Custom roles are looked up when they are assigned.
+		if _, err := rbac.RoleByName(r); err != nil && rbac.IsBuiltInRoleName(r) {
 			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
 		}
 	}
 
-	updatedUser, err := api.Database.UpdateUserRoles(ctx, args)
+	var updatedUser database.User
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.LockIDRoleAssignments)
+		if err != nil {
+			return xerrors.Errorf("acquire role assignments lock: %w", err)
+		}
+		updatedUser, err = tx.UpdateUserRoles(ctx, args)
+		return err
+	}, nil)
 	if err != nil {
 		return database.User{}, xerrors.Errorf("update site roles: %w", err)
 	}
@@ -992,6 +1017,28 @@ func (api *API) organizationByUserAndName(rw http.ResponseWriter, r *http.Reques
 	httpapi.Write(ctx, rw, http.StatusOK, convertOrganization(organization))
 }
 
+// NotifyUser enqueues a notification about their account to a user. Failing
+// to enqueue it doesn't fail the request that caused it, so it's only logged.
+func (api *API) NotifyUser(ctx context.Context, user database.User, template codersdk.NotificationTemplate, labels map[string]string) {
+	err := api.NotificationsEnqueuer.Enqueue(ctx, user.ID, template, labels)
+	if err != nil {
+		api.Logger.Warn(ctx, "enqueue user notification", slog.F("user_id", user.ID), slog.F("template", template), slog.Error(err))
+	}
+}
+
+// WebhookUserCreated delivers the creation of a user to the webhooks
+// subscribed to it. Like notifications, failing to enqueue it is only logged.
+func (api *API) WebhookUserCreated(ctx context.Context, user database.User) {
+	err := api.WebhooksEnqueuer.Enqueue(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "enqueue user created webhook", slog.F("user_id", user.ID), slog.Error(err))
+	}
+}
+
 type CreateUserRequest struct {
 	codersdk.CreateUserRequest
 	CreateOrganization bool
@@ -1086,6 +1133,12 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		if err != nil {
 			return xerrors.Errorf("create organization member: %w", err)
 		}
+		// Enqueued in the transaction, so every way a user can be created
+		// notifies them, and only once they exist.
+		err = api.NotificationsEnqueuer.EnqueueWithStore(ctx, tx, user.ID, codersdk.NotificationTemplateUserCreated, nil)
+		if err != nil {
+			return xerrors.Errorf("enqueue user created notification: %w", err)
+		}
 		return nil
 	}, nil)
 }
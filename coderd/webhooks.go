@@ -0,0 +1,329 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get webhooks
+// @ID get-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Success 200 {array} codersdk.Webhook
+// @Router /webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWebhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	webhooks, err := api.Database.GetWebhooks(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		converted = append(converted, convertWebhook(webhook))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param request body codersdk.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} codersdk.Webhook
+// @Router /webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceWebhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	events, ok := parseWebhookEvents(rw, r, req.Events)
+	if !ok {
+		return
+	}
+
+	now := database.Now()
+	webhook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Endpoint:  req.Endpoint,
+		Secret:    req.Secret,
+		Events:    events,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = webhook
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWebhook(webhook))
+}
+
+// @Summary Get webhook by ID
+// @ID get-webhook-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [get]
+func (api *API) webhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		webhook = httpmw.WebhookParam(r)
+	)
+	if !api.Authorize(r, rbac.ActionRead, webhook) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(webhook))
+}
+
+// @Summary Update webhook
+// @ID update-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param request body codersdk.UpdateWebhookRequest true "Update webhook request"
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [patch]
+func (api *API) patchWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		webhook           = httpmw.WebhookParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = webhook
+	if !api.Authorize(r, rbac.ActionUpdate, webhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	events, ok := parseWebhookEvents(rw, r, req.Events)
+	if !ok {
+		return
+	}
+	secret := webhook.Secret
+	if req.Secret != "" {
+		secret = req.Secret
+	}
+
+	updated, err := api.Database.UpdateWebhookByID(ctx, database.UpdateWebhookByIDParams{
+		ID:        webhook.ID,
+		Name:      req.Name,
+		Endpoint:  req.Endpoint,
+		Secret:    secret,
+		Events:    events,
+		UpdatedAt: database.Now(),
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(updated))
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		webhook           = httpmw.WebhookParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = webhook
+	if !api.Authorize(r, rbac.ActionDelete, webhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	err := api.Database.DeleteWebhookByID(ctx, webhook.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Webhook has been deleted!",
+	})
+}
+
+// @Summary Get webhook deliveries
+// @ID get-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		webhook = httpmw.WebhookParam(r)
+	)
+	if !api.Authorize(r, rbac.ActionRead, webhook) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhook.ID,
+		OffsetOpt: int32(page.Offset),
+		LimitOpt:  int32(page.Limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook deliveries.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	converted := make([]codersdk.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		converted = append(converted, convertWebhookDelivery(delivery))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+func parseWebhookEvents(rw http.ResponseWriter, r *http.Request, events []codersdk.WebhookEvent) ([]database.WebhookEvent, bool) {
+	parsed := make([]database.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		if !database.WebhookEvent(event).Valid() {
+			httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid webhook events.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "events",
+					Detail: fmt.Sprintf("unknown webhook event %q", event),
+				}},
+			})
+			return nil, false
+		}
+		parsed = append(parsed, database.WebhookEvent(event))
+	}
+	return parsed, true
+}
+
+func convertWebhook(webhook database.Webhook) codersdk.Webhook {
+	events := make([]codersdk.WebhookEvent, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, codersdk.WebhookEvent(event))
+	}
+	return codersdk.Webhook{
+		ID:        webhook.ID,
+		Name:      webhook.Name,
+		Endpoint:  webhook.Endpoint,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func convertWebhookDelivery(delivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	status := codersdk.WebhookDeliveryStatus(delivery.Status)
+	if delivery.Status == database.WebhookDeliveryStatusLeased {
+		// Leased deliveries are being sent, which isn't a state of its own
+		// to API consumers.
+		status = codersdk.WebhookDeliveryStatusPending
+	}
+	return codersdk.WebhookDelivery{
+		ID:         delivery.ID,
+		WebhookID:  delivery.WebhookID,
+		Event:      codersdk.WebhookEvent(delivery.Event),
+		Status:     status,
+		Attempts:   delivery.Attempts,
+		StatusCode: int(delivery.StatusCode.Int32),
+		Error:      delivery.LastError.String,
+		CreatedAt:  delivery.CreatedAt,
+		UpdatedAt:  delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+// Package webhooks delivers events to the webhook endpoints registered by
+// admins. Deliveries are enqueued in the database, and sent by a Manager on
+// any replica, so they survive restarts and are retried when sending fails.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/database/pubsub"
+	"github.com/coder/coder/codersdk"
+)
+
+// PubsubEventDeliveries is published when deliveries are enqueued, so
+// managers send them without waiting for their next tick.
+const PubsubEventDeliveries = "webhook_deliveries"
+
+// Enqueuer enqueues events to be delivered to webhooks.
+type Enqueuer interface {
+	// Enqueue enqueues a delivery of the event to every webhook subscribed
+	// to it. The data is marshaled to JSON, and sent as the data of the
+	// payload.
+	Enqueue(ctx context.Context, event codersdk.WebhookEvent, data any) error
+}
+
+// NewNoopEnqueuer returns an Enqueuer that drops all events.
+func NewNoopEnqueuer() Enqueuer {
+	return noopEnqueuer{}
+}
+
+type noopEnqueuer struct{}
+
+func (noopEnqueuer) Enqueue(context.Context, codersdk.WebhookEvent, any) error {
+	return nil
+}
+
+// NewMock returns an Enqueuer that records events, for tests.
+func NewMock() *MockEnqueuer {
+	return &MockEnqueuer{}
+}
+
+// MockEvent is an event recorded by MockEnqueuer.
+type MockEvent struct {
+	Event codersdk.WebhookEvent
+	Data  any
+}
+
+type MockEnqueuer struct {
+	mutex  sync.Mutex
+	events []MockEvent
+}
+
+func (e *MockEnqueuer) Events() []MockEvent {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	events := make([]MockEvent, len(e.events))
+	copy(events, e.events)
+	return events
+}
+
+func (e *MockEnqueuer) Enqueue(_ context.Context, event codersdk.WebhookEvent, data any) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.events = append(e.events, MockEvent{
+		Event: event,
+		Data:  data,
+	})
+	return nil
+}
+
+// StoreEnqueuer enqueues deliveries in the database.
+type StoreEnqueuer struct {
+	db     database.Store
+	pubsub pubsub.Pubsub
+	log    slog.Logger
+}
+
+// NewStoreEnqueuer returns an Enqueuer that enqueues deliveries in the
+// database, and wakes up managers with the pubsub.
+func NewStoreEnqueuer(db database.Store, ps pubsub.Pubsub, log slog.Logger) *StoreEnqueuer {
+	return &StoreEnqueuer{
+		db:     db,
+		pubsub: ps,
+		log:    log.Named("webhooks"),
+	}
+}
+
+func (e *StoreEnqueuer) Enqueue(ctx context.Context, event codersdk.WebhookEvent, data any) error {
+	if !database.WebhookEvent(event).Valid() {
+		return xerrors.Errorf("unknown webhook event %q", event)
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return xerrors.Errorf("marshal data: %w", err)
+	}
+
+	//nolint:gocritic // Events are enqueued by the system for all webhooks.
+	deliveries, err := e.db.EnqueueWebhookDeliveries(dbauthz.AsSystemRestricted(ctx), database.EnqueueWebhookDeliveriesParams{
+		Event:     database.WebhookEvent(event),
+		Payload:   payload,
+		CreatedAt: database.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("enqueue deliveries: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err = e.pubsub.Publish(PubsubEventDeliveries, nil)
+	if err != nil {
+		// The deliveries are sent on the next tick of the managers.
+		e.log.Warn(ctx, "publish webhook deliveries", slog.F("event", event), slog.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/database/pubsub"
+	"github.com/coder/coder/coderd/queue"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// FetchInterval is how often the manager sends pending deliveries when
+	// it isn't woken up by the pubsub.
+	FetchInterval = 10 * time.Second
+	// LeasePeriod is how long a replica may take to send a delivery before
+	// another replica sends it again.
+	LeasePeriod = time.Minute
+	// RetryBackoff is how long the manager waits before retrying a delivery
+	// that failed for the first time. It doubles with every attempt, up to
+	// MaxRetryBackoff.
+	RetryBackoff    = 30 * time.Second
+	MaxRetryBackoff = time.Hour
+	// MaxAttempts is how often a delivery is attempted before it fails.
+	MaxAttempts = 8
+	// Retention is how long succeeded and failed deliveries are kept in the
+	// delivery log.
+	Retention = 30 * 24 * time.Hour
+	// MaxDeliveriesPerRun is the maximum number of deliveries sent on a
+	// tick.
+	MaxDeliveriesPerRun = 50
+
+	requestTimeout = 10 * time.Second
+)
+
+// Manager sends the deliveries enqueued in the database to the endpoints of
+// their webhooks.
+type Manager struct {
+	*queue.Manager[database.WebhookDelivery]
+
+	ctx    context.Context
+	pubsub pubsub.Pubsub
+	log    slog.Logger
+	wake   chan struct{}
+	cancel func()
+}
+
+// Stats contains the deliveries handled in one run of the Manager.
+type Stats = queue.Stats
+
+// NewManager returns a Manager that sends deliveries with the client on every
+// tick, and whenever deliveries are published on the pubsub.
+func NewManager(ctx context.Context, db database.Store, ps pubsub.Pubsub, log slog.Logger, client *http.Client, tick <-chan time.Time) *Manager {
+	log = log.Named("webhooks")
+	wake := make(chan struct{}, 1)
+	return &Manager{
+		//nolint:gocritic // The manager sends the deliveries of all webhooks.
+		Manager: queue.NewManager[database.WebhookDelivery](dbauthz.AsSystemRestricted(ctx), log, &handler{
+			db:     db,
+			log:    log,
+			client: client,
+		}, queue.Options{
+			LeasePeriod:    LeasePeriod,
+			Retention:      Retention,
+			MaxItemsPerRun: MaxDeliveriesPerRun,
+			Tick:           tick,
+			Wake:           wake,
+		}),
+		ctx:    ctx,
+		pubsub: ps,
+		log:    log,
+		wake:   wake,
+		cancel: func() {},
+	}
+}
+
+// WithStatsChannel will cause Manager to push Stats to ch after every run.
+// This push is blocking, so if ch is not read, the manager will hang. This
+// should only be used in tests.
+func (m *Manager) WithStatsChannel(ch chan<- Stats) *Manager {
+	m.Manager.WithStatsChannel(ch)
+	return m
+}
+
+// Start will cause the manager to send deliveries on every tick from its
+// channel, and when woken up by the pubsub. It will stop when its context is
+// Done, or when its channel is closed.
+//
+// Start should only be called once.
+func (m *Manager) Start() {
+	cancel, err := m.pubsub.Subscribe(PubsubEventDeliveries, func(context.Context, []byte) {
+		select {
+		case m.wake <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		// Deliveries are still sent on every tick.
+		m.log.Warn(m.ctx, "subscribe to webhook deliveries", slog.Error(err))
+	} else {
+		m.cancel = cancel
+	}
+	m.Manager.Start()
+}
+
+// Close will stop the manager.
+func (m *Manager) Close() {
+	m.cancel()
+	m.Manager.Close()
+}
+
+// handler sends webhook deliveries for the queue.
+type handler struct {
+	db     database.Store
+	log    slog.Logger
+	client *http.Client
+}
+
+func (*handler) ID(delivery database.WebhookDelivery) uuid.UUID {
+	return delivery.ID
+}
+
+func (h *handler) Acquire(ctx context.Context, now, leasedUntil time.Time, count int32) ([]database.WebhookDelivery, error) {
+	return h.db.AcquireWebhookDeliveries(ctx, database.AcquireWebhookDeliveriesParams{
+		Now:         now,
+		LeasedUntil: leasedUntil,
+		Count:       count,
+	})
+}
+
+func (h *handler) Purge(ctx context.Context, before time.Time) error {
+	return h.db.DeleteOldWebhookDeliveries(ctx, before)
+}
+
+func (h *handler) Handle(ctx context.Context, delivery database.WebhookDelivery) queue.Result {
+	log := h.log.With(slog.F("delivery_id", delivery.ID), slog.F("webhook_id", delivery.WebhookID), slog.F("event", delivery.Event))
+	statusCode, sendErr := h.send(ctx, delivery)
+
+	now := database.Now()
+	params := database.UpdateWebhookDeliveryByIDParams{
+		ID:         delivery.ID,
+		Status:     database.WebhookDeliveryStatusSucceeded,
+		StatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0},
+		UpdatedAt:  now,
+		SendAfter:  delivery.SendAfter,
+	}
+	result := queue.Succeeded
+	switch {
+	case sendErr == nil:
+	case delivery.Attempts < MaxAttempts:
+		params.Status = database.WebhookDeliveryStatusPending
+		params.LastError = sql.NullString{String: sendErr.Error(), Valid: true}
+		params.SendAfter = now.Add(retryBackoff(delivery.Attempts))
+		log.Info(ctx, "send webhook delivery, will retry", slog.F("attempts", delivery.Attempts), slog.Error(sendErr))
+		result = queue.Retried
+	default:
+		params.Status = database.WebhookDeliveryStatusFailed
+		params.LastError = sql.NullString{String: sendErr.Error(), Valid: true}
+		log.Warn(ctx, "send webhook delivery", slog.F("attempts", delivery.Attempts), slog.Error(sendErr))
+		result = queue.Failed
+	}
+	err := h.db.UpdateWebhookDeliveryByID(ctx, params)
+	if err != nil {
+		log.Warn(ctx, "update webhook delivery", slog.Error(err))
+	}
+	return result
+}
+
+// send sends the delivery to the endpoint of its webhook, and returns the
+// status code of the response, or 0 when no response was received.
+func (h *handler) send(ctx context.Context, delivery database.WebhookDelivery) (int, error) {
+	webhook, err := h.db.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return 0, xerrors.Errorf("get webhook: %w", err)
+	}
+	body, err := json.Marshal(codersdk.WebhookPayload{
+		ID:        delivery.ID,
+		Event:     codersdk.WebhookEvent(delivery.Event),
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(codersdk.WebhookSignatureHeader, codersdk.WebhookSignature(webhook.Secret, body))
+	req.Header.Set(codersdk.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(codersdk.WebhookDeliveryHeader, delivery.ID.String())
+	res, err := h.client.Do(req)
+	if err != nil {
+		return 0, xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return res.StatusCode, xerrors.Errorf("unexpected status %d: %s", res.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return res.StatusCode, nil
+}
+
+// retryBackoff is how long to wait before retrying a delivery after the
+// attempts failed.
+func retryBackoff(attempts int32) time.Duration {
+	backoff := RetryBackoff
+	for i := int32(1); i < attempts && backoff < MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxRetryBackoff {
+		backoff = MaxRetryBackoff
+	}
+	return backoff
+}
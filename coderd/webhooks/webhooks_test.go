@@ -0,0 +1,249 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/coderd/database/pubsub"
+	"github.com/coder/coder/coderd/webhooks"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = testutil.Context(t, testutil.WaitLong)
+		db, ps = dbtestutil.NewDB(t)
+		log    = slogtest.Make(t, nil)
+		builds = dbgen.Webhook(t, db, database.Webhook{
+			Events: []database.WebhookEvent{database.WebhookEventWorkspaceBuildSucceeded, database.WebhookEventWorkspaceBuildFailed},
+		})
+		users = dbgen.Webhook(t, db, database.Webhook{
+			Events: []database.WebhookEvent{database.WebhookEventUserCreated},
+		})
+	)
+	published := make(chan struct{}, 1)
+	cancel, err := ps.Subscribe(webhooks.PubsubEventDeliveries, func(context.Context, []byte) {
+		published <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer cancel()
+
+	enqueuer := webhooks.NewStoreEnqueuer(db, ps, log)
+	err = enqueuer.Enqueue(ctx, codersdk.WebhookEventWorkspaceBuildFailed, codersdk.WebhookWorkspaceBuildData{
+		WorkspaceName: "dev",
+		Error:         "oops",
+	})
+	require.NoError(t, err)
+	<-published
+
+	// Deliveries are only enqueued for webhooks subscribed to the event.
+	deliveries := webhookDeliveries(ctx, t, db, builds.ID)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, database.WebhookEventWorkspaceBuildFailed, deliveries[0].Event)
+	require.Equal(t, database.WebhookDeliveryStatusPending, deliveries[0].Status)
+	var data codersdk.WebhookWorkspaceBuildData
+	require.NoError(t, json.Unmarshal(deliveries[0].Payload, &data))
+	require.Equal(t, "dev", data.WorkspaceName)
+	require.Equal(t, "oops", data.Error)
+	require.Empty(t, webhookDeliveries(ctx, t, db, users.ID))
+
+	err = enqueuer.Enqueue(ctx, "unknown", nil)
+	require.ErrorContains(t, err, "unknown webhook event")
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = testutil.Context(t, testutil.WaitLong)
+		db, ps   = dbtestutil.NewDB(t)
+		log      = slogtest.Make(t, nil)
+		tickCh   = make(chan time.Time)
+		statsCh  = make(chan webhooks.Stats)
+		requests atomic.Int64
+		received = make(chan *http.Request, 1)
+		bodies   = make(chan []byte, 1)
+	)
+	// The endpoint fails the first request, so the delivery is retried.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	webhook := dbgen.Webhook(t, db, database.Webhook{
+		Endpoint: srv.URL,
+		Secret:   "hunter2",
+	})
+
+	err := webhooks.NewStoreEnqueuer(db, ps, log).Enqueue(ctx, codersdk.WebhookEventWorkspaceBuildSucceeded, codersdk.WebhookWorkspaceBuildData{
+		WorkspaceName: "dev",
+	})
+	require.NoError(t, err)
+
+	// The manager is subscribed to another pubsub, so it only sends
+	// deliveries on ticks.
+	manager := webhooks.NewManager(ctx, db, pubsub.NewInMemory(), log, srv.Client(), tickCh).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	tickCh <- time.Now()
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Retried, 1)
+	require.Empty(t, stats.Succeeded)
+
+	deliveries := webhookDeliveries(ctx, t, db, webhook.ID)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, database.WebhookDeliveryStatusPending, deliveries[0].Status)
+	require.EqualValues(t, http.StatusServiceUnavailable, deliveries[0].StatusCode.Int32)
+	require.Contains(t, deliveries[0].LastError.String, "unexpected status 503")
+
+	// Retries wait for the backoff.
+	tickCh <- time.Now()
+	stats = <-statsCh
+	require.Empty(t, stats.Succeeded)
+
+	tickCh <- time.Now().Add(2 * webhooks.RetryBackoff)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Succeeded, 1)
+
+	r := <-received
+	body := <-bodies
+	require.Equal(t, codersdk.WebhookSignature("hunter2", body), r.Header.Get(codersdk.WebhookSignatureHeader))
+	require.Equal(t, string(codersdk.WebhookEventWorkspaceBuildSucceeded), r.Header.Get(codersdk.WebhookEventHeader))
+	require.Equal(t, stats.Succeeded[0].String(), r.Header.Get(codersdk.WebhookDeliveryHeader))
+	var payload codersdk.WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Equal(t, stats.Succeeded[0], payload.ID)
+	require.Equal(t, codersdk.WebhookEventWorkspaceBuildSucceeded, payload.Event)
+	var data codersdk.WebhookWorkspaceBuildData
+	require.NoError(t, json.Unmarshal(payload.Data, &data))
+	require.Equal(t, "dev", data.WorkspaceName)
+
+	deliveries = webhookDeliveries(ctx, t, db, webhook.ID)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, database.WebhookDeliveryStatusSucceeded, deliveries[0].Status)
+	require.EqualValues(t, 2, deliveries[0].Attempts)
+	require.EqualValues(t, http.StatusNoContent, deliveries[0].StatusCode.Int32)
+	require.False(t, deliveries[0].LastError.Valid)
+}
+
+func TestManager_Pubsub(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = testutil.Context(t, testutil.WaitLong)
+		db, ps   = dbtestutil.NewDB(t)
+		log      = slogtest.Make(t, nil)
+		statsCh  = make(chan webhooks.Stats)
+		received = make(chan struct{}, 1)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	_ = dbgen.Webhook(t, db, database.Webhook{
+		Endpoint: srv.URL,
+		Events:   []database.WebhookEvent{database.WebhookEventUserCreated},
+	})
+
+	// The manager never ticks, so the delivery is sent because it's
+	// published.
+	manager := webhooks.NewManager(ctx, db, ps, log, srv.Client(), make(chan time.Time)).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	err := webhooks.NewStoreEnqueuer(db, ps, log).Enqueue(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{
+		Username: "alice",
+	})
+	require.NoError(t, err)
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Succeeded, 1)
+	<-received
+}
+
+func TestManager_Failure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitLong)
+		db, ps  = dbtestutil.NewDB(t)
+		log     = slogtest.Make(t, nil)
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan webhooks.Stats)
+	)
+	webhook := dbgen.Webhook(t, db, database.Webhook{
+		Endpoint: "http://127.0.0.1:1",
+	})
+	err := webhooks.NewStoreEnqueuer(db, ps, log).Enqueue(ctx, codersdk.WebhookEventWorkspaceBuildSucceeded, nil)
+	require.NoError(t, err)
+
+	manager := webhooks.NewManager(ctx, db, pubsub.NewInMemory(), log, http.DefaultClient, tickCh).WithStatsChannel(statsCh)
+	manager.Start()
+	defer manager.Close()
+
+	// Every attempt waits for a longer backoff, and the delivery fails
+	// after the last one.
+	now := time.Now()
+	for i := 1; i <= webhooks.MaxAttempts; i++ {
+		now = now.Add(webhooks.MaxRetryBackoff + time.Minute)
+		tickCh <- now
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		if i < webhooks.MaxAttempts {
+			require.Len(t, stats.Retried, 1, "attempt %d", i)
+		} else {
+			require.Len(t, stats.Failed, 1)
+		}
+	}
+
+	deliveries := webhookDeliveries(ctx, t, db, webhook.ID)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, database.WebhookDeliveryStatusFailed, deliveries[0].Status)
+	require.EqualValues(t, webhooks.MaxAttempts, deliveries[0].Attempts)
+	require.False(t, deliveries[0].StatusCode.Valid)
+	require.Contains(t, deliveries[0].LastError.String, "send request")
+}
+
+func webhookDeliveries(ctx context.Context, t *testing.T, db database.Store, webhookID uuid.UUID) []database.WebhookDelivery {
+	t.Helper()
+	deliveries, err := db.GetWebhookDeliveriesByWebhookID(dbauthz.AsSystemRestricted(ctx), database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhookID,
+	})
+	require.NoError(t, err)
+	return deliveries
+}
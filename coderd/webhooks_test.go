@@ -0,0 +1,251 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/webhooks"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/builds",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "builds", webhook.Name)
+		require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed}, webhook.Events)
+
+		webhooks, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.Webhook{webhook}, webhooks)
+
+		updated, err := client.UpdateWebhook(ctx, webhook.ID, codersdk.UpdateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/v2/builds",
+			Events: []codersdk.WebhookEvent{
+				codersdk.WebhookEventWorkspaceBuildSucceeded,
+				codersdk.WebhookEventWorkspaceBuildFailed,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://hooks.example.com/v2/builds", updated.Endpoint)
+		require.Len(t, updated.Events, 2)
+
+		got, err := client.Webhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, got)
+
+		err = client.DeleteWebhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		_, err = client.Webhook(ctx, webhook.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/builds",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateWebhook(ctx, webhook.ID, codersdk.UpdateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/v2/builds",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+		err = client.DeleteWebhook(ctx, webhook.ID)
+		require.NoError(t, err)
+
+		var actions []database.AuditAction
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceType != database.ResourceTypeWebhook {
+				continue
+			}
+			require.Equal(t, webhook.ID, log.ResourceID)
+			require.Equal(t, "builds", log.ResourceTarget)
+			require.NotContains(t, string(log.Diff), "hunter2")
+			actions = append(actions, log.Action)
+		}
+		require.Equal(t, []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionWrite,
+			database.AuditActionDelete,
+		}, actions)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		req := codersdk.CreateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/builds",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		}
+		_, err := client.CreateWebhook(ctx, req)
+		require.NoError(t, err)
+		_, err = client.CreateWebhook(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("UnknownEvent", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/builds",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{"unknown"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:     "builds",
+			Endpoint: "https://hooks.example.com/builds",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+
+		_, err = member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:     "mine",
+			Endpoint: "https://hooks.example.com/mine",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.Webhooks(ctx)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.WebhookDeliveries(ctx, webhook.ID, codersdk.Pagination{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Deliveries", func(t *testing.T) {
+		t.Parallel()
+		client, _, api := coderdtest.NewWithAPI(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:     "users",
+			Endpoint: "https://hooks.example.com/users",
+			Secret:   "hunter2",
+			Events:   []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		require.NoError(t, err)
+		enqueuer := webhooks.NewStoreEnqueuer(api.Database, api.Pubsub, api.Logger)
+		for i := 0; i < 3; i++ {
+			err = enqueuer.Enqueue(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{})
+			require.NoError(t, err)
+		}
+		// Leased deliveries are pending to API consumers.
+		//nolint:gocritic // Leasing deliveries is done by the system.
+		_, err = api.Database.AcquireWebhookDeliveries(dbauthz.AsSystemRestricted(ctx), database.AcquireWebhookDeliveriesParams{
+			Now:         database.Now(),
+			LeasedUntil: database.Now().Add(webhooks.LeasePeriod),
+			Count:       1,
+		})
+		require.NoError(t, err)
+
+		deliveries, err := client.WebhookDeliveries(ctx, webhook.ID, codersdk.Pagination{})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 3)
+		for _, delivery := range deliveries {
+			require.Equal(t, webhook.ID, delivery.WebhookID)
+			require.Equal(t, codersdk.WebhookEventUserCreated, delivery.Event)
+			require.Equal(t, codersdk.WebhookDeliveryStatusPending, delivery.Status)
+		}
+
+		deliveries, err = client.WebhookDeliveries(ctx, webhook.ID, codersdk.Pagination{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+	})
+}
+
+func TestWebhookEvents(t *testing.T) {
+	t.Parallel()
+
+	enqueuer := webhooks.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		WebhooksEnqueuer:         enqueuer,
+	})
+	first := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, first.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, first.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	events := enqueuer.Events()
+	require.Len(t, events, 4)
+	require.Equal(t, codersdk.WebhookEventUserCreated, events[0].Event)
+	require.Equal(t, first.UserID, events[0].Data.(codersdk.WebhookUserData).UserID)
+	require.Equal(t, codersdk.WebhookEventTemplateVersionPushed, events[1].Event)
+	require.Equal(t, version.ID, events[1].Data.(codersdk.WebhookTemplateVersionData).TemplateVersionID)
+	require.Equal(t, codersdk.WebhookEventWorkspaceBuildStarted, events[2].Event)
+	require.Equal(t, codersdk.WebhookEventWorkspaceBuildSucceeded, events[3].Event)
+	data := events[3].Data.(codersdk.WebhookWorkspaceBuildData)
+	require.Equal(t, workspace.ID, data.WorkspaceID)
+	require.Equal(t, workspace.LatestBuild.ID, data.BuildID)
+	require.Equal(t, template.Name, data.TemplateName)
+}
@@ -0,0 +1,153 @@
+package coderd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// agentProcessReportInterval is how often agents report the resource usage
+// of their processes.
+const agentProcessReportInterval = 30 * time.Second
+
+// @Summary Submit workspace agent processes
+// @ID submit-workspace-agent-processes
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body codersdk.WorkspaceAgentProcesses true "Processes"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/processes [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostProcesses(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req codersdk.WorkspaceAgentProcesses
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.CollectedAt.IsZero() {
+		req.CollectedAt = database.Now()
+	}
+	processes, err := json.Marshal(req)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.UpsertWorkspaceAgentProcesses(ctx, database.UpsertWorkspaceAgentProcessesParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		CollectedAt:      req.CollectedAt,
+		Processes:        processes,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace agent processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get processes of workspace agent
+// @ID get-processes-of-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentProcesses
+// @Router /workspaceagents/{workspaceagent}/processes [get]
+func (api *API) workspaceAgentProcesses(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	row, err := api.Database.GetWorkspaceAgentProcesses(ctx, workspaceAgent.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The workspace agent hasn't reported its processes yet.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var processes codersdk.WorkspaceAgentProcesses
+	err = json.Unmarshal(row.Processes, &processes)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error decoding workspace agent processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	processes.CollectedAt = row.CollectedAt
+	httpapi.Write(ctx, rw, http.StatusOK, processes)
+}
+
+// @Summary Kill process in workspace agent
+// @ID kill-process-in-workspace-agent
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param pid path int true "Process ID"
+// @Param request body codersdk.KillWorkspaceAgentProcessRequest true "Kill request"
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/processes/{pid}/kill [post]
+func (api *API) workspaceAgentKillProcess(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	// Killing a process requires the same permission as connecting to the
+	// workspace.
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	pid, err := strconv.ParseInt(chi.URLParam(r, "pid"), 10, 32)
+	if err != nil || pid <= 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Process ID must be a positive integer.",
+		})
+		return
+	}
+	var req codersdk.KillWorkspaceAgentProcessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	agentConn, release, ok := api.acquireWorkspaceAgentConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err = agentConn.KillProcess(ctx, int32(pid), req)
+	if err != nil {
+		writeWorkspaceAgentAPIError(ctx, rw, "Internal error killing workspace agent process.", err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,149 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceAgentProcesses(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	workspace, err := client.Workspace(context.Background(), workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	_, err = client.WorkspaceAgentProcesses(ctx, agentID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	collectedAt := time.Now().Add(-time.Second).Truncate(time.Millisecond)
+	err = agentClient.PostProcesses(ctx, codersdk.WorkspaceAgentProcesses{
+		CollectedAt:  collectedAt,
+		CPU:          codersdk.WorkspaceAgentResourceUsage{Used: 0.5, Unit: "cores"},
+		Memory:       codersdk.WorkspaceAgentResourceUsage{Used: 1024, Unit: "B"},
+		ProcessCount: 2,
+		Sessions: []codersdk.WorkspaceAgentSessionUsage{{
+			Type:         "ssh",
+			PID:          10,
+			StartedAt:    collectedAt,
+			ProcessCount: 1,
+			CPUCores:     0.5,
+			MemoryBytes:  512,
+		}},
+		Processes: []codersdk.WorkspaceAgentProcess{
+			{PID: 1, Name: "init", StartedAt: collectedAt, MemoryBytes: 512},
+			{PID: 10, PPID: 1, Name: "bash", StartedAt: collectedAt, CPUCores: 0.5, MemoryBytes: 512, SessionPID: 10},
+		},
+	})
+	require.NoError(t, err)
+
+	processes, err := client.WorkspaceAgentProcesses(ctx, agentID)
+	require.NoError(t, err)
+	require.WithinDuration(t, collectedAt, processes.CollectedAt, time.Millisecond)
+	require.Equal(t, 2, processes.ProcessCount)
+	require.InDelta(t, 0.5, processes.CPU.Used, 0.001)
+	require.Len(t, processes.Sessions, 1)
+	require.EqualValues(t, 10, processes.Sessions[0].PID)
+	require.Len(t, processes.Processes, 2)
+	require.Equal(t, "bash", processes.Processes[1].Name)
+	require.EqualValues(t, 10, processes.Processes[1].SessionPID)
+}
+
+func TestWorkspaceAgentKillProcess(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep isn't available on Windows")
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sleep", "60")
+	require.NoError(t, cmd.Start())
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+
+	err := client.WorkspaceAgentKillProcess(ctx, agentID, int32(cmd.Process.Pid), codersdk.KillWorkspaceAgentProcessRequest{
+		Signal: codersdk.WorkspaceAgentProcessSignalKill,
+	})
+	require.NoError(t, err)
+	select {
+	case err := <-waitErr:
+		require.Error(t, err)
+	case <-ctx.Done():
+		t.Fatal("process wasn't killed")
+	}
+
+	err = client.WorkspaceAgentKillProcess(ctx, agentID, -1, codersdk.KillWorkspaceAgentProcessRequest{})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
@@ -8,10 +8,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"mime"
 	"net"
 	"net/http"
 	"net/netip"
 	"net/url"
+	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"strconv"
 	"strings"
@@ -30,6 +34,8 @@ import (
 	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/agent/agentupdate"
+	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/gitauth"
@@ -37,6 +43,7 @@ import (
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/coderd/wsconncache"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/tailnet"
@@ -62,8 +69,27 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	// nolint:gocritic // Getting workspace agent scripts is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// nolint:gocritic // Getting workspace agent log sources is a system function.
+	logSources, err := api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent log sources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps),
+		convertScripts(scripts, logSources), convertLogSources(logSources), api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -88,7 +114,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -116,6 +142,25 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// nolint:gocritic // Getting workspace agent scripts is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// nolint:gocritic // Getting workspace agent log sources is a system function.
+	logSources, err := api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent log sources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -160,6 +205,13 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		vscodeProxyURI += fmt.Sprintf(":%s", api.AccessURL.Port())
 	}
 
+	// The checksum is sent over the authenticated agent API, so the agent
+	// doesn't have to trust the response it downloads the binary in.
+	binaryChecksum, err := api.SiteHandler.BinaryHash(agentupdate.BinaryName(workspaceAgent.OperatingSystem, workspaceAgent.Architecture))
+	if err != nil && !xerrors.Is(err, os.ErrNotExist) {
+		api.Logger.Warn(ctx, "hash agent binary", slog.Error(err))
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.Manifest{
 		Apps:                     convertApps(dbApps),
 		DERPMap:                  api.DERPMap,
@@ -174,6 +226,11 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		ShutdownScriptTimeout:    time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		Metadata:                 convertWorkspaceAgentMetadataDesc(metadata),
+		Scripts:                  convertScripts(scripts, logSources),
+		RecordSessions:           api.DeploymentValues.SessionRecording.Value(),
+		ProcessReportInterval:    agentProcessReportInterval,
+		ServerVersion:            buildinfo.Version(),
+		ServerBinaryChecksum:     binaryChecksum,
 	})
 }
 
@@ -191,7 +248,7 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -283,24 +340,47 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 	var logs []database.WorkspaceAgentStartupLog
 	// Ensure logs are not written after script ended.
 	scriptEndedError := xerrors.New("startup script has ended")
+	unknownLogSourceError := xerrors.New("unknown log source")
 	err := api.Database.InTx(func(db database.Store) error {
-		state, err := db.GetWorkspaceAgentLifecycleStateByID(ctx, workspaceAgent.ID)
-		if err != nil {
-			return xerrors.Errorf("workspace agent startup script status: %w", err)
-		}
+		if req.LogSourceID == uuid.Nil {
+			state, err := db.GetWorkspaceAgentLifecycleStateByID(ctx, workspaceAgent.ID)
+			if err != nil {
+				return xerrors.Errorf("workspace agent startup script status: %w", err)
+			}
 
-		if state.ReadyAt.Valid {
-			// The agent startup script has already ended, so we don't want to
-			// process any more logs.
-			return scriptEndedError
+			if state.ReadyAt.Valid {
+				// The agent startup script has already ended, so we don't want to
+				// process any more logs.
+				return scriptEndedError
+			}
+		} else {
+			// Named scripts may run on stop or on a schedule, so their logs
+			// are accepted for the lifetime of the agent.
+			// nolint:gocritic // Getting workspace agent log sources is a system function.
+			logSources, err := db.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+			if err != nil {
+				return xerrors.Errorf("get workspace agent log sources: %w", err)
+			}
+			found := false
+			for _, logSource := range logSources {
+				if logSource.ID == req.LogSourceID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return unknownLogSourceError
+			}
 		}
 
+		var err error
 		logs, err = db.InsertWorkspaceAgentStartupLogs(ctx, database.InsertWorkspaceAgentStartupLogsParams{
 			AgentID:      workspaceAgent.ID,
 			CreatedAt:    createdAt,
 			Output:       output,
 			Level:        level,
 			OutputLength: int32(outputLength),
+			LogSourceID:  req.LogSourceID,
 		})
 		return err
 	}, nil)
@@ -312,6 +392,13 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 			})
 			return
 		}
+		if errors.Is(err, unknownLogSourceError) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid log source provided.",
+				Detail:  fmt.Sprintf("log source %q does not belong to this agent", req.LogSourceID),
+			})
+			return
+		}
 		if database.IsStartupLogsLimitError(err) {
 			if !workspaceAgent.StartupLogsOverflowed {
 				err := api.Database.UpdateWorkspaceAgentStartupLogOverflowByID(ctx, database.UpdateWorkspaceAgentStartupLogOverflowByIDParams{
@@ -637,7 +724,7 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -729,6 +816,265 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// @Summary List files in workspace agent
+// @ID list-files-in-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File or directory path, relative paths are resolved from the home directory"
+// @Success 200 {object} codersdk.WorkspaceAgentListFilesResponse
+// @Router /workspaceagents/{workspaceagent}/files [get]
+func (api *API) workspaceAgentListFiles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	resp, err := agentConn.ListFiles(ctx, r.URL.Query().Get("path"))
+	if err != nil {
+		writeWorkspaceAgentFilesError(ctx, rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Read file from workspace agent
+// @ID read-file-from-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative paths are resolved from the home directory"
+// @Success 200
+// @Router /workspaceagents/{workspaceagent}/files/contents [get]
+func (api *API) workspaceAgentReadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	path := r.URL.Query().Get("path")
+	file, err := agentConn.ReadFile(ctx, path)
+	if err != nil {
+		writeWorkspaceAgentFilesError(ctx, rw, err)
+		return
+	}
+	defer file.Close()
+
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": filepath.Base(path),
+	}))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(rw, file)
+}
+
+// @Summary Write file to workspace agent
+// @Description Swagger notice: Swagger 2.0 doesn't support file upload with a `content-type` different than `application/x-www-form-urlencoded`.
+// @ID write-file-to-workspace-agent
+// @Security CoderSessionToken
+// @Accept application/octet-stream
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param file formData file true "File contents"
+// @Param path query string true "File path, relative paths are resolved from the home directory"
+// @Param mode query string false "Octal file permissions, defaults to 644"
+// @Success 200 {object} codersdk.WorkspaceAgentFile
+// @Router /workspaceagents/{workspaceagent}/files/contents [put]
+func (api *API) workspaceAgentWriteFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	mode := os.FileMode(0o644)
+	if raw := r.URL.Query().Get("mode"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 8, 32)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid file mode.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		mode = os.FileMode(parsed).Perm()
+	}
+
+	file, err := agentConn.WriteFile(ctx, r.URL.Query().Get("path"), mode, r.Body)
+	if err != nil {
+		writeWorkspaceAgentFilesError(ctx, rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, file)
+}
+
+// @Summary Download tar archive from workspace agent
+// @ID download-tar-archive-from-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File or directory path, relative paths are resolved from the home directory"
+// @Success 200
+// @Router /workspaceagents/{workspaceagent}/files/tar [get]
+func (api *API) workspaceAgentDownloadTar(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	archive, err := agentConn.DownloadTar(ctx, r.URL.Query().Get("path"))
+	if err != nil {
+		writeWorkspaceAgentFilesError(ctx, rw, err)
+		return
+	}
+	defer archive.Close()
+
+	rw.Header().Set("Content-Type", "application/x-tar")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(rw, archive)
+}
+
+// @Summary Upload tar archive to workspace agent
+// @Description Swagger notice: Swagger 2.0 doesn't support file upload with a `content-type` different than `application/x-www-form-urlencoded`.
+// @ID upload-tar-archive-to-workspace-agent
+// @Security CoderSessionToken
+// @Accept application/x-tar
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param file formData file true "Tar archive"
+// @Param path query string true "Directory to extract into, relative paths are resolved from the home directory"
+// @Success 200 {object} codersdk.WorkspaceAgentFile
+// @Router /workspaceagents/{workspaceagent}/files/tar [put]
+func (api *API) workspaceAgentUploadTar(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := api.workspaceAgentFilesConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	file, err := agentConn.UploadTar(ctx, r.URL.Query().Get("path"), r.Body)
+	if err != nil {
+		writeWorkspaceAgentFilesError(ctx, rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, file)
+}
+
+// @Summary Report user activity in workspace agent
+// @ID report-user-activity-in-workspace-agent
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/activity [post]
+func (api *API) workspaceAgentReportActivity(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	// Activity keeps the workspace running, like extending its deadline.
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	agentConn, release, ok := api.acquireWorkspaceAgentConn(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err := agentConn.ReportActivity(ctx)
+	if err != nil {
+		writeWorkspaceAgentAPIError(ctx, rw, "Internal error reporting activity to workspace agent.", err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// workspaceAgentFilesConn authorizes access to the files of the workspace
+// agent in the request and returns a connection to it. Moving files in and
+// out of a workspace requires the same permission as connecting to it.
+func (api *API) workspaceAgentFilesConn(rw http.ResponseWriter, r *http.Request) (*wsconncache.Conn, func(), bool) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return nil, nil, false
+	}
+	if r.URL.Query().Get("path") == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: `The "path" query parameter is required.`,
+		})
+		return nil, nil, false
+	}
+	return api.acquireWorkspaceAgentConn(rw, r)
+}
+
+// acquireWorkspaceAgentConn returns a connection to the workspace agent in
+// the request if it's connected. The caller must authorize the request.
+func (api *API) acquireWorkspaceAgentConn(rw http.ResponseWriter, r *http.Request) (*wsconncache.Conn, func(), bool) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	apiAgent, err := convertWorkspaceAgent(
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return nil, nil, false
+	}
+
+	agentConn, release, err := api.workspaceAgentCache.Acquire(workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return agentConn, release, true
+}
+
+// writeWorkspaceAgentFilesError relays errors returned by the agent file
+// API, keeping the status code the agent responded with.
+func writeWorkspaceAgentFilesError(ctx context.Context, rw http.ResponseWriter, err error) {
+	writeWorkspaceAgentAPIError(ctx, rw, "Internal error accessing workspace agent files.", err)
+}
+
+// writeWorkspaceAgentAPIError relays errors returned by the agent HTTP API,
+// keeping the status code the agent responded with. Other errors are
+// written as internal errors with the given message.
+func writeWorkspaceAgentAPIError(ctx context.Context, rw http.ResponseWriter, message string, err error) {
+	var sdkErr *codersdk.Error
+	if xerrors.As(err, &sdkErr) {
+		httpapi.Write(ctx, rw, sdkErr.StatusCode(), sdkErr.Response)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
+
 func (api *API) dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
 	clientConn, serverConn := net.Pipe()
 	conn, err := tailnet.NewConn(&tailnet.Options{
@@ -1139,13 +1485,50 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	go httpapi.Heartbeat(ctx, conn)
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	// Workspace proxies coordinate their own connections, which aren't tied
+	// to a single user.
+	var userID uuid.UUID
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		userID = apiKey.UserID
+	}
+	coordinatorConn := stampClientNodes(wsNetConn, userID)
+	defer coordinatorConn.Close()
+	err = (*api.TailnetCoordinator.Load()).ServeClient(coordinatorConn, uuid.New(), workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
 	}
 }
 
+// stampClientNodes returns a connection for the coordinator that sets the
+// user of every node the client sends to userID, overwriting whatever the
+// client claimed. Updates from the coordinator are passed through as is.
+func stampClientNodes(conn net.Conn, userID uuid.UUID) net.Conn {
+	coordinatorConn, clientConn := net.Pipe()
+	go func() {
+		defer clientConn.Close()
+		decoder := json.NewDecoder(conn)
+		encoder := json.NewEncoder(clientConn)
+		for {
+			var node tailnet.Node
+			err := decoder.Decode(&node)
+			if err != nil {
+				return
+			}
+			node.UserID = userID
+			err = encoder.Encode(&node)
+			if err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		defer conn.Close()
+		_, _ = io.Copy(conn, clientConn)
+	}()
+	return coordinatorConn
+}
+
 func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 	apps := make([]codersdk.WorkspaceApp, 0)
 	for _, dbApp := range dbApps {
@@ -1177,6 +1560,7 @@ func convertWorkspaceAgentMetadataDesc(mds []database.WorkspaceAgentMetadatum) [
 			DisplayName: datum.DisplayName,
 			Key:         datum.Key,
 			Script:      datum.Script,
+			Collector:   datum.Collector,
 			Interval:    datum.Interval,
 			Timeout:     datum.Timeout,
 		})
@@ -1184,7 +1568,7 @@ func convertWorkspaceAgentMetadataDesc(mds []database.WorkspaceAgentMetadatum) [
 	return metadata
 }
 
-func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
+func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, scripts []codersdk.WorkspaceAgentScript, logSources []codersdk.WorkspaceAgentLogSource, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
 	var envs map[string]string
 	if dbAgent.EnvironmentVariables.Valid {
 		err := json.Unmarshal(dbAgent.EnvironmentVariables.RawMessage, &envs)
@@ -1222,6 +1606,17 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		ShutdownScript:               dbAgent.ShutdownScript.String,
 		ShutdownScriptTimeoutSeconds: dbAgent.ShutdownScriptTimeoutSeconds,
 		Subsystem:                    codersdk.AgentSubsystem(dbAgent.Subsystem),
+		Scripts:                      scripts,
+		LogSources:                   logSources,
+	}
+	for _, script := range scripts {
+		// A script that blocks login makes the whole agent blocking,
+		// regardless of the legacy startup script behavior.
+		if script.RunOnStart && script.StartBlocksLogin {
+			workspaceAgent.StartupScriptBehavior = codersdk.WorkspaceAgentStartupScriptBehaviorBlocking
+			workspaceAgent.LoginBeforeReady = false
+			break
+		}
 	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
@@ -1265,6 +1660,51 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 	return workspaceAgent, nil
 }
 
+func convertLogSources(dbLogSources []database.WorkspaceAgentLogSource) []codersdk.WorkspaceAgentLogSource {
+	logSources := make([]codersdk.WorkspaceAgentLogSource, 0, len(dbLogSources))
+	for _, dbLogSource := range dbLogSources {
+		logSources = append(logSources, codersdk.WorkspaceAgentLogSource{
+			WorkspaceAgentID: dbLogSource.WorkspaceAgentID,
+			ID:               dbLogSource.ID,
+			CreatedAt:        dbLogSource.CreatedAt,
+			DisplayName:      dbLogSource.DisplayName,
+			Icon:             dbLogSource.Icon,
+		})
+	}
+	return logSources
+}
+
+func convertScripts(dbScripts []database.WorkspaceAgentScript, dbLogSources []database.WorkspaceAgentLogSource) []codersdk.WorkspaceAgentScript {
+	displayNames := make(map[uuid.UUID]string, len(dbLogSources))
+	for _, dbLogSource := range dbLogSources {
+		displayNames[dbLogSource.ID] = dbLogSource.DisplayName
+	}
+	scripts := make([]codersdk.WorkspaceAgentScript, 0, len(dbScripts))
+	for _, dbScript := range dbScripts {
+		script := codersdk.WorkspaceAgentScript{
+			LogSourceID:      dbScript.LogSourceID,
+			LogPath:          dbScript.LogPath,
+			DisplayName:      displayNames[dbScript.LogSourceID],
+			Script:           dbScript.Script,
+			Cron:             dbScript.Cron,
+			RunOnStart:       dbScript.RunOnStart,
+			RunOnStop:        dbScript.RunOnStop,
+			StartBlocksLogin: dbScript.StartBlocksLogin,
+			Timeout:          time.Duration(dbScript.TimeoutSeconds) * time.Second,
+			Status:           codersdk.WorkspaceAgentScriptStatus(dbScript.Status),
+			ExitCode:         dbScript.ExitCode,
+		}
+		if dbScript.StartedAt.Valid {
+			script.StartedAt = &dbScript.StartedAt.Time
+		}
+		if dbScript.EndedAt.Valid {
+			script.EndedAt = &dbScript.EndedAt.Time
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts
+}
+
 // @Summary Submit workspace agent stats
 // @ID submit-workspace-agent-stats
 // @Security CoderSessionToken
@@ -1308,7 +1748,26 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		slog.F("payload", req),
 	)
 
-	if req.ConnectionCount > 0 {
+	idleThreshold, err := api.idleThresholds.Get(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// If the template has an idle threshold, only user activity reported by
+	// the agent keeps the workspace running. Otherwise any connection does.
+	// Agents report no activity after they restarted, or if they are too old
+	// to track it, so they fall back to connections too.
+	knownActivity := idleThreshold > 0 && !req.LastActivityAt.IsZero()
+	switch {
+	case knownActivity && database.Now().Sub(req.LastActivityAt) < idleThreshold:
+		activityBumpWorkspace(ctx, api.Logger.Named("activity_bump"), api.Database, workspace.ID)
+	case knownActivity:
+		idleStopWorkspace(ctx, api.Logger.Named("idle_stop"), api.Database, workspace.ID, req.LastActivityAt, idleThreshold)
+	case req.ConnectionCount > 0:
 		activityBumpWorkspace(ctx, api.Logger.Named("activity_bump"), api.Database, workspace.ID)
 	}
 
@@ -1461,6 +1920,66 @@ func (api *API) workspaceAgentPostMetadata(rw http.ResponseWriter, r *http.Reque
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Submit workspace agent script status
+// @ID submit-workspace-agent-script-status
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostScriptStatusRequest true "Script status request"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/script-status [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentPostScriptStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostScriptStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	status := database.WorkspaceAgentScriptStatus(req.Status)
+	if !status.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script status provided.",
+			Detail:  fmt.Sprintf("invalid script status: %q", req.Status),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	_, err = api.Database.UpdateWorkspaceAgentScriptStatus(ctx, database.UpdateWorkspaceAgentScriptStatusParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		LogSourceID:      req.LogSourceID,
+		Status:           status,
+		ExitCode:         req.ExitCode,
+		StartedAt:        sql.NullTime{Time: req.StartedAt, Valid: !req.StartedAt.IsZero()},
+		EndedAt:          sql.NullTime{Time: req.EndedAt, Valid: !req.EndedAt.IsZero()},
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Script not found.",
+			Detail:  fmt.Sprintf("The agent has no script with log source %s.", req.LogSourceID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
 // @Summary Watch for workspace agent metadata updates
 // @ID watch-for-workspace-agent-metadata-updates
 // @Security CoderSessionToken
@@ -1584,6 +2103,7 @@ func convertWorkspaceAgentMetadata(db []database.WorkspaceAgentMetadatum) []code
 				DisplayName: datum.DisplayName,
 				Key:         datum.Key,
 				Script:      datum.Script,
+				Collector:   datum.Collector,
 				Interval:    datum.Interval,
 				Timeout:     datum.Timeout,
 			},
@@ -2109,6 +2629,7 @@ func convertWorkspaceAgentStartupLog(logEntry database.WorkspaceAgentStartupLog)
 		CreatedAt: logEntry.CreatedAt,
 		Output:    logEntry.Output,
 		Level:     codersdk.LogLevel(logEntry.Level),
+		SourceID:  logEntry.LogSourceID,
 	}
 }
 
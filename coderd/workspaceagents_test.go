@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strconv"
@@ -897,6 +900,126 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentFiles(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hello.txt")
+	file, err := client.WorkspaceAgentWriteFile(ctx, agentID, path, 0o600, strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.Equal(t, path, file.Path)
+	require.EqualValues(t, 5, file.Size)
+
+	list, err := client.WorkspaceAgentListFiles(ctx, agentID, dir)
+	require.NoError(t, err)
+	require.True(t, list.File.IsDir)
+	require.Len(t, list.Files, 1)
+	require.Equal(t, "hello.txt", list.Files[0].Name)
+
+	rc, err := client.WorkspaceAgentReadFile(ctx, agentID, path)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	archive, err := client.WorkspaceAgentDownloadTar(ctx, agentID, dir)
+	require.NoError(t, err)
+	defer archive.Close()
+	dst := filepath.Join(t.TempDir(), "extracted")
+	file, err = client.WorkspaceAgentUploadTar(ctx, agentID, dst, archive)
+	require.NoError(t, err)
+	require.True(t, file.IsDir)
+	data, err = os.ReadFile(filepath.Join(dst, "hello.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	_, err = client.WorkspaceAgentListFiles(ctx, agentID, filepath.Join(dir, "missing"))
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	_, err = client.WorkspaceAgentListFiles(ctx, agentID, "")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
+func TestWorkspaceAgentReportActivity(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	// The agent must be connected to receive activity.
+	err = client.WorkspaceAgentReportActivity(ctx, agentID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	err = client.WorkspaceAgentReportActivity(ctx, agentID)
+	require.NoError(t, err)
+}
+
 func TestWorkspaceAgentAppHealth(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{
@@ -1388,6 +1511,80 @@ func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgent_ScriptStatus(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Scripts: []*proto.Script{{
+								DisplayName: "Startup",
+								Script:      "echo hello",
+								RunOnStart:  true,
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	manifest, err := agentClient.Manifest(testutil.Context(t, testutil.WaitLong))
+	require.NoError(t, err)
+	require.Len(t, manifest.Scripts, 1)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+			LogSourceID: manifest.Scripts[0].LogSourceID,
+			Status:      codersdk.WorkspaceAgentScriptStatusSucceeded,
+			StartedAt:   time.Now().Add(-time.Second),
+			EndedAt:     time.Now(),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("UnknownLogSource", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+			LogSourceID: uuid.New(),
+			Status:      codersdk.WorkspaceAgentScriptStatusSucceeded,
+			StartedAt:   time.Now().Add(-time.Second),
+			EndedAt:     time.Now(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1417,7 +1614,7 @@ func TestWorkspaceAgent_Metadata(t *testing.T) {
 								{
 									DisplayName: "Second Meta",
 									Key:         "foo2",
-									Script:      "echo howdy",
+									Collector:   "cpu",
 									Interval:    10,
 									Timeout:     3,
 								},
@@ -1464,6 +1661,8 @@ func TestWorkspaceAgent_Metadata(t *testing.T) {
 	require.Equal(t, "echo hi", manifest.Metadata[0].Script)
 	require.EqualValues(t, 10, manifest.Metadata[0].Interval)
 	require.EqualValues(t, 3, manifest.Metadata[0].Timeout)
+	require.Equal(t, "cpu", manifest.Metadata[1].Collector)
+	require.Empty(t, manifest.Metadata[1].Script)
 
 	post := func(key string, mr codersdk.WorkspaceAgentMetadataResult) {
 		err := agentClient.PostMetadata(ctx, key, mr)
@@ -36,7 +36,8 @@ func (api *API) appHost(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AppHostResponse{
-		Host: host,
+		Host:            host,
+		DisablePathApps: api.DeploymentValues.DisablePathApps.Value(),
 	})
 }
 
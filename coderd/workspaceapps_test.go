@@ -28,6 +28,7 @@ func TestGetAppHost(t *testing.T) {
 		name        string
 		accessURL   string
 		appHostname string
+		noPathApps  bool
 		expected    string
 	}{
 		{
@@ -54,6 +55,13 @@ func TestGetAppHost(t *testing.T) {
 			appHostname: "*--suffix.test.coder.com",
 			expected:    "*--suffix.test.coder.com:8443",
 		},
+		{
+			name:        "DisablePathApps",
+			accessURL:   "https://test.coder.com",
+			appHostname: "*.test.coder.com",
+			noPathApps:  true,
+			expected:    "*.test.coder.com",
+		},
 	}
 	for _, c := range cases {
 		c := c
@@ -63,9 +71,12 @@ func TestGetAppHost(t *testing.T) {
 			accessURL, err := url.Parse(c.accessURL)
 			require.NoError(t, err)
 
+			dv := coderdtest.DeploymentValues(t)
+			dv.DisablePathApps = clibase.Bool(c.noPathApps)
 			client := coderdtest.New(t, &coderdtest.Options{
-				AccessURL:   accessURL,
-				AppHostname: c.appHostname,
+				AccessURL:        accessURL,
+				AppHostname:      c.appHostname,
+				DeploymentValues: dv,
 			})
 
 			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
@@ -80,6 +91,7 @@ func TestGetAppHost(t *testing.T) {
 			host, err = client.AppHost(ctx)
 			require.NoError(t, err)
 			require.Equal(t, c.expected, host.Host)
+			require.Equal(t, c.noPathApps, host.DisablePathApps)
 		})
 	}
 }
@@ -257,3 +257,15 @@ func (*client) PostStartup(_ context.Context, _ agentsdk.PostStartupRequest) err
 func (*client) PatchStartupLogs(_ context.Context, _ agentsdk.PatchStartupLogs) error {
 	return nil
 }
+
+func (*client) PostScriptStatus(_ context.Context, _ agentsdk.PostScriptStatusRequest) error {
+	return nil
+}
+
+func (*client) PostSessionRecording(_ context.Context, _ agentsdk.PostSessionRecordingRequest) error {
+	return nil
+}
+
+func (*client) PostProcesses(_ context.Context, _ codersdk.WorkspaceAgentProcesses) error {
+	return nil
+}
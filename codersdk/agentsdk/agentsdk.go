@@ -100,6 +100,19 @@ type Manifest struct {
 	ShutdownScriptTimeout    time.Duration                                `json:"shutdown_script_timeout"`
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	// RecordSessions enables recording of SSH and reconnecting PTY sessions.
+	RecordSessions bool `json:"record_sessions"`
+	// ProcessReportInterval is how often the agent reports the resource
+	// usage of its processes. Zero disables reporting.
+	ProcessReportInterval time.Duration `json:"process_report_interval"`
+	// ServerVersion is the version of coderd. The agent updates itself
+	// to match it if self-updates are enabled.
+	ServerVersion string `json:"server_version"`
+	// ServerBinaryChecksum is the SHA1 checksum of the agent binary coderd
+	// serves for the agent's operating system and architecture. The agent
+	// verifies updates against it, and doesn't update if it's empty.
+	ServerBinaryChecksum string `json:"server_binary_checksum"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -485,6 +498,11 @@ type Stats struct {
 	// that are normal, non-tagged SSH sessions.
 	SessionCountSSH int64 `json:"session_count_ssh"`
 
+	// LastActivityAt is the last time a user interacted with the workspace,
+	// e.g. typed into a terminal, used an app or had VS Code focused. It's
+	// zero if there has been no activity since the agent started.
+	LastActivityAt time.Time `json:"last_activity_at" format:"date-time"`
+
 	// Metrics collected by the agent
 	Metrics []AgentMetric `json:"metrics"`
 }
@@ -576,7 +594,10 @@ type StartupLog struct {
 }
 
 type PatchStartupLogs struct {
-	Logs []StartupLog `json:"logs"`
+	// LogSourceID is the log source the logs belong to. It is the zero
+	// UUID for the legacy startup script.
+	LogSourceID uuid.UUID    `json:"log_source_id" format:"uuid"`
+	Logs        []StartupLog `json:"logs"`
 }
 
 // PatchStartupLogs writes log messages to the agent startup script.
@@ -593,6 +614,72 @@ func (c *Client) PatchStartupLogs(ctx context.Context, req PatchStartupLogs) err
 	return nil
 }
 
+// PostScriptStatusRequest reports the outcome of a single script run.
+type PostScriptStatusRequest struct {
+	LogSourceID uuid.UUID                           `json:"log_source_id" format:"uuid"`
+	Status      codersdk.WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode    int32                               `json:"exit_code"`
+	StartedAt   time.Time                           `json:"started_at" format:"date-time"`
+	EndedAt     time.Time                           `json:"ended_at" format:"date-time"`
+}
+
+// PostScriptStatus reports the status of a script run by the agent.
+func (c *Client) PostScriptStatus(ctx context.Context, req PostScriptStatusRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/script-status", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostSessionRecordingRequest uploads a chunk of an asciicast v2 session
+// recording. Chunks are numbered from zero and the first chunk starts with
+// the asciicast header.
+type PostSessionRecordingRequest struct {
+	ID        uuid.UUID                     `json:"id" format:"uuid"`
+	Type      codersdk.SessionRecordingType `json:"type"`
+	StartedAt time.Time                     `json:"started_at" format:"date-time"`
+	// UserID is the user that connected to the session, as reported by the
+	// coordinator. It is the zero UUID if the connection is unattributed.
+	UserID   uuid.UUID `json:"user_id" format:"uuid"`
+	Sequence int32     `json:"sequence"`
+	Data     []byte    `json:"data"`
+	// Final is set on the last chunk of a recording, after the session has
+	// ended.
+	Final bool `json:"final"`
+}
+
+// PostSessionRecording uploads a chunk of a session recording.
+func (c *Client) PostSessionRecording(ctx context.Context, req PostSessionRecordingRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/session-recordings", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostProcesses reports a snapshot of the resource usage of the processes
+// inside of the workspace.
+func (c *Client) PostProcesses(ctx context.Context, req codersdk.WorkspaceAgentProcesses) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/processes", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type GitAuthResponse struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
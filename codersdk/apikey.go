@@ -18,7 +18,7 @@ type APIKey struct {
 	ExpiresAt       time.Time   `json:"expires_at" validate:"required" format:"date-time"`
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
-	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
+	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token,oauth2_provider_app"`
 	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
@@ -37,6 +37,9 @@ const (
 	// API keys can still be created by an owner and used by the user.
 	// These keys would use the `LoginTypeToken` type.
 	LoginTypeNone LoginType = "none"
+	// LoginTypeOAuth2ProviderApp is used by the API keys issued to OAuth2 apps
+	// that act on behalf of the user.
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
 )
 
 type APIKeyScope string
@@ -14,15 +14,18 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeGitSSHKey       ResourceType = "git_ssh_key"
-	ResourceTypeAPIKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeLicense         ResourceType = "license"
+	ResourceTypeTemplate          ResourceType = "template"
+	ResourceTypeTemplateVersion   ResourceType = "template_version"
+	ResourceTypeUser              ResourceType = "user"
+	ResourceTypeWorkspace         ResourceType = "workspace"
+	ResourceTypeWorkspaceBuild    ResourceType = "workspace_build"
+	ResourceTypeGitSSHKey         ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey            ResourceType = "api_key"
+	ResourceTypeGroup             ResourceType = "group"
+	ResourceTypeLicense           ResourceType = "license"
+	ResourceTypeOAuth2ProviderApp ResourceType = "oauth2_provider_app"
+	//nolint:gosec // Not a secret.
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -47,6 +50,10 @@ func (r ResourceType) FriendlyString() string {
 		return "group"
 	case ResourceTypeLicense:
 		return "license"
+	case ResourceTypeOAuth2ProviderApp:
+		return "OAuth2 app"
+	case ResourceTypeOAuth2ProviderAppSecret:
+		return "OAuth2 app secret"
 	default:
 		return "unknown"
 	}
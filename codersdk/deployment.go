@@ -164,7 +164,9 @@ type DeploymentValues struct {
 	SSHConfig                       SSHConfig                       `json:"config_ssh,omitempty" typescript:",notnull"`
 	WgtunnelHost                    clibase.String                  `json:"wgtunnel_host,omitempty" typescript:",notnull"`
 	DisableOwnerWorkspaceExec       clibase.Bool                    `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	SessionRecording                clibase.Bool                    `json:"session_recording,omitempty" typescript:",notnull"`
 	ProxyHealthStatusInterval       clibase.Duration                `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
+	Notifications                   NotificationsConfig             `json:"notifications,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -254,21 +256,23 @@ type OAuth2GithubConfig struct {
 }
 
 type OIDCConfig struct {
-	AllowSignups        clibase.Bool                      `json:"allow_signups" typescript:",notnull"`
-	ClientID            clibase.String                    `json:"client_id" typescript:",notnull"`
-	ClientSecret        clibase.String                    `json:"client_secret" typescript:",notnull"`
-	EmailDomain         clibase.StringArray               `json:"email_domain" typescript:",notnull"`
-	IssuerURL           clibase.String                    `json:"issuer_url" typescript:",notnull"`
-	Scopes              clibase.StringArray               `json:"scopes" typescript:",notnull"`
-	IgnoreEmailVerified clibase.Bool                      `json:"ignore_email_verified" typescript:",notnull"`
-	UsernameField       clibase.String                    `json:"username_field" typescript:",notnull"`
-	EmailField          clibase.String                    `json:"email_field" typescript:",notnull"`
-	AuthURLParams       clibase.Struct[map[string]string] `json:"auth_url_params" typescript:",notnull"`
-	IgnoreUserInfo      clibase.Bool                      `json:"ignore_user_info" typescript:",notnull"`
-	GroupField          clibase.String                    `json:"groups_field" typescript:",notnull"`
-	GroupMapping        clibase.Struct[map[string]string] `json:"group_mapping" typescript:",notnull"`
-	SignInText          clibase.String                    `json:"sign_in_text" typescript:",notnull"`
-	IconURL             clibase.URL                       `json:"icon_url" typescript:",notnull"`
+	AllowSignups        clibase.Bool                        `json:"allow_signups" typescript:",notnull"`
+	ClientID            clibase.String                      `json:"client_id" typescript:",notnull"`
+	ClientSecret        clibase.String                      `json:"client_secret" typescript:",notnull"`
+	EmailDomain         clibase.StringArray                 `json:"email_domain" typescript:",notnull"`
+	IssuerURL           clibase.String                      `json:"issuer_url" typescript:",notnull"`
+	Scopes              clibase.StringArray                 `json:"scopes" typescript:",notnull"`
+	IgnoreEmailVerified clibase.Bool                        `json:"ignore_email_verified" typescript:",notnull"`
+	UsernameField       clibase.String                      `json:"username_field" typescript:",notnull"`
+	EmailField          clibase.String                      `json:"email_field" typescript:",notnull"`
+	AuthURLParams       clibase.Struct[map[string]string]   `json:"auth_url_params" typescript:",notnull"`
+	IgnoreUserInfo      clibase.Bool                        `json:"ignore_user_info" typescript:",notnull"`
+	GroupField          clibase.String                      `json:"groups_field" typescript:",notnull"`
+	GroupMapping        clibase.Struct[map[string]string]   `json:"group_mapping" typescript:",notnull"`
+	UserRoleField       clibase.String                      `json:"user_role_field" typescript:",notnull"`
+	UserRoleMapping     clibase.Struct[map[string][]string] `json:"user_role_mapping" typescript:",notnull"`
+	SignInText          clibase.String                      `json:"sign_in_text" typescript:",notnull"`
+	IconURL             clibase.URL                         `json:"icon_url" typescript:",notnull"`
 }
 
 type TelemetryConfig struct {
@@ -322,6 +326,39 @@ type RateLimitConfig struct {
 	API        clibase.Int64 `json:"api" typescript:",notnull"`
 }
 
+type NotificationsConfig struct {
+	// MaxSendAttempts is how often sending a notification is attempted
+	// before it's marked as failed.
+	MaxSendAttempts clibase.Int64              `json:"max_send_attempts" typescript:",notnull"`
+	Email           NotificationsEmailConfig   `json:"email" typescript:",notnull"`
+	Webhook         NotificationsWebhookConfig `json:"webhook" typescript:",notnull"`
+}
+
+// Enabled returns whether a notification method is configured.
+func (c NotificationsConfig) Enabled() bool {
+	return c.Email.Enabled() || c.Webhook.Enabled()
+}
+
+type NotificationsEmailConfig struct {
+	From      clibase.String `json:"from" typescript:",notnull"`
+	Smarthost clibase.String `json:"smarthost" typescript:",notnull"`
+	Hello     clibase.String `json:"hello" typescript:",notnull"`
+	Username  clibase.String `json:"username" typescript:",notnull"`
+	Password  clibase.String `json:"password" typescript:",notnull"`
+}
+
+func (c NotificationsEmailConfig) Enabled() bool {
+	return c.From != "" && c.Smarthost != ""
+}
+
+type NotificationsWebhookConfig struct {
+	Endpoint clibase.URL `json:"endpoint" typescript:",notnull"`
+}
+
+func (c NotificationsWebhookConfig) Enabled() bool {
+	return c.Endpoint.String() != ""
+}
+
 type SwaggerConfig struct {
 	Enable clibase.Bool `json:"enable" typescript:",notnull"`
 }
@@ -461,6 +498,21 @@ when required by your organization's security policy.`,
 			Description: `Tune the behavior of the provisioner, which is responsible for creating, updating, and deleting workspace resources.`,
 			YAML:        "provisioning",
 		}
+		deploymentGroupNotifications = clibase.Group{
+			Name:        "Notifications",
+			Description: `Notify users of events in their workspaces and account by email or webhook.`,
+			YAML:        "notifications",
+		}
+		deploymentGroupNotificationsEmail = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Email",
+			YAML:   "email",
+		}
+		deploymentGroupNotificationsWebhook = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Webhook",
+			YAML:   "webhook",
+		}
 		deploymentGroupDangerous = clibase.Group{
 			Name: "⚠️ Dangerous",
 			YAML: "dangerous",
@@ -1013,6 +1065,26 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "groupMapping",
 		},
+		{
+			Name:        "OIDC User Role Field",
+			Description: "The claim to sync the roles of users from at each login. Roles are not synced if this is empty, and can only be changed by the identity provider otherwise. Roles that don't exist in Coder are ignored.",
+			Flag:        "oidc-user-role-field",
+			Env:         "CODER_OIDC_USER_ROLE_FIELD",
+			Default:     "",
+			Value:       &c.OIDC.UserRoleField,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "userRoleField",
+		},
+		{
+			Name:        "OIDC User Role Mapping",
+			Description: "A map of OIDC roles and the Coder roles they should map to, e.g. {\"coder-admins\": [\"owner\"]}. Organization roles are given as \"<role>:<organization_id>\". Roles that aren't mapped are used by name.",
+			Flag:        "oidc-user-role-mapping",
+			Env:         "CODER_OIDC_USER_ROLE_MAPPING",
+			Default:     "{}",
+			Value:       &c.OIDC.UserRoleMapping,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "userRoleMapping",
+		},
 		{
 			Name:        "OpenID Connect sign in text",
 			Description: "The text to show on the OpenID Connect sign in button.",
@@ -1443,6 +1515,15 @@ when required by your organization's security policy.`,
 			YAML:        "disableOwnerWorkspaceAccess",
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Session Recording",
+			Description: "Record the input and output of SSH and web terminal sessions in workspaces. Recordings are stored in asciicast v2 format, can be listed and downloaded with \"coder recordings\", and are deleted after 30 days.",
+			Flag:        "session-recording",
+			Env:         "CODER_SESSION_RECORDING",
+
+			Value: &c.SessionRecording,
+			YAML:  "sessionRecording",
+		},
 		{
 			Name:        "Session Duration",
 			Description: "The token expiry duration for browser sessions. Sessions may last longer if they are actively making requests, but this functionality can be disabled via --disable-session-expiry-refresh.",
@@ -1555,6 +1636,72 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupNetworkingHTTP,
 			YAML:        "proxyHealthInterval",
 		},
+		// Notifications settings
+		{
+			Name:        "Notifications Max Send Attempts",
+			Description: "The number of times sending a notification is attempted before it's marked as failed.",
+			Flag:        "notifications-max-send-attempts",
+			Env:         "CODER_NOTIFICATIONS_MAX_SEND_ATTEMPTS",
+			Default:     "5",
+			Value:       &c.Notifications.MaxSendAttempts,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "maxSendAttempts",
+		},
+		{
+			Name:        "Notifications Email From",
+			Description: "The sender address of notification emails. Notifications are sent by email when this and the smarthost are set.",
+			Flag:        "notifications-email-from",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FROM",
+			Value:       &c.Notifications.Email.From,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "from",
+		},
+		{
+			Name:        "Notifications Email Smarthost",
+			Description: "The host:port of the SMTP server notification emails are sent through. STARTTLS is used when the server supports it.",
+			Flag:        "notifications-email-smarthost",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_SMARTHOST",
+			Value:       &c.Notifications.Email.Smarthost,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "smarthost",
+		},
+		{
+			Name:        "Notifications Email Hello",
+			Description: "The hostname to identify with to the SMTP server.",
+			Flag:        "notifications-email-hello",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_HELLO",
+			Default:     "localhost",
+			Value:       &c.Notifications.Email.Hello,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "hello",
+		},
+		{
+			Name:        "Notifications Email Username",
+			Description: "The username to authenticate to the SMTP server with. Authentication is skipped when unset.",
+			Flag:        "notifications-email-username",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_USERNAME",
+			Value:       &c.Notifications.Email.Username,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "username",
+		},
+		{
+			Name:        "Notifications Email Password",
+			Description: "The password to authenticate to the SMTP server with.",
+			Flag:        "notifications-email-password",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Notifications.Email.Password,
+			Group:       &deploymentGroupNotificationsEmail,
+		},
+		{
+			Name:        "Notifications Webhook Endpoint",
+			Description: "The URL notifications are sent to as JSON in POST requests. Notifications are sent by webhook when this is set.",
+			Flag:        "notifications-webhook-endpoint",
+			Env:         "CODER_NOTIFICATIONS_WEBHOOK_ENDPOINT",
+			Value:       &c.Notifications.Webhook.Endpoint,
+			Group:       &deploymentGroupNotificationsWebhook,
+			YAML:        "endpoint",
+		},
 	}
 	return opts
 }
@@ -1840,6 +1987,8 @@ func (c *Client) DeploymentDAUs(ctx context.Context, tzOffset int) (*DAUsRespons
 type AppHostResponse struct {
 	// Host is the externally accessible URL for the Coder instance.
 	Host string `json:"host"`
+	// DisablePathApps is true if apps can only be accessed on a subdomain.
+	DisablePathApps bool `json:"disable_path_apps"`
 }
 
 // AppHost returns the site-wide application wildcard hostname without the
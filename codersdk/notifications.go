@@ -0,0 +1,72 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// NotificationTemplate is a kind of notification sent to users.
+type NotificationTemplate string
+
+const (
+	NotificationTemplateWorkspaceAutostopped       NotificationTemplate = "workspace_autostopped"
+	NotificationTemplateWorkspaceBuildFailed       NotificationTemplate = "workspace_build_failed"
+	NotificationTemplateWorkspaceMarkedForDeletion NotificationTemplate = "workspace_marked_for_deletion"
+	NotificationTemplateUserCreated                NotificationTemplate = "user_created"
+	NotificationTemplateUserSuspended              NotificationTemplate = "user_suspended"
+)
+
+// NotificationTemplates are all notifications users can receive.
+var NotificationTemplates = []NotificationTemplate{
+	NotificationTemplateWorkspaceAutostopped,
+	NotificationTemplateWorkspaceBuildFailed,
+	NotificationTemplateWorkspaceMarkedForDeletion,
+	NotificationTemplateUserCreated,
+	NotificationTemplateUserSuspended,
+}
+
+// NotificationPreference is whether a user receives a kind of notification.
+type NotificationPreference struct {
+	Template NotificationTemplate `json:"template" enums:"workspace_autostopped,workspace_build_failed,workspace_marked_for_deletion,user_created,user_suspended"`
+	Disabled bool                 `json:"disabled"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	// Preferences to change, templates that are left out keep their
+	// preference.
+	Preferences []NotificationPreference `json:"preferences" validate:"required"`
+}
+
+// NotificationPreferences returns the preference of a user for every kind of
+// notification. Notifications are enabled unless the user disabled them.
+func (c *Client) NotificationPreferences(ctx context.Context, user string) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", user), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var preferences []NotificationPreference
+	return preferences, json.NewDecoder(res.Body).Decode(&preferences)
+}
+
+// UpdateNotificationPreferences enables or disables kinds of notifications
+// for a user, and returns the resulting preferences.
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, user string, req UpdateNotificationPreferencesRequest) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", user), req)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var preferences []NotificationPreference
+	return preferences, json.NewDecoder(res.Body).Decode(&preferences)
+}
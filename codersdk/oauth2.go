@@ -0,0 +1,208 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// OAuth2ProviderApp is an app that authenticates users against Coder, which
+// acts as its OAuth2 provider.
+type OAuth2ProviderApp struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	Name        string    `json:"name"`
+	CallbackURL string    `json:"callback_url"`
+	Icon        string    `json:"icon"`
+
+	// Endpoints are included in the app response for easier discovery. The
+	// OAuth2 spec does not have a defined place to find these (for comparison,
+	// OIDC has a '/.well-known/openid-configuration' endpoint).
+	Endpoints OAuth2AppEndpoints `json:"endpoints"`
+}
+
+type OAuth2AppEndpoints struct {
+	Authorization string `json:"authorization"`
+	Token         string `json:"token"`
+}
+
+type OAuth2ProviderAppFilter struct {
+	// UserID filters the apps to those the user authorized.
+	UserID uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+}
+
+type CreateOAuth2ProviderAppRequest struct {
+	Name        string `json:"name" validate:"required,template_display_name"`
+	CallbackURL string `json:"callback_url" validate:"required,http_url"`
+	Icon        string `json:"icon" validate:"omitempty,max=256"`
+}
+
+type UpdateOAuth2ProviderAppRequest struct {
+	Name        string `json:"name" validate:"required,template_display_name"`
+	CallbackURL string `json:"callback_url" validate:"required,http_url"`
+	Icon        string `json:"icon" validate:"omitempty,max=256"`
+}
+
+// OAuth2ProviderAppSecret is a client secret of an app. The secret itself is
+// only returned when it's created, in an OAuth2ProviderAppSecretFull.
+type OAuth2ProviderAppSecret struct {
+	ID                    uuid.UUID  `json:"id" format:"uuid"`
+	CreatedAt             time.Time  `json:"created_at" format:"date-time"`
+	LastUsedAt            *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+	ClientSecretTruncated string     `json:"client_secret_truncated"`
+}
+
+// OAuth2ProviderAppSecretFull is a client secret that was just created.
+type OAuth2ProviderAppSecretFull struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	ClientSecretFull string    `json:"client_secret_full"`
+}
+
+// OAuth2TokenResponse is the response of the token endpoint, as defined by
+// RFC 6749. The access token is an API key of the user that authorized the
+// app, which is sent in the "Authorization: Bearer" or the Coder-Session-Token
+// header.
+type OAuth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the lifetime of the access token in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// OAuth2Error is the error response of the token endpoint, as defined by
+// RFC 6749.
+type OAuth2Error struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuth2ProviderApps returns the registered apps, or the apps a user
+// authorized if the filter has a user.
+func (c *Client) OAuth2ProviderApps(ctx context.Context, filter OAuth2ProviderAppFilter) ([]OAuth2ProviderApp, error) {
+	var opts []RequestOption
+	if filter.UserID != uuid.Nil {
+		opts = append(opts, WithQueryParam("user_id", filter.UserID.String()))
+	}
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/oauth2-provider/apps", nil, opts...)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var apps []OAuth2ProviderApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+func (c *Client) OAuth2ProviderApp(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+func (c *Client) CreateOAuth2ProviderApp(ctx context.Context, req CreateOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/oauth2-provider/apps", req)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+func (c *Client) UpdateOAuth2ProviderApp(ctx context.Context, id uuid.UUID, req UpdateOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), req)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+// DeleteOAuth2ProviderApp deletes an app, which revokes all API keys issued
+// to it.
+func (c *Client) DeleteOAuth2ProviderApp(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) OAuth2ProviderAppSecrets(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []OAuth2ProviderAppSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+// CreateOAuth2ProviderAppSecret generates a client secret for an app. The
+// secret can't be retrieved again.
+func (c *Client) CreateOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID) (OAuth2ProviderAppSecretFull, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return OAuth2ProviderAppSecretFull{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderAppSecretFull{}, ReadBodyAsError(res)
+	}
+	var secret OAuth2ProviderAppSecretFull
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+func (c *Client) DeleteOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID, secretID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets/%s", appID, secretID), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RevokeOAuth2ProviderApp revokes the API keys the authenticated user issued
+// to an app. The app has to be authorized again to act on behalf of the user.
+func (c *Client) RevokeOAuth2ProviderApp(ctx context.Context, appID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, "/oauth2/tokens", nil, WithQueryParam("client_id", appID.String()))
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -111,6 +111,9 @@ type CreateTemplateRequest struct {
 	// LockedTTL allows optionally specifying the max lifetime before Coder
 	// permanently deletes locked workspaces created from this template.
 	LockedTTL *int64 `json:"locked_ttl_ms,omitempty"`
+	// IdleThresholdMillis allows optionally stopping workspaces created from
+	// this template once their agents report no user activity for this long.
+	IdleThresholdMillis *int64 `json:"idle_threshold_ms,omitempty"`
 
 	// DisableEveryoneGroupAccess allows optionally disabling the default
 	// behavior of granting the 'everyone' group access to use the template.
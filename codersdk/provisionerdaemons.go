@@ -100,6 +100,21 @@ type ProvisionerJobLog struct {
 	Output    string    `json:"output"`
 }
 
+// provisionerJobLogs returns the logs of a job that exist at the time of
+// the request without waiting for more.
+func (c *Client) provisionerJobLogs(ctx context.Context, path string) ([]ProvisionerJobLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []ProvisionerJobLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
 // provisionerJobLogsAfter streams logs that occurred after a specific time.
 func (c *Client) provisionerJobLogsAfter(ctx context.Context, path string, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
 	afterQuery := ""
@@ -25,6 +25,10 @@ const (
 	ResourceReplicas                    RBACResource = "replicas"
 	ResourceDebugInfo                   RBACResource = "debug_info"
 	ResourceSystem                      RBACResource = "system"
+	ResourceWebhook                     RBACResource = "webhook"
+	ResourceCustomRole                  RBACResource = "custom_role"
+	ResourceOAuth2ProviderApp           RBACResource = "oauth2_app"
+	ResourceOAuth2ProviderAppSecret     RBACResource = "oauth2_app_secret"
 )
 
 func (r RBACResource) String() string {
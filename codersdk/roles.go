@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
 type Role struct {
@@ -46,3 +48,136 @@ func (c *Client) ListOrganizationRoles(ctx context.Context, org uuid.UUID) ([]As
 	var roles []AssignableRoles
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// Permission allows or, when negated, denies an action on all resources of a
+// type at the level it is granted at.
+type Permission struct {
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	Action       string       `json:"action" enums:"create,read,update,delete"`
+}
+
+// CustomRole is a role defined by admins in addition to the built-in roles.
+// Site roles grant their site wide permissions, and their user permissions on
+// the resources owned by the user they are assigned to. Organization roles
+// grant their organization permissions in their organization, and are
+// assigned to members as "name:organization_id".
+type CustomRole struct {
+	ID                      uuid.UUID    `json:"id" format:"uuid"`
+	Name                    string       `json:"name"`
+	DisplayName             string       `json:"display_name"`
+	OrganizationID          *uuid.UUID   `json:"organization_id,omitempty" format:"uuid"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+	CreatedAt               time.Time    `json:"created_at" format:"date-time"`
+	UpdatedAt               time.Time    `json:"updated_at" format:"date-time"`
+}
+
+// RoleName is the name the role is assigned with.
+func (r CustomRole) RoleName() string {
+	if r.OrganizationID == nil {
+		return r.Name
+	}
+	return r.Name + ":" + r.OrganizationID.String()
+}
+
+type CreateCustomRoleRequest struct {
+	Name string `json:"name" validate:"required,username"`
+	// DisplayName defaults to the name.
+	DisplayName             string       `json:"display_name,omitempty"`
+	SitePermissions         []Permission `json:"site_permissions,omitempty"`
+	OrganizationPermissions []Permission `json:"organization_permissions,omitempty"`
+	UserPermissions         []Permission `json:"user_permissions,omitempty"`
+}
+
+// UpdateCustomRoleRequest replaces the display name and the permissions of a
+// role.
+type UpdateCustomRoleRequest struct {
+	// DisplayName defaults to the name.
+	DisplayName             string       `json:"display_name,omitempty"`
+	SitePermissions         []Permission `json:"site_permissions,omitempty"`
+	OrganizationPermissions []Permission `json:"organization_permissions,omitempty"`
+	UserPermissions         []Permission `json:"user_permissions,omitempty"`
+}
+
+// customRolesPath returns the path of the site roles if the organization ID
+// is uuid.Nil, and the path of the roles of the organization otherwise.
+func customRolesPath(organizationID uuid.UUID) string {
+	if organizationID == uuid.Nil {
+		return "/api/v2/roles"
+	}
+	return fmt.Sprintf("/api/v2/organizations/%s/roles", organizationID)
+}
+
+// CustomRoles lists the custom site roles if the organization ID is uuid.Nil,
+// and the custom roles of the organization otherwise.
+func (c *Client) CustomRoles(ctx context.Context, organizationID uuid.UUID) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, customRolesPath(organizationID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+// CustomRole returns a custom site role if the organization ID is uuid.Nil,
+// and a custom role of the organization otherwise.
+func (c *Client) CustomRole(ctx context.Context, organizationID uuid.UUID, name string) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("%s/%s", customRolesPath(organizationID), name), nil)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// CreateCustomRole creates a custom site role if the organization ID is
+// uuid.Nil, and a custom role of the organization otherwise.
+func (c *Client) CreateCustomRole(ctx context.Context, organizationID uuid.UUID, req CreateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPost, customRolesPath(organizationID), req)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+func (c *Client) UpdateCustomRole(ctx context.Context, organizationID uuid.UUID, name string, req UpdateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("%s/%s", customRolesPath(organizationID), name), req)
+	if err != nil {
+		return CustomRole{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// DeleteCustomRole deletes a custom role, and removes it from the users and
+// members it is assigned to.
+func (c *Client) DeleteCustomRole(ctx context.Context, organizationID uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("%s/%s", customRolesPath(organizationID), name), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
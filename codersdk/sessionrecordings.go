@@ -0,0 +1,67 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// SessionRecordingType is the kind of terminal session that was recorded.
+type SessionRecordingType string
+
+const (
+	SessionRecordingTypeSSH             SessionRecordingType = "ssh"
+	SessionRecordingTypeReconnectingPTY SessionRecordingType = "reconnecting_pty"
+)
+
+// SessionRecording is a recorded terminal session in asciicast v2 format.
+type SessionRecording struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID      uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceBuildID uuid.UUID `json:"workspace_build_id" format:"uuid"`
+	AgentID          uuid.UUID `json:"agent_id" format:"uuid"`
+	// UserID is the user that connected to the session. It is unset if the
+	// agent could not attribute the connection.
+	UserID    *uuid.UUID           `json:"user_id,omitempty" format:"uuid"`
+	Type      SessionRecordingType `json:"type" enums:"ssh,reconnecting_pty"`
+	StartedAt time.Time            `json:"started_at" format:"date-time"`
+	// EndedAt is unset while the session is in progress.
+	EndedAt *time.Time `json:"ended_at,omitempty" format:"date-time"`
+	// Size is the size of the recording in bytes.
+	Size int64 `json:"size"`
+}
+
+// WorkspaceSessionRecordings returns the session recordings of a workspace,
+// most recent first.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, workspaceID uuid.UUID) ([]SessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings", workspaceID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []SessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecording streams a session recording as an asciicast v2
+// file. The caller must close the returned reader.
+func (c *Client) WorkspaceSessionRecording(ctx context.Context, workspaceID, recordingID uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings/%s", workspaceID, recordingID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
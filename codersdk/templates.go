@@ -47,6 +47,11 @@ type Template struct {
 	FailureTTLMillis    int64 `json:"failure_ttl_ms"`
 	InactivityTTLMillis int64 `json:"inactivity_ttl_ms"`
 	LockedTTLMillis     int64 `json:"locked_ttl_ms"`
+
+	// IdleThresholdMillis is how long workspaces can go without user
+	// activity reported by their agents before they are stopped. Zero
+	// disables idle detection.
+	IdleThresholdMillis int64 `json:"idle_threshold_ms"`
 }
 
 type TransitionStats struct {
@@ -105,6 +110,7 @@ type UpdateTemplateMeta struct {
 	FailureTTLMillis             int64 `json:"failure_ttl_ms,omitempty"`
 	InactivityTTLMillis          int64 `json:"inactivity_ttl_ms,omitempty"`
 	LockedTTLMillis              int64 `json:"locked_ttl_ms,omitempty"`
+	IdleThresholdMillis          int64 `json:"idle_threshold_ms,omitempty"`
 }
 
 type TemplateExample struct {
@@ -0,0 +1,233 @@
+package codersdk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// WebhookSignatureHeader is the header webhook deliveries are signed in.
+	// The signature is "sha256=" followed by the hex encoded HMAC-SHA256 of
+	// the request body, keyed with the secret of the webhook.
+	WebhookSignatureHeader = "X-Coder-Webhook-Signature"
+	// WebhookEventHeader is the header with the event of a delivery.
+	WebhookEventHeader = "X-Coder-Webhook-Event"
+	// WebhookDeliveryHeader is the header with the ID of a delivery, it's
+	// the same when a delivery is retried.
+	WebhookDeliveryHeader = "X-Coder-Webhook-Delivery"
+)
+
+// WebhookEvent is an event webhooks can subscribe to.
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted   WebhookEvent = "workspace_build_started"
+	WebhookEventWorkspaceBuildSucceeded WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build_failed"
+	WebhookEventTemplateVersionPushed   WebhookEvent = "template_version_pushed"
+	WebhookEventUserCreated             WebhookEvent = "user_created"
+)
+
+// WebhookEvents are all events webhooks can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEventWorkspaceBuildStarted,
+	WebhookEventWorkspaceBuildSucceeded,
+	WebhookEventWorkspaceBuildFailed,
+	WebhookEventTemplateVersionPushed,
+	WebhookEventUserCreated,
+}
+
+// Webhook is an endpoint events are delivered to. The secret deliveries are
+// signed with is never returned.
+type Webhook struct {
+	ID        uuid.UUID      `json:"id" format:"uuid"`
+	Name      string         `json:"name"`
+	Endpoint  string         `json:"endpoint"`
+	Events    []WebhookEvent `json:"events"`
+	CreatedAt time.Time      `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time      `json:"updated_at" format:"date-time"`
+}
+
+type CreateWebhookRequest struct {
+	Name     string `json:"name" validate:"required,username"`
+	Endpoint string `json:"endpoint" validate:"required,url"`
+	// Secret is the key deliveries are signed with.
+	Secret string         `json:"secret" validate:"required"`
+	Events []WebhookEvent `json:"events" validate:"required,min=1"`
+}
+
+type UpdateWebhookRequest struct {
+	Name     string `json:"name" validate:"required,username"`
+	Endpoint string `json:"endpoint" validate:"required,url"`
+	// Secret replaces the key deliveries are signed with, the key is kept
+	// when empty.
+	Secret string         `json:"secret,omitempty"`
+	Events []WebhookEvent `json:"events" validate:"required,min=1"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an attempt to deliver an event to a webhook. Pending
+// deliveries are retried with backoff until they succeed or fail.
+type WebhookDelivery struct {
+	ID        uuid.UUID             `json:"id" format:"uuid"`
+	WebhookID uuid.UUID             `json:"webhook_id" format:"uuid"`
+	Event     WebhookEvent          `json:"event"`
+	Status    WebhookDeliveryStatus `json:"status" enums:"pending,succeeded,failed"`
+	Attempts  int32                 `json:"attempts"`
+	// StatusCode is the HTTP status code of the response to the last
+	// attempt, it's unset when no response was received.
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt  time.Time `json:"updated_at" format:"date-time"`
+}
+
+// WebhookPayload is the body of the requests webhook deliveries are sent in.
+// Data is one of WebhookWorkspaceBuildData, WebhookTemplateVersionData or
+// WebhookUserData, depending on the event.
+type WebhookPayload struct {
+	ID        uuid.UUID       `json:"id" format:"uuid"`
+	Event     WebhookEvent    `json:"event"`
+	CreatedAt time.Time       `json:"created_at" format:"date-time"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookWorkspaceBuildData is the data of workspace build events.
+type WebhookWorkspaceBuildData struct {
+	WorkspaceID        uuid.UUID           `json:"workspace_id" format:"uuid"`
+	WorkspaceName      string              `json:"workspace_name"`
+	WorkspaceOwnerID   uuid.UUID           `json:"workspace_owner_id" format:"uuid"`
+	WorkspaceOwnerName string              `json:"workspace_owner_name"`
+	TemplateID         uuid.UUID           `json:"template_id" format:"uuid"`
+	TemplateName       string              `json:"template_name"`
+	TemplateVersionID  uuid.UUID           `json:"template_version_id" format:"uuid"`
+	BuildID            uuid.UUID           `json:"build_id" format:"uuid"`
+	BuildNumber        int32               `json:"build_number"`
+	Transition         WorkspaceTransition `json:"transition"`
+	Reason             BuildReason         `json:"reason"`
+	InitiatorID        uuid.UUID           `json:"initiator_id" format:"uuid"`
+	// Error is set on failed builds.
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookTemplateVersionData is the data of template version events.
+type WebhookTemplateVersionData struct {
+	TemplateVersionID   uuid.UUID `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string    `json:"template_version_name"`
+	// TemplateID is unset for versions that were pushed to create a
+	// template.
+	TemplateID     *uuid.UUID `json:"template_id,omitempty" format:"uuid"`
+	TemplateName   string     `json:"template_name,omitempty"`
+	OrganizationID uuid.UUID  `json:"organization_id" format:"uuid"`
+	CreatedByID    uuid.UUID  `json:"created_by_id" format:"uuid"`
+	CreatedByName  string     `json:"created_by_name"`
+}
+
+// WebhookUserData is the data of user events.
+type WebhookUserData struct {
+	UserID   uuid.UUID `json:"user_id" format:"uuid"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+}
+
+// WebhookSignature returns the signature of a webhook request body. Receivers
+// should compare it to the WebhookSignatureHeader with hmac.Equal.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var webhooks []Webhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+func (c *Client) Webhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/webhooks/%s", id), req)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebhookDeliveries returns the deliveries of a webhook, newest first.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID, page Pagination) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id), nil, page.asRequestOption())
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var deliveries []WebhookDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
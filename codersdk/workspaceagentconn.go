@@ -1,6 +1,7 @@
 package codersdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
@@ -9,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -159,6 +161,20 @@ type WorkspaceAgentReconnectingPTYInit struct {
 	Height  uint16
 	Width   uint16
 	Command string
+	// UserID is the user a server connection opens the session on behalf
+	// of. Agents ignore it for clients coordinated by coderd.
+	UserID uuid.UUID
+}
+
+// ReconnectingPTYOption modifies the init message of a reconnecting PTY.
+type ReconnectingPTYOption func(*WorkspaceAgentReconnectingPTYInit)
+
+// WithReconnectingPTYUser sets the user a reconnecting PTY is opened on
+// behalf of.
+func WithReconnectingPTYUser(userID uuid.UUID) ReconnectingPTYOption {
+	return func(msg *WorkspaceAgentReconnectingPTYInit) {
+		msg.UserID = userID
+	}
 }
 
 // ReconnectingPTYRequest is sent from the client to the server
@@ -173,7 +189,7 @@ type ReconnectingPTYRequest struct {
 // ReconnectingPTY spawns a new reconnecting terminal session.
 // `ReconnectingPTYRequest` should be JSON marshaled and written to the returned net.Conn.
 // Raw terminal output will be read from the returned net.Conn.
-func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string) (net.Conn, error) {
+func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string, opts ...ReconnectingPTYOption) (net.Conn, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 	if !c.AwaitReachable(ctx) {
@@ -183,12 +199,16 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	data, err := json.Marshal(WorkspaceAgentReconnectingPTYInit{
+	msg := WorkspaceAgentReconnectingPTYInit{
 		ID:      id,
 		Height:  height,
 		Width:   width,
 		Command: command,
-	})
+	}
+	for _, opt := range opts {
+		opt(&msg)
+	}
+	data, err := json.Marshal(msg)
 	if err != nil {
 		_ = conn.Close()
 		return nil, err
@@ -305,6 +325,159 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// WorkspaceAgentFile describes a file or directory inside of a workspace.
+type WorkspaceAgentFile struct {
+	Name string `json:"name"`
+	// Path is the absolute path of the file.
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time" format:"date-time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+type WorkspaceAgentListFilesResponse struct {
+	// File is the file or directory that was listed.
+	File WorkspaceAgentFile `json:"file"`
+	// Files are the contents of the directory. It is empty if File is
+	// not a directory.
+	Files []WorkspaceAgentFile `json:"files"`
+}
+
+// ListFiles returns the file at path and, if it is a directory, the files
+// within it. Relative paths are resolved from the home directory of the
+// workspace user.
+func (c *WorkspaceAgentConn) ListFiles(ctx context.Context, path string) (WorkspaceAgentListFilesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesPath("/api/v0/files", path, nil), nil)
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ReadFile streams the contents of the file at path. The caller must
+// close the returned reader.
+func (c *WorkspaceAgentConn) ReadFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesPath("/api/v0/files/contents", path, nil), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WriteFile replaces the contents of the file at path with the contents
+// of r, creating parent directories as needed.
+func (c *WorkspaceAgentConn) WriteFile(ctx context.Context, path string, mode os.FileMode, r io.Reader) (WorkspaceAgentFile, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	query := url.Values{}
+	query.Set("mode", strconv.FormatUint(uint64(mode.Perm()), 8))
+	res, err := c.apiRequest(ctx, http.MethodPut, filesPath("/api/v0/files/contents", path, query), r)
+	if err != nil {
+		return WorkspaceAgentFile{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFile{}, ReadBodyAsError(res)
+	}
+
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
+
+// DownloadTar streams the file or directory at path as a tar archive.
+// Entries are named relative to path, or to the parent directory if path
+// is a file. The caller must close the returned reader.
+func (c *WorkspaceAgentConn) DownloadTar(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, filesPath("/api/v0/files/tar", path, nil), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// UploadTar extracts the tar archive read from r into the directory at
+// path, creating it if needed.
+func (c *WorkspaceAgentConn) UploadTar(ctx context.Context, path string, r io.Reader) (WorkspaceAgentFile, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPut, filesPath("/api/v0/files/tar", path, nil), r)
+	if err != nil {
+		return WorkspaceAgentFile{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFile{}, ReadBodyAsError(res)
+	}
+
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
+
+// KillProcess sends a signal to the process with the given PID.
+func (c *WorkspaceAgentConn) KillProcess(ctx context.Context, pid int32, req KillWorkspaceAgentProcessRequest) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	body, err := json.Marshal(req)
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, fmt.Sprintf("/api/v0/processes/%d/kill", pid), bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// ReportActivity tells the agent that the user is interacting with the
+// workspace in a way the agent can't observe, e.g. through an IDE
+// extension. It keeps workspaces with an idle threshold running.
+func (c *WorkspaceAgentConn) ReportActivity(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/activity", nil)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func filesPath(endpoint, path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("path", path)
+	return endpoint + "?" + query.Encode()
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/netip"
+	"os"
 	"strconv"
 	"time"
 
@@ -112,8 +113,11 @@ type WorkspaceAgentMetadataDescription struct {
 	DisplayName string `json:"display_name"`
 	Key         string `json:"key"`
 	Script      string `json:"script"`
-	Interval    int64  `json:"interval"`
-	Timeout     int64  `json:"timeout"`
+	// Collector is the name of a built-in collector the agent evaluates
+	// in-process instead of running Script, e.g. "cpu" or "disk:/home".
+	Collector string `json:"collector,omitempty"`
+	Interval  int64  `json:"interval"`
+	Timeout   int64  `json:"timeout"`
 }
 
 type WorkspaceAgentMetadata struct {
@@ -152,10 +156,56 @@ type WorkspaceAgent struct {
 	ConnectionTimeoutSeconds int32                 `json:"connection_timeout_seconds"`
 	TroubleshootingURL       string                `json:"troubleshooting_url"`
 	// Deprecated: Use StartupScriptBehavior instead.
-	LoginBeforeReady             bool           `json:"login_before_ready"`
-	ShutdownScript               string         `json:"shutdown_script,omitempty"`
-	ShutdownScriptTimeoutSeconds int32          `json:"shutdown_script_timeout_seconds"`
-	Subsystem                    AgentSubsystem `json:"subsystem"`
+	LoginBeforeReady             bool                      `json:"login_before_ready"`
+	ShutdownScript               string                    `json:"shutdown_script,omitempty"`
+	ShutdownScriptTimeoutSeconds int32                     `json:"shutdown_script_timeout_seconds"`
+	Subsystem                    AgentSubsystem            `json:"subsystem"`
+	Scripts                      []WorkspaceAgentScript    `json:"scripts"`
+	LogSources                   []WorkspaceAgentLogSource `json:"log_sources"`
+}
+
+// WorkspaceAgentLogSource identifies a stream of startup logs produced by an
+// agent, e.g. a single script.
+type WorkspaceAgentLogSource struct {
+	WorkspaceAgentID uuid.UUID `json:"workspace_agent_id" format:"uuid"`
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	CreatedAt        time.Time `json:"created_at" format:"date-time"`
+	DisplayName      string    `json:"display_name"`
+	Icon             string    `json:"icon"`
+}
+
+type WorkspaceAgentScriptStatus string
+
+const (
+	WorkspaceAgentScriptStatusPending   WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning   WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusSucceeded WorkspaceAgentScriptStatus = "succeeded"
+	WorkspaceAgentScriptStatusFailed    WorkspaceAgentScriptStatus = "failed"
+	WorkspaceAgentScriptStatusTimedOut  WorkspaceAgentScriptStatus = "timed_out"
+)
+
+// WorkspaceAgentScript is a script defined by a template that the agent
+// runs on start, on stop or on a cron schedule.
+type WorkspaceAgentScript struct {
+	LogSourceID      uuid.UUID                  `json:"log_source_id" format:"uuid"`
+	LogPath          string                     `json:"log_path"`
+	DisplayName      string                     `json:"display_name"`
+	Script           string                     `json:"script"`
+	Cron             string                     `json:"cron"`
+	RunOnStart       bool                       `json:"run_on_start"`
+	RunOnStop        bool                       `json:"run_on_stop"`
+	StartBlocksLogin bool                       `json:"start_blocks_login"`
+	Timeout          time.Duration              `json:"timeout"`
+	Status           WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode         int32                      `json:"exit_code"`
+	StartedAt        *time.Time                 `json:"started_at,omitempty" format:"date-time"`
+	EndedAt          *time.Time                 `json:"ended_at,omitempty" format:"date-time"`
+}
+
+// WorkspaceAgentScriptMetadataKey returns the agent metadata key that the
+// result of the most recent scheduled run of a cron script is reported under.
+func WorkspaceAgentScriptMetadataKey(logSourceID uuid.UUID) string {
+	return "coder_script_" + logSourceID.String()
 }
 
 type DERPRegion struct {
@@ -536,6 +586,219 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentListFiles returns the file at path inside the workspace
+// and, if it is a directory, the files within it.
+func (c *Client) WorkspaceAgentListFiles(ctx context.Context, agentID uuid.UUID, path string) (WorkspaceAgentListFilesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentReadFile streams the contents of the file at path inside
+// the workspace. The caller must close the returned reader.
+func (c *Client) WorkspaceAgentReadFile(ctx context.Context, agentID uuid.UUID, path string) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/contents", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WorkspaceAgentWriteFile replaces the contents of the file at path inside
+// the workspace with the contents of r.
+func (c *Client) WorkspaceAgentWriteFile(ctx context.Context, agentID uuid.UUID, path string, mode os.FileMode, r io.Reader) (WorkspaceAgentFile, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaceagents/%s/files/contents", agentID), r,
+		WithQueryParam("path", path),
+		WithQueryParam("mode", strconv.FormatUint(uint64(mode.Perm()), 8)),
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/octet-stream")
+		},
+	)
+	if err != nil {
+		return WorkspaceAgentFile{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFile{}, ReadBodyAsError(res)
+	}
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
+
+// WorkspaceAgentDownloadTar streams the file or directory at path inside
+// the workspace as a tar archive. The caller must close the returned reader.
+func (c *Client) WorkspaceAgentDownloadTar(ctx context.Context, agentID uuid.UUID, path string) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/tar", agentID), nil,
+		WithQueryParam("path", path),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WorkspaceAgentUploadTar extracts the tar archive read from r into the
+// directory at path inside the workspace, creating it if needed.
+func (c *Client) WorkspaceAgentUploadTar(ctx context.Context, agentID uuid.UUID, path string, r io.Reader) (WorkspaceAgentFile, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaceagents/%s/files/tar", agentID), r,
+		WithQueryParam("path", path),
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/x-tar")
+		},
+	)
+	if err != nil {
+		return WorkspaceAgentFile{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentFile{}, ReadBodyAsError(res)
+	}
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
+
+// WorkspaceAgentProcesses is a snapshot of the resource usage inside of a
+// workspace, periodically reported by the agent.
+type WorkspaceAgentProcesses struct {
+	CollectedAt time.Time `json:"collected_at" format:"date-time"`
+	// CPU and Memory are the totals of the container cgroup if the agent
+	// runs in a container, otherwise of the host.
+	CPU    WorkspaceAgentResourceUsage `json:"cpu"`
+	Memory WorkspaceAgentResourceUsage `json:"memory"`
+	// ProcessCount is the number of processes visible to the agent.
+	ProcessCount int                          `json:"process_count"`
+	Sessions     []WorkspaceAgentSessionUsage `json:"sessions"`
+	// Processes are the processes using the most CPU and memory, along
+	// with their ancestors so the process tree can be rebuilt from PPID.
+	Processes []WorkspaceAgentProcess `json:"processes"`
+}
+
+type WorkspaceAgentResourceUsage struct {
+	Used float64 `json:"used"`
+	// Total is unset if the resource is unlimited.
+	Total *float64 `json:"total,omitempty"`
+	Unit  string   `json:"unit"`
+}
+
+// WorkspaceAgentSessionUsage is the resource usage of the processes
+// started by an SSH session or a reconnecting PTY.
+type WorkspaceAgentSessionUsage struct {
+	// Type is one of "ssh", "vscode", "jetbrains" or "reconnecting_pty".
+	Type string `json:"type"`
+	// PID is the process the session was started with.
+	PID          int32     `json:"pid"`
+	StartedAt    time.Time `json:"started_at" format:"date-time"`
+	ProcessCount int       `json:"process_count"`
+	// CPUCores is the number of cores used since the previous report.
+	CPUCores    float64 `json:"cpu_cores"`
+	MemoryBytes int64   `json:"memory_bytes"`
+}
+
+type WorkspaceAgentProcess struct {
+	PID       int32     `json:"pid"`
+	PPID      int32     `json:"ppid"`
+	Name      string    `json:"name"`
+	Cmdline   string    `json:"cmdline"`
+	StartedAt time.Time `json:"started_at" format:"date-time"`
+	// CPUCores is the number of cores used since the previous report.
+	CPUCores    float64 `json:"cpu_cores"`
+	MemoryBytes int64   `json:"memory_bytes"`
+	// SessionPID is the PID of the session the process belongs to, or
+	// zero if it wasn't started by a session.
+	SessionPID int32 `json:"session_pid,omitempty"`
+}
+
+type WorkspaceAgentProcessSignal string
+
+const (
+	WorkspaceAgentProcessSignalTerminate WorkspaceAgentProcessSignal = "terminate"
+	WorkspaceAgentProcessSignalKill      WorkspaceAgentProcessSignal = "kill"
+)
+
+type KillWorkspaceAgentProcessRequest struct {
+	// Signal defaults to terminate.
+	Signal WorkspaceAgentProcessSignal `json:"signal,omitempty" enums:"terminate,kill"`
+}
+
+// WorkspaceAgentProcesses returns the latest process snapshot reported by
+// the agent.
+func (c *Client) WorkspaceAgentProcesses(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentProcesses, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/processes", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentProcesses{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentProcesses{}, ReadBodyAsError(res)
+	}
+	var processes WorkspaceAgentProcesses
+	return processes, json.NewDecoder(res.Body).Decode(&processes)
+}
+
+// WorkspaceAgentKillProcess sends a signal to a process inside of the
+// workspace.
+func (c *Client) WorkspaceAgentKillProcess(ctx context.Context, agentID uuid.UUID, pid int32, req KillWorkspaceAgentProcessRequest) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/processes/%d/kill", agentID, pid), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceAgentReportActivity reports that the user is interacting with
+// the workspace, e.g. from an IDE extension. Workspaces from templates with
+// an idle threshold are stopped when no activity is reported.
+func (c *Client) WorkspaceAgentReportActivity(ctx context.Context, agentID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/activity", agentID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceAgentStartupLogs returns the startup logs of an agent that exist
+// at the time of the request. Use WorkspaceAgentStartupLogsAfter to follow
+// an agent that is starting.
+func (c *Client) WorkspaceAgentStartupLogs(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentStartupLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/startup-logs", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []WorkspaceAgentStartupLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
 func (c *Client) WorkspaceAgentStartupLogsAfter(ctx context.Context, agentID uuid.UUID, after int64) (<-chan []WorkspaceAgentStartupLog, io.Closer, error) {
 	afterQuery := ""
 	if after != 0 {
@@ -626,6 +889,7 @@ type WorkspaceAgentStartupLog struct {
 	CreatedAt time.Time `json:"created_at" format:"date-time"`
 	Output    string    `json:"output"`
 	Level     LogLevel  `json:"level"`
+	SourceID  uuid.UUID `json:"source_id" format:"uuid"`
 }
 
 type AgentSubsystem string
@@ -130,6 +130,12 @@ func (c *Client) CancelWorkspaceBuild(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// WorkspaceBuildLogs returns the logs of a workspace build that exist at the
+// time of the request. Use WorkspaceBuildLogsAfter to follow a running build.
+func (c *Client) WorkspaceBuildLogs(ctx context.Context, build uuid.UUID) ([]ProvisionerJobLog, error) {
+	return c.provisionerJobLogs(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build))
+}
+
 // WorkspaceBuildLogsAfter streams logs for a workspace build that occurred after a specific log ID.
 func (c *Client) WorkspaceBuildLogsAfter(ctx context.Context, build uuid.UUID, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build), after)
@@ -14,15 +14,18 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":               {codersdk.AuditActionCreate},
+	"Template":                {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":          {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                   {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                  {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                 {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"Webhook":                 {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderApp":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderAppSecret": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -78,6 +81,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"failure_ttl":                      ActionTrack,
 		"inactivity_ttl":                   ActionTrack,
 		"locked_ttl":                       ActionTrack,
+		"idle_threshold":                   ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                 ActionTrack,
@@ -177,6 +181,31 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"deleted":             ActionIgnore,
 		"token_hashed_secret": ActionSecret,
 	},
+	&database.Webhook{}: {
+		"id":         ActionTrack,
+		"name":       ActionTrack,
+		"endpoint":   ActionTrack,
+		"secret":     ActionSecret,
+		"events":     ActionTrack,
+		"created_at": ActionTrack,
+		"updated_at": ActionIgnore,
+	},
+	&database.OAuth2ProviderApp{}: {
+		"id":           ActionTrack,
+		"created_at":   ActionIgnore, // Never changes.
+		"updated_at":   ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"name":         ActionTrack,
+		"icon":         ActionTrack,
+		"callback_url": ActionTrack,
+	},
+	&database.OAuth2ProviderAppSecret{}: {
+		"id":             ActionIgnore,
+		"created_at":     ActionIgnore,
+		"last_used_at":   ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"hashed_secret":  ActionSecret,
+		"display_secret": ActionTrack,
+		"app_id":         ActionTrack,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
@@ -0,0 +1,91 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	client := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{IncludeProvisionerDaemon: true},
+	})
+	admin := coderdtest.CreateFirstUser(t, client)
+	_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureTemplateRBAC: 1,
+		},
+	})
+	_, member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+	source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ProvisionComplete,
+	})
+	manifest := filepath.Join(t.TempDir(), "coder.yaml")
+	err := os.WriteFile(manifest, []byte(fmt.Sprintf(`groups:
+  - name: developers
+    quota_allowance: 10
+    members: [%s]
+templates:
+  - name: docker
+    directory: %s
+    acl:
+      groups:
+        developers: use
+      users:
+        %s: admin
+`, member.Username, source, member.Username)), 0o600)
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	inv, conf := newCLI(t, "apply", "-f", manifest, "-y", "--test.provisioner", string(database.ProvisionerTypeEcho))
+	clitest.SetupConfig(t, client, conf)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), `+ create group "developers"`)
+	require.Contains(t, stdout.String(), `+ create template "docker"`)
+
+	group, err := client.GroupByOrgAndName(ctx, admin.OrganizationID, "developers")
+	require.NoError(t, err)
+	require.Equal(t, 10, group.QuotaAllowance)
+	require.Len(t, group.Members, 1)
+	require.Equal(t, member.ID, group.Members[0].ID)
+
+	template, err := client.TemplateByName(ctx, admin.OrganizationID, "docker")
+	require.NoError(t, err)
+	acl, err := client.TemplateACL(ctx, template.ID)
+	require.NoError(t, err)
+	// The Everyone group isn't in the manifest, so it's removed.
+	require.Len(t, acl.Groups, 1)
+	require.Equal(t, "developers", acl.Groups[0].Name)
+	require.Equal(t, codersdk.TemplateRoleUse, acl.Groups[0].Role)
+	require.Len(t, acl.Users, 1)
+	require.Equal(t, codersdk.TemplateRoleAdmin, acl.Users[0].Role)
+
+	// Applying the manifest again doesn't change anything.
+	inv, conf = newCLI(t, "apply", "-f", manifest, "--dry-run")
+	clitest.SetupConfig(t, client, conf)
+	stdout.Reset()
+	inv.Stdout = &stdout
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), "No changes.")
+}
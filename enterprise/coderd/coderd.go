@@ -74,9 +74,10 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		OIDC:   options.OIDCConfig,
 	}
 	apiKeyMiddleware := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
-		DB:              options.Database,
-		OAuth2Configs:   oauthConfigs,
-		RedirectToLogin: false,
+		DB:               options.Database,
+		OAuth2Configs:    oauthConfigs,
+		RedirectToLogin:  false,
+		SessionTokenFunc: httpmw.APITokenOrBearerFromRequest,
 	})
 
 	deploymentID, err := options.Database.GetDeploymentID(ctx)
@@ -11,6 +11,7 @@ import (
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/db2sdk"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
@@ -419,8 +420,7 @@ func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, convertRole(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, db2sdk.RoleFromName(roleName))
 	}
 
 	return convertedUser
@@ -434,10 +434,3 @@ func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][
 	}
 	return converted
 }
-
-func convertRole(role rbac.Role) codersdk.Role {
-	return codersdk.Role{
-		DisplayName: role.DisplayName,
-		Name:        role.Name,
-	}
-}
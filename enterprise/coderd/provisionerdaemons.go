@@ -228,6 +228,8 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		Provisioners:          daemon.Provisioners,
 		Telemetry:             api.Telemetry,
 		Auditor:               &api.AGPL.Auditor,
+		NotificationsEnqueuer: api.NotificationsEnqueuer,
+		WebhooksEnqueuer:      api.WebhooksEnqueuer,
 		TemplateScheduleStore: api.AGPL.TemplateScheduleStore,
 		Logger:                api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
 		Tags:                  rawTags,
@@ -4,6 +4,7 @@ import (
 	"crypto/subtle"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -195,6 +196,8 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	api.AGPL.WebhookUserCreated(ctx, user)
+
 	sUser.ID = user.ID.String()
 	sUser.UserName = user.Username
 
@@ -250,7 +253,7 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
+	updatedUser, err := api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
 		ID:        dbUser.ID,
 		Status:    status,
 		UpdatedAt: database.Now(),
@@ -259,6 +262,11 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	if status == database.UserStatusSuspended && dbUser.Status != database.UserStatusSuspended {
+		api.AGPL.NotifyUser(ctx, updatedUser, codersdk.NotificationTemplateUserSuspended, map[string]string{
+			"suspended_at": updatedUser.UpdatedAt.Format(time.RFC3339),
+		})
+	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/coderd/autobuild"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/notifications"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/coderd"
@@ -228,6 +229,123 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		// Expect no transitions since the field is unset on the template.
 		require.Len(t, stats.Transitions, 0)
 	})
+
+	t.Run("InactivityTTLNotification", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ticker        = make(chan time.Time)
+			statCh        = make(chan autobuild.Stats)
+			enqueuer      = notifications.NewMock()
+			inactivityTTL = 7 * 24 * time.Hour
+
+			client = coderdenttest.New(t, &coderdenttest.Options{
+				Options: &coderdtest.Options{
+					AutobuildTicker:          ticker,
+					IncludeProvisionerDaemon: true,
+					AutobuildStats:           statCh,
+					TemplateScheduleStore:    &coderd.EnterpriseTemplateScheduleStore{},
+					NotificationsEnqueuer:    enqueuer,
+				},
+			})
+		)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.InactivityTTLMillis = ptr.Ref[int64](inactivityTTL.Milliseconds())
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		ws := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, ws.LatestBuild.ID)
+		stopBuild := coderdtest.CreateWorkspaceBuild(t, client, ws, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, stopBuild.ID)
+
+		ticker <- time.Now()
+		stats := <-statCh
+		require.Len(t, stats.Transitions, 0)
+
+		// Expect the owner to be notified that the stopped workspace will be
+		// deleted.
+		sent := markedForDeletion(enqueuer)
+		require.Len(t, sent, 1)
+		require.Equal(t, user.UserID, sent[0].UserID)
+		require.Equal(t, ws.Name, sent[0].Labels["workspace"])
+		require.Equal(t, ws.ID.String(), sent[0].Labels["workspace_id"])
+		require.NotEmpty(t, sent[0].Labels["deleting_at"])
+	})
+
+	t.Run("LockedTTLNotification", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ticker        = make(chan time.Time)
+			statCh        = make(chan autobuild.Stats)
+			enqueuer      = notifications.NewMock()
+			inactivityTTL = time.Minute
+			lockedTTL     = 7 * 24 * time.Hour
+
+			client = coderdenttest.New(t, &coderdenttest.Options{
+				Options: &coderdtest.Options{
+					AutobuildTicker:          ticker,
+					IncludeProvisionerDaemon: true,
+					AutobuildStats:           statCh,
+					TemplateScheduleStore:    &coderd.EnterpriseTemplateScheduleStore{},
+					NotificationsEnqueuer:    enqueuer,
+				},
+			})
+		)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAdvancedTemplateScheduling: 1,
+			},
+		})
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			InactivityTTLMillis: inactivityTTL.Milliseconds(),
+			LockedTTLMillis:     lockedTTL.Milliseconds(),
+		})
+		require.NoError(t, err)
+
+		ws := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, ws.LatestBuild.ID)
+		stopBuild := coderdtest.CreateWorkspaceBuild(t, client, ws, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, stopBuild.ID)
+		ws = coderdtest.MustWorkspace(t, client, ws.ID)
+
+		// The workspace has been inactive for longer than the inactivity
+		// TTL, so it's locked until the locked TTL deletes it.
+		ticker <- ws.LastUsedAt.Add(2 * inactivityTTL)
+		stats := <-statCh
+		require.Len(t, stats.Transitions, 0)
+
+		sent := markedForDeletion(enqueuer)
+		require.Len(t, sent, 1)
+		require.Equal(t, ws.ID.String(), sent[0].Labels["workspace_id"])
+		require.Equal(t, ws.LastUsedAt.Add(inactivityTTL+lockedTTL).UTC().Format(time.RFC1123), sent[0].Labels["deleting_at"])
+	})
+}
+
+// markedForDeletion returns the workspace marked for deletion notifications
+// recorded by the enqueuer.
+func markedForDeletion(enqueuer *notifications.MockEnqueuer) []notifications.MockNotification {
+	var sent []notifications.MockNotification
+	for _, n := range enqueuer.Notifications() {
+		if n.Template == codersdk.NotificationTemplateWorkspaceMarkedForDeletion {
+			sent = append(sent, n)
+		}
+	}
+	return sent
 }
 
 func TestWorkspacesFiltering(t *testing.T) {
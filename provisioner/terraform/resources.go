@@ -23,6 +23,7 @@ type agentMetadata struct {
 	Key         string `mapstructure:"key"`
 	DisplayName string `mapstructure:"display_name"`
 	Script      string `mapstructure:"script"`
+	Collector   string `mapstructure:"collector"`
 	Interval    int64  `mapstructure:"interval"`
 	Timeout     int64  `mapstructure:"timeout"`
 }
@@ -67,6 +68,20 @@ type agentAppAttributes struct {
 	Healthcheck []appHealthcheckAttributes `mapstructure:"healthcheck"`
 }
 
+// A mapping of attributes on the "coder_script" resource.
+type agentScriptAttributes struct {
+	AgentID          string `mapstructure:"agent_id"`
+	DisplayName      string `mapstructure:"display_name"`
+	Icon             string `mapstructure:"icon"`
+	Script           string `mapstructure:"script"`
+	Cron             string `mapstructure:"cron"`
+	LogPath          string `mapstructure:"log_path"`
+	StartBlocksLogin bool   `mapstructure:"start_blocks_login"`
+	RunOnStart       bool   `mapstructure:"run_on_start"`
+	RunOnStop        bool   `mapstructure:"run_on_stop"`
+	TimeoutSeconds   int32  `mapstructure:"timeout"`
+}
+
 // A mapping of attributes on the "healthcheck" resource.
 type appHealthcheckAttributes struct {
 	URL       string `mapstructure:"url"`
@@ -178,6 +193,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string, rawParameterNa
 					Key:         item.Key,
 					DisplayName: item.DisplayName,
 					Script:      item.Script,
+					Collector:   item.Collector,
 					Interval:    item.Interval,
 					Timeout:     item.Timeout,
 				})
@@ -380,6 +396,47 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string, rawParameterNa
 		}
 	}
 
+	// Associate scripts with agents.
+	for _, resources := range tfResourcesByLabel {
+		for _, resource := range resources {
+			if resource.Type != "coder_script" {
+				continue
+			}
+
+			var attrs agentScriptAttributes
+			err = mapstructure.Decode(resource.AttributeValues, &attrs)
+			if err != nil {
+				return nil, xerrors.Errorf("decode script attributes: %w", err)
+			}
+			if attrs.DisplayName == "" {
+				attrs.DisplayName = resource.Name
+			}
+			if !attrs.RunOnStart && !attrs.RunOnStop && attrs.Cron == "" {
+				return nil, xerrors.Errorf("script %q must set at least one of run_on_start, run_on_stop or cron", resource.Address)
+			}
+
+			for _, agents := range resourceAgents {
+				for _, agent := range agents {
+					// Find agents with the matching ID and associate them!
+					if agent.Id != attrs.AgentID {
+						continue
+					}
+					agent.Scripts = append(agent.Scripts, &proto.Script{
+						DisplayName:      attrs.DisplayName,
+						Icon:             attrs.Icon,
+						Script:           attrs.Script,
+						Cron:             attrs.Cron,
+						LogPath:          attrs.LogPath,
+						StartBlocksLogin: attrs.StartBlocksLogin,
+						RunOnStart:       attrs.RunOnStart,
+						RunOnStop:        attrs.RunOnStop,
+						TimeoutSeconds:   attrs.TimeoutSeconds,
+					})
+				}
+			}
+		}
+	}
+
 	// Associate metadata blocks with resources.
 	resourceMetadata := map[string][]*proto.Resource_Metadata{}
 	resourceHidden := map[string]bool{}
@@ -454,7 +511,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string, rawParameterNa
 			if resource.Mode == tfjson.DataResourceMode {
 				continue
 			}
-			if resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" {
+			if resource.Type == "coder_agent" || resource.Type == "coder_agent_instance" || resource.Type == "coder_app" || resource.Type == "coder_metadata" || resource.Type == "coder_script" {
 				continue
 			}
 			label := convertAddressToLabel(resource.Address)
@@ -796,6 +796,85 @@ func TestInstanceIDAssociation(t *testing.T) {
 	}
 }
 
+func TestAgentScriptAssociation(t *testing.T) {
+	t.Parallel()
+	state, err := terraform.ConvertState([]*tfjson.StateModule{{
+		Resources: []*tfjson.StateResource{{
+			Address: "coder_agent.dev",
+			Type:    "coder_agent",
+			Name:    "dev",
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"id":   "agent-id",
+				"arch": "amd64",
+				"auth": "token",
+			},
+		}, {
+			Address:   "null_resource.dev",
+			Type:      "null_resource",
+			Name:      "dev",
+			Mode:      tfjson.ManagedResourceMode,
+			DependsOn: []string{"coder_agent.dev"},
+		}, {
+			Address:   "coder_script.dotfiles",
+			Type:      "coder_script",
+			Name:      "dotfiles",
+			Mode:      tfjson.ManagedResourceMode,
+			DependsOn: []string{"coder_agent.dev"},
+			AttributeValues: map[string]interface{}{
+				"agent_id":           "agent-id",
+				"display_name":       "Dotfiles",
+				"script":             "coder dotfiles -y",
+				"run_on_start":       true,
+				"start_blocks_login": true,
+				"timeout":            60,
+			},
+		}, {
+			Address:   "coder_script.cleanup",
+			Type:      "coder_script",
+			Name:      "cleanup",
+			Mode:      tfjson.ManagedResourceMode,
+			DependsOn: []string{"coder_agent.dev"},
+			AttributeValues: map[string]interface{}{
+				"agent_id":    "agent-id",
+				"script":      "rm -rf /tmp/cache",
+				"run_on_stop": true,
+			},
+		}},
+		// This is manually created to join the edges.
+	}}, `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] coder_agent.dev" [label = "coder_agent.dev", shape = "box"]
+		"[root] coder_script.cleanup" [label = "coder_script.cleanup", shape = "box"]
+		"[root] coder_script.dotfiles" [label = "coder_script.dotfiles", shape = "box"]
+		"[root] null_resource.dev" [label = "null_resource.dev", shape = "box"]
+		"[root] coder_script.cleanup" -> "[root] coder_agent.dev"
+		"[root] coder_script.dotfiles" -> "[root] coder_agent.dev"
+		"[root] null_resource.dev" -> "[root] coder_agent.dev"
+	}
+}
+`, nil)
+	require.NoError(t, err)
+	require.Len(t, state.Resources, 1)
+	require.Len(t, state.Resources[0].Agents, 1)
+	scripts := state.Resources[0].Agents[0].Scripts
+	require.Len(t, scripts, 2)
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].DisplayName < scripts[j].DisplayName
+	})
+	require.Equal(t, "Dotfiles", scripts[0].DisplayName)
+	require.Equal(t, "coder dotfiles -y", scripts[0].Script)
+	require.True(t, scripts[0].RunOnStart)
+	require.True(t, scripts[0].StartBlocksLogin)
+	require.EqualValues(t, 60, scripts[0].TimeoutSeconds)
+	// The display name defaults to the resource name.
+	require.Equal(t, "cleanup", scripts[1].DisplayName)
+	require.True(t, scripts[1].RunOnStop)
+	require.False(t, scripts[1].RunOnStart)
+}
+
 // sortResource ensures resources appear in a consistent ordering
 // to prevent tests from flaking.
 func sortResources(resources []*proto.Resource) {
@@ -69,6 +69,9 @@ type Process interface {
 
 	// Kill the command process.  Returned error is as for os.Process.Kill()
 	Kill() error
+
+	// PID is the process ID of the command.
+	PID() int
 }
 
 // WithFlags represents a PTY whose flags can be inspected, in particular
@@ -216,6 +216,10 @@ func (p *windowsProcess) Kill() error {
 	return p.proc.Kill()
 }
 
+func (p *windowsProcess) PID() int {
+	return p.proc.Pid
+}
+
 // killOnContext waits for the context to be done and kills the process, unless it exits on its own first.
 func (p *windowsProcess) killOnContext(ctx context.Context) {
 	select {
@@ -117,6 +117,7 @@ export interface Agent {
   shutdownScriptTimeoutSeconds: number
   metadata: Agent_Metadata[]
   startupScriptBehavior: string
+  scripts: Script[]
 }
 
 export interface Agent_Metadata {
@@ -125,6 +126,7 @@ export interface Agent_Metadata {
   script: string
   interval: number
   timeout: number
+  collector: string
 }
 
 export interface Agent_EnvEntry {
@@ -132,6 +134,19 @@ export interface Agent_EnvEntry {
   value: string
 }
 
+/** Script represents a script to be run on the workspace. */
+export interface Script {
+  displayName: string
+  icon: string
+  script: string
+  cron: string
+  startBlocksLogin: boolean
+  runOnStart: boolean
+  runOnStop: boolean
+  timeoutSeconds: number
+  logPath: string
+}
+
 /** App represents a dev-accessible application on the workspace. */
 export interface App {
   /**
@@ -495,6 +510,9 @@ export const Agent = {
     if (message.startupScriptBehavior !== "") {
       writer.uint32(154).string(message.startupScriptBehavior)
     }
+    for (const v of message.scripts) {
+      Script.encode(v!, writer.uint32(162).fork()).ldelim()
+    }
     return writer
   },
 }
@@ -519,6 +537,9 @@ export const Agent_Metadata = {
     if (message.timeout !== 0) {
       writer.uint32(40).int64(message.timeout)
     }
+    if (message.collector !== "") {
+      writer.uint32(50).string(message.collector)
+    }
     return writer
   },
 }
@@ -538,6 +559,42 @@ export const Agent_EnvEntry = {
   },
 }
 
+export const Script = {
+  encode(
+    message: Script,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.displayName !== "") {
+      writer.uint32(10).string(message.displayName)
+    }
+    if (message.icon !== "") {
+      writer.uint32(18).string(message.icon)
+    }
+    if (message.script !== "") {
+      writer.uint32(26).string(message.script)
+    }
+    if (message.cron !== "") {
+      writer.uint32(34).string(message.cron)
+    }
+    if (message.startBlocksLogin === true) {
+      writer.uint32(40).bool(message.startBlocksLogin)
+    }
+    if (message.runOnStart === true) {
+      writer.uint32(48).bool(message.runOnStart)
+    }
+    if (message.runOnStop === true) {
+      writer.uint32(56).bool(message.runOnStop)
+    }
+    if (message.timeoutSeconds !== 0) {
+      writer.uint32(64).int32(message.timeoutSeconds)
+    }
+    if (message.logPath !== "") {
+      writer.uint32(74).string(message.logPath)
+    }
+    return writer
+  },
+}
+
 export const App = {
   encode(message: App, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.slug !== "") {
@@ -50,6 +50,11 @@ var (
 	errorHTML string
 
 	errorTemplate *htmltemplate.Template
+
+	//go:embed static/oauth2allow.html
+	oauthHTML string
+
+	oauthTemplate *htmltemplate.Template
 )
 
 func init() {
@@ -58,6 +63,11 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	oauthTemplate, err = htmltemplate.New("oauth2allow").Parse(oauthHTML)
+	if err != nil {
+		panic(err)
+	}
 }
 
 type Options struct {
@@ -81,7 +91,7 @@ func New(opts *Options) *Handler {
 		panic(fmt.Sprintf("Failed to parse html files: %v", err))
 	}
 
-	binHashCache := newBinHashCache(opts.BinFS, opts.BinHashes)
+	handler.binHashCache = newBinHashCache(opts.BinFS, opts.BinHashes)
 
 	mux := http.NewServeMux()
 	mux.Handle("/bin/", http.StripPrefix("/bin", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -103,7 +113,7 @@ func New(opts *Options) *Handler {
 			http.NotFound(rw, r)
 			return
 		}
-		hash, err := binHashCache.getHash(name)
+		hash, err := handler.binHashCache.getHash(name)
 		if xerrors.Is(err, os.ErrNotExist) {
 			http.NotFound(rw, r)
 			return
@@ -144,6 +154,7 @@ type Handler struct {
 	htmlTemplates *template.Template
 
 	buildInfoJSON string
+	binHashCache  *binHashCache
 
 	AppearanceFetcher func(ctx context.Context) (codersdk.AppearanceConfig, error)
 	RegionsFetcher    func(ctx context.Context) (codersdk.RegionsResponse, error)
@@ -152,6 +163,16 @@ type Handler struct {
 	Experiments  atomic.Pointer[codersdk.Experiments]
 }
 
+// BinaryHash returns the SHA1 checksum of the binary with the given name
+// served under /bin. It returns an error wrapping os.ErrNotExist if there is
+// no such binary.
+func (h *Handler) BinaryHash(name string) (string, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return "", os.ErrNotExist
+	}
+	return h.binHashCache.getHash(name)
+}
+
 func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	err := h.secureHeaders.Process(rw, r)
 	if err != nil {
@@ -751,6 +772,36 @@ func RenderStaticErrorPage(rw http.ResponseWriter, r *http.Request, data ErrorPa
 	}
 }
 
+// RenderOAuthAllowData contains the variables that are found in
+// site/static/oauth2allow.html.
+type RenderOAuthAllowData struct {
+	AppIcon  string
+	AppName  string
+	Username string
+	// FullAccess is whether the app asks for full access to the account of
+	// the user, instead of only connecting to workspace applications.
+	FullAccess bool
+	// CancelURI is where the user is sent to if they don't allow the app.
+	CancelURI string
+}
+
+// RenderOAuthAllowPage renders the static page asking the user whether they
+// allow an OAuth2 app to act on their behalf. Allowing submits a form to the
+// same URL.
+func RenderOAuthAllowPage(rw http.ResponseWriter, r *http.Request, data RenderOAuthAllowData) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// Framing the page could trick users into allowing the app.
+	rw.Header().Set("X-Frame-Options", "DENY")
+
+	err := oauthTemplate.Execute(rw, data)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to render oauth page: " + err.Error(),
+		})
+		return
+	}
+}
+
 type binHashCache struct {
 	binFS http.FileSystem
 
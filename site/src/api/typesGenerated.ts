@@ -34,6 +34,7 @@ export interface AgentStatsReportResponse {
 // From codersdk/deployment.go
 export interface AppHostResponse {
   readonly host: string
+  readonly disable_path_apps: boolean
 }
 
 // From codersdk/deployment.go
@@ -139,6 +140,15 @@ export interface BuildInfoResponse {
   readonly workspace_proxy: boolean
 }
 
+// From codersdk/roles.go
+export interface CreateCustomRoleRequest {
+  readonly name: string
+  readonly display_name?: string
+  readonly site_permissions?: Permission[]
+  readonly organization_permissions?: Permission[]
+  readonly user_permissions?: Permission[]
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string
@@ -160,6 +170,13 @@ export interface CreateGroupRequest {
   readonly quota_allowance: number
 }
 
+// From codersdk/oauth2.go
+export interface CreateOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+}
+
 // From codersdk/users.go
 export interface CreateOrganizationRequest {
   readonly name: string
@@ -180,6 +197,7 @@ export interface CreateTemplateRequest {
   readonly failure_ttl_ms?: number
   readonly inactivity_ttl_ms?: number
   readonly locked_ttl_ms?: number
+  readonly idle_threshold_ms?: number
   readonly disable_everyone_group_access: boolean
 }
 
@@ -229,6 +247,14 @@ export interface CreateUserRequest {
   readonly organization_id: string
 }
 
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+  readonly name: string
+  readonly endpoint: string
+  readonly secret: string
+  readonly events: WebhookEvent[]
+}
+
 // From codersdk/workspaces.go
 export interface CreateWorkspaceBuildRequest {
   readonly template_version_id?: string
@@ -256,6 +282,19 @@ export interface CreateWorkspaceRequest {
   readonly rich_parameter_values?: WorkspaceBuildParameter[]
 }
 
+// From codersdk/roles.go
+export interface CustomRole {
+  readonly id: string
+  readonly name: string
+  readonly display_name: string
+  readonly organization_id?: string
+  readonly site_permissions: Permission[]
+  readonly organization_permissions: Permission[]
+  readonly user_permissions: Permission[]
+  readonly created_at: string
+  readonly updated_at: string
+}
+
 // From codersdk/deployment.go
 export interface DAUEntry {
   readonly date: string
@@ -373,7 +412,9 @@ export interface DeploymentValues {
   readonly config_ssh?: SSHConfig
   readonly wgtunnel_host?: string
   readonly disable_owner_workspace_exec?: boolean
+  readonly session_recording?: boolean
   readonly proxy_health_status_interval?: number
+  readonly notifications?: NotificationsConfig
   // This is likely an enum in an external package ("github.com/coder/coder/cli/clibase.YAMLConfigPath")
   readonly config?: string
   readonly write_config?: boolean
@@ -463,6 +504,11 @@ export interface IssueReconnectingPTYSignedTokenResponse {
   readonly signed_token: string
 }
 
+// From codersdk/workspaceagents.go
+export interface KillWorkspaceAgentProcessRequest {
+  readonly signal?: WorkspaceAgentProcessSignal
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number
@@ -498,11 +544,50 @@ export interface LoginWithPasswordResponse {
   readonly session_token: string
 }
 
+// From codersdk/notifications.go
+export interface NotificationPreference {
+  readonly template: NotificationTemplate
+  readonly disabled: boolean
+}
+
+// From codersdk/deployment.go
+export interface NotificationsConfig {
+  readonly max_send_attempts: number
+  readonly email: NotificationsEmailConfig
+  readonly webhook: NotificationsWebhookConfig
+}
+
+// From codersdk/deployment.go
+export interface NotificationsEmailConfig {
+  readonly from: string
+  readonly smarthost: string
+  readonly hello: string
+  readonly username: string
+  readonly password: string
+}
+
+// From codersdk/deployment.go
+export interface NotificationsWebhookConfig {
+  readonly endpoint: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2AppEndpoints {
+  readonly authorization: string
+  readonly token: string
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2Error {
+  readonly error: string
+  readonly error_description?: string
+}
+
 // From codersdk/deployment.go
 export interface OAuth2GithubConfig {
   readonly client_id: string
@@ -516,6 +601,42 @@ export interface OAuth2GithubConfig {
   readonly enterprise_base_url: string
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2ProviderApp {
+  readonly id: string
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+  readonly endpoints: OAuth2AppEndpoints
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppFilter {
+  readonly user_id?: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecret {
+  readonly id: string
+  readonly created_at: string
+  readonly last_used_at?: string
+  readonly client_secret_truncated: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecretFull {
+  readonly id: string
+  readonly client_secret_full: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2TokenResponse {
+  readonly access_token: string
+  readonly token_type: string
+  readonly refresh_token: string
+  readonly expires_in: number
+}
+
 // From codersdk/users.go
 export interface OIDCAuthMethod extends AuthMethod {
   readonly signInText: string
@@ -543,6 +664,10 @@ export interface OIDCConfig {
   // Named type "github.com/coder/coder/cli/clibase.Struct[map[string]string]" unknown, using "any"
   // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
   readonly group_mapping: any
+  readonly user_role_field: string
+  // Named type "github.com/coder/coder/cli/clibase.Struct[map[string][]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly user_role_mapping: any
   readonly sign_in_text: string
   readonly icon_url: string
 }
@@ -594,6 +719,13 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean
 }
 
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean
+  readonly resource_type: RBACResource
+  readonly action: string
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean
@@ -751,6 +883,19 @@ export interface SessionCountDeploymentStats {
   readonly reconnecting_pty: number
 }
 
+// From codersdk/sessionrecordings.go
+export interface SessionRecording {
+  readonly id: string
+  readonly workspace_id: string
+  readonly workspace_build_id: string
+  readonly agent_id: string
+  readonly user_id?: string
+  readonly type: SessionRecordingType
+  readonly started_at: string
+  readonly ended_at?: string
+  readonly size: number
+}
+
 // From codersdk/deployment.go
 export interface SupportConfig {
   // Named type "github.com/coder/coder/cli/clibase.Struct[[]github.com/coder/coder/codersdk.LinkConfig]" unknown, using "any"
@@ -812,6 +957,7 @@ export interface Template {
   readonly failure_ttl_ms: number
   readonly inactivity_ttl_ms: number
   readonly locked_ttl_ms: number
+  readonly idle_threshold_ms: number
 }
 
 // From codersdk/templates.go
@@ -955,6 +1101,26 @@ export interface UpdateCheckResponse {
   readonly url: string
 }
 
+// From codersdk/roles.go
+export interface UpdateCustomRoleRequest {
+  readonly display_name?: string
+  readonly site_permissions?: Permission[]
+  readonly organization_permissions?: Permission[]
+  readonly user_permissions?: Permission[]
+}
+
+// From codersdk/notifications.go
+export interface UpdateNotificationPreferencesRequest {
+  readonly preferences: NotificationPreference[]
+}
+
+// From codersdk/oauth2.go
+export interface UpdateOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[]
@@ -980,6 +1146,7 @@ export interface UpdateTemplateMeta {
   readonly failure_ttl_ms?: number
   readonly inactivity_ttl_ms?: number
   readonly locked_ttl_ms?: number
+  readonly idle_threshold_ms?: number
 }
 
 // From codersdk/users.go
@@ -993,6 +1160,14 @@ export interface UpdateUserProfileRequest {
   readonly username: string
 }
 
+// From codersdk/webhooks.go
+export interface UpdateWebhookRequest {
+  readonly name: string
+  readonly endpoint: string
+  readonly secret?: string
+  readonly events: WebhookEvent[]
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
@@ -1055,6 +1230,72 @@ export interface VariableValue {
   readonly value: string
 }
 
+// From codersdk/webhooks.go
+export interface Webhook {
+  readonly id: string
+  readonly name: string
+  readonly endpoint: string
+  readonly events: WebhookEvent[]
+  readonly created_at: string
+  readonly updated_at: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDelivery {
+  readonly id: string
+  readonly webhook_id: string
+  readonly event: WebhookEvent
+  readonly status: WebhookDeliveryStatus
+  readonly attempts: number
+  readonly status_code?: number
+  readonly error?: string
+  readonly created_at: string
+  readonly updated_at: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayload {
+  readonly id: string
+  readonly event: WebhookEvent
+  readonly created_at: string
+  readonly data: Record<string, string>
+}
+
+// From codersdk/webhooks.go
+export interface WebhookTemplateVersionData {
+  readonly template_version_id: string
+  readonly template_version_name: string
+  readonly template_id?: string
+  readonly template_name?: string
+  readonly organization_id: string
+  readonly created_by_id: string
+  readonly created_by_name: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookUserData {
+  readonly user_id: string
+  readonly username: string
+  readonly email: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceBuildData {
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly workspace_owner_id: string
+  readonly workspace_owner_name: string
+  readonly template_id: string
+  readonly template_name: string
+  readonly template_version_id: string
+  readonly build_id: string
+  readonly build_number: number
+  readonly transition: WorkspaceTransition
+  readonly reason: BuildReason
+  readonly initiator_id: string
+  readonly error?: string
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string
@@ -1111,6 +1352,24 @@ export interface WorkspaceAgent {
   readonly shutdown_script?: string
   readonly shutdown_script_timeout_seconds: number
   readonly subsystem: AgentSubsystem
+  readonly scripts: WorkspaceAgentScript[]
+  readonly log_sources: WorkspaceAgentLogSource[]
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentFile {
+  readonly name: string
+  readonly path: string
+  readonly size: number
+  readonly mode: string
+  readonly mod_time: string
+  readonly is_dir: boolean
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentListFilesResponse {
+  readonly file: WorkspaceAgentFile
+  readonly files: WorkspaceAgentFile[]
 }
 
 // From codersdk/workspaceagentconn.go
@@ -1125,6 +1384,15 @@ export interface WorkspaceAgentListeningPortsResponse {
   readonly ports: WorkspaceAgentListeningPort[]
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentLogSource {
+  readonly workspace_agent_id: string
+  readonly id: string
+  readonly created_at: string
+  readonly display_name: string
+  readonly icon: string
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentMetadata {
   readonly result: WorkspaceAgentMetadataResult
@@ -1136,6 +1404,7 @@ export interface WorkspaceAgentMetadataDescription {
   readonly display_name: string
   readonly key: string
   readonly script: string
+  readonly collector?: string
   readonly interval: number
   readonly timeout: number
 }
@@ -1148,12 +1417,69 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentProcess {
+  readonly pid: number
+  readonly ppid: number
+  readonly name: string
+  readonly cmdline: string
+  readonly started_at: string
+  readonly cpu_cores: number
+  readonly memory_bytes: number
+  readonly session_pid?: number
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentProcesses {
+  readonly collected_at: string
+  readonly cpu: WorkspaceAgentResourceUsage
+  readonly memory: WorkspaceAgentResourceUsage
+  readonly process_count: number
+  readonly sessions: WorkspaceAgentSessionUsage[]
+  readonly processes: WorkspaceAgentProcess[]
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentResourceUsage {
+  readonly used: number
+  readonly total?: number
+  readonly unit: string
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentScript {
+  readonly log_source_id: string
+  readonly log_path: string
+  readonly display_name: string
+  readonly script: string
+  readonly cron: string
+  readonly run_on_start: boolean
+  readonly run_on_stop: boolean
+  readonly start_blocks_login: boolean
+  readonly timeout: number
+  readonly status: WorkspaceAgentScriptStatus
+  readonly exit_code: number
+  readonly started_at?: string
+  readonly ended_at?: string
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentSessionUsage {
+  readonly type: string
+  readonly pid: number
+  readonly started_at: string
+  readonly process_count: number
+  readonly cpu_cores: number
+  readonly memory_bytes: number
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentStartupLog {
   readonly id: number
   readonly created_at: string
   readonly output: string
   readonly level: LogLevel
+  readonly source_id: string
 }
 
 // From codersdk/workspaceapps.go
@@ -1411,15 +1737,37 @@ export type LogSource = "provisioner" | "provisioner_daemon"
 export const LogSources: LogSource[] = ["provisioner", "provisioner_daemon"]
 
 // From codersdk/apikey.go
-export type LoginType = "github" | "none" | "oidc" | "password" | "token"
+export type LoginType =
+  | "github"
+  | "none"
+  | "oauth2_provider_app"
+  | "oidc"
+  | "password"
+  | "token"
 export const LoginTypes: LoginType[] = [
   "github",
   "none",
+  "oauth2_provider_app",
   "oidc",
   "password",
   "token",
 ]
 
+// From codersdk/notifications.go
+export type NotificationTemplate =
+  | "user_created"
+  | "user_suspended"
+  | "workspace_autostopped"
+  | "workspace_build_failed"
+  | "workspace_marked_for_deletion"
+export const NotificationTemplates: NotificationTemplate[] = [
+  "user_created",
+  "user_suspended",
+  "workspace_autostopped",
+  "workspace_build_failed",
+  "workspace_marked_for_deletion",
+]
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1469,12 +1817,15 @@ export type RBACResource =
   | "assign_org_role"
   | "assign_role"
   | "audit_log"
+  | "custom_role"
   | "debug_info"
   | "deployment_config"
   | "deployment_stats"
   | "file"
   | "group"
   | "license"
+  | "oauth2_app"
+  | "oauth2_app_secret"
   | "organization"
   | "organization_member"
   | "provisioner_daemon"
@@ -1483,6 +1834,7 @@ export type RBACResource =
   | "template"
   | "user"
   | "user_data"
+  | "webhook"
   | "workspace"
   | "workspace_execution"
   | "workspace_proxy"
@@ -1492,12 +1844,15 @@ export const RBACResources: RBACResource[] = [
   "assign_org_role",
   "assign_role",
   "audit_log",
+  "custom_role",
   "debug_info",
   "deployment_config",
   "deployment_stats",
   "file",
   "group",
   "license",
+  "oauth2_app",
+  "oauth2_app_secret",
   "organization",
   "organization_member",
   "provisioner_daemon",
@@ -1506,6 +1861,7 @@ export const RBACResources: RBACResource[] = [
   "template",
   "user",
   "user_data",
+  "webhook",
   "workspace",
   "workspace_execution",
   "workspace_proxy",
@@ -1517,6 +1873,8 @@ export type ResourceType =
   | "git_ssh_key"
   | "group"
   | "license"
+  | "oauth2_provider_app"
+  | "oauth2_provider_app_secret"
   | "template"
   | "template_version"
   | "user"
@@ -1527,6 +1885,8 @@ export const ResourceTypes: ResourceType[] = [
   "git_ssh_key",
   "group",
   "license",
+  "oauth2_provider_app",
+  "oauth2_provider_app_secret",
   "template",
   "template_version",
   "user",
@@ -1542,6 +1902,13 @@ export const ServerSentEventTypes: ServerSentEventType[] = [
   "ping",
 ]
 
+// From codersdk/sessionrecordings.go
+export type SessionRecordingType = "reconnecting_pty" | "ssh"
+export const SessionRecordingTypes: SessionRecordingType[] = [
+  "reconnecting_pty",
+  "ssh",
+]
+
 // From codersdk/templates.go
 export type TemplateRole = "" | "admin" | "use"
 export const TemplateRoles: TemplateRole[] = ["", "admin", "use"]
@@ -1563,6 +1930,29 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ]
 
+// From codersdk/webhooks.go
+export type WebhookDeliveryStatus = "failed" | "pending" | "succeeded"
+export const WebhookDeliveryStatuses: WebhookDeliveryStatus[] = [
+  "failed",
+  "pending",
+  "succeeded",
+]
+
+// From codersdk/webhooks.go
+export type WebhookEvent =
+  | "template_version_pushed"
+  | "user_created"
+  | "workspace_build_failed"
+  | "workspace_build_started"
+  | "workspace_build_succeeded"
+export const WebhookEvents: WebhookEvent[] = [
+  "template_version_pushed",
+  "user_created",
+  "workspace_build_failed",
+  "workspace_build_started",
+  "workspace_build_succeeded",
+]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -1586,6 +1976,28 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
   "starting",
 ]
 
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentProcessSignal = "kill" | "terminate"
+export const WorkspaceAgentProcessSignals: WorkspaceAgentProcessSignal[] = [
+  "kill",
+  "terminate",
+]
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentScriptStatus =
+  | "failed"
+  | "pending"
+  | "running"
+  | "succeeded"
+  | "timed_out"
+export const WorkspaceAgentScriptStatuses: WorkspaceAgentScriptStatus[] = [
+  "failed",
+  "pending",
+  "running",
+  "succeeded",
+  "timed_out",
+]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking"
 export const WorkspaceAgentStartupScriptBehaviors: WorkspaceAgentStartupScriptBehavior[] =
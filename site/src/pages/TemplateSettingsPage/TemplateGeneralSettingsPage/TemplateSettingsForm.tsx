@@ -72,6 +72,8 @@ export const TemplateSettingsForm: FC<TemplateSettingsForm> = ({
         icon: template.icon,
         allow_user_cancel_workspace_jobs:
           template.allow_user_cancel_workspace_jobs,
+        // The idle threshold isn't editable here, but it's reset if omitted.
+        idle_threshold_ms: template.idle_threshold_ms,
       },
       validationSchema,
       onSubmit,
@@ -29,6 +29,7 @@ const validFormValues: FormValues = {
   failure_ttl_ms: 0,
   inactivity_ttl_ms: 0,
   locked_ttl_ms: 0,
+  idle_threshold_ms: 0,
 }
 
 const renderTemplateSettingsPage = async () => {
@@ -126,6 +126,8 @@ export const TemplateScheduleForm: FC<TemplateScheduleForm> = ({
 
       allow_user_autostart: form.values.allow_user_autostart,
       allow_user_autostop: form.values.allow_user_autostop,
+      // The idle threshold isn't editable here, but it's reset if omitted.
+      idle_threshold_ms: template.idle_threshold_ms,
     })
   }
 
@@ -391,6 +391,7 @@ export const MockTemplate: TypesGen.Template = {
   failure_ttl_ms: 0,
   inactivity_ttl_ms: 0,
   locked_ttl_ms: 0,
+  idle_threshold_ms: 0,
   allow_user_autostart: false,
   allow_user_autostop: false,
 }
@@ -508,6 +509,8 @@ export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
   startup_script_timeout_seconds: 120,
   shutdown_script_timeout_seconds: 120,
   subsystem: "envbox",
+  scripts: [],
+  log_sources: [],
 }
 
 export const MockWorkspaceAgentDisconnected: TypesGen.WorkspaceAgent = {
@@ -1651,6 +1654,7 @@ export const MockStartupLogs: TypesGen.WorkspaceAgentStartupLog[] = [
     created_at: "2023-05-04T11:30:41.402072Z",
     output: "+ curl -fsSL https://code-server.dev/install.sh",
     level: "info",
+    source_id: "00000000-0000-0000-0000-000000000000",
   },
   {
     id: 166664,
@@ -1658,18 +1662,21 @@ export const MockStartupLogs: TypesGen.WorkspaceAgentStartupLog[] = [
     output:
       "+ sh -s -- --method=standalone --prefix=/tmp/code-server --version 4.8.3",
     level: "info",
+    source_id: "00000000-0000-0000-0000-000000000000",
   },
   {
     id: 166665,
     created_at: "2023-05-04T11:30:42.590731Z",
     output: "Ubuntu 22.04.2 LTS",
     level: "info",
+    source_id: "00000000-0000-0000-0000-000000000000",
   },
   {
     id: 166666,
     created_at: "2023-05-04T11:30:42.593686Z",
     output: "Installing v4.8.3 of the amd64 release from GitHub.",
     level: "info",
+    source_id: "00000000-0000-0000-0000-000000000000",
   },
 ]
 
@@ -74,6 +74,10 @@ type Node struct {
 	// Endpoints are ip:port combinations that can be used to establish
 	// peer-to-peer connections.
 	Endpoints []string `json:"endpoints"`
+	// UserID is the user a client connection is authenticated as. It is set
+	// by coderd on the nodes of clients, so agents can attribute incoming
+	// connections. It is the zero UUID for agents and server connections.
+	UserID uuid.UUID `json:"user_id"`
 }
 
 // ServeCoordinator matches the RW structure of a coordinator to exchange node messages.